@@ -41,6 +41,8 @@ type Config struct {
 
 	// BaseURL 服务的基础 URL
 	BaseURL string `json:"base_url" yaml:"base_url"`
+	// ShareMessagesURL 聊天记录分享页面地址，{code} 会被替换为分享码
+	ShareMessagesURL string `json:"share_messages_url" yaml:"share_messages_url"`
 
 	// UniversalLinkConfig 通用链接配置
 	UniversalLinkConfig string `json:"universal_link_config" yaml:"universal_link_config"`
@@ -241,6 +243,16 @@ type Config struct {
 	AliyunSMSTemplateID string `json:"aliyun_sms_template_id" yaml:"aliyun_sms_template_id"`
 	AliyunSMSSign       string `json:"aliyun_sms_sign" yaml:"aliyun_sms_sign"`
 
+	// 内容审核
+	ModerationNicknameProviders []string `json:"moderation_nickname_providers" yaml:"moderation_nickname_providers"`
+	ModerationPromptProviders   []string `json:"moderation_prompt_providers" yaml:"moderation_prompt_providers"`
+	ModerationChatProviders     []string `json:"moderation_chat_providers" yaml:"moderation_chat_providers"`
+	ModerationAnswerProviders   []string `json:"moderation_answer_providers" yaml:"moderation_answer_providers"`
+	ModerationOpenAIServer      string   `json:"moderation_openai_server" yaml:"moderation_openai_server"`
+	ModerationOpenAIKey         string   `json:"-" yaml:"moderation_openai_key"`
+	ModerationOpenAIModel       string   `json:"moderation_openai_model" yaml:"moderation_openai_model"`
+	ModerationOpenAIAutoProxy   bool     `json:"moderation_openai_autoproxy" yaml:"moderation_openai_autoproxy"`
+
 	// Apple 应用内支付
 	EnableApplePay bool `json:"enable_apple_pay" yaml:"enable_apple_pay"`
 
@@ -328,6 +340,15 @@ type Config struct {
 	EnableSummarizer bool   `json:"enable_summarizer" yaml:"enable_summarizer"`
 	SummarizerModel  string `json:"summarizer_model" yaml:"summarizer_model"`
 
+	// 群聊讨论模式下，由大模型选择下一位发言者时使用的模型
+	GroupDiscussionSelectorModel string `json:"group_discussion_selector_model" yaml:"group_discussion_selector_model"`
+
+	// 从对话中提取用户长期记忆时使用的模型
+	MemoryExtractModel string `json:"memory_extract_model" yaml:"memory_extract_model"`
+
+	// 根据首轮对话自动生成数字人标题和描述时使用的模型
+	RoomTitleModel string `json:"room_title_model" yaml:"room_title_model"`
+
 	// Flux model
 	FluxAPIServer string `json:"flux_api_server" yaml:"flux_api_server"`
 	FluxAPIKey    string `json:"flux_api_key" yaml:"flux_api_key"`
@@ -436,9 +457,14 @@ func Register(ins *app.App) {
 			EnableSummarizer: ctx.Bool("enable-summarizer"),
 			SummarizerModel:  ctx.String("summarizer-model"),
 
-			BaseURL:      strings.TrimSuffix(ctx.String("base-url"), "/"),
-			IsProduction: ctx.Bool("production"),
-			TempDir:      ctx.String("temp-dir"),
+			GroupDiscussionSelectorModel: ctx.String("group-discussion-selector-model"),
+			MemoryExtractModel:           ctx.String("memory-extract-model"),
+			RoomTitleModel:               ctx.String("room-title-model"),
+
+			BaseURL:          strings.TrimSuffix(ctx.String("base-url"), "/"),
+			ShareMessagesURL: ctx.String("share-messages-url"),
+			IsProduction:     ctx.Bool("production"),
+			TempDir:          ctx.String("temp-dir"),
 
 			EnableModelRateLimit:   ctx.Bool("enable-model-rate-limit"),
 			EnableCustomHomeModels: ctx.Bool("enable-custom-home-models"),
@@ -606,6 +632,15 @@ func Register(ins *app.App) {
 			AliyunSMSTemplateID: ctx.String("aliyun-smstemplateid"),
 			AliyunSMSSign:       ctx.String("aliyun-smssign"),
 
+			ModerationNicknameProviders: ctx.StringSlice("moderation-nickname-providers"),
+			ModerationPromptProviders:   ctx.StringSlice("moderation-prompt-providers"),
+			ModerationChatProviders:     ctx.StringSlice("moderation-chat-providers"),
+			ModerationAnswerProviders:   ctx.StringSlice("moderation-answer-providers"),
+			ModerationOpenAIServer:      ctx.String("moderation-openai-server"),
+			ModerationOpenAIKey:         ctx.String("moderation-openai-key"),
+			ModerationOpenAIModel:       ctx.String("moderation-openai-model"),
+			ModerationOpenAIAutoProxy:   ctx.Bool("moderation-openai-autoproxy"),
+
 			EnableApplePay: ctx.Bool("enable-applepay"),
 
 			EnableAlipay:            ctx.Bool("enable-alipay"),
@@ -14,12 +14,13 @@ func initCmdFlags(ins *app.App) {
 	ins.AddDurationFlag("start-delay", 0, "服务启动延迟时间，用于在服务启动前做一些初始化工作，例如 Docker 环境下等待初始化数据库等")
 
 	ins.AddStringFlag("base-url", "", "Web 服务的基础 URL，例如 https://web.aicode.cc")
+	ins.AddStringFlag("share-messages-url", "", "聊天记录分享页面地址，{code} 会被替换为分享码，例如 https://web.aicode.cc/s/{code}，为空时使用 base-url 下的分享接口地址")
 	ins.AddBoolFlag("production", "是否为生产环境，生产环境下只有正式的支付渠道可用")
 	ins.AddStringFlag("socks5-proxy", "", "socks5 proxy")
 	ins.AddStringFlag("proxy-url", "", "HTTP 代理放置，支持 http、https、socks5，代理类型由 URL schema 决定，如果 scheme 为空，则默认为 http")
 	ins.AddStringFlag("db-uri", "root:12345@tcp(127.0.0.1:3306)/aiserver?charset=utf8mb4&parseTime=True&loc=Local", "database url")
 	ins.AddStringFlag("session-secret", "aidea-secret", "用户会话加密密钥")
-	ins.AddBoolFlag("enable-recordchat", "是否记录聊天历史记录（目前只做记录，没有实际作用，只是为后期增加多端聊天记录同步做准备）")
+	ins.AddBoolFlag("enable-recordchat", "是否记录聊天历史记录，开启后客户端可以通过 /v1/sync 接口进行多端聊天记录同步")
 	ins.AddBoolFlag("enable-cors", "是否启用跨域请求支持")
 	ins.AddBoolFlag("enable-websocket", "是否启用 WebSocket 支持")
 	ins.AddBoolFlag("debug-with-sql", "是否在日志中输出 SQL 语句")
@@ -186,7 +187,15 @@ func initCmdFlags(ins *app.App) {
 	ins.AddStringFlag("aliyun-secret", "", "aliyun app secret")
 	ins.AddStringFlag("aliyun-smstemplateid", "", "阿里云短信验证码模板 ID")
 	ins.AddStringFlag("aliyun-smssign", "AIdea", "阿里云短信签名")
-	ins.AddBoolFlag("enable-contentdetect", "是否启用内容安全检测（使用阿里云的内容安全服务）")
+	ins.AddBoolFlag("enable-contentdetect", "是否启用内容安全检测，使用的审核服务由 moderation-xxx-providers 配置")
+	ins.AddStringSliceFlag("moderation-nickname-providers", []string{"keyword", "aliyun"}, "昵称审核使用的服务，按顺序执行，支持 keyword（本地敏感词库）、aliyun、openai")
+	ins.AddStringSliceFlag("moderation-prompt-providers", []string{"keyword", "aliyun"}, "创作岛提示语审核使用的服务，按顺序执行，支持 keyword（本地敏感词库）、aliyun、openai")
+	ins.AddStringSliceFlag("moderation-chat-providers", []string{"keyword", "aliyun"}, "聊天内容审核使用的服务，按顺序执行，支持 keyword（本地敏感词库）、aliyun、openai")
+	ins.AddStringSliceFlag("moderation-answer-providers", []string{"keyword"}, "模型回复内容审核使用的服务，流式输出时按句子检查，为空时不审核模型回复")
+	ins.AddStringFlag("moderation-openai-server", "https://api.openai.com/v1", "兼容 OpenAI Moderation 接口的审核服务地址，不要忘记在 URL 后面添加 /v1")
+	ins.AddStringFlag("moderation-openai-key", "", "OpenAI Moderation 审核服务 Key，为空时不启用该服务")
+	ins.AddStringFlag("moderation-openai-model", "omni-moderation-latest", "OpenAI Moderation 审核模型")
+	ins.AddBoolFlag("moderation-openai-autoproxy", "使用 socks5 代理访问 OpenAI Moderation 审核服务")
 
 	ins.AddBoolFlag("enable-applepay", "启用 Apple 应用内支付")
 
@@ -259,6 +268,10 @@ func initCmdFlags(ins *app.App) {
 	ins.AddBoolFlag("enable-summarizer", "是否启用聊天记录总结功能")
 	ins.AddStringFlag("summarizer-model", "gpt-4o-mini", "总结模型名称")
 
+	ins.AddStringFlag("group-discussion-selector-model", "gpt-4o-mini", "群聊讨论模式下，用于选择下一位发言者的模型")
+	ins.AddStringFlag("memory-extract-model", "gpt-4o-mini", "从对话中提取用户长期记忆时使用的模型")
+	ins.AddStringFlag("room-title-model", "gpt-4o-mini", "根据首轮对话自动生成数字人标题和描述时使用的模型")
+
 	ins.AddStringFlag("flux-api-server", "https://api.bfl.ml", "flux api server")
 	ins.AddStringFlag("flux-api-key", "", "flux api key")
 
@@ -39,6 +39,7 @@ require (
 	github.com/tideland/gorest v2.15.5+incompatible
 	github.com/wagslane/go-password-validator v0.3.0
 	github.com/wechatpay-apiv3/wechatpay-go v0.2.18
+	golang.org/x/image v0.14.0
 	golang.org/x/net v0.24.0
 	gopkg.in/gomail.v2 v2.0.0-20160411212932-81ebce5c23df
 	gopkg.in/guregu/null.v3 v3.5.0
@@ -87,7 +88,6 @@ require (
 	github.com/tideland/golib v4.24.2+incompatible // indirect
 	github.com/tink-ab/tempfile v0.0.0-20180226111222-33beb0518f1a // indirect
 	github.com/tjfoc/gmsm v1.3.2 // indirect
-	golang.org/x/image v0.14.0 // indirect
 	golang.org/x/sync v0.7.0 // indirect
 	golang.org/x/sys v0.19.0 // indirect
 	golang.org/x/text v0.14.0 // indirect
@@ -5,24 +5,24 @@ import (
 	"database/sql"
 	"github.com/mylxsw/aidea-server/pkg/repo"
 	"github.com/mylxsw/aidea-server/pkg/repo/model"
-	"math/rand"
-	"time"
 
 	"github.com/mylxsw/asteria/log"
 	"github.com/mylxsw/eloquent/query"
 	"github.com/redis/go-redis/v9"
-	"gopkg.in/guregu/null.v3"
 )
 
 func GallerySortJob(ctx context.Context, db *sql.DB, rds *redis.Client) error {
-	// 随机增加热度
-	randomUpdateGalleriesHotValue(ctx, db)
+	// 根据点赞、收藏、评论等互动数据以及发布时间重新计算热度，管理员设置的星级和热度加成同样参与计算
+	if err := repo.NewGalleryRepo(db).RefreshHotValues(ctx); err != nil {
+		log.Errorf("refresh gallery hot values failed for GallerySortJob: %v", err)
+	}
 
 	// 查询最新的排序
 	q := query.Builder().
 		Select(model.FieldCreativeGalleryId).
 		Where(model.FieldCreativeGalleryStatus, repo.CreativeGalleryStatusOK).
-		OrderByRaw("RAND()*hot_value+id DESC")
+		OrderBy(model.FieldCreativeGalleryHotValue, "DESC").
+		OrderBy(model.FieldCreativeGalleryId, "DESC")
 
 	galleries, err := model.NewCreativeGalleryModel(db).Get(ctx, q)
 	if err != nil {
@@ -62,23 +62,3 @@ func GallerySortJob(ctx context.Context, db *sql.DB, rds *redis.Client) error {
 
 	return nil
 }
-
-func randomUpdateGalleriesHotValue(ctx context.Context, db *sql.DB) {
-	galleries, err := model.NewCreativeGalleryModel(db).Get(ctx, query.Builder().Where(model.FieldCreativeGalleryStatus, repo.CreativeGalleryStatusOK))
-	if err != nil {
-		log.Errorf("get galleries failed for GallerySortJob: %v", err)
-		return
-	}
-
-	for _, gallery := range galleries {
-		var delta int64
-		if gallery.CreatedAt.ValueOrZero().After(time.Now().Add(-24 * time.Hour)) {
-			delta = gallery.Id.ValueOrZero()
-		}
-
-		gallery.HotValue = null.IntFrom(gallery.HotValue.ValueOrZero() + rand.Int63n(100+delta))
-		if err := gallery.Save(ctx, model.FieldCreativeGalleryHotValue); err != nil {
-			log.Errorf("random update gallery hot value failed: %v", err)
-		}
-	}
-}
@@ -68,7 +68,7 @@ func (Provider) Jobs(cc infra.Resolver, creator scheduler.JobCreator) {
 		log.Errorf("注册定时任务 healthcheck-task 失败: %v", err)
 	}
 
-	// 注册 Gallery 自动随机排序任务
+	// 注册 Gallery 热度排序任务
 	if err := creator.Add(
 		"gallery-sort-task",
 		"0 */60 * * * *",
@@ -2,11 +2,13 @@ package consumer
 
 import (
 	"context"
+	"encoding/json"
 	"github.com/mylxsw/aidea-server/pkg/ai/chat"
 	"github.com/mylxsw/aidea-server/pkg/ai/dashscope"
 	"github.com/mylxsw/aidea-server/pkg/ai/deepai"
 	"github.com/mylxsw/aidea-server/pkg/ai/fromston"
 	"github.com/mylxsw/aidea-server/pkg/ai/getimgai"
+	"github.com/mylxsw/aidea-server/pkg/ai/imagegen"
 	"github.com/mylxsw/aidea-server/pkg/ai/leap"
 	"github.com/mylxsw/aidea-server/pkg/ai/lepton"
 	"github.com/mylxsw/aidea-server/pkg/ai/openai"
@@ -50,9 +52,9 @@ func (Provider) Register(binder infra.Binder) {
 		)
 	})
 
-	binder.MustSingleton(func(server *asynq.Server) *asynq.ServeMux {
+	binder.MustSingleton(func(server *asynq.Server, rep *repo.Repository) *asynq.ServeMux {
 		mux := asynq.NewServeMux()
-		mux.Use(loggingMiddleware)
+		mux.Use(loggingMiddleware, runningMiddleware(rep.Queue))
 		return mux
 	})
 }
@@ -73,6 +75,24 @@ func loggingMiddleware(h asynq.Handler) asynq.Handler {
 	})
 }
 
+// runningMiddleware 任务开始执行时，将任务状态更新为执行中，触发任务执行中事件
+func runningMiddleware(queueRepo *repo.QueueRepo) asynq.MiddlewareFunc {
+	return func(h asynq.Handler) asynq.Handler {
+		return asynq.HandlerFunc(func(ctx context.Context, t *asynq.Task) error {
+			var payload struct {
+				ID string `json:"id"`
+			}
+			if err := json.Unmarshal(t.Payload(), &payload); err == nil && payload.ID != "" {
+				if err := queueRepo.Update(ctx, payload.ID, repo.QueueTaskStatusRunning, nil); err != nil {
+					log.F(log.M{"task_id": payload.ID, "type": t.Type()}).Warningf("update queue task status to running failed: %v", err)
+				}
+			}
+
+			return h.ProcessTask(ctx, t)
+		})
+	}
+}
+
 func (p Provider) Boot(resolver infra.Resolver) {
 	resolver.MustResolve(func(
 		mux *asynq.ServeMux,
@@ -97,6 +117,7 @@ func (p Provider) Boot(resolver infra.Resolver) {
 		dalleClient *openai.DalleImageClient,
 		leptonClient *lepton.Lepton,
 		aiProvider *chat.AIProvider,
+		imageRegistry *imagegen.Registry,
 	) {
 		log.Debugf("register all queue handlers")
 		mux.HandleFunc(queue.TypeOpenAICompletion, queue.BuildOpenAICompletionHandler(openaiClient, rep, svc))
@@ -108,7 +129,7 @@ func (p Provider) Boot(resolver infra.Resolver) {
 		mux.HandleFunc(queue.TypeSignup, queue.BuildSignupHandler(rep, mailer, ding))
 		mux.HandleFunc(queue.TypePayment, queue.BuildPaymentHandler(conf, rep, mailer, que, ding))
 		mux.HandleFunc(queue.TypeBindPhone, queue.BuildBindPhoneHandler(rep, mailer))
-		mux.HandleFunc(queue.TypeImageGenCompletion, queue.BuildImageCompletionHandler(conf, aiProvider, leapClient, stabaiClient, deepaiClient, fromstonClient, dashscopeClient, getimgaiClient, translater, uploader, rep, openaiClient, dalleClient))
+		mux.HandleFunc(queue.TypeImageGenCompletion, queue.BuildImageCompletionHandler(conf, aiProvider, imageRegistry, translater, uploader, rep, openaiClient, que))
 		mux.HandleFunc(queue.TypeFromStonCompletion, queue.BuildFromStonCompletionHandler(fromstonClient, uploader, rep))
 		mux.HandleFunc(queue.TypeDashscopeImageCompletion, queue.BuildDashscopeImageCompletionHandler(dashscopeClient, uploader, rep, translater, openaiClient))
 		mux.HandleFunc(queue.TypeGetimgAICompletion, queue.BuildGetimgAICompletionHandler(getimgaiClient, translater, uploader, rep, openaiClient))
@@ -116,9 +137,12 @@ func (p Provider) Boot(resolver infra.Resolver) {
 		mux.HandleFunc(queue.TypeImageUpscale, queue.BuildImageUpscaleHandler(deepaiClient, stabaiClient, uploader, rep))
 		mux.HandleFunc(queue.TypeImageColorization, queue.BuildImageColorizationHandler(deepaiClient, uploader, rep))
 		mux.HandleFunc(queue.TypeGroupChat, queue.BuildGroupChatHandler(conf, ct, rep, svc))
+		mux.HandleFunc(queue.TypeGroupDiscussion, queue.BuildGroupDiscussionHandler(conf, ct, rep, svc, que))
 		mux.HandleFunc(queue.TypeDalleCompletion, queue.BuildDalleCompletionHandler(dalleClient, uploader, rep))
 		mux.HandleFunc(queue.TypeArtisticTextCompletion, queue.BuildArtisticTextCompletionHandler(leptonClient, translater, uploader, rep, openaiClient))
 		mux.HandleFunc(queue.TypeImageToVideoCompletion, queue.BuildImageToVideoCompletionHandler(stabaiClient, rep))
+		mux.HandleFunc(queue.TypeMemoryExtract, queue.BuildMemoryExtractHandler(conf, ct, rep))
+		mux.HandleFunc(queue.TypeRoomTitle, queue.BuildRoomTitleHandler(conf, ct, rep, svc))
 	})
 }
 
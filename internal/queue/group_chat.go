@@ -5,8 +5,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/mylxsw/aidea-server/pkg/ai/chat"
+	"github.com/mylxsw/aidea-server/pkg/ai/control"
 	repo "github.com/mylxsw/aidea-server/pkg/repo"
 	"github.com/mylxsw/aidea-server/pkg/service"
+	"strings"
 	"time"
 
 	"github.com/hibiken/asynq"
@@ -25,6 +27,7 @@ type GroupChatPayload struct {
 	MessageID       int64         `json:"message_id,omitempty"`
 	ModelID         string        `json:"model_id,omitempty"`
 	ContextMessages chat.Messages `json:"context_messages,omitempty"`
+	Temperature     float64       `json:"temperature,omitempty"`
 	CreatedAt       time.Time     `json:"created_at,omitempty"`
 	FreezedCoins    int64         `json:"freezed_coins,omitempty"`
 }
@@ -86,6 +89,15 @@ func BuildGroupChatHandler(conf *config.Config, ct chat.Chat, rep *repo.Reposito
 					log.With(task).Errorf("update chat message failed: %s", err)
 				}
 
+				publishGroupChatEvent(ctx, svc, service.GroupChatEvent{
+					Type:      service.GroupChatEventStatus,
+					GroupID:   payload.GroupID,
+					MessageID: payload.MessageID,
+					MemberID:  payload.MemberID,
+					Status:    repo.MessageStatusFailed,
+					Error:     err.Error(),
+				})
+
 				// 更新队列状态为失败
 				if err := rep.Queue.Update(
 					context.TODO(),
@@ -113,28 +125,29 @@ func BuildGroupChatHandler(conf *config.Config, ct chat.Chat, rep *repo.Reposito
 		}
 
 		req, _, err := (chat.Request{
-			Model:    mod.ModelId,
-			Messages: payload.ContextMessages,
+			Model:       mod.ModelId,
+			Messages:    payload.ContextMessages,
+			Temperature: payload.Temperature,
 		}).Init().FixContextWindow(ct, 5, 1024*200, 2000)
 		if err != nil {
 			panic(fmt.Errorf("fix chat request failed: %w", err))
 		}
 
-		// 调用 AI 系统
-		resp, err := ct.Chat(ctx, *req)
+		// 调用 AI 系统，以流的方式输出，将增量内容实时推送给订阅了该群组的客户端
+		replyText, channel, err := streamGroupChatReply(ctx, ct, svc, *req, service.GroupChatEvent{
+			GroupID:   payload.GroupID,
+			MessageID: payload.MessageID,
+			MemberID:  payload.MemberID,
+		})
 		if err != nil {
-			panic(fmt.Errorf("chat failed: %w", err))
-		}
-
-		if resp.ErrorCode != "" {
-			panic(fmt.Errorf("chat failed: %s %s", resp.ErrorCode, resp.Error))
+			panic(err)
 		}
 
 		inputTokens, _ := chat.MessageTokenCount(req.Messages, req.Model)
 		outputTokens, _ := chat.MessageTokenCount(
 			chat.Messages{{
 				Role:    "assistant",
-				Content: resp.Text,
+				Content: replyText,
 			}}, req.Model,
 		)
 
@@ -151,15 +164,28 @@ func BuildGroupChatHandler(conf *config.Config, ct chat.Chat, rep *repo.Reposito
 
 		// 更新消息状态
 		msg := repo.ChatGroupMessageUpdate{
-			Message:       resp.Text,
+			Message:       replyText,
 			TokenConsumed: tokenConsumed,
 			QuotaConsumed: quotaConsumed,
 			Status:        repo.MessageStatusSucceed,
+			Channel:       channel,
+			Model:         mod.ModelId,
 		}
 		if err := rep.ChatGroup.UpdateChatMessage(ctx, payload.GroupID, payload.UserID, payload.MessageID, msg); err != nil {
 			panic(fmt.Errorf("update chat message failed: %w", err))
 		}
 
+		publishGroupChatEvent(ctx, svc, service.GroupChatEvent{
+			Type:          service.GroupChatEventStatus,
+			GroupID:       payload.GroupID,
+			MessageID:     payload.MessageID,
+			MemberID:      payload.MemberID,
+			Message:       replyText,
+			Status:        repo.MessageStatusSucceed,
+			TokenConsumed: tokenConsumed,
+			QuotaConsumed: quotaConsumed,
+		})
+
 		// 更新免费聊天次数
 		if err := svc.Chat.UpdateFreeChatCount(ctx, payload.UserID, req.Model); err != nil {
 			log.With(payload).Errorf("update free chat count failed: %s", err)
@@ -180,3 +206,41 @@ func BuildGroupChatHandler(conf *config.Config, ct chat.Chat, rep *repo.Reposito
 		)
 	}
 }
+
+// streamGroupChatReply 以流的方式调用 AI 系统，并将成员的回复过程通过群聊事件实时发布出去，返回完整的回复内容以及实际回答问题的渠道
+func streamGroupChatReply(ctx context.Context, ct chat.Chat, svc *service.Service, req chat.Request, evt service.GroupChatEvent) (string, string, error) {
+	evt.Type = service.GroupChatEventTyping
+	publishGroupChatEvent(ctx, svc, evt)
+
+	trace := &control.Trace{}
+	stream, err := ct.ChatStream(control.NewTraceContext(ctx, trace), req)
+	if err != nil {
+		return "", "", fmt.Errorf("chat failed: %w", err)
+	}
+
+	var replyText strings.Builder
+	for resp := range stream {
+		if resp.ErrorCode != "" {
+			return "", "", fmt.Errorf("chat failed: %s %s", resp.ErrorCode, resp.Error)
+		}
+
+		if resp.Text == "" {
+			continue
+		}
+
+		replyText.WriteString(resp.Text)
+
+		evt.Type = service.GroupChatEventDelta
+		evt.Delta = resp.Text
+		publishGroupChatEvent(ctx, svc, evt)
+	}
+
+	return replyText.String(), trace.Channel, nil
+}
+
+// publishGroupChatEvent 发布群聊事件，事件推送失败不影响消息本身的处理
+func publishGroupChatEvent(ctx context.Context, svc *service.Service, evt service.GroupChatEvent) {
+	if err := svc.GroupChat.Publish(ctx, evt); err != nil {
+		log.F(log.M{"event": evt}).Warningf("publish group chat event failed: %s", err)
+	}
+}
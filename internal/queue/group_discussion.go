@@ -0,0 +1,608 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/mylxsw/aidea-server/pkg/ai/chat"
+	"github.com/mylxsw/aidea-server/pkg/misc"
+	repo "github.com/mylxsw/aidea-server/pkg/repo"
+	"github.com/mylxsw/aidea-server/pkg/repo/model"
+	"github.com/mylxsw/aidea-server/pkg/service"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/mylxsw/aidea-server/config"
+	"github.com/mylxsw/aidea-server/internal/coins"
+	"github.com/mylxsw/asteria/log"
+	"github.com/mylxsw/go-utils/array"
+	"github.com/mylxsw/go-utils/ternary"
+)
+
+var ErrDiscussionQuotaNotEnough = errors.New("智慧果不足，无法继续讨论")
+
+// ErrDiscussionBudgetNotEnough 讨论剩余的智慧果预算不足以完成下一次发言
+var ErrDiscussionBudgetNotEnough = errors.New("讨论的智慧果预算不足，无法继续讨论")
+
+// DiscussionAgreeMarker 成员认为讨论已经达成一致时，在回复最后输出的标记
+const DiscussionAgreeMarker = "[AGREE]"
+
+const groupDiscussionPrompt = `你正在参与一场多人讨论，参与讨论的成员有：%s。你在讨论中的身份是「%s」。
+讨论的主题由用户提出，请围绕主题发表你的观点，可以赞同、补充或反驳其他成员的观点，发言尽量简洁，不要重复其他成员已经表达过的内容。
+其他成员的发言会以「[成员名称]: 发言内容」的形式提供给你，用户的插话会以「[用户]: 发言内容」的形式提供给你，回复时不要使用这种格式。
+如果你认为讨论已经达成一致，不需要继续讨论，请在回复的最后单独一行输出 %s。`
+
+const groupDiscussionSelectorPrompt = `以下是一场多人讨论的记录：
+
+%s
+
+请根据讨论的进展，从下列候选成员中选择最适合下一个发言的成员：
+
+%s
+
+只需要回复成员编号，不要输出其它内容。`
+
+type GroupDiscussionPayload struct {
+	ID           string    `json:"id,omitempty"`
+	DiscussionID int64     `json:"discussion_id,omitempty"`
+	GroupID      int64     `json:"group_id,omitempty"`
+	UserID       int64     `json:"user_id,omitempty"`
+	MemberID     int64     `json:"member_id,omitempty"`
+	MessageID    int64     `json:"message_id,omitempty"`
+	CreatedAt    time.Time `json:"created_at,omitempty"`
+	FreezedCoins int64     `json:"freezed_coins,omitempty"`
+}
+
+func (payload *GroupDiscussionPayload) GetTitle() string {
+	return "群聊讨论"
+}
+
+func (payload *GroupDiscussionPayload) SetID(id string) {
+	payload.ID = id
+}
+
+func (payload *GroupDiscussionPayload) GetID() string {
+	return payload.ID
+}
+
+func (payload *GroupDiscussionPayload) GetUID() int64 {
+	return payload.UserID
+}
+
+func (payload *GroupDiscussionPayload) GetQuotaID() int64 {
+	return 0
+}
+
+func (payload *GroupDiscussionPayload) GetQuota() int64 {
+	return 0
+}
+
+func NewGroupDiscussionTask(payload any) *asynq.Task {
+	data, _ := json.Marshal(payload)
+	return asynq.NewTask(TypeGroupDiscussion, data)
+}
+
+func BuildGroupDiscussionHandler(conf *config.Config, ct chat.Chat, rep *repo.Repository, svc *service.Service, que *Queue) TaskHandler {
+	return func(ctx context.Context, task *asynq.Task) (err error) {
+		var payload GroupDiscussionPayload
+		if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+			return err
+		}
+
+		defer func() {
+			if err2 := recover(); err2 != nil {
+				log.With(task).Errorf("panic: %v", err2)
+				err = fmt.Errorf("%v", err2)
+			}
+
+			if err != nil {
+				// 更新消息状态为失败
+				msg := repo.ChatGroupMessageUpdate{
+					Message: err.Error(),
+					Status:  repo.MessageStatusFailed,
+					Error:   err.Error(),
+				}
+				if err := rep.ChatGroup.UpdateChatMessage(ctx, payload.GroupID, payload.UserID, payload.MessageID, msg); err != nil {
+					log.With(task).Errorf("update chat message failed: %s", err)
+				}
+
+				publishGroupChatEvent(ctx, svc, service.GroupChatEvent{
+					Type:      service.GroupChatEventStatus,
+					GroupID:   payload.GroupID,
+					MessageID: payload.MessageID,
+					MemberID:  payload.MemberID,
+					Status:    repo.MessageStatusFailed,
+					Error:     err.Error(),
+				})
+
+				// 当前发言失败，终止整个讨论
+				if err := rep.ChatGroup.StopDiscussion(ctx, payload.GroupID, payload.UserID, payload.DiscussionID, repo.ChatGroupDiscussionStatusFinished, repo.DiscussionStopReasonError); err != nil {
+					log.With(task).Errorf("stop discussion failed: %s", err)
+				}
+
+				// 更新队列状态为失败
+				if err := rep.Queue.Update(
+					context.TODO(),
+					payload.GetID(),
+					repo.QueueTaskStatusFailed,
+					ErrorResult{
+						Errors: []string{err.Error()},
+					},
+				); err != nil {
+					log.With(task).Errorf("update queue status failed: %s", err)
+				}
+			}
+
+			// 无论如何，都要释放用户被冻结的智慧果
+			if payload.FreezedCoins > 0 {
+				if err := svc.User.UnfreezeUserQuota(ctx, payload.UserID, payload.FreezedCoins); err != nil {
+					log.F(log.M{"payload": payload}).Errorf("群聊讨论任务执行失败，释放用户冻结的智慧果失败: %s", err)
+				}
+			}
+		}()
+
+		// 如果任务是 15 分钟前创建的，不再处理
+		if payload.CreatedAt.Add(15 * time.Minute).Before(time.Now()) {
+			panic(errors.New("讨论任务已过期"))
+		}
+
+		disc, err := rep.ChatGroup.GetDiscussion(ctx, payload.GroupID, payload.UserID, payload.DiscussionID)
+		if err != nil {
+			panic(fmt.Errorf("query discussion failed: %w", err))
+		}
+
+		// 讨论已经结束（用户终止或者达到结束条件），当前发言不再处理
+		if !disc.IsRunning() {
+			msg := repo.ChatGroupMessageUpdate{Status: repo.MessageStatusFailed, Error: "讨论已结束"}
+			if err := rep.ChatGroup.UpdateChatMessage(ctx, payload.GroupID, payload.UserID, payload.MessageID, msg); err != nil {
+				log.With(payload).Errorf("update chat message failed: %s", err)
+			}
+
+			publishGroupChatEvent(ctx, svc, service.GroupChatEvent{
+				Type:      service.GroupChatEventStatus,
+				GroupID:   payload.GroupID,
+				MessageID: payload.MessageID,
+				MemberID:  payload.MemberID,
+				Status:    repo.MessageStatusFailed,
+				Error:     msg.Error,
+			})
+
+			return rep.Queue.Update(context.TODO(), payload.GetID(), repo.QueueTaskStatusSuccess, EmptyResult{})
+		}
+
+		grp, err := rep.ChatGroup.GetGroup(ctx, payload.GroupID, payload.UserID)
+		if err != nil {
+			panic(fmt.Errorf("query group failed: %w", err))
+		}
+
+		members := array.ToMap(grp.Members, func(mem model.ChatGroupMember, _ int) int64 { return mem.Id })
+		speaker, ok := members[payload.MemberID]
+		if !ok {
+			panic(fmt.Errorf("member %d not found", payload.MemberID))
+		}
+
+		mod := svc.Chat.Model(ctx, speaker.ModelId)
+		if mod == nil || mod.Status == repo.ModelStatusDisabled {
+			panic(fmt.Errorf("model %s not found or disabled", speaker.ModelId))
+		}
+
+		history, err := rep.ChatGroup.GetDiscussionMessages(ctx, payload.GroupID, payload.UserID, disc.QuestionId)
+		if err != nil {
+			panic(fmt.Errorf("query discussion messages failed: %w", err))
+		}
+
+		req, _, err := (chat.Request{
+			Model:       mod.ModelId,
+			Messages:    BuildGroupDiscussionMessages(disc, members, speaker.Id, history),
+			Temperature: repo.MemberPersonaOf(speaker).Temperature,
+		}).Init().FixContextWindow(ct, 20, 1024*200, 4000)
+		if err != nil {
+			panic(fmt.Errorf("fix chat request failed: %w", err))
+		}
+
+		// 调用 AI 系统
+		respText, channel, err := streamGroupChatReply(ctx, ct, svc, *req, service.GroupChatEvent{
+			GroupID:   payload.GroupID,
+			MessageID: payload.MessageID,
+			MemberID:  payload.MemberID,
+		})
+		if err != nil {
+			panic(err)
+		}
+
+		replyText, agreed := ParseGroupDiscussionReply(respText)
+
+		inputTokens, _ := chat.MessageTokenCount(req.Messages, req.Model)
+		outputTokens, _ := chat.MessageTokenCount(
+			chat.Messages{{
+				Role:    "assistant",
+				Content: respText,
+			}}, req.Model,
+		)
+
+		tokenConsumed := int64(inputTokens + outputTokens)
+		// 免费请求不计费
+		leftCount, _ := svc.Chat.FreeChatRequestCounts(ctx, payload.UserID, req.Model)
+		quotaConsumed := ternary.IfLazy(
+			leftCount > 0,
+			func() int64 { return 0 },
+			func() int64 {
+				return coins.GetTextModelCoins(mod.ToCoinModel(), int64(inputTokens), int64(outputTokens))
+			},
+		)
+
+		// 更新消息状态
+		msg := repo.ChatGroupMessageUpdate{
+			Message:       replyText,
+			TokenConsumed: tokenConsumed,
+			QuotaConsumed: quotaConsumed,
+			Status:        repo.MessageStatusSucceed,
+			Channel:       channel,
+			Model:         mod.ModelId,
+		}
+		if err := rep.ChatGroup.UpdateChatMessage(ctx, payload.GroupID, payload.UserID, payload.MessageID, msg); err != nil {
+			panic(fmt.Errorf("update chat message failed: %w", err))
+		}
+
+		publishGroupChatEvent(ctx, svc, service.GroupChatEvent{
+			Type:          service.GroupChatEventStatus,
+			GroupID:       payload.GroupID,
+			MessageID:     payload.MessageID,
+			MemberID:      payload.MemberID,
+			Message:       replyText,
+			Status:        repo.MessageStatusSucceed,
+			TokenConsumed: tokenConsumed,
+			QuotaConsumed: quotaConsumed,
+		})
+
+		// 更新免费聊天次数
+		if err := svc.Chat.UpdateFreeChatCount(ctx, payload.UserID, req.Model); err != nil {
+			log.With(payload).Errorf("update free chat count failed: %s", err)
+		}
+
+		// 扣除智慧果
+		if quotaConsumed > 0 {
+			if err := rep.Quota.QuotaConsume(ctx, payload.UserID, quotaConsumed, repo.NewQuotaUsedMeta("group_chat", req.Model)); err != nil {
+				log.Errorf("used quota add failed: %s", err)
+			}
+		}
+
+		if err := rep.Queue.Update(context.TODO(), payload.GetID(), repo.QueueTaskStatusSuccess, EmptyResult{}); err != nil {
+			log.With(payload).Errorf("update queue status failed: %s", err)
+		}
+
+		// 记录本轮发言，并决定讨论是否继续
+		disc, err = rep.ChatGroup.FinishDiscussionTurn(ctx, payload.GroupID, payload.UserID, payload.DiscussionID, repo.ChatGroupDiscussionTurn{
+			CoinsConsumed: quotaConsumed,
+			Agreed:        agreed,
+		})
+		if err != nil {
+			log.With(payload).Errorf("finish discussion turn failed: %s", err)
+			return nil
+		}
+
+		if !disc.IsRunning() {
+			return nil
+		}
+
+		if reason := GroupDiscussionStopReason(disc); reason != "" {
+			if err := rep.ChatGroup.StopDiscussion(ctx, payload.GroupID, payload.UserID, payload.DiscussionID, repo.ChatGroupDiscussionStatusFinished, reason); err != nil {
+				log.With(payload).Errorf("stop discussion failed: %s", err)
+			}
+
+			return nil
+		}
+
+		history = append(history, model.ChatGroupMessage{
+			Id:       payload.MessageID,
+			Role:     int64(repo.MessageRoleAssistant),
+			MemberId: speaker.Id,
+			Message:  replyText,
+			Status:   repo.MessageStatusSucceed,
+		})
+
+		nextMemberID, selectorModel, selectorCoins := selectGroupDiscussionNextSpeaker(ctx, conf, ct, svc, disc, members, speaker.Id, history)
+		if selectorCoins > 0 {
+			// 选择发言者的请求同样需要扣除智慧果，并计入讨论的消耗
+			if err := rep.Quota.QuotaConsume(ctx, payload.UserID, selectorCoins, repo.NewQuotaUsedMeta("group_discussion_selector", selectorModel)); err != nil {
+				log.With(payload).Errorf("used quota add failed: %s", err)
+			}
+
+			if err := rep.ChatGroup.AddDiscussionCoinsConsumed(ctx, payload.GroupID, payload.UserID, payload.DiscussionID, selectorCoins); err != nil {
+				log.With(payload).Errorf("add discussion coins consumed failed: %s", err)
+			}
+
+			disc.CoinsConsumed += selectorCoins
+		}
+
+		if _, _, err := EnqueueGroupDiscussionTurn(ctx, que, rep, svc, disc, members[nextMemberID], history); err != nil {
+			log.F(log.M{"payload": payload, "next_member_id": nextMemberID}).Errorf("enqueue next discussion turn failed: %s", err)
+
+			reason := repo.DiscussionStopReasonError
+			switch {
+			case errors.Is(err, ErrDiscussionQuotaNotEnough):
+				reason = repo.DiscussionStopReasonQuota
+			case errors.Is(err, ErrDiscussionBudgetNotEnough):
+				reason = repo.DiscussionStopReasonCoinBudget
+			}
+
+			if err := rep.ChatGroup.StopDiscussion(ctx, payload.GroupID, payload.UserID, payload.DiscussionID, repo.ChatGroupDiscussionStatusFinished, reason); err != nil {
+				log.With(payload).Errorf("stop discussion failed: %s", err)
+			}
+		}
+
+		return nil
+	}
+}
+
+// EnqueueGroupDiscussionTurn 为指定的成员创建一次发言任务：预估并冻结本次发言需要的智慧果，创建待处理的消息，然后加入任务队列
+func EnqueueGroupDiscussionTurn(
+	ctx context.Context,
+	que *Queue,
+	rep *repo.Repository,
+	svc *service.Service,
+	disc *repo.ChatGroupDiscussion,
+	member model.ChatGroupMember,
+	history []model.ChatGroupMessage,
+) (taskID string, answerID int64, err error) {
+	if member.Id == 0 {
+		return "", 0, errors.New("member not found")
+	}
+
+	mod := svc.Chat.Model(ctx, member.ModelId)
+	if mod == nil || mod.Status == repo.ModelStatusDisabled {
+		return "", 0, fmt.Errorf("model %s not found or disabled", member.ModelId)
+	}
+
+	// 预估本次发言需要消耗的智慧果
+	var needCoins int64
+	if leftCount, _ := svc.Chat.FreeChatRequestCounts(ctx, disc.UserId, member.ModelId); leftCount <= 0 {
+		contents := array.Map(history, func(msg model.ChatGroupMessage, _ int) chat.Message {
+			return chat.Message{Role: "user", Content: msg.Message}
+		})
+
+		count, err := chat.MessageTokenCount(contents, member.ModelId)
+		if err != nil {
+			count = 500
+		}
+
+		needCoins = coins.GetTextModelCoins(mod.ToCoinModel(), int64(count), 500)
+	}
+
+	if !GroupDiscussionBudgetAllows(disc, needCoins) {
+		return "", 0, ErrDiscussionBudgetNotEnough
+	}
+
+	if needCoins > 0 {
+		quota, err := svc.User.UserQuota(ctx, disc.UserId)
+		if err != nil {
+			return "", 0, fmt.Errorf("query user quota failed: %w", err)
+		}
+
+		if quota.Rest-quota.Freezed < needCoins {
+			return "", 0, ErrDiscussionQuotaNotEnough
+		}
+
+		// 冻结用户的智慧果
+		if err := svc.User.FreezeUserQuota(ctx, disc.UserId, needCoins); err != nil {
+			log.F(log.M{"user_id": disc.UserId, "quota": needCoins}).Errorf("群聊讨论冻结用户智慧果失败: %s", err)
+		}
+	}
+
+	answerID, err = rep.ChatGroup.AddChatMessage(ctx, disc.GroupId, disc.UserId, repo.ChatGroupMessage{
+		Role:     int64(repo.MessageRoleAssistant),
+		Pid:      disc.QuestionId,
+		MemberId: member.Id,
+		Status:   repo.MessageStatusWaiting,
+	})
+	if err != nil {
+		misc.NoError(svc.User.UnfreezeUserQuota(ctx, disc.UserId, needCoins))
+		return "", 0, fmt.Errorf("add chat message failed: %w", err)
+	}
+
+	payload := GroupDiscussionPayload{
+		DiscussionID: disc.Id,
+		GroupID:      disc.GroupId,
+		UserID:       disc.UserId,
+		MemberID:     member.Id,
+		MessageID:    answerID,
+		CreatedAt:    time.Now(),
+		FreezedCoins: needCoins,
+	}
+
+	taskID, err = que.Enqueue(&payload, NewGroupDiscussionTask)
+	if err != nil {
+		misc.NoError(svc.User.UnfreezeUserQuota(ctx, disc.UserId, needCoins))
+		return "", 0, fmt.Errorf("enqueue discussion task failed: %w", err)
+	}
+
+	if err := rep.ChatGroup.UpdateDiscussionNextMember(ctx, disc.GroupId, disc.UserId, disc.Id, member.Id); err != nil {
+		log.F(log.M{"discussion_id": disc.Id, "member_id": member.Id}).Errorf("update discussion next member failed: %s", err)
+	}
+
+	return taskID, answerID, nil
+}
+
+// GroupDiscussionStopReason 检查讨论是否满足结束条件，返回结束原因，不需要结束时返回空字符串
+func GroupDiscussionStopReason(disc *repo.ChatGroupDiscussion) string {
+	memberCount := int64(len(disc.Members))
+
+	if disc.StopOnConsensus == 1 && memberCount > 0 && disc.AgreeCount >= memberCount {
+		return repo.DiscussionStopReasonConsensus
+	}
+
+	if disc.CoinBudget > 0 && disc.CoinsConsumed >= disc.CoinBudget {
+		return repo.DiscussionStopReasonCoinBudget
+	}
+
+	if disc.Turns >= disc.MaxRounds*memberCount {
+		return repo.DiscussionStopReasonMaxRounds
+	}
+
+	return ""
+}
+
+// GroupDiscussionBudgetAllows 检查讨论剩余的智慧果预算是否足够完成一次预估消耗为 needCoins 的发言
+func GroupDiscussionBudgetAllows(disc *repo.ChatGroupDiscussion, needCoins int64) bool {
+	return disc.CoinBudget <= 0 || disc.CoinsConsumed+needCoins <= disc.CoinBudget
+}
+
+// ParseGroupDiscussionReply 解析成员的发言，移除达成一致的标记，返回发言内容以及是否赞同结束讨论
+func ParseGroupDiscussionReply(text string) (string, bool) {
+	text = strings.TrimSpace(text)
+	if !strings.Contains(text, DiscussionAgreeMarker) {
+		return text, false
+	}
+
+	return strings.TrimSpace(strings.ReplaceAll(text, DiscussionAgreeMarker, "")), true
+}
+
+func groupDiscussionMemberName(mem model.ChatGroupMember) string {
+	return ternary.If(mem.ModelName != "", mem.ModelName, mem.ModelId)
+}
+
+// BuildGroupDiscussionMessages 以指定成员的视角构建讨论上下文（遵循成员的人设以及可见范围）：自己的发言作为 assistant 消息，讨论主题、用户插话以及其他成员的发言作为 user 消息
+func BuildGroupDiscussionMessages(disc *repo.ChatGroupDiscussion, members map[int64]model.ChatGroupMember, speakerID int64, history []model.ChatGroupMessage) chat.Messages {
+	names := array.Map(
+		array.Filter(disc.Members, func(id int64, _ int) bool { _, ok := members[id]; return ok }),
+		func(id int64, _ int) string { return groupDiscussionMemberName(members[id]) },
+	)
+
+	systemPrompt := fmt.Sprintf(groupDiscussionPrompt, strings.Join(names, "、"), groupDiscussionMemberName(members[speakerID]), DiscussionAgreeMarker)
+
+	// 成员自己的人设提示语优先，讨论规则附加在其后
+	persona := repo.MemberPersonaOf(members[speakerID])
+	if persona.SystemPrompt != "" {
+		systemPrompt = persona.SystemPrompt + "\n\n" + systemPrompt
+	}
+
+	messages := chat.Messages{{Role: "system", Content: systemPrompt}}
+
+	for _, msg := range history {
+		if msg.Status != repo.MessageStatusSucceed || strings.TrimSpace(msg.Message) == "" {
+			continue
+		}
+
+		// 根据成员的可见范围，忽略其不可见的其它成员的发言
+		if repo.MessageRole(msg.Role) == repo.MessageRoleAssistant && !persona.CanSee(speakerID, msg.MemberId) {
+			continue
+		}
+
+		var next chat.Message
+		switch {
+		case msg.Id == disc.QuestionId:
+			next = chat.Message{Role: "user", Content: msg.Message}
+		case repo.MessageRole(msg.Role) == repo.MessageRoleUser:
+			next = chat.Message{Role: "user", Content: fmt.Sprintf("[用户]: %s", msg.Message)}
+		case msg.MemberId == speakerID:
+			next = chat.Message{Role: "assistant", Content: msg.Message}
+		default:
+			next = chat.Message{Role: "user", Content: fmt.Sprintf("[%s]: %s", groupDiscussionMemberName(members[msg.MemberId]), msg.Message)}
+		}
+
+		// 连续的同角色消息合并为一条，保证 user/assistant 交替出现
+		if last := len(messages) - 1; messages[last].Role == next.Role {
+			messages[last].Content += "\n\n" + next.Content
+			continue
+		}
+
+		messages = append(messages, next)
+	}
+
+	return messages
+}
+
+// selectGroupDiscussionNextSpeaker 根据讨论的发言顺序策略选择下一位发言者，选择失败时退化为轮流发言，
+// 同时返回选择时使用的模型以及消耗的智慧果
+func selectGroupDiscussionNextSpeaker(
+	ctx context.Context,
+	conf *config.Config,
+	ct chat.Chat,
+	svc *service.Service,
+	disc *repo.ChatGroupDiscussion,
+	members map[int64]model.ChatGroupMember,
+	currentID int64,
+	history []model.ChatGroupMessage,
+) (nextID int64, selectorModel string, quotaConsumed int64) {
+	fallback := disc.NextSpeakerRoundRobin(currentID)
+
+	switch disc.TurnOrder {
+	case repo.DiscussionTurnOrderModerator:
+		selectorModel = members[disc.ModeratorId].ModelId
+	case repo.DiscussionTurnOrderLLM:
+		selectorModel = conf.GroupDiscussionSelectorModel
+	}
+
+	candidates := array.Filter(disc.Members, func(id int64, _ int) bool {
+		_, ok := members[id]
+		return ok && id != currentID
+	})
+	if selectorModel == "" || len(candidates) <= 1 {
+		return fallback, selectorModel, 0
+	}
+
+	mod := svc.Chat.Model(ctx, selectorModel)
+	if mod == nil || mod.Status == repo.ModelStatusDisabled {
+		log.F(log.M{"discussion_id": disc.Id, "model": selectorModel}).Warningf("selector model not found or disabled, using round-robin instead")
+		return fallback, selectorModel, 0
+	}
+
+	transcript := make([]string, 0)
+	for _, msg := range history {
+		if msg.Status != repo.MessageStatusSucceed || strings.TrimSpace(msg.Message) == "" {
+			continue
+		}
+
+		speaker := ternary.If(repo.MessageRole(msg.Role) == repo.MessageRoleUser, "用户", groupDiscussionMemberName(members[msg.MemberId]))
+		transcript = append(transcript, fmt.Sprintf("[%s]: %s", speaker, misc.SubString(msg.Message, 300)))
+	}
+
+	// 只保留最近的 10 条发言，避免选择请求过长
+	if len(transcript) > 10 {
+		transcript = transcript[len(transcript)-10:]
+	}
+
+	options := array.Map(candidates, func(id int64, i int) string {
+		return fmt.Sprintf("%d. %s", i+1, groupDiscussionMemberName(members[id]))
+	})
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	req := chat.Request{
+		Model: selectorModel,
+		Messages: chat.Messages{{
+			Role:    "user",
+			Content: fmt.Sprintf(groupDiscussionSelectorPrompt, strings.Join(transcript, "\n\n"), strings.Join(options, "\n")),
+		}},
+	}
+
+	resp, err := ct.Chat(ctx, req)
+	if err != nil || resp.ErrorCode != "" {
+		log.F(log.M{"discussion_id": disc.Id, "model": selectorModel}).Warningf("select next speaker failed, using round-robin instead: %v", err)
+		return fallback, selectorModel, 0
+	}
+
+	// 优先使用服务端返回的 token 用量，未返回时自行计算
+	inputTokens, outputTokens := resp.InputTokens, resp.OutputTokens
+	if inputTokens == 0 {
+		inputTokens, _ = chat.MessageTokenCount(req.Messages, selectorModel)
+	}
+	if outputTokens == 0 {
+		outputTokens, _ = chat.MessageTokenCount(chat.Messages{{Role: "assistant", Content: resp.Text}}, selectorModel)
+	}
+
+	quotaConsumed = coins.GetTextModelCoins(mod.ToCoinModel(), int64(inputTokens), int64(outputTokens))
+
+	index, err := strconv.Atoi(regexp.MustCompile(`\d+`).FindString(resp.Text))
+	if err != nil || index < 1 || index > len(candidates) {
+		log.F(log.M{"discussion_id": disc.Id, "model": selectorModel, "reply": resp.Text}).Warningf("invalid next speaker reply, using round-robin instead")
+		return fallback, selectorModel, quotaConsumed
+	}
+
+	return candidates[index-1], selectorModel, quotaConsumed
+}
@@ -0,0 +1,97 @@
+package queue_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mylxsw/aidea-server/internal/queue"
+	"github.com/mylxsw/aidea-server/pkg/repo"
+	"github.com/mylxsw/aidea-server/pkg/repo/model"
+	"github.com/mylxsw/go-utils/assert"
+)
+
+func TestParseGroupDiscussionReply(t *testing.T) {
+	text, agreed := queue.ParseGroupDiscussionReply("我同意上面的观点。\n[AGREE]")
+	assert.True(t, agreed)
+	assert.Equal(t, "我同意上面的观点。", text)
+
+	text, agreed = queue.ParseGroupDiscussionReply("  我有不同的看法  ")
+	assert.False(t, agreed)
+	assert.Equal(t, "我有不同的看法", text)
+}
+
+func TestGroupDiscussionStopReason(t *testing.T) {
+	disc := &repo.ChatGroupDiscussion{
+		ChatGroupDiscussion: model.ChatGroupDiscussion{MaxRounds: 2, Turns: 3},
+		Members:             []int64{1, 2},
+	}
+	assert.Equal(t, "", queue.GroupDiscussionStopReason(disc))
+
+	disc.Turns = 4
+	assert.Equal(t, repo.DiscussionStopReasonMaxRounds, queue.GroupDiscussionStopReason(disc))
+
+	disc.Turns = 1
+	disc.CoinBudget, disc.CoinsConsumed = 10, 12
+	assert.Equal(t, repo.DiscussionStopReasonCoinBudget, queue.GroupDiscussionStopReason(disc))
+
+	disc.StopOnConsensus, disc.AgreeCount = 1, 2
+	assert.Equal(t, repo.DiscussionStopReasonConsensus, queue.GroupDiscussionStopReason(disc))
+}
+
+func TestGroupDiscussionBudgetAllows(t *testing.T) {
+	disc := &repo.ChatGroupDiscussion{ChatGroupDiscussion: model.ChatGroupDiscussion{CoinsConsumed: 6}}
+	assert.True(t, queue.GroupDiscussionBudgetAllows(disc, 100))
+
+	disc.CoinBudget = 10
+	assert.True(t, queue.GroupDiscussionBudgetAllows(disc, 4))
+	assert.False(t, queue.GroupDiscussionBudgetAllows(disc, 5))
+}
+
+func TestBuildGroupDiscussionMessages(t *testing.T) {
+	disc := &repo.ChatGroupDiscussion{
+		ChatGroupDiscussion: model.ChatGroupDiscussion{QuestionId: 1},
+		Members:             []int64{10, 20},
+	}
+	members := map[int64]model.ChatGroupMember{
+		10: {Id: 10, ModelName: "GPT"},
+		20: {Id: 20, ModelName: "Claude"},
+	}
+	history := []model.ChatGroupMessage{
+		{Id: 1, Role: int64(repo.MessageRoleUser), Message: "主题", Status: repo.MessageStatusSucceed},
+		{Id: 2, Role: int64(repo.MessageRoleAssistant), MemberId: 10, Message: "观点一", Status: repo.MessageStatusSucceed},
+		{Id: 3, Role: int64(repo.MessageRoleAssistant), MemberId: 20, Message: "观点二", Status: repo.MessageStatusSucceed},
+		{Id: 4, Role: int64(repo.MessageRoleUser), Message: "补充", Status: repo.MessageStatusSucceed},
+		{Id: 5, Role: int64(repo.MessageRoleAssistant), MemberId: 10, Status: repo.MessageStatusWaiting},
+	}
+
+	messages := queue.BuildGroupDiscussionMessages(disc, members, 20, history)
+	assert.Equal(t, 4, len(messages))
+	assert.Equal(t, "system", messages[0].Role)
+	assert.Equal(t, "user", messages[1].Role)
+	assert.Equal(t, "主题\n\n[GPT]: 观点一", messages[1].Content)
+	assert.Equal(t, "assistant", messages[2].Role)
+	assert.Equal(t, "user", messages[3].Role)
+	assert.Equal(t, "[用户]: 补充", messages[3].Content)
+}
+
+func TestBuildGroupDiscussionMessagesWithPersona(t *testing.T) {
+	disc := &repo.ChatGroupDiscussion{
+		ChatGroupDiscussion: model.ChatGroupDiscussion{QuestionId: 1},
+		Members:             []int64{10, 20},
+	}
+	members := map[int64]model.ChatGroupMember{
+		10: {Id: 10, ModelName: "GPT"},
+		20: {Id: 20, ModelName: "Claude", PersonaJson: `{"system_prompt":"你是一名律师","visibility":"self"}`},
+	}
+	history := []model.ChatGroupMessage{
+		{Id: 1, Role: int64(repo.MessageRoleUser), Message: "主题", Status: repo.MessageStatusSucceed},
+		{Id: 2, Role: int64(repo.MessageRoleAssistant), MemberId: 10, Message: "观点一", Status: repo.MessageStatusSucceed},
+		{Id: 3, Role: int64(repo.MessageRoleAssistant), MemberId: 20, Message: "观点二", Status: repo.MessageStatusSucceed},
+	}
+
+	messages := queue.BuildGroupDiscussionMessages(disc, members, 20, history)
+	assert.Equal(t, 3, len(messages))
+	assert.True(t, strings.HasPrefix(messages[0].Content, "你是一名律师"))
+	assert.Equal(t, "主题", messages[1].Content)
+	assert.Equal(t, "观点二", messages[2].Content)
+}
@@ -3,22 +3,24 @@ package queue
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"github.com/mylxsw/aidea-server/config"
 	"github.com/mylxsw/aidea-server/pkg/ai/chat"
-	"github.com/mylxsw/aidea-server/pkg/ai/dashscope"
-	"github.com/mylxsw/aidea-server/pkg/ai/deepai"
-	"github.com/mylxsw/aidea-server/pkg/ai/fromston"
-	"github.com/mylxsw/aidea-server/pkg/ai/getimgai"
-	"github.com/mylxsw/aidea-server/pkg/ai/leap"
+	"github.com/mylxsw/aidea-server/pkg/ai/imagegen"
 	openai2 "github.com/mylxsw/aidea-server/pkg/ai/openai"
-	"github.com/mylxsw/aidea-server/pkg/ai/stabilityai"
 	repo2 "github.com/mylxsw/aidea-server/pkg/repo"
+	"github.com/mylxsw/aidea-server/pkg/repo/model"
 	"github.com/mylxsw/aidea-server/pkg/uploader"
 	"github.com/mylxsw/aidea-server/pkg/youdao"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/hibiken/asynq"
 	"github.com/mylxsw/asteria/log"
+	"github.com/mylxsw/go-utils/array"
+	"github.com/mylxsw/go-utils/ternary"
 )
 
 type ImageCompletionPayload struct {
@@ -50,6 +52,23 @@ type ImageCompletionPayload struct {
 	FilterName     string    `json:"filter_name,omitempty"`
 	GalleryCopyID  int64     `json:"gallery_copy_id,omitempty"`
 
+	// EditMode 图片编辑模式：inpaint（局部重绘）、outpaint（扩图）
+	EditMode string `json:"edit_mode,omitempty"`
+	// Mask 局部重绘的蒙版图片地址，扩图时为扩展画布后自动生成的蒙版
+	Mask string `json:"mask,omitempty"`
+	// Outpaint 扩图时四个方向扩展的像素值
+	Outpaint *repo2.ImageExtent `json:"outpaint,omitempty"`
+	// EditImage 扩图时扩展画布后的图片地址
+	EditImage string `json:"edit_image,omitempty"`
+
+	// RealPrompt 重新生成时使用历史记录中实际使用的提示语，不为空时不再进行过滤器、AI 改写以及翻译处理
+	RealPrompt         string `json:"real_prompt,omitempty"`
+	RealNegativePrompt string `json:"real_negative_prompt,omitempty"`
+	// Variation 使用服务商的变体接口，根据 Image 生成相似的图片
+	Variation bool `json:"variation,omitempty"`
+	// Fallbacks 剩余的备用服务商模型，任务首次执行时从模型配置中读取，生成失败或者超时后依次切换
+	Fallbacks []repo2.ImageModelRef `json:"fallbacks,omitempty"`
+
 	FreezedCoins int64 `json:"freezed_coins,omitempty"`
 }
 
@@ -82,34 +101,61 @@ func NewImageCompletionTask(payload any) *asynq.Task {
 	return asynq.NewTask(TypeImageGenCompletion, data)
 }
 
+// ImagePendingTaskPayload 异步图片生成任务的 PendingTask 载荷
 type ImagePendingTaskPayload struct {
-	LeapTaskID string                 `json:"leap_task_id,omitempty"`
-	Payload    ImageCompletionPayload `json:"payload,omitempty"`
+	// TaskID 服务商侧的任务 ID
+	TaskID  string                 `json:"task_id,omitempty"`
+	Payload ImageCompletionPayload `json:"payload,omitempty"`
+	// Prompt 实际提交给服务商的提示语，切换备用服务商时使用
+	Prompt         string `json:"prompt,omitempty"`
+	NegativePrompt string `json:"negative_prompt,omitempty"`
+	// FallbackAt 超过该时间任务仍未完成时，切换到备用服务商重新生成，没有备用服务商时为空
+	FallbackAt time.Time `json:"fallback_at,omitempty"`
 }
 
-type ImageResponse interface {
-	GetID() string
-	GetState() string
-	IsFinished() bool
-	IsProcessing() bool
-	UploadResources(ctx context.Context, up *uploader.Uploader, uid int64) ([]string, error)
-	GetImages() []string
+// imageGenFallbackTimeout 异步任务超过该时间仍未完成时，切换到备用服务商
+const imageGenFallbackTimeout = 10 * time.Minute
+
+// imageGenRequest 构建图片生成请求，meta 为模型在 image_models 表中的配置
+func (payload *ImageCompletionPayload) imageGenRequest(prompt, negativePrompt string, meta repo2.ImageModelMeta) imagegen.Request {
+	// 局部重绘时，优先使用模型配置的局部重绘工作流
+	workflow := meta.Workflow
+	if payload.Mask != "" && meta.InpaintWorkflow != "" {
+		workflow = meta.InpaintWorkflow
+	}
+
+	return imagegen.Request{
+		Model:          payload.Model,
+		Prompt:         prompt,
+		NegativePrompt: negativePrompt,
+		ImageCount:     payload.ImageCount,
+		Width:          payload.Width,
+		Height:         payload.Height,
+		Steps:          payload.Steps,
+		Seed:           payload.Seed,
+		StylePreset:    payload.StylePreset,
+		Mode:           payload.Mode,
+		UpscaleBy:      payload.UpscaleBy,
+		AIRewrite:      payload.AIRewrite,
+		Image:          ternary.If(payload.EditImage != "", payload.EditImage, payload.Image),
+		ImageStrength:  payload.ImageStrength,
+		Mask:           payload.Mask,
+		Variation:      payload.Variation,
+		Workflow:       workflow,
+		Sampler:        meta.Sampler,
+		CfgScale:       meta.CfgScale,
+	}
 }
 
 func BuildImageCompletionHandler(
 	conf *config.Config,
 	aiProvider *chat.AIProvider,
-	leapClient *leap.LeapAI,
-	stabaiClient *stabilityai.StabilityAI,
-	deepaiClient *deepai.DeepAI,
-	fromstonClient *fromston.Fromston,
-	dashscopeClient *dashscope.DashScope,
-	getimgaiClient *getimgai.GetimgAI,
+	registry *imagegen.Registry,
 	translator youdao.Translater,
 	up *uploader.Uploader,
 	rep *repo2.Repository,
 	oai openai2.Client,
-	dalleClient *openai2.DalleImageClient,
+	que *Queue,
 ) TaskHandler {
 	return func(ctx context.Context, task *asynq.Task) (err error) {
 		var payload ImageCompletionPayload
@@ -123,34 +169,584 @@ func BuildImageCompletionHandler(
 			return nil
 		}
 
+		defer func() {
+			if err2 := recover(); err2 != nil {
+				log.With(task).Errorf("panic: %v", err2)
+				err = err2.(error)
+
+				// 更新创作岛历史记录
+				if err := rep.Creative.UpdateRecordByTaskID(ctx, payload.GetUID(), payload.GetID(), repo2.CreativeRecordUpdateRequest{
+					Status: repo2.CreativeStatusFailed,
+					Answer: err.Error(),
+				}); err != nil {
+					log.WithFields(log.Fields{"payload": payload}).Errorf("update creative failed: %s", err)
+				}
+			}
+
+			if err != nil {
+				if err := rep.Queue.Update(
+					context.TODO(),
+					payload.GetID(),
+					repo2.QueueTaskStatusFailed,
+					ErrorResult{
+						Errors: []string{err.Error()},
+					},
+				); err != nil {
+					log.With(task).Errorf("update queue status failed: %s", err)
+				}
+			}
+		}()
+
+		gen, meta, err := resolveImageGenerator(ctx, registry, rep, payload.Vendor, payload.Model)
+		if err != nil {
+			log.With(payload).Errorf("resolve image generator failed: %v", err)
+			panic(err)
+		}
+
+		// 记录模型配置的备用服务商，生成失败或者超时后依次切换
+		payload.Fallbacks = meta.Fallbacks
+
+		// 扩图，扩展图片画布并生成蒙版，转换为局部重绘处理
+		if payload.EditMode == imagegen.EditModeOutpaint && payload.EditImage == "" {
+			if err := prepareOutpaint(ctx, up, &payload); err != nil {
+				log.With(payload).Errorf("prepare outpaint failed: %v", err)
+				panic(err)
+			}
+		}
+
 		// 如果是图生图，生成图生图提示语
+		originalPrompt := payload.Prompt
 		if payload.Image != "" && payload.Prompt == "" && conf.ImageToImageRecognitionProvider != "" {
 			payload.Prompt = imageToImagePrompt(ctx, aiProvider, conf.ImageToImageRecognitionProvider, payload.Image)
+		}
+
+		// 根据服务商的能力决定是否需要 AI 改写、翻译提示语
+		// 重新生成时，直接使用历史记录中实际使用的提示语，保证生成结果可以复现
+		caps := gen.Capabilities()
+		var prompt, negativePrompt string
+		if payload.RealPrompt != "" {
+			prompt, negativePrompt = payload.RealPrompt, payload.RealNegativePrompt
+		} else {
+			prompt, negativePrompt, payload.AIRewrite = resolvePrompts(
+				ctx,
+				PromptResolverPayload{
+					Prompt:         payload.Prompt,
+					PromptTags:     payload.PromptTags,
+					NegativePrompt: payload.NegativePrompt,
+					FilterID:       payload.FilterID,
+					AIRewrite:      payload.AIRewrite,
+					Image:          payload.Image,
+					Vendor:         payload.Vendor,
+					Model:          payload.Model,
+				},
+				rep.Creative,
+				ternary.If(caps.RewritePrompt, oai, nil),
+				ternary.If(caps.TranslatePrompt, translator, nil),
+			)
+		}
+
+		// 记录实际使用的提示语以及模型配置的生成参数，用于重新生成
+		argUpdate := repo2.CreativeRecordUpdateExtArgs{Sampler: meta.Sampler, CfgScale: meta.CfgScale}
+		if prompt != originalPrompt {
+			argUpdate.RealPrompt = prompt
+		}
+
+		if negativePrompt != payload.NegativePrompt {
+			argUpdate.RealNegativePrompt = negativePrompt
+		}
+
+		if err := rep.Creative.UpdateRecordArgumentsByTaskID(ctx, payload.GetUID(), payload.GetID(), argUpdate); err != nil {
+			log.WithFields(log.Fields{"payload": payload}).Errorf("update creative arguments failed: %s", err)
+		}
+
+		res, err := generateImage(ctx, gen, payload.imageGenRequest(prompt, negativePrompt, meta))
+		if err != nil {
+			log.With(payload).Errorf("[%s] 图片生成失败: %v", payload.Vendor, err)
+
+			res, prompt, negativePrompt, err = fallbackImageGen(ctx, registry, translator, rep, &payload, prompt, negativePrompt, err)
+			if err != nil {
+				panic(err)
+			}
+		}
+
+		if res.Finished {
+			return handleImageGenResult(conf, que, up, rep, &payload, res)
+		}
+
+		// 任务未完成，说明是异步任务，创建 Pending Task，后面检查结果生成后再更新状态
+		if err := createImagePendingTask(ctx, rep, payload, prompt, negativePrompt, res); err != nil {
+			log.WithFields(log.Fields{"payload": payload}).Errorf("create pending task failed: %s", err)
+			panic(err)
+		}
+
+		return rep.Queue.Update(
+			context.TODO(),
+			payload.GetID(),
+			repo2.QueueTaskStatusRunning,
+			nil,
+		)
+	}
+}
+
+// generateImage 调用服务商生成图片，服务商返回失败结果时同样返回 error
+func generateImage(ctx context.Context, gen imagegen.Generator, req imagegen.Request) (*imagegen.Result, error) {
+	res, err := gen.Generate(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.Error != "" {
+		return nil, errors.New(res.Error)
+	}
+
+	if !res.Finished {
+		if _, ok := gen.(imagegen.AsyncGenerator); !ok {
+			return nil, imagegen.ErrNotAsync
+		}
+	}
+
+	return res, nil
+}
+
+// fallbackImageGen 服务商生成失败或者超时后，依次使用备用服务商重新生成图片，返回生成结果以及实际使用的提示语
+// 生成参数根据备用服务商的能力调整，不支持当前任务的备用服务商会被跳过
+// 切换服务商时不会创建新的任务，创作岛历史记录与扣费保持不变，所有备用服务商都失败时返回最后一次失败的原因
+func fallbackImageGen(
+	ctx context.Context,
+	registry *imagegen.Registry,
+	translator youdao.Translater,
+	rep *repo2.Repository,
+	payload *ImageCompletionPayload,
+	prompt, negativePrompt string,
+	cause error,
+) (*imagegen.Result, string, string, error) {
+	for len(payload.Fallbacks) > 0 {
+		target := payload.Fallbacks[0]
+		payload.Fallbacks = payload.Fallbacks[1:]
+
+		logger := log.F(log.M{"task_id": payload.GetID(), "from": payload.Vendor, "vendor": target.Vendor, "model": target.Model})
+
+		gen, meta, err := resolveImageGenerator(ctx, registry, rep, target.Vendor, target.Model)
+		if err != nil {
+			logger.Warningf("备用服务商不可用: %v", err)
+			continue
+		}
+
+		// 提示语已经在主服务商处理过，备用服务商只需要补充翻译以及模型的艺术风格
+		caps := gen.Capabilities()
+		fbPrompt, fbNegativePrompt, _ := resolvePrompts(
+			ctx,
+			PromptResolverPayload{Prompt: prompt, NegativePrompt: negativePrompt, Image: payload.Image, Vendor: target.Vendor, Model: target.Model},
+			rep.Creative,
+			nil,
+			ternary.If(caps.TranslatePrompt, translator, nil),
+		)
+
+		next := *payload
+		next.Vendor, next.Model = target.Vendor, target.Model
+
+		req, err := caps.Adapt(next.imageGenRequest(fbPrompt, fbNegativePrompt, meta), next.ImageRatio)
+		if err != nil {
+			logger.Warningf("备用服务商不支持当前任务: %v", err)
+			continue
+		}
+
+		if dim, ok := meta.RatioDimensions[next.ImageRatio]; ok && dim.Width > 0 && dim.Height > 0 {
+			req.Width, req.Height = int64(dim.Width), int64(dim.Height)
+		}
+
+		next.applyImageGenRequest(req)
+
+		logger.Warningf("图片生成失败，切换到备用服务商: %v", cause)
+
+		res, err := generateImage(ctx, gen, req)
+		if err != nil {
+			logger.Errorf("备用服务商图片生成失败: %v", err)
+			cause = err
+			continue
+		}
+
+		*payload = next
+
+		if err := rep.Creative.UpdateRecordArgumentsByTaskID(ctx, payload.GetUID(), payload.GetID(), repo2.CreativeRecordUpdateExtArgs{
+			FallbackVendor: target.Vendor,
+			FallbackModel:  target.Model,
+		}); err != nil {
+			log.WithFields(log.Fields{"payload": payload}).Errorf("update creative arguments failed: %s", err)
+		}
+
+		return res, req.Prompt, req.NegativePrompt, nil
+	}
+
+	return nil, "", "", cause
+}
+
+// applyImageGenRequest 切换服务商后，将根据服务商能力调整过的生成参数写回任务载荷
+// 生成的图片数量减少时，按比例减少扣费
+func (payload *ImageCompletionPayload) applyImageGenRequest(req imagegen.Request) {
+	if req.ImageCount > 0 && req.ImageCount < payload.ImageCount {
+		payload.Quota = payload.Quota / payload.ImageCount * req.ImageCount
+		payload.ImageCount = req.ImageCount
+	}
+
+	payload.Width, payload.Height = req.Width, req.Height
+	payload.Steps = req.Steps
+	payload.Seed = req.Seed
+	payload.StylePreset = req.StylePreset
+	payload.UpscaleBy = req.UpscaleBy
+	payload.ImageStrength = req.ImageStrength
+}
+
+// createImagePendingTask 异步生成任务创建 PendingTask，由 imageGenAsyncJobProcesser 轮询任务状态
+func createImagePendingTask(ctx context.Context, rep *repo2.Repository, payload ImageCompletionPayload, prompt, negativePrompt string, res *imagegen.Result) error {
+	pending := ImagePendingTaskPayload{
+		TaskID:         res.TaskID,
+		Payload:        payload,
+		Prompt:         prompt,
+		NegativePrompt: negativePrompt,
+	}
+
+	// 还有备用服务商时，任务长时间未完成则切换到备用服务商，不需要等到任务超时
+	if len(payload.Fallbacks) > 0 {
+		pending.FallbackAt = time.Now().Add(imageGenFallbackTimeout)
+	}
+
+	return rep.Queue.CreatePendingTask(ctx, &repo2.PendingTask{
+		TaskID:        payload.GetID(),
+		TaskType:      TypeImageGenCompletion,
+		NextExecuteAt: time.Now().Add(res.RetryAfter),
+		DeadlineAt:    time.Now().Add(30 * time.Minute),
+		Status:        repo2.PendingTaskStatusProcessing,
+		Payload:       pending,
+	})
+}
+
+// imageGenAsyncJobProcesser 异步图片生成任务状态查询
+func imageGenAsyncJobProcesser(
+	conf *config.Config,
+	que *Queue,
+	registry *imagegen.Registry,
+	translator youdao.Translater,
+	up *uploader.Uploader,
+	rep *repo2.Repository,
+) PendingTaskHandler {
+	return func(task *model.QueueTasksPending) (update *repo2.PendingTaskUpdate, err error) {
+		var payload ImagePendingTaskPayload
+		if err := json.Unmarshal([]byte(task.Payload), &payload); err != nil {
+			return nil, err
+		}
+
+		defer func() {
+			if err2 := recover(); err2 != nil {
+				log.With(task).Errorf("panic: %v", err2)
+				err = err2.(error)
+
+				// 更新创作岛历史记录
+				if err := rep.Creative.UpdateRecordByTaskID(context.TODO(), payload.Payload.GetUID(), payload.Payload.GetID(), repo2.CreativeRecordUpdateRequest{
+					Answer: err.Error(),
+					Status: repo2.CreativeStatusFailed,
+				}); err != nil {
+					log.WithFields(log.Fields{"payload": payload}).Errorf("update creative failed: %s", err)
+				}
+
+				update = &repo2.PendingTaskUpdate{Status: repo2.PendingTaskStatusFailed}
+			}
+
+			if err != nil {
+				if err := rep.Queue.Update(
+					context.TODO(),
+					payload.Payload.GetID(),
+					repo2.QueueTaskStatusFailed,
+					ErrorResult{
+						Errors: []string{err.Error()},
+					},
+				); err != nil {
+					log.With(task).Errorf("update queue status failed: %s", err)
+				}
+			}
+		}()
 
-			// 重写 Task，更新 Prompt
-			newPayload, _ := json.Marshal(payload)
-			task = asynq.NewTask(task.Type(), newPayload)
-		}
-
-		switch payload.Vendor {
-		case "leapai":
-			return BuildLeapAICompletionHandler(leapClient, translator, up, rep, oai)(ctx, task)
-		case "deepai":
-			return BuildDeepAICompletionHandler(deepaiClient, translator, up, rep, oai)(ctx, task)
-		case "stabilityai":
-			return BuildStabilityAICompletionHandler(stabaiClient, translator, up, rep, oai)(ctx, task)
-		case "fromston":
-			return BuildFromStonCompletionHandler(fromstonClient, up, rep)(ctx, task)
-		case "getimgai":
-			return BuildGetimgAICompletionHandler(getimgaiClient, translator, up, rep, oai)(ctx, task)
-		case "dashscope":
-			return BuildDashscopeImageCompletionHandler(dashscopeClient, up, rep, translator, oai)(ctx, task)
-		case "dalle":
-			return BuildDalleCompletionHandler(dalleClient, up, rep)(ctx, task)
-		default:
+		gen, meta, err := resolveImageGenerator(context.TODO(), registry, rep, payload.Payload.Vendor, payload.Payload.Model)
+		if err != nil {
+			log.With(payload).Errorf("resolve image generator failed: %v", err)
+			panic(err)
+		}
+
+		asyncGen, ok := gen.(imagegen.AsyncGenerator)
+		if !ok {
+			panic(imagegen.ErrNotAsync)
+		}
+
+		req := payload.Payload.imageGenRequest(payload.Payload.Prompt, payload.Payload.NegativePrompt, meta)
+		res, err := asyncGen.Query(context.TODO(), req, payload.TaskID)
+		if err != nil {
+			log.With(payload).Errorf("query %s job result failed: %v", payload.Payload.Vendor, err)
+			return &repo2.PendingTaskUpdate{
+				NextExecuteAt: time.Now().Add(10 * time.Second),
+				Status:        repo2.PendingTaskStatusProcessing,
+				ExecuteTimes:  task.ExecuteTimes + 1,
+			}, nil
+		}
+
+		var failure error
+		if res.Error != "" {
+			log.WithFields(log.Fields{"payload": payload, "result": res}).Errorf("image generation task failed")
+			failure = errors.New(res.Error)
+		} else if !res.Finished {
+			if payload.FallbackAt.IsZero() || time.Now().Before(payload.FallbackAt) {
+				// 服务商返回了任务进度，更新任务状态，推送进度给用户
+				if res.Progress > 0 {
+					if err := rep.Queue.Update(context.TODO(), payload.Payload.GetID(), repo2.QueueTaskStatusRunning, ProgressResult{Progress: res.Progress}); err != nil {
+						log.WithFields(log.Fields{"payload": payload}).Errorf("update queue task progress failed: %s", err)
+					}
+				}
+
+				return &repo2.PendingTaskUpdate{
+					NextExecuteAt: time.Now().Add(ternary.If(res.RetryAfter > 0, res.RetryAfter, 5*time.Second)),
+					Status:        repo2.PendingTaskStatusProcessing,
+					ExecuteTimes:  task.ExecuteTimes + 1,
+				}, nil
+			}
+
+			// 长时间未完成，取消当前服务商的任务，切换到备用服务商
+			if canceler, ok := gen.(imagegen.Canceler); ok {
+				if err := canceler.Cancel(context.TODO(), req, payload.TaskID); err != nil {
+					log.WithFields(log.Fields{"payload": payload}).Warningf("取消服务商任务失败: %v", err)
+				}
+			}
+
+			failure = errors.New("任务处理超时")
+		}
+
+		if failure != nil {
+			prompt, negativePrompt := payload.Prompt, payload.NegativePrompt
+			if prompt == "" {
+				prompt, negativePrompt = payload.Payload.Prompt, payload.Payload.NegativePrompt
+			}
+
+			res, prompt, negativePrompt, err = fallbackImageGen(context.TODO(), registry, translator, rep, &payload.Payload, prompt, negativePrompt, failure)
+			if err != nil {
+				panic(err)
+			}
+
+			// 备用服务商同样是异步任务，创建新的 PendingTask 继续查询，当前 PendingTask 结束
+			if !res.Finished {
+				if err := createImagePendingTask(context.TODO(), rep, payload.Payload, prompt, negativePrompt, res); err != nil {
+					log.WithFields(log.Fields{"payload": payload}).Errorf("create pending task failed: %s", err)
+					panic(err)
+				}
+
+				return &repo2.PendingTaskUpdate{Status: repo2.PendingTaskStatusFailed}, nil
+			}
+		}
+
+		if err := handleImageGenResult(conf, que, up, rep, &payload.Payload, res); err != nil {
+			log.WithFields(log.Fields{"payload": payload}).Errorf("update creative failed: %s", err)
+			return nil, err
+		}
+
+		return &repo2.PendingTaskUpdate{Status: repo2.PendingTaskStatusSuccess}, nil
+	}
+}
+
+// imageGenAsyncJobCanceler 取消异步图片生成任务，服务商支持取消时调用服务商的取消接口
+func imageGenAsyncJobCanceler(registry *imagegen.Registry, rep *repo2.Repository) PendingTaskCanceler {
+	return func(ctx context.Context, task *model.QueueTasksPending) error {
+		var payload ImagePendingTaskPayload
+		if err := json.Unmarshal([]byte(task.Payload), &payload); err != nil {
+			return err
+		}
+
+		gen, meta, err := resolveImageGenerator(ctx, registry, rep, payload.Payload.Vendor, payload.Payload.Model)
+		if err != nil {
+			return err
+		}
+
+		canceler, ok := gen.(imagegen.Canceler)
+		if !ok {
 			return nil
 		}
+
+		return canceler.Cancel(ctx, payload.Payload.imageGenRequest(payload.Payload.Prompt, payload.Payload.NegativePrompt, meta), payload.TaskID)
+	}
+}
+
+// prepareOutpaint 扩图预处理：扩展原图画布，生成扩展区域的蒙版，上传到云存储后作为局部重绘的图片和蒙版
+func prepareOutpaint(ctx context.Context, up *uploader.Uploader, payload *ImageCompletionPayload) error {
+	if payload.Outpaint == nil {
+		return errors.New("扩图参数不能为空")
+	}
+
+	imagePath, err := uploader.DownloadRemoteFile(ctx, payload.Image)
+	if err != nil {
+		return fmt.Errorf("download image failed: %w", err)
+	}
+	defer os.Remove(imagePath)
+
+	data, err := os.ReadFile(imagePath)
+	if err != nil {
+		return err
+	}
+
+	ext := payload.Outpaint
+	img, mask, err := imagegen.ExtendCanvas(data, int(ext.Left), int(ext.Top), int(ext.Right), int(ext.Bottom))
+	if err != nil {
+		return err
+	}
+
+	if payload.EditImage, err = up.UploadStream(ctx, int(payload.GetUID()), uploader.DefaultUploadExpireAfterDays, img, "png"); err != nil {
+		return fmt.Errorf("upload outpaint image failed: %w", err)
+	}
+
+	if payload.Mask, err = up.UploadStream(ctx, int(payload.GetUID()), uploader.DefaultUploadExpireAfterDays, mask, "png"); err != nil {
+		return fmt.Errorf("upload outpaint mask failed: %w", err)
+	}
+
+	// 扩展区域没有可以参考的内容，需要完全重绘
+	payload.ImageStrength = 1
+
+	return nil
+}
+
+// resolveImageGenerator 查找模型对应的图片生成服务以及模型配置，模型配置了渠道时，使用渠道配置创建
+func resolveImageGenerator(ctx context.Context, registry *imagegen.Registry, rep *repo2.Repository, vendor, modelName string) (imagegen.Generator, repo2.ImageModelMeta, error) {
+	var meta repo2.ImageModelMeta
+	mod, err := rep.Creative.Model(ctx, vendor, modelName)
+	if err != nil {
+		if !errors.Is(err, repo2.ErrNotFound) {
+			log.F(log.M{"vendor": vendor, "model": modelName}).Errorf("query image model failed: %v", err)
+		}
+	} else {
+		meta = mod.ImageMeta
+	}
+
+	gen, err := registry.ResolveChannel(ctx, rep.Model, vendor, meta.ChannelID)
+	return gen, meta, err
+}
+
+// handleImageGenResult 图片生成完成，上传图片到云存储，更新创作岛历史记录、用户配额以及队列任务状态
+func handleImageGenResult(
+	conf *config.Config,
+	que *Queue,
+	up *uploader.Uploader,
+	rep *repo2.Repository,
+	payload *ImageCompletionPayload,
+	res *imagegen.Result,
+) error {
+	resources := uploadGeneratedImages(up, payload.GetUID(), res.Images)
+	if len(resources) == 0 {
+		log.WithFields(log.Fields{
+			"payload": payload,
+		}).Errorf("没有生成任何图片")
+		panic(errors.New("没有生成任何图片"))
+	}
+
+	// 更新创作岛历史记录状态，写入生成的图片资源地址
+	retJson, err := json.Marshal(resources)
+	if err != nil {
+		log.WithFields(log.Fields{"payload": payload}).Errorf("update creative failed: %s", err)
+		panic(err)
+	}
+
+	// 实际生成的图片数量少于请求数量时，按照实际生成的数量计算配额消耗
+	quotaUsed := payload.GetQuota()
+	if payload.ImageCount > 0 && int64(len(resources)) < payload.ImageCount {
+		quotaUsed = payload.GetQuota() / payload.ImageCount * int64(len(resources))
+	}
+
+	req := repo2.CreativeRecordUpdateRequest{
+		Answer:    string(retJson),
+		QuotaUsed: quotaUsed,
+		Status:    repo2.CreativeStatusSuccess,
 	}
+
+	// 记录服务商返回的每张图片实际使用的种子，部分图片上传失败时无法与图片对应，不再记录
+	if len(res.Seeds) > 0 && len(res.Seeds) == len(resources) {
+		req.ExtArguments = &repo2.CreativeRecordUpdateExtArgs{Seeds: res.Seeds}
+	}
+	if err := rep.Creative.UpdateRecordByTaskID(context.TODO(), payload.GetUID(), payload.GetID(), req); err != nil {
+		log.WithFields(log.Fields{"payload": payload}).Errorf("update creative failed: %s", err)
+		panic(err)
+	}
+
+	// 更新用户配额
+	if err := rep.Quota.QuotaConsume(
+		context.TODO(),
+		payload.GetUID(),
+		quotaUsed,
+		repo2.NewQuotaUsedMeta(payload.Vendor, payload.Model, "upload"),
+	); err != nil {
+		log.Errorf("used quota add failed: %s", err)
+	}
+
+	// 部分图片上传云存储失败，触发文件下载上传七牛云任务
+	needDownloadResources := array.Filter(resources, func(item string, _ int) bool { return !strings.HasPrefix(item, conf.StorageDomain) })
+	if len(needDownloadResources) > 0 {
+		downloadPayload := ImageDownloaderPayload{
+			CreativeHistoryTaskID: payload.GetID(),
+			UserID:                payload.GetUID(),
+			CreatedAt:             time.Now(),
+		}
+		downloadTaskID, err := que.Enqueue(&downloadPayload, NewImageDownloaderTask)
+		if err != nil {
+			log.WithFields(log.Fields{"payload": payload}).Errorf("enqueue image downloader task failed: %s", err)
+		} else {
+			log.WithFields(log.Fields{"payload": payload, "task_id": downloadTaskID}).Debugf("enqueue image downloader task success")
+		}
+	}
+
+	// 更新队列任务状态
+	return rep.Queue.Update(
+		context.TODO(),
+		payload.GetID(),
+		repo2.QueueTaskStatusSuccess,
+		CompletionResult{
+			Resources:   resources,
+			OriginImage: payload.Image,
+			ValidBefore: time.Now().Add(7 * 24 * time.Hour),
+		},
+	)
+}
+
+// uploadGeneratedImages 上传生成的图片到云存储，远程图片上传失败时保留原始地址，Base64 图片上传失败时丢弃
+func uploadGeneratedImages(up *uploader.Uploader, uid int64, images []string) []string {
+	resources := make([]string, 0, len(images))
+	for _, img := range images {
+		ret := func() string {
+			ctx, cancel := context.WithTimeout(context.TODO(), 30*time.Second)
+			defer cancel()
+
+			data, isBase64, err := imagegen.DecodeBase64Image(img)
+			if isBase64 {
+				if err != nil {
+					log.Errorf("decode base64 image failed: %v", err)
+					return ""
+				}
+
+				res, err := up.UploadStream(ctx, int(uid), uploader.DefaultUploadExpireAfterDays, data, "png")
+				if err != nil {
+					log.Errorf("upload image to qiniu failed: %v", err)
+					return ""
+				}
+
+				return res
+			}
+
+			res, err := up.UploadRemoteFile(ctx, img, int(uid), uploader.DefaultUploadExpireAfterDays, "png", false)
+			if err != nil {
+				log.F(log.M{"image": img}).Errorf("upload image to qiniu failed: %v", err)
+				return img
+			}
+
+			return res
+		}()
+
+		if ret != "" {
+			resources = append(resources, ret)
+		}
+	}
+
+	return resources
 }
 
 // imageToImagePrompt 通过图像识别生成图生图的提示语，目前仅支持讯飞星火
@@ -0,0 +1,143 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/mylxsw/aidea-server/config"
+	"github.com/mylxsw/aidea-server/pkg/ai/chat"
+	"github.com/mylxsw/aidea-server/pkg/memory"
+	"github.com/mylxsw/aidea-server/pkg/misc"
+	repo2 "github.com/mylxsw/aidea-server/pkg/repo"
+	"github.com/mylxsw/aidea-server/pkg/repo/model"
+	"github.com/mylxsw/asteria/log"
+	"github.com/mylxsw/go-utils/array"
+)
+
+type MemoryExtractPayload struct {
+	ID        string    `json:"id,omitempty"`
+	UserID    int64     `json:"user_id"`
+	RoomID    int64     `json:"room_id"`
+	Question  string    `json:"question"`
+	Answer    string    `json:"answer"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+}
+
+func (payload *MemoryExtractPayload) GetTitle() string {
+	return "提取长期记忆"
+}
+
+func (payload *MemoryExtractPayload) SetID(id string) {
+	payload.ID = id
+}
+
+func (payload *MemoryExtractPayload) GetID() string {
+	return payload.ID
+}
+
+func (payload *MemoryExtractPayload) GetUID() int64 {
+	return payload.UserID
+}
+
+func (payload *MemoryExtractPayload) GetQuotaID() int64 {
+	return 0
+}
+
+func (payload *MemoryExtractPayload) GetQuota() int64 {
+	return 0
+}
+
+func NewMemoryExtractTask(payload any) *asynq.Task {
+	data, _ := json.Marshal(payload)
+	return asynq.NewTask(TypeMemoryExtract, data)
+}
+
+// BuildMemoryExtractHandler 从一轮对话中提取用户的长期记忆
+func BuildMemoryExtractHandler(conf *config.Config, ct chat.Chat, rep *repo2.Repository) TaskHandler {
+	return func(ctx context.Context, task *asynq.Task) (err error) {
+		var payload MemoryExtractPayload
+		if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+			return err
+		}
+
+		// 如果任务是 30 分钟前创建的，不再处理
+		if payload.CreatedAt.Add(30 * time.Minute).Before(time.Now()) {
+			return nil
+		}
+
+		defer func() {
+			if err2 := recover(); err2 != nil {
+				log.With(task).Errorf("panic: %v", err2)
+				err = fmt.Errorf("panic: %v", err2)
+			}
+
+			if err != nil {
+				if err := rep.Queue.Update(
+					context.TODO(),
+					payload.GetID(),
+					repo2.QueueTaskStatusFailed,
+					ErrorResult{
+						Errors: []string{err.Error()},
+					},
+				); err != nil {
+					log.With(task).Errorf("update queue status failed: %s", err)
+				}
+			}
+		}()
+
+		// 用户可能在任务执行前关闭了长期记忆
+		cus, err := rep.User.CustomConfig(ctx, payload.UserID)
+		if err != nil {
+			return fmt.Errorf("query user custom config failed: %w", err)
+		}
+
+		if !cus.MemoryEnabled {
+			return rep.Queue.Update(context.TODO(), payload.GetID(), repo2.QueueTaskStatusSuccess, EmptyResult{})
+		}
+
+		memories, err := rep.Memory.Memories(ctx, payload.UserID)
+		if err != nil {
+			return err
+		}
+
+		known := array.Map(memories, func(m model.UserMemory, _ int) string { return m.Content })
+
+		ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
+		defer cancel()
+
+		resp, err := ct.Chat(ctx, chat.Request{
+			Model: conf.MemoryExtractModel,
+			Messages: chat.Messages{
+				{Role: "system", Content: memory.ExtractPrompt},
+				{Role: "user", Content: memory.BuildExtractMessage(known, misc.SubString(payload.Question, 2000), misc.SubString(payload.Answer, 2000))},
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("extract memories failed: %w", err)
+		}
+
+		if resp.ErrorCode != "" {
+			return fmt.Errorf("extract memories failed: %s", resp.Error)
+		}
+
+		items, err := memory.ParseExtracted(resp.Text)
+		if err != nil {
+			log.F(log.M{"user_id": payload.UserID, "reply": resp.Text}).Warningf("parse extracted memories failed: %s", err)
+			return rep.Queue.Update(context.TODO(), payload.GetID(), repo2.QueueTaskStatusSuccess, EmptyResult{})
+		}
+
+		items = array.Map(memory.Dedup(known, items), func(item string, _ int) string {
+			return misc.SubString(item, repo2.MemoryMaxLength)
+		})
+		if len(items) > 0 {
+			if _, err := rep.Memory.Add(ctx, payload.UserID, payload.RoomID, repo2.MemorySourceAuto, items...); err != nil {
+				return fmt.Errorf("save memories failed: %w", err)
+			}
+		}
+
+		return rep.Queue.Update(context.TODO(), payload.GetID(), repo2.QueueTaskStatusSuccess, EmptyResult{})
+	}
+}
@@ -13,13 +13,20 @@ import (
 
 type PendingTaskHandler func(task *model.QueueTasksPending) (*repo.PendingTaskUpdate, error)
 
+// PendingTaskCanceler 取消服务商侧的异步任务
+type PendingTaskCanceler func(ctx context.Context, task *model.QueueTasksPending) error
+
 type PendingTaskManager struct {
-	lock     sync.RWMutex
-	handlers map[string]PendingTaskHandler
+	lock      sync.RWMutex
+	handlers  map[string]PendingTaskHandler
+	cancelers map[string]PendingTaskCanceler
 }
 
 func NewPendingTaskManager() *PendingTaskManager {
-	return &PendingTaskManager{handlers: make(map[string]PendingTaskHandler)}
+	return &PendingTaskManager{
+		handlers:  make(map[string]PendingTaskHandler),
+		cancelers: make(map[string]PendingTaskCanceler),
+	}
 }
 
 func (m *PendingTaskManager) Register(taskType string, handler PendingTaskHandler) {
@@ -37,6 +44,38 @@ func (m *PendingTaskManager) Get(taskType string) (PendingTaskHandler, bool) {
 	return handler, found
 }
 
+// RegisterCanceler 注册任务取消处理器，用户取消任务时调用服务商的取消接口
+func (m *PendingTaskManager) RegisterCanceler(taskType string, canceler PendingTaskCanceler) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.cancelers[taskType] = canceler
+}
+
+// Cancel 取消处理中的异步任务，不再查询任务状态，任务类型注册了取消处理器时，同时取消服务商侧的任务
+// 任务不存在或者已经结束时返回 repo.ErrNotFound
+func (m *PendingTaskManager) Cancel(ctx context.Context, queueRepo *repo.QueueRepo, taskID string) error {
+	task, err := queueRepo.CancelPendingTask(ctx, taskID)
+	if err != nil {
+		return err
+	}
+
+	m.lock.RLock()
+	canceler, found := m.cancelers[task.TaskType]
+	m.lock.RUnlock()
+
+	if !found {
+		return nil
+	}
+
+	// 服务商取消失败不影响任务的取消，任务状态不会再被查询
+	if err := canceler(ctx, task); err != nil {
+		log.WithFields(log.Fields{"task_id": taskID, "task_type": task.TaskType}).Warningf("取消服务商任务失败: %v", err)
+	}
+
+	return nil
+}
+
 func (m *PendingTaskManager) Remove(taskType string) {
 	m.lock.Lock()
 	defer m.lock.Unlock()
@@ -86,6 +125,11 @@ func handlePendingTask(ctx context.Context, queueRepo *repo.QueueRepo, manager *
 			return err
 		}
 
+		// 同步更新队列任务状态，通知用户任务失败
+		if err := queueRepo.Update(ctx, task.TaskId, repo.QueueTaskStatusFailed, ErrorResult{Errors: []string{"任务处理超时"}}); err != nil {
+			log.WithFields(log.Fields{"task_id": task.TaskId}).Errorf("更新队列任务状态失败: %v", err)
+		}
+
 		return nil
 	}
 
@@ -2,14 +2,19 @@ package queue
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/mylxsw/aidea-server/pkg/ai/dashscope"
 	"github.com/mylxsw/aidea-server/pkg/ai/fromston"
+	"github.com/mylxsw/aidea-server/pkg/ai/imagegen"
 	"github.com/mylxsw/aidea-server/pkg/ai/leap"
 	"github.com/mylxsw/aidea-server/pkg/ai/stabilityai"
 	"github.com/mylxsw/aidea-server/pkg/repo"
+	"github.com/mylxsw/aidea-server/pkg/repo/model"
 	"github.com/mylxsw/aidea-server/pkg/service"
 	"github.com/mylxsw/aidea-server/pkg/uploader"
+	"github.com/mylxsw/aidea-server/pkg/youdao"
 	"time"
 
 	"github.com/hashicorp/go-uuid"
@@ -31,6 +36,13 @@ func (Provider) Register(binder infra.Binder) {
 		})
 	})
 
+	binder.MustSingleton(func(conf *config.Config) *asynq.Inspector {
+		return asynq.NewInspector(asynq.RedisClientOpt{
+			Addr:     conf.RedisAddr(),
+			Password: conf.RedisPassword,
+		})
+	})
+
 	binder.MustSingleton(NewQueue)
 	binder.MustSingleton(NewPendingTaskManager)
 }
@@ -42,11 +54,14 @@ func (Provider) Boot(app infra.Resolver) {
 		fromstonClient *fromston.Fromston,
 		dashscopeClient *dashscope.DashScope,
 		stabilityClient *stabilityai.StabilityAI,
+		imageRegistry *imagegen.Registry,
+		translator youdao.Translater,
 		up *uploader.Uploader,
 		queue *Queue,
 		conf *config.Config,
 		rep *repo.Repository,
 		userSvc *service.UserService,
+		taskEvents *service.TaskEventService,
 		rds *redis.Client,
 	) {
 		// 注册异步 PendingTask 任务处理器
@@ -54,23 +69,22 @@ func (Provider) Boot(app infra.Resolver) {
 		manager.Register(TypeFromStonCompletion, fromStonAsyncJobProcesser(conf, queue, fromstonClient, up, rep))
 		manager.Register(TypeDashscopeImageCompletion, dashscopeImageAsyncJobProcesser(queue, dashscopeClient, up, rep))
 		manager.Register(TypeImageToVideoCompletion, imageToVideoJobProcesser(stabilityClient, up, rep))
+		manager.Register(TypeImageGenCompletion, imageGenAsyncJobProcesser(conf, queue, imageRegistry, translator, up, rep))
+		manager.RegisterCanceler(TypeImageGenCompletion, imageGenAsyncJobCanceler(imageRegistry, rep))
 
 		// 注册创作岛更新后，自动释放冻结的智慧果任务
 		rep.Creative.RegisterRecordStatusUpdateCallback(func(taskID string, userID int64, status repo.CreativeStatus) {
-			key := fmt.Sprintf("creative-island:%d:task:%s:quota-freeze", userID, taskID)
 			if status == repo.CreativeStatusSuccess || status == repo.CreativeStatusFailed {
-				freezedValue, err := rds.Get(context.TODO(), key).Int64()
-				if err != nil {
-					log.F(log.M{"task_id": taskID, "user_id": userID, "status": status}).Errorf("获取创作岛任务冻结的智慧果数量失败：%s", err)
-					return
+				if _, err := ReleaseTaskFrozenQuota(context.TODO(), rds, userSvc, userID, taskID); err != nil {
+					log.F(log.M{"task_id": taskID, "user_id": userID, "status": status}).Errorf("释放创作岛任务冻结的智慧果失败：%s", err)
 				}
+			}
+		})
 
-				if freezedValue > 0 {
-					if err := userSvc.UnfreezeUserQuota(context.TODO(), userID, freezedValue); err != nil {
-						log.F(log.M{"task_id": taskID, "user_id": userID, "status": status}).Errorf("释放创作岛任务冻结的智慧果失败：%s", err)
-						return
-					}
-				}
+		// 注册任务状态变更后，推送任务事件给用户
+		rep.Queue.RegisterStatusUpdateCallback(func(task model.QueueTasks) {
+			if evt := BuildTaskEvent(task); evt != nil {
+				taskEvents.Notify(context.TODO(), *evt)
 			}
 		})
 	})
@@ -95,8 +109,11 @@ const (
 	TypeSignup                   = "signup"
 	TypeBindPhone                = "bind_phone"
 	TypeGroupChat                = "group_chat"
+	TypeGroupDiscussion          = "group_chat:discussion"
 	TypeArtisticTextCompletion   = "artistic_text:completion"
 	TypeImageToVideoCompletion   = "image_to_video:completion"
+	TypeMemoryExtract            = "memory:extract"
+	TypeRoomTitle                = "room:title"
 )
 
 func ResolveTaskType(category, model string) string {
@@ -133,6 +150,12 @@ type CompletionResult struct {
 	Height      int64     `json:"height,omitempty"`
 }
 
+// ProgressResult 任务执行中的进度，服务商返回进度时更新
+type ProgressResult struct {
+	// Progress 进度百分比（0-100）
+	Progress int `json:"progress"`
+}
+
 // ErrorResult 任务失败后的结果
 type ErrorResult struct {
 	Errors []string `json:"errors"`
@@ -140,6 +163,46 @@ type ErrorResult struct {
 
 type EmptyResult struct{}
 
+// BuildTaskEvent 根据队列任务状态构建推送给用户的任务事件，系统任务（没有所属用户）不推送
+func BuildTaskEvent(task model.QueueTasks) *service.TaskEvent {
+	if task.Uid <= 0 {
+		return nil
+	}
+
+	evt := service.TaskEvent{
+		UserID:   task.Uid,
+		TaskID:   task.TaskId,
+		TaskType: task.TaskType,
+	}
+
+	switch repo.QueueTaskStatus(task.Status) {
+	case repo.QueueTaskStatusPending:
+		evt.Type = service.TaskEventQueued
+	case repo.QueueTaskStatusRunning:
+		evt.Type = service.TaskEventRunning
+
+		var progress ProgressResult
+		if task.Result != "" && json.Unmarshal([]byte(task.Result), &progress) == nil {
+			evt.Progress = progress.Progress
+		}
+	case repo.QueueTaskStatusSuccess:
+		evt.Type = service.TaskEventSucceeded
+		evt.Result = json.RawMessage(task.Result)
+	case repo.QueueTaskStatusFailed:
+		evt.Type = service.TaskEventFailed
+		evt.Result = json.RawMessage(task.Result)
+	default:
+		return nil
+	}
+
+	// 结果不是合法的 JSON 时不推送结果，避免事件序列化失败
+	if len(evt.Result) > 0 && !json.Valid(evt.Result) {
+		evt.Result = nil
+	}
+
+	return &evt
+}
+
 // TaskHandler 任务处理器
 type TaskHandler func(context.Context, *asynq.Task) error
 
@@ -159,20 +222,22 @@ type Payload interface {
 // Queue 任务队列
 type Queue struct {
 	client    *asynq.Client
+	inspector *asynq.Inspector
 	queueRepo *repo.QueueRepo
 }
 
 // NewQueue 创建一个任务队列
-func NewQueue(client *asynq.Client, queueRepo *repo.QueueRepo) *Queue {
-	return &Queue{client: client, queueRepo: queueRepo}
+func NewQueue(client *asynq.Client, inspector *asynq.Inspector, queueRepo *repo.QueueRepo) *Queue {
+	return &Queue{client: client, inspector: inspector, queueRepo: queueRepo}
 }
 
 // Enqueue 将任务加入队列
 func (q *Queue) Enqueue(payload Payload, taskBuilder TaskBuilder, opts ...asynq.Option) (string, error) {
 	payload.SetID(must.Must(uuid.GenerateUUID()))
 
+	// 使用任务 ID 作为 asynq 的任务 ID，取消任务时可以直接从队列中删除
 	task := taskBuilder(payload)
-	info, err := q.client.Enqueue(task, opts...)
+	info, err := q.client.Enqueue(task, append([]asynq.Option{asynq.TaskID(payload.GetID())}, opts...)...)
 	if err != nil {
 		return "", err
 	}
@@ -187,3 +252,58 @@ func (q *Queue) Enqueue(payload Payload, taskBuilder TaskBuilder, opts ...asynq.
 		task.Payload(),
 	)
 }
+
+// ErrTaskCancelled 任务已被用户取消
+var ErrTaskCancelled = errors.New("任务已取消")
+
+// Cancel 取消队列中的任务：等待执行的任务直接从队列中删除，正在执行的任务发送取消信号，并将任务状态更新为失败
+func (q *Queue) Cancel(ctx context.Context, taskID string) error {
+	task, err := q.queueRepo.Task(ctx, taskID)
+	if err != nil {
+		return err
+	}
+
+	if err := q.inspector.DeleteTask(task.QueueName, taskID); err != nil && !errors.Is(err, asynq.ErrTaskNotFound) {
+		// 任务正在执行，无法删除，发送取消信号，任务处理器的 context 会被取消
+		if err := q.inspector.CancelProcessing(taskID); err != nil {
+			log.F(log.M{"task_id": taskID}).Warningf("cancel processing task failed: %v", err)
+		}
+	}
+
+	return q.queueRepo.Update(ctx, taskID, repo.QueueTaskStatusFailed, ErrorResult{Errors: []string{ErrTaskCancelled.Error()}})
+}
+
+// ReleaseTaskFrozenQuota 释放创作岛任务提交时冻结的智慧果，返回释放的数量
+// 读取并删除冻结记录在同一个事务中完成，任务完成与任务取消同时发生时，冻结的智慧果只会被释放一次
+func ReleaseTaskFrozenQuota(ctx context.Context, rds *redis.Client, userSvc *service.UserService, userID int64, taskID string) (int64, error) {
+	key := fmt.Sprintf("creative-island:%d:task:%s:quota-freeze", userID, taskID)
+
+	var get *redis.StringCmd
+	if _, err := rds.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		get = pipe.Get(ctx, key)
+		pipe.Del(ctx, key)
+		return nil
+	}); err != nil && !errors.Is(err, redis.Nil) {
+		return 0, fmt.Errorf("获取创作岛任务冻结的智慧果数量失败：%w", err)
+	}
+
+	freezed, err := get.Int64()
+	if err != nil {
+		// 没有冻结记录，可能已经释放或者已经过期
+		if errors.Is(err, redis.Nil) {
+			return 0, nil
+		}
+
+		return 0, err
+	}
+
+	if freezed <= 0 {
+		return 0, nil
+	}
+
+	if err := userSvc.UnfreezeUserQuota(ctx, userID, freezed); err != nil {
+		return 0, err
+	}
+
+	return freezed, nil
+}
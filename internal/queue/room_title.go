@@ -0,0 +1,219 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/mylxsw/aidea-server/config"
+	"github.com/mylxsw/aidea-server/pkg/ai/chat"
+	"github.com/mylxsw/aidea-server/pkg/misc"
+	repo2 "github.com/mylxsw/aidea-server/pkg/repo"
+	"github.com/mylxsw/aidea-server/pkg/service"
+	"github.com/mylxsw/asteria/log"
+)
+
+// roomTitlePrompt 根据首轮对话生成数字人标题和描述的提示语
+const roomTitlePrompt = `You are given the first question and answer of a conversation. Generate a short title (no more than 15 characters for CJK languages or 6 words otherwise) and a one-line description (no more than 50 characters) that summarize the topic of the conversation.
+
+Rules:
+- Use the same language as the user's question.
+- Do not wrap the title in quotes and do not end it with punctuation.
+- Reply with a JSON object only, for example: {"title": "...", "description": "..."}`
+
+const (
+	// RoomTitleMaxLength 自动生成的标题最大长度（与数字人名称限制保持一致）
+	RoomTitleMaxLength = 30
+	// RoomDescriptionMaxLength 自动生成的描述最大长度（与数字人描述限制保持一致）
+	RoomDescriptionMaxLength = 100
+)
+
+type RoomTitlePayload struct {
+	ID        string    `json:"id,omitempty"`
+	UserID    int64     `json:"user_id"`
+	RoomID    int64     `json:"room_id"`
+	Question  string    `json:"question"`
+	Answer    string    `json:"answer"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+}
+
+func (payload *RoomTitlePayload) GetTitle() string {
+	return "生成数字人标题"
+}
+
+func (payload *RoomTitlePayload) SetID(id string) {
+	payload.ID = id
+}
+
+func (payload *RoomTitlePayload) GetID() string {
+	return payload.ID
+}
+
+func (payload *RoomTitlePayload) GetUID() int64 {
+	return payload.UserID
+}
+
+func (payload *RoomTitlePayload) GetQuotaID() int64 {
+	return 0
+}
+
+func (payload *RoomTitlePayload) GetQuota() int64 {
+	return 0
+}
+
+func NewRoomTitleTask(payload any) *asynq.Task {
+	data, _ := json.Marshal(payload)
+	return asynq.NewTask(TypeRoomTitle, data)
+}
+
+// RoomTitle 自动生成的数字人标题和描述
+type RoomTitle struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+// ParseRoomTitle 解析模型返回的标题和描述，兼容 Markdown 代码块包裹的 JSON，超长的内容会被截断
+func ParseRoomTitle(text string) (*RoomTitle, error) {
+	text = strings.TrimSpace(text)
+	if strings.HasPrefix(text, "```") {
+		text = strings.TrimPrefix(text[strings.Index(text, "\n")+1:], "\n")
+		text = strings.TrimSuffix(strings.TrimSpace(text), "```")
+	}
+
+	start, end := strings.Index(text, "{"), strings.LastIndex(text, "}")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("invalid room title: %s", text)
+	}
+
+	var ret RoomTitle
+	if err := json.Unmarshal([]byte(text[start:end+1]), &ret); err != nil {
+		return nil, fmt.Errorf("invalid room title: %w", err)
+	}
+
+	ret.Title = truncateRunes(strings.Trim(strings.TrimSpace(ret.Title), `"'“”「」《》`), RoomTitleMaxLength)
+	ret.Description = truncateRunes(strings.TrimSpace(ret.Description), RoomDescriptionMaxLength)
+	if ret.Title == "" {
+		return nil, errors.New("room title is empty")
+	}
+
+	return &ret, nil
+}
+
+func truncateRunes(text string, length int) string {
+	runes := []rune(text)
+	if len(runes) <= length {
+		return text
+	}
+
+	return strings.TrimSpace(string(runes[:length]))
+}
+
+// BuildRoomTitleHandler 根据首轮对话自动生成数字人的标题和描述，用户已经修改过名称的数字人不会被覆盖
+func BuildRoomTitleHandler(conf *config.Config, ct chat.Chat, rep *repo2.Repository, svc *service.Service) TaskHandler {
+	return func(ctx context.Context, task *asynq.Task) (err error) {
+		var payload RoomTitlePayload
+		if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+			return err
+		}
+
+		// 如果任务是 30 分钟前创建的，不再处理
+		if payload.CreatedAt.Add(30 * time.Minute).Before(time.Now()) {
+			return nil
+		}
+
+		defer func() {
+			if err2 := recover(); err2 != nil {
+				log.With(task).Errorf("panic: %v", err2)
+				err = fmt.Errorf("panic: %v", err2)
+			}
+
+			if err != nil {
+				if err := rep.Queue.Update(
+					context.TODO(),
+					payload.GetID(),
+					repo2.QueueTaskStatusFailed,
+					ErrorResult{
+						Errors: []string{err.Error()},
+					},
+				); err != nil {
+					log.With(task).Errorf("update queue status failed: %s", err)
+				}
+			}
+		}()
+
+		room, err := rep.Room.Room(ctx, payload.UserID, payload.RoomID)
+		if err != nil {
+			if errors.Is(err, repo2.ErrNotFound) {
+				return rep.Queue.Update(context.TODO(), payload.GetID(), repo2.QueueTaskStatusSuccess, EmptyResult{})
+			}
+
+			return fmt.Errorf("query room failed: %w", err)
+		}
+
+		// 用户可能在任务执行前已经修改了名称
+		if room.AutoTitle != 1 {
+			return rep.Queue.Update(context.TODO(), payload.GetID(), repo2.QueueTaskStatusSuccess, EmptyResult{})
+		}
+
+		// 先清除自动标题标记，生成失败时也不会在后续的每次回复中重复触发
+		claimed, err := rep.Room.ClaimAutoTitle(ctx, payload.UserID, payload.RoomID)
+		if err != nil {
+			return fmt.Errorf("claim room auto title failed: %w", err)
+		}
+
+		if !claimed {
+			return rep.Queue.Update(context.TODO(), payload.GetID(), repo2.QueueTaskStatusSuccess, EmptyResult{})
+		}
+
+		chatCtx, cancel := context.WithTimeout(ctx, 60*time.Second)
+		defer cancel()
+
+		resp, err := ct.Chat(chatCtx, chat.Request{
+			Model: conf.RoomTitleModel,
+			Messages: chat.Messages{
+				{Role: "system", Content: roomTitlePrompt},
+				{Role: "user", Content: fmt.Sprintf("Question:\n%s\n\nAnswer:\n%s", misc.SubString(payload.Question, 1000), misc.SubString(payload.Answer, 1000))},
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("generate room title failed: %w", err)
+		}
+
+		if resp.ErrorCode != "" {
+			return fmt.Errorf("generate room title failed: %s", resp.Error)
+		}
+
+		title, err := ParseRoomTitle(resp.Text)
+		if err != nil {
+			log.F(log.M{"user_id": payload.UserID, "room_id": payload.RoomID, "reply": resp.Text}).Warningf("parse room title failed: %s", err)
+			return rep.Queue.Update(context.TODO(), payload.GetID(), repo2.QueueTaskStatusSuccess, EmptyResult{})
+		}
+
+		room.Name, room.AutoTitle = title.Title, 0
+		if title.Description != "" {
+			room.Description = title.Description
+		}
+
+		// 版本号不一致说明生成标题期间数字人被修改过，此时放弃更新，避免覆盖用户的修改
+		if err := rep.Room.Update(ctx, payload.UserID, payload.RoomID, room); err != nil {
+			if errors.Is(err, repo2.ErrRoomVersionConflict) {
+				log.F(log.M{"user_id": payload.UserID, "room_id": payload.RoomID}).Warningf("room has been modified, skip auto title")
+				return rep.Queue.Update(context.TODO(), payload.GetID(), repo2.QueueTaskStatusSuccess, EmptyResult{})
+			}
+
+			return fmt.Errorf("update room failed: %w", err)
+		}
+
+		if err := svc.Chat.ForgetRoom(ctx, payload.UserID, payload.RoomID); err != nil {
+			log.F(log.M{"user_id": payload.UserID, "room_id": payload.RoomID}).Errorf("forget room cache failed: %s", err)
+		}
+
+		svc.Sync.Notify(ctx, payload.UserID, "", repo2.SyncEntityRoom, payload.RoomID)
+
+		return rep.Queue.Update(context.TODO(), payload.GetID(), repo2.QueueTaskStatusSuccess, EmptyResult{})
+	}
+}
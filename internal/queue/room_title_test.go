@@ -0,0 +1,32 @@
+package queue_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mylxsw/aidea-server/internal/queue"
+	"github.com/mylxsw/go-utils/assert"
+)
+
+func TestParseRoomTitle(t *testing.T) {
+	title, err := queue.ParseRoomTitle(`{"title": "“周末旅行计划”", "description": "讨论杭州两日游的行程安排"}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "周末旅行计划", title.Title)
+	assert.Equal(t, "讨论杭州两日游的行程安排", title.Description)
+
+	title, err = queue.ParseRoomTitle("```json\n{\"title\": \"Go generics\", \"description\": \"How type parameters work\"}\n```")
+	assert.NoError(t, err)
+	assert.Equal(t, "Go generics", title.Title)
+	assert.Equal(t, "How type parameters work", title.Description)
+
+	title, err = queue.ParseRoomTitle(`{"title": "` + strings.Repeat("长", 50) + `"}`)
+	assert.NoError(t, err)
+	assert.Equal(t, queue.RoomTitleMaxLength, len([]rune(title.Title)))
+	assert.Equal(t, "", title.Description)
+
+	_, err = queue.ParseRoomTitle(`{"title": "  ", "description": "empty"}`)
+	assert.True(t, err != nil)
+
+	_, err = queue.ParseRoomTitle("not a json")
+	assert.True(t, err != nil)
+}
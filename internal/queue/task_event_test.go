@@ -0,0 +1,32 @@
+package queue_test
+
+import (
+	"testing"
+
+	"github.com/mylxsw/aidea-server/internal/queue"
+	"github.com/mylxsw/aidea-server/pkg/repo"
+	"github.com/mylxsw/aidea-server/pkg/repo/model"
+	"github.com/mylxsw/aidea-server/pkg/service"
+	"github.com/mylxsw/go-utils/assert"
+)
+
+func TestBuildTaskEvent(t *testing.T) {
+	assert.True(t, queue.BuildTaskEvent(model.QueueTasks{TaskId: "t1", Status: string(repo.QueueTaskStatusPending)}) == nil)
+
+	evt := queue.BuildTaskEvent(model.QueueTasks{Uid: 1, TaskId: "t1", TaskType: queue.TypeImageGenCompletion, Status: string(repo.QueueTaskStatusPending)})
+	assert.Equal(t, service.TaskEventQueued, evt.Type)
+	assert.Equal(t, int64(1), evt.UserID)
+	assert.Equal(t, queue.TypeImageGenCompletion, evt.TaskType)
+
+	evt = queue.BuildTaskEvent(model.QueueTasks{Uid: 1, TaskId: "t1", Status: string(repo.QueueTaskStatusRunning), Result: `{"progress":40}`})
+	assert.Equal(t, service.TaskEventRunning, evt.Type)
+	assert.Equal(t, 40, evt.Progress)
+
+	evt = queue.BuildTaskEvent(model.QueueTasks{Uid: 1, TaskId: "t1", Status: string(repo.QueueTaskStatusSuccess), Result: `{"resources":["a.png"]}`})
+	assert.Equal(t, service.TaskEventSucceeded, evt.Type)
+	assert.Equal(t, `{"resources":["a.png"]}`, string(evt.Result))
+
+	evt = queue.BuildTaskEvent(model.QueueTasks{Uid: 1, TaskId: "t1", Status: string(repo.QueueTaskStatusFailed), Result: "invalid"})
+	assert.Equal(t, service.TaskEventFailed, evt.Type)
+	assert.True(t, evt.Result == nil)
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/mylxsw/aidea-server/pkg/ai/fromston"
 	"github.com/mylxsw/aidea-server/pkg/ai/getimgai"
 	"github.com/mylxsw/aidea-server/pkg/ai/gpt360"
+	"github.com/mylxsw/aidea-server/pkg/ai/imagegen"
 	"github.com/mylxsw/aidea-server/pkg/ai/leap"
 	"github.com/mylxsw/aidea-server/pkg/ai/lepton"
 	"github.com/mylxsw/aidea-server/pkg/ai/oneapi"
@@ -39,6 +40,7 @@ import (
 	"github.com/mylxsw/aidea-server/pkg/aliyun"
 	"github.com/mylxsw/aidea-server/pkg/dingding"
 	"github.com/mylxsw/aidea-server/pkg/mail"
+	"github.com/mylxsw/aidea-server/pkg/moderation"
 	"github.com/mylxsw/aidea-server/pkg/proxy"
 	"github.com/mylxsw/aidea-server/pkg/rate"
 	"github.com/mylxsw/aidea-server/pkg/redis"
@@ -147,6 +149,7 @@ func main() {
 		uploader.Provider{},
 		tencent.Provider{},
 		aliyun.Provider{},
+		moderation.Provider{},
 		sms.Provider{},
 		mail.Provider{},
 		dingding.Provider{},
@@ -166,6 +169,7 @@ func main() {
 		fromston.Provider{},
 		getimgai.Provider{},
 		dashscope.Provider{},
+		imagegen.Provider{},
 		xfyun.Provider{},
 		leap.Provider{},
 		baidu.Provider{},
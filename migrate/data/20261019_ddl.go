@@ -0,0 +1,27 @@
+package data
+
+import "github.com/mylxsw/eloquent/migrate"
+
+func Migrate20261019DDL(m *migrate.Manager) {
+	m.Schema("20261019-ddl").Create("chat_group_discussion", func(builder *migrate.Builder) {
+		builder.Increments("id")
+		builder.Timestamps(0)
+		builder.Integer("user_id", false, true).Comment("用户ID")
+		builder.Integer("group_id", false, true).Comment("群组ID")
+		builder.Integer("question_id", false, true).Comment("讨论主题对应的消息ID")
+		builder.String("member_ids", 255).Nullable(false).Comment("参与讨论的成员ID，多个使用英文逗号分隔")
+		builder.String("turn_order", 20).Nullable(false).Comment("发言顺序：round-robin/moderator/llm")
+		builder.Integer("moderator_id", false, true).Nullable(true).Comment("主持人成员ID")
+		builder.Integer("max_rounds", false, true).Nullable(false).Comment("最大讨论轮数")
+		builder.TinyInteger("stop_on_consensus", false, true).Nullable(true).Comment("达成共识后是否结束讨论：0-否 1-是")
+		builder.Integer("coin_budget", false, true).Nullable(true).Comment("智慧果预算，0 为不限制")
+		builder.Integer("coins_consumed", false, true).Nullable(true).Comment("已消耗的智慧果")
+		builder.Integer("turns", false, true).Nullable(true).Comment("已完成的发言次数")
+		builder.Integer("agree_count", false, true).Nullable(true).Comment("连续表示赞同的发言次数")
+		builder.Integer("next_member_id", false, true).Nullable(true).Comment("下一位发言的成员ID")
+		builder.TinyInteger("status", false, true).Nullable(false).Comment("状态：1-进行中 2-已结束 3-已终止")
+		builder.String("stop_reason", 50).Nullable(true).Comment("结束原因")
+
+		builder.Index("idx_group_user", "group_id", "user_id")
+	})
+}
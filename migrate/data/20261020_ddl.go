@@ -0,0 +1,9 @@
+package data
+
+import "github.com/mylxsw/eloquent/migrate"
+
+func Migrate20261020DDL(m *migrate.Manager) {
+	m.Schema("20261020-ddl").Table("chat_group_member", func(builder *migrate.Builder) {
+		builder.Json("persona_json").Nullable(true).Comment("成员人设：系统提示语、温度、可见范围等")
+	})
+}
@@ -0,0 +1,18 @@
+package data
+
+import "github.com/mylxsw/eloquent/migrate"
+
+// Migrate20261021DDL 为聊天记录创建全文索引，使用 ngram 解析器以支持中文分词
+func Migrate20261021DDL(m *migrate.Manager) {
+	m.Schema("20261021-ddl").Raw("chat_messages", func() []string {
+		return []string{
+			"ALTER TABLE `chat_messages` ADD FULLTEXT INDEX `ft_message` (`message`) WITH PARSER ngram",
+		}
+	})
+
+	m.Schema("20261021-ddl").Raw("chat_group_message", func() []string {
+		return []string{
+			"ALTER TABLE `chat_group_message` ADD FULLTEXT INDEX `ft_message` (`message`) WITH PARSER ngram",
+		}
+	})
+}
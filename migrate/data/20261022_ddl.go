@@ -0,0 +1,24 @@
+package data
+
+import "github.com/mylxsw/eloquent/migrate"
+
+func Migrate20261022DDL(m *migrate.Manager) {
+	m.Schema("20261022-ddl").Table("rooms", func(builder *migrate.Builder) {
+		builder.Integer("version", false, true).Nullable(true).Comment("版本号，每次修改后递增，用于多端同步时的冲突检测")
+		builder.Index("idx_user_updated", "user_id", "updated_at")
+	})
+
+	m.Schema("20261022-ddl").Table("chat_messages", func(builder *migrate.Builder) {
+		builder.Index("idx_user_updated", "user_id", "updated_at")
+	})
+
+	m.Schema("20261022-ddl").Create("sync_tombstone", func(builder *migrate.Builder) {
+		builder.Increments("id")
+		builder.Timestamps(0)
+		builder.Integer("user_id", false, true).Comment("用户ID")
+		builder.String("entity_type", 20).Comment("被删除的数据类型：room/message")
+		builder.Integer("entity_id", false, true).Comment("被删除的数据ID")
+
+		builder.Index("idx_user_id", "user_id", "id")
+	})
+}
@@ -0,0 +1,33 @@
+package data
+
+import "github.com/mylxsw/eloquent/migrate"
+
+func Migrate20261023DDL(m *migrate.Manager) {
+	m.Schema("20261023-ddl").Table("rooms", func(builder *migrate.Builder) {
+		builder.Integer("folder_id", false, true).Nullable(true).Comment("所属文件夹ID，0 为未分类")
+		builder.TinyInteger("pinned", false, true).Nullable(true).Comment("是否置顶：0-否 1-是")
+		builder.TinyInteger("archived", false, true).Nullable(true).Comment("是否归档：0-否 1-是")
+		builder.Index("idx_user_folder", "user_id", "folder_id")
+	})
+
+	m.Schema("20261023-ddl").Create("room_folder", func(builder *migrate.Builder) {
+		builder.Increments("id")
+		builder.Timestamps(0)
+		builder.Integer("user_id", false, true).Comment("用户ID")
+		builder.String("name", 50).Comment("文件夹名称")
+		builder.Integer("sort", false, true).Nullable(true).Comment("排序，值越小越靠前")
+
+		builder.Index("idx_user_id", "user_id")
+	})
+
+	m.Schema("20261023-ddl").Create("room_tag", func(builder *migrate.Builder) {
+		builder.Increments("id")
+		builder.Timestamps(0)
+		builder.Integer("user_id", false, true).Comment("用户ID")
+		builder.Integer("room_id", false, true).Comment("房间ID")
+		builder.String("name", 30).Comment("标签名称")
+
+		builder.Unique("uk_room_name", "room_id", "name")
+		builder.Index("idx_user_name", "user_id", "name")
+	})
+}
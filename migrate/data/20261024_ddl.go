@@ -0,0 +1,20 @@
+package data
+
+import "github.com/mylxsw/eloquent/migrate"
+
+func Migrate20261024DDL(m *migrate.Manager) {
+	m.Schema("20261024-ddl").Table("rooms", func(builder *migrate.Builder) {
+		builder.TinyInteger("memory_disabled", false, true).Nullable(true).Comment("是否禁用长期记忆：0-否 1-是")
+	})
+
+	m.Schema("20261024-ddl").Create("user_memory", func(builder *migrate.Builder) {
+		builder.Increments("id")
+		builder.Timestamps(0)
+		builder.Integer("user_id", false, true).Comment("用户ID")
+		builder.String("content", 500).Comment("记忆内容")
+		builder.Integer("room_id", false, true).Nullable(true).Comment("提取记忆的房间ID")
+		builder.String("source", 20).Nullable(true).Comment("来源：auto-从对话中提取 manual-用户手动添加")
+
+		builder.Index("idx_user_id", "user_id")
+	})
+}
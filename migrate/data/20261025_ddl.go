@@ -0,0 +1,16 @@
+package data
+
+import "github.com/mylxsw/eloquent/migrate"
+
+func Migrate20261025DDL(m *migrate.Manager) {
+	m.Schema("20261025-ddl").Table("chat_messages_share", func(builder *migrate.Builder) {
+		builder.String("share_type", 20).Nullable(true).Comment("分享类型：messages-聊天记录 creative-创作岛作品，为空时为聊天记录")
+		builder.String("password", 256).Nullable(true).Comment("访问密码（加密后）")
+		builder.Timestamp("expired_at", 0).Nullable(true).Comment("过期时间，为空时永不过期")
+		builder.TinyInteger("revoked", false, true).Nullable(true).Comment("是否已撤销：0-否 1-是")
+		builder.Integer("views", false, true).Nullable(true).Comment("访问次数")
+		builder.Timestamp("last_viewed_at", 0).Nullable(true).Comment("最后访问时间")
+
+		builder.Index("idx_user_id", "user_id")
+	})
+}
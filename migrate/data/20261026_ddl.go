@@ -0,0 +1,9 @@
+package data
+
+import "github.com/mylxsw/eloquent/migrate"
+
+func Migrate20261026DDL(m *migrate.Manager) {
+	m.Schema("20261026-ddl").Table("rooms", func(builder *migrate.Builder) {
+		builder.TinyInteger("auto_title", false, true).Nullable(true).Comment("是否等待自动生成标题：0-否 1-是，用户修改名称后不再自动生成")
+	})
+}
@@ -0,0 +1,23 @@
+package data
+
+import "github.com/mylxsw/eloquent/migrate"
+
+func Migrate20261027DDL(m *migrate.Manager) {
+	m.Schema("20261027-ddl").Create("prompt_template", func(builder *migrate.Builder) {
+		builder.Increments("id")
+		builder.Timestamps(0)
+		builder.Integer("user_id", false, true).Comment("用户ID")
+		builder.String("title", 100).Comment("模板标题")
+		builder.String("description", 255).Nullable(true).Comment("模板描述")
+		builder.Text("content").Comment("模板内容，使用 {{name}} 引用变量")
+		builder.Json("variables").Nullable(true).Comment("变量定义，JSON 格式")
+		builder.Json("tags").Nullable(true).Comment("标签，JSON 格式")
+		builder.TinyInteger("published", false, true).Nullable(true).Comment("是否发布到公共模板库：0-否 1-是")
+		builder.Timestamp("published_at", 0).Nullable(true).Comment("发布时间")
+		builder.Integer("copy_count", false, true).Nullable(true).Comment("被复制次数")
+		builder.Integer("source_id", false, true).Nullable(true).Comment("复制来源模板ID")
+
+		builder.Index("idx_user_id", "user_id")
+		builder.Index("idx_published", "published", "copy_count")
+	})
+}
@@ -0,0 +1,11 @@
+package data
+
+import "github.com/mylxsw/eloquent/migrate"
+
+func Migrate20261028DDL(m *migrate.Manager) {
+	m.Schema("20261028-ddl").Table("chat_messages", func(builder *migrate.Builder) {
+		builder.TinyInteger("pinned", false, true).Nullable(true).Comment("是否置顶到上下文：0-否 1-是，置顶的消息总是包含在对话上下文中")
+
+		builder.Index("idx_room_pinned", "user_id", "room_id", "pinned")
+	})
+}
@@ -0,0 +1,13 @@
+package data
+
+import "github.com/mylxsw/eloquent/migrate"
+
+func Migrate20261029DDL(m *migrate.Manager) {
+	m.Schema("20261029-ddl").Table("rooms", func(builder *migrate.Builder) {
+		builder.Json("presets_json").Nullable(true).Comment("生成参数预设：温度、最大输出 Tokens、推理强度、联网搜索等，JSON 格式")
+	})
+
+	m.Schema("20261029-ddl").Table("room_gallery", func(builder *migrate.Builder) {
+		builder.Json("presets_json").Nullable(true).Comment("生成参数预设，复制数字人时一并复制，JSON 格式")
+	})
+}
@@ -0,0 +1,30 @@
+package data
+
+import "github.com/mylxsw/eloquent/migrate"
+
+func Migrate20261030DDL(m *migrate.Manager) {
+	m.Schema("20261030-ddl").Table("chat_messages", func(builder *migrate.Builder) {
+		builder.String("channel", 50).Nullable(true).Comment("实际回答问题的渠道")
+	})
+
+	m.Schema("20261030-ddl").Table("chat_group_message", func(builder *migrate.Builder) {
+		builder.String("channel", 50).Nullable(true).Comment("实际回答问题的渠道")
+	})
+
+	m.Schema("20261030-ddl").Create("message_feedback", func(builder *migrate.Builder) {
+		builder.Increments("id")
+		builder.Timestamps(0)
+		builder.Integer("user_id", false, true).Comment("用户ID")
+		builder.String("message_type", 10).Comment("消息类型：chat-单聊 group-群聊")
+		builder.Integer("message_id", false, true).Comment("消息ID")
+		builder.Integer("room_id", false, true).Nullable(true).Comment("数字人ID或者群组ID")
+		builder.String("model", 100).Nullable(true).Comment("回答问题的模型")
+		builder.String("channel", 50).Nullable(true).Comment("回答问题的渠道")
+		builder.TinyInteger("rating", false, false).Comment("评价：1-赞 -1-踩")
+		builder.Json("reasons").Nullable(true).Comment("原因分类，JSON 格式")
+		builder.String("comment", 500).Nullable(true).Comment("用户补充说明")
+
+		builder.Unique("uk_user_message", "user_id", "message_type", "message_id")
+		builder.Index("idx_created_at", "created_at")
+	})
+}
@@ -0,0 +1,51 @@
+package data
+
+import "github.com/mylxsw/eloquent/migrate"
+
+func Migrate20261031DDL(m *migrate.Manager) {
+	m.Schema("20261031-ddl").Table("creative_gallery", func(builder *migrate.Builder) {
+		builder.Integer("like_count", false, true).Nullable(true).Comment("点赞数")
+		builder.Integer("favorite_count", false, true).Nullable(true).Comment("收藏数")
+		builder.Integer("comment_count", false, true).Nullable(true).Comment("评论数")
+		builder.Integer("report_count", false, true).Nullable(true).Comment("待处理的举报数")
+		builder.Integer("hot_boost", false, false).Nullable(true).Comment("管理员设置的热度加成，排序任务计算热度时累加")
+	})
+
+	m.Schema("20261031-ddl").Create("creative_gallery_interaction", func(builder *migrate.Builder) {
+		builder.Increments("id")
+		builder.Timestamps(0)
+		builder.Integer("user_id", false, true).Comment("用户ID")
+		builder.Integer("gallery_id", false, true).Comment("作品ID")
+		builder.String("type", 20).Comment("互动类型：like-点赞 favorite-收藏")
+
+		builder.Unique("uk_user_gallery_type", "user_id", "gallery_id", "type")
+		builder.Index("idx_gallery_type", "gallery_id", "type")
+	})
+
+	m.Schema("20261031-ddl").Create("creative_gallery_comment", func(builder *migrate.Builder) {
+		builder.Increments("id")
+		builder.Timestamps(0)
+		builder.Integer("gallery_id", false, true).Comment("作品ID")
+		builder.Integer("user_id", false, true).Comment("用户ID")
+		builder.String("username", 100).Nullable(true).Comment("评论时的用户名")
+		builder.String("content", 500).Comment("评论内容")
+		builder.TinyInteger("status", false, true).Nullable(false).Comment("状态：1-正常 2-已删除 3-已屏蔽")
+
+		builder.Index("idx_gallery_status", "gallery_id", "status")
+	})
+
+	m.Schema("20261031-ddl").Create("creative_gallery_report", func(builder *migrate.Builder) {
+		builder.Increments("id")
+		builder.Timestamps(0)
+		builder.Integer("gallery_id", false, true).Comment("作品ID")
+		builder.Integer("user_id", false, true).Comment("举报人用户ID")
+		builder.String("reason", 20).Comment("举报原因：porn-色情 violence-暴力 politics-政治敏感 copyright-侵权 spam-广告 other-其它")
+		builder.String("comment", 500).Nullable(true).Comment("举报补充说明")
+		builder.TinyInteger("status", false, true).Nullable(false).Comment("状态：0-待处理 1-已下架作品 2-已驳回")
+		builder.Integer("handled_by", false, true).Nullable(true).Comment("处理人用户ID")
+		builder.Timestamp("handled_at", 0).Nullable(true).Comment("处理时间")
+
+		builder.Unique("uk_user_gallery", "user_id", "gallery_id")
+		builder.Index("idx_status_gallery", "status", "gallery_id")
+	})
+}
@@ -0,0 +1,23 @@
+package data
+
+import "github.com/mylxsw/eloquent/migrate"
+
+func Migrate20261101DDL(m *migrate.Manager) {
+	// 多端同步使用数据库维护的微秒级变更时间分页，所有写入路径都会自动更新，不依赖秒级的 updated_at，
+	// 原先用于同步的 idx_user_updated 索引不再需要
+	m.Schema("20261101-ddl").Raw("rooms", func() []string {
+		return []string{
+			"ALTER TABLE rooms ADD COLUMN sync_at TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6) COMMENT '最后变更时间（微秒），用于多端同步'",
+			"CREATE INDEX idx_user_sync ON rooms (user_id, sync_at, id)",
+			"DROP INDEX idx_user_updated ON rooms",
+		}
+	})
+
+	m.Schema("20261101-ddl").Raw("chat_messages", func() []string {
+		return []string{
+			"ALTER TABLE chat_messages ADD COLUMN sync_at TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6) COMMENT '最后变更时间（微秒），用于多端同步'",
+			"CREATE INDEX idx_user_sync ON chat_messages (user_id, sync_at, id)",
+			"DROP INDEX idx_user_updated ON chat_messages",
+		}
+	})
+}
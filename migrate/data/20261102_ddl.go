@@ -0,0 +1,9 @@
+package data
+
+import "github.com/mylxsw/eloquent/migrate"
+
+func Migrate20261102DDL(m *migrate.Manager) {
+	m.Schema("20261102-ddl").Table("chat_group_message", func(builder *migrate.Builder) {
+		builder.String("model", 100).Nullable(true).Comment("实际回答问题的模型")
+	})
+}
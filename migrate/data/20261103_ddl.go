@@ -0,0 +1,15 @@
+package data
+
+import "github.com/mylxsw/eloquent/migrate"
+
+func Migrate20261103DDL(m *migrate.Manager) {
+	// 文件夹、置顶以及归档字段默认为 0，已有的房间回填为 0（显式保留 sync_at，避免回填触发多端同步）
+	m.Schema("20261103-ddl").Raw("rooms", func() []string {
+		return []string{
+			"UPDATE rooms SET folder_id = IFNULL(folder_id, 0), pinned = IFNULL(pinned, 0), archived = IFNULL(archived, 0), sync_at = sync_at WHERE folder_id IS NULL OR pinned IS NULL OR archived IS NULL",
+			"ALTER TABLE rooms MODIFY COLUMN folder_id INT UNSIGNED NOT NULL DEFAULT 0 COMMENT '所属文件夹ID，0 为未分类'",
+			"ALTER TABLE rooms MODIFY COLUMN pinned TINYINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '是否置顶：0-否 1-是'",
+			"ALTER TABLE rooms MODIFY COLUMN archived TINYINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '是否归档：0-否 1-是'",
+		}
+	})
+}
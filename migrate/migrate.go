@@ -28,6 +28,22 @@ func Migrate(ctx context.Context, db *sql.DB) error {
 	data.Migrate20240411DDL(m)
 	data.Migrate20240709DDL(m)
 	data.Migrate20240805DDL(m)
+	data.Migrate20261019DDL(m)
+	data.Migrate20261020DDL(m)
+	data.Migrate20261021DDL(m)
+	data.Migrate20261022DDL(m)
+	data.Migrate20261023DDL(m)
+	data.Migrate20261024DDL(m)
+	data.Migrate20261025DDL(m)
+	data.Migrate20261026DDL(m)
+	data.Migrate20261027DDL(m)
+	data.Migrate20261028DDL(m)
+	data.Migrate20261029DDL(m)
+	data.Migrate20261030DDL(m)
+	data.Migrate20261031DDL(m)
+	data.Migrate20261101DDL(m)
+	data.Migrate20261102DDL(m)
+	data.Migrate20261103DDL(m)
 
 	return m.Run(ctx)
 }
@@ -65,7 +65,7 @@ func (chat *AnthropicChat) initRequest(req Request) (anthropic.MessageRequest, e
 	res := anthropic.MessageRequest{
 		Model:     anthropic.Model(req.Model),
 		Messages:  contextMessages,
-		MaxTokens: 20000,
+		MaxTokens: ternary.If(req.MaxTokens > 0, req.MaxTokens, 20000),
 	}
 
 	if req.Temperature > 0 {
@@ -73,9 +73,17 @@ func (chat *AnthropicChat) initRequest(req Request) (anthropic.MessageRequest, e
 	}
 
 	if req.EnableReasoning() {
-		res.Thinking = &anthropic.Thinking{
-			Type:         "enabled",
-			BudgetTokens: 16000,
+		// 思考过程的 Tokens 需要小于最大输出 Tokens，并且不能小于 1024
+		budget := anthropicThinkingBudget(req.ReasoningEffort)
+		if budget >= res.MaxTokens {
+			budget = res.MaxTokens / 2
+		}
+
+		if budget >= 1024 {
+			res.Thinking = &anthropic.Thinking{
+				Type:         "enabled",
+				BudgetTokens: budget,
+			}
 		}
 	}
 
@@ -86,6 +94,18 @@ func (chat *AnthropicChat) initRequest(req Request) (anthropic.MessageRequest, e
 	return res, nil
 }
 
+// anthropicThinkingBudget 根据推理强度计算思考过程可以使用的 Tokens 数量（需要小于 MaxTokens）
+func anthropicThinkingBudget(effort string) int {
+	switch effort {
+	case "low":
+		return 4000
+	case "high":
+		return 19000
+	default:
+		return 16000
+	}
+}
+
 func (chat *AnthropicChat) Chat(ctx context.Context, req Request) (*Response, error) {
 	r, err := chat.initRequest(req)
 	if err != nil {
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/mylxsw/aidea-server/pkg/ai/anthropic"
+	"github.com/mylxsw/aidea-server/pkg/ai/control"
 	"github.com/mylxsw/aidea-server/pkg/ai/deepseek"
 	"github.com/mylxsw/aidea-server/pkg/ai/google"
 	"github.com/mylxsw/aidea-server/pkg/ai/oneapi"
@@ -36,6 +37,8 @@ type Message struct {
 	Role              string              `json:"role"`
 	Content           string              `json:"content"`
 	MultipartContents []*MultipartContent `json:"multipart_content,omitempty"`
+	// Pinned 用户置顶的消息，与 system 消息一样总是保留在上下文中
+	Pinned bool `json:"-"`
 }
 
 func (m Message) UploadedFile() *FileURL {
@@ -216,9 +219,16 @@ type Request struct {
 	TempModel string `json:"temp_model,omitempty"`
 	// Flags 用于传递一些特殊的标记，进行更高级的控制
 	Flags []string `json:"flags,omitempty"`
+	// PromptTemplateID 使用提示语模板作为本次对话的系统提示语，由服务端使用 PromptVariables 渲染
+	PromptTemplateID int64             `json:"prompt_template_id,omitempty"`
+	PromptVariables  map[string]string `json:"prompt_variables,omitempty"`
 
 	// 额外参数
 	SearchCount int `json:"search_count,omitempty"`
+	// SearchEngine 联网搜索时优先使用的搜索引擎
+	SearchEngine string `json:"search_engine,omitempty"`
+	// ReasoningEffort 推理强度：low/medium/high，仅支持推理强度的模型有效
+	ReasoningEffort string `json:"reasoning_effort,omitempty"`
 }
 
 func (req Request) EnableReasoning() bool {
@@ -242,6 +252,11 @@ func (req Request) Clone() Request {
 		TempModel:   req.TempModel,
 		Flags:       req.Flags,
 		SearchCount: req.SearchCount,
+
+		SearchEngine:     req.SearchEngine,
+		ReasoningEffort:  req.ReasoningEffort,
+		PromptTemplateID: req.PromptTemplateID,
+		PromptVariables:  req.PromptVariables,
 	}
 }
 
@@ -300,6 +315,45 @@ func (req Request) ReplaceSystemPrompt(prompt string) *Request {
 	return &req
 }
 
+// WithPinnedMessages 将置顶消息插入到系统消息之后，同时移除历史消息中与置顶消息重复的内容（最后一条消息除外）
+func (req Request) WithPinnedMessages(pinned Messages) *Request {
+	if len(pinned) == 0 {
+		return &req
+	}
+
+	isPinned := func(msg Message) bool {
+		for _, p := range pinned {
+			if p.Role == msg.Role && p.Content == msg.Content {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	systemMessages, historyMessages := make(Messages, 0), make(Messages, 0)
+	for i, msg := range req.Messages {
+		if msg.Role == "system" {
+			systemMessages = append(systemMessages, msg)
+			continue
+		}
+
+		if i < len(req.Messages)-1 && isPinned(msg) {
+			continue
+		}
+
+		historyMessages = append(historyMessages, msg)
+	}
+
+	pinned = array.Map(pinned, func(msg Message, _ int) Message {
+		msg.Pinned = true
+		return msg
+	})
+
+	req.Messages = append(append(systemMessages, pinned...), historyMessages...)
+	return &req
+}
+
 // GetSystemPrompt 获取系统提示消息
 func (req Request) GetSystemPrompt() string {
 	if len(req.Messages) > 0 && req.Messages[0].Role == "system" {
@@ -339,15 +393,27 @@ func (req Request) FixContextWindow(chat Chat, maxContextMessageCount int64, max
 	systemMessages := array.Filter(req.Messages, func(item Message, _ int) bool { return item.Role == "system" })
 	systemMessageLen, _ := MessageTokenCount(systemMessages, req.Model)
 
+	// 置顶消息不受上下文消息数量限制，优先计入 Tokens 预算
+	pinnedMessages := array.Filter(req.Messages, func(item Message, _ int) bool { return item.Role != "system" && item.Pinned })
+	var pinnedMessageLen int
+	if len(pinnedMessages) > 0 {
+		pinnedMessageLen, _ = MessageTokenCount(pinnedMessages, req.Model)
+	}
+
 	// 模型允许的 Tokens 数量和请求参数指定的 Tokens 数量，取最小值
 	modelTokenLimit := chat.MaxContextLength(req.Model) - systemMessageLen
 	if modelTokenLimit < maxTokenCount {
 		maxTokenCount = modelTokenLimit
 	}
 
+	maxTokenCount -= pinnedMessageLen
+	if maxTokenCount <= 0 {
+		return nil, 0, errors.New("置顶消息超过模型最大允许的上下文长度限制，请减少置顶消息")
+	}
+
 	messages, inputTokens, err := ReduceMessageContext(
 		ReduceMessageContextUpToContextWindow(
-			array.Filter(req.Messages, func(item Message, _ int) bool { return item.Role != "system" }),
+			array.Filter(req.Messages, func(item Message, _ int) bool { return item.Role != "system" && !item.Pinned }),
 			int(maxContextMessageCount),
 		),
 		req.Model,
@@ -357,6 +423,9 @@ func (req Request) FixContextWindow(chat Chat, maxContextMessageCount int64, max
 		return nil, 0, errors.New("超过模型最大允许的上下文长度限制，请尝试“新对话”或缩短输入内容长度")
 	}
 
+	inputTokens += pinnedMessageLen
+	systemMessages = append(systemMessages, pinnedMessages...)
+
 	if len(messages) > 0 {
 		for _, msg := range messages {
 			tks, _ := TextTokenCount(msg.Content, req.Model)
@@ -555,6 +624,7 @@ func (ai *Imp) standardizedRequest(ctx context.Context, req Request) (Request, r
 	}
 
 	pro := mod.SelectProvider(ctx)
+	control.TraceFromContext(ctx).Channel = pro.Channel()
 
 	if pro.ModelRewrite != "" {
 		req.Model = pro.ModelRewrite
@@ -563,7 +633,8 @@ func (ai *Imp) standardizedRequest(ctx context.Context, req Request) (Request, r
 	req = *req.MergeSystemPrompt(mod.Meta.Prompt)
 	req.Messages = req.Messages.Fix()
 
-	if mod.Meta.Temperature > 0 {
+	// 请求（或者数字人预设）中未指定温度时，使用模型默认的温度
+	if req.Temperature == 0 && mod.Meta.Temperature > 0 {
 		req.Temperature = mod.Meta.Temperature
 	}
 
@@ -289,3 +289,31 @@ func TestBaiduAIChat_InitRequest(t *testing.T) {
 	}
 
 }
+
+func TestRequestWithPinnedMessages(t *testing.T) {
+	req := Request{
+		Messages: Messages{
+			{Role: "system", Content: "system #1"},
+			{Role: "user", Content: "my name is Tom"},
+			{Role: "assistant", Content: "assistant #1"},
+			{Role: "user", Content: "user #2"},
+		},
+	}
+
+	fixed := req.WithPinnedMessages(Messages{{Role: "user", Content: "my name is Tom"}})
+	assert.Equal(t, 4, len(fixed.Messages))
+	assert.Equal(t, "system", fixed.Messages[0].Role)
+	assert.Equal(t, "my name is Tom", fixed.Messages[1].Content)
+	assert.True(t, fixed.Messages[1].Pinned)
+	assert.Equal(t, "assistant #1", fixed.Messages[2].Content)
+	assert.False(t, fixed.Messages[2].Pinned)
+	assert.Equal(t, "user #2", fixed.Messages[3].Content)
+
+	// 最后一条消息即使与置顶消息相同也需要保留
+	fixed = req.WithPinnedMessages(Messages{{Role: "user", Content: "user #2"}})
+	assert.Equal(t, 5, len(fixed.Messages))
+	assert.Equal(t, "user #2", fixed.Messages[1].Content)
+	assert.Equal(t, "user #2", fixed.Messages[4].Content)
+
+	assert.Equal(t, 4, len(req.WithPinnedMessages(nil).Messages))
+}
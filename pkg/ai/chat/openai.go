@@ -75,10 +75,11 @@ func (chat *OpenAIChat) initRequest(req Request) (*openai.ChatCompletionRequest,
 
 	messages := append(systemMessages, contextMessages...)
 	return &openai.ChatCompletionRequest{
-		Model:       req.Model,
-		Messages:    messages,
-		MaxTokens:   req.MaxTokens,
-		Temperature: float32(req.Temperature),
+		Model:           req.Model,
+		Messages:        messages,
+		MaxTokens:       req.MaxTokens,
+		Temperature:     float32(req.Temperature),
+		ReasoningEffort: req.ReasoningEffort,
 	}, nil
 }
 
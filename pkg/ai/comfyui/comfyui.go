@@ -0,0 +1,265 @@
+package comfyui
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/go-uuid"
+	"gopkg.in/resty.v1"
+)
+
+var (
+	// ErrPromptNotFound 任务不存在或者还没有执行完成
+	ErrPromptNotFound = errors.New("prompt not found in history")
+)
+
+// ComfyUI 自托管的 ComfyUI 服务
+type ComfyUI struct {
+	server   string
+	token    string
+	clientID string
+	resty    *resty.Client
+}
+
+// New 创建 ComfyUI 客户端，server 为服务地址，token 不为空时使用 Bearer Token 认证（通常由反向代理校验）
+func New(server, token string, client *resty.Client) *ComfyUI {
+	clientID, _ := uuid.GenerateUUID()
+	return &ComfyUI{
+		server:   strings.TrimSuffix(server, "/"),
+		token:    token,
+		clientID: clientID,
+		resty:    client,
+	}
+}
+
+func (c *ComfyUI) request(ctx context.Context) *resty.Request {
+	req := c.resty.R().SetContext(ctx)
+	if c.token != "" {
+		req.SetHeader("Authorization", "Bearer "+c.token)
+	}
+
+	return req
+}
+
+type QueuePromptResponse struct {
+	PromptID   string         `json:"prompt_id,omitempty"`
+	Number     int64          `json:"number,omitempty"`
+	NodeErrors map[string]any `json:"node_errors,omitempty"`
+}
+
+// QueuePrompt 提交工作流到执行队列，返回任务 ID
+func (c *ComfyUI) QueuePrompt(ctx context.Context, workflow map[string]any) (*QueuePromptResponse, error) {
+	resp, err := c.request(ctx).
+		SetHeader("Content-Type", "application/json").
+		SetBody(map[string]any{"prompt": workflow, "client_id": c.clientID}).
+		Post(c.server + "/prompt")
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.IsError() {
+		return nil, fmt.Errorf("queue prompt failed: %s", string(resp.Body()))
+	}
+
+	var ret QueuePromptResponse
+	if err := json.Unmarshal(resp.Body(), &ret); err != nil {
+		return nil, err
+	}
+
+	if ret.PromptID == "" {
+		return nil, fmt.Errorf("queue prompt failed: %s", string(resp.Body()))
+	}
+
+	return &ret, nil
+}
+
+type OutputImage struct {
+	Filename  string `json:"filename,omitempty"`
+	Subfolder string `json:"subfolder,omitempty"`
+	Type      string `json:"type,omitempty"`
+}
+
+type NodeOutput struct {
+	Images []OutputImage `json:"images,omitempty"`
+}
+
+type HistoryStatus struct {
+	StatusStr string `json:"status_str,omitempty"`
+	Completed bool   `json:"completed,omitempty"`
+	Messages  []any  `json:"messages,omitempty"`
+}
+
+type History struct {
+	Outputs map[string]NodeOutput `json:"outputs,omitempty"`
+	Status  HistoryStatus         `json:"status,omitempty"`
+}
+
+// IsFinished 任务是否已经执行结束（成功或者失败）
+func (h History) IsFinished() bool {
+	return h.Status.Completed || h.Status.StatusStr == "error"
+}
+
+// IsSuccess 任务是否执行成功
+func (h History) IsSuccess() bool {
+	return h.Status.Completed && h.Status.StatusStr != "error"
+}
+
+// Images 返回所有输出节点生成的图片，忽略预览（temp）图片
+func (h History) Images() []OutputImage {
+	images := make([]OutputImage, 0)
+	for _, output := range h.Outputs {
+		for _, img := range output.Images {
+			if img.Type == "temp" {
+				continue
+			}
+
+			images = append(images, img)
+		}
+	}
+
+	return images
+}
+
+// History 查询任务执行结果，任务还在排队或者执行中时返回 ErrPromptNotFound
+func (c *ComfyUI) History(ctx context.Context, promptID string) (*History, error) {
+	resp, err := c.request(ctx).Get(c.server + "/history/" + promptID)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.IsError() {
+		return nil, fmt.Errorf("query history failed: %s", string(resp.Body()))
+	}
+
+	var ret map[string]History
+	if err := json.Unmarshal(resp.Body(), &ret); err != nil {
+		return nil, err
+	}
+
+	history, ok := ret[promptID]
+	if !ok {
+		return nil, ErrPromptNotFound
+	}
+
+	return &history, nil
+}
+
+// DeleteQueued 从执行队列中删除还没有开始执行的任务，正在执行的任务不受影响
+func (c *ComfyUI) DeleteQueued(ctx context.Context, promptIDs ...string) error {
+	resp, err := c.request(ctx).
+		SetHeader("Content-Type", "application/json").
+		SetBody(map[string]any{"delete": promptIDs}).
+		Post(c.server + "/queue")
+	if err != nil {
+		return err
+	}
+
+	if resp.IsError() {
+		return fmt.Errorf("delete queued prompt failed: %s", string(resp.Body()))
+	}
+
+	return nil
+}
+
+// View 下载生成的图片
+func (c *ComfyUI) View(ctx context.Context, img OutputImage) ([]byte, error) {
+	resp, err := c.request(ctx).
+		SetQueryParams(map[string]string{
+			"filename":  img.Filename,
+			"subfolder": img.Subfolder,
+			"type":      img.Type,
+		}).
+		Get(c.server + "/view")
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.IsError() {
+		return nil, fmt.Errorf("view image failed: %s", string(resp.Body()))
+	}
+
+	return resp.Body(), nil
+}
+
+// UploadImage 上传本地图片到 ComfyUI 的 input 目录，返回可以在 LoadImage 节点中使用的文件名
+func (c *ComfyUI) UploadImage(ctx context.Context, imagePath string) (string, error) {
+	resp, err := c.request(ctx).
+		SetFile("image", imagePath).
+		SetFormData(map[string]string{"overwrite": "true"}).
+		Post(c.server + "/upload/image")
+	if err != nil {
+		return "", err
+	}
+
+	if resp.IsError() {
+		return "", fmt.Errorf("upload image failed: %s", string(resp.Body()))
+	}
+
+	var ret struct {
+		Name      string `json:"name"`
+		Subfolder string `json:"subfolder"`
+	}
+	if err := json.Unmarshal(resp.Body(), &ret); err != nil {
+		return "", err
+	}
+
+	if ret.Subfolder != "" {
+		return path.Join(ret.Subfolder, ret.Name), nil
+	}
+
+	return ret.Name, nil
+}
+
+var placeholderRegexp = regexp.MustCompile(`{{\s*([a-z_]+)\s*}}`)
+
+// RenderWorkflow 使用变量替换工作流模板（API 格式）中的占位符 {{name}}
+//
+// 字符串值完全等于占位符时，替换为变量的原始类型（如 "{{seed}}" 替换为数字），
+// 否则按照字符串进行替换；没有提供的变量保持原样
+func RenderWorkflow(template string, vars map[string]any) (map[string]any, error) {
+	var workflow map[string]any
+	if err := json.Unmarshal([]byte(template), &workflow); err != nil {
+		return nil, fmt.Errorf("invalid workflow template: %w", err)
+	}
+
+	return renderValue(workflow, vars).(map[string]any), nil
+}
+
+func renderValue(val any, vars map[string]any) any {
+	switch v := val.(type) {
+	case map[string]any:
+		for k, item := range v {
+			v[k] = renderValue(item, vars)
+		}
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = renderValue(item, vars)
+		}
+		return v
+	case string:
+		if matches := placeholderRegexp.FindStringSubmatch(v); matches != nil && matches[0] == v {
+			if rep, ok := vars[matches[1]]; ok {
+				return rep
+			}
+
+			return v
+		}
+
+		return placeholderRegexp.ReplaceAllStringFunc(v, func(s string) string {
+			name := placeholderRegexp.FindStringSubmatch(s)[1]
+			if rep, ok := vars[name]; ok {
+				return fmt.Sprint(rep)
+			}
+
+			return s
+		})
+	}
+
+	return val
+}
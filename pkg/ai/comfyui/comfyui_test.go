@@ -0,0 +1,44 @@
+package comfyui_test
+
+import (
+	"testing"
+
+	"github.com/mylxsw/aidea-server/pkg/ai/comfyui"
+	"github.com/mylxsw/go-utils/assert"
+)
+
+func TestRenderWorkflow(t *testing.T) {
+	template := `{
+		"3": {"class_type": "KSampler", "inputs": {"seed": "{{seed}}", "steps": "{{ steps }}", "denoise": "{{denoise}}", "model": ["4", 0]}},
+		"5": {"class_type": "EmptyLatentImage", "inputs": {"width": "{{width}}", "height": "{{height}}", "batch_size": 1}},
+		"6": {"class_type": "CLIPTextEncode", "inputs": {"text": "masterpiece, {{prompt}}"}},
+		"7": {"class_type": "CLIPTextEncode", "inputs": {"text": "{{negative_prompt}}, {{unknown}}"}}
+	}`
+
+	workflow, err := comfyui.RenderWorkflow(template, map[string]any{
+		"seed":            int64(12345),
+		"steps":           int64(30),
+		"denoise":         0.65,
+		"width":           int64(768),
+		"height":          int64(512),
+		"prompt":          `a "cat"`,
+		"negative_prompt": "blurry",
+	})
+	assert.NoError(t, err)
+
+	sampler := workflow["3"].(map[string]any)["inputs"].(map[string]any)
+	assert.Equal(t, int64(12345), sampler["seed"])
+	assert.Equal(t, int64(30), sampler["steps"])
+	assert.Equal(t, 0.65, sampler["denoise"])
+	assert.Equal(t, []any{"4", float64(0)}, sampler["model"])
+
+	latent := workflow["5"].(map[string]any)["inputs"].(map[string]any)
+	assert.Equal(t, int64(768), latent["width"])
+	assert.Equal(t, float64(1), latent["batch_size"])
+
+	assert.Equal(t, `masterpiece, a "cat"`, workflow["6"].(map[string]any)["inputs"].(map[string]any)["text"])
+	assert.Equal(t, "blurry, {{unknown}}", workflow["7"].(map[string]any)["inputs"].(map[string]any)["text"])
+
+	_, err = comfyui.RenderWorkflow("not json", nil)
+	assert.True(t, err != nil)
+}
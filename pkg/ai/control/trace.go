@@ -0,0 +1,24 @@
+package control
+
+import "context"
+
+// Trace 记录请求实际使用的服务提供商，由 chat.Imp 在选择服务提供商后回填，重试时会被覆盖为最后一次使用的渠道
+type Trace struct {
+	Channel string `json:"channel,omitempty"`
+}
+
+const traceContextKey = "chat-trace"
+
+func NewTraceContext(ctx context.Context, trace *Trace) context.Context {
+	return context.WithValue(ctx, traceContextKey, trace)
+}
+
+// TraceFromContext 获取上下文中的 Trace，上下文中不存在时返回一个新的 Trace，写入的内容会被丢弃
+func TraceFromContext(ctx context.Context) *Trace {
+	t, ok := ctx.Value(traceContextKey).(*Trace)
+	if !ok {
+		return &Trace{}
+	}
+
+	return t
+}
@@ -315,6 +315,17 @@ type ImageTaskOutput struct {
 	// 输出图像分辨率说明：
 	// 对于输入分辨率小于2048*1024（以像素点总数计算）的图像，返回和输入分辨率相同大小的图像。对于超过该阈值的图像，按照输入长宽比返回不超过2048*1024的图像
 	Results []ImageTaskOutputImage `json:"results,omitempty"`
+	// TaskMetrics 作业中每个 batch 任务的状态统计
+	TaskMetrics *ImageTaskMetrics `json:"task_metrics,omitempty"`
+}
+
+type ImageTaskMetrics struct {
+	// Total 总的 batch 数目
+	Total int `json:"TOTAL,omitempty"`
+	// Succeeded 已经成功的 batch 数目
+	Succeeded int `json:"SUCCEEDED,omitempty"`
+	// Failed 已经失败的 batch 数目
+	Failed int `json:"FAILED,omitempty"`
 }
 
 const (
@@ -363,6 +374,30 @@ func (ds *DashScope) ImageTaskStatus(ctx context.Context, taskID string) (*Image
 	return &chatResp, nil
 }
 
+// CancelTask 取消排队中（PENDING）的异步任务，处理中的任务无法取消
+func (ds *DashScope) CancelTask(ctx context.Context, taskID string) error {
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", ds.serviceURL+"/api/v1/tasks/"+taskID+"/cancel", nil)
+	if err != nil {
+		return err
+	}
+
+	httpReq.Header.Set("Authorization", "Bearer "+ds.apiKeyLoadBalanced())
+
+	httpResp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		return err
+	}
+
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode < http.StatusOK || httpResp.StatusCode >= http.StatusBadRequest {
+		data, _ := io.ReadAll(httpResp.Body)
+		return fmt.Errorf("cancel task failed [%d]: %s", httpResp.StatusCode, string(data))
+	}
+
+	return nil
+}
+
 func (ds *DashScope) apiKeyLoadBalanced() string {
 	return ds.apiKeys[rand.Intn(len(ds.apiKeys))]
 }
@@ -0,0 +1,140 @@
+package imagegen
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/mylxsw/aidea-server/pkg/ai/comfyui"
+	"github.com/mylxsw/aidea-server/pkg/uploader"
+	"github.com/mylxsw/asteria/log"
+	"github.com/mylxsw/go-utils/ternary"
+)
+
+type comfyUIGenerator struct {
+	client *comfyui.ComfyUI
+}
+
+// NewComfyUIGenerator 使用自托管的 ComfyUI 生成图片，工作流模板在 image_models 表中配置
+func NewComfyUIGenerator(client *comfyui.ComfyUI) AsyncGenerator {
+	return &comfyUIGenerator{client: client}
+}
+
+func (g *comfyUIGenerator) Vendor() string {
+	return "comfyui"
+}
+
+func (g *comfyUIGenerator) Capabilities() Capabilities {
+	return Capabilities{
+		TextToImage:     true,
+		ImageToImage:    true,
+		NegativePrompt:  true,
+		ImageStrength:   true,
+		Seed:            true,
+		Steps:           true,
+		Inpaint:         true,
+		TranslatePrompt: true,
+		RewritePrompt:   true,
+		Async:           true,
+	}
+}
+
+func (g *comfyUIGenerator) Generate(ctx context.Context, req Request) (*Result, error) {
+	if req.Workflow == "" {
+		return nil, errors.New("没有配置 ComfyUI 工作流模板")
+	}
+
+	// 图生图模式，先将参考图片上传到 ComfyUI，工作流中的 LoadImage 节点使用 {{image}} 引用
+	// 局部重绘时，蒙版使用 {{mask}} 引用
+	inputImage, err := g.upload(ctx, req.Image)
+	if err != nil {
+		return nil, err
+	}
+
+	inputMask, err := g.upload(ctx, req.Mask)
+	if err != nil {
+		return nil, err
+	}
+
+	workflow, err := comfyui.RenderWorkflow(req.Workflow, map[string]any{
+		"prompt":          req.Prompt,
+		"negative_prompt": req.NegativePrompt,
+		"width":           req.Width,
+		"height":          req.Height,
+		"seed":            req.Seed,
+		"steps":           req.Steps,
+		"image_count":     req.ImageCount,
+		"image":           inputImage,
+		"mask":            inputMask,
+		"denoise":         ternary.If(req.Image != "", req.ImageStrength, 1.0),
+		"cfg_scale":       ternary.If(req.CfgScale > 0, req.CfgScale, 7.0),
+		"sampler":         ternary.If(req.Sampler != "", req.Sampler, "euler"),
+		"model":           req.Model,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := g.client.QueuePrompt(ctx, workflow)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Result{TaskID: resp.PromptID, RetryAfter: 5 * time.Second}, nil
+}
+
+// upload 下载远程图片并上传到 ComfyUI，返回 ComfyUI 中的文件名
+func (g *comfyUIGenerator) upload(ctx context.Context, url string) (string, error) {
+	if url == "" {
+		return "", nil
+	}
+
+	imagePath, err := uploader.DownloadRemoteFile(ctx, url)
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(imagePath)
+
+	return g.client.UploadImage(ctx, imagePath)
+}
+
+func (g *comfyUIGenerator) Query(ctx context.Context, req Request, taskID string) (*Result, error) {
+	history, err := g.client.History(ctx, taskID)
+	if err != nil {
+		// 任务还在排队或者执行中
+		if errors.Is(err, comfyui.ErrPromptNotFound) {
+			return &Result{TaskID: taskID, RetryAfter: 5 * time.Second}, nil
+		}
+
+		return nil, err
+	}
+
+	if !history.IsFinished() {
+		return &Result{TaskID: taskID, RetryAfter: 5 * time.Second}, nil
+	}
+
+	if !history.IsSuccess() {
+		log.WithFields(log.Fields{"task_id": taskID, "status": history.Status}).Warningf("comfyui task failed")
+		return &Result{TaskID: taskID, Error: "任务处理失败，请重试"}, nil
+	}
+
+	// ComfyUI 的图片需要通过接口下载，服务地址可能无法公开访问，这里直接下载后以 Base64 格式返回
+	images := make([]string, 0)
+	for _, img := range history.Images() {
+		data, err := g.client.View(ctx, img)
+		if err != nil {
+			return nil, err
+		}
+
+		images = append(images, Base64Image(base64.StdEncoding.EncodeToString(data)))
+	}
+
+	return &Result{TaskID: taskID, Finished: true, Images: images}, nil
+}
+
+// Cancel 从 ComfyUI 执行队列中删除任务，已经开始执行的任务无法取消
+func (g *comfyUIGenerator) Cancel(ctx context.Context, req Request, taskID string) error {
+	return g.client.DeleteQueued(ctx, taskID)
+}
@@ -0,0 +1,159 @@
+package imagegen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mylxsw/aidea-server/pkg/ai/openai"
+	"github.com/mylxsw/go-utils/array"
+)
+
+type dalleGenerator struct {
+	client *openai.DalleImageClient
+}
+
+// NewDalleGenerator 使用 OpenAI DALL·E 生成图片
+func NewDalleGenerator(client *openai.DalleImageClient) Generator {
+	return &dalleGenerator{client: client}
+}
+
+func (g *dalleGenerator) Vendor() string {
+	return "dalle"
+}
+
+func (g *dalleGenerator) Capabilities() Capabilities {
+	return Capabilities{
+		TextToImage: true,
+		StylePreset: true,
+		Inpaint:     true,
+		Variation:   true,
+		Dimensions: map[string]Dimension{
+			"1:1":  {Width: 1024, Height: 1024},
+			"16:9": {Width: 1792, Height: 1024},
+			"3:2":  {Width: 1792, Height: 1024},
+			"4:3":  {Width: 1792, Height: 1024},
+			"2:3":  {Width: 1024, Height: 1792},
+			"3:4":  {Width: 1024, Height: 1792},
+		},
+	}
+}
+
+func (g *dalleGenerator) Generate(ctx context.Context, req Request) (*Result, error) {
+	if req.Image != "" && req.Mask != "" {
+		return g.edit(ctx, req)
+	}
+
+	if req.Image != "" && req.Variation {
+		return g.variation(ctx, req)
+	}
+
+	// 模型名称格式：
+	// dall-e-3    -> model: dalle-e-3 quality: standard
+	// dall-e-3:hd -> model: dalle-e-3 quality: hd
+	model, quality := req.Model, ""
+	if segs := strings.SplitN(req.Model, ":", 2); len(segs) == 2 {
+		model, quality = segs[0], segs[1]
+	}
+
+	resp, err := g.client.CreateImage(ctx, openai.ImageRequest{
+		Prompt:         req.Prompt,
+		Model:          model,
+		N:              req.ImageCount,
+		Size:           fmt.Sprintf("%dx%d", req.Width, req.Height),
+		Style:          req.StylePreset,
+		Quality:        quality,
+		ResponseFormat: "b64_json",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return finished(array.Map(resp.Data, func(item openai.ImageResponseDataInner, _ int) string {
+		return Base64Image(item.Base64JSON)
+	})...), nil
+}
+
+// edit 局部重绘，图片编辑接口只支持 dall-e-2 模型，且图片必须为正方形
+func (g *dalleGenerator) edit(ctx context.Context, req Request) (*Result, error) {
+	image, mask, err := downloadImageAndMask(ctx, req.Image, req.Mask, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	width, height, err := ImageSize(image)
+	if err != nil {
+		return nil, err
+	}
+
+	if width != height {
+		return nil, errors.New("DALL·E 局部重绘只支持正方形图片")
+	}
+
+	if image, err = ResizeImage(image, 1024, 1024); err != nil {
+		return nil, err
+	}
+
+	if mask, err = ResizeImage(mask, 1024, 1024); err != nil {
+		return nil, err
+	}
+
+	// OpenAI 使用透明区域表示需要编辑的区域
+	if mask, err = MaskToAlpha(mask); err != nil {
+		return nil, err
+	}
+
+	resp, err := g.client.EditImage(ctx, openai.ImageEditRequest{
+		Image:          image,
+		Mask:           mask,
+		Prompt:         req.Prompt,
+		Model:          "dall-e-2",
+		N:              req.ImageCount,
+		Size:           "1024x1024",
+		ResponseFormat: "b64_json",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return finished(array.Map(resp.Data, func(item openai.ImageResponseDataInner, _ int) string {
+		return Base64Image(item.Base64JSON)
+	})...), nil
+}
+
+// variation 根据图片生成变体，变体接口只支持 dall-e-2 模型，且图片必须为正方形
+func (g *dalleGenerator) variation(ctx context.Context, req Request) (*Result, error) {
+	image, err := downloadImage(ctx, req.Image)
+	if err != nil {
+		return nil, fmt.Errorf("download image failed: %w", err)
+	}
+
+	width, height, err := ImageSize(image)
+	if err != nil {
+		return nil, err
+	}
+
+	if width != height {
+		return nil, errors.New("DALL·E 变体只支持正方形图片")
+	}
+
+	if image, err = ResizeImage(image, 1024, 1024); err != nil {
+		return nil, err
+	}
+
+	resp, err := g.client.CreateVariation(ctx, openai.ImageVariationRequest{
+		Image:          image,
+		Model:          "dall-e-2",
+		N:              req.ImageCount,
+		Size:           "1024x1024",
+		ResponseFormat: "b64_json",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return finished(array.Map(resp.Data, func(item openai.ImageResponseDataInner, _ int) string {
+		return Base64Image(item.Base64JSON)
+	})...), nil
+}
@@ -0,0 +1,122 @@
+package imagegen
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mylxsw/aidea-server/pkg/ai/dashscope"
+	"github.com/mylxsw/go-utils/array"
+)
+
+type dashscopeGenerator struct {
+	client *dashscope.DashScope
+}
+
+// NewDashscopeGenerator 使用阿里云灵积生成图片
+func NewDashscopeGenerator(client *dashscope.DashScope) AsyncGenerator {
+	return &dashscopeGenerator{client: client}
+}
+
+func (g *dashscopeGenerator) Vendor() string {
+	return "dashscope"
+}
+
+func (g *dashscopeGenerator) Capabilities() Capabilities {
+	return Capabilities{
+		TextToImage:     true,
+		ImageToImage:    true,
+		NegativePrompt:  true,
+		Seed:            true,
+		Steps:           true,
+		Inpaint:         true,
+		TranslatePrompt: true,
+		RewritePrompt:   true,
+		Async:           true,
+	}
+}
+
+func (g *dashscopeGenerator) Generate(ctx context.Context, req Request) (*Result, error) {
+	var resp *dashscope.ImageGenerationResponse
+	var err error
+
+	if req.Image != "" && req.Mask != "" {
+		// 局部重绘，调用通用图像编辑接口，图片和蒙版需要能够公开访问
+		resp, err = g.client.ImageEdit(ctx, dashscope.ImageEditRequest{
+			Model: dashscope.ImageModelImageEdit,
+			Input: dashscope.ImageEditInput{
+				Function:     dashscope.ImageEditFunctionInpaint,
+				Prompt:       req.Prompt,
+				BaseImageURL: req.Image,
+				MaskImageURL: req.Mask,
+			},
+			Parameters: dashscope.ImageEditParameters{
+				N:    int(req.ImageCount),
+				Seed: int(req.Seed),
+			},
+		})
+	} else if req.Image != "" {
+		// 图生图模式，调用人像风格重绘接口
+		resp, err = g.client.ImageGeneration(ctx, dashscope.ImageGenerationRequest{
+			Model: req.Model,
+			Input: dashscope.ImageGenerationRequestInput{
+				ImageURL:   req.Image,
+				StyleIndex: dashscope.ImageStyleComic,
+			},
+		})
+	} else {
+		// 文生图模式，调用 Stable Diffusion 接口
+		resp, err = g.client.StableDiffusion(ctx, dashscope.StableDiffusionRequest{
+			Model: req.Model,
+			Input: dashscope.StableDiffusionInput{
+				Prompt:         req.Prompt,
+				NegativePrompt: req.NegativePrompt,
+			},
+			Parameters: dashscope.StableDiffusionParameters{
+				Size:  fmt.Sprintf("%d*%d", req.Width, req.Height),
+				N:     int(req.ImageCount),
+				Steps: int(req.Steps),
+				Seed:  int(req.Seed),
+			},
+		})
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Result{TaskID: resp.Output.TaskID, RetryAfter: 5 * time.Second}, nil
+}
+
+func (g *dashscopeGenerator) Query(ctx context.Context, req Request, taskID string) (*Result, error) {
+	res, err := g.client.ImageTaskStatus(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch res.Output.TaskStatus {
+	case dashscope.TaskStatusPending, dashscope.TaskStatusRunning, dashscope.TaskStatusUnknown:
+		ret := &Result{TaskID: taskID, RetryAfter: 5 * time.Second}
+		if metrics := res.Output.TaskMetrics; metrics != nil {
+			ret.Progress = progress(metrics.Succeeded+metrics.Failed, metrics.Total)
+		}
+
+		return ret, nil
+	case dashscope.TaskStatusFailed:
+		return &Result{TaskID: taskID, Error: "task failed: " + res.Output.TaskStatus}, nil
+	}
+
+	return &Result{
+		TaskID:   taskID,
+		Finished: true,
+		Images: array.Filter(
+			array.Map(res.Output.Results, func(item dashscope.ImageTaskOutputImage, _ int) string { return item.URL }),
+			func(item string, _ int) bool { return item != "" },
+		),
+	}, nil
+}
+
+// Cancel 取消排队中的任务，已经开始处理的任务无法取消
+func (g *dashscopeGenerator) Cancel(ctx context.Context, req Request, taskID string) error {
+	return g.client.CancelTask(ctx, taskID)
+}
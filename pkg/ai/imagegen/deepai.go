@@ -0,0 +1,44 @@
+package imagegen
+
+import (
+	"context"
+
+	"github.com/mylxsw/aidea-server/pkg/ai/deepai"
+)
+
+type deepAIGenerator struct {
+	client *deepai.DeepAI
+}
+
+// NewDeepAIGenerator 使用 DeepAI 生成图片
+func NewDeepAIGenerator(client *deepai.DeepAI) Generator {
+	return &deepAIGenerator{client: client}
+}
+
+func (g *deepAIGenerator) Vendor() string {
+	return "deepai"
+}
+
+func (g *deepAIGenerator) Capabilities() Capabilities {
+	return Capabilities{
+		TextToImage:     true,
+		NegativePrompt:  true,
+		TranslatePrompt: true,
+		RewritePrompt:   true,
+	}
+}
+
+func (g *deepAIGenerator) Generate(ctx context.Context, req Request) (*Result, error) {
+	res, err := g.client.TextToImage(req.Model, deepai.TextToImageParam{
+		Text:         req.Prompt,
+		Width:        int(req.Width),
+		Height:       int(req.Height),
+		GridSize:     int(req.ImageCount),
+		NegativeText: req.NegativePrompt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return finished(res.OutputURL), nil
+}
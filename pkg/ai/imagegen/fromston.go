@@ -0,0 +1,201 @@
+package imagegen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mylxsw/aidea-server/pkg/ai/fromston"
+	"github.com/mylxsw/aidea-server/pkg/misc"
+	"github.com/mylxsw/aidea-server/pkg/uploader"
+	"github.com/mylxsw/asteria/log"
+	"github.com/mylxsw/go-utils/array"
+	"github.com/mylxsw/go-utils/ternary"
+)
+
+type fromstonGenerator struct {
+	client *fromston.Fromston
+}
+
+// NewFromstonGenerator 使用 6pen（Fromston）生成图片
+func NewFromstonGenerator(client *fromston.Fromston) AsyncGenerator {
+	return &fromstonGenerator{client: client}
+}
+
+func (g *fromstonGenerator) Vendor() string {
+	return "fromston"
+}
+
+func (g *fromstonGenerator) Capabilities() Capabilities {
+	return Capabilities{
+		TextToImage:     true,
+		ImageToImage:    true,
+		NegativePrompt:  true,
+		ImageStrength:   true,
+		SquareImageOnly: true,
+		Async:           true,
+	}
+}
+
+// parseModel 模型名称格式为 模型类型:模型 ID，custom 类型为自己训练的模型
+func (g *fromstonGenerator) parseModel(model string) (string, string, error) {
+	ms := strings.SplitN(model, ":", 2)
+	if len(ms) != 2 {
+		return "", "", fmt.Errorf("invalid model: %s", model)
+	}
+
+	return ms[0], ms[1], nil
+}
+
+func (g *fromstonGenerator) Generate(ctx context.Context, req Request) (*Result, error) {
+	modelType, modelID, err := g.parseModel(req.Model)
+	if err != nil {
+		return nil, err
+	}
+
+	// 先尝试本地下载后上传到 6Pen，失败则直接使用远程图片地址
+	refImage := req.Image
+	if req.Image != "" {
+		if imagePath, err := uploader.DownloadRemoteFile(ctx, req.Image); err != nil {
+			log.With(req).Errorf("下载远程图片失败: %s", err)
+		} else {
+			refImage = imagePath
+			defer os.Remove(imagePath)
+		}
+
+		if uploadPath, err := g.client.UploadImage(ctx, refImage); err != nil {
+			log.With(req).Errorf("上传用户图片到 6Pen 失败: %s", err)
+		} else {
+			refImage = uploadPath
+		}
+	}
+
+	addition := &fromston.GenImageAddition{
+		ImgFmt:         "jpg",
+		NegativePrompt: misc.WordTruncate(req.NegativePrompt, 500),
+		Strength:       req.ImageStrength,
+		CfgScale:       7,
+	}
+
+	var resp *fromston.GenImageResponseData
+	if modelType == "custom" {
+		// 自己训练的模型
+		resp, err = g.client.GenImageCustom(ctx, fromston.GenImageCustomRequest{
+			Prompt:     misc.WordTruncate(req.Prompt, 500),
+			FillPrompt: int64(ternary.If(req.AIRewrite, 1, 0)),
+			Width:      req.Width,
+			Height:     req.Height,
+			RefImg:     refImage,
+			ModelID:    modelID,
+			Multiply:   req.ImageCount,
+			Addition:   addition,
+		})
+	} else {
+		id, convErr := strconv.Atoi(modelID)
+		if convErr != nil {
+			return nil, fmt.Errorf("invalid model: %s", req.Model)
+		}
+
+		resp, err = g.client.GenImage(ctx, fromston.GenImageRequest{
+			Prompt:     misc.WordTruncate(req.Prompt, 500),
+			FillPrompt: int64(ternary.If(req.AIRewrite, 1, 0)),
+			Width:      req.Width,
+			Height:     req.Height,
+			RefImg:     refImage,
+			ModelType:  modelType,
+			ModelID:    int64(id),
+			Multiply:   req.ImageCount,
+			Addition:   addition,
+		})
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	// 限制最大等待时间为 15 秒
+	estimates := array.Reduce(resp.Estimates, func(carry int64, item int64) int64 { return carry + item }, 0)
+	if estimates > 15 {
+		estimates = 15
+	}
+
+	return &Result{
+		TaskID:     strings.Join(resp.IDs, ","),
+		RetryAfter: time.Duration(estimates) * time.Second,
+	}, nil
+}
+
+func (g *fromstonGenerator) Query(ctx context.Context, req Request, taskID string) (*Result, error) {
+	modelType, _, err := g.parseModel(req.Model)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := strings.Split(taskID, ",")
+
+	var tasks []fromston.Task
+	if modelType == "custom" {
+		// 自己训练的模型任务查询
+		for _, id := range ids {
+			customTask, err := g.client.QueryCustomTask(ctx, id)
+			if err != nil {
+				log.WithFields(log.Fields{"task_id": id}).Errorf("query fromston custom job result failed: %v", err)
+				continue
+			}
+
+			tasks = append(tasks, *customTask)
+		}
+	} else {
+		tasks, err = g.client.QueryTasks(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	unfinished := array.Filter(tasks, func(item fromston.Task, _ int) bool {
+		return array.In(item.State, []string{"in_wait", "in_create"})
+	})
+	if len(unfinished) > 0 || len(tasks) == 0 {
+		// 每张图片对应一个任务，根据已完成的任务数量计算进度
+		return &Result{
+			TaskID:     taskID,
+			RetryAfter: 5 * time.Second,
+			Progress:   progress(len(tasks)-len(unfinished), len(ids)),
+		}, nil
+	}
+
+	successTasks := array.Filter(tasks, func(item fromston.Task, _ int) bool { return item.State == "success" })
+	if len(successTasks) == 0 {
+		return &Result{TaskID: taskID, Error: fromstonFailReason(tasks).Error()}, nil
+	}
+
+	return &Result{
+		TaskID:   taskID,
+		Finished: true,
+		Images: array.Filter(
+			array.Map(successTasks, func(item fromston.Task, _ int) string { return item.GenImg }),
+			func(item string, _ int) bool { return item != "" },
+		),
+	}, nil
+}
+
+func fromstonFailReason(tasks []fromston.Task) error {
+	failedTasks := array.Filter(tasks, func(item fromston.Task, _ int) bool { return item.State == "fail" })
+	if len(failedTasks) == 0 {
+		return errors.New("fromston tasks failed")
+	}
+
+	return errors.New(strings.Join(array.Map(failedTasks, func(t fromston.Task, _ int) string {
+		switch t.FailReson {
+		case "NSFW":
+			return "检测到违规内容，请修改后重试"
+		case "":
+			return "生成失败，请重试"
+		}
+		return t.FailReson
+	}), "; "))
+}
@@ -0,0 +1,101 @@
+package imagegen
+
+import (
+	"context"
+	"os"
+
+	"github.com/mylxsw/aidea-server/pkg/ai/getimgai"
+	"github.com/mylxsw/aidea-server/pkg/misc"
+	"github.com/mylxsw/aidea-server/pkg/uploader"
+	"github.com/mylxsw/asteria/log"
+)
+
+type getimgAIGenerator struct {
+	client *getimgai.GetimgAI
+}
+
+// NewGetimgAIGenerator 使用 getimg.ai 生成图片
+func NewGetimgAIGenerator(client *getimgai.GetimgAI) Generator {
+	return &getimgAIGenerator{client: client}
+}
+
+func (g *getimgAIGenerator) Vendor() string {
+	return "getimgai"
+}
+
+func (g *getimgAIGenerator) Capabilities() Capabilities {
+	return Capabilities{
+		TextToImage:     true,
+		ImageToImage:    true,
+		NegativePrompt:  true,
+		ImageStrength:   true,
+		Seed:            true,
+		Steps:           true,
+		Upscale:         true,
+		MaxImageCount:   1,
+		TranslatePrompt: true,
+		RewritePrompt:   true,
+	}
+}
+
+func (g *getimgAIGenerator) Generate(ctx context.Context, req Request) (*Result, error) {
+	var resp *getimgai.ImageResponse
+	if req.Image != "" {
+		// 下载远程图片（图生图）
+		imagePath, err := uploader.DownloadRemoteFile(ctx, req.Image)
+		if err != nil {
+			return nil, err
+		}
+		defer os.Remove(imagePath)
+
+		imageBase64, err := misc.ImageToRawBase64(imagePath)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err = g.client.ImageToImage(ctx, getimgai.ImageToImageRequest{
+			Model:          req.Model,
+			Prompt:         req.Prompt,
+			NegativePrompt: req.NegativePrompt,
+			Image:          imageBase64,
+			Steps:          req.Steps,
+			Strength:       req.ImageStrength,
+			OutputFormat:   "png",
+		})
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		var err error
+		resp, err = g.client.TextToImage(ctx, getimgai.TextToImageRequest{
+			Model:          req.Model,
+			Prompt:         req.Prompt,
+			NegativePrompt: req.NegativePrompt,
+			Width:          req.Width,
+			Height:         req.Height,
+			Seed:           req.Seed,
+			Steps:          req.Steps,
+			OutputFormat:   "png",
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// getimg.ai 只支持放大 4 倍，放大失败时使用原图
+	if req.UpscaleBy != "" && req.UpscaleBy != "x1" {
+		upscaleRes, err := g.client.Upscale(ctx, getimgai.UpscaleRequest{
+			Model:        "real-esrgan-4x",
+			Image:        resp.Image,
+			Scale:        4,
+			OutputFormat: "png",
+		})
+		if err != nil {
+			log.With(req).Errorf("upscale failed: %v", err)
+		} else {
+			resp.Image = upscaleRes.Image
+		}
+	}
+
+	return finished(Base64Image(resp.Image)), nil
+}
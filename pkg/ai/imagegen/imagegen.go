@@ -0,0 +1,243 @@
+package imagegen
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrGeneratorNotFound 没有找到匹配的图片生成服务
+	ErrGeneratorNotFound = errors.New("image generator not found")
+	// ErrNotAsync 图片生成服务不支持异步任务查询
+	ErrNotAsync = errors.New("image generator does not support async task")
+	// ErrIncompatible 图片生成服务不支持请求依赖的能力
+	ErrIncompatible = errors.New("image generator is not compatible with the request")
+)
+
+// Dimension 图片尺寸
+type Dimension struct {
+	Width  int `json:"width,omitempty"`
+	Height int `json:"height,omitempty"`
+}
+
+// DefaultDimensions 默认的宽高比与图片尺寸对应关系
+var DefaultDimensions = map[string]Dimension{
+	"1:1":  {Width: 512, Height: 512},
+	"4:3":  {Width: 768, Height: 576},
+	"3:4":  {Width: 576, Height: 768},
+	"3:2":  {Width: 768, Height: 512},
+	"2:3":  {Width: 512, Height: 768},
+	"16:9": {Width: 1024, Height: 576},
+}
+
+// Capabilities 图片生成服务支持的能力
+type Capabilities struct {
+	// TextToImage 是否支持文生图
+	TextToImage bool `json:"text_to_image,omitempty"`
+	// ImageToImage 是否支持图生图
+	ImageToImage bool `json:"image_to_image,omitempty"`
+	// NegativePrompt 是否支持排除内容（反向提示语）
+	NegativePrompt bool `json:"negative_prompt,omitempty"`
+	// StylePreset 是否支持风格预设
+	StylePreset bool `json:"style_preset,omitempty"`
+	// ImageStrength 图生图时是否支持设置参考图片的影响程度
+	ImageStrength bool `json:"image_strength,omitempty"`
+	// Seed 是否支持指定随机种子
+	Seed bool `json:"seed,omitempty"`
+	// Steps 是否支持指定迭代步数
+	Steps bool `json:"steps,omitempty"`
+	// Upscale 是否支持生成后放大
+	Upscale bool `json:"upscale,omitempty"`
+	// MaxImageCount 单次最多生成的图片数量，0 表示不限制
+	MaxImageCount int64 `json:"max_image_count,omitempty"`
+	// SquareImageOnly 图生图时参考图片必须为正方形
+	SquareImageOnly bool `json:"square_image_only,omitempty"`
+	// TranslatePrompt 只支持英文提示语，中文提示语需要先翻译为英文
+	TranslatePrompt bool `json:"translate_prompt,omitempty"`
+	// RewritePrompt 是否支持使用 AI 改写提示语
+	RewritePrompt bool `json:"rewrite_prompt,omitempty"`
+	// Inpaint 是否支持使用蒙版局部重绘，扩图会被转换为局部重绘，因此支持局部重绘的服务同时支持扩图
+	Inpaint bool `json:"inpaint,omitempty"`
+	// Variation 是否支持服务商的变体接口（根据图片生成相似的图片），不支持时使用相近的种子生成变体
+	Variation bool `json:"variation,omitempty"`
+	// Async 是否为异步生成，异步生成时需要轮询任务状态
+	Async bool `json:"async,omitempty"`
+	// Dimensions 服务商在不同宽高比下推荐的图片尺寸，未设置时使用 DefaultDimensions
+	Dimensions map[string]Dimension `json:"dimensions,omitempty"`
+}
+
+// Dimension 返回指定宽高比对应的默认图片尺寸
+func (c Capabilities) Dimension(ratio string) Dimension {
+	if dim, ok := c.Dimensions[ratio]; ok && dim.Width > 0 && dim.Height > 0 {
+		return dim
+	}
+
+	if dim, ok := DefaultDimensions[ratio]; ok {
+		return dim
+	}
+
+	return DefaultDimensions["1:1"]
+}
+
+// Adapt 根据服务支持的能力调整图片生成请求，用于切换服务商时映射生成参数
+// 不支持的可选参数会被移除，图片数量不超过服务的上限，指定了宽高比时使用服务推荐的尺寸
+// 请求依赖服务不支持的能力（图生图、局部重绘、变体）时返回 ErrIncompatible
+func (c Capabilities) Adapt(req Request, ratio string) (Request, error) {
+	switch {
+	case req.Variation:
+		if !c.Variation {
+			return req, ErrIncompatible
+		}
+	case req.Mask != "":
+		if !c.Inpaint {
+			return req, ErrIncompatible
+		}
+	case req.Image != "":
+		if !c.ImageToImage {
+			return req, ErrIncompatible
+		}
+	default:
+		if !c.TextToImage {
+			return req, ErrIncompatible
+		}
+	}
+
+	if !c.NegativePrompt {
+		req.NegativePrompt = ""
+	}
+
+	if !c.StylePreset {
+		req.StylePreset = ""
+	}
+
+	if !c.ImageStrength {
+		req.ImageStrength = 0
+	}
+
+	if !c.Seed {
+		req.Seed = 0
+	}
+
+	if !c.Steps {
+		req.Steps = 0
+	}
+
+	if !c.Upscale {
+		req.UpscaleBy = ""
+	}
+
+	if c.MaxImageCount > 0 && req.ImageCount > c.MaxImageCount {
+		req.ImageCount = c.MaxImageCount
+	}
+
+	if ratio != "" {
+		dim := c.Dimension(ratio)
+		req.Width, req.Height = int64(dim.Width), int64(dim.Height)
+	}
+
+	return req, nil
+}
+
+// Request 图片生成请求
+type Request struct {
+	Model          string  `json:"model,omitempty"`
+	Prompt         string  `json:"prompt,omitempty"`
+	NegativePrompt string  `json:"negative_prompt,omitempty"`
+	ImageCount     int64   `json:"image_count,omitempty"`
+	Width          int64   `json:"width,omitempty"`
+	Height         int64   `json:"height,omitempty"`
+	Steps          int64   `json:"steps,omitempty"`
+	Seed           int64   `json:"seed,omitempty"`
+	StylePreset    string  `json:"style_preset,omitempty"`
+	Mode           string  `json:"mode,omitempty"`
+	UpscaleBy      string  `json:"upscale_by,omitempty"`
+	AIRewrite      bool    `json:"ai_rewrite,omitempty"`
+	Image          string  `json:"image,omitempty"`
+	ImageStrength  float64 `json:"image_strength,omitempty"`
+	// Mask 局部重绘的蒙版图片地址，尺寸与 Image 一致，白色区域为需要重绘的区域
+	Mask string `json:"mask,omitempty"`
+	// Variation 使用服务商的变体接口，根据 Image 生成相似的图片，忽略提示语
+	Variation bool `json:"variation,omitempty"`
+
+	// Workflow 模型配置的工作流模板，ComfyUI 使用
+	Workflow string `json:"workflow,omitempty"`
+	// Sampler 模型配置的采样器，Stable Diffusion WebUI 使用
+	Sampler string `json:"sampler,omitempty"`
+	// CfgScale 模型配置的提示语相关性，为 0 时使用默认值
+	CfgScale float64 `json:"cfg_scale,omitempty"`
+}
+
+// Result 图片生成结果
+type Result struct {
+	// TaskID 服务商侧的任务 ID，异步任务需要通过该 ID 查询任务状态
+	TaskID string `json:"task_id,omitempty"`
+	// Finished 任务是否已经完成
+	Finished bool `json:"finished,omitempty"`
+	// Images 生成的图片，可以是远程图片地址，也可以是 Base64Image 编码的图片数据
+	Images []string `json:"images,omitempty"`
+	// Seeds 每张图片实际使用的种子，与 Images 一一对应，服务商没有返回时为空
+	Seeds []int64 `json:"seeds,omitempty"`
+	// Error 任务失败的原因，不为空时表示任务已经失败，不需要再继续查询
+	Error string `json:"error,omitempty"`
+	// RetryAfter 异步任务未完成时，下次查询任务状态的等待时间
+	RetryAfter time.Duration `json:"retry_after,omitempty"`
+	// Progress 异步任务未完成时的进度百分比（0-100），服务商没有返回进度时为 0
+	Progress int `json:"progress,omitempty"`
+}
+
+// Generator 图片生成服务
+type Generator interface {
+	// Vendor 服务商标识，与 image_models 表中的 vendor 字段对应
+	Vendor() string
+	// Capabilities 服务支持的能力
+	Capabilities() Capabilities
+	// Generate 生成图片，同步服务直接返回生成的图片，异步服务返回任务 ID
+	Generate(ctx context.Context, req Request) (*Result, error)
+}
+
+// AsyncGenerator 异步图片生成服务，需要轮询任务状态
+type AsyncGenerator interface {
+	Generator
+	// Query 查询任务状态，返回 error 时表示查询失败，会在稍后重试
+	Query(ctx context.Context, req Request, taskID string) (*Result, error)
+}
+
+// Canceler 支持取消任务的异步图片生成服务
+type Canceler interface {
+	// Cancel 取消服务商侧的任务，服务商不支持取消当前状态的任务时返回 error
+	Cancel(ctx context.Context, req Request, taskID string) error
+}
+
+const base64ImagePrefix = "data:image/png;base64,"
+
+// Base64Image 将 Base64 编码的图片数据转换为 Result.Images 中使用的格式
+func Base64Image(data string) string {
+	return base64ImagePrefix + data
+}
+
+// DecodeBase64Image 解析 Base64Image 格式的图片数据，如果不是 Base64Image 格式，则第二个返回值为 false
+func DecodeBase64Image(image string) ([]byte, bool, error) {
+	if !strings.HasPrefix(image, base64ImagePrefix) {
+		return nil, false, nil
+	}
+
+	data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(image, base64ImagePrefix))
+	return data, true, err
+}
+
+// finished 同步生成的结果
+func finished(images ...string) *Result {
+	return &Result{Finished: true, Images: images}
+}
+
+// progress 根据已完成的子任务数量计算任务进度百分比
+func progress(finished, total int) int {
+	if total <= 0 || finished <= 0 {
+		return 0
+	}
+
+	return min(finished*100/total, 100)
+}
@@ -0,0 +1,132 @@
+package imagegen
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/mylxsw/aidea-server/pkg/ai/leap"
+	"github.com/mylxsw/aidea-server/pkg/uploader"
+	"github.com/mylxsw/asteria/log"
+	"github.com/mylxsw/go-utils/str"
+	"github.com/mylxsw/go-utils/ternary"
+)
+
+type leapResponse interface {
+	GetID() string
+	GetState() string
+	IsFinished() bool
+	IsProcessing() bool
+	GetImages() []string
+}
+
+type leapGenerator struct {
+	client *leap.LeapAI
+}
+
+// NewLeapGenerator 使用 Leap 生成图片
+func NewLeapGenerator(client *leap.LeapAI) AsyncGenerator {
+	return &leapGenerator{client: client}
+}
+
+func (g *leapGenerator) Vendor() string {
+	return "leapai"
+}
+
+func (g *leapGenerator) Capabilities() Capabilities {
+	return Capabilities{
+		TextToImage:     true,
+		ImageToImage:    true,
+		NegativePrompt:  true,
+		Seed:            true,
+		Steps:           true,
+		Upscale:         true,
+		TranslatePrompt: true,
+		RewritePrompt:   true,
+		Async:           true,
+	}
+}
+
+func (g *leapGenerator) Generate(ctx context.Context, req Request) (*Result, error) {
+	var resp leapResponse
+	var err error
+
+	if req.Image != "" {
+		// 先尝试本地下载，成功则发送文件到 Leap
+		// 如果本地下载失败，则直接发送远程图片地址到 Leap
+		localImagePath := req.Image
+		if imagePath, err := uploader.DownloadRemoteFile(ctx, req.Image); err != nil {
+			log.With(req).Errorf("下载远程图片失败: %s", err)
+		} else {
+			localImagePath = imagePath
+			defer os.Remove(imagePath)
+		}
+
+		remixReq := leap.RemixImageRequest{
+			Prompt:         req.Prompt,
+			NegativePrompt: req.NegativePrompt,
+			Seed:           req.Seed,
+			Steps:          req.Steps,
+			NumberOfImages: req.ImageCount,
+			Mode:           req.Mode,
+		}
+
+		if !str.HasPrefixes(localImagePath, []string{"http://", "https://"}) {
+			remixReq.Files = localImagePath
+			resp, err = g.client.RemixImageUpload(ctx, req.Model, &remixReq)
+		} else {
+			remixReq.ImageUrl = localImagePath
+			resp, err = g.client.RemixImageURL(ctx, req.Model, &remixReq)
+		}
+	} else {
+		resp, err = g.client.TextToImage(ctx, req.Model, &leap.TextToImageRequest{
+			Prompt:         req.Prompt,
+			NegativePrompt: req.NegativePrompt,
+			Width:          req.Width,
+			Height:         req.Height,
+			NumberOfImages: req.ImageCount,
+			Seed:           req.Seed,
+			Steps:          req.Steps,
+			EnhancePrompt:  true,
+			Sampler:        "dpm_plusplus_sde",
+			// 默认 4 倍放大
+			UpscaleBy:    ternary.If(req.UpscaleBy == "x1", "x4", req.UpscaleBy),
+			RestoreFaces: true,
+		})
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return g.result(resp, 15*time.Second), nil
+}
+
+func (g *leapGenerator) Query(ctx context.Context, req Request, taskID string) (*Result, error) {
+	var resp leapResponse
+	var err error
+	if req.Image != "" {
+		resp, err = g.client.QueryRemixImageJobResult(ctx, req.Model, taskID)
+	} else {
+		resp, err = g.client.QueryTextToImageJobResult(ctx, req.Model, taskID)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return g.result(resp, 10*time.Second), nil
+}
+
+func (g *leapGenerator) result(resp leapResponse, retryAfter time.Duration) *Result {
+	if resp.IsFinished() {
+		return &Result{TaskID: resp.GetID(), Finished: true, Images: resp.GetImages()}
+	}
+
+	if !resp.IsProcessing() {
+		log.Warningf("leap task %s state is %s", resp.GetID(), resp.GetState())
+		return &Result{TaskID: resp.GetID(), Error: "任务处理失败，请重试"}
+	}
+
+	return &Result{TaskID: resp.GetID(), RetryAfter: retryAfter}
+}
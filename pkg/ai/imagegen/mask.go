@@ -0,0 +1,184 @@
+package imagegen
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+
+	_ "image/jpeg"
+
+	"github.com/mylxsw/aidea-server/pkg/uploader"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	// EditModeInpaint 局部重绘，根据蒙版重绘图片的指定区域
+	EditModeInpaint = "inpaint"
+	// EditModeOutpaint 扩图，扩展图片画布并生成扩展区域的内容，会被转换为局部重绘处理
+	EditModeOutpaint = "outpaint"
+)
+
+// ImageSize 返回图片的宽度和高度
+func ImageSize(data []byte) (int, int, error) {
+	conf, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return conf.Width, conf.Height, nil
+}
+
+// ExtendCanvas 扩展图片画布（扩图），left/top/right/bottom 为四个方向扩展的像素值，
+// 原图放置在扩展后画布的对应位置，扩展区域使用原图边缘像素填充，
+// 返回扩展后的图片以及对应的蒙版（白色为需要重绘的区域，黑色为保留的区域），均为 PNG 格式
+func ExtendCanvas(data []byte, left, top, right, bottom int) ([]byte, []byte, error) {
+	if left < 0 || top < 0 || right < 0 || bottom < 0 {
+		return nil, nil, fmt.Errorf("invalid extent: %d, %d, %d, %d", left, top, right, bottom)
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	bounds := src.Bounds()
+	width, height := bounds.Dx()+left+right, bounds.Dy()+top+bottom
+
+	canvas := image.NewRGBA(image.Rect(0, 0, width, height))
+	mask := image.NewGray(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			sx, sy := x-left, y-top
+			inside := sx >= 0 && sx < bounds.Dx() && sy >= 0 && sy < bounds.Dy()
+
+			canvas.Set(x, y, src.At(bounds.Min.X+clamp(sx, 0, bounds.Dx()-1), bounds.Min.Y+clamp(sy, 0, bounds.Dy()-1)))
+			if inside {
+				mask.SetGray(x, y, color.Gray{Y: 0})
+			} else {
+				mask.SetGray(x, y, color.Gray{Y: 255})
+			}
+		}
+	}
+
+	canvasData, err := encodePNG(canvas)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	maskData, err := encodePNG(mask)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return canvasData, maskData, nil
+}
+
+// ResizeImage 调整图片尺寸，返回 PNG 格式的图片
+func ResizeImage(data []byte, width, height int) ([]byte, error) {
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	if src.Bounds().Dx() == width && src.Bounds().Dy() == height {
+		return encodePNG(src)
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, src.Bounds(), draw.Src, nil)
+
+	return encodePNG(dst)
+}
+
+// MaskToAlpha 将蒙版（白色为需要重绘的区域）转换为使用透明区域表示重绘区域的蒙版，用于 OpenAI 的图片编辑接口
+func MaskToAlpha(data []byte) ([]byte, error) {
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := src.Bounds()
+	dst := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	for y := 0; y < bounds.Dy(); y++ {
+		for x := 0; x < bounds.Dx(); x++ {
+			gray := color.GrayModel.Convert(src.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.Gray)
+			if gray.Y >= 128 {
+				dst.SetNRGBA(x, y, color.NRGBA{})
+			} else {
+				dst.SetNRGBA(x, y, color.NRGBA{A: 255})
+			}
+		}
+	}
+
+	return encodePNG(dst)
+}
+
+// downloadImage 下载远程图片
+func downloadImage(ctx context.Context, url string) ([]byte, error) {
+	imagePath, err := uploader.DownloadRemoteFile(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(imagePath)
+
+	return os.ReadFile(imagePath)
+}
+
+// downloadImageAndMask 下载局部重绘的图片和蒙版，并根据 resize 返回的尺寸调整为 PNG 格式的图片，
+// resize 为空时保持原图尺寸，蒙版尺寸与图片不一致时，会被调整为图片的尺寸
+func downloadImageAndMask(ctx context.Context, imageURL, maskURL string, resize func(width, height int) (int, int)) ([]byte, []byte, error) {
+	imageData, err := downloadImage(ctx, imageURL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("download image failed: %w", err)
+	}
+
+	maskData, err := downloadImage(ctx, maskURL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("download mask failed: %w", err)
+	}
+
+	width, height, err := ImageSize(imageData)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if resize != nil {
+		width, height = resize(width, height)
+	}
+
+	if imageData, err = ResizeImage(imageData, width, height); err != nil {
+		return nil, nil, err
+	}
+
+	if maskData, err = ResizeImage(maskData, width, height); err != nil {
+		return nil, nil, err
+	}
+
+	return imageData, maskData, nil
+}
+
+func encodePNG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func clamp(val, lo, hi int) int {
+	if val < lo {
+		return lo
+	}
+
+	if val > hi {
+		return hi
+	}
+
+	return val
+}
@@ -0,0 +1,86 @@
+package imagegen_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/mylxsw/aidea-server/pkg/ai/imagegen"
+	"github.com/mylxsw/go-utils/assert"
+)
+
+func createImage(t *testing.T, width, height int, c color.Color) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, c)
+		}
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func decodeImage(t *testing.T, data []byte) image.Image {
+	img, err := png.Decode(bytes.NewReader(data))
+	assert.NoError(t, err)
+	return img
+}
+
+func TestExtendCanvas(t *testing.T) {
+	src := createImage(t, 4, 3, color.RGBA{R: 255, A: 255})
+
+	canvas, mask, err := imagegen.ExtendCanvas(src, 2, 1, 0, 3)
+	assert.NoError(t, err)
+
+	width, height, err := imagegen.ImageSize(canvas)
+	assert.NoError(t, err)
+	assert.Equal(t, 6, width)
+	assert.Equal(t, 7, height)
+
+	// 扩展区域使用边缘像素填充
+	r, _, _, _ := decodeImage(t, canvas).At(0, 0).RGBA()
+	assert.Equal(t, uint32(0xffff), r)
+
+	maskImg := decodeImage(t, mask)
+	assert.Equal(t, 6, maskImg.Bounds().Dx())
+	assert.Equal(t, 7, maskImg.Bounds().Dy())
+	assert.Equal(t, color.Gray{Y: 255}, color.GrayModel.Convert(maskImg.At(0, 0)))
+	assert.Equal(t, color.Gray{Y: 255}, color.GrayModel.Convert(maskImg.At(3, 6)))
+	assert.Equal(t, color.Gray{Y: 0}, color.GrayModel.Convert(maskImg.At(2, 1)))
+	assert.Equal(t, color.Gray{Y: 0}, color.GrayModel.Convert(maskImg.At(5, 3)))
+
+	_, _, err = imagegen.ExtendCanvas(src, -1, 0, 0, 0)
+	assert.True(t, err != nil)
+}
+
+func TestMaskToAlpha(t *testing.T) {
+	mask := image.NewGray(image.Rect(0, 0, 2, 1))
+	mask.SetGray(0, 0, color.Gray{Y: 255})
+	mask.SetGray(1, 0, color.Gray{Y: 0})
+
+	var buf bytes.Buffer
+	assert.NoError(t, png.Encode(&buf, mask))
+
+	data, err := imagegen.MaskToAlpha(buf.Bytes())
+	assert.NoError(t, err)
+
+	img := decodeImage(t, data)
+	_, _, _, a := img.At(0, 0).RGBA()
+	assert.Equal(t, uint32(0), a)
+	_, _, _, a = img.At(1, 0).RGBA()
+	assert.Equal(t, uint32(0xffff), a)
+}
+
+func TestResizeImage(t *testing.T) {
+	data, err := imagegen.ResizeImage(createImage(t, 130, 70, color.White), 128, 64)
+	assert.NoError(t, err)
+
+	width, height, err := imagegen.ImageSize(data)
+	assert.NoError(t, err)
+	assert.Equal(t, 128, width)
+	assert.Equal(t, 64, height)
+}
@@ -0,0 +1,161 @@
+package imagegen
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/mylxsw/aidea-server/config"
+	"github.com/mylxsw/aidea-server/pkg/ai/comfyui"
+	"github.com/mylxsw/aidea-server/pkg/ai/dashscope"
+	"github.com/mylxsw/aidea-server/pkg/ai/deepai"
+	"github.com/mylxsw/aidea-server/pkg/ai/fromston"
+	"github.com/mylxsw/aidea-server/pkg/ai/getimgai"
+	"github.com/mylxsw/aidea-server/pkg/ai/leap"
+	"github.com/mylxsw/aidea-server/pkg/ai/openai"
+	"github.com/mylxsw/aidea-server/pkg/ai/sdwebui"
+	"github.com/mylxsw/aidea-server/pkg/ai/stabilityai"
+	"github.com/mylxsw/aidea-server/pkg/misc"
+	"github.com/mylxsw/aidea-server/pkg/proxy"
+	"github.com/mylxsw/aidea-server/pkg/repo"
+	"github.com/mylxsw/glacier/infra"
+	"github.com/mylxsw/go-utils/ternary"
+	"gopkg.in/resty.v1"
+)
+
+type Provider struct{}
+
+func (Provider) Register(binder infra.Binder) {
+	binder.MustSingleton(NewRegistry)
+}
+
+// NewRegistry 创建图片生成服务注册中心，注册配置文件中已启用的服务商，以及支持通过 channels 表配置的渠道类型
+func NewRegistry(resolver infra.Resolver, conf *config.Config) *Registry {
+	registry := New()
+
+	if conf.EnableLeapAI {
+		resolver.MustResolve(func(client *leap.LeapAI) { registry.Register(NewLeapGenerator(client)) })
+	}
+
+	if conf.EnableStabilityAI {
+		resolver.MustResolve(func(client *stabilityai.StabilityAI) { registry.Register(NewStabilityAIGenerator(client)) })
+	}
+
+	if conf.EnableDeepAI {
+		resolver.MustResolve(func(client *deepai.DeepAI) { registry.Register(NewDeepAIGenerator(client)) })
+	}
+
+	if conf.EnableFromstonAI {
+		resolver.MustResolve(func(client *fromston.Fromston) { registry.Register(NewFromstonGenerator(client)) })
+	}
+
+	if conf.EnableGetimgAI {
+		resolver.MustResolve(func(client *getimgai.GetimgAI) { registry.Register(NewGetimgAIGenerator(client)) })
+	}
+
+	if conf.EnableDashScopeAI {
+		resolver.MustResolve(func(client *dashscope.DashScope) { registry.Register(NewDashscopeGenerator(client)) })
+	}
+
+	if conf.EnableOpenAIDalle {
+		resolver.MustResolve(func(client *openai.DalleImageClient) { registry.Register(NewDalleGenerator(client)) })
+	}
+
+	var pp *proxy.Proxy
+	if conf.SupportProxy() {
+		resolver.MustResolve(func(p *proxy.Proxy) { pp = p })
+	}
+
+	// 渠道配置中，Server 为服务地址（为空时使用配置文件中的地址），Secret 为 API Key
+	registry.RegisterFactory("leapai", "Leap AI", func(ch *repo.Channel) (Generator, error) {
+		c := *conf
+		c.LeapAIKey = ch.Secret
+		if ch.Server != "" {
+			c.LeapAIServers = []string{ch.Server}
+		}
+
+		client := &http.Client{Timeout: 180 * time.Second}
+		if ch.Meta.UsingProxy && pp != nil {
+			client.Transport = pp.BuildTransport()
+		}
+
+		return NewLeapGenerator(leap.NewLeapAIWithClient(&c, client, channelRestyClient(ch, pp))), nil
+	})
+
+	registry.RegisterFactory("stabilityai", "Stability AI", func(ch *repo.Channel) (Generator, error) {
+		c := *conf
+		c.StabilityAIKey = ch.Secret
+		if ch.Server != "" {
+			c.StabilityAIServer = []string{ch.Server}
+		}
+
+		client := &http.Client{Timeout: 300 * time.Second}
+		if ch.Meta.UsingProxy && pp != nil {
+			client.Transport = pp.BuildTransport()
+		}
+
+		return NewStabilityAIGenerator(stabilityai.NewStabilityAIWithClient(&c, client)), nil
+	})
+
+	registry.RegisterFactory("getimgai", "Getimg.ai", func(ch *repo.Channel) (Generator, error) {
+		c := *conf
+		c.GetimgAIKey = ch.Secret
+		c.GetimgAIServer = ternary.If(ch.Server != "", ch.Server, conf.GetimgAIServer)
+
+		return NewGetimgAIGenerator(getimgai.NewGetimgAIWithResty(&c, channelRestyClient(ch, pp))), nil
+	})
+
+	registry.RegisterFactory("deepai", "DeepAI", func(ch *repo.Channel) (Generator, error) {
+		c := *conf
+		c.DeepAIKey = ch.Secret
+		if ch.Server != "" {
+			c.DeepAIServer = []string{ch.Server}
+		}
+
+		return NewDeepAIGenerator(deepai.NewDeepAIRaw(&c)), nil
+	})
+
+	registry.RegisterFactory("fromston", "6Pen", func(ch *repo.Channel) (Generator, error) {
+		c := *conf
+		c.FromstonKey = ch.Secret
+		c.FromstonServer = ternary.If(ch.Server != "", ch.Server, conf.FromstonServer)
+
+		return NewFromstonGenerator(fromston.NewFromston(&c)), nil
+	})
+
+	registry.RegisterFactory("dashscope", "阿里灵积（图片）", func(ch *repo.Channel) (Generator, error) {
+		return NewDashscopeGenerator(dashscope.New(ch.Secret)), nil
+	})
+
+	registry.RegisterFactory("dalle", "DALL·E", func(ch *repo.Channel) (Generator, error) {
+		return NewDalleGenerator(openai.NewDalleImageClient(&openai.Config{
+			Enable:           true,
+			OpenAIAzure:      ch.Meta.OpenAIAzure,
+			OpenAIAPIVersion: ch.Meta.OpenAIAzureAPIVersion,
+			OpenAIServers:    []string{ternary.If(ch.Server != "", ch.Server, "https://api.openai.com/v1")},
+			OpenAIKeys:       []string{ch.Secret},
+			AutoProxy:        ch.Meta.UsingProxy,
+		}, pp)), nil
+	})
+
+	// 自托管的图片生成服务，只能通过渠道配置使用，渠道的 Server 为服务地址
+	// ComfyUI 的 Secret 为 Bearer Token，Stable Diffusion WebUI 的 Secret 为 username:password，不需要认证时留空
+	registry.RegisterFactory("comfyui", "ComfyUI", func(ch *repo.Channel) (Generator, error) {
+		return NewComfyUIGenerator(comfyui.New(ch.Server, ch.Secret, channelRestyClient(ch, pp))), nil
+	})
+
+	registry.RegisterFactory("sdwebui", "Stable Diffusion WebUI", func(ch *repo.Channel) (Generator, error) {
+		return NewSDWebUIGenerator(sdwebui.New(ch.Server, ch.Secret, channelRestyClient(ch, pp).SetTimeout(300*time.Second))), nil
+	})
+
+	return registry
+}
+
+// channelRestyClient 根据渠道配置创建 HTTP 客户端
+func channelRestyClient(ch *repo.Channel, pp *proxy.Proxy) *resty.Client {
+	client := misc.RestyClient(2).SetTimeout(180 * time.Second)
+	if ch.Meta.UsingProxy && pp != nil {
+		client.SetTransport(pp.BuildTransport())
+	}
+
+	return client
+}
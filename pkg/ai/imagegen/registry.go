@@ -0,0 +1,146 @@
+package imagegen
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/mylxsw/aidea-server/pkg/repo"
+)
+
+// Factory 根据 channels 表中的渠道配置创建图片生成服务
+type Factory func(ch *repo.Channel) (Generator, error)
+
+// ChannelType 支持通过 channels 表配置的渠道类型
+type ChannelType struct {
+	Name    string `json:"name"`
+	Display string `json:"display"`
+}
+
+type channelFactory struct {
+	display string
+	factory Factory
+}
+
+// Registry 图片生成服务注册中心
+//
+// 通过配置文件启用的服务商使用 Register 注册，可以直接根据 vendor 获取；
+// 通过 channels 表配置的渠道，根据渠道类型使用 RegisterFactory 注册的工厂函数动态创建
+type Registry struct {
+	lock       sync.RWMutex
+	generators map[string]Generator
+	factories  map[string]channelFactory
+}
+
+// New 创建一个空的图片生成服务注册中心
+func New() *Registry {
+	return &Registry{
+		generators: make(map[string]Generator),
+		factories:  make(map[string]channelFactory),
+	}
+}
+
+// Register 注册图片生成服务，相同 vendor 的服务会被覆盖
+func (r *Registry) Register(gen Generator) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.generators[gen.Vendor()] = gen
+}
+
+// RegisterFactory 注册渠道类型对应的图片生成服务工厂函数，display 为渠道类型的展示名称
+func (r *Registry) RegisterFactory(channelType string, display string, factory Factory) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.factories[channelType] = channelFactory{display: display, factory: factory}
+}
+
+// Get 获取已注册的图片生成服务
+func (r *Registry) Get(vendor string) (Generator, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	gen, ok := r.generators[vendor]
+	return gen, ok
+}
+
+// Enabled 服务商是否已启用
+func (r *Registry) Enabled(vendor string) bool {
+	_, ok := r.Get(vendor)
+	return ok
+}
+
+// SupportChannel 是否支持使用该类型的渠道
+func (r *Registry) SupportChannel(channelType string) bool {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	_, ok := r.factories[channelType]
+	return ok
+}
+
+// ChannelTypes 返回所有支持的渠道类型，按照名称排序
+func (r *Registry) ChannelTypes() []ChannelType {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	types := make([]ChannelType, 0, len(r.factories))
+	for name, f := range r.factories {
+		types = append(types, ChannelType{Name: name, Display: f.display})
+	}
+
+	sort.Slice(types, func(i, j int) bool { return types[i].Name < types[j].Name })
+	return types
+}
+
+// Vendors 返回所有已启用的服务商
+func (r *Registry) Vendors() []string {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	vendors := make([]string, 0, len(r.generators))
+	for vendor := range r.generators {
+		vendors = append(vendors, vendor)
+	}
+
+	sort.Strings(vendors)
+	return vendors
+}
+
+// Resolve 获取图片生成服务，指定渠道时，优先使用渠道配置创建服务，否则使用 vendor 对应的已注册服务
+func (r *Registry) Resolve(vendor string, ch *repo.Channel) (Generator, error) {
+	if ch != nil {
+		r.lock.RLock()
+		f, ok := r.factories[ch.Type]
+		r.lock.RUnlock()
+
+		if !ok {
+			return nil, fmt.Errorf("%w: unsupported channel type %s", ErrGeneratorNotFound, ch.Type)
+		}
+
+		return f.factory(ch)
+	}
+
+	gen, ok := r.Get(vendor)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrGeneratorNotFound, vendor)
+	}
+
+	return gen, nil
+}
+
+// ResolveChannel 获取图片生成服务，channelID 大于 0 时，使用 channels 表中对应的渠道配置创建
+func (r *Registry) ResolveChannel(ctx context.Context, channels *repo.ModelRepo, vendor string, channelID int64) (Generator, error) {
+	if channelID <= 0 {
+		return r.Resolve(vendor, nil)
+	}
+
+	ch, err := channels.GetChannel(ctx, channelID)
+	if err != nil {
+		return nil, fmt.Errorf("get channel %d failed: %w", channelID, err)
+	}
+
+	return r.Resolve(vendor, ch)
+}
@@ -0,0 +1,130 @@
+package imagegen_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mylxsw/aidea-server/pkg/ai/imagegen"
+	"github.com/mylxsw/aidea-server/pkg/repo"
+	"github.com/mylxsw/aidea-server/pkg/repo/model"
+	"github.com/mylxsw/go-utils/assert"
+)
+
+type fakeGenerator struct {
+	vendor string
+	key    string
+}
+
+func (g fakeGenerator) Vendor() string { return g.vendor }
+
+func (g fakeGenerator) Capabilities() imagegen.Capabilities {
+	return imagegen.Capabilities{TextToImage: true, MaxImageCount: 1}
+}
+
+func (g fakeGenerator) Generate(ctx context.Context, req imagegen.Request) (*imagegen.Result, error) {
+	return &imagegen.Result{Finished: true, Images: []string{g.key + ":" + req.Prompt}}, nil
+}
+
+func TestRegistry(t *testing.T) {
+	registry := imagegen.New()
+	registry.Register(fakeGenerator{vendor: "stabilityai", key: "conf"})
+	registry.Register(fakeGenerator{vendor: "leapai", key: "conf"})
+	registry.RegisterFactory("stabilityai", "Stability AI", func(ch *repo.Channel) (imagegen.Generator, error) {
+		return fakeGenerator{vendor: "stabilityai", key: ch.Secret}, nil
+	})
+
+	assert.True(t, registry.Enabled("stabilityai"))
+	assert.False(t, registry.Enabled("dalle"))
+	assert.True(t, registry.SupportChannel("stabilityai"))
+	assert.False(t, registry.SupportChannel("leapai"))
+	assert.Equal(t, []string{"leapai", "stabilityai"}, registry.Vendors())
+	assert.Equal(t, []imagegen.ChannelType{{Name: "stabilityai", Display: "Stability AI"}}, registry.ChannelTypes())
+
+	gen, err := registry.Resolve("stabilityai", nil)
+	assert.NoError(t, err)
+
+	res, err := gen.Generate(context.TODO(), imagegen.Request{Prompt: "cat"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"conf:cat"}, res.Images)
+
+	gen, err = registry.Resolve("stabilityai", &repo.Channel{Channels: model.Channels{Type: "stabilityai", Secret: "channel"}})
+	assert.NoError(t, err)
+
+	res, err = gen.Generate(context.TODO(), imagegen.Request{Prompt: "dog"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"channel:dog"}, res.Images)
+
+	_, err = registry.Resolve("dalle", nil)
+	assert.True(t, errors.Is(err, imagegen.ErrGeneratorNotFound))
+
+	_, err = registry.Resolve("leapai", &repo.Channel{Channels: model.Channels{Type: "leapai"}})
+	assert.True(t, errors.Is(err, imagegen.ErrGeneratorNotFound))
+}
+
+func TestCapabilities_Dimension(t *testing.T) {
+	caps := imagegen.Capabilities{
+		Dimensions: map[string]imagegen.Dimension{
+			"1:1": {Width: 1024, Height: 1024},
+			"3:2": {Width: 0, Height: 768},
+		},
+	}
+
+	assert.Equal(t, imagegen.Dimension{Width: 1024, Height: 1024}, caps.Dimension("1:1"))
+	assert.Equal(t, imagegen.Dimension{Width: 768, Height: 512}, caps.Dimension("3:2"))
+	assert.Equal(t, imagegen.Dimension{Width: 1024, Height: 576}, caps.Dimension("16:9"))
+	assert.Equal(t, imagegen.Dimension{Width: 512, Height: 512}, caps.Dimension("5:4"))
+}
+
+func TestCapabilities_Adapt(t *testing.T) {
+	caps := imagegen.Capabilities{
+		TextToImage:   true,
+		ImageToImage:  true,
+		Seed:          true,
+		MaxImageCount: 2,
+		Dimensions:    map[string]imagegen.Dimension{"1:1": {Width: 1024, Height: 1024}},
+	}
+
+	req, err := caps.Adapt(imagegen.Request{
+		Prompt:         "a cat",
+		NegativePrompt: "blurry",
+		ImageCount:     4,
+		Width:          512,
+		Height:         512,
+		Steps:          30,
+		Seed:           42,
+		StylePreset:    "anime",
+		UpscaleBy:      "x2",
+	}, "1:1")
+	assert.NoError(t, err)
+	assert.Equal(t, "a cat", req.Prompt)
+	assert.Equal(t, "", req.NegativePrompt)
+	assert.Equal(t, int64(2), req.ImageCount)
+	assert.Equal(t, int64(1024), req.Width)
+	assert.Equal(t, int64(1024), req.Height)
+	assert.Equal(t, int64(0), req.Steps)
+	assert.Equal(t, int64(42), req.Seed)
+	assert.Equal(t, "", req.StylePreset)
+	assert.Equal(t, "", req.UpscaleBy)
+
+	_, err = caps.Adapt(imagegen.Request{Image: "https://example.com/a.png", Mask: "https://example.com/mask.png"}, "")
+	assert.True(t, errors.Is(err, imagegen.ErrIncompatible))
+
+	_, err = caps.Adapt(imagegen.Request{Image: "https://example.com/a.png", Variation: true}, "")
+	assert.True(t, errors.Is(err, imagegen.ErrIncompatible))
+
+	req, err = caps.Adapt(imagegen.Request{Image: "https://example.com/a.png", Width: 768, Height: 512}, "")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(768), req.Width)
+}
+
+func TestDecodeBase64Image(t *testing.T) {
+	data, isBase64, err := imagegen.DecodeBase64Image(imagegen.Base64Image("aGVsbG8="))
+	assert.NoError(t, err)
+	assert.True(t, isBase64)
+	assert.Equal(t, "hello", string(data))
+
+	_, isBase64, err = imagegen.DecodeBase64Image("https://example.com/a.png")
+	assert.NoError(t, err)
+	assert.False(t, isBase64)
+}
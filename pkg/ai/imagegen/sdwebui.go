@@ -0,0 +1,114 @@
+package imagegen
+
+import (
+	"context"
+	"encoding/base64"
+	"os"
+
+	"github.com/mylxsw/aidea-server/pkg/ai/sdwebui"
+	"github.com/mylxsw/aidea-server/pkg/uploader"
+	"github.com/mylxsw/go-utils/array"
+	"github.com/mylxsw/go-utils/ternary"
+)
+
+type sdWebUIGenerator struct {
+	client *sdwebui.SDWebUI
+}
+
+// NewSDWebUIGenerator 使用自托管的 Stable Diffusion WebUI（AUTOMATIC1111）生成图片
+func NewSDWebUIGenerator(client *sdwebui.SDWebUI) Generator {
+	return &sdWebUIGenerator{client: client}
+}
+
+func (g *sdWebUIGenerator) Vendor() string {
+	return "sdwebui"
+}
+
+func (g *sdWebUIGenerator) Capabilities() Capabilities {
+	return Capabilities{
+		TextToImage:     true,
+		ImageToImage:    true,
+		NegativePrompt:  true,
+		ImageStrength:   true,
+		Seed:            true,
+		Steps:           true,
+		Inpaint:         true,
+		TranslatePrompt: true,
+		RewritePrompt:   true,
+	}
+}
+
+func (g *sdWebUIGenerator) Generate(ctx context.Context, req Request) (*Result, error) {
+	txt2img := sdwebui.TextToImageRequest{
+		Prompt:         req.Prompt,
+		NegativePrompt: req.NegativePrompt,
+		Width:          req.Width,
+		Height:         req.Height,
+		Steps:          req.Steps,
+		Seed:           req.Seed,
+		CfgScale:       ternary.If(req.CfgScale > 0, req.CfgScale, 7.0),
+		SamplerName:    req.Sampler,
+		BatchSize:      req.ImageCount,
+	}
+
+	// 模型名称为 WebUI 中的 checkpoint 名称，为 default 时使用 WebUI 当前加载的模型
+	if req.Model != "" && req.Model != "default" {
+		txt2img.OverrideSettings = map[string]any{"sd_model_checkpoint": req.Model}
+	}
+
+	var resp *sdwebui.ImageResponse
+	if req.Image != "" {
+		imagePath, err := uploader.DownloadRemoteFile(ctx, req.Image)
+		if err != nil {
+			return nil, err
+		}
+		defer os.Remove(imagePath)
+
+		data, err := os.ReadFile(imagePath)
+		if err != nil {
+			return nil, err
+		}
+
+		img2img := sdwebui.ImageToImageRequest{
+			TextToImageRequest: txt2img,
+			InitImages:         []string{base64.StdEncoding.EncodeToString(data)},
+			DenoisingStrength:  req.ImageStrength,
+		}
+
+		// 局部重绘，蒙版区域以原图内容为基础重绘
+		if req.Mask != "" {
+			mask, err := downloadImage(ctx, req.Mask)
+			if err != nil {
+				return nil, err
+			}
+
+			img2img.Mask = base64.StdEncoding.EncodeToString(mask)
+			img2img.MaskBlur = 4
+			img2img.InpaintingFill = 1
+		}
+
+		resp, err = g.client.ImageToImage(ctx, img2img)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		var err error
+		resp, err = g.client.TextToImage(ctx, txt2img)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 批量生成时，WebUI 可能会在最前面返回拼接后的预览图，只保留最后生成的图片
+	images := resp.Images
+	if req.ImageCount > 0 && int64(len(images)) > req.ImageCount {
+		images = images[int64(len(images))-req.ImageCount:]
+	}
+
+	res := finished(array.Map(images, func(item string, _ int) string { return Base64Image(item) })...)
+	if seeds := resp.Seeds(); len(seeds) == len(images) {
+		res.Seeds = seeds
+	}
+
+	return res, nil
+}
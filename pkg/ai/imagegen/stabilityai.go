@@ -0,0 +1,117 @@
+package imagegen
+
+import (
+	"context"
+	"os"
+
+	"github.com/mylxsw/aidea-server/pkg/ai/stabilityai"
+	"github.com/mylxsw/aidea-server/pkg/uploader"
+	"github.com/mylxsw/go-utils/array"
+)
+
+type stabilityAIGenerator struct {
+	client *stabilityai.StabilityAI
+}
+
+// NewStabilityAIGenerator 使用 StabilityAI 生成图片
+func NewStabilityAIGenerator(client *stabilityai.StabilityAI) Generator {
+	return &stabilityAIGenerator{client: client}
+}
+
+func (g *stabilityAIGenerator) Vendor() string {
+	return "stabilityai"
+}
+
+func (g *stabilityAIGenerator) Capabilities() Capabilities {
+	return Capabilities{
+		TextToImage:     true,
+		ImageToImage:    true,
+		NegativePrompt:  true,
+		StylePreset:     true,
+		ImageStrength:   true,
+		Seed:            true,
+		Steps:           true,
+		SquareImageOnly: true,
+		Inpaint:         true,
+		TranslatePrompt: true,
+		RewritePrompt:   true,
+	}
+}
+
+func (g *stabilityAIGenerator) Generate(ctx context.Context, req Request) (*Result, error) {
+	var resp *stabilityai.TextToImageResponse
+	if req.Image != "" && req.Mask != "" {
+		// 局部重绘，图片宽高需要为 64 的整数倍
+		image, mask, err := downloadImageAndMask(ctx, req.Image, req.Mask, func(width, height int) (int, int) {
+			return width / 64 * 64, height / 64 * 64
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err = g.client.Masking(ctx, req.Model, stabilityai.MaskingRequest{
+			TextPrompt:     req.Prompt,
+			NegativePrompt: req.NegativePrompt,
+			InitImage:      image,
+			MaskImage:      mask,
+			CfgScale:       7,
+			Samples:        int(req.ImageCount),
+			Seed:           int(req.Seed),
+			Steps:          int(req.Steps),
+			StylePreset:    req.StylePreset,
+		})
+		if err != nil {
+			return nil, err
+		}
+	} else if req.Image != "" {
+		// 下载远程图片（图生图）
+		imagePath, err := uploader.DownloadRemoteFile(ctx, req.Image)
+		if err != nil {
+			return nil, err
+		}
+		defer os.Remove(imagePath)
+
+		resp, err = g.client.ImageToImage(ctx, req.Model, stabilityai.ImageToImageRequest{
+			TextPrompt:    req.Prompt,
+			InitImage:     imagePath,
+			CfgScale:      7,
+			Samples:       1,
+			Seed:          int(req.Seed),
+			Steps:         int(req.Steps),
+			StylePreset:   req.StylePreset,
+			ImageStrength: 1.0 - req.ImageStrength,
+		})
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		prompts := []stabilityai.TextPrompts{{Text: req.Prompt, Weight: 0.9}}
+		if req.NegativePrompt != "" {
+			prompts = append(prompts, stabilityai.TextPrompts{Text: req.NegativePrompt, Weight: -0.5})
+		}
+
+		var err error
+		resp, err = g.client.TextToImage(req.Model, stabilityai.TextToImageRequest{
+			TextPrompts: prompts,
+			Width:       int(req.Width),
+			Height:      int(req.Height),
+			CfgScale:    7,
+			Samples:     int(req.ImageCount),
+			Seed:        int(req.Seed),
+			Steps:       int(req.Steps),
+			StylePreset: req.StylePreset,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	res := finished(array.Map(resp.Images, func(img stabilityai.TextToImageImage, _ int) string {
+		return Base64Image(img.Base64)
+	})...)
+	res.Seeds = array.Map(resp.Images, func(img stabilityai.TextToImageImage, _ int) int64 {
+		return int64(img.Seed)
+	})
+
+	return res, nil
+}
@@ -1,6 +1,7 @@
 package openai
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
 	"encoding/json"
@@ -10,6 +11,7 @@ import (
 	"github.com/mylxsw/aidea-server/pkg/uploader"
 	"gopkg.in/resty.v1"
 	"math/rand"
+	"strconv"
 	"time"
 )
 
@@ -126,3 +128,116 @@ func (client *DalleImageClient) CreateImage(ctx context.Context, request ImageRe
 
 	return &ret, nil
 }
+
+type ImageEditRequest struct {
+	// Image The image to edit. Must be a valid PNG file, less than 4MB, and square.
+	Image []byte `json:"-"`
+	// Mask An additional image whose fully transparent areas (e.g. where alpha is zero) indicate where image should be edited.
+	// Must be a valid PNG file, less than 4MB, and have the same dimensions as image.
+	Mask []byte `json:"-"`
+	// Prompt A text description of the desired image(s). The maximum length is 1000 characters.
+	Prompt string `json:"prompt"`
+	// Model The model to use for image generation. Only dall-e-2 is supported at this time.
+	Model string `json:"model,omitempty"`
+	// N The number of images to generate. Must be between 1 and 10.
+	N int64 `json:"n,omitempty"`
+	// Size The size of the generated images. Must be one of 256x256, 512x512, or 1024x1024.
+	Size string `json:"size,omitempty"`
+	// ResponseFormat The format in which the generated images are returned. Must be one of url or b64_json.
+	ResponseFormat string `json:"response_format,omitempty"`
+	// User A unique identifier representing your end-user
+	User string `json:"user,omitempty"`
+}
+
+// EditImage 根据蒙版编辑图片 https://platform.openai.com/docs/api-reference/images/createEdit
+func (client *DalleImageClient) EditImage(ctx context.Context, request ImageEditRequest) (*ImageResponse, error) {
+	formData := map[string]string{
+		"prompt":          request.Prompt,
+		"model":           request.Model,
+		"n":               strconv.Itoa(int(request.N)),
+		"size":            request.Size,
+		"response_format": request.ResponseFormat,
+	}
+	if request.User != "" {
+		formData["user"] = request.User
+	}
+
+	resp, err := client.http.R().
+		SetContext(ctx).
+		SetHeader("Authorization", "Bearer "+client.pickAPIKey()).
+		SetFileReader("image", "image.png", bytes.NewReader(request.Image)).
+		SetFileReader("mask", "mask.png", bytes.NewReader(request.Mask)).
+		SetFormData(formData).
+		Post(fmt.Sprintf("%s/images/edits", client.pickServer()))
+	if err != nil {
+		return nil, err
+	}
+
+	var ret ImageResponse
+	if err := json.Unmarshal(resp.Body(), &ret); err != nil {
+		return nil, err
+	}
+
+	if resp.IsError() {
+		if ret.Error == nil {
+			return nil, fmt.Errorf("edit image failed: %s", string(resp.Body()))
+		}
+
+		return nil, fmt.Errorf("%s: %s", ret.Error.Type, ret.Error.Message)
+	}
+
+	return &ret, nil
+}
+
+type ImageVariationRequest struct {
+	// Image The image to use as the basis for the variation(s). Must be a valid PNG file, less than 4MB, and square.
+	Image []byte `json:"-"`
+	// Model The model to use for image generation. Only dall-e-2 is supported at this time.
+	Model string `json:"model,omitempty"`
+	// N The number of images to generate. Must be between 1 and 10.
+	N int64 `json:"n,omitempty"`
+	// Size The size of the generated images. Must be one of 256x256, 512x512, or 1024x1024.
+	Size string `json:"size,omitempty"`
+	// ResponseFormat The format in which the generated images are returned. Must be one of url or b64_json.
+	ResponseFormat string `json:"response_format,omitempty"`
+	// User A unique identifier representing your end-user
+	User string `json:"user,omitempty"`
+}
+
+// CreateVariation 根据图片生成变体 https://platform.openai.com/docs/api-reference/images/createVariation
+func (client *DalleImageClient) CreateVariation(ctx context.Context, request ImageVariationRequest) (*ImageResponse, error) {
+	formData := map[string]string{
+		"model":           request.Model,
+		"n":               strconv.Itoa(int(request.N)),
+		"size":            request.Size,
+		"response_format": request.ResponseFormat,
+	}
+	if request.User != "" {
+		formData["user"] = request.User
+	}
+
+	resp, err := client.http.R().
+		SetContext(ctx).
+		SetHeader("Authorization", "Bearer "+client.pickAPIKey()).
+		SetFileReader("image", "image.png", bytes.NewReader(request.Image)).
+		SetFormData(formData).
+		Post(fmt.Sprintf("%s/images/variations", client.pickServer()))
+	if err != nil {
+		return nil, err
+	}
+
+	var ret ImageResponse
+	if err := json.Unmarshal(resp.Body(), &ret); err != nil {
+		return nil, err
+	}
+
+	if resp.IsError() {
+		if ret.Error == nil {
+			return nil, fmt.Errorf("create image variation failed: %s", string(resp.Body()))
+		}
+
+		return nil, fmt.Errorf("%s: %s", ret.Error.Type, ret.Error.Message)
+	}
+
+	return &ret, nil
+}
@@ -0,0 +1,113 @@
+package sdwebui
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gopkg.in/resty.v1"
+)
+
+// SDWebUI 自托管的 Stable Diffusion WebUI（AUTOMATIC1111）服务，需要以 --api 参数启动
+type SDWebUI struct {
+	server string
+	auth   string
+	resty  *resty.Client
+}
+
+// New 创建 Stable Diffusion WebUI 客户端，auth 格式为 username:password（对应 --api-auth 参数），为空时不认证
+func New(server, auth string, client *resty.Client) *SDWebUI {
+	return &SDWebUI{
+		server: strings.TrimSuffix(server, "/"),
+		auth:   auth,
+		resty:  client,
+	}
+}
+
+func (sd *SDWebUI) request(ctx context.Context) *resty.Request {
+	req := sd.resty.R().SetContext(ctx).SetHeader("Content-Type", "application/json")
+	if username, password, ok := strings.Cut(sd.auth, ":"); ok {
+		req.SetBasicAuth(username, password)
+	}
+
+	return req
+}
+
+type TextToImageRequest struct {
+	Prompt         string  `json:"prompt"`
+	NegativePrompt string  `json:"negative_prompt,omitempty"`
+	Width          int64   `json:"width,omitempty"`
+	Height         int64   `json:"height,omitempty"`
+	Steps          int64   `json:"steps,omitempty"`
+	Seed           int64   `json:"seed,omitempty"`
+	CfgScale       float64 `json:"cfg_scale,omitempty"`
+	SamplerName    string  `json:"sampler_name,omitempty"`
+	// BatchSize 单批次生成的图片数量
+	BatchSize int64 `json:"batch_size,omitempty"`
+	// OverrideSettings 临时覆盖 WebUI 的设置，如 sd_model_checkpoint 指定使用的模型
+	OverrideSettings map[string]any `json:"override_settings,omitempty"`
+}
+
+type ImageToImageRequest struct {
+	TextToImageRequest
+	// InitImages 参考图片，Base64 编码
+	InitImages []string `json:"init_images"`
+	// DenoisingStrength 重绘幅度，取值 0-1，越大与参考图片差异越大
+	DenoisingStrength float64 `json:"denoising_strength,omitempty"`
+	// Mask 局部重绘蒙版，Base64 编码，白色区域为需要重绘的区域
+	Mask string `json:"mask,omitempty"`
+	// MaskBlur 蒙版边缘模糊的像素值
+	MaskBlur int `json:"mask_blur,omitempty"`
+	// InpaintingFill 蒙版区域的初始内容：0 填充，1 原图，2 潜空间噪声，3 潜空间数值零
+	InpaintingFill int `json:"inpainting_fill,omitempty"`
+	// InpaintFullRes 是否仅重绘蒙版区域（以全分辨率处理蒙版区域）
+	InpaintFullRes bool `json:"inpaint_full_res,omitempty"`
+}
+
+type ImageResponse struct {
+	// Images 生成的图片，Base64 编码
+	Images []string `json:"images,omitempty"`
+	// Info 生成信息，JSON 编码的字符串，包含实际使用的种子等参数
+	Info string `json:"info,omitempty"`
+}
+
+// Seeds 返回每张图片实际使用的种子，解析失败时返回 nil
+func (resp ImageResponse) Seeds() []int64 {
+	var info struct {
+		AllSeeds []int64 `json:"all_seeds"`
+	}
+	if err := json.Unmarshal([]byte(resp.Info), &info); err != nil {
+		return nil
+	}
+
+	return info.AllSeeds
+}
+
+// TextToImage 文生图
+func (sd *SDWebUI) TextToImage(ctx context.Context, req TextToImageRequest) (*ImageResponse, error) {
+	return sd.generate(ctx, "/sdapi/v1/txt2img", req)
+}
+
+// ImageToImage 图生图
+func (sd *SDWebUI) ImageToImage(ctx context.Context, req ImageToImageRequest) (*ImageResponse, error) {
+	return sd.generate(ctx, "/sdapi/v1/img2img", req)
+}
+
+func (sd *SDWebUI) generate(ctx context.Context, endpoint string, req any) (*ImageResponse, error) {
+	resp, err := sd.request(ctx).SetBody(req).Post(sd.server + endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.IsError() {
+		return nil, fmt.Errorf("generate image failed: %s", string(resp.Body()))
+	}
+
+	var ret ImageResponse
+	if err := json.Unmarshal(resp.Body(), &ret); err != nil {
+		return nil, err
+	}
+
+	return &ret, nil
+}
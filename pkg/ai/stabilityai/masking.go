@@ -0,0 +1,105 @@
+package stabilityai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+
+	"github.com/mylxsw/asteria/log"
+)
+
+type MaskingRequest struct {
+	// TextPrompt 提示语
+	TextPrompt string `json:"text_prompt,omitempty"`
+	// NegativePrompt 反向提示语
+	NegativePrompt string `json:"negative_prompt,omitempty"`
+	// InitImage 需要编辑的图片，宽度和高度必须为 64 的整数倍
+	InitImage []byte `json:"-"`
+	// MaskImage 蒙版图片，尺寸必须与 InitImage 一致，白色区域为需要重绘的区域
+	MaskImage   []byte `json:"-"`
+	CfgScale    int    `json:"cfg_scale,omitempty"`
+	Samples     int    `json:"samples,omitempty"`
+	Seed        int    `json:"seed,omitempty"`
+	Steps       int    `json:"steps,omitempty"`
+	StylePreset string `json:"style_preset,omitempty"`
+}
+
+// Masking 使用蒙版局部重绘图片 https://platform.stability.ai/docs/api-reference#tag/v1generation/operation/masking
+func (ai *StabilityAI) Masking(ctx context.Context, model string, param MaskingRequest) (*TextToImageResponse, error) {
+	data := &bytes.Buffer{}
+	writer := multipart.NewWriter(data)
+
+	initImageWriter, _ := writer.CreateFormFile("init_image", "init_image.png")
+	_, _ = initImageWriter.Write(param.InitImage)
+
+	maskImageWriter, _ := writer.CreateFormFile("mask_image", "mask_image.png")
+	_, _ = maskImageWriter.Write(param.MaskImage)
+
+	_ = writer.WriteField("mask_source", "MASK_IMAGE_WHITE")
+	_ = writer.WriteField("text_prompts[0][text]", param.TextPrompt)
+	_ = writer.WriteField("text_prompts[0][weight]", "1")
+	if param.NegativePrompt != "" {
+		_ = writer.WriteField("text_prompts[1][text]", param.NegativePrompt)
+		_ = writer.WriteField("text_prompts[1][weight]", "-1")
+	}
+
+	if param.CfgScale > 0 {
+		_ = writer.WriteField("cfg_scale", strconv.Itoa(param.CfgScale))
+	}
+	if param.Samples > 0 {
+		_ = writer.WriteField("samples", strconv.Itoa(param.Samples))
+	}
+	if param.Steps > 0 {
+		_ = writer.WriteField("steps", strconv.Itoa(param.Steps))
+	}
+	_ = writer.WriteField("seed", strconv.Itoa(param.Seed))
+	if param.StylePreset != "" {
+		_ = writer.WriteField("style_preset", param.StylePreset)
+	}
+
+	writer.Close()
+
+	req, _ := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/v1/generation/%s/image-to-image/masking", ai.conf.StabilityAIServer[0], model), bytes.NewReader(data.Bytes()))
+	req.Header.Add("Content-Type", writer.FormDataContentType())
+	req.Header.Add("Accept", "application/json")
+	req.Header.Add("Authorization", "Bearer "+ai.conf.StabilityAIKey)
+	if ai.conf.StabilityAIOrganization != "" {
+		req.Header.Add("Organization", ai.conf.StabilityAIOrganization)
+	}
+
+	resp, err := ai.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %v", err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		log.F(log.M{
+			"status_code": resp.StatusCode,
+			"status":      resp.Status,
+			"body":        string(respBody),
+		}).Errorf("stabilityai masking request failed")
+
+		var body map[string]interface{}
+		if err := json.Unmarshal(respBody, &body); err != nil {
+			return nil, errors.New(string(respBody))
+		}
+
+		return nil, fmt.Errorf("请求失败: %s", body["message"])
+	}
+
+	var body TextToImageResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode response body: %v", err)
+	}
+
+	return &body, nil
+}
@@ -142,6 +142,47 @@ func New[T InitRequest[T]](enableWs bool, enableCors bool, r *http.Request, w ht
 	return sw, &req, nil
 }
 
+// NewPublisher 创建一个只用于服务端向客户端推送消息的 StreamWriter，不读取客户端的请求
+func NewPublisher(enableWs bool, enableCors bool, r *http.Request, w http.ResponseWriter) (*StreamWriter, error) {
+	sw := &StreamWriter{
+		r:          r,
+		w:          w,
+		enableCors: enableCors,
+	}
+
+	if !enableWs {
+		return sw, nil
+	}
+
+	upgrader := websocket.Upgrader{
+		CheckOrigin: func(r *http.Request) bool {
+			return true
+		},
+	}
+
+	wsConn, err := upgrader.Upgrade(w, r, ternary.If(enableCors, corsHeaders, http.Header{}))
+	if err != nil {
+		sw.writeJSON(NewErrorResponse(fmt.Errorf("upgrade websocket failed: %v", err)), http.StatusInternalServerError)
+		return nil, err
+	}
+
+	sw.ws = wsConn
+
+	// 客户端发送的消息全部忽略，只用于检测连接是否已经关闭
+	go func() {
+		defer func() {
+			sw.handleClosed()
+		}()
+		for {
+			if _, _, err := wsConn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	return sw, nil
+}
+
 func (sw *StreamWriter) initSSE() {
 	if sw.ws != nil {
 		return
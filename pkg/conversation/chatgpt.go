@@ -0,0 +1,150 @@
+package conversation
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+)
+
+// chatGPTConversation ChatGPT 导出文件（conversations.json）中的会话
+type chatGPTConversation struct {
+	Title            string                 `json:"title"`
+	CreateTime       float64                `json:"create_time"`
+	CurrentNode      string                 `json:"current_node"`
+	DefaultModelSlug string                 `json:"default_model_slug"`
+	Mapping          map[string]chatGPTNode `json:"mapping"`
+}
+
+type chatGPTNode struct {
+	ID       string          `json:"id"`
+	Parent   string          `json:"parent"`
+	Children []string        `json:"children"`
+	Message  *chatGPTMessage `json:"message"`
+}
+
+type chatGPTMessage struct {
+	Author struct {
+		Role string `json:"role"`
+	} `json:"author"`
+	CreateTime float64 `json:"create_time"`
+	Content    struct {
+		ContentType string            `json:"content_type"`
+		Parts       []json.RawMessage `json:"parts"`
+	} `json:"content"`
+	Metadata struct {
+		ModelSlug string `json:"model_slug"`
+	} `json:"metadata"`
+}
+
+func (msg chatGPTMessage) text() string {
+	if msg.Content.ContentType != "text" && msg.Content.ContentType != "multimodal_text" {
+		return ""
+	}
+
+	// parts 中可能包含图片等非文本内容，这里只保留文本
+	texts := make([]string, 0, len(msg.Content.Parts))
+	for _, part := range msg.Content.Parts {
+		var text string
+		if err := json.Unmarshal(part, &text); err == nil && strings.TrimSpace(text) != "" {
+			texts = append(texts, text)
+		}
+	}
+
+	return strings.Join(texts, "\n\n")
+}
+
+func unixFloatToTime(ts float64) time.Time {
+	if ts <= 0 {
+		return time.Time{}
+	}
+
+	sec, frac := math.Modf(ts)
+	return time.Unix(int64(sec), int64(frac*1e9))
+}
+
+// ParseChatGPTExport 解析 ChatGPT 导出的 conversations.json 文件
+// 每个会话都是一颗消息树（用户编辑消息或者重新生成回复时会产生分支），这里只保留从根节点到 current_node 的分支
+func ParseChatGPTExport(r io.Reader) ([]Conversation, error) {
+	var items []chatGPTConversation
+	if err := json.NewDecoder(r).Decode(&items); err != nil {
+		return nil, fmt.Errorf("invalid chatgpt export file: %w", err)
+	}
+
+	convs := make([]Conversation, 0, len(items))
+	for _, item := range items {
+		conv := Conversation{
+			Type:      TypeChat,
+			Title:     strings.TrimSpace(item.Title),
+			Model:     item.DefaultModelSlug,
+			CreatedAt: unixFloatToTime(item.CreateTime),
+		}
+
+		// 从当前节点沿着 parent 向上回溯，得到当前分支上的所有消息
+		path := make([]*chatGPTMessage, 0)
+		visited := make(map[string]bool)
+		for nodeID := item.CurrentNode; nodeID != "" && !visited[nodeID]; {
+			visited[nodeID] = true
+
+			node, ok := item.Mapping[nodeID]
+			if !ok {
+				break
+			}
+
+			if node.Message != nil {
+				path = append(path, node.Message)
+			}
+
+			nodeID = node.Parent
+		}
+
+		for i := len(path) - 1; i >= 0; i-- {
+			msg := path[i]
+			if msg.Author.Role != RoleUser && msg.Author.Role != RoleAssistant {
+				continue
+			}
+
+			text := msg.text()
+			if text == "" {
+				continue
+			}
+
+			m := Message{
+				Role:      msg.Author.Role,
+				Content:   text,
+				CreatedAt: unixFloatToTime(msg.CreateTime),
+			}
+			if msg.Author.Role == RoleAssistant {
+				m.Model = msg.Metadata.ModelSlug
+			}
+
+			if m.CreatedAt.IsZero() {
+				m.CreatedAt = conv.CreatedAt
+			}
+
+			if m.Model != "" && conv.Model == "" {
+				conv.Model = m.Model
+			}
+
+			conv.Messages = append(conv.Messages, m)
+		}
+
+		if len(conv.Messages) == 0 {
+			continue
+		}
+
+		if conv.Title == "" {
+			conv.Title = "ChatGPT"
+		}
+
+		if conv.CreatedAt.IsZero() {
+			conv.CreatedAt = conv.Messages[0].CreatedAt
+		}
+
+		convs = append(convs, conv)
+	}
+
+	return convs, nil
+}
@@ -0,0 +1,213 @@
+package conversation
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"strings"
+	"time"
+)
+
+const (
+	FormatMarkdown = "markdown"
+	FormatJSON     = "json"
+	FormatHTML     = "html"
+)
+
+const (
+	TypeChat      = "chat"
+	TypeGroupChat = "group_chat"
+)
+
+const (
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+)
+
+// Conversation 一个完整的会话（数字人或者群聊）
+type Conversation struct {
+	ID           int64     `json:"id,omitempty"`
+	Type         string    `json:"type"`
+	Title        string    `json:"title"`
+	Model        string    `json:"model,omitempty"`
+	SystemPrompt string    `json:"system_prompt,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	Messages     []Message `json:"messages"`
+}
+
+// Message 会话中的一条消息
+type Message struct {
+	Role      string    `json:"role"`
+	Author    string    `json:"author,omitempty"`
+	Model     string    `json:"model,omitempty"`
+	Content   string    `json:"content"`
+	Images    []string  `json:"images,omitempty"`
+	File      string    `json:"file,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Export 导出文件的内容
+type Export struct {
+	ExportedAt    time.Time      `json:"exported_at"`
+	Conversations []Conversation `json:"conversations"`
+}
+
+// ValidFormat 判断导出格式是否支持
+func ValidFormat(format string) bool {
+	return format == FormatMarkdown || format == FormatJSON || format == FormatHTML
+}
+
+// ContentType 返回导出格式对应的 Content-Type 以及文件扩展名
+func ContentType(format string) (string, string) {
+	switch format {
+	case FormatJSON:
+		return "application/json; charset=utf-8", "json"
+	case FormatHTML:
+		return "text/html; charset=utf-8", "html"
+	default:
+		return "text/markdown; charset=utf-8", "md"
+	}
+}
+
+// Render 将会话渲染为指定的格式
+func Render(format string, exp Export) ([]byte, error) {
+	switch format {
+	case FormatJSON:
+		return json.MarshalIndent(exp, "", "  ")
+	case FormatHTML:
+		return RenderHTML(exp)
+	case FormatMarkdown:
+		return RenderMarkdown(exp), nil
+	default:
+		return nil, fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+func (msg Message) speaker() string {
+	if msg.Role == RoleUser {
+		return "用户"
+	}
+
+	if msg.Author != "" {
+		return msg.Author
+	}
+
+	if msg.Model != "" {
+		return msg.Model
+	}
+
+	return "AI"
+}
+
+// RenderMarkdown 将会话渲染为 Markdown 格式
+func RenderMarkdown(exp Export) []byte {
+	var buf bytes.Buffer
+	for i, conv := range exp.Conversations {
+		if i > 0 {
+			buf.WriteString("\n---\n\n")
+		}
+
+		buf.WriteString(fmt.Sprintf("# %s\n\n", conv.Title))
+		if conv.Model != "" {
+			buf.WriteString(fmt.Sprintf("- 模型：%s\n", conv.Model))
+		}
+		buf.WriteString(fmt.Sprintf("- 创建时间：%s\n\n", conv.CreatedAt.Format(time.DateTime)))
+
+		if conv.SystemPrompt != "" {
+			buf.WriteString("> " + strings.ReplaceAll(conv.SystemPrompt, "\n", "\n> ") + "\n\n")
+		}
+
+		for _, msg := range conv.Messages {
+			buf.WriteString(fmt.Sprintf("### %s · %s\n\n", msg.speaker(), msg.CreatedAt.Format(time.DateTime)))
+			buf.WriteString(msg.Content)
+			buf.WriteString("\n\n")
+
+			for _, img := range msg.Images {
+				buf.WriteString(fmt.Sprintf("![image](%s)\n\n", img))
+			}
+
+			if msg.File != "" {
+				buf.WriteString(fmt.Sprintf("[附件](%s)\n\n", msg.File))
+			}
+		}
+	}
+
+	return buf.Bytes()
+}
+
+var htmlTemplate = template.Must(template.New("export").Funcs(template.FuncMap{
+	"datetime": func(t time.Time) string { return t.Format(time.DateTime) },
+}).Parse(`<!DOCTYPE html>
+<html lang="zh-CN">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>AIdea 聊天记录</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", "PingFang SC", "Microsoft YaHei", sans-serif; max-width: 860px; margin: 0 auto; padding: 24px; background: #f5f5f5; color: #333; }
+h1 { font-size: 22px; margin: 32px 0 8px; }
+.meta { color: #888; font-size: 13px; margin-bottom: 16px; }
+.prompt { border-left: 4px solid #ccc; padding: 8px 12px; color: #666; white-space: pre-wrap; background: #fff; }
+.message { margin: 12px 0; padding: 12px 16px; border-radius: 8px; background: #fff; }
+.message.user { background: #e8f4ff; }
+.speaker { font-weight: bold; font-size: 13px; margin-bottom: 6px; }
+.speaker span { font-weight: normal; color: #999; margin-left: 8px; }
+.content { white-space: pre-wrap; word-break: break-word; line-height: 1.6; }
+.message img { max-width: 100%; border-radius: 4px; margin-top: 8px; display: block; }
+hr { border: none; border-top: 1px solid #ddd; margin: 32px 0; }
+</style>
+</head>
+<body>
+{{ range $i, $conv := .Conversations }}
+{{ if $i }}<hr>{{ end }}
+<h1>{{ $conv.Title }}</h1>
+<div class="meta">{{ if $conv.Model }}{{ $conv.Model }} · {{ end }}{{ datetime $conv.CreatedAt }}</div>
+{{ if $conv.SystemPrompt }}<div class="prompt">{{ $conv.SystemPrompt }}</div>{{ end }}
+{{ range $conv.Messages }}
+<div class="message {{ .Role }}">
+<div class="speaker">{{ .Speaker }}<span>{{ datetime .CreatedAt }}</span></div>
+<div class="content">{{ .Content }}</div>
+{{ range .Images }}<img src="{{ . }}" alt="image">{{ end }}
+{{ if .File }}<a href="{{ .File }}">附件</a>{{ end }}
+</div>
+{{ end }}
+{{ end }}
+<div class="meta">导出时间：{{ datetime .ExportedAt }}</div>
+</body>
+</html>
+`))
+
+type htmlMessage struct {
+	Message
+	Speaker string
+}
+
+type htmlConversation struct {
+	Conversation
+	Messages []htmlMessage
+}
+
+// RenderHTML 将会话渲染为不依赖外部资源的 HTML 页面
+func RenderHTML(exp Export) ([]byte, error) {
+	data := struct {
+		ExportedAt    time.Time
+		Conversations []htmlConversation
+	}{ExportedAt: exp.ExportedAt}
+
+	for _, conv := range exp.Conversations {
+		hc := htmlConversation{Conversation: conv}
+		for _, msg := range conv.Messages {
+			hc.Messages = append(hc.Messages, htmlMessage{Message: msg, Speaker: msg.speaker()})
+		}
+
+		data.Conversations = append(data.Conversations, hc)
+	}
+
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,88 @@
+package conversation_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mylxsw/aidea-server/pkg/conversation"
+	"github.com/mylxsw/go-utils/assert"
+)
+
+func testExport() conversation.Export {
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.Local)
+	return conversation.Export{
+		ExportedAt: createdAt,
+		Conversations: []conversation.Conversation{
+			{
+				Type:         conversation.TypeChat,
+				Title:        "Go 并发",
+				Model:        "gpt-4o",
+				SystemPrompt: "你是一名 Go 专家",
+				CreatedAt:    createdAt,
+				Messages: []conversation.Message{
+					{Role: conversation.RoleUser, Content: "<b>channel</b> 怎么关闭？", CreatedAt: createdAt},
+					{Role: conversation.RoleAssistant, Model: "gpt-4o", Content: "使用 close 函数", Images: []string{"https://example.com/a.png"}, CreatedAt: createdAt},
+				},
+			},
+		},
+	}
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	data := string(conversation.RenderMarkdown(testExport()))
+
+	assert.True(t, strings.Contains(data, "# Go 并发"))
+	assert.True(t, strings.Contains(data, "> 你是一名 Go 专家"))
+	assert.True(t, strings.Contains(data, "### 用户 · 2024-01-02 03:04:05"))
+	assert.True(t, strings.Contains(data, "### gpt-4o · 2024-01-02 03:04:05"))
+	assert.True(t, strings.Contains(data, "![image](https://example.com/a.png)"))
+}
+
+func TestRenderHTML(t *testing.T) {
+	data, err := conversation.RenderHTML(testExport())
+	assert.NoError(t, err)
+
+	html := string(data)
+	assert.True(t, strings.Contains(html, "&lt;b&gt;channel&lt;/b&gt;"))
+	assert.False(t, strings.Contains(html, "<b>channel</b>"))
+	assert.True(t, strings.Contains(html, `<img src="https://example.com/a.png"`))
+}
+
+func TestParseChatGPTExport(t *testing.T) {
+	data := `[{
+		"title": "Hello",
+		"create_time": 1700000000.5,
+		"current_node": "c",
+		"default_model_slug": "gpt-4",
+		"mapping": {
+			"root": {"id": "root", "parent": "", "children": ["s"], "message": null},
+			"s": {"id": "s", "parent": "root", "children": ["a"], "message": {"author": {"role": "system"}, "content": {"content_type": "text", "parts": ["system"]}}},
+			"a": {"id": "a", "parent": "s", "children": ["b", "b2"], "message": {"author": {"role": "user"}, "create_time": 1700000001, "content": {"content_type": "text", "parts": ["hi"]}}},
+			"b2": {"id": "b2", "parent": "a", "children": [], "message": {"author": {"role": "assistant"}, "content": {"content_type": "text", "parts": ["discarded"]}}},
+			"b": {"id": "b", "parent": "a", "children": ["c"], "message": {"author": {"role": "assistant"}, "create_time": 1700000002, "content": {"content_type": "text", "parts": ["hello"]}, "metadata": {"model_slug": "gpt-4o"}}},
+			"c": {"id": "c", "parent": "b", "children": [], "message": {"author": {"role": "user"}, "create_time": 1700000003, "content": {"content_type": "multimodal_text", "parts": [{"asset_pointer": "file-service://x"}, "look"]}}}
+		}
+	}, {
+		"title": "Empty",
+		"current_node": "x",
+		"mapping": {"x": {"id": "x", "parent": "", "message": null}}
+	}]`
+
+	convs, err := conversation.ParseChatGPTExport(strings.NewReader(data))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(convs))
+
+	conv := convs[0]
+	assert.Equal(t, "Hello", conv.Title)
+	assert.Equal(t, "gpt-4", conv.Model)
+	assert.Equal(t, 3, len(conv.Messages))
+	assert.Equal(t, "hi", conv.Messages[0].Content)
+	assert.Equal(t, "hello", conv.Messages[1].Content)
+	assert.Equal(t, "gpt-4o", conv.Messages[1].Model)
+	assert.Equal(t, "look", conv.Messages[2].Content)
+	assert.Equal(t, int64(1700000002), conv.Messages[1].CreatedAt.Unix())
+
+	_, err = conversation.ParseChatGPTExport(strings.NewReader("{}"))
+	assert.True(t, err != nil)
+}
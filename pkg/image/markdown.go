@@ -0,0 +1,129 @@
+package image
+
+import (
+	"regexp"
+	"strings"
+)
+
+type blockKind int
+
+const (
+	blockParagraph blockKind = iota
+	blockHeading
+	blockListItem
+	blockQuote
+	blockCode
+	blockRule
+)
+
+// block 海报中渲染的 Markdown 块，只支持常用的块级元素，行内格式会被去掉
+type block struct {
+	kind blockKind
+	text string
+	// level 标题级别
+	level int
+	// marker 列表项的标记，如 • 或者 1.
+	marker string
+	// indent 列表项的缩进级别
+	indent int
+}
+
+var (
+	headingRegexp     = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	unorderedRegexp   = regexp.MustCompile(`^(\s*)[-*+]\s+(.*)$`)
+	orderedRegexp     = regexp.MustCompile(`^(\s*)(\d+[.)])\s+(.*)$`)
+	ruleRegexp        = regexp.MustCompile(`^\s*([-*_])(\s*[-*_]){2,}\s*$`)
+	imageRegexp       = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	linkRegexp        = regexp.MustCompile(`\[([^\]]+)\]\([^)]*\)`)
+	inlineCodeRegexp  = regexp.MustCompile("`([^`]+)`")
+	emphasisRegexp    = regexp.MustCompile(`(\*\*|__|~~)(.+?)(\*\*|__|~~)`)
+	singleEmphRegexp  = regexp.MustCompile(`(^|[^\w*])\*([^*\s][^*]*?)\*`)
+	multiSpacesRegexp = regexp.MustCompile(`[ \t]+`)
+)
+
+// parseMarkdown 将 Markdown 文本解析为块
+func parseMarkdown(text string) []block {
+	blocks := make([]block, 0)
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+
+	var paragraph []string
+	flush := func() {
+		if len(paragraph) > 0 {
+			blocks = append(blocks, block{kind: blockParagraph, text: strings.Join(paragraph, "\n")})
+			paragraph = nil
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			flush()
+
+			fence := trimmed[:3]
+			code := make([]string, 0)
+			for i++; i < len(lines); i++ {
+				if strings.HasPrefix(strings.TrimSpace(lines[i]), fence) {
+					break
+				}
+
+				code = append(code, strings.ReplaceAll(lines[i], "\t", "    "))
+			}
+
+			blocks = append(blocks, block{kind: blockCode, text: strings.Join(code, "\n")})
+			continue
+		}
+
+		if trimmed == "" {
+			flush()
+			continue
+		}
+
+		if ruleRegexp.MatchString(trimmed) {
+			flush()
+			blocks = append(blocks, block{kind: blockRule})
+			continue
+		}
+
+		if m := headingRegexp.FindStringSubmatch(trimmed); m != nil {
+			flush()
+			blocks = append(blocks, block{kind: blockHeading, level: len(m[1]), text: stripInline(strings.TrimRight(m[2], " #"))})
+			continue
+		}
+
+		if m := unorderedRegexp.FindStringSubmatch(line); m != nil {
+			flush()
+			blocks = append(blocks, block{kind: blockListItem, marker: "•", indent: len(m[1]) / 2, text: stripInline(m[2])})
+			continue
+		}
+
+		if m := orderedRegexp.FindStringSubmatch(line); m != nil {
+			flush()
+			blocks = append(blocks, block{kind: blockListItem, marker: m[2], indent: len(m[1]) / 2, text: stripInline(m[3])})
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, ">") {
+			flush()
+			blocks = append(blocks, block{kind: blockQuote, text: stripInline(strings.TrimSpace(strings.TrimLeft(trimmed, "> ")))})
+			continue
+		}
+
+		paragraph = append(paragraph, stripInline(trimmed))
+	}
+
+	flush()
+	return blocks
+}
+
+// stripInline 去掉行内的 Markdown 格式标记，只保留文本
+func stripInline(text string) string {
+	text = imageRegexp.ReplaceAllString(text, "[$1]")
+	text = linkRegexp.ReplaceAllString(text, "$1")
+	text = inlineCodeRegexp.ReplaceAllString(text, "$1")
+	text = emphasisRegexp.ReplaceAllString(text, "$2")
+	text = singleEmphRegexp.ReplaceAllString(text, "$1$2")
+
+	return strings.TrimSpace(multiSpacesRegexp.ReplaceAllString(text, " "))
+}
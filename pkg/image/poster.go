@@ -0,0 +1,532 @@
+package image
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	goimage "image"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/fogleman/gg"
+	"github.com/mylxsw/go-utils/ternary"
+	"github.com/skip2/go-qrcode"
+	"golang.org/x/image/font"
+)
+
+const (
+	posterWidth       = 1080
+	posterPadding     = 56
+	posterCardPadding = 36
+	posterAvatarSize  = 72
+	posterGap         = 28
+	posterQRSize      = 180
+
+	// PosterMaxHeight 海报的最大高度，超出时返回 ErrPosterTooLarge，避免生成过大的图片
+	PosterMaxHeight = 30000
+)
+
+var ErrPosterTooLarge = errors.New("poster is too large")
+
+// PosterTheme 分享海报主题，颜色均为十六进制格式
+type PosterTheme struct {
+	Background     string `json:"background"`
+	Card           string `json:"card"`
+	UserCard       string `json:"user_card"`
+	Text           string `json:"text"`
+	SubText        string `json:"sub_text"`
+	Accent         string `json:"accent"`
+	CodeBackground string `json:"code_background"`
+	CodeText       string `json:"code_text"`
+}
+
+const DefaultPosterTheme = "light"
+
+// PosterThemes 内置的海报主题
+var PosterThemes = map[string]PosterTheme{
+	"light": {
+		Background:     "#F3F4F6",
+		Card:           "#FFFFFF",
+		UserCard:       "#EEF2FF",
+		Text:           "#1F2937",
+		SubText:        "#6B7280",
+		Accent:         "#4F46E5",
+		CodeBackground: "#1F2937",
+		CodeText:       "#E5E7EB",
+	},
+	"dark": {
+		Background:     "#0F172A",
+		Card:           "#1E293B",
+		UserCard:       "#312E81",
+		Text:           "#F1F5F9",
+		SubText:        "#94A3B8",
+		Accent:         "#818CF8",
+		CodeBackground: "#020617",
+		CodeText:       "#CBD5E1",
+	},
+	"warm": {
+		Background:     "#FBF1DD",
+		Card:           "#FFFBF2",
+		UserCard:       "#F6E7C8",
+		Text:           "#3F2E1E",
+		SubText:        "#8B7355",
+		Accent:         "#C2410C",
+		CodeBackground: "#3F2E1E",
+		CodeText:       "#FBF1DD",
+	},
+}
+
+// PosterMessage 海报中的一条消息
+type PosterMessage struct {
+	// Name 发言者名称，用户名或者模型名称
+	Name string
+	// Avatar 发言者头像，为空时使用名称的首字母代替
+	Avatar goimage.Image
+	IsUser bool
+	// Content 消息内容，支持 Markdown 格式
+	Content string
+}
+
+// Poster 分享海报
+type Poster struct {
+	Title    string
+	Subtitle string
+	Messages []PosterMessage
+	// QRLink 二维码链接，为空时不显示二维码
+	QRLink string
+	// Footer 显示在二维码旁边的说明文字
+	Footer []string
+	// Theme 主题名称，见 PosterThemes
+	Theme string
+}
+
+// Poster 将对话渲染为长图海报（PNG）
+func (builder *Imager) Poster(poster Poster) ([]byte, error) {
+	theme, ok := PosterThemes[poster.Theme]
+	if !ok {
+		theme = PosterThemes[DefaultPosterTheme]
+	}
+
+	var qr goimage.Image
+	if poster.QRLink != "" {
+		code, err := qrcode.New(poster.QRLink, qrcode.Medium)
+		if err != nil {
+			return nil, fmt.Errorf("生成二维码失败: %w", err)
+		}
+
+		code.DisableBorder = true
+		qr = code.Image(posterQRSize)
+	}
+
+	// 第一遍只计算高度，第二遍绘制
+	r := &posterRenderer{fontPath: builder.fontPath, theme: theme, faces: make(map[float64]font.Face), qr: qr}
+	r.dc = gg.NewContext(posterWidth, 1)
+	if err := r.render(poster); err != nil {
+		return nil, err
+	}
+
+	height := int(r.y)
+	if height > PosterMaxHeight {
+		return nil, ErrPosterTooLarge
+	}
+
+	r.dc, r.draw, r.y = gg.NewContext(posterWidth, height), true, 0
+	r.dc.SetHexColor(theme.Background)
+	r.dc.Clear()
+
+	if err := r.render(poster); err != nil {
+		return nil, err
+	}
+
+	buf := bytes.NewBuffer(nil)
+	if err := r.dc.EncodePNG(buf); err != nil {
+		return nil, fmt.Errorf("编码 PNG 数据失败: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+type posterRenderer struct {
+	fontPath string
+	theme    PosterTheme
+	faces    map[float64]font.Face
+	qr       goimage.Image
+
+	dc   *gg.Context
+	draw bool
+	y    float64
+}
+
+func (r *posterRenderer) render(poster Poster) error {
+	r.y = posterPadding
+	contentWidth := float64(posterWidth - 2*posterPadding)
+
+	if poster.Title != "" {
+		if err := r.text(poster.Title, 44, r.theme.Text, posterPadding, contentWidth); err != nil {
+			return err
+		}
+	}
+
+	if poster.Subtitle != "" {
+		if err := r.text(poster.Subtitle, 26, r.theme.SubText, posterPadding, contentWidth); err != nil {
+			return err
+		}
+	}
+
+	r.y += posterGap
+
+	for _, msg := range poster.Messages {
+		if err := r.message(msg); err != nil {
+			return err
+		}
+
+		r.y += posterGap
+	}
+
+	if err := r.footer(poster); err != nil {
+		return err
+	}
+
+	r.y += posterPadding
+	return nil
+}
+
+// measure 只计算 fn 绘制内容的高度，不进行绘制
+func (r *posterRenderer) measure(fn func() error) (float64, error) {
+	draw, y := r.draw, r.y
+	r.draw = false
+	defer func() { r.draw, r.y = draw, y }()
+
+	if err := fn(); err != nil {
+		return 0, err
+	}
+
+	return r.y - y, nil
+}
+
+func (r *posterRenderer) message(msg PosterMessage) error {
+	x := float64(posterPadding + posterCardPadding)
+	width := float64(posterWidth - 2*posterPadding - 2*posterCardPadding)
+	content := func() error {
+		if err := r.avatar(msg, x); err != nil {
+			return err
+		}
+
+		return r.blocks(parseMarkdown(msg.Content), x, width)
+	}
+
+	height, err := r.measure(content)
+	if err != nil {
+		return err
+	}
+
+	if r.draw {
+		r.dc.SetHexColor(ternary.If(msg.IsUser, r.theme.UserCard, r.theme.Card))
+		r.dc.DrawRoundedRectangle(posterPadding, r.y, float64(posterWidth-2*posterPadding), height+2*posterCardPadding, 24)
+		r.dc.Fill()
+	}
+
+	r.y += posterCardPadding
+	if err := content(); err != nil {
+		return err
+	}
+
+	r.y += posterCardPadding
+	return nil
+}
+
+func (r *posterRenderer) avatar(msg PosterMessage, x float64) error {
+	const size = posterAvatarSize
+	cx, cy := x+size/2, r.y+size/2
+
+	if r.draw {
+		if msg.Avatar != nil {
+			bounds := msg.Avatar.Bounds()
+			side := min(bounds.Dx(), bounds.Dy())
+			scale := float64(size) / float64(side)
+
+			r.dc.Push()
+			r.dc.DrawCircle(cx, cy, size/2)
+			r.dc.Clip()
+			r.dc.Translate(x, r.y)
+			r.dc.Scale(scale, scale)
+			r.dc.DrawImage(msg.Avatar, -bounds.Min.X-(bounds.Dx()-side)/2, -bounds.Min.Y-(bounds.Dy()-side)/2)
+			r.dc.ResetClip()
+			r.dc.Pop()
+		} else {
+			r.dc.SetHexColor(r.theme.Accent)
+			r.dc.DrawCircle(cx, cy, size/2)
+			r.dc.Fill()
+
+			if err := r.setFont(32); err != nil {
+				return err
+			}
+
+			initial, _ := utf8.DecodeRuneInString(strings.TrimSpace(msg.Name))
+			if initial != utf8.RuneError {
+				r.dc.SetHexColor("#FFFFFF")
+				r.dc.DrawStringAnchored(strings.ToUpper(string(initial)), cx, cy, 0.5, 0.35)
+			}
+		}
+
+		if err := r.setFont(28); err != nil {
+			return err
+		}
+
+		r.dc.SetHexColor(r.theme.SubText)
+		r.dc.DrawStringAnchored(msg.Name, x+size+20, cy, 0, 0.35)
+	}
+
+	r.y += size + 20
+	return nil
+}
+
+func (r *posterRenderer) blocks(blocks []block, x, width float64) error {
+	for _, b := range blocks {
+		var err error
+		switch b.kind {
+		case blockHeading:
+			r.y += 8
+			err = r.text(b.text, float64(max(30, 44-4*b.level)), r.theme.Text, x, width)
+		case blockListItem:
+			indent := x + float64(b.indent*32)
+			if r.draw {
+				if err := r.setFont(30); err != nil {
+					return err
+				}
+
+				r.dc.SetHexColor(r.theme.Accent)
+				r.dc.DrawString(b.marker, indent, r.baseline())
+			}
+			err = r.text(b.text, 30, r.theme.Text, indent+44, width-(indent-x)-44)
+		case blockQuote:
+			var height float64
+			height, err = r.measure(func() error { return r.text(b.text, 30, r.theme.SubText, x+28, width-28) })
+			if err == nil && r.draw {
+				r.dc.SetHexColor(r.theme.Accent)
+				r.dc.DrawRectangle(x, r.y, 6, height)
+				r.dc.Fill()
+			}
+			if err == nil {
+				err = r.text(b.text, 30, r.theme.SubText, x+28, width-28)
+			}
+		case blockCode:
+			err = r.code(b.text, x, width)
+		case blockRule:
+			if r.draw {
+				r.dc.SetHexColor(r.theme.SubText)
+				r.dc.SetLineWidth(2)
+				r.dc.DrawLine(x, r.y+14, x+width, r.y+14)
+				r.dc.Stroke()
+			}
+			r.y += 28
+		default:
+			err = r.text(b.text, 30, r.theme.Text, x, width)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		r.y += 12
+	}
+
+	return nil
+}
+
+func (r *posterRenderer) code(text string, x, width float64) error {
+	const padding = 24
+	if err := r.setFont(24); err != nil {
+		return err
+	}
+
+	lines := make([]string, 0)
+	for _, line := range strings.Split(text, "\n") {
+		lines = append(lines, r.wrapRunes(line, width-2*padding)...)
+	}
+
+	lineHeight := r.lineHeight()
+	height := float64(len(lines))*lineHeight + 2*padding
+
+	if r.draw {
+		r.dc.SetHexColor(r.theme.CodeBackground)
+		r.dc.DrawRoundedRectangle(x, r.y, width, height, 16)
+		r.dc.Fill()
+
+		r.dc.SetHexColor(r.theme.CodeText)
+		y := r.y
+		r.y += padding
+		for _, line := range lines {
+			r.dc.DrawString(line, x+padding, r.baseline())
+			r.y += lineHeight
+		}
+		r.y = y
+	}
+
+	r.y += height
+	return nil
+}
+
+func (r *posterRenderer) footer(poster Poster) error {
+	if r.qr == nil && len(poster.Footer) == 0 {
+		return nil
+	}
+
+	if r.draw {
+		r.dc.SetHexColor(r.theme.SubText)
+		r.dc.SetLineWidth(1)
+		r.dc.DrawLine(posterPadding, r.y, posterWidth-posterPadding, r.y)
+		r.dc.Stroke()
+	}
+
+	r.y += posterGap
+	top := r.y
+
+	textWidth := float64(posterWidth - 2*posterPadding)
+	if r.qr != nil {
+		textWidth -= posterQRSize + 48
+	}
+
+	for i, line := range poster.Footer {
+		color := ternary.If(i == 0, r.theme.Text, r.theme.SubText)
+		if err := r.text(line, ternary.If(i == 0, 30.0, 24.0), color, posterPadding, textWidth); err != nil {
+			return err
+		}
+	}
+
+	if r.qr != nil {
+		qrX := float64(posterWidth - posterPadding - posterQRSize - 12)
+		if r.draw {
+			r.dc.SetHexColor("#FFFFFF")
+			r.dc.DrawRoundedRectangle(qrX-12, top, posterQRSize+24, posterQRSize+24, 12)
+			r.dc.Fill()
+			r.dc.DrawImage(r.qr, int(qrX), int(top)+12)
+		}
+
+		r.y = max(r.y, top+posterQRSize+24)
+	}
+
+	return nil
+}
+
+// text 绘制自动换行的文本
+func (r *posterRenderer) text(text string, size float64, color string, x, width float64) error {
+	if err := r.setFont(size); err != nil {
+		return err
+	}
+
+	lineHeight := r.lineHeight()
+	if r.draw {
+		r.dc.SetHexColor(color)
+	}
+
+	for _, line := range r.wrap(text, width) {
+		if r.draw {
+			r.dc.DrawString(line, x, r.baseline())
+		}
+
+		r.y += lineHeight
+	}
+
+	return nil
+}
+
+func (r *posterRenderer) lineHeight() float64 {
+	return r.dc.FontHeight() * 1.6
+}
+
+// baseline 当前行文字的基线位置，使文字在行内垂直居中
+func (r *posterRenderer) baseline() float64 {
+	return r.y + (r.lineHeight()+r.dc.FontHeight())/2 - r.dc.FontHeight()*0.15
+}
+
+func (r *posterRenderer) setFont(size float64) error {
+	// 没有指定字体时使用 gg 内置的字体，只支持 ASCII 字符
+	if r.fontPath == "" {
+		return nil
+	}
+
+	face, ok := r.faces[size]
+	if !ok {
+		var err error
+		if face, err = gg.LoadFontFace(r.fontPath, size); err != nil {
+			return fmt.Errorf("加载字体文件失败: %w", err)
+		}
+
+		r.faces[size] = face
+	}
+
+	r.dc.SetFontFace(face)
+	return nil
+}
+
+// wrap 按照宽度对文本进行换行，中日韩文字可以在任意字符处换行，其它文字在空白处换行
+func (r *posterRenderer) wrap(text string, width float64) []string {
+	lines := make([]string, 0)
+	for _, paragraph := range strings.Split(text, "\n") {
+		var line string
+		for _, token := range wrapTokens(paragraph) {
+			if w, _ := r.dc.MeasureString(line + token); w <= width || line == "" {
+				line += token
+				continue
+			}
+
+			// 单个单词超出宽度时（比如很长的链接），按照字符截断
+			lines = append(lines, r.wrapRunes(strings.TrimRight(line, " "), width)...)
+			line = strings.TrimLeft(token, " ")
+		}
+
+		lines = append(lines, r.wrapRunes(strings.TrimRight(line, " "), width)...)
+	}
+
+	return lines
+}
+
+// wrapRunes 按照字符对文本进行换行，不考虑单词边界，用于代码块
+func (r *posterRenderer) wrapRunes(text string, width float64) []string {
+	if w, _ := r.dc.MeasureString(text); w <= width {
+		return []string{text}
+	}
+
+	lines := make([]string, 0)
+	var line []rune
+	for _, c := range text {
+		if w, _ := r.dc.MeasureString(string(append(line, c))); w > width && len(line) > 0 {
+			lines = append(lines, string(line))
+			line = line[:0]
+		}
+
+		line = append(line, c)
+	}
+
+	return append(lines, string(line))
+}
+
+// wrapTokens 将文本拆分为换行的最小单元：单词、空白以及单个中日韩字符
+func wrapTokens(text string) []string {
+	tokens := make([]string, 0)
+	var word []rune
+	flush := func() {
+		if len(word) > 0 {
+			tokens = append(tokens, string(word))
+			word = word[:0]
+		}
+	}
+
+	for _, c := range text {
+		switch {
+		case unicode.IsSpace(c):
+			flush()
+			tokens = append(tokens, " ")
+		case unicode.In(c, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) || (c >= 0x3000 && c <= 0x303F) || (c >= 0xFF00 && c <= 0xFFEF):
+			flush()
+			tokens = append(tokens, string(c))
+		default:
+			word = append(word, c)
+		}
+	}
+
+	flush()
+	return tokens
+}
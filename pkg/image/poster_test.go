@@ -0,0 +1,57 @@
+package image
+
+import (
+	"bytes"
+	"image/png"
+	"testing"
+
+	"github.com/mylxsw/go-utils/assert"
+)
+
+func TestParseMarkdown(t *testing.T) {
+	blocks := parseMarkdown("# Title\n\nHello **world**, see [docs](https://example.com).\nNext line\n\n- item `one`\n  1. nested\n> quote\n\n---\n\n```go\nfunc main() {\n\tprintln(1)\n}\n```")
+
+	assert.Equal(t, 7, len(blocks))
+	assert.Equal(t, block{kind: blockHeading, level: 1, text: "Title"}, blocks[0])
+	assert.Equal(t, block{kind: blockParagraph, text: "Hello world, see docs.\nNext line"}, blocks[1])
+	assert.Equal(t, block{kind: blockListItem, marker: "•", text: "item one"}, blocks[2])
+	assert.Equal(t, block{kind: blockListItem, marker: "1.", indent: 1, text: "nested"}, blocks[3])
+	assert.Equal(t, block{kind: blockQuote, text: "quote"}, blocks[4])
+	assert.Equal(t, blockRule, blocks[5].kind)
+	assert.Equal(t, block{kind: blockCode, text: "func main() {\n    println(1)\n}"}, blocks[6])
+}
+
+func TestWrapTokens(t *testing.T) {
+	assert.Equal(t, []string{"hello", " ", "你", "好", "，", "world"}, wrapTokens("hello 你好，world"))
+}
+
+func TestImager_Poster(t *testing.T) {
+	imager := New("../../resources/fonts/FangZhengHeiTiJianTi-1.ttf")
+
+	short, err := imager.Poster(Poster{
+		Title:    "分享对话",
+		Messages: []PosterMessage{{Name: "User", IsUser: true, Content: "你好"}},
+		QRLink:   "https://example.com/s/abc",
+		Footer:   []string{"扫码查看完整对话"},
+	})
+	assert.NoError(t, err)
+
+	long, err := imager.Poster(Poster{
+		Title: "分享对话",
+		Messages: []PosterMessage{
+			{Name: "User", IsUser: true, Content: "写一个 Go 的 Hello World"},
+			{Name: "GPT-4o", Content: "## 示例\n\n```go\npackage main\n\nfunc main() {\n\tprintln(\"Hello World\")\n}\n```\n\n- 保存为 `main.go`\n- 运行 `go run main.go`"},
+		},
+		QRLink: "https://example.com/s/abc",
+		Theme:  "dark",
+	})
+	assert.NoError(t, err)
+
+	shortImg, err := png.Decode(bytes.NewReader(short))
+	assert.NoError(t, err)
+	longImg, err := png.Decode(bytes.NewReader(long))
+	assert.NoError(t, err)
+
+	assert.Equal(t, posterWidth, shortImg.Bounds().Dx())
+	assert.True(t, longImg.Bounds().Dy() > shortImg.Bounds().Dy())
+}
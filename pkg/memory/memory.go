@@ -0,0 +1,205 @@
+package memory
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ExtractPrompt 从对话中提取用户长期记忆的系统提示语
+const ExtractPrompt = `You are a memory extraction assistant. Read the conversation between the user and the assistant, and extract durable facts about the user that will be useful in future conversations, such as name, occupation, location, preferences, goals, skills and long-term plans.
+
+Rules:
+- Only extract facts stated or clearly implied by the user, never from the assistant's reply alone.
+- Ignore temporary requests, small talk, and sensitive data such as passwords, ID numbers or bank accounts.
+- Do not repeat facts that are already in the known memories.
+- Each fact should be a short, self-contained sentence in the same language as the user.
+- Reply with a JSON array of strings only, reply [] if there is nothing worth remembering.`
+
+// Memory 一条用户记忆
+type Memory struct {
+	ID      int64
+	Content string
+}
+
+// BuildExtractMessage 构建提取记忆时发送给模型的用户消息
+func BuildExtractMessage(known []string, question, answer string) string {
+	var sb strings.Builder
+	sb.WriteString("Known memories:\n")
+	if len(known) == 0 {
+		sb.WriteString("(none)\n")
+	}
+	for _, m := range known {
+		sb.WriteString("- " + m + "\n")
+	}
+
+	sb.WriteString("\nConversation:\nUser: " + question + "\nAssistant: " + answer)
+	return sb.String()
+}
+
+// ParseExtracted 解析模型返回的记忆列表，兼容使用 Markdown 代码块包裹的情况
+func ParseExtracted(text string) ([]string, error) {
+	text = strings.TrimSpace(text)
+	if strings.HasPrefix(text, "```") {
+		text = strings.TrimPrefix(text[strings.Index(text, "\n")+1:], "\n")
+		text = strings.TrimSuffix(strings.TrimSpace(text), "```")
+	}
+
+	start, end := strings.Index(text, "["), strings.LastIndex(text, "]")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("invalid memory list: %s", text)
+	}
+
+	var items []string
+	if err := json.Unmarshal([]byte(text[start:end+1]), &items); err != nil {
+		return nil, fmt.Errorf("invalid memory list: %w", err)
+	}
+
+	ret := make([]string, 0, len(items))
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			ret = append(ret, item)
+		}
+	}
+
+	return ret, nil
+}
+
+// Dedup 过滤掉与已有记忆重复的内容（忽略大小写以及首尾空白）
+func Dedup(known []string, items []string) []string {
+	seen := make(map[string]bool, len(known)+len(items))
+	for _, k := range known {
+		seen[normalize(k)] = true
+	}
+
+	ret := make([]string, 0, len(items))
+	for _, item := range items {
+		key := normalize(item)
+		if key == "" || seen[key] {
+			continue
+		}
+
+		seen[key] = true
+		ret = append(ret, item)
+	}
+
+	return ret
+}
+
+func normalize(s string) string {
+	return strings.ToLower(strings.TrimRight(strings.TrimSpace(s), ".。!！"))
+}
+
+// Select 按照与 query 的相关性选择最多 limit 条记忆，相关性为 0 的记忆不会被选中
+func Select(query string, memories []Memory, limit int) []Memory {
+	queryTokens := tokenize(query)
+	if len(queryTokens) == 0 || limit <= 0 {
+		return nil
+	}
+
+	type scored struct {
+		memory Memory
+		score  float64
+		index  int
+	}
+
+	candidates := make([]scored, 0, len(memories))
+	for i, m := range memories {
+		tokens := tokenize(m.Content)
+		if len(tokens) == 0 {
+			continue
+		}
+
+		hits := 0
+		for t := range tokens {
+			if queryTokens[t] {
+				hits++
+			}
+		}
+
+		if hits == 0 {
+			continue
+		}
+
+		// 命中数量为主，按照记忆长度归一化，避免过长的记忆总是排在前面
+		candidates = append(candidates, scored{memory: m, score: float64(hits) + float64(hits)/float64(len(tokens)), index: i})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].score > candidates[j].score
+	})
+
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+
+	ret := make([]Memory, 0, len(candidates))
+	for _, c := range candidates {
+		ret = append(ret, c.memory)
+	}
+
+	return ret
+}
+
+// BuildPrompt 将选中的记忆构建为系统提示语，用于合并到对话的系统提示语中
+func BuildPrompt(memories []Memory) string {
+	if len(memories) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("Here is what you remember about the user from previous conversations, use it only when it is relevant:\n")
+	for _, m := range memories {
+		sb.WriteString("- " + m.Content + "\n")
+	}
+
+	return strings.TrimSpace(sb.String())
+}
+
+// tokenize 分词：拉丁字母和数字按照单词切分，中日韩文字按照二元组切分
+func tokenize(text string) map[string]bool {
+	tokens := make(map[string]bool)
+
+	var word []rune
+	var prevCJK rune
+	flushWord := func() {
+		if len(word) > 1 && !stopWords[string(word)] {
+			tokens[string(word)] = true
+		}
+		word = word[:0]
+	}
+
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case isCJK(r):
+			flushWord()
+			if prevCJK != 0 {
+				tokens[string([]rune{prevCJK, r})] = true
+			}
+			prevCJK = r
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			prevCJK = 0
+			word = append(word, r)
+		default:
+			prevCJK = 0
+			flushWord()
+		}
+	}
+	flushWord()
+
+	return tokens
+}
+
+func isCJK(r rune) bool {
+	return r >= utf8.RuneSelf && (unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) || unicode.Is(unicode.Katakana, r) || unicode.Is(unicode.Hangul, r))
+}
+
+var stopWords = map[string]bool{
+	"the": true, "and": true, "is": true, "are": true, "was": true, "to": true, "of": true,
+	"in": true, "on": true, "for": true, "with": true, "it": true, "my": true, "me": true,
+	"you": true, "your": true, "what": true, "how": true, "do": true, "does": true, "can": true,
+	"an": true, "be": true, "this": true, "that": true, "at": true, "or": true, "as": true,
+}
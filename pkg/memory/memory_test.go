@@ -0,0 +1,53 @@
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/mylxsw/aidea-server/pkg/memory"
+	"github.com/mylxsw/go-utils/assert"
+)
+
+func TestParseExtracted(t *testing.T) {
+	items, err := memory.ParseExtracted("```json\n[\"User lives in Beijing\", \" \", \"用户是一名程序员\"]\n```")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"User lives in Beijing", "用户是一名程序员"}, items)
+
+	items, err = memory.ParseExtracted("[]")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(items))
+
+	_, err = memory.ParseExtracted("nothing to remember")
+	assert.True(t, err != nil)
+}
+
+func TestDedup(t *testing.T) {
+	items := memory.Dedup([]string{"User likes cats."}, []string{"user likes cats", "User likes dogs", "User likes dogs."})
+	assert.Equal(t, []string{"User likes dogs"}, items)
+}
+
+func TestSelect(t *testing.T) {
+	memories := []memory.Memory{
+		{ID: 1, Content: "User lives in Beijing"},
+		{ID: 2, Content: "用户喜欢喝咖啡"},
+		{ID: 3, Content: "User is learning the Go programming language"},
+		{ID: 4, Content: "User has a cat named Tom"},
+	}
+
+	selected := memory.Select("Recommend some Go programming books", memories, 5)
+	assert.Equal(t, 1, len(selected))
+	assert.Equal(t, int64(3), selected[0].ID)
+
+	selected = memory.Select("附近有什么好喝的咖啡店", memories, 5)
+	assert.Equal(t, 1, len(selected))
+	assert.Equal(t, int64(2), selected[0].ID)
+
+	assert.Equal(t, 0, len(memory.Select("hello", memories, 5)))
+	assert.Equal(t, 1, len(memory.Select("my cat Tom lives in Beijing", memories, 1)))
+}
+
+func TestBuildPrompt(t *testing.T) {
+	assert.Equal(t, "", memory.BuildPrompt(nil))
+
+	prompt := memory.BuildPrompt([]memory.Memory{{ID: 1, Content: "User lives in Beijing"}})
+	assert.True(t, len(prompt) > 0)
+}
@@ -0,0 +1,56 @@
+package moderation
+
+import (
+	"context"
+	"strings"
+
+	"github.com/mylxsw/aidea-server/pkg/aliyun"
+)
+
+// AliyunProvider 阿里云内容安全服务
+type AliyunProvider struct {
+	client *aliyun.Aliyun
+}
+
+func NewAliyunProvider(client *aliyun.Aliyun) *AliyunProvider {
+	return &AliyunProvider{client: client}
+}
+
+func (p *AliyunProvider) Name() string {
+	return "aliyun"
+}
+
+func (p *AliyunProvider) Check(ctx context.Context, scene Scene, content string) (*Result, error) {
+	checkType := aliyun.CheckTypeChat
+	switch scene {
+	case SceneNickname:
+		checkType = aliyun.CheckTypeNickname
+	case ScenePrompt:
+		checkType = aliyun.CheckTypeAIGCPrompt
+	}
+
+	res, err := p.client.ContentDetect(checkType, content)
+	if err != nil {
+		return nil, err
+	}
+
+	// 阿里云只有同时给出敏感词时才认为是确定违规
+	if !res.IsReallyUnSafe() {
+		return &Result{Safe: true}, nil
+	}
+
+	var words []string
+	for _, word := range strings.Split(res.Reason.RiskWords, ",") {
+		if word = strings.TrimSpace(word); word != "" {
+			words = append(words, word)
+		}
+	}
+
+	return &Result{
+		Safe:     false,
+		Provider: p.Name(),
+		Label:    res.Label,
+		Reason:   res.Reason.RiskTips,
+		Words:    words,
+	}, nil
+}
@@ -0,0 +1,50 @@
+package moderation
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mylxsw/asteria/log"
+	"github.com/redis/go-redis/v9"
+)
+
+// cachedProvider 缓存远程审核服务的检查结果，避免相同内容重复请求
+type cachedProvider struct {
+	Moderator
+	rds *redis.Client
+	ttl time.Duration
+}
+
+// Cached 为审核服务增加 Redis 结果缓存
+func Cached(p Moderator, rds *redis.Client, ttl time.Duration) Moderator {
+	return &cachedProvider{Moderator: p, rds: rds, ttl: ttl}
+}
+
+func (p *cachedProvider) Check(ctx context.Context, scene Scene, content string) (*Result, error) {
+	cacheKey := fmt.Sprintf("detect:%s:%s:%x", p.Name(), scene, md5.Sum([]byte(content)))
+	if cacheValue, err := p.rds.Get(ctx, cacheKey).Result(); err == nil {
+		var res Result
+		if err := json.Unmarshal([]byte(cacheValue), &res); err != nil {
+			log.WithFields(log.Fields{"cache_key": cacheKey}).Errorf("unmarshal cache value failed: %s", err)
+		} else {
+			return &res, nil
+		}
+	}
+
+	res, err := p.Moderator.Check(ctx, scene, content)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheValue, err := json.Marshal(res)
+	if err != nil {
+		log.WithFields(log.Fields{"cache_key": cacheKey}).Errorf("marshal content detect result failed: %s", err)
+	} else if err := p.rds.Set(ctx, cacheKey, string(cacheValue), p.ttl).Err(); err != nil {
+		log.WithFields(log.Fields{"cache_key": cacheKey}).Errorf("cache content detect result failed: %s", err)
+	}
+
+	return res, nil
+}
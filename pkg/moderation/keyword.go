@@ -0,0 +1,81 @@
+package moderation
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mylxsw/asteria/log"
+)
+
+// WordsLoader 加载敏感词列表
+type WordsLoader func(ctx context.Context) ([]string, error)
+
+// KeywordProvider 本地敏感词过滤，词库定期通过 WordsLoader 重新加载
+type KeywordProvider struct {
+	loader   WordsLoader
+	interval time.Duration
+
+	matcher  atomic.Pointer[Matcher]
+	loadedAt atomic.Int64
+	lock     sync.Mutex
+}
+
+// NewKeywordProvider 创建本地敏感词过滤服务，loader 为空时只使用 Reload 设置的词库
+func NewKeywordProvider(loader WordsLoader, interval time.Duration) *KeywordProvider {
+	p := &KeywordProvider{loader: loader, interval: interval}
+	p.matcher.Store(NewMatcher(nil))
+
+	return p
+}
+
+func (p *KeywordProvider) Name() string {
+	return "keyword"
+}
+
+// Reload 使用指定的词库替换当前词库
+func (p *KeywordProvider) Reload(words []string) {
+	p.matcher.Store(NewMatcher(words))
+	p.loadedAt.Store(time.Now().UnixNano())
+}
+
+func (p *KeywordProvider) Check(ctx context.Context, scene Scene, content string) (*Result, error) {
+	p.refresh(ctx)
+
+	words := p.matcher.Load().Match(content)
+	if len(words) == 0 {
+		return &Result{Safe: true}, nil
+	}
+
+	return &Result{
+		Safe:     false,
+		Provider: p.Name(),
+		Label:    "keyword",
+		Reason:   "内容包含敏感词",
+		Words:    words,
+	}, nil
+}
+
+// refresh 词库过期时重新加载，加载过程中其它请求继续使用旧词库
+func (p *KeywordProvider) refresh(ctx context.Context) {
+	if p.loader == nil || time.Since(time.Unix(0, p.loadedAt.Load())) < p.interval {
+		return
+	}
+
+	if !p.lock.TryLock() {
+		return
+	}
+	defer p.lock.Unlock()
+
+	// 加载失败时同样更新加载时间，避免每次请求都重试
+	defer p.loadedAt.Store(time.Now().UnixNano())
+
+	words, err := p.loader(ctx)
+	if err != nil {
+		log.Errorf("load moderation words failed: %v", err)
+		return
+	}
+
+	p.matcher.Store(NewMatcher(words))
+}
@@ -0,0 +1,186 @@
+package moderation
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/mylxsw/asteria/log"
+)
+
+// RegexpPrefix 以该前缀开头的词条按正则表达式匹配
+const RegexpPrefix = "re:"
+
+// Matcher 基于 Aho-Corasick 自动机的多模式敏感词匹配器
+//
+// 匹配时忽略大小写，并跳过空白和标点符号，避免通过插入分隔符绕过检测
+type Matcher struct {
+	nodes    []acNode
+	words    []string
+	regexps  []*regexp.Regexp
+	patterns int
+}
+
+type acNode struct {
+	next    map[rune]int
+	fail    int
+	outputs []int
+}
+
+// NewMatcher 创建匹配器，无效的词条（空白、无法编译的正则）会被忽略
+func NewMatcher(words []string) *Matcher {
+	m := &Matcher{nodes: []acNode{{next: make(map[rune]int)}}}
+
+	for _, word := range words {
+		word = strings.TrimSpace(word)
+		if word == "" {
+			continue
+		}
+
+		if strings.HasPrefix(word, RegexpPrefix) {
+			re, err := regexp.Compile("(?i)" + strings.TrimPrefix(word, RegexpPrefix))
+			if err != nil {
+				log.F(log.M{"word": word}).Errorf("invalid moderation regexp: %v", err)
+				continue
+			}
+
+			m.regexps = append(m.regexps, re)
+			m.patterns++
+			continue
+		}
+
+		if m.insert(word) {
+			m.patterns++
+		}
+	}
+
+	m.build()
+	return m
+}
+
+// Len 有效词条数量
+func (m *Matcher) Len() int {
+	return m.patterns
+}
+
+// Match 返回文本中命中的词条，按首次命中的顺序去重
+func (m *Matcher) Match(text string) []string {
+	if m.patterns == 0 || text == "" {
+		return nil
+	}
+
+	matched := make([]string, 0)
+	seen := make(map[string]bool)
+	appendMatched := func(word string) {
+		if !seen[word] {
+			seen[word] = true
+			matched = append(matched, word)
+		}
+	}
+
+	state := 0
+	for _, r := range text {
+		r, ok := normalizeRune(r)
+		if !ok {
+			continue
+		}
+
+		for state != 0 && !m.hasNext(state, r) {
+			state = m.nodes[state].fail
+		}
+
+		if next, ok := m.nodes[state].next[r]; ok {
+			state = next
+		}
+
+		for _, idx := range m.nodes[state].outputs {
+			appendMatched(m.words[idx])
+		}
+	}
+
+	for _, re := range m.regexps {
+		if word := re.FindString(text); word != "" {
+			appendMatched(word)
+		}
+	}
+
+	return matched
+}
+
+func (m *Matcher) hasNext(state int, r rune) bool {
+	_, ok := m.nodes[state].next[r]
+	return ok
+}
+
+func (m *Matcher) insert(word string) bool {
+	state, length := 0, 0
+	for _, r := range word {
+		r, ok := normalizeRune(r)
+		if !ok {
+			continue
+		}
+
+		next, ok := m.nodes[state].next[r]
+		if !ok {
+			m.nodes = append(m.nodes, acNode{next: make(map[rune]int)})
+			next = len(m.nodes) - 1
+			m.nodes[state].next[r] = next
+		}
+
+		state = next
+		length++
+	}
+
+	if length == 0 {
+		return false
+	}
+
+	for _, idx := range m.nodes[state].outputs {
+		if strings.EqualFold(m.words[idx], word) {
+			return false
+		}
+	}
+
+	m.words = append(m.words, word)
+	m.nodes[state].outputs = append(m.nodes[state].outputs, len(m.words)-1)
+
+	return true
+}
+
+// build 按广度优先构建失配指针，并将失配节点的输出合并到当前节点
+func (m *Matcher) build() {
+	queue := make([]int, 0, len(m.nodes))
+	for _, child := range m.nodes[0].next {
+		queue = append(queue, child)
+	}
+
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+
+		for r, child := range m.nodes[state].next {
+			fail := m.nodes[state].fail
+			for fail != 0 && !m.hasNext(fail, r) {
+				fail = m.nodes[fail].fail
+			}
+
+			if next, ok := m.nodes[fail].next[r]; ok && next != child {
+				fail = next
+			} else {
+				fail = 0
+			}
+
+			m.nodes[child].fail = fail
+			m.nodes[child].outputs = append(m.nodes[child].outputs, m.nodes[fail].outputs...)
+			queue = append(queue, child)
+		}
+	}
+}
+
+func normalizeRune(r rune) (rune, bool) {
+	if unicode.IsSpace(r) || unicode.IsPunct(r) || unicode.IsSymbol(r) {
+		return 0, false
+	}
+
+	return unicode.ToLower(r), true
+}
@@ -0,0 +1,116 @@
+package moderation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mylxsw/asteria/log"
+)
+
+// Scene 内容审核场景
+type Scene string
+
+const (
+	SceneNickname Scene = "nickname"
+	ScenePrompt   Scene = "prompt"
+	SceneChat     Scene = "chat"
+	// SceneAnswer 模型回复内容
+	SceneAnswer Scene = "answer"
+)
+
+// Result 内容审核结果
+type Result struct {
+	Safe bool `json:"safe"`
+	// Provider 给出判定结果的审核服务
+	Provider string `json:"provider,omitempty"`
+	// Label 违规类型标签，多个以逗号分隔
+	Label string `json:"label,omitempty"`
+	// Reason 违规原因
+	Reason string `json:"reason,omitempty"`
+	// Words 命中的敏感词
+	Words []string `json:"words,omitempty"`
+}
+
+// IsReallyUnSafe 内容是否违规
+func (res *Result) IsReallyUnSafe() bool {
+	return !res.Safe
+}
+
+// ReasonDetail 违规原因详情
+func (res *Result) ReasonDetail() string {
+	detail := res.Reason
+	if len(res.Words) > 0 {
+		detail += fmt.Sprintf("（敏感词：%s）", strings.Join(res.Words, ","))
+	}
+
+	return detail
+}
+
+// Moderator 内容审核服务
+type Moderator interface {
+	// Name 审核服务名称，用于在场景策略中引用
+	Name() string
+	// Check 检查内容是否违规
+	Check(ctx context.Context, scene Scene, content string) (*Result, error)
+}
+
+// Policy 场景审核策略
+type Policy struct {
+	// Providers 按顺序执行的审核服务名称，任一服务判定违规则内容违规
+	Providers []string
+}
+
+// Chain 组合多个审核服务，按场景策略依次检查
+type Chain struct {
+	providers map[string]Moderator
+	policies  map[Scene]Policy
+}
+
+func NewChain(policies map[Scene]Policy, providers ...Moderator) *Chain {
+	chain := &Chain{
+		providers: make(map[string]Moderator),
+		policies:  make(map[Scene]Policy),
+	}
+
+	for _, p := range providers {
+		chain.providers[p.Name()] = p
+	}
+
+	for scene, policy := range policies {
+		names := make([]string, 0, len(policy.Providers))
+		for _, name := range policy.Providers {
+			if _, ok := chain.providers[name]; !ok {
+				log.F(log.M{"scene": scene, "provider": name}).Warningf("moderation provider not available, ignored")
+				continue
+			}
+
+			names = append(names, name)
+		}
+
+		chain.policies[scene] = Policy{Providers: names}
+	}
+
+	return chain
+}
+
+// Check 按场景策略检查内容，审核服务调用失败时跳过该服务，不会阻断请求
+func (c *Chain) Check(ctx context.Context, scene Scene, content string) *Result {
+	for _, name := range c.policies[scene].Providers {
+		res, err := c.providers[name].Check(ctx, scene, content)
+		if err != nil {
+			log.F(log.M{"scene": scene, "provider": name, "content": content}).Errorf("content moderation failed: %v", err)
+			continue
+		}
+
+		if res != nil && !res.Safe {
+			if res.Provider == "" {
+				res.Provider = name
+			}
+
+			return res
+		}
+	}
+
+	return &Result{Safe: true}
+}
@@ -0,0 +1,150 @@
+package moderation_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mylxsw/aidea-server/pkg/moderation"
+	"github.com/mylxsw/go-utils/assert"
+)
+
+func TestMatcher(t *testing.T) {
+	m := moderation.NewMatcher([]string{"he", "she", "his", "hers", "敏感词", "  ", "re:\\d{11}", "re:[invalid", "Bad Word", "bad word"})
+	assert.Equal(t, 7, m.Len())
+
+	assert.Equal(t, []string{"she", "he", "hers"}, m.Match("ushers"))
+	assert.Equal(t, []string{"敏感词"}, m.Match("这是一个敏 感-词测试"))
+	assert.Equal(t, []string{"Bad Word"}, m.Match("a BADWORD!"))
+	assert.Equal(t, []string{"13800138000"}, m.Match("call me 13800138000"))
+	assert.Equal(t, 0, len(m.Match("nothing to see")))
+
+	assert.Equal(t, 0, len(moderation.NewMatcher(nil).Match("anything")))
+}
+
+type fakeModerator struct {
+	name   string
+	unsafe bool
+	err    error
+	calls  int
+}
+
+func (f *fakeModerator) Name() string { return f.name }
+
+func (f *fakeModerator) Check(ctx context.Context, scene moderation.Scene, content string) (*moderation.Result, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	return &moderation.Result{Safe: !f.unsafe, Reason: f.name}, nil
+}
+
+func TestChain(t *testing.T) {
+	broken := &fakeModerator{name: "broken", err: errors.New("timeout")}
+	strict := &fakeModerator{name: "strict", unsafe: true}
+	loose := &fakeModerator{name: "loose"}
+
+	chain := moderation.NewChain(map[moderation.Scene]moderation.Policy{
+		moderation.SceneNickname: {Providers: []string{"broken", "loose"}},
+		moderation.SceneChat:     {Providers: []string{"unknown", "broken", "strict", "loose"}},
+	}, broken, strict, loose)
+
+	res := chain.Check(context.TODO(), moderation.SceneNickname, "hello")
+	assert.True(t, res.Safe)
+
+	res = chain.Check(context.TODO(), moderation.SceneChat, "hello")
+	assert.True(t, res.IsReallyUnSafe())
+	assert.Equal(t, "strict", res.Provider)
+	assert.Equal(t, 1, loose.calls)
+
+	res = chain.Check(context.TODO(), moderation.ScenePrompt, "hello")
+	assert.True(t, res.Safe)
+	assert.Equal(t, 1, strict.calls)
+}
+
+func TestKeywordProvider(t *testing.T) {
+	words := []string{"foo"}
+	p := moderation.NewKeywordProvider(func(ctx context.Context) ([]string, error) {
+		return words, nil
+	}, time.Hour)
+
+	res, err := p.Check(context.TODO(), moderation.SceneChat, "FOO bar")
+	assert.NoError(t, err)
+	assert.False(t, res.Safe)
+	assert.Equal(t, []string{"foo"}, res.Words)
+	assert.Equal(t, "内容包含敏感词（敏感词：foo）", res.ReasonDetail())
+
+	// 词库在刷新间隔内不会重新加载，Reload 立即生效
+	words = []string{"bar"}
+	res, err = p.Check(context.TODO(), moderation.SceneChat, "FOO bar")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"foo"}, res.Words)
+
+	p.Reload(words)
+	res, err = p.Check(context.TODO(), moderation.SceneChat, "FOO bar")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"bar"}, res.Words)
+}
+
+func TestStreamChecker(t *testing.T) {
+	matcher := moderation.NewMatcher([]string{"违禁词"})
+	windows := make([]string, 0)
+	checker := moderation.NewStreamChecker(func(content string) *moderation.Result {
+		windows = append(windows, content)
+		if words := matcher.Match(content); len(words) > 0 {
+			return &moderation.Result{Safe: false, Words: words}
+		}
+
+		return &moderation.Result{Safe: true}
+	})
+	checker.MinChars = 4
+	checker.OverlapChars = 2
+
+	// 未遇到句子边界时暂存内容
+	content, res := checker.Write("你好")
+	assert.Equal(t, "", content)
+	assert.True(t, res == nil)
+
+	content, res = checker.Write("，世界。今天")
+	assert.Equal(t, "你好，世界。", content)
+	assert.True(t, res == nil)
+
+	// 敏感词跨越窗口边界时，通过重叠部分检测
+	content, res = checker.Write("天气不错。违")
+	assert.Equal(t, "今天天气不错。", content)
+	assert.True(t, res == nil)
+
+	content, res = checker.Write("禁词出现了！后续")
+	assert.Equal(t, "", content)
+	assert.Equal(t, []string{"违禁词"}, res.Words)
+	assert.Equal(t, []string{"你好，世界。", "界。今天天气不错。", "错。违禁词出现了！"}, windows)
+
+	// 违规后不再输出任何内容
+	content, res = checker.Write("更多内容。")
+	assert.Equal(t, "", content)
+	assert.True(t, checker.Violation() == res)
+
+	content, _ = checker.Flush()
+	assert.Equal(t, "", content)
+}
+
+func TestStreamChecker_Flush(t *testing.T) {
+	checker := moderation.NewStreamChecker(func(content string) *moderation.Result { return nil })
+	checker.MaxChars = 5
+
+	content, res := checker.Write("abc")
+	assert.Equal(t, "", content)
+	assert.True(t, res == nil)
+
+	content, _ = checker.Write("defg")
+	assert.Equal(t, "abcdefg", content)
+
+	content, _ = checker.Write("hi")
+	assert.Equal(t, "", content)
+
+	content, res = checker.Flush()
+	assert.Equal(t, "hi", content)
+	assert.True(t, res == nil)
+}
@@ -0,0 +1,120 @@
+package moderation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// OpenAIProvider 兼容 OpenAI Moderation 接口的审核服务
+type OpenAIProvider struct {
+	server string
+	key    string
+	model  string
+	client *http.Client
+}
+
+// NewOpenAIProvider 创建 OpenAI Moderation 审核服务，server 为包含 /v1 的服务地址
+func NewOpenAIProvider(server, key, model string, client *http.Client) *OpenAIProvider {
+	return &OpenAIProvider{
+		server: strings.TrimSuffix(server, "/"),
+		key:    key,
+		model:  model,
+		client: client,
+	}
+}
+
+func (p *OpenAIProvider) Name() string {
+	return "openai"
+}
+
+type openaiModerationRequest struct {
+	Model string `json:"model,omitempty"`
+	Input string `json:"input"`
+}
+
+type openaiModerationResponse struct {
+	Results []struct {
+		Flagged    bool            `json:"flagged"`
+		Categories map[string]bool `json:"categories"`
+	} `json:"results"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+func (p *OpenAIProvider) Check(ctx context.Context, scene Scene, content string) (*Result, error) {
+	body, err := json.Marshal(openaiModerationRequest{Model: p.model, Input: content})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.server+"/moderations", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+p.key)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("moderation request failed: status code %d, body: %s", resp.StatusCode, string(respBody))
+	}
+
+	var ret openaiModerationResponse
+	if err := json.Unmarshal(respBody, &ret); err != nil {
+		return nil, err
+	}
+
+	if ret.Error != nil {
+		return nil, fmt.Errorf("moderation request failed: %s", ret.Error.Message)
+	}
+
+	flagged, categories := false, make([]string, 0)
+	for _, item := range ret.Results {
+		if !item.Flagged {
+			continue
+		}
+
+		flagged = true
+
+		for category, hit := range item.Categories {
+			if hit {
+				categories = append(categories, category)
+			}
+		}
+	}
+
+	if !flagged {
+		return &Result{Safe: true}, nil
+	}
+
+	sort.Strings(categories)
+	label := strings.Join(categories, ",")
+	if label == "" {
+		label = "flagged"
+	}
+
+	return &Result{
+		Safe:     false,
+		Provider: p.Name(),
+		Label:    label,
+		Reason:   fmt.Sprintf("内容违反使用政策：%s", label),
+	}, nil
+}
@@ -0,0 +1,75 @@
+package moderation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/mylxsw/aidea-server/config"
+	"github.com/mylxsw/aidea-server/pkg/aliyun"
+	"github.com/mylxsw/aidea-server/pkg/proxy"
+	"github.com/mylxsw/aidea-server/pkg/repo"
+	"github.com/mylxsw/glacier/infra"
+	"github.com/redis/go-redis/v9"
+)
+
+// SettingKeyWords 敏感词库配置项，值为 JSON 字符串数组，以 re: 开头的词条为正则表达式
+const SettingKeyWords = "moderation-words"
+
+type Provider struct{}
+
+func (Provider) Register(binder infra.Binder) {
+	binder.MustSingleton(func(settingRepo *repo.SettingRepo) *KeywordProvider {
+		return NewKeywordProvider(func(ctx context.Context) ([]string, error) {
+			data, err := settingRepo.Get(ctx, SettingKeyWords)
+			if err != nil {
+				if errors.Is(err, repo.ErrNotFound) {
+					return []string{}, nil
+				}
+
+				return nil, err
+			}
+
+			var words []string
+			if err := json.Unmarshal([]byte(data), &words); err != nil {
+				return nil, err
+			}
+
+			return words, nil
+		}, time.Minute)
+	})
+
+	binder.MustSingleton(func(conf *config.Config, resolver infra.Resolver, keyword *KeywordProvider, rds *redis.Client) *Chain {
+		providers := []Moderator{keyword}
+
+		if conf.AliyunAccessKeyID != "" {
+			var client *aliyun.Aliyun
+			resolver.MustResolve(func(ali *aliyun.Aliyun) { client = ali })
+			providers = append(providers, Cached(NewAliyunProvider(client), rds, 24*time.Hour))
+		}
+
+		if conf.ModerationOpenAIKey != "" {
+			httpClient := &http.Client{Timeout: 10 * time.Second}
+			if conf.SupportProxy() && conf.ModerationOpenAIAutoProxy {
+				resolver.MustResolve(func(pp *proxy.Proxy) {
+					httpClient.Transport = pp.BuildTransport()
+				})
+			}
+
+			providers = append(providers, Cached(
+				NewOpenAIProvider(conf.ModerationOpenAIServer, conf.ModerationOpenAIKey, conf.ModerationOpenAIModel, httpClient),
+				rds,
+				24*time.Hour,
+			))
+		}
+
+		return NewChain(map[Scene]Policy{
+			SceneNickname: {Providers: conf.ModerationNicknameProviders},
+			ScenePrompt:   {Providers: conf.ModerationPromptProviders},
+			SceneChat:     {Providers: conf.ModerationChatProviders},
+			SceneAnswer:   {Providers: conf.ModerationAnswerProviders},
+		}, providers...)
+	})
+}
@@ -0,0 +1,99 @@
+package moderation
+
+import "strings"
+
+// sentenceBoundaries 句子边界字符，流式内容在这些位置切分后检查
+const sentenceBoundaries = "。！？；…!?;\n"
+
+// StreamChecker 流式输出内容审核
+//
+// 写入的内容先暂存，在句子边界处将上一窗口的末尾与新增的句子拼接为检查窗口，
+// 检查通过后才返回可以输出的内容，发现违规后不再输出任何内容
+type StreamChecker struct {
+	check func(content string) *Result
+
+	// MinChars 待检查内容少于该长度时，即使遇到句子边界也继续累积
+	MinChars int
+	// MaxChars 待检查内容超过该长度时，即使没有句子边界也强制检查
+	MaxChars int
+	// OverlapChars 每个检查窗口保留上一窗口末尾的长度，避免敏感词被切分后漏检
+	OverlapChars int
+
+	pending   []rune
+	overlap   []rune
+	violation *Result
+}
+
+// NewStreamChecker 创建流式输出内容审核，check 返回 nil 时视为内容安全
+func NewStreamChecker(check func(content string) *Result) *StreamChecker {
+	return &StreamChecker{
+		check:        check,
+		MinChars:     10,
+		MaxChars:     200,
+		OverlapChars: 20,
+	}
+}
+
+// Write 写入新增的内容，返回检查通过可以输出的内容，发现违规时返回违规结果
+func (c *StreamChecker) Write(text string) (string, *Result) {
+	if c.violation != nil {
+		return "", c.violation
+	}
+
+	c.pending = append(c.pending, []rune(text)...)
+
+	end := -1
+	for i := len(c.pending) - 1; i >= 0; i-- {
+		if strings.ContainsRune(sentenceBoundaries, c.pending[i]) {
+			end = i + 1
+			break
+		}
+	}
+
+	if end < c.MinChars {
+		end = -1
+	}
+
+	if end < 0 && len(c.pending) >= c.MaxChars {
+		end = len(c.pending)
+	}
+
+	if end < 0 {
+		return "", nil
+	}
+
+	return c.checkSegment(end)
+}
+
+// Flush 检查剩余的全部内容，在流式输出结束时调用
+func (c *StreamChecker) Flush() (string, *Result) {
+	if c.violation != nil {
+		return "", c.violation
+	}
+
+	if len(c.pending) == 0 {
+		return "", nil
+	}
+
+	return c.checkSegment(len(c.pending))
+}
+
+// Violation 返回违规结果，内容安全时返回 nil
+func (c *StreamChecker) Violation() *Result {
+	return c.violation
+}
+
+func (c *StreamChecker) checkSegment(end int) (string, *Result) {
+	segment := c.pending[:end]
+	window := append(append([]rune{}, c.overlap...), segment...)
+
+	if res := c.check(string(window)); res != nil && res.IsReallyUnSafe() {
+		c.violation = res
+		return "", res
+	}
+
+	c.overlap = window[max(0, len(window)-c.OverlapChars):]
+	c.pending = append([]rune{}, c.pending[end:]...)
+
+	return string(segment), nil
+}
@@ -0,0 +1,182 @@
+package prompt
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mylxsw/go-utils/array"
+	"github.com/mylxsw/go-utils/ternary"
+)
+
+const (
+	// VariableTypeText 文本变量
+	VariableTypeText = "text"
+	// VariableTypeSelect 选项变量，取值必须为预设的选项之一
+	VariableTypeSelect = "select"
+	// VariableTypeNumber 数字变量
+	VariableTypeNumber = "number"
+)
+
+const (
+	// MaxVariables 每个模板最多可以定义的变量数量
+	MaxVariables = 20
+	// MaxValueLength 变量取值的最大长度
+	MaxValueLength = 2000
+	// MaxOptions 选项变量最多可以定义的选项数量
+	MaxOptions = 50
+)
+
+// variablePattern 模板中的变量占位符，格式为 {{name}}，名称两侧允许有空白
+var variablePattern = regexp.MustCompile(`{{\s*([a-zA-Z_][a-zA-Z0-9_]*)\s*}}`)
+
+var variableNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// Variable 模板变量定义
+type Variable struct {
+	Name     string   `json:"name"`
+	Label    string   `json:"label,omitempty"`
+	Type     string   `json:"type"`
+	Required bool     `json:"required,omitempty"`
+	Default  string   `json:"default,omitempty"`
+	Options  []string `json:"options,omitempty"`
+	Min      *float64 `json:"min,omitempty"`
+	Max      *float64 `json:"max,omitempty"`
+}
+
+// Validate 检查变量定义是否合法
+func (v Variable) Validate() error {
+	if !variableNamePattern.MatchString(v.Name) {
+		return fmt.Errorf("invalid variable name: %s", v.Name)
+	}
+
+	switch v.Type {
+	case VariableTypeText:
+	case VariableTypeSelect:
+		if len(v.Options) == 0 || len(v.Options) > MaxOptions {
+			return fmt.Errorf("variable %s must have 1-%d options", v.Name, MaxOptions)
+		}
+	case VariableTypeNumber:
+		if v.Min != nil && v.Max != nil && *v.Min > *v.Max {
+			return fmt.Errorf("variable %s: min is greater than max", v.Name)
+		}
+	default:
+		return fmt.Errorf("variable %s: unsupported type %s", v.Name, v.Type)
+	}
+
+	if v.Default != "" {
+		if err := v.check(v.Default); err != nil {
+			return fmt.Errorf("variable %s: invalid default value: %w", v.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func (v Variable) displayName() string {
+	return ternary.If(v.Label != "", v.Label, v.Name)
+}
+
+// check 检查变量取值是否符合变量定义
+func (v Variable) check(value string) error {
+	if utf8.RuneCountInString(value) > MaxValueLength {
+		return errors.New("value is too long")
+	}
+
+	switch v.Type {
+	case VariableTypeSelect:
+		if !array.In(value, v.Options) {
+			return fmt.Errorf("value must be one of %s", strings.Join(v.Options, ", "))
+		}
+	case VariableTypeNumber:
+		num, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return errors.New("value must be a number")
+		}
+
+		if v.Min != nil && num < *v.Min {
+			return fmt.Errorf("value must be greater than or equal to %v", *v.Min)
+		}
+
+		if v.Max != nil && num > *v.Max {
+			return fmt.Errorf("value must be less than or equal to %v", *v.Max)
+		}
+	}
+
+	return nil
+}
+
+// Placeholders 返回模板中引用的变量名称（去重，按照首次出现的顺序）
+func Placeholders(content string) []string {
+	names := make([]string, 0)
+	for _, match := range variablePattern.FindAllStringSubmatch(content, -1) {
+		if !array.In(match[1], names) {
+			names = append(names, match[1])
+		}
+	}
+
+	return names
+}
+
+// Validate 检查模板以及变量定义是否合法：变量名称不能重复，模板中引用的变量必须已经定义
+func Validate(content string, vars []Variable) error {
+	if len(vars) > MaxVariables {
+		return fmt.Errorf("too many variables, at most %d", MaxVariables)
+	}
+
+	defined := make(map[string]bool, len(vars))
+	for _, v := range vars {
+		if err := v.Validate(); err != nil {
+			return err
+		}
+
+		if defined[v.Name] {
+			return fmt.Errorf("duplicate variable: %s", v.Name)
+		}
+
+		defined[v.Name] = true
+	}
+
+	for _, name := range Placeholders(content) {
+		if !defined[name] {
+			return fmt.Errorf("undefined variable: %s", name)
+		}
+	}
+
+	return nil
+}
+
+// Render 使用变量取值渲染模板，未提供取值的变量使用默认值，必填变量缺失或者取值不合法时返回错误
+func Render(content string, vars []Variable, values map[string]string) (string, error) {
+	resolved := make(map[string]string, len(vars))
+	for _, v := range vars {
+		value, ok := values[v.Name]
+		value = strings.TrimSpace(value)
+		if !ok || value == "" {
+			if v.Required && v.Default == "" {
+				return "", fmt.Errorf("variable %s is required", v.displayName())
+			}
+
+			resolved[v.Name] = v.Default
+			continue
+		}
+
+		if err := v.check(value); err != nil {
+			return "", fmt.Errorf("variable %s: %w", v.displayName(), err)
+		}
+
+		resolved[v.Name] = value
+	}
+
+	return variablePattern.ReplaceAllStringFunc(content, func(placeholder string) string {
+		name := variablePattern.FindStringSubmatch(placeholder)[1]
+		if value, ok := resolved[name]; ok {
+			return value
+		}
+
+		return placeholder
+	}), nil
+}
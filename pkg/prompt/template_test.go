@@ -0,0 +1,58 @@
+package prompt_test
+
+import (
+	"testing"
+
+	"github.com/mylxsw/aidea-server/pkg/prompt"
+	"github.com/mylxsw/go-utils/assert"
+)
+
+func float(v float64) *float64 { return &v }
+
+func TestValidate(t *testing.T) {
+	vars := []prompt.Variable{
+		{Name: "topic", Type: prompt.VariableTypeText, Required: true},
+		{Name: "tone", Type: prompt.VariableTypeSelect, Options: []string{"formal", "casual"}, Default: "formal"},
+		{Name: "words", Type: prompt.VariableTypeNumber, Min: float(50), Max: float(2000)},
+	}
+
+	assert.NoError(t, prompt.Validate("Write about {{topic}} in a {{ tone }} tone within {{words}} words", vars))
+	assert.Equal(t, []string{"topic", "tone"}, prompt.Placeholders("{{topic}} {{tone}} {{topic}}"))
+
+	assert.True(t, prompt.Validate("{{missing}}", vars) != nil)
+	assert.True(t, prompt.Validate("", append(vars, prompt.Variable{Name: "topic", Type: prompt.VariableTypeText})) != nil)
+	assert.True(t, prompt.Validate("", []prompt.Variable{{Name: "1abc", Type: prompt.VariableTypeText}}) != nil)
+	assert.True(t, prompt.Validate("", []prompt.Variable{{Name: "a", Type: "date"}}) != nil)
+	assert.True(t, prompt.Validate("", []prompt.Variable{{Name: "a", Type: prompt.VariableTypeSelect}}) != nil)
+	assert.True(t, prompt.Validate("", []prompt.Variable{{Name: "a", Type: prompt.VariableTypeSelect, Options: []string{"x"}, Default: "y"}}) != nil)
+	assert.True(t, prompt.Validate("", []prompt.Variable{{Name: "a", Type: prompt.VariableTypeNumber, Min: float(10), Max: float(1)}}) != nil)
+}
+
+func TestRender(t *testing.T) {
+	vars := []prompt.Variable{
+		{Name: "topic", Label: "主题", Type: prompt.VariableTypeText, Required: true},
+		{Name: "tone", Type: prompt.VariableTypeSelect, Options: []string{"formal", "casual"}, Default: "formal"},
+		{Name: "words", Type: prompt.VariableTypeNumber, Min: float(50), Max: float(2000)},
+	}
+	content := "Write about {{topic}} in a {{ tone }} tone within {{words}} words"
+
+	text, err := prompt.Render(content, vars, map[string]string{"topic": "Go", "words": "300"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Write about Go in a formal tone within 300 words", text)
+
+	text, err = prompt.Render(content, vars, map[string]string{"topic": "Go", "tone": "casual", "unknown": "x"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Write about Go in a casual tone within  words", text)
+
+	_, err = prompt.Render(content, vars, map[string]string{"topic": "  "})
+	assert.True(t, err != nil)
+
+	_, err = prompt.Render(content, vars, map[string]string{"topic": "Go", "tone": "angry"})
+	assert.True(t, err != nil)
+
+	_, err = prompt.Render(content, vars, map[string]string{"topic": "Go", "words": "abc"})
+	assert.True(t, err != nil)
+
+	_, err = prompt.Render(content, vars, map[string]string{"topic": "Go", "words": "10"})
+	assert.True(t, err != nil)
+}
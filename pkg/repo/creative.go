@@ -53,8 +53,13 @@ const (
 	CreativeStatusFailed     CreativeStatus = 4
 	// CreativeStatusForbid  资源封禁
 	CreativeStatusForbid CreativeStatus = 5
+	// CreativeStatusCancelled 用户取消
+	CreativeStatusCancelled CreativeStatus = 6
 )
 
+// ErrCreativeRecordCancelled 创作岛任务已经被用户取消
+var ErrCreativeRecordCancelled = errors.New("creative record cancelled")
+
 type CreativeRepo struct {
 	db                         *sql.DB
 	recordStatusUpdateCallback func(taskID string, userID int64, status CreativeStatus)
@@ -305,6 +310,24 @@ func (r *CreativeRepo) UpdateRecordByID(ctx context.Context, userId, id int64, a
 	return err
 }
 
+// CancelRecord 取消等待中或者处理中的创作岛任务，任务已经结束时返回 false
+// 状态变更使用条件更新，与任务完成时的状态更新互斥，保证任务只会被取消或者完成其中之一
+func (r *CreativeRepo) CancelRecord(ctx context.Context, userId, id int64) (bool, error) {
+	q := query.Builder().Where(model.FieldCreativeHistoryId, id).
+		Where(model.FieldCreativeHistoryUserId, userId).
+		WhereIn(model.FieldCreativeHistoryStatus, []int64{int64(CreativeStatusPending), int64(CreativeStatusProcessing)})
+
+	affected, err := model.NewCreativeHistoryModel(r.db).Update(ctx, q, model.CreativeHistoryN{
+		Status: null.IntFrom(int64(CreativeStatusCancelled)),
+		Answer: null.StringFrom("任务已取消"),
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
 func (r *CreativeRepo) UpdateRecordStatusByID(ctx context.Context, id int64, answer string, status CreativeStatus) error {
 	q := query.Builder().Where(model.FieldCreativeHistoryId, id)
 	_, err := model.NewCreativeHistoryModel(r.db).Update(ctx, q, model.CreativeHistoryN{
@@ -344,6 +367,43 @@ type CreativeRecordUpdateRequest struct {
 type CreativeRecordUpdateExtArgs struct {
 	RealPrompt         string `json:"real_prompt,omitempty"`
 	RealNegativePrompt string `json:"real_negative_prompt,omitempty"`
+	// Sampler 实际使用的采样器
+	Sampler string `json:"sampler,omitempty"`
+	// CfgScale 实际使用的提示语相关性
+	CfgScale float64 `json:"cfg_scale,omitempty"`
+	// Seeds 服务商返回的每张图片实际使用的种子
+	Seeds []int64 `json:"seeds,omitempty"`
+	// FallbackVendor 切换到的备用服务商
+	FallbackVendor string `json:"fallback_vendor,omitempty"`
+	// FallbackModel 切换到的备用服务商模型
+	FallbackModel string `json:"fallback_model,omitempty"`
+}
+
+// Apply 将生成时实际使用的参数合并到创作岛历史记录参数中，空值不覆盖原有参数
+func (ext CreativeRecordUpdateExtArgs) Apply(arg *CreativeRecordArguments) {
+	if ext.RealPrompt != "" {
+		arg.RealPrompt = ext.RealPrompt
+	}
+
+	if ext.RealNegativePrompt != "" {
+		arg.RealNegativePrompt = ext.RealNegativePrompt
+	}
+
+	if ext.Sampler != "" {
+		arg.Sampler = ext.Sampler
+	}
+
+	if ext.CfgScale > 0 {
+		arg.CfgScale = ext.CfgScale
+	}
+
+	if len(ext.Seeds) > 0 {
+		arg.Seeds = ext.Seeds
+	}
+
+	if ext.FallbackVendor != "" {
+		arg.FallbackVendor, arg.FallbackModel = ext.FallbackVendor, ext.FallbackModel
+	}
 }
 
 func (r *CreativeRepo) UpdateRecordArgumentsByTaskID(ctx context.Context, userId int64, taskID string, ext CreativeRecordUpdateExtArgs) error {
@@ -362,13 +422,7 @@ func (r *CreativeRepo) UpdateRecordArgumentsByTaskID(ctx context.Context, userId
 		}
 	}
 
-	if ext.RealPrompt != "" {
-		arg.RealPrompt = ext.RealPrompt
-	}
-
-	if ext.RealNegativePrompt != "" {
-		arg.RealNegativePrompt = ext.RealNegativePrompt
-	}
+	ext.Apply(&arg)
 
 	argData, _ := json.Marshal(arg)
 	update := model.CreativeHistoryN{
@@ -394,8 +448,10 @@ func (r *CreativeRepo) UpdateRecordByTaskID(ctx context.Context, userId int64, t
 		}
 	}()
 
+	// 已经取消的任务不再更新，避免任务完成后覆盖取消状态
 	q := query.Builder().Where(model.FieldCreativeHistoryTaskId, taskID).
-		Where(model.FieldCreativeHistoryUserId, userId)
+		Where(model.FieldCreativeHistoryUserId, userId).
+		Where(model.FieldCreativeHistoryStatus, "!=", int64(CreativeStatusCancelled))
 
 	update := model.CreativeHistoryN{
 		Answer:    null.StringFrom(req.Answer),
@@ -406,6 +462,12 @@ func (r *CreativeRepo) UpdateRecordByTaskID(ctx context.Context, userId int64, t
 	if req.ExtArguments != nil {
 		original, err := model.NewCreativeHistoryModel(r.db).First(ctx, q)
 		if err != nil {
+			if errors.Is(err, query.ErrNoResult) {
+				if err := r.checkRecordCancelled(ctx, userId, taskID); err != nil {
+					return err
+				}
+			}
+
 			return err
 		}
 
@@ -416,20 +478,40 @@ func (r *CreativeRepo) UpdateRecordByTaskID(ctx context.Context, userId int64, t
 			}
 		}
 
-		if req.ExtArguments.RealPrompt != "" {
-			arg.RealPrompt = req.ExtArguments.RealPrompt
-		}
-
-		if req.ExtArguments.RealNegativePrompt != "" {
-			arg.RealNegativePrompt = req.ExtArguments.RealNegativePrompt
-		}
+		req.ExtArguments.Apply(&arg)
 
 		argData, _ := json.Marshal(arg)
 		update.Arguments = null.StringFrom(string(argData))
 	}
 
-	_, err := model.NewCreativeHistoryModel(r.db).Update(ctx, q, update)
-	return err
+	affected, err := model.NewCreativeHistoryModel(r.db).Update(ctx, q, update)
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return r.checkRecordCancelled(ctx, userId, taskID)
+	}
+
+	return nil
+}
+
+// checkRecordCancelled 检查任务是否已经被用户取消，已取消时返回 ErrCreativeRecordCancelled
+func (r *CreativeRepo) checkRecordCancelled(ctx context.Context, userId int64, taskID string) error {
+	q := query.Builder().Where(model.FieldCreativeHistoryTaskId, taskID).
+		Where(model.FieldCreativeHistoryUserId, userId).
+		Where(model.FieldCreativeHistoryStatus, int64(CreativeStatusCancelled))
+
+	count, err := model.NewCreativeHistoryModel(r.db).Count(ctx, q)
+	if err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return ErrCreativeRecordCancelled
+	}
+
+	return nil
 }
 
 func (r *CreativeRepo) FindHistoryRecordByTaskId(ctx context.Context, userId int64, taskId string) (*model.CreativeHistory, error) {
@@ -467,18 +549,20 @@ func (r *CreativeRepo) FindHistoryRecord(ctx context.Context, userId, id int64)
 	}
 
 	return &CreativeHistoryItem{
-		Id:         item.Id.ValueOrZero(),
-		UserID:     item.UserId.ValueOrZero(),
-		IslandId:   item.IslandId.ValueOrZero(),
-		IslandType: item.IslandType.ValueOrZero(),
-		Arguments:  item.Arguments.ValueOrZero(),
-		Prompt:     item.Prompt.ValueOrZero(),
-		Answer:     item.Answer.ValueOrZero(),
-		QuotaUsed:  item.QuotaUsed.ValueOrZero(),
-		Status:     item.Status.ValueOrZero(),
-		Shared:     item.Shared.ValueOrZero(),
-		CreatedAt:  item.CreatedAt.ValueOrZero(),
-		UpdatedAt:  item.UpdatedAt.ValueOrZero(),
+		Id:          item.Id.ValueOrZero(),
+		UserID:      item.UserId.ValueOrZero(),
+		IslandId:    item.IslandId.ValueOrZero(),
+		IslandType:  item.IslandType.ValueOrZero(),
+		IslandModel: item.IslandModel.ValueOrZero(),
+		TaskID:      item.TaskId.ValueOrZero(),
+		Arguments:   item.Arguments.ValueOrZero(),
+		Prompt:      item.Prompt.ValueOrZero(),
+		Answer:      item.Answer.ValueOrZero(),
+		QuotaUsed:   item.QuotaUsed.ValueOrZero(),
+		Status:      item.Status.ValueOrZero(),
+		Shared:      item.Shared.ValueOrZero(),
+		CreatedAt:   item.CreatedAt.ValueOrZero(),
+		UpdatedAt:   item.UpdatedAt.ValueOrZero(),
 	}, nil
 }
 
@@ -489,6 +573,7 @@ type CreativeHistoryItem struct {
 	IslandName  string    `json:"island_name,omitempty"`
 	IslandTitle string    `json:"island_title,omitempty"`
 	IslandModel string    `json:"-"`
+	TaskID      string    `json:"-"`
 	Arguments   string    `json:"arguments,omitempty"`
 	Prompt      string    `json:"prompt,omitempty"`
 	Answer      string    `json:"answer,omitempty"`
@@ -678,6 +763,36 @@ type CreativeRecordArguments struct {
 	ArtisticType       string   `json:"artistic_type,omitempty"`
 	CfgScale           float64  `json:"cfg_scale,omitempty"`
 	MotionBucketID     int      `json:"motion_bucket_id,omitempty"`
+	// EditMode 图片编辑模式：inpaint（局部重绘）、outpaint（扩图），为空时为文生图或者图生图
+	EditMode string `json:"edit_mode,omitempty"`
+	// Mask 局部重绘的蒙版图片地址
+	Mask string `json:"mask,omitempty"`
+	// Outpaint 扩图时四个方向扩展的像素值
+	Outpaint *ImageExtent `json:"outpaint,omitempty"`
+	// Vendor 图片生成服务商
+	Vendor string `json:"vendor,omitempty"`
+	// Sampler 采样器
+	Sampler string `json:"sampler,omitempty"`
+	// ImageStrength 图生图时参考图片的影响程度
+	ImageStrength float64 `json:"image_strength,omitempty"`
+	// Seeds 每张图片实际使用的种子，服务商返回时才有
+	Seeds []int64 `json:"seeds,omitempty"`
+	// RerunFrom 从哪条历史记录重新生成
+	RerunFrom int64 `json:"rerun_from,omitempty"`
+	// VariationOf 基于哪条历史记录生成的变体
+	VariationOf int64 `json:"variation_of,omitempty"`
+	// FallbackVendor 服务商生成失败后，实际生成图片的备用服务商
+	FallbackVendor string `json:"fallback_vendor,omitempty"`
+	// FallbackModel 备用服务商实际使用的模型
+	FallbackModel string `json:"fallback_model,omitempty"`
+}
+
+// ImageExtent 扩图时图片四个方向扩展的像素值
+type ImageExtent struct {
+	Left   int64 `json:"left,omitempty"`
+	Top    int64 `json:"top,omitempty"`
+	Right  int64 `json:"right,omitempty"`
+	Bottom int64 `json:"bottom,omitempty"`
 }
 
 func (arg CreativeRecordArguments) ToGalleryMeta() GalleryMeta {
@@ -705,6 +820,25 @@ const (
 	CreativeGalleryStatusDeleted = 3
 )
 
+// galleryListFields 作品列表查询的字段
+var galleryListFields = []any{
+	model.FieldCreativeGalleryId,
+	model.FieldCreativeGalleryUserId,
+	model.FieldCreativeGalleryUsername,
+	model.FieldCreativeGalleryCreativeType,
+	model.FieldCreativeGalleryPrompt,
+	model.FieldCreativeGalleryAnswer,
+	model.FieldCreativeGalleryTags,
+	model.FieldCreativeGalleryRefCount,
+	model.FieldCreativeGalleryStarLevel,
+	model.FieldCreativeGalleryHotValue,
+	model.FieldCreativeGalleryLikeCount,
+	model.FieldCreativeGalleryFavoriteCount,
+	model.FieldCreativeGalleryCommentCount,
+	model.FieldCreativeGalleryCreatedAt,
+	model.FieldCreativeGalleryUpdatedAt,
+}
+
 func (r *CreativeRepo) Gallery(ctx context.Context, page, perPage int64) ([]model.CreativeGallery, query.PaginateMeta, error) {
 	ids, meta, err := model.NewCreativeGalleryRandomModel(r.db).Paginate(ctx, page, perPage, query.Builder())
 	if err != nil {
@@ -722,22 +856,9 @@ func (r *CreativeRepo) Gallery(ctx context.Context, page, perPage int64) ([]mode
 
 	q := query.Builder().
 		WhereIn(model.FieldCreativeGalleryId, randomIds).
-		Select(
-			model.FieldCreativeGalleryId,
-			model.FieldCreativeGalleryUserId,
-			model.FieldCreativeGalleryUsername,
-			model.FieldCreativeGalleryCreativeType,
-			model.FieldCreativeGalleryPrompt,
-			model.FieldCreativeGalleryAnswer,
-			model.FieldCreativeGalleryTags,
-			model.FieldCreativeGalleryRefCount,
-			model.FieldCreativeGalleryStarLevel,
-			model.FieldCreativeGalleryHotValue,
-			model.FieldCreativeGalleryCreatedAt,
-			model.FieldCreativeGalleryUpdatedAt,
-		).
+		Select(galleryListFields...).
 		OrderBy(model.FieldCreativeGalleryHotValue, "DESC").
-		OrderByRaw("RAND()")
+		OrderBy(model.FieldCreativeGalleryId, "DESC")
 
 	items, err := model.NewCreativeGalleryModel(r.db).Get(ctx, q)
 	if err != nil {
@@ -895,6 +1016,36 @@ func (r *CreativeRepo) CancelCreativeHistoryShare(ctx context.Context, userID in
 	})
 }
 
+// CreativeShareData 创作岛作品分享链接的数据
+type CreativeShareData struct {
+	HistoryID int64 `json:"history_id"`
+}
+
+// ShareCreativeHistory 为创作岛作品生成分享链接，与分享到发现页不同，只有拿到链接的人才能访问
+func (r *CreativeRepo) ShareCreativeHistory(ctx context.Context, userID int64, historyID int64, opts ShareOptions) (string, error) {
+	if _, err := r.FindHistoryRecord(ctx, userID, historyID); err != nil {
+		return "", err
+	}
+
+	data := CreativeShareData{HistoryID: historyID}
+	return createShare(ctx, r.db, userID, ShareTypeCreative, fmt.Sprintf("creative:%d", historyID), data, opts)
+}
+
+// SharedCreativeHistory 通过分享码查询分享的创作岛作品，受密码保护的分享需要提供访问密码
+func (r *CreativeRepo) SharedCreativeHistory(ctx context.Context, code string, password string) (*CreativeHistoryItem, error) {
+	share, err := accessShare(ctx, r.db, code, ShareTypeCreative, password)
+	if err != nil {
+		return nil, err
+	}
+
+	var data CreativeShareData
+	if err := json.Unmarshal([]byte(share.Data), &data); err != nil {
+		return nil, err
+	}
+
+	return r.FindHistoryRecord(ctx, share.UserId, data.HistoryID)
+}
+
 type ImageModel struct {
 	model.ImageModel
 	ImageMeta ImageModelMeta `json:"image_meta"`
@@ -908,6 +1059,25 @@ type ImageModelMeta struct {
 	IntroURL          string               `json:"intro_url,omitempty"`
 	ArtistStyle       string               `json:"artist_style,omitempty"`
 	RatioDimensions   map[string]Dimension `json:"ratio_dimensions,omitempty"`
+	// ChannelID 使用 channels 表中的渠道生成图片，为 0 时使用配置文件中的服务商配置
+	ChannelID int64 `json:"channel_id,omitempty"`
+	// Workflow ComfyUI 工作流模板（API 格式），支持 {{prompt}}、{{negative_prompt}}、{{width}}、{{height}}、
+	// {{seed}}、{{steps}}、{{image_count}}、{{image}}、{{denoise}}、{{cfg_scale}}、{{sampler}}、{{model}} 变量
+	Workflow string `json:"workflow,omitempty"`
+	// InpaintWorkflow ComfyUI 局部重绘（扩图）使用的工作流模板，额外支持 {{mask}} 变量，为空时使用 Workflow
+	InpaintWorkflow string `json:"inpaint_workflow,omitempty"`
+	// Sampler 采样器名称，Stable Diffusion WebUI 使用，为空时使用 WebUI 的默认值
+	Sampler string `json:"sampler,omitempty"`
+	// CfgScale 提示语相关性，为 0 时使用默认值 7
+	CfgScale float64 `json:"cfg_scale,omitempty"`
+	// Fallbacks 生成失败或者超时后依次尝试的备用服务商模型，生成参数会根据备用服务商的能力自动调整
+	Fallbacks []ImageModelRef `json:"fallbacks,omitempty"`
+}
+
+// ImageModelRef 通过服务商与实际模型名称引用 image_models 表中的模型
+type ImageModelRef struct {
+	Vendor string `json:"vendor"`
+	Model  string `json:"model"`
 }
 
 type Dimension struct {
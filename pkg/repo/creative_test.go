@@ -0,0 +1,37 @@
+package repo_test
+
+import (
+	"testing"
+
+	"github.com/mylxsw/aidea-server/pkg/repo"
+	"github.com/mylxsw/go-utils/assert"
+)
+
+func TestCreativeRecordUpdateExtArgs_Apply(t *testing.T) {
+	arg := repo.CreativeRecordArguments{
+		NegativePrompt:     "blurry",
+		RealPrompt:         "a cat",
+		RealNegativePrompt: "blurry",
+		Seed:               42,
+		CfgScale:           7,
+	}
+
+	repo.CreativeRecordUpdateExtArgs{
+		RealPrompt: "a cute cat, best quality",
+		Sampler:    "euler_a",
+		Seeds:      []int64{42, 43},
+	}.Apply(&arg)
+
+	assert.Equal(t, "a cute cat, best quality", arg.RealPrompt)
+	assert.Equal(t, "blurry", arg.RealNegativePrompt)
+	assert.Equal(t, "euler_a", arg.Sampler)
+	assert.Equal(t, float64(7), arg.CfgScale)
+	assert.Equal(t, []int64{42, 43}, arg.Seeds)
+	assert.Equal(t, int64(42), arg.Seed)
+	assert.Equal(t, "", arg.FallbackVendor)
+
+	repo.CreativeRecordUpdateExtArgs{FallbackVendor: "stabilityai", FallbackModel: "stable-diffusion-xl-1024-v1-0"}.Apply(&arg)
+	assert.Equal(t, "stabilityai", arg.FallbackVendor)
+	assert.Equal(t, "stable-diffusion-xl-1024-v1-0", arg.FallbackModel)
+	assert.Equal(t, "euler_a", arg.Sampler)
+}
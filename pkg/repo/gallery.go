@@ -0,0 +1,583 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mylxsw/aidea-server/pkg/repo/model"
+	"github.com/mylxsw/eloquent"
+	"github.com/mylxsw/eloquent/query"
+	"github.com/mylxsw/go-utils/array"
+)
+
+const (
+	// GalleryInteractionLike 点赞
+	GalleryInteractionLike = "like"
+	// GalleryInteractionFavorite 收藏
+	GalleryInteractionFavorite = "favorite"
+)
+
+const (
+	// GalleryCommentStatusNormal 正常
+	GalleryCommentStatusNormal = 1
+	// GalleryCommentStatusDeleted 用户删除
+	GalleryCommentStatusDeleted = 2
+	// GalleryCommentStatusHidden 管理员屏蔽
+	GalleryCommentStatusHidden = 3
+)
+
+const (
+	// GalleryReportStatusPending 待处理
+	GalleryReportStatusPending = 0
+	// GalleryReportStatusAccepted 举报成立，作品已下架
+	GalleryReportStatusAccepted = 1
+	// GalleryReportStatusRejected 举报已驳回
+	GalleryReportStatusRejected = 2
+)
+
+// 举报原因
+const (
+	GalleryReportReasonPorn      = "porn"
+	GalleryReportReasonViolence  = "violence"
+	GalleryReportReasonPolitics  = "politics"
+	GalleryReportReasonCopyright = "copyright"
+	GalleryReportReasonSpam      = "spam"
+	GalleryReportReasonOther     = "other"
+)
+
+// GalleryReportReasons 举报可选的原因分类
+var GalleryReportReasons = []string{
+	GalleryReportReasonPorn,
+	GalleryReportReasonViolence,
+	GalleryReportReasonPolitics,
+	GalleryReportReasonCopyright,
+	GalleryReportReasonSpam,
+	GalleryReportReasonOther,
+}
+
+const (
+	// GalleryCommentMaxLength 评论内容的最大长度
+	GalleryCommentMaxLength = 500
+	// GalleryReportCommentMaxLength 举报补充说明的最大长度
+	GalleryReportCommentMaxLength = 500
+)
+
+// ErrGalleryReported 用户已经举报过该作品
+var ErrGalleryReported = errors.New("gallery item has already been reported")
+
+// GalleryComment 作品评论
+type GalleryComment struct {
+	ID        int64     `json:"id"`
+	GalleryID int64     `json:"gallery_id"`
+	UserID    int64     `json:"user_id"`
+	Username  string    `json:"username,omitempty"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func createGalleryCommentFromModel(item model.CreativeGalleryCommentN) GalleryComment {
+	return GalleryComment{
+		ID:        item.Id.ValueOrZero(),
+		GalleryID: item.GalleryId.ValueOrZero(),
+		UserID:    item.UserId.ValueOrZero(),
+		Username:  item.Username.ValueOrZero(),
+		Content:   item.Content.ValueOrZero(),
+		CreatedAt: item.CreatedAt.ValueOrZero(),
+	}
+}
+
+// GalleryReportInput 用户提交的举报内容
+type GalleryReportInput struct {
+	Reason  string `json:"reason"`
+	Comment string `json:"comment,omitempty"`
+}
+
+// Validate 检查举报内容是否合法
+func (in GalleryReportInput) Validate() error {
+	if !array.In(in.Reason, GalleryReportReasons) {
+		return fmt.Errorf("invalid reason: %s", in.Reason)
+	}
+
+	if utf8.RuneCountInString(in.Comment) > GalleryReportCommentMaxLength {
+		return fmt.Errorf("comment is too long, at most %d characters", GalleryReportCommentMaxLength)
+	}
+
+	return nil
+}
+
+// GalleryReport 作品举报记录
+type GalleryReport struct {
+	ID        int64     `json:"id"`
+	GalleryID int64     `json:"gallery_id"`
+	UserID    int64     `json:"user_id"`
+	Reason    string    `json:"reason"`
+	Comment   string    `json:"comment,omitempty"`
+	Status    int64     `json:"status"`
+	HandledBy int64     `json:"handled_by,omitempty"`
+	HandledAt time.Time `json:"handled_at,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	// Gallery 被举报的作品
+	Gallery *model.CreativeGallery `json:"gallery,omitempty"`
+}
+
+func createGalleryReportFromModel(item model.CreativeGalleryReportN) GalleryReport {
+	return GalleryReport{
+		ID:        item.Id.ValueOrZero(),
+		GalleryID: item.GalleryId.ValueOrZero(),
+		UserID:    item.UserId.ValueOrZero(),
+		Reason:    item.Reason.ValueOrZero(),
+		Comment:   item.Comment.ValueOrZero(),
+		Status:    item.Status.ValueOrZero(),
+		HandledBy: item.HandledBy.ValueOrZero(),
+		HandledAt: item.HandledAt.ValueOrZero(),
+		CreatedAt: item.CreatedAt.ValueOrZero(),
+	}
+}
+
+// GalleryEngagement 作品的互动数据以及管理员设置，用于计算热度
+type GalleryEngagement struct {
+	Likes     int64
+	Favorites int64
+	Comments  int64
+	// Refs 作品被用户引用（生成同款）的次数
+	Refs int64
+	// StarLevel 管理员设置的星级
+	StarLevel int64
+	// HotBoost 管理员设置的热度加成，不参与时间衰减
+	HotBoost  int64
+	CreatedAt time.Time
+}
+
+const (
+	// galleryHotGravity 热度随时间衰减的速度，值越大衰减越快
+	galleryHotGravity = 1.5
+	// galleryHotScale 热度值放大倍数，热度值存储为整数
+	galleryHotScale = 10000
+)
+
+// GalleryHotValue 根据互动数据计算作品的热度，互动越多热度越高，发布时间越久热度越低
+//
+// 热度 = (互动得分 + 1) / (发布小时数 + 2) ^ galleryHotGravity * galleryHotScale + 管理员热度加成
+func GalleryHotValue(e GalleryEngagement, now time.Time) int64 {
+	points := float64(e.Likes) +
+		float64(e.Favorites)*2 +
+		float64(e.Comments)*1.5 +
+		float64(e.Refs)*3 +
+		float64(e.StarLevel)*10 + 1
+
+	hours := math.Max(now.Sub(e.CreatedAt).Hours(), 0)
+
+	return int64(points/math.Pow(hours+2, galleryHotGravity)*galleryHotScale) + e.HotBoost
+}
+
+type GalleryRepo struct {
+	db *sql.DB
+}
+
+func NewGalleryRepo(db *sql.DB) *GalleryRepo {
+	return &GalleryRepo{db: db}
+}
+
+// visibleGallery 查询公开展示中的作品，作品不存在或者未通过审核时返回 ErrNotFound
+func (r *GalleryRepo) visibleGallery(ctx context.Context, tx query.Database, galleryID int64) (*model.CreativeGalleryN, error) {
+	item, err := model.NewCreativeGalleryModel(tx).First(ctx, query.Builder().
+		Where(model.FieldCreativeGalleryId, galleryID).
+		Where(model.FieldCreativeGalleryStatus, CreativeGalleryStatusOK))
+	if err != nil {
+		if errors.Is(err, query.ErrNoResult) {
+			return nil, ErrNotFound
+		}
+
+		return nil, fmt.Errorf("query gallery failed: %w", err)
+	}
+
+	return item, nil
+}
+
+// incrGalleryCounter 更新作品的计数字段，delta 为负数时不会小于 0
+func incrGalleryCounter(ctx context.Context, tx query.Database, galleryID int64, field string, delta int64) error {
+	_, err := tx.ExecContext(
+		ctx,
+		fmt.Sprintf("UPDATE %s SET %s = GREATEST(IFNULL(%s, 0) + ?, 0) WHERE id = ?", model.CreativeGalleryTable(), field, field),
+		delta, galleryID,
+	)
+	return err
+}
+
+func galleryInteractionCounter(typ string) string {
+	if typ == GalleryInteractionFavorite {
+		return model.FieldCreativeGalleryFavoriteCount
+	}
+
+	return model.FieldCreativeGalleryLikeCount
+}
+
+// AddInteraction 点赞或者收藏作品，重复操作时返回 false
+func (r *GalleryRepo) AddInteraction(ctx context.Context, userID, galleryID int64, typ string) (added bool, err error) {
+	err = eloquent.Transaction(r.db, func(tx query.Database) error {
+		if _, err := r.visibleGallery(ctx, tx, galleryID); err != nil {
+			return err
+		}
+
+		q := query.Builder().
+			Where(model.FieldCreativeGalleryInteractionUserId, userID).
+			Where(model.FieldCreativeGalleryInteractionGalleryId, galleryID).
+			Where(model.FieldCreativeGalleryInteractionType, typ)
+
+		exist, err := model.NewCreativeGalleryInteractionModel(tx).Exists(ctx, q)
+		if err != nil {
+			return fmt.Errorf("query gallery interaction failed: %w", err)
+		}
+
+		if exist {
+			return nil
+		}
+
+		if _, err := model.NewCreativeGalleryInteractionModel(tx).Create(ctx, query.KV{
+			model.FieldCreativeGalleryInteractionUserId:    userID,
+			model.FieldCreativeGalleryInteractionGalleryId: galleryID,
+			model.FieldCreativeGalleryInteractionType:      typ,
+		}); err != nil {
+			return err
+		}
+
+		added = true
+		return incrGalleryCounter(ctx, tx, galleryID, galleryInteractionCounter(typ), 1)
+	})
+
+	return added, err
+}
+
+// RemoveInteraction 取消点赞或者收藏，没有点赞或者收藏过时返回 false
+func (r *GalleryRepo) RemoveInteraction(ctx context.Context, userID, galleryID int64, typ string) (removed bool, err error) {
+	err = eloquent.Transaction(r.db, func(tx query.Database) error {
+		affected, err := model.NewCreativeGalleryInteractionModel(tx).Delete(ctx, query.Builder().
+			Where(model.FieldCreativeGalleryInteractionUserId, userID).
+			Where(model.FieldCreativeGalleryInteractionGalleryId, galleryID).
+			Where(model.FieldCreativeGalleryInteractionType, typ))
+		if err != nil {
+			return err
+		}
+
+		if affected == 0 {
+			return nil
+		}
+
+		removed = true
+		return incrGalleryCounter(ctx, tx, galleryID, galleryInteractionCounter(typ), -1)
+	})
+
+	return removed, err
+}
+
+// UserInteractions 查询用户对作品的互动（点赞、收藏）
+func (r *GalleryRepo) UserInteractions(ctx context.Context, userID, galleryID int64) ([]string, error) {
+	items, err := model.NewCreativeGalleryInteractionModel(r.db).Get(ctx, query.Builder().
+		Select(model.FieldCreativeGalleryInteractionType).
+		Where(model.FieldCreativeGalleryInteractionUserId, userID).
+		Where(model.FieldCreativeGalleryInteractionGalleryId, galleryID))
+	if err != nil {
+		return nil, err
+	}
+
+	return array.Map(items, func(item model.CreativeGalleryInteractionN, _ int) string { return item.Type.ValueOrZero() }), nil
+}
+
+// Favorites 分页查询用户收藏的作品，按照收藏时间倒序排列，已经下架的作品不返回
+func (r *GalleryRepo) Favorites(ctx context.Context, userID, page, perPage int64) ([]model.CreativeGallery, query.PaginateMeta, error) {
+	interactions, meta, err := model.NewCreativeGalleryInteractionModel(r.db).Paginate(ctx, page, perPage, query.Builder().
+		Where(model.FieldCreativeGalleryInteractionUserId, userID).
+		Where(model.FieldCreativeGalleryInteractionType, GalleryInteractionFavorite).
+		OrderBy(model.FieldCreativeGalleryInteractionId, "DESC"))
+	if err != nil {
+		return nil, meta, fmt.Errorf("query gallery favorites failed: %w", err)
+	}
+
+	if len(interactions) == 0 {
+		return []model.CreativeGallery{}, meta, nil
+	}
+
+	ids := array.Map(interactions, func(item model.CreativeGalleryInteractionN, _ int) int64 { return item.GalleryId.ValueOrZero() })
+	galleries, err := model.NewCreativeGalleryModel(r.db).Get(ctx, query.Builder().
+		Select(galleryListFields...).
+		WhereIn(model.FieldCreativeGalleryId, array.Map(ids, func(id int64, _ int) any { return id })...).
+		Where(model.FieldCreativeGalleryStatus, CreativeGalleryStatusOK))
+	if err != nil {
+		return nil, meta, fmt.Errorf("query galleries failed: %w", err)
+	}
+
+	byID := array.ToMap(
+		array.Map(galleries, func(item model.CreativeGalleryN, _ int) model.CreativeGallery { return item.ToCreativeGallery() }),
+		func(item model.CreativeGallery, _ int) int64 { return item.Id },
+	)
+
+	// 保持收藏时间的顺序
+	ret := make([]model.CreativeGallery, 0, len(ids))
+	for _, id := range ids {
+		if item, ok := byID[id]; ok {
+			ret = append(ret, item)
+		}
+	}
+
+	return ret, meta, nil
+}
+
+// Comments 分页查询作品的评论，按照时间倒序排列
+func (r *GalleryRepo) Comments(ctx context.Context, galleryID, page, perPage int64) ([]GalleryComment, query.PaginateMeta, error) {
+	items, meta, err := model.NewCreativeGalleryCommentModel(r.db).Paginate(ctx, page, perPage, query.Builder().
+		Where(model.FieldCreativeGalleryCommentGalleryId, galleryID).
+		Where(model.FieldCreativeGalleryCommentStatus, GalleryCommentStatusNormal).
+		OrderBy(model.FieldCreativeGalleryCommentId, "DESC"))
+	if err != nil {
+		return nil, meta, fmt.Errorf("query gallery comments failed: %w", err)
+	}
+
+	return array.Map(items, func(item model.CreativeGalleryCommentN, _ int) GalleryComment {
+		return createGalleryCommentFromModel(item)
+	}), meta, nil
+}
+
+// AddComment 评论作品，返回评论 ID，作品不存在或者已下架时返回 ErrNotFound
+func (r *GalleryRepo) AddComment(ctx context.Context, userID int64, username string, galleryID int64, content string) (id int64, err error) {
+	err = eloquent.Transaction(r.db, func(tx query.Database) error {
+		if _, err := r.visibleGallery(ctx, tx, galleryID); err != nil {
+			return err
+		}
+
+		id, err = model.NewCreativeGalleryCommentModel(tx).Create(ctx, query.KV{
+			model.FieldCreativeGalleryCommentGalleryId: galleryID,
+			model.FieldCreativeGalleryCommentUserId:    userID,
+			model.FieldCreativeGalleryCommentUsername:  username,
+			model.FieldCreativeGalleryCommentContent:   content,
+			model.FieldCreativeGalleryCommentStatus:    GalleryCommentStatusNormal,
+		})
+		if err != nil {
+			return err
+		}
+
+		return incrGalleryCounter(ctx, tx, galleryID, model.FieldCreativeGalleryCommentCount, 1)
+	})
+
+	return id, err
+}
+
+// DeleteComment 删除评论，userID 不为 0 时只能删除该用户自己的评论，为 0 时为管理员屏蔽评论
+// 评论不存在或者已经删除时返回 ErrNotFound
+func (r *GalleryRepo) DeleteComment(ctx context.Context, userID, commentID int64) error {
+	return eloquent.Transaction(r.db, func(tx query.Database) error {
+		q := query.Builder().
+			Where(model.FieldCreativeGalleryCommentId, commentID).
+			Where(model.FieldCreativeGalleryCommentStatus, GalleryCommentStatusNormal)
+		if userID > 0 {
+			q = q.Where(model.FieldCreativeGalleryCommentUserId, userID)
+		}
+
+		comment, err := model.NewCreativeGalleryCommentModel(tx).First(ctx, q)
+		if err != nil {
+			if errors.Is(err, query.ErrNoResult) {
+				return ErrNotFound
+			}
+
+			return err
+		}
+
+		status := GalleryCommentStatusDeleted
+		if userID == 0 {
+			status = GalleryCommentStatusHidden
+		}
+
+		if _, err := model.NewCreativeGalleryCommentModel(tx).UpdateFields(ctx, query.KV{
+			model.FieldCreativeGalleryCommentStatus: status,
+		}, query.Builder().Where(model.FieldCreativeGalleryCommentId, commentID)); err != nil {
+			return err
+		}
+
+		return incrGalleryCounter(ctx, tx, comment.GalleryId.ValueOrZero(), model.FieldCreativeGalleryCommentCount, -1)
+	})
+}
+
+// Report 举报作品，每个用户只能举报同一个作品一次，重复举报时返回 ErrGalleryReported
+func (r *GalleryRepo) Report(ctx context.Context, userID, galleryID int64, in GalleryReportInput) error {
+	return eloquent.Transaction(r.db, func(tx query.Database) error {
+		if _, err := r.visibleGallery(ctx, tx, galleryID); err != nil {
+			return err
+		}
+
+		exist, err := model.NewCreativeGalleryReportModel(tx).Exists(ctx, query.Builder().
+			Where(model.FieldCreativeGalleryReportUserId, userID).
+			Where(model.FieldCreativeGalleryReportGalleryId, galleryID))
+		if err != nil {
+			return fmt.Errorf("query gallery report failed: %w", err)
+		}
+
+		if exist {
+			return ErrGalleryReported
+		}
+
+		if _, err := model.NewCreativeGalleryReportModel(tx).Create(ctx, query.KV{
+			model.FieldCreativeGalleryReportGalleryId: galleryID,
+			model.FieldCreativeGalleryReportUserId:    userID,
+			model.FieldCreativeGalleryReportReason:    in.Reason,
+			model.FieldCreativeGalleryReportComment:   in.Comment,
+			model.FieldCreativeGalleryReportStatus:    GalleryReportStatusPending,
+		}); err != nil {
+			return err
+		}
+
+		return incrGalleryCounter(ctx, tx, galleryID, model.FieldCreativeGalleryReportCount, 1)
+	})
+}
+
+// Reports 举报审核队列，分页查询指定状态的举报记录，待处理的举报按照时间先后排列
+func (r *GalleryRepo) Reports(ctx context.Context, status, page, perPage int64) ([]GalleryReport, query.PaginateMeta, error) {
+	q := query.Builder().Where(model.FieldCreativeGalleryReportStatus, status)
+	if status == GalleryReportStatusPending {
+		q = q.OrderBy(model.FieldCreativeGalleryReportId, "ASC")
+	} else {
+		q = q.OrderBy(model.FieldCreativeGalleryReportId, "DESC")
+	}
+
+	items, meta, err := model.NewCreativeGalleryReportModel(r.db).Paginate(ctx, page, perPage, q)
+	if err != nil {
+		return nil, meta, fmt.Errorf("query gallery reports failed: %w", err)
+	}
+
+	reports := array.Map(items, func(item model.CreativeGalleryReportN, _ int) GalleryReport {
+		return createGalleryReportFromModel(item)
+	})
+	if len(reports) == 0 {
+		return reports, meta, nil
+	}
+
+	galleryIDs := array.Uniq(array.Map(reports, func(item GalleryReport, _ int) any { return item.GalleryID }))
+	galleries, err := model.NewCreativeGalleryModel(r.db).Get(ctx, query.Builder().
+		Select(galleryListFields...).
+		WhereIn(model.FieldCreativeGalleryId, galleryIDs...))
+	if err != nil {
+		return nil, meta, fmt.Errorf("query galleries failed: %w", err)
+	}
+
+	byID := array.ToMap(
+		array.Map(galleries, func(item model.CreativeGalleryN, _ int) model.CreativeGallery { return item.ToCreativeGallery() }),
+		func(item model.CreativeGallery, _ int) int64 { return item.Id },
+	)
+
+	for i := range reports {
+		if item, ok := byID[reports[i].GalleryID]; ok {
+			reports[i].Gallery = &item
+		}
+	}
+
+	return reports, meta, nil
+}
+
+// HandleReports 处理作品所有待处理的举报，accept 为 true 时举报成立，作品下架，否则驳回举报
+// 返回处理的举报数量，没有待处理的举报时返回 ErrNotFound
+func (r *GalleryRepo) HandleReports(ctx context.Context, adminID, galleryID int64, accept bool) (handled int64, err error) {
+	err = eloquent.Transaction(r.db, func(tx query.Database) error {
+		status := GalleryReportStatusRejected
+		if accept {
+			status = GalleryReportStatusAccepted
+		}
+
+		handled, err = model.NewCreativeGalleryReportModel(tx).UpdateFields(ctx, query.KV{
+			model.FieldCreativeGalleryReportStatus:    status,
+			model.FieldCreativeGalleryReportHandledBy: adminID,
+			model.FieldCreativeGalleryReportHandledAt: time.Now(),
+		}, query.Builder().
+			Where(model.FieldCreativeGalleryReportGalleryId, galleryID).
+			Where(model.FieldCreativeGalleryReportStatus, GalleryReportStatusPending))
+		if err != nil {
+			return err
+		}
+
+		if handled == 0 {
+			return ErrNotFound
+		}
+
+		kv := query.KV{model.FieldCreativeGalleryReportCount: 0}
+		if accept {
+			kv[model.FieldCreativeGalleryStatus] = CreativeGalleryStatusDenied
+		}
+
+		_, err = model.NewCreativeGalleryModel(tx).UpdateFields(ctx, kv, query.Builder().Where(model.FieldCreativeGalleryId, galleryID))
+		return err
+	})
+
+	return handled, err
+}
+
+// GalleryRanking 管理员设置的作品排序参数
+type GalleryRanking struct {
+	// HotBoost 热度加成，可以为负数，用于置顶或者压制作品
+	HotBoost int64 `json:"hot_boost"`
+	// StarLevel 星级，星级越高热度越高
+	StarLevel int64 `json:"star_level"`
+}
+
+// UpdateRanking 更新管理员设置的作品排序参数，在下次排序任务执行时生效
+func (r *GalleryRepo) UpdateRanking(ctx context.Context, galleryID int64, ranking GalleryRanking) error {
+	affected, err := model.NewCreativeGalleryModel(r.db).UpdateFields(ctx, query.KV{
+		model.FieldCreativeGalleryHotBoost:  ranking.HotBoost,
+		model.FieldCreativeGalleryStarLevel: ranking.StarLevel,
+	}, query.Builder().Where(model.FieldCreativeGalleryId, galleryID))
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// RefreshHotValues 根据互动数据以及管理员设置重新计算所有公开作品的热度
+func (r *GalleryRepo) RefreshHotValues(ctx context.Context) error {
+	galleries, err := model.NewCreativeGalleryModel(r.db).Get(ctx, query.Builder().
+		Select(
+			model.FieldCreativeGalleryId,
+			model.FieldCreativeGalleryLikeCount,
+			model.FieldCreativeGalleryFavoriteCount,
+			model.FieldCreativeGalleryCommentCount,
+			model.FieldCreativeGalleryRefCount,
+			model.FieldCreativeGalleryStarLevel,
+			model.FieldCreativeGalleryHotBoost,
+			model.FieldCreativeGalleryHotValue,
+			model.FieldCreativeGalleryCreatedAt,
+		).
+		Where(model.FieldCreativeGalleryStatus, CreativeGalleryStatusOK))
+	if err != nil {
+		return fmt.Errorf("query galleries failed: %w", err)
+	}
+
+	now := time.Now()
+	for _, gallery := range galleries {
+		hotValue := GalleryHotValue(GalleryEngagement{
+			Likes:     gallery.LikeCount.ValueOrZero(),
+			Favorites: gallery.FavoriteCount.ValueOrZero(),
+			Comments:  gallery.CommentCount.ValueOrZero(),
+			Refs:      gallery.RefCount.ValueOrZero(),
+			StarLevel: gallery.StarLevel.ValueOrZero(),
+			HotBoost:  gallery.HotBoost.ValueOrZero(),
+			CreatedAt: gallery.CreatedAt.ValueOrZero(),
+		}, now)
+
+		if hotValue == gallery.HotValue.ValueOrZero() {
+			continue
+		}
+
+		if _, err := model.NewCreativeGalleryModel(r.db).UpdateFields(ctx, query.KV{
+			model.FieldCreativeGalleryHotValue: hotValue,
+		}, query.Builder().Where(model.FieldCreativeGalleryId, gallery.Id.ValueOrZero())); err != nil {
+			return fmt.Errorf("update gallery hot value failed: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,33 @@
+package repo_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mylxsw/aidea-server/pkg/repo"
+	"github.com/mylxsw/go-utils/assert"
+)
+
+func TestGalleryHotValue(t *testing.T) {
+	now := time.Now()
+
+	fresh := repo.GalleryEngagement{Likes: 10, CreatedAt: now.Add(-2 * time.Hour)}
+	stale := repo.GalleryEngagement{Likes: 10, CreatedAt: now.Add(-48 * time.Hour)}
+	popular := repo.GalleryEngagement{Likes: 10, Favorites: 5, Comments: 4, CreatedAt: now.Add(-2 * time.Hour)}
+
+	// 互动相同时，发布时间越久热度越低
+	assert.True(t, repo.GalleryHotValue(fresh, now) > repo.GalleryHotValue(stale, now))
+	// 发布时间相同时，互动越多热度越高
+	assert.True(t, repo.GalleryHotValue(popular, now) > repo.GalleryHotValue(fresh, now))
+
+	// 管理员热度加成不参与时间衰减
+	boosted := stale
+	boosted.HotBoost = 100000
+	assert.Equal(t, repo.GalleryHotValue(stale, now)+100000, repo.GalleryHotValue(boosted, now))
+	assert.True(t, repo.GalleryHotValue(boosted, now) > repo.GalleryHotValue(popular, now))
+}
+
+func TestGalleryReportInput_Validate(t *testing.T) {
+	assert.NoError(t, repo.GalleryReportInput{Reason: repo.GalleryReportReasonSpam}.Validate())
+	assert.True(t, repo.GalleryReportInput{Reason: "unknown"}.Validate() != nil)
+}
@@ -3,15 +3,20 @@ package repo
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/mylxsw/aidea-server/pkg/misc"
 	model2 "github.com/mylxsw/aidea-server/pkg/repo/model"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/mylxsw/asteria/log"
 	"github.com/mylxsw/eloquent"
 	"github.com/mylxsw/eloquent/query"
 	"github.com/mylxsw/go-utils/array"
+	"github.com/mylxsw/go-utils/ternary"
 	"gopkg.in/guregu/null.v3"
 )
 
@@ -24,9 +29,84 @@ func NewChatGroupRepo(db *sql.DB) *ChatGroupRepo {
 }
 
 type Member struct {
-	ID        int    `json:"id,omitempty"`
-	ModelID   string `json:"model_id"`
-	ModelName string `json:"model_name,omitempty"`
+	ID        int            `json:"id,omitempty"`
+	ModelID   string         `json:"model_id"`
+	ModelName string         `json:"model_name,omitempty"`
+	Persona   *MemberPersona `json:"persona,omitempty"`
+}
+
+const (
+	// MemberVisibilityAll 成员可以看到所有成员的回复
+	MemberVisibilityAll = "all"
+	// MemberVisibilitySelf 成员只能看到自己的回复
+	MemberVisibilitySelf = "self"
+	// MemberVisibilityMembers 成员只能看到自己以及指定成员的回复
+	MemberVisibilityMembers = "members"
+)
+
+// MemberPersona 群组成员的人设
+type MemberPersona struct {
+	// SystemPrompt 成员的系统提示语
+	SystemPrompt string `json:"system_prompt,omitempty"`
+	// Temperature 成员回复时使用的温度，为 0 时使用模型默认值
+	Temperature float64 `json:"temperature,omitempty"`
+	// Visibility 成员能够看到哪些成员的回复：all/self/members，为空时等同于 all
+	Visibility string `json:"visibility,omitempty"`
+	// VisibleMemberIDs Visibility 为 members 时，成员能够看到的其它成员 ID
+	VisibleMemberIDs []int64 `json:"visible_member_ids,omitempty"`
+}
+
+// CanSee 判断成员是否能够看到指定成员的回复
+func (p MemberPersona) CanSee(selfID, memberID int64) bool {
+	if selfID == memberID {
+		return true
+	}
+
+	switch p.Visibility {
+	case MemberVisibilitySelf:
+		return false
+	case MemberVisibilityMembers:
+		return array.In(memberID, p.VisibleMemberIDs)
+	default:
+		return true
+	}
+}
+
+// Validate 检查人设是否合法
+func (p MemberPersona) Validate() error {
+	if p.Temperature < 0 || p.Temperature > 2 {
+		return errors.New("temperature must be between 0 and 2")
+	}
+
+	switch p.Visibility {
+	case "", MemberVisibilityAll, MemberVisibilitySelf, MemberVisibilityMembers:
+	default:
+		return fmt.Errorf("invalid visibility: %s", p.Visibility)
+	}
+
+	return nil
+}
+
+// MemberPersonaOf 解析群组成员的人设
+func MemberPersonaOf(mem model2.ChatGroupMember) MemberPersona {
+	var persona MemberPersona
+	if mem.PersonaJson != "" {
+		if err := json.Unmarshal([]byte(mem.PersonaJson), &persona); err != nil {
+			log.F(log.M{"member_id": mem.Id}).Errorf("unmarshal member persona failed: %s", err)
+		}
+	}
+
+	return persona
+}
+
+// encodeMemberPersona 将成员人设编码为 JSON，未设置人设时返回 NULL（JSON 类型的字段不允许写入空字符串）
+func encodeMemberPersona(persona *MemberPersona) null.String {
+	if persona == nil {
+		return null.String{}
+	}
+
+	data, _ := json.Marshal(persona)
+	return null.StringFrom(string(data))
 }
 
 const (
@@ -65,11 +145,12 @@ func (repo *ChatGroupRepo) CreateGroup(ctx context.Context, userID int64, name s
 
 		for _, member := range members {
 			if _, err := model2.NewChatGroupMemberModel(tx).Create(ctx, query.KV{
-				model2.FieldChatGroupMemberGroupId:   gid,
-				model2.FieldChatGroupMemberUserId:    userID,
-				model2.FieldChatGroupMemberModelId:   member.ModelID,
-				model2.FieldChatGroupMemberModelName: member.ModelName,
-				model2.FieldChatGroupMemberStatus:    ChatGroupMemberStatusNormal,
+				model2.FieldChatGroupMemberGroupId:     gid,
+				model2.FieldChatGroupMemberUserId:      userID,
+				model2.FieldChatGroupMemberModelId:     member.ModelID,
+				model2.FieldChatGroupMemberModelName:   member.ModelName,
+				model2.FieldChatGroupMemberPersonaJson: encodeMemberPersona(member.Persona),
+				model2.FieldChatGroupMemberStatus:      ChatGroupMemberStatusNormal,
 			}); err != nil {
 				return fmt.Errorf("create group member failed: %w", err)
 			}
@@ -113,10 +194,12 @@ func (repo *ChatGroupRepo) GetMembers(ctx context.Context, memberIds []int64) ([
 	}
 
 	return array.Map(members, func(m model2.ChatGroupMemberN, _ int) Member {
+		persona := MemberPersonaOf(m.ToChatGroupMember())
 		return Member{
 			ID:        int(m.Id.ValueOrZero()),
 			ModelID:   m.ModelId.ValueOrZero(),
 			ModelName: m.ModelName.ValueOrZero(),
+			Persona:   &persona,
 		}
 	}), nil
 }
@@ -143,6 +226,10 @@ func (repo *ChatGroupRepo) UpdateGroupMembers(ctx context.Context, groupID int64
 				member.ModelId = null.StringFrom(modifyMember.ModelID)
 				member.ModelName = null.StringFrom(modifyMember.ModelName)
 				member.Status = null.IntFrom(ChatGroupMemberStatusNormal)
+				// 未指定人设时，保留成员原有的人设
+				if modifyMember.Persona != nil {
+					member.PersonaJson = encodeMemberPersona(modifyMember.Persona)
+				}
 				currentMembers[i] = member
 			}
 		}
@@ -151,11 +238,12 @@ func (repo *ChatGroupRepo) UpdateGroupMembers(ctx context.Context, groupID int64
 		for _, member := range members {
 			if _, ok := currentMembersMap[member.ModelID]; !ok {
 				mem := model2.ChatGroupMemberN{
-					GroupId:   null.IntFrom(groupID),
-					UserId:    null.IntFrom(userID),
-					ModelId:   null.StringFrom(member.ModelID),
-					ModelName: null.StringFrom(member.ModelName),
-					Status:    null.IntFrom(ChatGroupMemberStatusNormal),
+					GroupId:     null.IntFrom(groupID),
+					UserId:      null.IntFrom(userID),
+					ModelId:     null.StringFrom(member.ModelID),
+					ModelName:   null.StringFrom(member.ModelName),
+					PersonaJson: encodeMemberPersona(member.Persona),
+					Status:      null.IntFrom(ChatGroupMemberStatusNormal),
 				}
 
 				mem.SetModel(model2.NewChatGroupMemberModel(tx))
@@ -179,10 +267,11 @@ func (repo *ChatGroupRepo) AddMembersToGroup(ctx context.Context, groupID, userI
 	return eloquent.Transaction(repo.db, func(tx query.Database) error {
 		for _, member := range members {
 			if _, err := model2.NewChatGroupMemberModel(tx).Create(ctx, query.KV{
-				model2.FieldChatGroupMemberGroupId:   groupID,
-				model2.FieldChatGroupMemberModelId:   member.ModelID,
-				model2.FieldChatGroupMemberModelName: member.ModelName,
-				model2.FieldChatGroupMemberStatus:    ChatGroupMemberStatusNormal,
+				model2.FieldChatGroupMemberGroupId:     groupID,
+				model2.FieldChatGroupMemberModelId:     member.ModelID,
+				model2.FieldChatGroupMemberModelName:   member.ModelName,
+				model2.FieldChatGroupMemberPersonaJson: encodeMemberPersona(member.Persona),
+				model2.FieldChatGroupMemberStatus:      ChatGroupMemberStatusNormal,
 			}); err != nil {
 				return fmt.Errorf("create group member failed: %w", err)
 			}
@@ -209,6 +298,27 @@ func (repo *ChatGroupRepo) RemoveMembersFromGroup(ctx context.Context, groupID,
 	})
 }
 
+// UpdateMemberPersona 更新群组成员的人设
+func (repo *ChatGroupRepo) UpdateMemberPersona(ctx context.Context, groupID, userID, memberID int64, persona MemberPersona) error {
+	q := query.Builder().Where(model2.FieldChatGroupMemberGroupId, groupID).
+		Where(model2.FieldChatGroupMemberUserId, userID).
+		Where(model2.FieldChatGroupMemberStatus, ChatGroupMemberStatusNormal).
+		Where(model2.FieldChatGroupMemberId, memberID)
+
+	affected, err := model2.NewChatGroupMemberModel(repo.db).UpdateFields(ctx, query.KV{
+		model2.FieldChatGroupMemberPersonaJson: encodeMemberPersona(&persona),
+	}, q)
+	if err != nil {
+		return fmt.Errorf("update member persona failed: %w", err)
+	}
+
+	if affected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
 type Group struct {
 	Group   model2.Rooms             `json:"group"`
 	Members []model2.ChatGroupMember `json:"members"`
@@ -462,6 +572,10 @@ type ChatGroupMessageUpdate struct {
 	QuotaConsumed int64  `json:"quota_consumed,omitempty"`
 	Status        int64  `json:"status,omitempty"`
 	Error         string `json:"error,omitempty"`
+	// Channel 实际回答问题的渠道
+	Channel string `json:"-"`
+	// Model 实际回答问题的模型
+	Model string `json:"-"`
 }
 
 // UpdateChatMessage 更新聊天消息
@@ -478,6 +592,8 @@ func (repo *ChatGroupRepo) UpdateChatMessage(ctx context.Context, groupID, userI
 			model2.FieldChatGroupMessageQuotaConsumed: msg.QuotaConsumed,
 			model2.FieldChatGroupMessageStatus:        msg.Status,
 			model2.FieldChatGroupMessageError:         msg.Error,
+			model2.FieldChatGroupMessageChannel:       msg.Channel,
+			model2.FieldChatGroupMessageModel:         msg.Model,
 		}, q)
 
 		return err
@@ -497,6 +613,13 @@ func (repo *ChatGroupRepo) DeleteGroup(ctx context.Context, groupID, userID int6
 			}
 		}
 
+		// 删除讨论
+		if _, err := model2.NewChatGroupDiscussionModel(tx).Delete(ctx, query.Builder().
+			Where(model2.FieldChatGroupDiscussionGroupId, groupID).
+			Where(model2.FieldChatGroupDiscussionUserId, userID)); err != nil {
+			return fmt.Errorf("delete chat group discussions failed: %w", err)
+		}
+
 		// 删除成员
 		if _, err := model2.NewChatGroupMemberModel(tx).Delete(ctx, query.Builder().
 			Where(model2.FieldChatGroupMemberGroupId, groupID).
@@ -505,12 +628,220 @@ func (repo *ChatGroupRepo) DeleteGroup(ctx context.Context, groupID, userID int6
 		}
 
 		// 删除组
-		if _, err := model2.NewRoomsModel(tx).Delete(ctx, query.Builder().
+		affected, err := model2.NewRoomsModel(tx).Delete(ctx, query.Builder().
 			Where(model2.FieldRoomsId, groupID).
-			Where(model2.FieldRoomsUserId, userID)); err != nil {
+			Where(model2.FieldRoomsUserId, userID))
+		if err != nil {
 			return fmt.Errorf("delete chat group failed: %w", err)
 		}
 
-		return nil
+		if affected == 0 {
+			return nil
+		}
+
+		if _, err := model2.NewRoomTagModel(tx).Delete(ctx, query.Builder().
+			Where(model2.FieldRoomTagUserId, userID).
+			Where(model2.FieldRoomTagRoomId, groupID)); err != nil {
+			return fmt.Errorf("delete chat group tags failed: %w", err)
+		}
+
+		return addSyncTombstones(ctx, tx, userID, SyncEntityRoom, []int64{groupID})
+	})
+}
+
+const (
+	// ChatGroupDiscussionStatusRunning 讨论状态：进行中
+	ChatGroupDiscussionStatusRunning = 1
+	// ChatGroupDiscussionStatusFinished 讨论状态：已结束
+	ChatGroupDiscussionStatusFinished = 2
+	// ChatGroupDiscussionStatusStopped 讨论状态：用户终止
+	ChatGroupDiscussionStatusStopped = 3
+)
+
+const (
+	// DiscussionTurnOrderRoundRobin 发言顺序：轮流发言
+	DiscussionTurnOrderRoundRobin = "round-robin"
+	// DiscussionTurnOrderModerator 发言顺序：由主持人指定下一位发言者
+	DiscussionTurnOrderModerator = "moderator"
+	// DiscussionTurnOrderLLM 发言顺序：由大模型选择下一位发言者
+	DiscussionTurnOrderLLM = "llm"
+)
+
+const (
+	DiscussionStopReasonMaxRounds  = "max_rounds"
+	DiscussionStopReasonConsensus  = "consensus"
+	DiscussionStopReasonCoinBudget = "coin_budget"
+	DiscussionStopReasonQuota      = "quota_not_enough"
+	DiscussionStopReasonUser       = "user"
+	DiscussionStopReasonError      = "error"
+)
+
+type ChatGroupDiscussion struct {
+	model2.ChatGroupDiscussion
+	Members []int64 `json:"members"`
+}
+
+// IsRunning 讨论是否正在进行中
+func (d ChatGroupDiscussion) IsRunning() bool {
+	return d.Status == ChatGroupDiscussionStatusRunning
+}
+
+// NextSpeakerRoundRobin 按照成员顺序，返回当前发言者之后的下一位发言者
+func (d ChatGroupDiscussion) NextSpeakerRoundRobin(current int64) int64 {
+	if len(d.Members) == 0 {
+		return 0
+	}
+
+	for i, mem := range d.Members {
+		if mem == current {
+			return d.Members[(i+1)%len(d.Members)]
+		}
+	}
+
+	return d.Members[0]
+}
+
+type ChatGroupDiscussionCreate struct {
+	QuestionID      int64   `json:"question_id"`
+	MemberIDs       []int64 `json:"member_ids"`
+	TurnOrder       string  `json:"turn_order"`
+	ModeratorID     int64   `json:"moderator_id,omitempty"`
+	MaxRounds       int64   `json:"max_rounds"`
+	StopOnConsensus bool    `json:"stop_on_consensus,omitempty"`
+	CoinBudget      int64   `json:"coin_budget,omitempty"`
+	NextMemberID    int64   `json:"next_member_id"`
+}
+
+// CreateDiscussion 创建一个群聊讨论
+func (repo *ChatGroupRepo) CreateDiscussion(ctx context.Context, groupID, userID int64, req ChatGroupDiscussionCreate) (int64, error) {
+	return model2.NewChatGroupDiscussionModel(repo.db).Create(ctx, query.KV{
+		model2.FieldChatGroupDiscussionGroupId:         groupID,
+		model2.FieldChatGroupDiscussionUserId:          userID,
+		model2.FieldChatGroupDiscussionQuestionId:      req.QuestionID,
+		model2.FieldChatGroupDiscussionMemberIds:       strings.Join(array.Map(req.MemberIDs, func(id int64, _ int) string { return strconv.Itoa(int(id)) }), ","),
+		model2.FieldChatGroupDiscussionTurnOrder:       req.TurnOrder,
+		model2.FieldChatGroupDiscussionModeratorId:     req.ModeratorID,
+		model2.FieldChatGroupDiscussionMaxRounds:       req.MaxRounds,
+		model2.FieldChatGroupDiscussionStopOnConsensus: ternary.If(req.StopOnConsensus, 1, 0),
+		model2.FieldChatGroupDiscussionCoinBudget:      req.CoinBudget,
+		model2.FieldChatGroupDiscussionNextMemberId:    req.NextMemberID,
+		model2.FieldChatGroupDiscussionStatus:          ChatGroupDiscussionStatusRunning,
+	})
+}
+
+// GetDiscussion 获取群聊讨论信息
+func (repo *ChatGroupRepo) GetDiscussion(ctx context.Context, groupID, userID, discussionID int64) (*ChatGroupDiscussion, error) {
+	disc, err := model2.NewChatGroupDiscussionModel(repo.db).First(ctx, query.Builder().
+		Where(model2.FieldChatGroupDiscussionId, discussionID).
+		Where(model2.FieldChatGroupDiscussionGroupId, groupID).
+		Where(model2.FieldChatGroupDiscussionUserId, userID))
+	if err != nil {
+		if errors.Is(err, query.ErrNoResult) {
+			return nil, ErrNotFound
+		}
+
+		return nil, fmt.Errorf("query discussion failed: %w", err)
+	}
+
+	ret := ChatGroupDiscussion{ChatGroupDiscussion: disc.ToChatGroupDiscussion()}
+	for _, seg := range strings.Split(ret.MemberIds, ",") {
+		if id, err := strconv.Atoi(strings.TrimSpace(seg)); err == nil && id > 0 {
+			ret.Members = append(ret.Members, int64(id))
+		}
+	}
+
+	return &ret, nil
+}
+
+// GetDiscussionMessages 获取群聊讨论中的所有消息（按照时间正序排列），包含讨论主题、成员发言以及用户的插话
+func (repo *ChatGroupRepo) GetDiscussionMessages(ctx context.Context, groupID, userID, questionID int64) ([]model2.ChatGroupMessage, error) {
+	messages, err := model2.NewChatGroupMessageModel(repo.db).Get(ctx, query.Builder().
+		Where(model2.FieldChatGroupMessageGroupId, groupID).
+		Where(model2.FieldChatGroupMessageUserId, userID).
+		WhereGroup(func(builder query.Condition) {
+			builder.Where(model2.FieldChatGroupMessageId, questionID).
+				OrWhere(model2.FieldChatGroupMessagePid, questionID)
+		}).
+		OrderBy(model2.FieldChatGroupMessageId, "ASC"))
+	if err != nil {
+		return nil, fmt.Errorf("query discussion messages failed: %w", err)
+	}
+
+	return array.Map(messages, func(msg model2.ChatGroupMessageN, _ int) model2.ChatGroupMessage {
+		return msg.ToChatGroupMessage()
+	}), nil
+}
+
+type ChatGroupDiscussionTurn struct {
+	CoinsConsumed int64 `json:"coins_consumed"`
+	Agreed        bool  `json:"agreed"`
+}
+
+// FinishDiscussionTurn 记录一次成员发言完成，返回更新后的讨论信息
+func (repo *ChatGroupRepo) FinishDiscussionTurn(ctx context.Context, groupID, userID, discussionID int64, turn ChatGroupDiscussionTurn) (*ChatGroupDiscussion, error) {
+	err := eloquent.Transaction(repo.db, func(tx query.Database) error {
+		q := query.Builder().
+			Where(model2.FieldChatGroupDiscussionId, discussionID).
+			Where(model2.FieldChatGroupDiscussionGroupId, groupID).
+			Where(model2.FieldChatGroupDiscussionUserId, userID)
+
+		disc, err := model2.NewChatGroupDiscussionModel(tx).First(ctx, q)
+		if err != nil {
+			if errors.Is(err, query.ErrNoResult) {
+				return ErrNotFound
+			}
+
+			return fmt.Errorf("query discussion failed: %w", err)
+		}
+
+		disc.Turns = null.IntFrom(disc.Turns.ValueOrZero() + 1)
+		disc.CoinsConsumed = null.IntFrom(disc.CoinsConsumed.ValueOrZero() + turn.CoinsConsumed)
+		disc.AgreeCount = null.IntFrom(ternary.If(turn.Agreed, disc.AgreeCount.ValueOrZero()+1, 0))
+
+		return disc.Save(ctx, model2.FieldChatGroupDiscussionTurns, model2.FieldChatGroupDiscussionCoinsConsumed, model2.FieldChatGroupDiscussionAgreeCount)
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	return repo.GetDiscussion(ctx, groupID, userID, discussionID)
+}
+
+// AddDiscussionCoinsConsumed 增加讨论消耗的智慧果，用于记录发言之外的消耗（例如选择下一位发言者）
+func (repo *ChatGroupRepo) AddDiscussionCoinsConsumed(ctx context.Context, groupID, userID, discussionID, coins int64) error {
+	_, err := model2.NewChatGroupDiscussionModel(repo.db).UpdateFields(ctx, query.KV{
+		model2.FieldChatGroupDiscussionCoinsConsumed: query.Raw(fmt.Sprintf("IFNULL(coins_consumed, 0) + %d", coins)),
+	}, query.Builder().
+		Where(model2.FieldChatGroupDiscussionId, discussionID).
+		Where(model2.FieldChatGroupDiscussionGroupId, groupID).
+		Where(model2.FieldChatGroupDiscussionUserId, userID))
+
+	return err
+}
+
+// UpdateDiscussionNextMember 更新下一位发言的成员
+func (repo *ChatGroupRepo) UpdateDiscussionNextMember(ctx context.Context, groupID, userID, discussionID, memberID int64) error {
+	_, err := model2.NewChatGroupDiscussionModel(repo.db).UpdateFields(ctx, query.KV{
+		model2.FieldChatGroupDiscussionNextMemberId: memberID,
+	}, query.Builder().
+		Where(model2.FieldChatGroupDiscussionId, discussionID).
+		Where(model2.FieldChatGroupDiscussionGroupId, groupID).
+		Where(model2.FieldChatGroupDiscussionUserId, userID))
+
+	return err
+}
+
+// StopDiscussion 结束一个进行中的群聊讨论，如果讨论已经结束，则不做任何处理
+func (repo *ChatGroupRepo) StopDiscussion(ctx context.Context, groupID, userID, discussionID int64, status int64, reason string) error {
+	_, err := model2.NewChatGroupDiscussionModel(repo.db).UpdateFields(ctx, query.KV{
+		model2.FieldChatGroupDiscussionStatus:       status,
+		model2.FieldChatGroupDiscussionStopReason:   reason,
+		model2.FieldChatGroupDiscussionNextMemberId: 0,
+	}, query.Builder().
+		Where(model2.FieldChatGroupDiscussionId, discussionID).
+		Where(model2.FieldChatGroupDiscussionGroupId, groupID).
+		Where(model2.FieldChatGroupDiscussionUserId, userID).
+		Where(model2.FieldChatGroupDiscussionStatus, ChatGroupDiscussionStatusRunning))
+
+	return err
 }
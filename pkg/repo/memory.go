@@ -0,0 +1,149 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/mylxsw/aidea-server/pkg/repo/model"
+	"github.com/mylxsw/eloquent"
+	"github.com/mylxsw/eloquent/query"
+	"github.com/mylxsw/go-utils/array"
+)
+
+const (
+	// MemorySourceAuto 从对话中自动提取的记忆
+	MemorySourceAuto = "auto"
+	// MemorySourceManual 用户手动添加的记忆
+	MemorySourceManual = "manual"
+)
+
+const (
+	// MemoryMaxCount 每个用户最多保存的记忆数量
+	MemoryMaxCount = 200
+	// MemoryMaxLength 单条记忆内容的最大长度（字符数）
+	MemoryMaxLength = 500
+)
+
+var ErrMemoryLimitExceeded = errors.New("memory limit exceeded")
+
+type MemoryRepo struct {
+	db *sql.DB
+}
+
+func NewMemoryRepo(db *sql.DB) *MemoryRepo {
+	return &MemoryRepo{db: db}
+}
+
+// Memories 查询用户的所有记忆，最新的在前
+func (r *MemoryRepo) Memories(ctx context.Context, userID int64) ([]model.UserMemory, error) {
+	memories, err := model.NewUserMemoryModel(r.db).Get(ctx, query.Builder().
+		Where(model.FieldUserMemoryUserId, userID).
+		OrderBy(model.FieldUserMemoryId, "DESC").
+		Limit(MemoryMaxCount))
+	if err != nil {
+		return nil, fmt.Errorf("query user memories failed: %w", err)
+	}
+
+	return array.Map(memories, func(m model.UserMemoryN, _ int) model.UserMemory { return m.ToUserMemory() }), nil
+}
+
+// Memory 查询单条记忆
+func (r *MemoryRepo) Memory(ctx context.Context, userID, id int64) (*model.UserMemory, error) {
+	memory, err := model.NewUserMemoryModel(r.db).First(ctx, query.Builder().
+		Where(model.FieldUserMemoryUserId, userID).
+		Where(model.FieldUserMemoryId, id))
+	if err != nil {
+		if errors.Is(err, query.ErrNoResult) {
+			return nil, ErrNotFound
+		}
+
+		return nil, err
+	}
+
+	ret := memory.ToUserMemory()
+	return &ret, nil
+}
+
+// Add 添加记忆
+// 数量达到上限时，自动提取的记忆会淘汰最早的自动提取记忆，用户手动添加的记忆则返回 ErrMemoryLimitExceeded
+func (r *MemoryRepo) Add(ctx context.Context, userID int64, roomID int64, source string, contents ...string) ([]int64, error) {
+	ids := make([]int64, 0, len(contents))
+	err := eloquent.Transaction(r.db, func(tx query.Database) error {
+		count, err := model.NewUserMemoryModel(tx).Count(ctx, query.Builder().Where(model.FieldUserMemoryUserId, userID))
+		if err != nil {
+			return fmt.Errorf("query user memories failed: %w", err)
+		}
+
+		if overflow := count + int64(len(contents)) - MemoryMaxCount; overflow > 0 {
+			if source != MemorySourceAuto {
+				return ErrMemoryLimitExceeded
+			}
+
+			oldest, err := model.NewUserMemoryModel(tx).Get(ctx, query.Builder().
+				Where(model.FieldUserMemoryUserId, userID).
+				Where(model.FieldUserMemorySource, MemorySourceAuto).
+				OrderBy(model.FieldUserMemoryId, "ASC").
+				Limit(overflow))
+			if err != nil {
+				return fmt.Errorf("query oldest memories failed: %w", err)
+			}
+
+			if int64(len(oldest)) < overflow {
+				return ErrMemoryLimitExceeded
+			}
+
+			if _, err := model.NewUserMemoryModel(tx).Delete(ctx, query.Builder().
+				Where(model.FieldUserMemoryUserId, userID).
+				WhereIn(model.FieldUserMemoryId, array.Map(oldest, func(m model.UserMemoryN, _ int) any { return m.Id.ValueOrZero() })...)); err != nil {
+				return fmt.Errorf("remove oldest memories failed: %w", err)
+			}
+		}
+
+		for _, content := range contents {
+			id, err := model.NewUserMemoryModel(tx).Create(ctx, query.KV{
+				model.FieldUserMemoryUserId:  userID,
+				model.FieldUserMemoryRoomId:  roomID,
+				model.FieldUserMemorySource:  source,
+				model.FieldUserMemoryContent: content,
+			})
+			if err != nil {
+				return fmt.Errorf("create user memory failed: %w", err)
+			}
+
+			ids = append(ids, id)
+		}
+
+		return nil
+	})
+
+	return ids, err
+}
+
+// Update 更新记忆内容，用户编辑后的记忆视为手动添加，不会被自动淘汰
+func (r *MemoryRepo) Update(ctx context.Context, userID, id int64, content string) error {
+	_, err := model.NewUserMemoryModel(r.db).UpdateFields(ctx, query.KV{
+		model.FieldUserMemoryContent: content,
+		model.FieldUserMemorySource:  MemorySourceManual,
+	}, query.Builder().
+		Where(model.FieldUserMemoryUserId, userID).
+		Where(model.FieldUserMemoryId, id))
+
+	return err
+}
+
+// Delete 删除记忆
+func (r *MemoryRepo) Delete(ctx context.Context, userID, id int64) error {
+	_, err := model.NewUserMemoryModel(r.db).Delete(ctx, query.Builder().
+		Where(model.FieldUserMemoryUserId, userID).
+		Where(model.FieldUserMemoryId, id))
+
+	return err
+}
+
+// Clear 清空用户的所有记忆
+func (r *MemoryRepo) Clear(ctx context.Context, userID int64) error {
+	_, err := model.NewUserMemoryModel(r.db).Delete(ctx, query.Builder().Where(model.FieldUserMemoryUserId, userID))
+	return err
+}
@@ -9,7 +9,6 @@ import (
 	"github.com/mylxsw/aidea-server/pkg/misc"
 	"github.com/mylxsw/aidea-server/pkg/repo/model"
 	"github.com/mylxsw/go-utils/array"
-	"github.com/mylxsw/go-utils/must"
 	"strings"
 	"time"
 
@@ -45,6 +44,8 @@ type MessageAddReq struct {
 	Status        int64
 	Error         string
 	Meta          MessageMeta
+	// Channel 实际回答问题的渠道，仅助理消息有值
+	Channel string
 }
 
 type MessageMeta struct {
@@ -87,6 +88,10 @@ func (r *MessageRepo) Add(ctx context.Context, req MessageAddReq, updateRoom boo
 		kvs[model.FieldChatMessagesError] = req.Error
 	}
 
+	if req.Channel != "" {
+		kvs[model.FieldChatMessagesChannel] = req.Channel
+	}
+
 	return id, eloquent.Transaction(r.db, func(tx query.Database) error {
 		var err error
 		id, err = model.NewChatMessagesModel(tx).Create(ctx, kvs)
@@ -137,6 +142,33 @@ func (r *MessageRepo) UpdateMessageStatus(ctx context.Context, id int64, req Mes
 	return err
 }
 
+// Delete 删除用户的聊天消息，同时记录删除操作，供其它设备同步
+func (r *MessageRepo) Delete(ctx context.Context, userID int64, ids []int64) error {
+	return eloquent.Transaction(r.db, func(tx query.Database) error {
+		q := query.Builder().
+			Where(model.FieldChatMessagesUserId, userID).
+			WhereIn(model.FieldChatMessagesId, ids)
+
+		messages, err := model.NewChatMessagesModel(tx).Get(ctx, q)
+		if err != nil {
+			return fmt.Errorf("query messages failed: %w", err)
+		}
+
+		if len(messages) == 0 {
+			return nil
+		}
+
+		ids = array.Map(messages, func(msg model.ChatMessagesN, _ int) int64 { return msg.Id.ValueOrZero() })
+		if _, err := model.NewChatMessagesModel(tx).Delete(ctx, query.Builder().
+			Where(model.FieldChatMessagesUserId, userID).
+			WhereIn(model.FieldChatMessagesId, ids)); err != nil {
+			return fmt.Errorf("delete messages failed: %w", err)
+		}
+
+		return addSyncTombstones(ctx, tx, userID, SyncEntityMessage, ids)
+	})
+}
+
 func (r *MessageRepo) RecentlyMessages(ctx context.Context, userID, roomID int64, offset, limit int64) ([]model.ChatMessages, error) {
 	q := query.Builder().
 		OrderBy(model.FieldChatMessagesId, "DESC").
@@ -190,48 +222,29 @@ func (data ShareData) String() string {
 }
 
 // Share chat history, generate a sharing code
-func (r *MessageRepo) Share(ctx context.Context, userID int64, data ShareData) (string, error) {
+func (r *MessageRepo) Share(ctx context.Context, userID int64, data ShareData, opts ShareOptions) (string, error) {
 	if len(data.IDs) == 0 {
 		return "", errors.New("no message ids")
 	}
 
-	shareCode := misc.Sha1([]byte(fmt.Sprintf("%d:%s", userID, data.String())))
-
-	shareInfo, err := model.NewChatMessagesShareModel(r.db).First(ctx, query.Builder().Where(model.FieldChatMessagesShareCode, shareCode))
-	if err == nil && shareInfo != nil {
-		return shareCode, nil
-	}
-
-	if _, err := model.NewChatMessagesShareModel(r.db).Create(ctx, query.KV{
-		model.FieldChatMessagesShareCode:   shareCode,
-		model.FieldChatMessagesShareUserId: userID,
-		model.FieldChatMessagesShareData:   string(must.Must(json.Marshal(data))),
-	}); err != nil {
-		return "", err
-	}
-
-	return shareCode, nil
+	return createShare(ctx, r.db, userID, ShareTypeMessages, data.String(), data, opts)
 }
 
-// SharedMessages get shared messages by sharing code
-func (r *MessageRepo) SharedMessages(ctx context.Context, code string) ([]model.ChatMessages, *ShareData, error) {
-	shareInfo, err := model.NewChatMessagesShareModel(r.db).First(ctx, query.Builder().Where(model.FieldChatMessagesShareCode, code))
+// SharedMessages get shared messages by sharing code, the password is required if the share is protected
+func (r *MessageRepo) SharedMessages(ctx context.Context, code string, password string) ([]model.ChatMessages, *ShareData, error) {
+	shareInfo, err := accessShare(ctx, r.db, code, ShareTypeMessages, password)
 	if err != nil {
-		if errors.Is(err, query.ErrNoResult) {
-			return nil, nil, ErrNotFound
-		}
-
 		return nil, nil, err
 	}
 
 	var data ShareData
-	if err := json.Unmarshal([]byte(shareInfo.Data.ValueOrZero()), &data); err != nil {
+	if err := json.Unmarshal([]byte(shareInfo.Data), &data); err != nil {
 		return nil, nil, err
 	}
 
 	q := query.Builder().
 		WhereIn(model.FieldChatMessagesId, data.IDs).
-		Where(model.FieldChatMessagesUserId, shareInfo.UserId.ValueOrZero())
+		Where(model.FieldChatMessagesUserId, shareInfo.UserId)
 	messages, err := model.NewChatMessagesModel(r.db).Get(ctx, q)
 	if err != nil {
 		return nil, nil, err
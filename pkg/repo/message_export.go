@@ -0,0 +1,136 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mylxsw/aidea-server/pkg/repo/model"
+	"github.com/mylxsw/eloquent"
+	"github.com/mylxsw/eloquent/query"
+	"github.com/mylxsw/go-utils/array"
+)
+
+// MessageExportLimit 单个会话最多导出的消息数量
+const MessageExportLimit = 5000
+
+// RoomMessages 按照时间正序返回房间中所有成功的消息，用于导出聊天记录
+func (r *MessageRepo) RoomMessages(ctx context.Context, userID, roomID int64) ([]model.ChatMessages, error) {
+	q := query.Builder().
+		Where(model.FieldChatMessagesUserId, userID).
+		Where(model.FieldChatMessagesRoomId, roomID).
+		Where(model.FieldChatMessagesStatus, MessageStatusSucceed).
+		OrderBy(model.FieldChatMessagesId, "ASC").
+		Limit(MessageExportLimit)
+
+	messages, err := model.NewChatMessagesModel(r.db).Get(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("query room messages failed: %w", err)
+	}
+
+	return array.Map(messages, func(m model.ChatMessagesN, _ int) model.ChatMessages { return m.ToChatMessages() }), nil
+}
+
+type MessageImportItem struct {
+	Role      MessageRole
+	Message   string
+	Model     string
+	CreatedAt time.Time
+}
+
+// ImportRoom 创建一个新的房间，并导入房间中的聊天记录（created_at 保留消息的原始时间）
+func (r *MessageRepo) ImportRoom(ctx context.Context, userID int64, room model.Rooms, items []MessageImportItem) (int64, error) {
+	var roomID int64
+	err := eloquent.Transaction(r.db, func(tx query.Database) error {
+		lastActiveTime := time.Now()
+		if len(items) > 0 && !items[len(items)-1].CreatedAt.IsZero() {
+			lastActiveTime = items[len(items)-1].CreatedAt
+		}
+
+		room.UserId = userID
+		room.LastActiveTime = lastActiveTime
+
+		id, err := model.NewRoomsModel(tx).Save(ctx, room.ToRoomsN(
+			model.FieldRoomsName,
+			model.FieldRoomsUserId,
+			model.FieldRoomsDescription,
+			model.FieldRoomsPriority,
+			model.FieldRoomsModel,
+			model.FieldRoomsVendor,
+			model.FieldRoomsSystemPrompt,
+			model.FieldRoomsLastActiveTime,
+			model.FieldRoomsMaxContext,
+			model.FieldRoomsRoomType,
+		))
+		if err != nil {
+			return fmt.Errorf("create room failed: %w", err)
+		}
+
+		roomID = id
+
+		meta, _ := json.Marshal(MessageMeta{})
+
+		// 消息只在 created_at 中保留原始时间，updated_at 使用导入时间，其它设备才能同步到导入的消息
+		now := time.Now()
+
+		// 助理的回复关联到前一条用户消息
+		var lastQuestionID int64
+		for _, item := range items {
+			createdAt := item.CreatedAt
+			if createdAt.IsZero() {
+				createdAt = lastActiveTime
+			}
+
+			kvs := query.KV{
+				model.FieldChatMessagesUserId:    userID,
+				model.FieldChatMessagesRoomId:    roomID,
+				model.FieldChatMessagesRole:      item.Role,
+				model.FieldChatMessagesMessage:   item.Message,
+				model.FieldChatMessagesStatus:    MessageStatusSucceed,
+				model.FieldChatMessagesMeta:      string(meta),
+				model.FieldChatMessagesCreatedAt: createdAt,
+				model.FieldChatMessagesUpdatedAt: now,
+			}
+
+			if item.Model != "" {
+				kvs[model.FieldChatMessagesModel] = item.Model
+			}
+
+			if item.Role == MessageRoleAssistant && lastQuestionID > 0 {
+				kvs[model.FieldChatMessagesPid] = lastQuestionID
+			}
+
+			msgID, err := model.NewChatMessagesModel(tx).Create(ctx, kvs)
+			if err != nil {
+				return fmt.Errorf("create message failed: %w", err)
+			}
+
+			if item.Role == MessageRoleUser {
+				lastQuestionID = msgID
+			}
+		}
+
+		return nil
+	})
+
+	return roomID, err
+}
+
+// ExportChatMessages 按照时间正序返回群聊中所有成功的消息，用于导出聊天记录
+func (repo *ChatGroupRepo) ExportChatMessages(ctx context.Context, groupID, userID int64) ([]model.ChatGroupMessage, error) {
+	q := query.Builder().
+		Where(model.FieldChatGroupMessageGroupId, groupID).
+		Where(model.FieldChatGroupMessageUserId, userID).
+		Where(model.FieldChatGroupMessageStatus, MessageStatusSucceed).
+		WhereIn(model.FieldChatGroupMessageRole, []int64{int64(MessageRoleUser), int64(MessageRoleAssistant)}).
+		OrderBy(model.FieldChatGroupMessageId, "ASC").
+		Limit(MessageExportLimit)
+
+	messages, err := model.NewChatGroupMessageModel(repo.db).Get(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("query group messages failed: %w", err)
+	}
+
+	return array.Map(messages, func(m model.ChatGroupMessageN, _ int) model.ChatGroupMessage { return m.ToChatGroupMessage() }), nil
+}
@@ -0,0 +1,383 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mylxsw/aidea-server/pkg/repo/model"
+	"github.com/mylxsw/asteria/log"
+	"github.com/mylxsw/eloquent"
+	"github.com/mylxsw/eloquent/query"
+	"github.com/mylxsw/go-utils/array"
+)
+
+const (
+	// FeedbackMessageTypeChat 单聊（数字人）消息
+	FeedbackMessageTypeChat = "chat"
+	// FeedbackMessageTypeGroup 群聊消息
+	FeedbackMessageTypeGroup = "group"
+)
+
+const (
+	// FeedbackRatingUp 赞
+	FeedbackRatingUp = 1
+	// FeedbackRatingDown 踩
+	FeedbackRatingDown = -1
+)
+
+// 好评原因
+const (
+	FeedbackReasonHelpful  = "helpful"
+	FeedbackReasonAccurate = "accurate"
+	FeedbackReasonCreative = "creative"
+)
+
+// 差评原因
+const (
+	FeedbackReasonInaccurate = "inaccurate"
+	FeedbackReasonIncomplete = "incomplete"
+	FeedbackReasonIrrelevant = "irrelevant"
+	FeedbackReasonHarmful    = "harmful"
+	FeedbackReasonBadFormat  = "bad_format"
+	FeedbackReasonOther      = "other"
+)
+
+var (
+	feedbackUpReasons   = []string{FeedbackReasonHelpful, FeedbackReasonAccurate, FeedbackReasonCreative, FeedbackReasonOther}
+	feedbackDownReasons = []string{FeedbackReasonInaccurate, FeedbackReasonIncomplete, FeedbackReasonIrrelevant, FeedbackReasonHarmful, FeedbackReasonBadFormat, FeedbackReasonOther}
+)
+
+// FeedbackCommentMaxLength 反馈补充说明的最大长度
+const FeedbackCommentMaxLength = 500
+
+const (
+	// FeedbackDimensionModel 按照模型汇总
+	FeedbackDimensionModel = "model"
+	// FeedbackDimensionChannel 按照渠道汇总
+	FeedbackDimensionChannel = "channel"
+	// FeedbackDimensionDay 按照日期汇总
+	FeedbackDimensionDay = "day"
+)
+
+// ErrFeedbackNotAllowed 只能对助理回复的消息进行评价
+var ErrFeedbackNotAllowed = errors.New("feedback is only allowed on assistant messages")
+
+// FeedbackReasons 返回评价可选的原因分类
+func FeedbackReasons(rating int64) []string {
+	if rating == FeedbackRatingUp {
+		return feedbackUpReasons
+	}
+
+	return feedbackDownReasons
+}
+
+// MessageFeedbackInput 用户提交的消息反馈
+type MessageFeedbackInput struct {
+	Rating  int64    `json:"rating"`
+	Reasons []string `json:"reasons,omitempty"`
+	Comment string   `json:"comment,omitempty"`
+}
+
+// Validate 检查反馈内容是否合法，原因分类必须与评价相匹配
+func (in MessageFeedbackInput) Validate() error {
+	if in.Rating != FeedbackRatingUp && in.Rating != FeedbackRatingDown {
+		return fmt.Errorf("invalid rating: %d", in.Rating)
+	}
+
+	allowed := FeedbackReasons(in.Rating)
+	for _, reason := range in.Reasons {
+		if !array.In(reason, allowed) {
+			return fmt.Errorf("invalid reason: %s", reason)
+		}
+	}
+
+	if utf8.RuneCountInString(in.Comment) > FeedbackCommentMaxLength {
+		return fmt.Errorf("comment is too long, at most %d characters", FeedbackCommentMaxLength)
+	}
+
+	return nil
+}
+
+// MessageFeedback 消息反馈
+type MessageFeedback struct {
+	ID          int64     `json:"id"`
+	UserID      int64     `json:"user_id,omitempty"`
+	MessageType string    `json:"message_type"`
+	MessageID   int64     `json:"message_id"`
+	RoomID      int64     `json:"room_id,omitempty"`
+	Model       string    `json:"model,omitempty"`
+	Channel     string    `json:"channel,omitempty"`
+	Rating      int64     `json:"rating"`
+	Reasons     []string  `json:"reasons"`
+	Comment     string    `json:"comment,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func createMessageFeedbackFromModel(item model.MessageFeedbackN) MessageFeedback {
+	ret := MessageFeedback{
+		ID:          item.Id.ValueOrZero(),
+		UserID:      item.UserId.ValueOrZero(),
+		MessageType: item.MessageType.ValueOrZero(),
+		MessageID:   item.MessageId.ValueOrZero(),
+		RoomID:      item.RoomId.ValueOrZero(),
+		Model:       item.Model.ValueOrZero(),
+		Channel:     item.Channel.ValueOrZero(),
+		Rating:      item.Rating.ValueOrZero(),
+		Reasons:     make([]string, 0),
+		Comment:     item.Comment.ValueOrZero(),
+		CreatedAt:   item.CreatedAt.ValueOrZero(),
+		UpdatedAt:   item.UpdatedAt.ValueOrZero(),
+	}
+
+	if reasons := item.Reasons.ValueOrZero(); reasons != "" {
+		if err := json.Unmarshal([]byte(reasons), &ret.Reasons); err != nil {
+			log.F(log.M{"feedback_id": ret.ID}).Errorf("unmarshal message feedback reasons failed: %v", err)
+		}
+	}
+
+	return ret
+}
+
+// feedbackTarget 被评价的消息，以及产生该消息的模型和渠道
+type feedbackTarget struct {
+	RoomID  int64
+	Model   string
+	Channel string
+}
+
+type MessageFeedbackRepo struct {
+	db *sql.DB
+}
+
+func NewMessageFeedbackRepo(db *sql.DB) *MessageFeedbackRepo {
+	return &MessageFeedbackRepo{db: db}
+}
+
+// Feedback 查询用户对消息的反馈
+func (r *MessageFeedbackRepo) Feedback(ctx context.Context, userID int64, messageType string, messageID int64) (*MessageFeedback, error) {
+	item, err := model.NewMessageFeedbackModel(r.db).First(ctx, query.Builder().
+		Where(model.FieldMessageFeedbackUserId, userID).
+		Where(model.FieldMessageFeedbackMessageType, messageType).
+		Where(model.FieldMessageFeedbackMessageId, messageID))
+	if err != nil {
+		if errors.Is(err, query.ErrNoResult) {
+			return nil, ErrNotFound
+		}
+
+		return nil, err
+	}
+
+	ret := createMessageFeedbackFromModel(*item)
+	return &ret, nil
+}
+
+// Save 保存用户对消息的反馈，重复提交时覆盖之前的反馈
+//
+// 消息不存在时返回 ErrNotFound，消息不是助理回复时返回 ErrFeedbackNotAllowed
+func (r *MessageFeedbackRepo) Save(ctx context.Context, userID int64, messageType string, messageID int64, in MessageFeedbackInput) error {
+	return eloquent.Transaction(r.db, func(tx query.Database) error {
+		target, err := r.target(ctx, tx, userID, messageType, messageID)
+		if err != nil {
+			return err
+		}
+
+		reasons, _ := json.Marshal(array.Uniq(append([]string{}, in.Reasons...)))
+		kv := query.KV{
+			model.FieldMessageFeedbackRoomId:  target.RoomID,
+			model.FieldMessageFeedbackModel:   target.Model,
+			model.FieldMessageFeedbackChannel: target.Channel,
+			model.FieldMessageFeedbackRating:  in.Rating,
+			model.FieldMessageFeedbackReasons: string(reasons),
+			model.FieldMessageFeedbackComment: in.Comment,
+		}
+
+		q := query.Builder().
+			Where(model.FieldMessageFeedbackUserId, userID).
+			Where(model.FieldMessageFeedbackMessageType, messageType).
+			Where(model.FieldMessageFeedbackMessageId, messageID)
+
+		exist, err := model.NewMessageFeedbackModel(tx).Exists(ctx, q)
+		if err != nil {
+			return fmt.Errorf("query message feedback failed: %w", err)
+		}
+
+		if exist {
+			_, err = model.NewMessageFeedbackModel(tx).UpdateFields(ctx, kv, q)
+			return err
+		}
+
+		kv[model.FieldMessageFeedbackUserId] = userID
+		kv[model.FieldMessageFeedbackMessageType] = messageType
+		kv[model.FieldMessageFeedbackMessageId] = messageID
+
+		_, err = model.NewMessageFeedbackModel(tx).Create(ctx, kv)
+		return err
+	})
+}
+
+// target 查询被评价的消息，只允许评价用户自己的助理回复消息
+func (r *MessageFeedbackRepo) target(ctx context.Context, tx query.Database, userID int64, messageType string, messageID int64) (*feedbackTarget, error) {
+	switch messageType {
+	case FeedbackMessageTypeChat:
+		msg, err := model.NewChatMessagesModel(tx).First(ctx, query.Builder().
+			Where(model.FieldChatMessagesUserId, userID).
+			Where(model.FieldChatMessagesId, messageID))
+		if err != nil {
+			if errors.Is(err, query.ErrNoResult) {
+				return nil, ErrNotFound
+			}
+
+			return nil, fmt.Errorf("query message failed: %w", err)
+		}
+
+		if MessageRole(msg.Role.ValueOrZero()) != MessageRoleAssistant {
+			return nil, ErrFeedbackNotAllowed
+		}
+
+		return &feedbackTarget{
+			RoomID:  msg.RoomId.ValueOrZero(),
+			Model:   msg.Model.ValueOrZero(),
+			Channel: msg.Channel.ValueOrZero(),
+		}, nil
+	case FeedbackMessageTypeGroup:
+		msg, err := model.NewChatGroupMessageModel(tx).First(ctx, query.Builder().
+			Where(model.FieldChatGroupMessageUserId, userID).
+			Where(model.FieldChatGroupMessageId, messageID))
+		if err != nil {
+			if errors.Is(err, query.ErrNoResult) {
+				return nil, ErrNotFound
+			}
+
+			return nil, fmt.Errorf("query group message failed: %w", err)
+		}
+
+		if MessageRole(msg.Role.ValueOrZero()) != MessageRoleAssistant {
+			return nil, ErrFeedbackNotAllowed
+		}
+
+		ret := feedbackTarget{
+			RoomID:  msg.GroupId.ValueOrZero(),
+			Model:   msg.Model.ValueOrZero(),
+			Channel: msg.Channel.ValueOrZero(),
+		}
+
+		if ret.Model != "" {
+			return &ret, nil
+		}
+
+		// 历史消息未记录模型，以成员当前的模型为准
+		member, err := model.NewChatGroupMemberModel(tx).First(ctx, query.Builder().
+			Where(model.FieldChatGroupMemberId, msg.MemberId.ValueOrZero()))
+		if err != nil && !errors.Is(err, query.ErrNoResult) {
+			return nil, fmt.Errorf("query group member failed: %w", err)
+		}
+
+		if member != nil {
+			ret.Model = member.ModelId.ValueOrZero()
+		}
+
+		return &ret, nil
+	}
+
+	return nil, fmt.Errorf("unsupported message type: %s", messageType)
+}
+
+// Delete 撤销用户对消息的反馈
+func (r *MessageFeedbackRepo) Delete(ctx context.Context, userID int64, messageType string, messageID int64) error {
+	_, err := model.NewMessageFeedbackModel(r.db).Delete(ctx, query.Builder().
+		Where(model.FieldMessageFeedbackUserId, userID).
+		Where(model.FieldMessageFeedbackMessageType, messageType).
+		Where(model.FieldMessageFeedbackMessageId, messageID))
+
+	return err
+}
+
+// MessageFeedbackFilter 管理后台查询反馈列表的过滤条件
+type MessageFeedbackFilter struct {
+	Model   string
+	Channel string
+	Rating  int64
+}
+
+// Feedbacks 分页查询反馈列表，按照时间倒序排列
+func (r *MessageFeedbackRepo) Feedbacks(ctx context.Context, filter MessageFeedbackFilter, page, perPage int64) ([]MessageFeedback, query.PaginateMeta, error) {
+	q := query.Builder()
+	if filter.Model != "" {
+		q = q.Where(model.FieldMessageFeedbackModel, filter.Model)
+	}
+
+	if filter.Channel != "" {
+		q = q.Where(model.FieldMessageFeedbackChannel, filter.Channel)
+	}
+
+	if filter.Rating != 0 {
+		q = q.Where(model.FieldMessageFeedbackRating, filter.Rating)
+	}
+
+	items, meta, err := model.NewMessageFeedbackModel(r.db).Paginate(ctx, page, perPage, q.OrderBy(model.FieldMessageFeedbackId, "DESC"))
+	if err != nil {
+		return nil, meta, fmt.Errorf("query message feedbacks failed: %w", err)
+	}
+
+	return array.Map(items, func(item model.MessageFeedbackN, _ int) MessageFeedback { return createMessageFeedbackFromModel(item) }), meta, nil
+}
+
+// MessageFeedbackStat 反馈汇总数据
+type MessageFeedbackStat struct {
+	// Key 汇总维度的取值：模型 ID、渠道或者日期（YYYY-MM-DD）
+	Key   string `json:"key"`
+	Up    int64  `json:"up"`
+	Down  int64  `json:"down"`
+	Total int64  `json:"total"`
+	// Satisfaction 好评率
+	Satisfaction float64 `json:"satisfaction"`
+}
+
+// Stats 按照模型、渠道或者日期汇总 [start, end) 时间范围内的反馈数据
+func (r *MessageFeedbackRepo) Stats(ctx context.Context, dimension string, start, end time.Time) ([]MessageFeedbackStat, error) {
+	var key string
+	switch dimension {
+	case FeedbackDimensionModel:
+		key = "IFNULL(model, '')"
+	case FeedbackDimensionChannel:
+		key = "IFNULL(channel, '')"
+	case FeedbackDimensionDay:
+		key = "DATE_FORMAT(created_at, '%Y-%m-%d')"
+	default:
+		return nil, fmt.Errorf("unsupported dimension: %s", dimension)
+	}
+
+	q := eloquent.Raw(
+		fmt.Sprintf(
+			"SELECT %s AS k, SUM(IF(rating = ?, 1, 0)) AS up, SUM(IF(rating = ?, 1, 0)) AS down FROM %s WHERE created_at >= ? AND created_at < ? GROUP BY k ORDER BY k",
+			key, model.MessageFeedbackTable(),
+		),
+		FeedbackRatingUp, FeedbackRatingDown, start, end,
+	)
+
+	return eloquent.Query(ctx, r.db, q, func(row eloquent.Scanner) (MessageFeedbackStat, error) {
+		var stat MessageFeedbackStat
+		var up, down sql.NullInt64
+		if err := row.Scan(&stat.Key, &up, &down); err != nil {
+			return stat, err
+		}
+
+		return NewMessageFeedbackStat(stat.Key, up.Int64, down.Int64), nil
+	})
+}
+
+// NewMessageFeedbackStat 根据赞和踩的数量创建汇总数据
+func NewMessageFeedbackStat(key string, up, down int64) MessageFeedbackStat {
+	stat := MessageFeedbackStat{Key: key, Up: up, Down: down, Total: up + down}
+	if stat.Total > 0 {
+		stat.Satisfaction = float64(up) / float64(stat.Total)
+	}
+
+	return stat
+}
@@ -0,0 +1,30 @@
+package repo_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mylxsw/aidea-server/pkg/repo"
+	"github.com/mylxsw/go-utils/assert"
+)
+
+func TestMessageFeedbackInput_Validate(t *testing.T) {
+	assert.NoError(t, repo.MessageFeedbackInput{Rating: repo.FeedbackRatingUp}.Validate())
+	assert.NoError(t, repo.MessageFeedbackInput{Rating: repo.FeedbackRatingUp, Reasons: []string{repo.FeedbackReasonHelpful}}.Validate())
+	assert.NoError(t, repo.MessageFeedbackInput{Rating: repo.FeedbackRatingDown, Reasons: []string{repo.FeedbackReasonInaccurate, repo.FeedbackReasonOther}, Comment: "答非所问"}.Validate())
+
+	assert.True(t, repo.MessageFeedbackInput{Rating: 0}.Validate() != nil)
+	assert.True(t, repo.MessageFeedbackInput{Rating: 2}.Validate() != nil)
+	// 原因分类必须与评价相匹配
+	assert.True(t, repo.MessageFeedbackInput{Rating: repo.FeedbackRatingUp, Reasons: []string{repo.FeedbackReasonInaccurate}}.Validate() != nil)
+	assert.True(t, repo.MessageFeedbackInput{Rating: repo.FeedbackRatingDown, Reasons: []string{"unknown"}}.Validate() != nil)
+	assert.True(t, repo.MessageFeedbackInput{Rating: repo.FeedbackRatingDown, Comment: strings.Repeat("长", repo.FeedbackCommentMaxLength+1)}.Validate() != nil)
+}
+
+func TestNewMessageFeedbackStat(t *testing.T) {
+	stat := repo.NewMessageFeedbackStat("gpt-4o", 3, 1)
+	assert.Equal(t, int64(4), stat.Total)
+	assert.Equal(t, 0.75, stat.Satisfaction)
+
+	assert.Equal(t, 0.0, repo.NewMessageFeedbackStat("gpt-4o", 0, 0).Satisfaction)
+}
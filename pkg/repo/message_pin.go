@@ -0,0 +1,74 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/mylxsw/aidea-server/pkg/repo/model"
+	"github.com/mylxsw/eloquent"
+	"github.com/mylxsw/eloquent/query"
+	"github.com/mylxsw/go-utils/array"
+	"github.com/mylxsw/go-utils/ternary"
+)
+
+// PinnedMessageMaxCount 每个房间最多可以置顶的消息数量
+const PinnedMessageMaxCount = 10
+
+var ErrPinnedMessageLimitExceeded = errors.New("pinned message limit exceeded")
+
+// PinnedMessages 查询房间中置顶的消息，按照消息的先后顺序排列
+func (r *MessageRepo) PinnedMessages(ctx context.Context, userID, roomID int64) ([]model.ChatMessages, error) {
+	messages, err := model.NewChatMessagesModel(r.db).Get(ctx, query.Builder().
+		Where(model.FieldChatMessagesUserId, userID).
+		Where(model.FieldChatMessagesRoomId, roomID).
+		Where(model.FieldChatMessagesPinned, 1).
+		OrderBy(model.FieldChatMessagesId, "ASC").
+		Limit(PinnedMessageMaxCount))
+	if err != nil {
+		return nil, fmt.Errorf("query pinned messages failed: %w", err)
+	}
+
+	return array.Map(messages, func(m model.ChatMessagesN, _ int) model.ChatMessages { return m.ToChatMessages() }), nil
+}
+
+// Pin 置顶或者取消置顶房间中的消息，置顶数量达到上限时返回 ErrPinnedMessageLimitExceeded
+func (r *MessageRepo) Pin(ctx context.Context, userID, roomID, id int64, pinned bool) error {
+	return eloquent.Transaction(r.db, func(tx query.Database) error {
+		msg, err := model.NewChatMessagesModel(tx).First(ctx, query.Builder().
+			Where(model.FieldChatMessagesUserId, userID).
+			Where(model.FieldChatMessagesRoomId, roomID).
+			Where(model.FieldChatMessagesId, id))
+		if err != nil {
+			if errors.Is(err, query.ErrNoResult) {
+				return ErrNotFound
+			}
+
+			return fmt.Errorf("query message failed: %w", err)
+		}
+
+		if (msg.Pinned.ValueOrZero() == 1) == pinned {
+			return nil
+		}
+
+		if pinned {
+			count, err := model.NewChatMessagesModel(tx).Count(ctx, query.Builder().
+				Where(model.FieldChatMessagesUserId, userID).
+				Where(model.FieldChatMessagesRoomId, roomID).
+				Where(model.FieldChatMessagesPinned, 1))
+			if err != nil {
+				return fmt.Errorf("query pinned messages failed: %w", err)
+			}
+
+			if count >= PinnedMessageMaxCount {
+				return ErrPinnedMessageLimitExceeded
+			}
+		}
+
+		_, err = model.NewChatMessagesModel(tx).UpdateFields(ctx, query.KV{
+			model.FieldChatMessagesPinned: ternary.If(pinned, 1, 0),
+		}, query.Builder().Where(model.FieldChatMessagesId, id))
+
+		return err
+	})
+}
@@ -0,0 +1,305 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mylxsw/aidea-server/pkg/repo/model"
+	"github.com/mylxsw/eloquent/query"
+	"github.com/mylxsw/go-utils/array"
+)
+
+const (
+	// MessageSearchSourceChat 普通聊天记录
+	MessageSearchSourceChat = "chat"
+	// MessageSearchSourceGroupChat 群聊聊天记录
+	MessageSearchSourceGroupChat = "group_chat"
+)
+
+// MessageSearchMaxWindow 搜索结果最多可以翻阅的记录数
+const MessageSearchMaxWindow = 500
+
+type MessageSearchReq struct {
+	// Keyword 搜索关键词，多个关键词使用空格分隔，使用英文双引号包裹的内容作为短语匹配
+	Keyword string
+	// Source 搜索范围：chat/group_chat，为空时搜索全部
+	Source  string
+	RoomID  int64
+	Model   string
+	StartAt time.Time
+	EndAt   time.Time
+	Page    int64
+	PerPage int64
+}
+
+type MessageSearchHighlight struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+type MessageSearchResult struct {
+	Source     string                   `json:"source"`
+	ID         int64                    `json:"id"`
+	RoomID     int64                    `json:"room_id"`
+	Role       int64                    `json:"role"`
+	Model      string                   `json:"model,omitempty"`
+	MemberID   int64                    `json:"member_id,omitempty"`
+	Snippet    string                   `json:"snippet"`
+	Highlights []MessageSearchHighlight `json:"highlights,omitempty"`
+	CreatedAt  time.Time                `json:"created_at"`
+}
+
+// ParseSearchKeyword 解析搜索关键词，返回普通关键词和短语
+func ParseSearchKeyword(keyword string) (terms []string, phrases []string) {
+	segments := strings.Split(keyword, `"`)
+	for i, seg := range segments {
+		seg = strings.TrimSpace(seg)
+		if seg == "" {
+			continue
+		}
+
+		// 奇数位置的片段位于一对双引号之间（未闭合的引号按照普通关键词处理）
+		if i%2 == 1 && i < len(segments)-1 {
+			phrases = append(phrases, seg)
+			continue
+		}
+
+		terms = append(terms, strings.Fields(seg)...)
+	}
+
+	return terms, phrases
+}
+
+// BuildFullTextQuery 构建 MySQL FULLTEXT 索引 BOOLEAN MODE 下的查询语句，所有的关键词和短语都必须匹配
+func BuildFullTextQuery(keyword string) string {
+	terms, phrases := ParseSearchKeyword(keyword)
+
+	parts := make([]string, 0, len(terms)+len(phrases))
+	for _, term := range terms {
+		if term = sanitizeFullTextTerm(term); term != "" {
+			parts = append(parts, "+"+term)
+		}
+	}
+
+	for _, phrase := range phrases {
+		if phrase = sanitizeFullTextTerm(phrase); phrase != "" {
+			parts = append(parts, `+"`+phrase+`"`)
+		}
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// sanitizeFullTextTerm 移除 BOOLEAN MODE 中具有特殊含义的操作符
+func sanitizeFullTextTerm(term string) string {
+	term = strings.Map(func(r rune) rune {
+		switch r {
+		case '+', '-', '<', '>', '(', ')', '~', '*', '"', '@':
+			return ' '
+		}
+		return r
+	}, term)
+
+	return strings.Join(strings.Fields(term), " ")
+}
+
+// BuildSearchSnippet 从消息内容中截取包含关键词的片段，并返回关键词在片段中的位置（按字符计算）
+func BuildSearchSnippet(content string, keyword string, radius int) (string, []MessageSearchHighlight) {
+	terms, phrases := ParseSearchKeyword(keyword)
+	words := array.Filter(append(phrases, terms...), func(w string, _ int) bool { return w != "" })
+
+	runes := []rune(content)
+	lower := []rune(strings.ToLower(content))
+
+	// 查找所有关键词出现的位置
+	matches := make([]MessageSearchHighlight, 0)
+	for _, word := range words {
+		w := []rune(strings.ToLower(word))
+		for i := 0; i+len(w) <= len(lower); i++ {
+			if string(lower[i:i+len(w)]) == string(w) {
+				matches = append(matches, MessageSearchHighlight{Start: i, End: i + len(w)})
+				i += len(w) - 1
+			}
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Start < matches[j].Start })
+
+	// 以第一个匹配位置为中心截取片段
+	start, end := 0, len(runes)
+	if len(matches) > 0 {
+		start = max(0, matches[0].Start-radius)
+	}
+	if end-start > radius*2 {
+		end = start + radius*2
+	}
+
+	prefix, suffix := "", ""
+	if start > 0 {
+		prefix = "..."
+	}
+	if end < len(runes) {
+		suffix = "..."
+	}
+
+	highlights := make([]MessageSearchHighlight, 0)
+	lastEnd := -1
+	for _, m := range matches {
+		// 忽略不在片段内或者与前一个关键词重叠的匹配
+		if m.Start < start || m.End > end || m.Start < lastEnd {
+			continue
+		}
+
+		highlights = append(highlights, MessageSearchHighlight{
+			Start: m.Start - start + utf8.RuneCountInString(prefix),
+			End:   m.End - start + utf8.RuneCountInString(prefix),
+		})
+		lastEnd = m.End
+	}
+
+	snippet := prefix + string(runes[start:end]) + suffix
+	return snippet, highlights
+}
+
+// Search 在用户的聊天记录（包括群聊）中进行全文搜索，依赖 message 字段上使用 ngram 解析器创建的 FULLTEXT 索引
+func (r *MessageRepo) Search(ctx context.Context, userID int64, req MessageSearchReq) ([]MessageSearchResult, error) {
+	ftQuery := BuildFullTextQuery(req.Keyword)
+	if ftQuery == "" {
+		return []MessageSearchResult{}, nil
+	}
+
+	// 多个来源的结果需要合并后分页，因此每个来源都需要取出当前页之前的所有记录
+	window := req.Page * req.PerPage
+	if window > MessageSearchMaxWindow {
+		window = MessageSearchMaxWindow
+	}
+
+	results := make([]MessageSearchResult, 0)
+	if req.Source == "" || req.Source == MessageSearchSourceChat {
+		q := query.Builder().
+			Where(model.FieldChatMessagesUserId, userID).
+			WhereRaw("MATCH(message) AGAINST (? IN BOOLEAN MODE)", ftQuery).
+			OrderBy(model.FieldChatMessagesId, "DESC").
+			Limit(window)
+
+		if req.RoomID > 0 {
+			q = q.Where(model.FieldChatMessagesRoomId, req.RoomID)
+		}
+
+		if req.Model != "" {
+			q = q.Where(model.FieldChatMessagesModel, req.Model)
+		}
+
+		q = applySearchTimeRange(q, model.FieldChatMessagesCreatedAt, req)
+
+		messages, err := model.NewChatMessagesModel(r.db).Get(ctx, q)
+		if err != nil {
+			return nil, fmt.Errorf("search chat messages failed: %w", err)
+		}
+
+		for _, msg := range messages {
+			snippet, highlights := BuildSearchSnippet(msg.Message.ValueOrZero(), req.Keyword, 60)
+			results = append(results, MessageSearchResult{
+				Source:     MessageSearchSourceChat,
+				ID:         msg.Id.ValueOrZero(),
+				RoomID:     msg.RoomId.ValueOrZero(),
+				Role:       msg.Role.ValueOrZero(),
+				Model:      msg.Model.ValueOrZero(),
+				Snippet:    snippet,
+				Highlights: highlights,
+				CreatedAt:  msg.CreatedAt.ValueOrZero(),
+			})
+		}
+	}
+
+	if req.Source == "" || req.Source == MessageSearchSourceGroupChat {
+		q := query.Builder().
+			Where(model.FieldChatGroupMessageUserId, userID).
+			WhereRaw("MATCH(message) AGAINST (? IN BOOLEAN MODE)", ftQuery).
+			OrderBy(model.FieldChatGroupMessageId, "DESC").
+			Limit(window)
+
+		if req.RoomID > 0 {
+			q = q.Where(model.FieldChatGroupMessageGroupId, req.RoomID)
+		}
+
+		if req.Model != "" {
+			q = q.WhereRaw(
+				"member_id IN (SELECT id FROM "+model.ChatGroupMemberTable()+" WHERE user_id = ? AND model_id = ?)",
+				userID, req.Model,
+			)
+		}
+
+		q = applySearchTimeRange(q, model.FieldChatGroupMessageCreatedAt, req)
+
+		messages, err := model.NewChatGroupMessageModel(r.db).Get(ctx, q)
+		if err != nil {
+			return nil, fmt.Errorf("search group chat messages failed: %w", err)
+		}
+
+		members, err := r.groupMemberModels(ctx, array.Uniq(array.Map(messages, func(msg model.ChatGroupMessageN, _ int) int64 { return msg.MemberId.ValueOrZero() })))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, msg := range messages {
+			snippet, highlights := BuildSearchSnippet(msg.Message.ValueOrZero(), req.Keyword, 60)
+			results = append(results, MessageSearchResult{
+				Source:     MessageSearchSourceGroupChat,
+				ID:         msg.Id.ValueOrZero(),
+				RoomID:     msg.GroupId.ValueOrZero(),
+				Role:       msg.Role.ValueOrZero(),
+				Model:      members[msg.MemberId.ValueOrZero()],
+				MemberID:   msg.MemberId.ValueOrZero(),
+				Snippet:    snippet,
+				Highlights: highlights,
+				CreatedAt:  msg.CreatedAt.ValueOrZero(),
+			})
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool { return results[i].CreatedAt.After(results[j].CreatedAt) })
+
+	offset := (req.Page - 1) * req.PerPage
+	if offset >= int64(len(results)) {
+		return []MessageSearchResult{}, nil
+	}
+
+	return results[offset:min(offset+req.PerPage, int64(len(results)))], nil
+}
+
+func applySearchTimeRange(q query.SQLBuilder, field string, req MessageSearchReq) query.SQLBuilder {
+	if !req.StartAt.IsZero() {
+		q = q.Where(field, ">=", req.StartAt)
+	}
+
+	if !req.EndAt.IsZero() {
+		q = q.Where(field, "<", req.EndAt)
+	}
+
+	return q
+}
+
+// groupMemberModels 查询群组成员对应的模型 ID
+func (r *MessageRepo) groupMemberModels(ctx context.Context, memberIDs []int64) (map[int64]string, error) {
+	memberIDs = array.Filter(memberIDs, func(id int64, _ int) bool { return id > 0 })
+	if len(memberIDs) == 0 {
+		return map[int64]string{}, nil
+	}
+
+	members, err := model.NewChatGroupMemberModel(r.db).Get(ctx, query.Builder().WhereIn(model.FieldChatGroupMemberId, memberIDs))
+	if err != nil {
+		return nil, fmt.Errorf("query group members failed: %w", err)
+	}
+
+	ret := make(map[int64]string)
+	for _, mem := range members {
+		ret[mem.Id.ValueOrZero()] = mem.ModelId.ValueOrZero()
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,35 @@
+package repo_test
+
+import (
+	"testing"
+
+	"github.com/mylxsw/aidea-server/pkg/repo"
+	"github.com/mylxsw/go-utils/assert"
+)
+
+func TestBuildFullTextQuery(t *testing.T) {
+	assert.Equal(t, `+golang +并发 +"channel 关闭"`, repo.BuildFullTextQuery(`golang 并发 "channel 关闭"`))
+	assert.Equal(t, `+a +b`, repo.BuildFullTextQuery(`-a +b*`))
+	assert.Equal(t, `+未闭合 +引号`, repo.BuildFullTextQuery(`未闭合 "引号`))
+	assert.Equal(t, "", repo.BuildFullTextQuery(` "" + `))
+}
+
+func TestBuildSearchSnippet(t *testing.T) {
+	snippet, highlights := repo.BuildSearchSnippet("今天我们讨论一下 Golang 的并发模型", "golang 并发", 100)
+	assert.Equal(t, "今天我们讨论一下 Golang 的并发模型", snippet)
+	assert.Equal(t, 2, len(highlights))
+
+	runes := []rune(snippet)
+	assert.Equal(t, "Golang", string(runes[highlights[0].Start:highlights[0].End]))
+	assert.Equal(t, "并发", string(runes[highlights[1].Start:highlights[1].End]))
+
+	// 片段长度不足以包含完整的关键词时，不返回高亮位置
+	snippet, highlights = repo.BuildSearchSnippet("0123456789关键词0123456789", "关键词", 1)
+	assert.Equal(t, "...9关...", snippet)
+	assert.Equal(t, 0, len(highlights))
+
+	snippet, highlights = repo.BuildSearchSnippet("0123456789关键词0123456789", "关键词", 5)
+	runes = []rune(snippet)
+	assert.Equal(t, "...56789关键词01...", snippet)
+	assert.Equal(t, "关键词", string(runes[highlights[0].Start:highlights[0].End]))
+}
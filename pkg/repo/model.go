@@ -145,6 +145,15 @@ type ModelProvider struct {
 	Type string `json:"type,omitempty"`
 }
 
+// Channel 返回供应商的渠道标识：动态渠道为 channel:<id>，否则为供应商名称
+func (p ModelProvider) Channel() string {
+	if p.ID > 0 {
+		return fmt.Sprintf("channel:%d", p.ID)
+	}
+
+	return p.Name
+}
+
 const (
 	ModelProviderTypeDefault   = "default"
 	ModelProviderTypeReasoning = "reasoning"
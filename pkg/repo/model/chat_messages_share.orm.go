@@ -20,12 +20,18 @@ type ChatMessagesShareN struct {
 	original               *chatMessagesShareOriginal
 	chatMessagesShareModel *ChatMessagesShareModel
 
-	Id        null.Int    `json:"id"`
-	UserId    null.Int    `json:"user_id,omitempty"`
-	Data      null.String `json:"data,omitempty"`
-	Code      null.String `json:"code"`
-	CreatedAt null.Time
-	UpdatedAt null.Time
+	Id           null.Int    `json:"id"`
+	UserId       null.Int    `json:"user_id,omitempty"`
+	Data         null.String `json:"data,omitempty"`
+	Code         null.String `json:"code"`
+	ShareType    null.String `json:"share_type,omitempty"`
+	Password     null.String `json:"-"`
+	ExpiredAt    null.Time   `json:"expired_at,omitempty"`
+	Revoked      null.Int    `json:"revoked,omitempty"`
+	Views        null.Int    `json:"views,omitempty"`
+	LastViewedAt null.Time   `json:"last_viewed_at,omitempty"`
+	CreatedAt    null.Time
+	UpdatedAt    null.Time
 }
 
 // As convert object to other type
@@ -41,12 +47,18 @@ func (inst *ChatMessagesShareN) SetModel(chatMessagesShareModel *ChatMessagesSha
 
 // chatMessagesShareOriginal is an object which stores original ChatMessagesShare from database
 type chatMessagesShareOriginal struct {
-	Id        null.Int
-	UserId    null.Int
-	Data      null.String
-	Code      null.String
-	CreatedAt null.Time
-	UpdatedAt null.Time
+	Id           null.Int
+	UserId       null.Int
+	Data         null.String
+	Code         null.String
+	ShareType    null.String
+	Password     null.String
+	ExpiredAt    null.Time
+	Revoked      null.Int
+	Views        null.Int
+	LastViewedAt null.Time
+	CreatedAt    null.Time
+	UpdatedAt    null.Time
 }
 
 // Staled identify whether the object has been modified
@@ -69,6 +81,24 @@ func (inst *ChatMessagesShareN) Staled(onlyFields ...string) bool {
 		if inst.Code != inst.original.Code {
 			return true
 		}
+		if inst.ShareType != inst.original.ShareType {
+			return true
+		}
+		if inst.Password != inst.original.Password {
+			return true
+		}
+		if inst.ExpiredAt != inst.original.ExpiredAt {
+			return true
+		}
+		if inst.Revoked != inst.original.Revoked {
+			return true
+		}
+		if inst.Views != inst.original.Views {
+			return true
+		}
+		if inst.LastViewedAt != inst.original.LastViewedAt {
+			return true
+		}
 		if inst.CreatedAt != inst.original.CreatedAt {
 			return true
 		}
@@ -95,6 +125,30 @@ func (inst *ChatMessagesShareN) Staled(onlyFields ...string) bool {
 				if inst.Code != inst.original.Code {
 					return true
 				}
+			case "share_type":
+				if inst.ShareType != inst.original.ShareType {
+					return true
+				}
+			case "password":
+				if inst.Password != inst.original.Password {
+					return true
+				}
+			case "expired_at":
+				if inst.ExpiredAt != inst.original.ExpiredAt {
+					return true
+				}
+			case "revoked":
+				if inst.Revoked != inst.original.Revoked {
+					return true
+				}
+			case "views":
+				if inst.Views != inst.original.Views {
+					return true
+				}
+			case "last_viewed_at":
+				if inst.LastViewedAt != inst.original.LastViewedAt {
+					return true
+				}
 			case "created_at":
 				if inst.CreatedAt != inst.original.CreatedAt {
 					return true
@@ -133,6 +187,24 @@ func (inst *ChatMessagesShareN) StaledKV(onlyFields ...string) query.KV {
 		if inst.Code != inst.original.Code {
 			kv["code"] = inst.Code
 		}
+		if inst.ShareType != inst.original.ShareType {
+			kv["share_type"] = inst.ShareType
+		}
+		if inst.Password != inst.original.Password {
+			kv["password"] = inst.Password
+		}
+		if inst.ExpiredAt != inst.original.ExpiredAt {
+			kv["expired_at"] = inst.ExpiredAt
+		}
+		if inst.Revoked != inst.original.Revoked {
+			kv["revoked"] = inst.Revoked
+		}
+		if inst.Views != inst.original.Views {
+			kv["views"] = inst.Views
+		}
+		if inst.LastViewedAt != inst.original.LastViewedAt {
+			kv["last_viewed_at"] = inst.LastViewedAt
+		}
 		if inst.CreatedAt != inst.original.CreatedAt {
 			kv["created_at"] = inst.CreatedAt
 		}
@@ -159,6 +231,30 @@ func (inst *ChatMessagesShareN) StaledKV(onlyFields ...string) query.KV {
 				if inst.Code != inst.original.Code {
 					kv["code"] = inst.Code
 				}
+			case "share_type":
+				if inst.ShareType != inst.original.ShareType {
+					kv["share_type"] = inst.ShareType
+				}
+			case "password":
+				if inst.Password != inst.original.Password {
+					kv["password"] = inst.Password
+				}
+			case "expired_at":
+				if inst.ExpiredAt != inst.original.ExpiredAt {
+					kv["expired_at"] = inst.ExpiredAt
+				}
+			case "revoked":
+				if inst.Revoked != inst.original.Revoked {
+					kv["revoked"] = inst.Revoked
+				}
+			case "views":
+				if inst.Views != inst.original.Views {
+					kv["views"] = inst.Views
+				}
+			case "last_viewed_at":
+				if inst.LastViewedAt != inst.original.LastViewedAt {
+					kv["last_viewed_at"] = inst.LastViewedAt
+				}
 			case "created_at":
 				if inst.CreatedAt != inst.original.CreatedAt {
 					kv["created_at"] = inst.CreatedAt
@@ -266,24 +362,36 @@ func (m *ChatMessagesShareModel) globalScopeEnabled(name string) bool {
 }
 
 type ChatMessagesShare struct {
-	Id        int64  `json:"id"`
-	UserId    int64  `json:"user_id,omitempty"`
-	Data      string `json:"data,omitempty"`
-	Code      string `json:"code"`
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	Id           int64     `json:"id"`
+	UserId       int64     `json:"user_id,omitempty"`
+	Data         string    `json:"data,omitempty"`
+	Code         string    `json:"code"`
+	ShareType    string    `json:"share_type,omitempty"`
+	Password     string    `json:"-"`
+	ExpiredAt    time.Time `json:"expired_at,omitempty"`
+	Revoked      int64     `json:"revoked,omitempty"`
+	Views        int64     `json:"views,omitempty"`
+	LastViewedAt time.Time `json:"last_viewed_at,omitempty"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
 }
 
 func (w ChatMessagesShare) ToChatMessagesShareN(allows ...string) ChatMessagesShareN {
 	if len(allows) == 0 {
 		return ChatMessagesShareN{
 
-			Id:        null.IntFrom(int64(w.Id)),
-			UserId:    null.IntFrom(int64(w.UserId)),
-			Data:      null.StringFrom(w.Data),
-			Code:      null.StringFrom(w.Code),
-			CreatedAt: null.TimeFrom(w.CreatedAt),
-			UpdatedAt: null.TimeFrom(w.UpdatedAt),
+			Id:           null.IntFrom(int64(w.Id)),
+			UserId:       null.IntFrom(int64(w.UserId)),
+			Data:         null.StringFrom(w.Data),
+			Code:         null.StringFrom(w.Code),
+			ShareType:    null.StringFrom(w.ShareType),
+			Password:     null.StringFrom(w.Password),
+			ExpiredAt:    null.TimeFrom(w.ExpiredAt),
+			Revoked:      null.IntFrom(int64(w.Revoked)),
+			Views:        null.IntFrom(int64(w.Views)),
+			LastViewedAt: null.TimeFrom(w.LastViewedAt),
+			CreatedAt:    null.TimeFrom(w.CreatedAt),
+			UpdatedAt:    null.TimeFrom(w.UpdatedAt),
 		}
 	}
 
@@ -299,6 +407,18 @@ func (w ChatMessagesShare) ToChatMessagesShareN(allows ...string) ChatMessagesSh
 			res.Data = null.StringFrom(w.Data)
 		case "code":
 			res.Code = null.StringFrom(w.Code)
+		case "share_type":
+			res.ShareType = null.StringFrom(w.ShareType)
+		case "password":
+			res.Password = null.StringFrom(w.Password)
+		case "expired_at":
+			res.ExpiredAt = null.TimeFrom(w.ExpiredAt)
+		case "revoked":
+			res.Revoked = null.IntFrom(int64(w.Revoked))
+		case "views":
+			res.Views = null.IntFrom(int64(w.Views))
+		case "last_viewed_at":
+			res.LastViewedAt = null.TimeFrom(w.LastViewedAt)
 		case "created_at":
 			res.CreatedAt = null.TimeFrom(w.CreatedAt)
 		case "updated_at":
@@ -319,12 +439,18 @@ func (w ChatMessagesShare) As(dst interface{}) error {
 func (w *ChatMessagesShareN) ToChatMessagesShare() ChatMessagesShare {
 	return ChatMessagesShare{
 
-		Id:        w.Id.Int64,
-		UserId:    w.UserId.Int64,
-		Data:      w.Data.String,
-		Code:      w.Code.String,
-		CreatedAt: w.CreatedAt.Time,
-		UpdatedAt: w.UpdatedAt.Time,
+		Id:           w.Id.Int64,
+		UserId:       w.UserId.Int64,
+		Data:         w.Data.String,
+		Code:         w.Code.String,
+		ShareType:    w.ShareType.String,
+		Password:     w.Password.String,
+		ExpiredAt:    w.ExpiredAt.Time,
+		Revoked:      w.Revoked.Int64,
+		Views:        w.Views.Int64,
+		LastViewedAt: w.LastViewedAt.Time,
+		CreatedAt:    w.CreatedAt.Time,
+		UpdatedAt:    w.UpdatedAt.Time,
 	}
 }
 
@@ -347,12 +473,18 @@ func ChatMessagesShareTable() string {
 }
 
 const (
-	FieldChatMessagesShareId        = "id"
-	FieldChatMessagesShareUserId    = "user_id"
-	FieldChatMessagesShareData      = "data"
-	FieldChatMessagesShareCode      = "code"
-	FieldChatMessagesShareCreatedAt = "created_at"
-	FieldChatMessagesShareUpdatedAt = "updated_at"
+	FieldChatMessagesShareId           = "id"
+	FieldChatMessagesShareUserId       = "user_id"
+	FieldChatMessagesShareData         = "data"
+	FieldChatMessagesShareCode         = "code"
+	FieldChatMessagesShareShareType    = "share_type"
+	FieldChatMessagesSharePassword     = "password"
+	FieldChatMessagesShareExpiredAt    = "expired_at"
+	FieldChatMessagesShareRevoked      = "revoked"
+	FieldChatMessagesShareViews        = "views"
+	FieldChatMessagesShareLastViewedAt = "last_viewed_at"
+	FieldChatMessagesShareCreatedAt    = "created_at"
+	FieldChatMessagesShareUpdatedAt    = "updated_at"
 )
 
 // ChatMessagesShareFields return all fields in ChatMessagesShare model
@@ -362,6 +494,12 @@ func ChatMessagesShareFields() []string {
 		"user_id",
 		"data",
 		"code",
+		"share_type",
+		"password",
+		"expired_at",
+		"revoked",
+		"views",
+		"last_viewed_at",
 		"created_at",
 		"updated_at",
 	}
@@ -498,6 +636,12 @@ func (m *ChatMessagesShareModel) Get(ctx context.Context, builders ...query.SQLB
 			"user_id",
 			"data",
 			"code",
+			"share_type",
+			"password",
+			"expired_at",
+			"revoked",
+			"views",
+			"last_viewed_at",
 			"created_at",
 			"updated_at",
 		)
@@ -517,6 +661,18 @@ func (m *ChatMessagesShareModel) Get(ctx context.Context, builders ...query.SQLB
 			selectFields = append(selectFields, f)
 		case "code":
 			selectFields = append(selectFields, f)
+		case "share_type":
+			selectFields = append(selectFields, f)
+		case "password":
+			selectFields = append(selectFields, f)
+		case "expired_at":
+			selectFields = append(selectFields, f)
+		case "revoked":
+			selectFields = append(selectFields, f)
+		case "views":
+			selectFields = append(selectFields, f)
+		case "last_viewed_at":
+			selectFields = append(selectFields, f)
 		case "created_at":
 			selectFields = append(selectFields, f)
 		case "updated_at":
@@ -539,6 +695,18 @@ func (m *ChatMessagesShareModel) Get(ctx context.Context, builders ...query.SQLB
 				scanFields = append(scanFields, &chatMessagesShareVar.Data)
 			case "code":
 				scanFields = append(scanFields, &chatMessagesShareVar.Code)
+			case "share_type":
+				scanFields = append(scanFields, &chatMessagesShareVar.ShareType)
+			case "password":
+				scanFields = append(scanFields, &chatMessagesShareVar.Password)
+			case "expired_at":
+				scanFields = append(scanFields, &chatMessagesShareVar.ExpiredAt)
+			case "revoked":
+				scanFields = append(scanFields, &chatMessagesShareVar.Revoked)
+			case "views":
+				scanFields = append(scanFields, &chatMessagesShareVar.Views)
+			case "last_viewed_at":
+				scanFields = append(scanFields, &chatMessagesShareVar.LastViewedAt)
 			case "created_at":
 				scanFields = append(scanFields, &chatMessagesShareVar.CreatedAt)
 			case "updated_at":
@@ -587,6 +587,11 @@ type CreativeGalleryN struct {
 	StarLevel         null.Int    `json:"star_level,omitempty"`
 	HotValue          null.Int    `json:"hot_value,omitempty"`
 	Status            null.Int    `json:"status,omitempty"`
+	LikeCount         null.Int    `json:"like_count,omitempty"`
+	FavoriteCount     null.Int    `json:"favorite_count,omitempty"`
+	CommentCount      null.Int    `json:"comment_count,omitempty"`
+	ReportCount       null.Int    `json:"report_count,omitempty"`
+	HotBoost          null.Int    `json:"hot_boost,omitempty"`
 	CreatedAt         null.Time
 	UpdatedAt         null.Time
 }
@@ -618,6 +623,11 @@ type creativeGalleryOriginal struct {
 	StarLevel         null.Int
 	HotValue          null.Int
 	Status            null.Int
+	LikeCount         null.Int
+	FavoriteCount     null.Int
+	CommentCount      null.Int
+	ReportCount       null.Int
+	HotBoost          null.Int
 	CreatedAt         null.Time
 	UpdatedAt         null.Time
 }
@@ -672,6 +682,21 @@ func (inst *CreativeGalleryN) Staled(onlyFields ...string) bool {
 		if inst.Status != inst.original.Status {
 			return true
 		}
+		if inst.LikeCount != inst.original.LikeCount {
+			return true
+		}
+		if inst.FavoriteCount != inst.original.FavoriteCount {
+			return true
+		}
+		if inst.CommentCount != inst.original.CommentCount {
+			return true
+		}
+		if inst.ReportCount != inst.original.ReportCount {
+			return true
+		}
+		if inst.HotBoost != inst.original.HotBoost {
+			return true
+		}
 		if inst.CreatedAt != inst.original.CreatedAt {
 			return true
 		}
@@ -738,6 +763,26 @@ func (inst *CreativeGalleryN) Staled(onlyFields ...string) bool {
 				if inst.Status != inst.original.Status {
 					return true
 				}
+			case "like_count":
+				if inst.LikeCount != inst.original.LikeCount {
+					return true
+				}
+			case "favorite_count":
+				if inst.FavoriteCount != inst.original.FavoriteCount {
+					return true
+				}
+			case "comment_count":
+				if inst.CommentCount != inst.original.CommentCount {
+					return true
+				}
+			case "report_count":
+				if inst.ReportCount != inst.original.ReportCount {
+					return true
+				}
+			case "hot_boost":
+				if inst.HotBoost != inst.original.HotBoost {
+					return true
+				}
 			case "created_at":
 				if inst.CreatedAt != inst.original.CreatedAt {
 					return true
@@ -806,6 +851,21 @@ func (inst *CreativeGalleryN) StaledKV(onlyFields ...string) query.KV {
 		if inst.Status != inst.original.Status {
 			kv["status"] = inst.Status
 		}
+		if inst.LikeCount != inst.original.LikeCount {
+			kv["like_count"] = inst.LikeCount
+		}
+		if inst.FavoriteCount != inst.original.FavoriteCount {
+			kv["favorite_count"] = inst.FavoriteCount
+		}
+		if inst.CommentCount != inst.original.CommentCount {
+			kv["comment_count"] = inst.CommentCount
+		}
+		if inst.ReportCount != inst.original.ReportCount {
+			kv["report_count"] = inst.ReportCount
+		}
+		if inst.HotBoost != inst.original.HotBoost {
+			kv["hot_boost"] = inst.HotBoost
+		}
 		if inst.CreatedAt != inst.original.CreatedAt {
 			kv["created_at"] = inst.CreatedAt
 		}
@@ -872,6 +932,26 @@ func (inst *CreativeGalleryN) StaledKV(onlyFields ...string) query.KV {
 				if inst.Status != inst.original.Status {
 					kv["status"] = inst.Status
 				}
+			case "like_count":
+				if inst.LikeCount != inst.original.LikeCount {
+					kv["like_count"] = inst.LikeCount
+				}
+			case "favorite_count":
+				if inst.FavoriteCount != inst.original.FavoriteCount {
+					kv["favorite_count"] = inst.FavoriteCount
+				}
+			case "comment_count":
+				if inst.CommentCount != inst.original.CommentCount {
+					kv["comment_count"] = inst.CommentCount
+				}
+			case "report_count":
+				if inst.ReportCount != inst.original.ReportCount {
+					kv["report_count"] = inst.ReportCount
+				}
+			case "hot_boost":
+				if inst.HotBoost != inst.original.HotBoost {
+					kv["hot_boost"] = inst.HotBoost
+				}
 			case "created_at":
 				if inst.CreatedAt != inst.original.CreatedAt {
 					kv["created_at"] = inst.CreatedAt
@@ -993,6 +1073,11 @@ type CreativeGallery struct {
 	StarLevel         int64  `json:"star_level,omitempty"`
 	HotValue          int64  `json:"hot_value,omitempty"`
 	Status            int64  `json:"status,omitempty"`
+	LikeCount         int64  `json:"like_count,omitempty"`
+	FavoriteCount     int64  `json:"favorite_count,omitempty"`
+	CommentCount      int64  `json:"comment_count,omitempty"`
+	ReportCount       int64  `json:"report_count,omitempty"`
+	HotBoost          int64  `json:"hot_boost,omitempty"`
 	CreatedAt         time.Time
 	UpdatedAt         time.Time
 }
@@ -1015,6 +1100,11 @@ func (w CreativeGallery) ToCreativeGalleryN(allows ...string) CreativeGalleryN {
 			StarLevel:         null.IntFrom(int64(w.StarLevel)),
 			HotValue:          null.IntFrom(int64(w.HotValue)),
 			Status:            null.IntFrom(int64(w.Status)),
+			LikeCount:         null.IntFrom(int64(w.LikeCount)),
+			FavoriteCount:     null.IntFrom(int64(w.FavoriteCount)),
+			CommentCount:      null.IntFrom(int64(w.CommentCount)),
+			ReportCount:       null.IntFrom(int64(w.ReportCount)),
+			HotBoost:          null.IntFrom(int64(w.HotBoost)),
 			CreatedAt:         null.TimeFrom(w.CreatedAt),
 			UpdatedAt:         null.TimeFrom(w.UpdatedAt),
 		}
@@ -1052,6 +1142,16 @@ func (w CreativeGallery) ToCreativeGalleryN(allows ...string) CreativeGalleryN {
 			res.HotValue = null.IntFrom(int64(w.HotValue))
 		case "status":
 			res.Status = null.IntFrom(int64(w.Status))
+		case "like_count":
+			res.LikeCount = null.IntFrom(int64(w.LikeCount))
+		case "favorite_count":
+			res.FavoriteCount = null.IntFrom(int64(w.FavoriteCount))
+		case "comment_count":
+			res.CommentCount = null.IntFrom(int64(w.CommentCount))
+		case "report_count":
+			res.ReportCount = null.IntFrom(int64(w.ReportCount))
+		case "hot_boost":
+			res.HotBoost = null.IntFrom(int64(w.HotBoost))
 		case "created_at":
 			res.CreatedAt = null.TimeFrom(w.CreatedAt)
 		case "updated_at":
@@ -1086,6 +1186,11 @@ func (w *CreativeGalleryN) ToCreativeGallery() CreativeGallery {
 		StarLevel:         w.StarLevel.Int64,
 		HotValue:          w.HotValue.Int64,
 		Status:            w.Status.Int64,
+		LikeCount:         w.LikeCount.Int64,
+		FavoriteCount:     w.FavoriteCount.Int64,
+		CommentCount:      w.CommentCount.Int64,
+		ReportCount:       w.ReportCount.Int64,
+		HotBoost:          w.HotBoost.Int64,
 		CreatedAt:         w.CreatedAt.Time,
 		UpdatedAt:         w.UpdatedAt.Time,
 	}
@@ -1124,6 +1229,11 @@ const (
 	FieldCreativeGalleryStarLevel         = "star_level"
 	FieldCreativeGalleryHotValue          = "hot_value"
 	FieldCreativeGalleryStatus            = "status"
+	FieldCreativeGalleryLikeCount         = "like_count"
+	FieldCreativeGalleryFavoriteCount     = "favorite_count"
+	FieldCreativeGalleryCommentCount      = "comment_count"
+	FieldCreativeGalleryReportCount       = "report_count"
+	FieldCreativeGalleryHotBoost          = "hot_boost"
 	FieldCreativeGalleryCreatedAt         = "created_at"
 	FieldCreativeGalleryUpdatedAt         = "updated_at"
 )
@@ -1145,6 +1255,11 @@ func CreativeGalleryFields() []string {
 		"star_level",
 		"hot_value",
 		"status",
+		"like_count",
+		"favorite_count",
+		"comment_count",
+		"report_count",
+		"hot_boost",
 		"created_at",
 		"updated_at",
 	}
@@ -1291,6 +1406,11 @@ func (m *CreativeGalleryModel) Get(ctx context.Context, builders ...query.SQLBui
 			"star_level",
 			"hot_value",
 			"status",
+			"like_count",
+			"favorite_count",
+			"comment_count",
+			"report_count",
+			"hot_boost",
 			"created_at",
 			"updated_at",
 		)
@@ -1330,6 +1450,16 @@ func (m *CreativeGalleryModel) Get(ctx context.Context, builders ...query.SQLBui
 			selectFields = append(selectFields, f)
 		case "status":
 			selectFields = append(selectFields, f)
+		case "like_count":
+			selectFields = append(selectFields, f)
+		case "favorite_count":
+			selectFields = append(selectFields, f)
+		case "comment_count":
+			selectFields = append(selectFields, f)
+		case "report_count":
+			selectFields = append(selectFields, f)
+		case "hot_boost":
+			selectFields = append(selectFields, f)
 		case "created_at":
 			selectFields = append(selectFields, f)
 		case "updated_at":
@@ -1372,6 +1502,16 @@ func (m *CreativeGalleryModel) Get(ctx context.Context, builders ...query.SQLBui
 				scanFields = append(scanFields, &creativeGalleryVar.HotValue)
 			case "status":
 				scanFields = append(scanFields, &creativeGalleryVar.Status)
+			case "like_count":
+				scanFields = append(scanFields, &creativeGalleryVar.LikeCount)
+			case "favorite_count":
+				scanFields = append(scanFields, &creativeGalleryVar.FavoriteCount)
+			case "comment_count":
+				scanFields = append(scanFields, &creativeGalleryVar.CommentCount)
+			case "report_count":
+				scanFields = append(scanFields, &creativeGalleryVar.ReportCount)
+			case "hot_boost":
+				scanFields = append(scanFields, &creativeGalleryVar.HotBoost)
 			case "created_at":
 				scanFields = append(scanFields, &creativeGalleryVar.CreatedAt)
 			case "updated_at":
@@ -1522,3 +1662,2196 @@ func (m *CreativeGalleryModel) Delete(ctx context.Context, builders ...query.SQL
 func (m *CreativeGalleryModel) DeleteById(ctx context.Context, id int64) (int64, error) {
 	return m.Condition(query.Builder().Where("id", "=", id)).Delete(ctx)
 }
+
+// CreativeGalleryInteractionN is a CreativeGalleryInteraction object, all fields are nullable
+type CreativeGalleryInteractionN struct {
+	original                        *creativeGalleryInteractionOriginal
+	creativeGalleryInteractionModel *CreativeGalleryInteractionModel
+
+	Id        null.Int    `json:"id"`
+	UserId    null.Int    `json:"user_id"`
+	GalleryId null.Int    `json:"gallery_id"`
+	Type      null.String `json:"type"`
+	CreatedAt null.Time
+	UpdatedAt null.Time
+}
+
+// As convert object to other type
+// dst must be a pointer to struct
+func (inst *CreativeGalleryInteractionN) As(dst interface{}) error {
+	return query.Copy(inst, dst)
+}
+
+// SetModel set model for CreativeGalleryInteraction
+func (inst *CreativeGalleryInteractionN) SetModel(creativeGalleryInteractionModel *CreativeGalleryInteractionModel) {
+	inst.creativeGalleryInteractionModel = creativeGalleryInteractionModel
+}
+
+// creativeGalleryInteractionOriginal is an object which stores original CreativeGalleryInteraction from database
+type creativeGalleryInteractionOriginal struct {
+	Id        null.Int
+	UserId    null.Int
+	GalleryId null.Int
+	Type      null.String
+	CreatedAt null.Time
+	UpdatedAt null.Time
+}
+
+// Staled identify whether the object has been modified
+func (inst *CreativeGalleryInteractionN) Staled(onlyFields ...string) bool {
+	if inst.original == nil {
+		inst.original = &creativeGalleryInteractionOriginal{}
+	}
+
+	if len(onlyFields) == 0 {
+
+		if inst.Id != inst.original.Id {
+			return true
+		}
+		if inst.UserId != inst.original.UserId {
+			return true
+		}
+		if inst.GalleryId != inst.original.GalleryId {
+			return true
+		}
+		if inst.Type != inst.original.Type {
+			return true
+		}
+		if inst.CreatedAt != inst.original.CreatedAt {
+			return true
+		}
+		if inst.UpdatedAt != inst.original.UpdatedAt {
+			return true
+		}
+	} else {
+		for _, f := range onlyFields {
+			switch strcase.ToSnake(f) {
+
+			case "id":
+				if inst.Id != inst.original.Id {
+					return true
+				}
+			case "user_id":
+				if inst.UserId != inst.original.UserId {
+					return true
+				}
+			case "gallery_id":
+				if inst.GalleryId != inst.original.GalleryId {
+					return true
+				}
+			case "type":
+				if inst.Type != inst.original.Type {
+					return true
+				}
+			case "created_at":
+				if inst.CreatedAt != inst.original.CreatedAt {
+					return true
+				}
+			case "updated_at":
+				if inst.UpdatedAt != inst.original.UpdatedAt {
+					return true
+				}
+			default:
+			}
+		}
+	}
+
+	return false
+}
+
+// StaledKV return all fields has been modified
+func (inst *CreativeGalleryInteractionN) StaledKV(onlyFields ...string) query.KV {
+	kv := make(query.KV, 0)
+
+	if inst.original == nil {
+		inst.original = &creativeGalleryInteractionOriginal{}
+	}
+
+	if len(onlyFields) == 0 {
+
+		if inst.Id != inst.original.Id {
+			kv["id"] = inst.Id
+		}
+		if inst.UserId != inst.original.UserId {
+			kv["user_id"] = inst.UserId
+		}
+		if inst.GalleryId != inst.original.GalleryId {
+			kv["gallery_id"] = inst.GalleryId
+		}
+		if inst.Type != inst.original.Type {
+			kv["type"] = inst.Type
+		}
+		if inst.CreatedAt != inst.original.CreatedAt {
+			kv["created_at"] = inst.CreatedAt
+		}
+		if inst.UpdatedAt != inst.original.UpdatedAt {
+			kv["updated_at"] = inst.UpdatedAt
+		}
+	} else {
+		for _, f := range onlyFields {
+			switch strcase.ToSnake(f) {
+
+			case "id":
+				if inst.Id != inst.original.Id {
+					kv["id"] = inst.Id
+				}
+			case "user_id":
+				if inst.UserId != inst.original.UserId {
+					kv["user_id"] = inst.UserId
+				}
+			case "gallery_id":
+				if inst.GalleryId != inst.original.GalleryId {
+					kv["gallery_id"] = inst.GalleryId
+				}
+			case "type":
+				if inst.Type != inst.original.Type {
+					kv["type"] = inst.Type
+				}
+			case "created_at":
+				if inst.CreatedAt != inst.original.CreatedAt {
+					kv["created_at"] = inst.CreatedAt
+				}
+			case "updated_at":
+				if inst.UpdatedAt != inst.original.UpdatedAt {
+					kv["updated_at"] = inst.UpdatedAt
+				}
+			default:
+			}
+		}
+	}
+
+	return kv
+}
+
+// Save create a new model or update it
+func (inst *CreativeGalleryInteractionN) Save(ctx context.Context, onlyFields ...string) error {
+	if inst.creativeGalleryInteractionModel == nil {
+		return query.ErrModelNotSet
+	}
+
+	id, _, err := inst.creativeGalleryInteractionModel.SaveOrUpdate(ctx, *inst, onlyFields...)
+	if err != nil {
+		return err
+	}
+
+	inst.Id = null.IntFrom(id)
+	return nil
+}
+
+// Delete remove a creative_gallery_interaction
+func (inst *CreativeGalleryInteractionN) Delete(ctx context.Context) error {
+	if inst.creativeGalleryInteractionModel == nil {
+		return query.ErrModelNotSet
+	}
+
+	_, err := inst.creativeGalleryInteractionModel.DeleteById(ctx, inst.Id.Int64)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// String convert instance to json string
+func (inst *CreativeGalleryInteractionN) String() string {
+	rs, _ := json.Marshal(inst)
+	return string(rs)
+}
+
+type creativeGalleryInteractionScope struct {
+	name  string
+	apply func(builder query.Condition)
+}
+
+var creativeGalleryInteractionGlobalScopes = make([]creativeGalleryInteractionScope, 0)
+var creativeGalleryInteractionLocalScopes = make([]creativeGalleryInteractionScope, 0)
+
+// AddGlobalScopeForCreativeGalleryInteraction assign a global scope to a model
+func AddGlobalScopeForCreativeGalleryInteraction(name string, apply func(builder query.Condition)) {
+	creativeGalleryInteractionGlobalScopes = append(creativeGalleryInteractionGlobalScopes, creativeGalleryInteractionScope{name: name, apply: apply})
+}
+
+// AddLocalScopeForCreativeGalleryInteraction assign a local scope to a model
+func AddLocalScopeForCreativeGalleryInteraction(name string, apply func(builder query.Condition)) {
+	creativeGalleryInteractionLocalScopes = append(creativeGalleryInteractionLocalScopes, creativeGalleryInteractionScope{name: name, apply: apply})
+}
+
+func (m *CreativeGalleryInteractionModel) applyScope() query.Condition {
+	scopeCond := query.ConditionBuilder()
+	for _, g := range creativeGalleryInteractionGlobalScopes {
+		if m.globalScopeEnabled(g.name) {
+			g.apply(scopeCond)
+		}
+	}
+
+	for _, s := range creativeGalleryInteractionLocalScopes {
+		if m.localScopeEnabled(s.name) {
+			s.apply(scopeCond)
+		}
+	}
+
+	return scopeCond
+}
+
+func (m *CreativeGalleryInteractionModel) localScopeEnabled(name string) bool {
+	for _, n := range m.includeLocalScopes {
+		if name == n {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m *CreativeGalleryInteractionModel) globalScopeEnabled(name string) bool {
+	for _, n := range m.excludeGlobalScopes {
+		if name == n {
+			return false
+		}
+	}
+
+	return true
+}
+
+type CreativeGalleryInteraction struct {
+	Id        int64  `json:"id"`
+	UserId    int64  `json:"user_id"`
+	GalleryId int64  `json:"gallery_id"`
+	Type      string `json:"type"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (w CreativeGalleryInteraction) ToCreativeGalleryInteractionN(allows ...string) CreativeGalleryInteractionN {
+	if len(allows) == 0 {
+		return CreativeGalleryInteractionN{
+
+			Id:        null.IntFrom(int64(w.Id)),
+			UserId:    null.IntFrom(int64(w.UserId)),
+			GalleryId: null.IntFrom(int64(w.GalleryId)),
+			Type:      null.StringFrom(w.Type),
+			CreatedAt: null.TimeFrom(w.CreatedAt),
+			UpdatedAt: null.TimeFrom(w.UpdatedAt),
+		}
+	}
+
+	res := CreativeGalleryInteractionN{}
+	for _, al := range allows {
+		switch strcase.ToSnake(al) {
+
+		case "id":
+			res.Id = null.IntFrom(int64(w.Id))
+		case "user_id":
+			res.UserId = null.IntFrom(int64(w.UserId))
+		case "gallery_id":
+			res.GalleryId = null.IntFrom(int64(w.GalleryId))
+		case "type":
+			res.Type = null.StringFrom(w.Type)
+		case "created_at":
+			res.CreatedAt = null.TimeFrom(w.CreatedAt)
+		case "updated_at":
+			res.UpdatedAt = null.TimeFrom(w.UpdatedAt)
+		default:
+		}
+	}
+
+	return res
+}
+
+// As convert object to other type
+// dst must be a pointer to struct
+func (w CreativeGalleryInteraction) As(dst interface{}) error {
+	return query.Copy(w, dst)
+}
+
+func (w *CreativeGalleryInteractionN) ToCreativeGalleryInteraction() CreativeGalleryInteraction {
+	return CreativeGalleryInteraction{
+
+		Id:        w.Id.Int64,
+		UserId:    w.UserId.Int64,
+		GalleryId: w.GalleryId.Int64,
+		Type:      w.Type.String,
+		CreatedAt: w.CreatedAt.Time,
+		UpdatedAt: w.UpdatedAt.Time,
+	}
+}
+
+// CreativeGalleryInteractionModel is a model which encapsulates the operations of the object
+type CreativeGalleryInteractionModel struct {
+	db        *query.DatabaseWrap
+	tableName string
+
+	excludeGlobalScopes []string
+	includeLocalScopes  []string
+
+	query query.SQLBuilder
+}
+
+var creativeGalleryInteractionTableName = "creative_gallery_interaction"
+
+// CreativeGalleryInteractionTable return table name for CreativeGalleryInteraction
+func CreativeGalleryInteractionTable() string {
+	return creativeGalleryInteractionTableName
+}
+
+const (
+	FieldCreativeGalleryInteractionId        = "id"
+	FieldCreativeGalleryInteractionUserId    = "user_id"
+	FieldCreativeGalleryInteractionGalleryId = "gallery_id"
+	FieldCreativeGalleryInteractionType      = "type"
+	FieldCreativeGalleryInteractionCreatedAt = "created_at"
+	FieldCreativeGalleryInteractionUpdatedAt = "updated_at"
+)
+
+// CreativeGalleryInteractionFields return all fields in CreativeGalleryInteraction model
+func CreativeGalleryInteractionFields() []string {
+	return []string{
+		"id",
+		"user_id",
+		"gallery_id",
+		"type",
+		"created_at",
+		"updated_at",
+	}
+}
+
+func SetCreativeGalleryInteractionTable(tableName string) {
+	creativeGalleryInteractionTableName = tableName
+}
+
+// NewCreativeGalleryInteractionModel create a CreativeGalleryInteractionModel
+func NewCreativeGalleryInteractionModel(db query.Database) *CreativeGalleryInteractionModel {
+	return &CreativeGalleryInteractionModel{
+		db:                  query.NewDatabaseWrap(db),
+		tableName:           creativeGalleryInteractionTableName,
+		excludeGlobalScopes: make([]string, 0),
+		includeLocalScopes:  make([]string, 0),
+		query:               query.Builder(),
+	}
+}
+
+// GetDB return database instance
+func (m *CreativeGalleryInteractionModel) GetDB() query.Database {
+	return m.db.GetDB()
+}
+
+func (m *CreativeGalleryInteractionModel) clone() *CreativeGalleryInteractionModel {
+	return &CreativeGalleryInteractionModel{
+		db:                  m.db,
+		tableName:           m.tableName,
+		excludeGlobalScopes: append([]string{}, m.excludeGlobalScopes...),
+		includeLocalScopes:  append([]string{}, m.includeLocalScopes...),
+		query:               m.query,
+	}
+}
+
+// WithoutGlobalScopes remove a global scope for given query
+func (m *CreativeGalleryInteractionModel) WithoutGlobalScopes(names ...string) *CreativeGalleryInteractionModel {
+	mc := m.clone()
+	mc.excludeGlobalScopes = append(mc.excludeGlobalScopes, names...)
+
+	return mc
+}
+
+// WithLocalScopes add a local scope for given query
+func (m *CreativeGalleryInteractionModel) WithLocalScopes(names ...string) *CreativeGalleryInteractionModel {
+	mc := m.clone()
+	mc.includeLocalScopes = append(mc.includeLocalScopes, names...)
+
+	return mc
+}
+
+// Condition add query builder to model
+func (m *CreativeGalleryInteractionModel) Condition(builder query.SQLBuilder) *CreativeGalleryInteractionModel {
+	mm := m.clone()
+	mm.query = mm.query.Merge(builder)
+
+	return mm
+}
+
+// Find retrieve a model by its primary key
+func (m *CreativeGalleryInteractionModel) Find(ctx context.Context, id int64) (*CreativeGalleryInteractionN, error) {
+	return m.First(ctx, m.query.Where("id", "=", id))
+}
+
+// Exists return whether the records exists for a given query
+func (m *CreativeGalleryInteractionModel) Exists(ctx context.Context, builders ...query.SQLBuilder) (bool, error) {
+	count, err := m.Count(ctx, builders...)
+	return count > 0, err
+}
+
+// Count return model count for a given query
+func (m *CreativeGalleryInteractionModel) Count(ctx context.Context, builders ...query.SQLBuilder) (int64, error) {
+	sqlStr, params := m.query.
+		Merge(builders...).
+		Table(m.tableName).
+		AppendCondition(m.applyScope()).
+		ResolveCount()
+
+	rows, err := m.db.QueryContext(ctx, sqlStr, params...)
+	if err != nil {
+		return 0, err
+	}
+
+	defer rows.Close()
+
+	rows.Next()
+	var res int64
+	if err := rows.Scan(&res); err != nil {
+		return 0, err
+	}
+
+	return res, nil
+}
+
+func (m *CreativeGalleryInteractionModel) Paginate(ctx context.Context, page int64, perPage int64, builders ...query.SQLBuilder) ([]CreativeGalleryInteractionN, query.PaginateMeta, error) {
+	if page <= 0 {
+		page = 1
+	}
+
+	if perPage <= 0 {
+		perPage = 15
+	}
+
+	meta := query.PaginateMeta{
+		PerPage: perPage,
+		Page:    page,
+	}
+
+	count, err := m.Count(ctx, builders...)
+	if err != nil {
+		return nil, meta, err
+	}
+
+	meta.Total = count
+	meta.LastPage = count / perPage
+	if count%perPage != 0 {
+		meta.LastPage += 1
+	}
+
+	res, err := m.Get(ctx, append([]query.SQLBuilder{query.Builder().Limit(perPage).Offset((page - 1) * perPage)}, builders...)...)
+	if err != nil {
+		return res, meta, err
+	}
+
+	return res, meta, nil
+}
+
+// Get retrieve all results for given query
+func (m *CreativeGalleryInteractionModel) Get(ctx context.Context, builders ...query.SQLBuilder) ([]CreativeGalleryInteractionN, error) {
+	b := m.query.Merge(builders...).Table(m.tableName).AppendCondition(m.applyScope())
+	if len(b.GetFields()) == 0 {
+		b = b.Select(
+			"id",
+			"user_id",
+			"gallery_id",
+			"type",
+			"created_at",
+			"updated_at",
+		)
+	}
+
+	fields := b.GetFields()
+	selectFields := make([]query.Expr, 0)
+
+	for _, f := range fields {
+		switch strcase.ToSnake(f.Value) {
+
+		case "id":
+			selectFields = append(selectFields, f)
+		case "user_id":
+			selectFields = append(selectFields, f)
+		case "gallery_id":
+			selectFields = append(selectFields, f)
+		case "type":
+			selectFields = append(selectFields, f)
+		case "created_at":
+			selectFields = append(selectFields, f)
+		case "updated_at":
+			selectFields = append(selectFields, f)
+		}
+	}
+
+	var createScanVar = func(fields []query.Expr) (*CreativeGalleryInteractionN, []interface{}) {
+		var creativeGalleryInteractionVar CreativeGalleryInteractionN
+		scanFields := make([]interface{}, 0)
+
+		for _, f := range fields {
+			switch strcase.ToSnake(f.Value) {
+
+			case "id":
+				scanFields = append(scanFields, &creativeGalleryInteractionVar.Id)
+			case "user_id":
+				scanFields = append(scanFields, &creativeGalleryInteractionVar.UserId)
+			case "gallery_id":
+				scanFields = append(scanFields, &creativeGalleryInteractionVar.GalleryId)
+			case "type":
+				scanFields = append(scanFields, &creativeGalleryInteractionVar.Type)
+			case "created_at":
+				scanFields = append(scanFields, &creativeGalleryInteractionVar.CreatedAt)
+			case "updated_at":
+				scanFields = append(scanFields, &creativeGalleryInteractionVar.UpdatedAt)
+			}
+		}
+
+		return &creativeGalleryInteractionVar, scanFields
+	}
+
+	sqlStr, params := b.Fields(selectFields...).ResolveQuery()
+
+	rows, err := m.db.QueryContext(ctx, sqlStr, params...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	creativeGalleryInteractions := make([]CreativeGalleryInteractionN, 0)
+	for rows.Next() {
+		creativeGalleryInteractionReal, scanFields := createScanVar(fields)
+		if err := rows.Scan(scanFields...); err != nil {
+			return nil, err
+		}
+
+		creativeGalleryInteractionReal.original = &creativeGalleryInteractionOriginal{}
+		_ = query.Copy(creativeGalleryInteractionReal, creativeGalleryInteractionReal.original)
+
+		creativeGalleryInteractionReal.SetModel(m)
+		creativeGalleryInteractions = append(creativeGalleryInteractions, *creativeGalleryInteractionReal)
+	}
+
+	return creativeGalleryInteractions, nil
+}
+
+// First return first result for given query
+func (m *CreativeGalleryInteractionModel) First(ctx context.Context, builders ...query.SQLBuilder) (*CreativeGalleryInteractionN, error) {
+	res, err := m.Get(ctx, append(builders, query.Builder().Limit(1))...)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res) == 0 {
+		return nil, query.ErrNoResult
+	}
+
+	return &res[0], nil
+}
+
+// Create save a new creative_gallery_interaction to database
+func (m *CreativeGalleryInteractionModel) Create(ctx context.Context, kv query.KV) (int64, error) {
+
+	if _, ok := kv["created_at"]; !ok {
+		kv["created_at"] = time.Now()
+	}
+
+	if _, ok := kv["updated_at"]; !ok {
+		kv["updated_at"] = time.Now()
+	}
+
+	sqlStr, params := m.query.Table(m.tableName).ResolveInsert(kv)
+
+	res, err := m.db.ExecContext(ctx, sqlStr, params...)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.LastInsertId()
+}
+
+// SaveAll save all creative_gallery_interactions to database
+func (m *CreativeGalleryInteractionModel) SaveAll(ctx context.Context, creativeGalleryInteractions []CreativeGalleryInteractionN) ([]int64, error) {
+	ids := make([]int64, 0)
+	for _, creativeGalleryInteraction := range creativeGalleryInteractions {
+		id, err := m.Save(ctx, creativeGalleryInteraction)
+		if err != nil {
+			return ids, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// Save save a creative_gallery_interaction to database
+func (m *CreativeGalleryInteractionModel) Save(ctx context.Context, creativeGalleryInteraction CreativeGalleryInteractionN, onlyFields ...string) (int64, error) {
+	return m.Create(ctx, creativeGalleryInteraction.StaledKV(onlyFields...))
+}
+
+// SaveOrUpdate save a new creative_gallery_interaction or update it when it has a id > 0
+func (m *CreativeGalleryInteractionModel) SaveOrUpdate(ctx context.Context, creativeGalleryInteraction CreativeGalleryInteractionN, onlyFields ...string) (id int64, updated bool, err error) {
+	if creativeGalleryInteraction.Id.Int64 > 0 {
+		_, _err := m.UpdateById(ctx, creativeGalleryInteraction.Id.Int64, creativeGalleryInteraction, onlyFields...)
+		return creativeGalleryInteraction.Id.Int64, true, _err
+	}
+
+	_id, _err := m.Save(ctx, creativeGalleryInteraction, onlyFields...)
+	return _id, false, _err
+}
+
+// UpdateFields update kv for a given query
+func (m *CreativeGalleryInteractionModel) UpdateFields(ctx context.Context, kv query.KV, builders ...query.SQLBuilder) (int64, error) {
+	if len(kv) == 0 {
+		return 0, nil
+	}
+
+	kv["updated_at"] = time.Now()
+
+	sqlStr, params := m.query.Merge(builders...).AppendCondition(m.applyScope()).
+		Table(m.tableName).
+		ResolveUpdate(kv)
+
+	res, err := m.db.ExecContext(ctx, sqlStr, params...)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
+// Update update a model for given query
+func (m *CreativeGalleryInteractionModel) Update(ctx context.Context, builder query.SQLBuilder, creativeGalleryInteraction CreativeGalleryInteractionN, onlyFields ...string) (int64, error) {
+	return m.UpdateFields(ctx, creativeGalleryInteraction.StaledKV(onlyFields...), builder)
+}
+
+// UpdateById update a model by id
+func (m *CreativeGalleryInteractionModel) UpdateById(ctx context.Context, id int64, creativeGalleryInteraction CreativeGalleryInteractionN, onlyFields ...string) (int64, error) {
+	return m.Condition(query.Builder().Where("id", "=", id)).UpdateFields(ctx, creativeGalleryInteraction.StaledKV(onlyFields...))
+}
+
+// Delete remove a model
+func (m *CreativeGalleryInteractionModel) Delete(ctx context.Context, builders ...query.SQLBuilder) (int64, error) {
+
+	sqlStr, params := m.query.Merge(builders...).AppendCondition(m.applyScope()).Table(m.tableName).ResolveDelete()
+
+	res, err := m.db.ExecContext(ctx, sqlStr, params...)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+
+}
+
+// DeleteById remove a model by id
+func (m *CreativeGalleryInteractionModel) DeleteById(ctx context.Context, id int64) (int64, error) {
+	return m.Condition(query.Builder().Where("id", "=", id)).Delete(ctx)
+}
+
+// CreativeGalleryCommentN is a CreativeGalleryComment object, all fields are nullable
+type CreativeGalleryCommentN struct {
+	original                    *creativeGalleryCommentOriginal
+	creativeGalleryCommentModel *CreativeGalleryCommentModel
+
+	Id        null.Int    `json:"id"`
+	GalleryId null.Int    `json:"gallery_id"`
+	UserId    null.Int    `json:"user_id"`
+	Username  null.String `json:"username,omitempty"`
+	Content   null.String `json:"content"`
+	Status    null.Int    `json:"status"`
+	CreatedAt null.Time
+	UpdatedAt null.Time
+}
+
+// As convert object to other type
+// dst must be a pointer to struct
+func (inst *CreativeGalleryCommentN) As(dst interface{}) error {
+	return query.Copy(inst, dst)
+}
+
+// SetModel set model for CreativeGalleryComment
+func (inst *CreativeGalleryCommentN) SetModel(creativeGalleryCommentModel *CreativeGalleryCommentModel) {
+	inst.creativeGalleryCommentModel = creativeGalleryCommentModel
+}
+
+// creativeGalleryCommentOriginal is an object which stores original CreativeGalleryComment from database
+type creativeGalleryCommentOriginal struct {
+	Id        null.Int
+	GalleryId null.Int
+	UserId    null.Int
+	Username  null.String
+	Content   null.String
+	Status    null.Int
+	CreatedAt null.Time
+	UpdatedAt null.Time
+}
+
+// Staled identify whether the object has been modified
+func (inst *CreativeGalleryCommentN) Staled(onlyFields ...string) bool {
+	if inst.original == nil {
+		inst.original = &creativeGalleryCommentOriginal{}
+	}
+
+	if len(onlyFields) == 0 {
+
+		if inst.Id != inst.original.Id {
+			return true
+		}
+		if inst.GalleryId != inst.original.GalleryId {
+			return true
+		}
+		if inst.UserId != inst.original.UserId {
+			return true
+		}
+		if inst.Username != inst.original.Username {
+			return true
+		}
+		if inst.Content != inst.original.Content {
+			return true
+		}
+		if inst.Status != inst.original.Status {
+			return true
+		}
+		if inst.CreatedAt != inst.original.CreatedAt {
+			return true
+		}
+		if inst.UpdatedAt != inst.original.UpdatedAt {
+			return true
+		}
+	} else {
+		for _, f := range onlyFields {
+			switch strcase.ToSnake(f) {
+
+			case "id":
+				if inst.Id != inst.original.Id {
+					return true
+				}
+			case "gallery_id":
+				if inst.GalleryId != inst.original.GalleryId {
+					return true
+				}
+			case "user_id":
+				if inst.UserId != inst.original.UserId {
+					return true
+				}
+			case "username":
+				if inst.Username != inst.original.Username {
+					return true
+				}
+			case "content":
+				if inst.Content != inst.original.Content {
+					return true
+				}
+			case "status":
+				if inst.Status != inst.original.Status {
+					return true
+				}
+			case "created_at":
+				if inst.CreatedAt != inst.original.CreatedAt {
+					return true
+				}
+			case "updated_at":
+				if inst.UpdatedAt != inst.original.UpdatedAt {
+					return true
+				}
+			default:
+			}
+		}
+	}
+
+	return false
+}
+
+// StaledKV return all fields has been modified
+func (inst *CreativeGalleryCommentN) StaledKV(onlyFields ...string) query.KV {
+	kv := make(query.KV, 0)
+
+	if inst.original == nil {
+		inst.original = &creativeGalleryCommentOriginal{}
+	}
+
+	if len(onlyFields) == 0 {
+
+		if inst.Id != inst.original.Id {
+			kv["id"] = inst.Id
+		}
+		if inst.GalleryId != inst.original.GalleryId {
+			kv["gallery_id"] = inst.GalleryId
+		}
+		if inst.UserId != inst.original.UserId {
+			kv["user_id"] = inst.UserId
+		}
+		if inst.Username != inst.original.Username {
+			kv["username"] = inst.Username
+		}
+		if inst.Content != inst.original.Content {
+			kv["content"] = inst.Content
+		}
+		if inst.Status != inst.original.Status {
+			kv["status"] = inst.Status
+		}
+		if inst.CreatedAt != inst.original.CreatedAt {
+			kv["created_at"] = inst.CreatedAt
+		}
+		if inst.UpdatedAt != inst.original.UpdatedAt {
+			kv["updated_at"] = inst.UpdatedAt
+		}
+	} else {
+		for _, f := range onlyFields {
+			switch strcase.ToSnake(f) {
+
+			case "id":
+				if inst.Id != inst.original.Id {
+					kv["id"] = inst.Id
+				}
+			case "gallery_id":
+				if inst.GalleryId != inst.original.GalleryId {
+					kv["gallery_id"] = inst.GalleryId
+				}
+			case "user_id":
+				if inst.UserId != inst.original.UserId {
+					kv["user_id"] = inst.UserId
+				}
+			case "username":
+				if inst.Username != inst.original.Username {
+					kv["username"] = inst.Username
+				}
+			case "content":
+				if inst.Content != inst.original.Content {
+					kv["content"] = inst.Content
+				}
+			case "status":
+				if inst.Status != inst.original.Status {
+					kv["status"] = inst.Status
+				}
+			case "created_at":
+				if inst.CreatedAt != inst.original.CreatedAt {
+					kv["created_at"] = inst.CreatedAt
+				}
+			case "updated_at":
+				if inst.UpdatedAt != inst.original.UpdatedAt {
+					kv["updated_at"] = inst.UpdatedAt
+				}
+			default:
+			}
+		}
+	}
+
+	return kv
+}
+
+// Save create a new model or update it
+func (inst *CreativeGalleryCommentN) Save(ctx context.Context, onlyFields ...string) error {
+	if inst.creativeGalleryCommentModel == nil {
+		return query.ErrModelNotSet
+	}
+
+	id, _, err := inst.creativeGalleryCommentModel.SaveOrUpdate(ctx, *inst, onlyFields...)
+	if err != nil {
+		return err
+	}
+
+	inst.Id = null.IntFrom(id)
+	return nil
+}
+
+// Delete remove a creative_gallery_comment
+func (inst *CreativeGalleryCommentN) Delete(ctx context.Context) error {
+	if inst.creativeGalleryCommentModel == nil {
+		return query.ErrModelNotSet
+	}
+
+	_, err := inst.creativeGalleryCommentModel.DeleteById(ctx, inst.Id.Int64)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// String convert instance to json string
+func (inst *CreativeGalleryCommentN) String() string {
+	rs, _ := json.Marshal(inst)
+	return string(rs)
+}
+
+type creativeGalleryCommentScope struct {
+	name  string
+	apply func(builder query.Condition)
+}
+
+var creativeGalleryCommentGlobalScopes = make([]creativeGalleryCommentScope, 0)
+var creativeGalleryCommentLocalScopes = make([]creativeGalleryCommentScope, 0)
+
+// AddGlobalScopeForCreativeGalleryComment assign a global scope to a model
+func AddGlobalScopeForCreativeGalleryComment(name string, apply func(builder query.Condition)) {
+	creativeGalleryCommentGlobalScopes = append(creativeGalleryCommentGlobalScopes, creativeGalleryCommentScope{name: name, apply: apply})
+}
+
+// AddLocalScopeForCreativeGalleryComment assign a local scope to a model
+func AddLocalScopeForCreativeGalleryComment(name string, apply func(builder query.Condition)) {
+	creativeGalleryCommentLocalScopes = append(creativeGalleryCommentLocalScopes, creativeGalleryCommentScope{name: name, apply: apply})
+}
+
+func (m *CreativeGalleryCommentModel) applyScope() query.Condition {
+	scopeCond := query.ConditionBuilder()
+	for _, g := range creativeGalleryCommentGlobalScopes {
+		if m.globalScopeEnabled(g.name) {
+			g.apply(scopeCond)
+		}
+	}
+
+	for _, s := range creativeGalleryCommentLocalScopes {
+		if m.localScopeEnabled(s.name) {
+			s.apply(scopeCond)
+		}
+	}
+
+	return scopeCond
+}
+
+func (m *CreativeGalleryCommentModel) localScopeEnabled(name string) bool {
+	for _, n := range m.includeLocalScopes {
+		if name == n {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m *CreativeGalleryCommentModel) globalScopeEnabled(name string) bool {
+	for _, n := range m.excludeGlobalScopes {
+		if name == n {
+			return false
+		}
+	}
+
+	return true
+}
+
+type CreativeGalleryComment struct {
+	Id        int64  `json:"id"`
+	GalleryId int64  `json:"gallery_id"`
+	UserId    int64  `json:"user_id"`
+	Username  string `json:"username,omitempty"`
+	Content   string `json:"content"`
+	Status    int64  `json:"status"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (w CreativeGalleryComment) ToCreativeGalleryCommentN(allows ...string) CreativeGalleryCommentN {
+	if len(allows) == 0 {
+		return CreativeGalleryCommentN{
+
+			Id:        null.IntFrom(int64(w.Id)),
+			GalleryId: null.IntFrom(int64(w.GalleryId)),
+			UserId:    null.IntFrom(int64(w.UserId)),
+			Username:  null.StringFrom(w.Username),
+			Content:   null.StringFrom(w.Content),
+			Status:    null.IntFrom(int64(w.Status)),
+			CreatedAt: null.TimeFrom(w.CreatedAt),
+			UpdatedAt: null.TimeFrom(w.UpdatedAt),
+		}
+	}
+
+	res := CreativeGalleryCommentN{}
+	for _, al := range allows {
+		switch strcase.ToSnake(al) {
+
+		case "id":
+			res.Id = null.IntFrom(int64(w.Id))
+		case "gallery_id":
+			res.GalleryId = null.IntFrom(int64(w.GalleryId))
+		case "user_id":
+			res.UserId = null.IntFrom(int64(w.UserId))
+		case "username":
+			res.Username = null.StringFrom(w.Username)
+		case "content":
+			res.Content = null.StringFrom(w.Content)
+		case "status":
+			res.Status = null.IntFrom(int64(w.Status))
+		case "created_at":
+			res.CreatedAt = null.TimeFrom(w.CreatedAt)
+		case "updated_at":
+			res.UpdatedAt = null.TimeFrom(w.UpdatedAt)
+		default:
+		}
+	}
+
+	return res
+}
+
+// As convert object to other type
+// dst must be a pointer to struct
+func (w CreativeGalleryComment) As(dst interface{}) error {
+	return query.Copy(w, dst)
+}
+
+func (w *CreativeGalleryCommentN) ToCreativeGalleryComment() CreativeGalleryComment {
+	return CreativeGalleryComment{
+
+		Id:        w.Id.Int64,
+		GalleryId: w.GalleryId.Int64,
+		UserId:    w.UserId.Int64,
+		Username:  w.Username.String,
+		Content:   w.Content.String,
+		Status:    w.Status.Int64,
+		CreatedAt: w.CreatedAt.Time,
+		UpdatedAt: w.UpdatedAt.Time,
+	}
+}
+
+// CreativeGalleryCommentModel is a model which encapsulates the operations of the object
+type CreativeGalleryCommentModel struct {
+	db        *query.DatabaseWrap
+	tableName string
+
+	excludeGlobalScopes []string
+	includeLocalScopes  []string
+
+	query query.SQLBuilder
+}
+
+var creativeGalleryCommentTableName = "creative_gallery_comment"
+
+// CreativeGalleryCommentTable return table name for CreativeGalleryComment
+func CreativeGalleryCommentTable() string {
+	return creativeGalleryCommentTableName
+}
+
+const (
+	FieldCreativeGalleryCommentId        = "id"
+	FieldCreativeGalleryCommentGalleryId = "gallery_id"
+	FieldCreativeGalleryCommentUserId    = "user_id"
+	FieldCreativeGalleryCommentUsername  = "username"
+	FieldCreativeGalleryCommentContent   = "content"
+	FieldCreativeGalleryCommentStatus    = "status"
+	FieldCreativeGalleryCommentCreatedAt = "created_at"
+	FieldCreativeGalleryCommentUpdatedAt = "updated_at"
+)
+
+// CreativeGalleryCommentFields return all fields in CreativeGalleryComment model
+func CreativeGalleryCommentFields() []string {
+	return []string{
+		"id",
+		"gallery_id",
+		"user_id",
+		"username",
+		"content",
+		"status",
+		"created_at",
+		"updated_at",
+	}
+}
+
+func SetCreativeGalleryCommentTable(tableName string) {
+	creativeGalleryCommentTableName = tableName
+}
+
+// NewCreativeGalleryCommentModel create a CreativeGalleryCommentModel
+func NewCreativeGalleryCommentModel(db query.Database) *CreativeGalleryCommentModel {
+	return &CreativeGalleryCommentModel{
+		db:                  query.NewDatabaseWrap(db),
+		tableName:           creativeGalleryCommentTableName,
+		excludeGlobalScopes: make([]string, 0),
+		includeLocalScopes:  make([]string, 0),
+		query:               query.Builder(),
+	}
+}
+
+// GetDB return database instance
+func (m *CreativeGalleryCommentModel) GetDB() query.Database {
+	return m.db.GetDB()
+}
+
+func (m *CreativeGalleryCommentModel) clone() *CreativeGalleryCommentModel {
+	return &CreativeGalleryCommentModel{
+		db:                  m.db,
+		tableName:           m.tableName,
+		excludeGlobalScopes: append([]string{}, m.excludeGlobalScopes...),
+		includeLocalScopes:  append([]string{}, m.includeLocalScopes...),
+		query:               m.query,
+	}
+}
+
+// WithoutGlobalScopes remove a global scope for given query
+func (m *CreativeGalleryCommentModel) WithoutGlobalScopes(names ...string) *CreativeGalleryCommentModel {
+	mc := m.clone()
+	mc.excludeGlobalScopes = append(mc.excludeGlobalScopes, names...)
+
+	return mc
+}
+
+// WithLocalScopes add a local scope for given query
+func (m *CreativeGalleryCommentModel) WithLocalScopes(names ...string) *CreativeGalleryCommentModel {
+	mc := m.clone()
+	mc.includeLocalScopes = append(mc.includeLocalScopes, names...)
+
+	return mc
+}
+
+// Condition add query builder to model
+func (m *CreativeGalleryCommentModel) Condition(builder query.SQLBuilder) *CreativeGalleryCommentModel {
+	mm := m.clone()
+	mm.query = mm.query.Merge(builder)
+
+	return mm
+}
+
+// Find retrieve a model by its primary key
+func (m *CreativeGalleryCommentModel) Find(ctx context.Context, id int64) (*CreativeGalleryCommentN, error) {
+	return m.First(ctx, m.query.Where("id", "=", id))
+}
+
+// Exists return whether the records exists for a given query
+func (m *CreativeGalleryCommentModel) Exists(ctx context.Context, builders ...query.SQLBuilder) (bool, error) {
+	count, err := m.Count(ctx, builders...)
+	return count > 0, err
+}
+
+// Count return model count for a given query
+func (m *CreativeGalleryCommentModel) Count(ctx context.Context, builders ...query.SQLBuilder) (int64, error) {
+	sqlStr, params := m.query.
+		Merge(builders...).
+		Table(m.tableName).
+		AppendCondition(m.applyScope()).
+		ResolveCount()
+
+	rows, err := m.db.QueryContext(ctx, sqlStr, params...)
+	if err != nil {
+		return 0, err
+	}
+
+	defer rows.Close()
+
+	rows.Next()
+	var res int64
+	if err := rows.Scan(&res); err != nil {
+		return 0, err
+	}
+
+	return res, nil
+}
+
+func (m *CreativeGalleryCommentModel) Paginate(ctx context.Context, page int64, perPage int64, builders ...query.SQLBuilder) ([]CreativeGalleryCommentN, query.PaginateMeta, error) {
+	if page <= 0 {
+		page = 1
+	}
+
+	if perPage <= 0 {
+		perPage = 15
+	}
+
+	meta := query.PaginateMeta{
+		PerPage: perPage,
+		Page:    page,
+	}
+
+	count, err := m.Count(ctx, builders...)
+	if err != nil {
+		return nil, meta, err
+	}
+
+	meta.Total = count
+	meta.LastPage = count / perPage
+	if count%perPage != 0 {
+		meta.LastPage += 1
+	}
+
+	res, err := m.Get(ctx, append([]query.SQLBuilder{query.Builder().Limit(perPage).Offset((page - 1) * perPage)}, builders...)...)
+	if err != nil {
+		return res, meta, err
+	}
+
+	return res, meta, nil
+}
+
+// Get retrieve all results for given query
+func (m *CreativeGalleryCommentModel) Get(ctx context.Context, builders ...query.SQLBuilder) ([]CreativeGalleryCommentN, error) {
+	b := m.query.Merge(builders...).Table(m.tableName).AppendCondition(m.applyScope())
+	if len(b.GetFields()) == 0 {
+		b = b.Select(
+			"id",
+			"gallery_id",
+			"user_id",
+			"username",
+			"content",
+			"status",
+			"created_at",
+			"updated_at",
+		)
+	}
+
+	fields := b.GetFields()
+	selectFields := make([]query.Expr, 0)
+
+	for _, f := range fields {
+		switch strcase.ToSnake(f.Value) {
+
+		case "id":
+			selectFields = append(selectFields, f)
+		case "gallery_id":
+			selectFields = append(selectFields, f)
+		case "user_id":
+			selectFields = append(selectFields, f)
+		case "username":
+			selectFields = append(selectFields, f)
+		case "content":
+			selectFields = append(selectFields, f)
+		case "status":
+			selectFields = append(selectFields, f)
+		case "created_at":
+			selectFields = append(selectFields, f)
+		case "updated_at":
+			selectFields = append(selectFields, f)
+		}
+	}
+
+	var createScanVar = func(fields []query.Expr) (*CreativeGalleryCommentN, []interface{}) {
+		var creativeGalleryCommentVar CreativeGalleryCommentN
+		scanFields := make([]interface{}, 0)
+
+		for _, f := range fields {
+			switch strcase.ToSnake(f.Value) {
+
+			case "id":
+				scanFields = append(scanFields, &creativeGalleryCommentVar.Id)
+			case "gallery_id":
+				scanFields = append(scanFields, &creativeGalleryCommentVar.GalleryId)
+			case "user_id":
+				scanFields = append(scanFields, &creativeGalleryCommentVar.UserId)
+			case "username":
+				scanFields = append(scanFields, &creativeGalleryCommentVar.Username)
+			case "content":
+				scanFields = append(scanFields, &creativeGalleryCommentVar.Content)
+			case "status":
+				scanFields = append(scanFields, &creativeGalleryCommentVar.Status)
+			case "created_at":
+				scanFields = append(scanFields, &creativeGalleryCommentVar.CreatedAt)
+			case "updated_at":
+				scanFields = append(scanFields, &creativeGalleryCommentVar.UpdatedAt)
+			}
+		}
+
+		return &creativeGalleryCommentVar, scanFields
+	}
+
+	sqlStr, params := b.Fields(selectFields...).ResolveQuery()
+
+	rows, err := m.db.QueryContext(ctx, sqlStr, params...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	creativeGalleryComments := make([]CreativeGalleryCommentN, 0)
+	for rows.Next() {
+		creativeGalleryCommentReal, scanFields := createScanVar(fields)
+		if err := rows.Scan(scanFields...); err != nil {
+			return nil, err
+		}
+
+		creativeGalleryCommentReal.original = &creativeGalleryCommentOriginal{}
+		_ = query.Copy(creativeGalleryCommentReal, creativeGalleryCommentReal.original)
+
+		creativeGalleryCommentReal.SetModel(m)
+		creativeGalleryComments = append(creativeGalleryComments, *creativeGalleryCommentReal)
+	}
+
+	return creativeGalleryComments, nil
+}
+
+// First return first result for given query
+func (m *CreativeGalleryCommentModel) First(ctx context.Context, builders ...query.SQLBuilder) (*CreativeGalleryCommentN, error) {
+	res, err := m.Get(ctx, append(builders, query.Builder().Limit(1))...)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res) == 0 {
+		return nil, query.ErrNoResult
+	}
+
+	return &res[0], nil
+}
+
+// Create save a new creative_gallery_comment to database
+func (m *CreativeGalleryCommentModel) Create(ctx context.Context, kv query.KV) (int64, error) {
+
+	if _, ok := kv["created_at"]; !ok {
+		kv["created_at"] = time.Now()
+	}
+
+	if _, ok := kv["updated_at"]; !ok {
+		kv["updated_at"] = time.Now()
+	}
+
+	sqlStr, params := m.query.Table(m.tableName).ResolveInsert(kv)
+
+	res, err := m.db.ExecContext(ctx, sqlStr, params...)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.LastInsertId()
+}
+
+// SaveAll save all creative_gallery_comments to database
+func (m *CreativeGalleryCommentModel) SaveAll(ctx context.Context, creativeGalleryComments []CreativeGalleryCommentN) ([]int64, error) {
+	ids := make([]int64, 0)
+	for _, creativeGalleryComment := range creativeGalleryComments {
+		id, err := m.Save(ctx, creativeGalleryComment)
+		if err != nil {
+			return ids, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// Save save a creative_gallery_comment to database
+func (m *CreativeGalleryCommentModel) Save(ctx context.Context, creativeGalleryComment CreativeGalleryCommentN, onlyFields ...string) (int64, error) {
+	return m.Create(ctx, creativeGalleryComment.StaledKV(onlyFields...))
+}
+
+// SaveOrUpdate save a new creative_gallery_comment or update it when it has a id > 0
+func (m *CreativeGalleryCommentModel) SaveOrUpdate(ctx context.Context, creativeGalleryComment CreativeGalleryCommentN, onlyFields ...string) (id int64, updated bool, err error) {
+	if creativeGalleryComment.Id.Int64 > 0 {
+		_, _err := m.UpdateById(ctx, creativeGalleryComment.Id.Int64, creativeGalleryComment, onlyFields...)
+		return creativeGalleryComment.Id.Int64, true, _err
+	}
+
+	_id, _err := m.Save(ctx, creativeGalleryComment, onlyFields...)
+	return _id, false, _err
+}
+
+// UpdateFields update kv for a given query
+func (m *CreativeGalleryCommentModel) UpdateFields(ctx context.Context, kv query.KV, builders ...query.SQLBuilder) (int64, error) {
+	if len(kv) == 0 {
+		return 0, nil
+	}
+
+	kv["updated_at"] = time.Now()
+
+	sqlStr, params := m.query.Merge(builders...).AppendCondition(m.applyScope()).
+		Table(m.tableName).
+		ResolveUpdate(kv)
+
+	res, err := m.db.ExecContext(ctx, sqlStr, params...)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
+// Update update a model for given query
+func (m *CreativeGalleryCommentModel) Update(ctx context.Context, builder query.SQLBuilder, creativeGalleryComment CreativeGalleryCommentN, onlyFields ...string) (int64, error) {
+	return m.UpdateFields(ctx, creativeGalleryComment.StaledKV(onlyFields...), builder)
+}
+
+// UpdateById update a model by id
+func (m *CreativeGalleryCommentModel) UpdateById(ctx context.Context, id int64, creativeGalleryComment CreativeGalleryCommentN, onlyFields ...string) (int64, error) {
+	return m.Condition(query.Builder().Where("id", "=", id)).UpdateFields(ctx, creativeGalleryComment.StaledKV(onlyFields...))
+}
+
+// Delete remove a model
+func (m *CreativeGalleryCommentModel) Delete(ctx context.Context, builders ...query.SQLBuilder) (int64, error) {
+
+	sqlStr, params := m.query.Merge(builders...).AppendCondition(m.applyScope()).Table(m.tableName).ResolveDelete()
+
+	res, err := m.db.ExecContext(ctx, sqlStr, params...)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+
+}
+
+// DeleteById remove a model by id
+func (m *CreativeGalleryCommentModel) DeleteById(ctx context.Context, id int64) (int64, error) {
+	return m.Condition(query.Builder().Where("id", "=", id)).Delete(ctx)
+}
+
+// CreativeGalleryReportN is a CreativeGalleryReport object, all fields are nullable
+type CreativeGalleryReportN struct {
+	original                   *creativeGalleryReportOriginal
+	creativeGalleryReportModel *CreativeGalleryReportModel
+
+	Id        null.Int    `json:"id"`
+	GalleryId null.Int    `json:"gallery_id"`
+	UserId    null.Int    `json:"user_id"`
+	Reason    null.String `json:"reason"`
+	Comment   null.String `json:"comment,omitempty"`
+	Status    null.Int    `json:"status"`
+	HandledBy null.Int    `json:"handled_by,omitempty"`
+	HandledAt null.Time   `json:"handled_at,omitempty"`
+	CreatedAt null.Time
+	UpdatedAt null.Time
+}
+
+// As convert object to other type
+// dst must be a pointer to struct
+func (inst *CreativeGalleryReportN) As(dst interface{}) error {
+	return query.Copy(inst, dst)
+}
+
+// SetModel set model for CreativeGalleryReport
+func (inst *CreativeGalleryReportN) SetModel(creativeGalleryReportModel *CreativeGalleryReportModel) {
+	inst.creativeGalleryReportModel = creativeGalleryReportModel
+}
+
+// creativeGalleryReportOriginal is an object which stores original CreativeGalleryReport from database
+type creativeGalleryReportOriginal struct {
+	Id        null.Int
+	GalleryId null.Int
+	UserId    null.Int
+	Reason    null.String
+	Comment   null.String
+	Status    null.Int
+	HandledBy null.Int
+	HandledAt null.Time
+	CreatedAt null.Time
+	UpdatedAt null.Time
+}
+
+// Staled identify whether the object has been modified
+func (inst *CreativeGalleryReportN) Staled(onlyFields ...string) bool {
+	if inst.original == nil {
+		inst.original = &creativeGalleryReportOriginal{}
+	}
+
+	if len(onlyFields) == 0 {
+
+		if inst.Id != inst.original.Id {
+			return true
+		}
+		if inst.GalleryId != inst.original.GalleryId {
+			return true
+		}
+		if inst.UserId != inst.original.UserId {
+			return true
+		}
+		if inst.Reason != inst.original.Reason {
+			return true
+		}
+		if inst.Comment != inst.original.Comment {
+			return true
+		}
+		if inst.Status != inst.original.Status {
+			return true
+		}
+		if inst.HandledBy != inst.original.HandledBy {
+			return true
+		}
+		if inst.HandledAt != inst.original.HandledAt {
+			return true
+		}
+		if inst.CreatedAt != inst.original.CreatedAt {
+			return true
+		}
+		if inst.UpdatedAt != inst.original.UpdatedAt {
+			return true
+		}
+	} else {
+		for _, f := range onlyFields {
+			switch strcase.ToSnake(f) {
+
+			case "id":
+				if inst.Id != inst.original.Id {
+					return true
+				}
+			case "gallery_id":
+				if inst.GalleryId != inst.original.GalleryId {
+					return true
+				}
+			case "user_id":
+				if inst.UserId != inst.original.UserId {
+					return true
+				}
+			case "reason":
+				if inst.Reason != inst.original.Reason {
+					return true
+				}
+			case "comment":
+				if inst.Comment != inst.original.Comment {
+					return true
+				}
+			case "status":
+				if inst.Status != inst.original.Status {
+					return true
+				}
+			case "handled_by":
+				if inst.HandledBy != inst.original.HandledBy {
+					return true
+				}
+			case "handled_at":
+				if inst.HandledAt != inst.original.HandledAt {
+					return true
+				}
+			case "created_at":
+				if inst.CreatedAt != inst.original.CreatedAt {
+					return true
+				}
+			case "updated_at":
+				if inst.UpdatedAt != inst.original.UpdatedAt {
+					return true
+				}
+			default:
+			}
+		}
+	}
+
+	return false
+}
+
+// StaledKV return all fields has been modified
+func (inst *CreativeGalleryReportN) StaledKV(onlyFields ...string) query.KV {
+	kv := make(query.KV, 0)
+
+	if inst.original == nil {
+		inst.original = &creativeGalleryReportOriginal{}
+	}
+
+	if len(onlyFields) == 0 {
+
+		if inst.Id != inst.original.Id {
+			kv["id"] = inst.Id
+		}
+		if inst.GalleryId != inst.original.GalleryId {
+			kv["gallery_id"] = inst.GalleryId
+		}
+		if inst.UserId != inst.original.UserId {
+			kv["user_id"] = inst.UserId
+		}
+		if inst.Reason != inst.original.Reason {
+			kv["reason"] = inst.Reason
+		}
+		if inst.Comment != inst.original.Comment {
+			kv["comment"] = inst.Comment
+		}
+		if inst.Status != inst.original.Status {
+			kv["status"] = inst.Status
+		}
+		if inst.HandledBy != inst.original.HandledBy {
+			kv["handled_by"] = inst.HandledBy
+		}
+		if inst.HandledAt != inst.original.HandledAt {
+			kv["handled_at"] = inst.HandledAt
+		}
+		if inst.CreatedAt != inst.original.CreatedAt {
+			kv["created_at"] = inst.CreatedAt
+		}
+		if inst.UpdatedAt != inst.original.UpdatedAt {
+			kv["updated_at"] = inst.UpdatedAt
+		}
+	} else {
+		for _, f := range onlyFields {
+			switch strcase.ToSnake(f) {
+
+			case "id":
+				if inst.Id != inst.original.Id {
+					kv["id"] = inst.Id
+				}
+			case "gallery_id":
+				if inst.GalleryId != inst.original.GalleryId {
+					kv["gallery_id"] = inst.GalleryId
+				}
+			case "user_id":
+				if inst.UserId != inst.original.UserId {
+					kv["user_id"] = inst.UserId
+				}
+			case "reason":
+				if inst.Reason != inst.original.Reason {
+					kv["reason"] = inst.Reason
+				}
+			case "comment":
+				if inst.Comment != inst.original.Comment {
+					kv["comment"] = inst.Comment
+				}
+			case "status":
+				if inst.Status != inst.original.Status {
+					kv["status"] = inst.Status
+				}
+			case "handled_by":
+				if inst.HandledBy != inst.original.HandledBy {
+					kv["handled_by"] = inst.HandledBy
+				}
+			case "handled_at":
+				if inst.HandledAt != inst.original.HandledAt {
+					kv["handled_at"] = inst.HandledAt
+				}
+			case "created_at":
+				if inst.CreatedAt != inst.original.CreatedAt {
+					kv["created_at"] = inst.CreatedAt
+				}
+			case "updated_at":
+				if inst.UpdatedAt != inst.original.UpdatedAt {
+					kv["updated_at"] = inst.UpdatedAt
+				}
+			default:
+			}
+		}
+	}
+
+	return kv
+}
+
+// Save create a new model or update it
+func (inst *CreativeGalleryReportN) Save(ctx context.Context, onlyFields ...string) error {
+	if inst.creativeGalleryReportModel == nil {
+		return query.ErrModelNotSet
+	}
+
+	id, _, err := inst.creativeGalleryReportModel.SaveOrUpdate(ctx, *inst, onlyFields...)
+	if err != nil {
+		return err
+	}
+
+	inst.Id = null.IntFrom(id)
+	return nil
+}
+
+// Delete remove a creative_gallery_report
+func (inst *CreativeGalleryReportN) Delete(ctx context.Context) error {
+	if inst.creativeGalleryReportModel == nil {
+		return query.ErrModelNotSet
+	}
+
+	_, err := inst.creativeGalleryReportModel.DeleteById(ctx, inst.Id.Int64)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// String convert instance to json string
+func (inst *CreativeGalleryReportN) String() string {
+	rs, _ := json.Marshal(inst)
+	return string(rs)
+}
+
+type creativeGalleryReportScope struct {
+	name  string
+	apply func(builder query.Condition)
+}
+
+var creativeGalleryReportGlobalScopes = make([]creativeGalleryReportScope, 0)
+var creativeGalleryReportLocalScopes = make([]creativeGalleryReportScope, 0)
+
+// AddGlobalScopeForCreativeGalleryReport assign a global scope to a model
+func AddGlobalScopeForCreativeGalleryReport(name string, apply func(builder query.Condition)) {
+	creativeGalleryReportGlobalScopes = append(creativeGalleryReportGlobalScopes, creativeGalleryReportScope{name: name, apply: apply})
+}
+
+// AddLocalScopeForCreativeGalleryReport assign a local scope to a model
+func AddLocalScopeForCreativeGalleryReport(name string, apply func(builder query.Condition)) {
+	creativeGalleryReportLocalScopes = append(creativeGalleryReportLocalScopes, creativeGalleryReportScope{name: name, apply: apply})
+}
+
+func (m *CreativeGalleryReportModel) applyScope() query.Condition {
+	scopeCond := query.ConditionBuilder()
+	for _, g := range creativeGalleryReportGlobalScopes {
+		if m.globalScopeEnabled(g.name) {
+			g.apply(scopeCond)
+		}
+	}
+
+	for _, s := range creativeGalleryReportLocalScopes {
+		if m.localScopeEnabled(s.name) {
+			s.apply(scopeCond)
+		}
+	}
+
+	return scopeCond
+}
+
+func (m *CreativeGalleryReportModel) localScopeEnabled(name string) bool {
+	for _, n := range m.includeLocalScopes {
+		if name == n {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m *CreativeGalleryReportModel) globalScopeEnabled(name string) bool {
+	for _, n := range m.excludeGlobalScopes {
+		if name == n {
+			return false
+		}
+	}
+
+	return true
+}
+
+type CreativeGalleryReport struct {
+	Id        int64     `json:"id"`
+	GalleryId int64     `json:"gallery_id"`
+	UserId    int64     `json:"user_id"`
+	Reason    string    `json:"reason"`
+	Comment   string    `json:"comment,omitempty"`
+	Status    int64     `json:"status"`
+	HandledBy int64     `json:"handled_by,omitempty"`
+	HandledAt time.Time `json:"handled_at,omitempty"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (w CreativeGalleryReport) ToCreativeGalleryReportN(allows ...string) CreativeGalleryReportN {
+	if len(allows) == 0 {
+		return CreativeGalleryReportN{
+
+			Id:        null.IntFrom(int64(w.Id)),
+			GalleryId: null.IntFrom(int64(w.GalleryId)),
+			UserId:    null.IntFrom(int64(w.UserId)),
+			Reason:    null.StringFrom(w.Reason),
+			Comment:   null.StringFrom(w.Comment),
+			Status:    null.IntFrom(int64(w.Status)),
+			HandledBy: null.IntFrom(int64(w.HandledBy)),
+			HandledAt: null.TimeFrom(w.HandledAt),
+			CreatedAt: null.TimeFrom(w.CreatedAt),
+			UpdatedAt: null.TimeFrom(w.UpdatedAt),
+		}
+	}
+
+	res := CreativeGalleryReportN{}
+	for _, al := range allows {
+		switch strcase.ToSnake(al) {
+
+		case "id":
+			res.Id = null.IntFrom(int64(w.Id))
+		case "gallery_id":
+			res.GalleryId = null.IntFrom(int64(w.GalleryId))
+		case "user_id":
+			res.UserId = null.IntFrom(int64(w.UserId))
+		case "reason":
+			res.Reason = null.StringFrom(w.Reason)
+		case "comment":
+			res.Comment = null.StringFrom(w.Comment)
+		case "status":
+			res.Status = null.IntFrom(int64(w.Status))
+		case "handled_by":
+			res.HandledBy = null.IntFrom(int64(w.HandledBy))
+		case "handled_at":
+			res.HandledAt = null.TimeFrom(w.HandledAt)
+		case "created_at":
+			res.CreatedAt = null.TimeFrom(w.CreatedAt)
+		case "updated_at":
+			res.UpdatedAt = null.TimeFrom(w.UpdatedAt)
+		default:
+		}
+	}
+
+	return res
+}
+
+// As convert object to other type
+// dst must be a pointer to struct
+func (w CreativeGalleryReport) As(dst interface{}) error {
+	return query.Copy(w, dst)
+}
+
+func (w *CreativeGalleryReportN) ToCreativeGalleryReport() CreativeGalleryReport {
+	return CreativeGalleryReport{
+
+		Id:        w.Id.Int64,
+		GalleryId: w.GalleryId.Int64,
+		UserId:    w.UserId.Int64,
+		Reason:    w.Reason.String,
+		Comment:   w.Comment.String,
+		Status:    w.Status.Int64,
+		HandledBy: w.HandledBy.Int64,
+		HandledAt: w.HandledAt.Time,
+		CreatedAt: w.CreatedAt.Time,
+		UpdatedAt: w.UpdatedAt.Time,
+	}
+}
+
+// CreativeGalleryReportModel is a model which encapsulates the operations of the object
+type CreativeGalleryReportModel struct {
+	db        *query.DatabaseWrap
+	tableName string
+
+	excludeGlobalScopes []string
+	includeLocalScopes  []string
+
+	query query.SQLBuilder
+}
+
+var creativeGalleryReportTableName = "creative_gallery_report"
+
+// CreativeGalleryReportTable return table name for CreativeGalleryReport
+func CreativeGalleryReportTable() string {
+	return creativeGalleryReportTableName
+}
+
+const (
+	FieldCreativeGalleryReportId        = "id"
+	FieldCreativeGalleryReportGalleryId = "gallery_id"
+	FieldCreativeGalleryReportUserId    = "user_id"
+	FieldCreativeGalleryReportReason    = "reason"
+	FieldCreativeGalleryReportComment   = "comment"
+	FieldCreativeGalleryReportStatus    = "status"
+	FieldCreativeGalleryReportHandledBy = "handled_by"
+	FieldCreativeGalleryReportHandledAt = "handled_at"
+	FieldCreativeGalleryReportCreatedAt = "created_at"
+	FieldCreativeGalleryReportUpdatedAt = "updated_at"
+)
+
+// CreativeGalleryReportFields return all fields in CreativeGalleryReport model
+func CreativeGalleryReportFields() []string {
+	return []string{
+		"id",
+		"gallery_id",
+		"user_id",
+		"reason",
+		"comment",
+		"status",
+		"handled_by",
+		"handled_at",
+		"created_at",
+		"updated_at",
+	}
+}
+
+func SetCreativeGalleryReportTable(tableName string) {
+	creativeGalleryReportTableName = tableName
+}
+
+// NewCreativeGalleryReportModel create a CreativeGalleryReportModel
+func NewCreativeGalleryReportModel(db query.Database) *CreativeGalleryReportModel {
+	return &CreativeGalleryReportModel{
+		db:                  query.NewDatabaseWrap(db),
+		tableName:           creativeGalleryReportTableName,
+		excludeGlobalScopes: make([]string, 0),
+		includeLocalScopes:  make([]string, 0),
+		query:               query.Builder(),
+	}
+}
+
+// GetDB return database instance
+func (m *CreativeGalleryReportModel) GetDB() query.Database {
+	return m.db.GetDB()
+}
+
+func (m *CreativeGalleryReportModel) clone() *CreativeGalleryReportModel {
+	return &CreativeGalleryReportModel{
+		db:                  m.db,
+		tableName:           m.tableName,
+		excludeGlobalScopes: append([]string{}, m.excludeGlobalScopes...),
+		includeLocalScopes:  append([]string{}, m.includeLocalScopes...),
+		query:               m.query,
+	}
+}
+
+// WithoutGlobalScopes remove a global scope for given query
+func (m *CreativeGalleryReportModel) WithoutGlobalScopes(names ...string) *CreativeGalleryReportModel {
+	mc := m.clone()
+	mc.excludeGlobalScopes = append(mc.excludeGlobalScopes, names...)
+
+	return mc
+}
+
+// WithLocalScopes add a local scope for given query
+func (m *CreativeGalleryReportModel) WithLocalScopes(names ...string) *CreativeGalleryReportModel {
+	mc := m.clone()
+	mc.includeLocalScopes = append(mc.includeLocalScopes, names...)
+
+	return mc
+}
+
+// Condition add query builder to model
+func (m *CreativeGalleryReportModel) Condition(builder query.SQLBuilder) *CreativeGalleryReportModel {
+	mm := m.clone()
+	mm.query = mm.query.Merge(builder)
+
+	return mm
+}
+
+// Find retrieve a model by its primary key
+func (m *CreativeGalleryReportModel) Find(ctx context.Context, id int64) (*CreativeGalleryReportN, error) {
+	return m.First(ctx, m.query.Where("id", "=", id))
+}
+
+// Exists return whether the records exists for a given query
+func (m *CreativeGalleryReportModel) Exists(ctx context.Context, builders ...query.SQLBuilder) (bool, error) {
+	count, err := m.Count(ctx, builders...)
+	return count > 0, err
+}
+
+// Count return model count for a given query
+func (m *CreativeGalleryReportModel) Count(ctx context.Context, builders ...query.SQLBuilder) (int64, error) {
+	sqlStr, params := m.query.
+		Merge(builders...).
+		Table(m.tableName).
+		AppendCondition(m.applyScope()).
+		ResolveCount()
+
+	rows, err := m.db.QueryContext(ctx, sqlStr, params...)
+	if err != nil {
+		return 0, err
+	}
+
+	defer rows.Close()
+
+	rows.Next()
+	var res int64
+	if err := rows.Scan(&res); err != nil {
+		return 0, err
+	}
+
+	return res, nil
+}
+
+func (m *CreativeGalleryReportModel) Paginate(ctx context.Context, page int64, perPage int64, builders ...query.SQLBuilder) ([]CreativeGalleryReportN, query.PaginateMeta, error) {
+	if page <= 0 {
+		page = 1
+	}
+
+	if perPage <= 0 {
+		perPage = 15
+	}
+
+	meta := query.PaginateMeta{
+		PerPage: perPage,
+		Page:    page,
+	}
+
+	count, err := m.Count(ctx, builders...)
+	if err != nil {
+		return nil, meta, err
+	}
+
+	meta.Total = count
+	meta.LastPage = count / perPage
+	if count%perPage != 0 {
+		meta.LastPage += 1
+	}
+
+	res, err := m.Get(ctx, append([]query.SQLBuilder{query.Builder().Limit(perPage).Offset((page - 1) * perPage)}, builders...)...)
+	if err != nil {
+		return res, meta, err
+	}
+
+	return res, meta, nil
+}
+
+// Get retrieve all results for given query
+func (m *CreativeGalleryReportModel) Get(ctx context.Context, builders ...query.SQLBuilder) ([]CreativeGalleryReportN, error) {
+	b := m.query.Merge(builders...).Table(m.tableName).AppendCondition(m.applyScope())
+	if len(b.GetFields()) == 0 {
+		b = b.Select(
+			"id",
+			"gallery_id",
+			"user_id",
+			"reason",
+			"comment",
+			"status",
+			"handled_by",
+			"handled_at",
+			"created_at",
+			"updated_at",
+		)
+	}
+
+	fields := b.GetFields()
+	selectFields := make([]query.Expr, 0)
+
+	for _, f := range fields {
+		switch strcase.ToSnake(f.Value) {
+
+		case "id":
+			selectFields = append(selectFields, f)
+		case "gallery_id":
+			selectFields = append(selectFields, f)
+		case "user_id":
+			selectFields = append(selectFields, f)
+		case "reason":
+			selectFields = append(selectFields, f)
+		case "comment":
+			selectFields = append(selectFields, f)
+		case "status":
+			selectFields = append(selectFields, f)
+		case "handled_by":
+			selectFields = append(selectFields, f)
+		case "handled_at":
+			selectFields = append(selectFields, f)
+		case "created_at":
+			selectFields = append(selectFields, f)
+		case "updated_at":
+			selectFields = append(selectFields, f)
+		}
+	}
+
+	var createScanVar = func(fields []query.Expr) (*CreativeGalleryReportN, []interface{}) {
+		var creativeGalleryReportVar CreativeGalleryReportN
+		scanFields := make([]interface{}, 0)
+
+		for _, f := range fields {
+			switch strcase.ToSnake(f.Value) {
+
+			case "id":
+				scanFields = append(scanFields, &creativeGalleryReportVar.Id)
+			case "gallery_id":
+				scanFields = append(scanFields, &creativeGalleryReportVar.GalleryId)
+			case "user_id":
+				scanFields = append(scanFields, &creativeGalleryReportVar.UserId)
+			case "reason":
+				scanFields = append(scanFields, &creativeGalleryReportVar.Reason)
+			case "comment":
+				scanFields = append(scanFields, &creativeGalleryReportVar.Comment)
+			case "status":
+				scanFields = append(scanFields, &creativeGalleryReportVar.Status)
+			case "handled_by":
+				scanFields = append(scanFields, &creativeGalleryReportVar.HandledBy)
+			case "handled_at":
+				scanFields = append(scanFields, &creativeGalleryReportVar.HandledAt)
+			case "created_at":
+				scanFields = append(scanFields, &creativeGalleryReportVar.CreatedAt)
+			case "updated_at":
+				scanFields = append(scanFields, &creativeGalleryReportVar.UpdatedAt)
+			}
+		}
+
+		return &creativeGalleryReportVar, scanFields
+	}
+
+	sqlStr, params := b.Fields(selectFields...).ResolveQuery()
+
+	rows, err := m.db.QueryContext(ctx, sqlStr, params...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	creativeGalleryReports := make([]CreativeGalleryReportN, 0)
+	for rows.Next() {
+		creativeGalleryReportReal, scanFields := createScanVar(fields)
+		if err := rows.Scan(scanFields...); err != nil {
+			return nil, err
+		}
+
+		creativeGalleryReportReal.original = &creativeGalleryReportOriginal{}
+		_ = query.Copy(creativeGalleryReportReal, creativeGalleryReportReal.original)
+
+		creativeGalleryReportReal.SetModel(m)
+		creativeGalleryReports = append(creativeGalleryReports, *creativeGalleryReportReal)
+	}
+
+	return creativeGalleryReports, nil
+}
+
+// First return first result for given query
+func (m *CreativeGalleryReportModel) First(ctx context.Context, builders ...query.SQLBuilder) (*CreativeGalleryReportN, error) {
+	res, err := m.Get(ctx, append(builders, query.Builder().Limit(1))...)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res) == 0 {
+		return nil, query.ErrNoResult
+	}
+
+	return &res[0], nil
+}
+
+// Create save a new creative_gallery_report to database
+func (m *CreativeGalleryReportModel) Create(ctx context.Context, kv query.KV) (int64, error) {
+
+	if _, ok := kv["created_at"]; !ok {
+		kv["created_at"] = time.Now()
+	}
+
+	if _, ok := kv["updated_at"]; !ok {
+		kv["updated_at"] = time.Now()
+	}
+
+	sqlStr, params := m.query.Table(m.tableName).ResolveInsert(kv)
+
+	res, err := m.db.ExecContext(ctx, sqlStr, params...)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.LastInsertId()
+}
+
+// SaveAll save all creative_gallery_reports to database
+func (m *CreativeGalleryReportModel) SaveAll(ctx context.Context, creativeGalleryReports []CreativeGalleryReportN) ([]int64, error) {
+	ids := make([]int64, 0)
+	for _, creativeGalleryReport := range creativeGalleryReports {
+		id, err := m.Save(ctx, creativeGalleryReport)
+		if err != nil {
+			return ids, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// Save save a creative_gallery_report to database
+func (m *CreativeGalleryReportModel) Save(ctx context.Context, creativeGalleryReport CreativeGalleryReportN, onlyFields ...string) (int64, error) {
+	return m.Create(ctx, creativeGalleryReport.StaledKV(onlyFields...))
+}
+
+// SaveOrUpdate save a new creative_gallery_report or update it when it has a id > 0
+func (m *CreativeGalleryReportModel) SaveOrUpdate(ctx context.Context, creativeGalleryReport CreativeGalleryReportN, onlyFields ...string) (id int64, updated bool, err error) {
+	if creativeGalleryReport.Id.Int64 > 0 {
+		_, _err := m.UpdateById(ctx, creativeGalleryReport.Id.Int64, creativeGalleryReport, onlyFields...)
+		return creativeGalleryReport.Id.Int64, true, _err
+	}
+
+	_id, _err := m.Save(ctx, creativeGalleryReport, onlyFields...)
+	return _id, false, _err
+}
+
+// UpdateFields update kv for a given query
+func (m *CreativeGalleryReportModel) UpdateFields(ctx context.Context, kv query.KV, builders ...query.SQLBuilder) (int64, error) {
+	if len(kv) == 0 {
+		return 0, nil
+	}
+
+	kv["updated_at"] = time.Now()
+
+	sqlStr, params := m.query.Merge(builders...).AppendCondition(m.applyScope()).
+		Table(m.tableName).
+		ResolveUpdate(kv)
+
+	res, err := m.db.ExecContext(ctx, sqlStr, params...)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
+// Update update a model for given query
+func (m *CreativeGalleryReportModel) Update(ctx context.Context, builder query.SQLBuilder, creativeGalleryReport CreativeGalleryReportN, onlyFields ...string) (int64, error) {
+	return m.UpdateFields(ctx, creativeGalleryReport.StaledKV(onlyFields...), builder)
+}
+
+// UpdateById update a model by id
+func (m *CreativeGalleryReportModel) UpdateById(ctx context.Context, id int64, creativeGalleryReport CreativeGalleryReportN, onlyFields ...string) (int64, error) {
+	return m.Condition(query.Builder().Where("id", "=", id)).UpdateFields(ctx, creativeGalleryReport.StaledKV(onlyFields...))
+}
+
+// Delete remove a model
+func (m *CreativeGalleryReportModel) Delete(ctx context.Context, builders ...query.SQLBuilder) (int64, error) {
+
+	sqlStr, params := m.query.Merge(builders...).AppendCondition(m.applyScope()).Table(m.tableName).ResolveDelete()
+
+	res, err := m.db.ExecContext(ctx, sqlStr, params...)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+
+}
+
+// DeleteById remove a model by id
+func (m *CreativeGalleryReportModel) DeleteById(ctx context.Context, id int64) (int64, error) {
+	return m.Condition(query.Builder().Where("id", "=", id)).Delete(ctx)
+}
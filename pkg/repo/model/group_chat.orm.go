@@ -20,14 +20,15 @@ type ChatGroupMemberN struct {
 	original             *chatGroupMemberOriginal
 	chatGroupMemberModel *ChatGroupMemberModel
 
-	Id        null.Int    `json:"id"`
-	GroupId   null.Int    `json:"group_id,omitempty"`
-	UserId    null.Int    `json:"user_id,omitempty"`
-	ModelId   null.String `json:"model_id,omitempty"`
-	ModelName null.String `json:"model_name,omitempty"`
-	Status    null.Int    `json:"status,omitempty"`
-	CreatedAt null.Time
-	UpdatedAt null.Time
+	Id          null.Int    `json:"id"`
+	GroupId     null.Int    `json:"group_id,omitempty"`
+	UserId      null.Int    `json:"user_id,omitempty"`
+	ModelId     null.String `json:"model_id,omitempty"`
+	ModelName   null.String `json:"model_name,omitempty"`
+	PersonaJson null.String `json:"-"`
+	Status      null.Int    `json:"status,omitempty"`
+	CreatedAt   null.Time
+	UpdatedAt   null.Time
 }
 
 // As convert object to other type
@@ -43,14 +44,15 @@ func (inst *ChatGroupMemberN) SetModel(chatGroupMemberModel *ChatGroupMemberMode
 
 // chatGroupMemberOriginal is an object which stores original ChatGroupMember from database
 type chatGroupMemberOriginal struct {
-	Id        null.Int
-	GroupId   null.Int
-	UserId    null.Int
-	ModelId   null.String
-	ModelName null.String
-	Status    null.Int
-	CreatedAt null.Time
-	UpdatedAt null.Time
+	Id          null.Int
+	GroupId     null.Int
+	UserId      null.Int
+	ModelId     null.String
+	ModelName   null.String
+	PersonaJson null.String
+	Status      null.Int
+	CreatedAt   null.Time
+	UpdatedAt   null.Time
 }
 
 // Staled identify whether the object has been modified
@@ -76,6 +78,9 @@ func (inst *ChatGroupMemberN) Staled(onlyFields ...string) bool {
 		if inst.ModelName != inst.original.ModelName {
 			return true
 		}
+		if inst.PersonaJson != inst.original.PersonaJson {
+			return true
+		}
 		if inst.Status != inst.original.Status {
 			return true
 		}
@@ -109,6 +114,10 @@ func (inst *ChatGroupMemberN) Staled(onlyFields ...string) bool {
 				if inst.ModelName != inst.original.ModelName {
 					return true
 				}
+			case "persona_json":
+				if inst.PersonaJson != inst.original.PersonaJson {
+					return true
+				}
 			case "status":
 				if inst.Status != inst.original.Status {
 					return true
@@ -154,6 +163,9 @@ func (inst *ChatGroupMemberN) StaledKV(onlyFields ...string) query.KV {
 		if inst.ModelName != inst.original.ModelName {
 			kv["model_name"] = inst.ModelName
 		}
+		if inst.PersonaJson != inst.original.PersonaJson {
+			kv["persona_json"] = inst.PersonaJson
+		}
 		if inst.Status != inst.original.Status {
 			kv["status"] = inst.Status
 		}
@@ -187,6 +199,10 @@ func (inst *ChatGroupMemberN) StaledKV(onlyFields ...string) query.KV {
 				if inst.ModelName != inst.original.ModelName {
 					kv["model_name"] = inst.ModelName
 				}
+			case "persona_json":
+				if inst.PersonaJson != inst.original.PersonaJson {
+					kv["persona_json"] = inst.PersonaJson
+				}
 			case "status":
 				if inst.Status != inst.original.Status {
 					kv["status"] = inst.Status
@@ -298,28 +314,30 @@ func (m *ChatGroupMemberModel) globalScopeEnabled(name string) bool {
 }
 
 type ChatGroupMember struct {
-	Id        int64  `json:"id"`
-	GroupId   int64  `json:"group_id,omitempty"`
-	UserId    int64  `json:"user_id,omitempty"`
-	ModelId   string `json:"model_id,omitempty"`
-	ModelName string `json:"model_name,omitempty"`
-	Status    int64  `json:"status,omitempty"`
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	Id          int64  `json:"id"`
+	GroupId     int64  `json:"group_id,omitempty"`
+	UserId      int64  `json:"user_id,omitempty"`
+	ModelId     string `json:"model_id,omitempty"`
+	ModelName   string `json:"model_name,omitempty"`
+	PersonaJson string `json:"-"`
+	Status      int64  `json:"status,omitempty"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
 }
 
 func (w ChatGroupMember) ToChatGroupMemberN(allows ...string) ChatGroupMemberN {
 	if len(allows) == 0 {
 		return ChatGroupMemberN{
 
-			Id:        null.IntFrom(int64(w.Id)),
-			GroupId:   null.IntFrom(int64(w.GroupId)),
-			UserId:    null.IntFrom(int64(w.UserId)),
-			ModelId:   null.StringFrom(w.ModelId),
-			ModelName: null.StringFrom(w.ModelName),
-			Status:    null.IntFrom(int64(w.Status)),
-			CreatedAt: null.TimeFrom(w.CreatedAt),
-			UpdatedAt: null.TimeFrom(w.UpdatedAt),
+			Id:          null.IntFrom(int64(w.Id)),
+			GroupId:     null.IntFrom(int64(w.GroupId)),
+			UserId:      null.IntFrom(int64(w.UserId)),
+			ModelId:     null.StringFrom(w.ModelId),
+			ModelName:   null.StringFrom(w.ModelName),
+			PersonaJson: null.StringFrom(w.PersonaJson),
+			Status:      null.IntFrom(int64(w.Status)),
+			CreatedAt:   null.TimeFrom(w.CreatedAt),
+			UpdatedAt:   null.TimeFrom(w.UpdatedAt),
 		}
 	}
 
@@ -337,6 +355,8 @@ func (w ChatGroupMember) ToChatGroupMemberN(allows ...string) ChatGroupMemberN {
 			res.ModelId = null.StringFrom(w.ModelId)
 		case "model_name":
 			res.ModelName = null.StringFrom(w.ModelName)
+		case "persona_json":
+			res.PersonaJson = null.StringFrom(w.PersonaJson)
 		case "status":
 			res.Status = null.IntFrom(int64(w.Status))
 		case "created_at":
@@ -359,14 +379,15 @@ func (w ChatGroupMember) As(dst interface{}) error {
 func (w *ChatGroupMemberN) ToChatGroupMember() ChatGroupMember {
 	return ChatGroupMember{
 
-		Id:        w.Id.Int64,
-		GroupId:   w.GroupId.Int64,
-		UserId:    w.UserId.Int64,
-		ModelId:   w.ModelId.String,
-		ModelName: w.ModelName.String,
-		Status:    w.Status.Int64,
-		CreatedAt: w.CreatedAt.Time,
-		UpdatedAt: w.UpdatedAt.Time,
+		Id:          w.Id.Int64,
+		GroupId:     w.GroupId.Int64,
+		UserId:      w.UserId.Int64,
+		ModelId:     w.ModelId.String,
+		ModelName:   w.ModelName.String,
+		PersonaJson: w.PersonaJson.String,
+		Status:      w.Status.Int64,
+		CreatedAt:   w.CreatedAt.Time,
+		UpdatedAt:   w.UpdatedAt.Time,
 	}
 }
 
@@ -389,14 +410,15 @@ func ChatGroupMemberTable() string {
 }
 
 const (
-	FieldChatGroupMemberId        = "id"
-	FieldChatGroupMemberGroupId   = "group_id"
-	FieldChatGroupMemberUserId    = "user_id"
-	FieldChatGroupMemberModelId   = "model_id"
-	FieldChatGroupMemberModelName = "model_name"
-	FieldChatGroupMemberStatus    = "status"
-	FieldChatGroupMemberCreatedAt = "created_at"
-	FieldChatGroupMemberUpdatedAt = "updated_at"
+	FieldChatGroupMemberId          = "id"
+	FieldChatGroupMemberGroupId     = "group_id"
+	FieldChatGroupMemberUserId      = "user_id"
+	FieldChatGroupMemberModelId     = "model_id"
+	FieldChatGroupMemberModelName   = "model_name"
+	FieldChatGroupMemberPersonaJson = "persona_json"
+	FieldChatGroupMemberStatus      = "status"
+	FieldChatGroupMemberCreatedAt   = "created_at"
+	FieldChatGroupMemberUpdatedAt   = "updated_at"
 )
 
 // ChatGroupMemberFields return all fields in ChatGroupMember model
@@ -407,6 +429,7 @@ func ChatGroupMemberFields() []string {
 		"user_id",
 		"model_id",
 		"model_name",
+		"persona_json",
 		"status",
 		"created_at",
 		"updated_at",
@@ -545,6 +568,7 @@ func (m *ChatGroupMemberModel) Get(ctx context.Context, builders ...query.SQLBui
 			"user_id",
 			"model_id",
 			"model_name",
+			"persona_json",
 			"status",
 			"created_at",
 			"updated_at",
@@ -567,6 +591,8 @@ func (m *ChatGroupMemberModel) Get(ctx context.Context, builders ...query.SQLBui
 			selectFields = append(selectFields, f)
 		case "model_name":
 			selectFields = append(selectFields, f)
+		case "persona_json":
+			selectFields = append(selectFields, f)
 		case "status":
 			selectFields = append(selectFields, f)
 		case "created_at":
@@ -593,6 +619,8 @@ func (m *ChatGroupMemberModel) Get(ctx context.Context, builders ...query.SQLBui
 				scanFields = append(scanFields, &chatGroupMemberVar.ModelId)
 			case "model_name":
 				scanFields = append(scanFields, &chatGroupMemberVar.ModelName)
+			case "persona_json":
+				scanFields = append(scanFields, &chatGroupMemberVar.PersonaJson)
 			case "status":
 				scanFields = append(scanFields, &chatGroupMemberVar.Status)
 			case "created_at":
@@ -762,6 +790,8 @@ type ChatGroupMessageN struct {
 	MemberId      null.Int    `json:"member_id,omitempty"`
 	Status        null.Int    `json:"status,omitempty"`
 	Error         null.String `json:"error,omitempty"`
+	Channel       null.String `json:"-"`
+	Model         null.String `json:"-"`
 	CreatedAt     null.Time
 	UpdatedAt     null.Time
 }
@@ -790,6 +820,8 @@ type chatGroupMessageOriginal struct {
 	MemberId      null.Int
 	Status        null.Int
 	Error         null.String
+	Channel       null.String
+	Model         null.String
 	CreatedAt     null.Time
 	UpdatedAt     null.Time
 }
@@ -835,6 +867,12 @@ func (inst *ChatGroupMessageN) Staled(onlyFields ...string) bool {
 		if inst.Error != inst.original.Error {
 			return true
 		}
+		if inst.Channel != inst.original.Channel {
+			return true
+		}
+		if inst.Model != inst.original.Model {
+			return true
+		}
 		if inst.CreatedAt != inst.original.CreatedAt {
 			return true
 		}
@@ -889,6 +927,14 @@ func (inst *ChatGroupMessageN) Staled(onlyFields ...string) bool {
 				if inst.Error != inst.original.Error {
 					return true
 				}
+			case "channel":
+				if inst.Channel != inst.original.Channel {
+					return true
+				}
+			case "model":
+				if inst.Model != inst.original.Model {
+					return true
+				}
 			case "created_at":
 				if inst.CreatedAt != inst.original.CreatedAt {
 					return true
@@ -948,6 +994,12 @@ func (inst *ChatGroupMessageN) StaledKV(onlyFields ...string) query.KV {
 		if inst.Error != inst.original.Error {
 			kv["error"] = inst.Error
 		}
+		if inst.Channel != inst.original.Channel {
+			kv["channel"] = inst.Channel
+		}
+		if inst.Model != inst.original.Model {
+			kv["model"] = inst.Model
+		}
 		if inst.CreatedAt != inst.original.CreatedAt {
 			kv["created_at"] = inst.CreatedAt
 		}
@@ -1002,6 +1054,14 @@ func (inst *ChatGroupMessageN) StaledKV(onlyFields ...string) query.KV {
 				if inst.Error != inst.original.Error {
 					kv["error"] = inst.Error
 				}
+			case "channel":
+				if inst.Channel != inst.original.Channel {
+					kv["channel"] = inst.Channel
+				}
+			case "model":
+				if inst.Model != inst.original.Model {
+					kv["model"] = inst.Model
+				}
 			case "created_at":
 				if inst.CreatedAt != inst.original.CreatedAt {
 					kv["created_at"] = inst.CreatedAt
@@ -1120,6 +1180,8 @@ type ChatGroupMessage struct {
 	MemberId      int64  `json:"member_id,omitempty"`
 	Status        int64  `json:"status,omitempty"`
 	Error         string `json:"error,omitempty"`
+	Channel       string `json:"-"`
+	Model         string `json:"-"`
 	CreatedAt     time.Time
 	UpdatedAt     time.Time
 }
@@ -1139,6 +1201,8 @@ func (w ChatGroupMessage) ToChatGroupMessageN(allows ...string) ChatGroupMessage
 			MemberId:      null.IntFrom(int64(w.MemberId)),
 			Status:        null.IntFrom(int64(w.Status)),
 			Error:         null.StringFrom(w.Error),
+			Channel:       null.StringFrom(w.Channel),
+			Model:         null.StringFrom(w.Model),
 			CreatedAt:     null.TimeFrom(w.CreatedAt),
 			UpdatedAt:     null.TimeFrom(w.UpdatedAt),
 		}
@@ -1170,6 +1234,10 @@ func (w ChatGroupMessage) ToChatGroupMessageN(allows ...string) ChatGroupMessage
 			res.Status = null.IntFrom(int64(w.Status))
 		case "error":
 			res.Error = null.StringFrom(w.Error)
+		case "channel":
+			res.Channel = null.StringFrom(w.Channel)
+		case "model":
+			res.Model = null.StringFrom(w.Model)
 		case "created_at":
 			res.CreatedAt = null.TimeFrom(w.CreatedAt)
 		case "updated_at":
@@ -1201,6 +1269,8 @@ func (w *ChatGroupMessageN) ToChatGroupMessage() ChatGroupMessage {
 		MemberId:      w.MemberId.Int64,
 		Status:        w.Status.Int64,
 		Error:         w.Error.String,
+		Channel:       w.Channel.String,
+		Model:         w.Model.String,
 		CreatedAt:     w.CreatedAt.Time,
 		UpdatedAt:     w.UpdatedAt.Time,
 	}
@@ -1236,6 +1306,8 @@ const (
 	FieldChatGroupMessageMemberId      = "member_id"
 	FieldChatGroupMessageStatus        = "status"
 	FieldChatGroupMessageError         = "error"
+	FieldChatGroupMessageChannel       = "channel"
+	FieldChatGroupMessageModel         = "model"
 	FieldChatGroupMessageCreatedAt     = "created_at"
 	FieldChatGroupMessageUpdatedAt     = "updated_at"
 )
@@ -1254,6 +1326,8 @@ func ChatGroupMessageFields() []string {
 		"member_id",
 		"status",
 		"error",
+		"channel",
+		"model",
 		"created_at",
 		"updated_at",
 	}
@@ -1397,6 +1471,8 @@ func (m *ChatGroupMessageModel) Get(ctx context.Context, builders ...query.SQLBu
 			"member_id",
 			"status",
 			"error",
+			"channel",
+			"model",
 			"created_at",
 			"updated_at",
 		)
@@ -1430,6 +1506,10 @@ func (m *ChatGroupMessageModel) Get(ctx context.Context, builders ...query.SQLBu
 			selectFields = append(selectFields, f)
 		case "error":
 			selectFields = append(selectFields, f)
+		case "channel":
+			selectFields = append(selectFields, f)
+		case "model":
+			selectFields = append(selectFields, f)
 		case "created_at":
 			selectFields = append(selectFields, f)
 		case "updated_at":
@@ -1466,6 +1546,10 @@ func (m *ChatGroupMessageModel) Get(ctx context.Context, builders ...query.SQLBu
 				scanFields = append(scanFields, &chatGroupMessageVar.Status)
 			case "error":
 				scanFields = append(scanFields, &chatGroupMessageVar.Error)
+			case "channel":
+				scanFields = append(scanFields, &chatGroupMessageVar.Channel)
+			case "model":
+				scanFields = append(scanFields, &chatGroupMessageVar.Model)
 			case "created_at":
 				scanFields = append(scanFields, &chatGroupMessageVar.CreatedAt)
 			case "updated_at":
@@ -1616,3 +1700,1014 @@ func (m *ChatGroupMessageModel) Delete(ctx context.Context, builders ...query.SQ
 func (m *ChatGroupMessageModel) DeleteById(ctx context.Context, id int64) (int64, error) {
 	return m.Condition(query.Builder().Where("id", "=", id)).Delete(ctx)
 }
+
+// ChatGroupDiscussionN is a ChatGroupDiscussion object, all fields are nullable
+type ChatGroupDiscussionN struct {
+	original                 *chatGroupDiscussionOriginal
+	chatGroupDiscussionModel *ChatGroupDiscussionModel
+
+	Id              null.Int    `json:"id"`
+	GroupId         null.Int    `json:"group_id,omitempty"`
+	UserId          null.Int    `json:"user_id,omitempty"`
+	QuestionId      null.Int    `json:"question_id,omitempty"`
+	MemberIds       null.String `json:"member_ids,omitempty"`
+	TurnOrder       null.String `json:"turn_order,omitempty"`
+	ModeratorId     null.Int    `json:"moderator_id,omitempty"`
+	MaxRounds       null.Int    `json:"max_rounds,omitempty"`
+	StopOnConsensus null.Int    `json:"stop_on_consensus,omitempty"`
+	CoinBudget      null.Int    `json:"coin_budget,omitempty"`
+	CoinsConsumed   null.Int    `json:"coins_consumed,omitempty"`
+	Turns           null.Int    `json:"turns,omitempty"`
+	AgreeCount      null.Int    `json:"agree_count,omitempty"`
+	NextMemberId    null.Int    `json:"next_member_id,omitempty"`
+	Status          null.Int    `json:"status,omitempty"`
+	StopReason      null.String `json:"stop_reason,omitempty"`
+	CreatedAt       null.Time
+	UpdatedAt       null.Time
+}
+
+// As convert object to other type
+// dst must be a pointer to struct
+func (inst *ChatGroupDiscussionN) As(dst interface{}) error {
+	return query.Copy(inst, dst)
+}
+
+// SetModel set model for ChatGroupDiscussion
+func (inst *ChatGroupDiscussionN) SetModel(chatGroupDiscussionModel *ChatGroupDiscussionModel) {
+	inst.chatGroupDiscussionModel = chatGroupDiscussionModel
+}
+
+// chatGroupDiscussionOriginal is an object which stores original ChatGroupDiscussion from database
+type chatGroupDiscussionOriginal struct {
+	Id              null.Int
+	GroupId         null.Int
+	UserId          null.Int
+	QuestionId      null.Int
+	MemberIds       null.String
+	TurnOrder       null.String
+	ModeratorId     null.Int
+	MaxRounds       null.Int
+	StopOnConsensus null.Int
+	CoinBudget      null.Int
+	CoinsConsumed   null.Int
+	Turns           null.Int
+	AgreeCount      null.Int
+	NextMemberId    null.Int
+	Status          null.Int
+	StopReason      null.String
+	CreatedAt       null.Time
+	UpdatedAt       null.Time
+}
+
+// Staled identify whether the object has been modified
+func (inst *ChatGroupDiscussionN) Staled(onlyFields ...string) bool {
+	if inst.original == nil {
+		inst.original = &chatGroupDiscussionOriginal{}
+	}
+
+	if len(onlyFields) == 0 {
+
+		if inst.Id != inst.original.Id {
+			return true
+		}
+		if inst.GroupId != inst.original.GroupId {
+			return true
+		}
+		if inst.UserId != inst.original.UserId {
+			return true
+		}
+		if inst.QuestionId != inst.original.QuestionId {
+			return true
+		}
+		if inst.MemberIds != inst.original.MemberIds {
+			return true
+		}
+		if inst.TurnOrder != inst.original.TurnOrder {
+			return true
+		}
+		if inst.ModeratorId != inst.original.ModeratorId {
+			return true
+		}
+		if inst.MaxRounds != inst.original.MaxRounds {
+			return true
+		}
+		if inst.StopOnConsensus != inst.original.StopOnConsensus {
+			return true
+		}
+		if inst.CoinBudget != inst.original.CoinBudget {
+			return true
+		}
+		if inst.CoinsConsumed != inst.original.CoinsConsumed {
+			return true
+		}
+		if inst.Turns != inst.original.Turns {
+			return true
+		}
+		if inst.AgreeCount != inst.original.AgreeCount {
+			return true
+		}
+		if inst.NextMemberId != inst.original.NextMemberId {
+			return true
+		}
+		if inst.Status != inst.original.Status {
+			return true
+		}
+		if inst.StopReason != inst.original.StopReason {
+			return true
+		}
+		if inst.CreatedAt != inst.original.CreatedAt {
+			return true
+		}
+		if inst.UpdatedAt != inst.original.UpdatedAt {
+			return true
+		}
+	} else {
+		for _, f := range onlyFields {
+			switch strcase.ToSnake(f) {
+
+			case "id":
+				if inst.Id != inst.original.Id {
+					return true
+				}
+			case "group_id":
+				if inst.GroupId != inst.original.GroupId {
+					return true
+				}
+			case "user_id":
+				if inst.UserId != inst.original.UserId {
+					return true
+				}
+			case "question_id":
+				if inst.QuestionId != inst.original.QuestionId {
+					return true
+				}
+			case "member_ids":
+				if inst.MemberIds != inst.original.MemberIds {
+					return true
+				}
+			case "turn_order":
+				if inst.TurnOrder != inst.original.TurnOrder {
+					return true
+				}
+			case "moderator_id":
+				if inst.ModeratorId != inst.original.ModeratorId {
+					return true
+				}
+			case "max_rounds":
+				if inst.MaxRounds != inst.original.MaxRounds {
+					return true
+				}
+			case "stop_on_consensus":
+				if inst.StopOnConsensus != inst.original.StopOnConsensus {
+					return true
+				}
+			case "coin_budget":
+				if inst.CoinBudget != inst.original.CoinBudget {
+					return true
+				}
+			case "coins_consumed":
+				if inst.CoinsConsumed != inst.original.CoinsConsumed {
+					return true
+				}
+			case "turns":
+				if inst.Turns != inst.original.Turns {
+					return true
+				}
+			case "agree_count":
+				if inst.AgreeCount != inst.original.AgreeCount {
+					return true
+				}
+			case "next_member_id":
+				if inst.NextMemberId != inst.original.NextMemberId {
+					return true
+				}
+			case "status":
+				if inst.Status != inst.original.Status {
+					return true
+				}
+			case "stop_reason":
+				if inst.StopReason != inst.original.StopReason {
+					return true
+				}
+			case "created_at":
+				if inst.CreatedAt != inst.original.CreatedAt {
+					return true
+				}
+			case "updated_at":
+				if inst.UpdatedAt != inst.original.UpdatedAt {
+					return true
+				}
+			default:
+			}
+		}
+	}
+
+	return false
+}
+
+// StaledKV return all fields has been modified
+func (inst *ChatGroupDiscussionN) StaledKV(onlyFields ...string) query.KV {
+	kv := make(query.KV, 0)
+
+	if inst.original == nil {
+		inst.original = &chatGroupDiscussionOriginal{}
+	}
+
+	if len(onlyFields) == 0 {
+
+		if inst.Id != inst.original.Id {
+			kv["id"] = inst.Id
+		}
+		if inst.GroupId != inst.original.GroupId {
+			kv["group_id"] = inst.GroupId
+		}
+		if inst.UserId != inst.original.UserId {
+			kv["user_id"] = inst.UserId
+		}
+		if inst.QuestionId != inst.original.QuestionId {
+			kv["question_id"] = inst.QuestionId
+		}
+		if inst.MemberIds != inst.original.MemberIds {
+			kv["member_ids"] = inst.MemberIds
+		}
+		if inst.TurnOrder != inst.original.TurnOrder {
+			kv["turn_order"] = inst.TurnOrder
+		}
+		if inst.ModeratorId != inst.original.ModeratorId {
+			kv["moderator_id"] = inst.ModeratorId
+		}
+		if inst.MaxRounds != inst.original.MaxRounds {
+			kv["max_rounds"] = inst.MaxRounds
+		}
+		if inst.StopOnConsensus != inst.original.StopOnConsensus {
+			kv["stop_on_consensus"] = inst.StopOnConsensus
+		}
+		if inst.CoinBudget != inst.original.CoinBudget {
+			kv["coin_budget"] = inst.CoinBudget
+		}
+		if inst.CoinsConsumed != inst.original.CoinsConsumed {
+			kv["coins_consumed"] = inst.CoinsConsumed
+		}
+		if inst.Turns != inst.original.Turns {
+			kv["turns"] = inst.Turns
+		}
+		if inst.AgreeCount != inst.original.AgreeCount {
+			kv["agree_count"] = inst.AgreeCount
+		}
+		if inst.NextMemberId != inst.original.NextMemberId {
+			kv["next_member_id"] = inst.NextMemberId
+		}
+		if inst.Status != inst.original.Status {
+			kv["status"] = inst.Status
+		}
+		if inst.StopReason != inst.original.StopReason {
+			kv["stop_reason"] = inst.StopReason
+		}
+		if inst.CreatedAt != inst.original.CreatedAt {
+			kv["created_at"] = inst.CreatedAt
+		}
+		if inst.UpdatedAt != inst.original.UpdatedAt {
+			kv["updated_at"] = inst.UpdatedAt
+		}
+	} else {
+		for _, f := range onlyFields {
+			switch strcase.ToSnake(f) {
+
+			case "id":
+				if inst.Id != inst.original.Id {
+					kv["id"] = inst.Id
+				}
+			case "group_id":
+				if inst.GroupId != inst.original.GroupId {
+					kv["group_id"] = inst.GroupId
+				}
+			case "user_id":
+				if inst.UserId != inst.original.UserId {
+					kv["user_id"] = inst.UserId
+				}
+			case "question_id":
+				if inst.QuestionId != inst.original.QuestionId {
+					kv["question_id"] = inst.QuestionId
+				}
+			case "member_ids":
+				if inst.MemberIds != inst.original.MemberIds {
+					kv["member_ids"] = inst.MemberIds
+				}
+			case "turn_order":
+				if inst.TurnOrder != inst.original.TurnOrder {
+					kv["turn_order"] = inst.TurnOrder
+				}
+			case "moderator_id":
+				if inst.ModeratorId != inst.original.ModeratorId {
+					kv["moderator_id"] = inst.ModeratorId
+				}
+			case "max_rounds":
+				if inst.MaxRounds != inst.original.MaxRounds {
+					kv["max_rounds"] = inst.MaxRounds
+				}
+			case "stop_on_consensus":
+				if inst.StopOnConsensus != inst.original.StopOnConsensus {
+					kv["stop_on_consensus"] = inst.StopOnConsensus
+				}
+			case "coin_budget":
+				if inst.CoinBudget != inst.original.CoinBudget {
+					kv["coin_budget"] = inst.CoinBudget
+				}
+			case "coins_consumed":
+				if inst.CoinsConsumed != inst.original.CoinsConsumed {
+					kv["coins_consumed"] = inst.CoinsConsumed
+				}
+			case "turns":
+				if inst.Turns != inst.original.Turns {
+					kv["turns"] = inst.Turns
+				}
+			case "agree_count":
+				if inst.AgreeCount != inst.original.AgreeCount {
+					kv["agree_count"] = inst.AgreeCount
+				}
+			case "next_member_id":
+				if inst.NextMemberId != inst.original.NextMemberId {
+					kv["next_member_id"] = inst.NextMemberId
+				}
+			case "status":
+				if inst.Status != inst.original.Status {
+					kv["status"] = inst.Status
+				}
+			case "stop_reason":
+				if inst.StopReason != inst.original.StopReason {
+					kv["stop_reason"] = inst.StopReason
+				}
+			case "created_at":
+				if inst.CreatedAt != inst.original.CreatedAt {
+					kv["created_at"] = inst.CreatedAt
+				}
+			case "updated_at":
+				if inst.UpdatedAt != inst.original.UpdatedAt {
+					kv["updated_at"] = inst.UpdatedAt
+				}
+			default:
+			}
+		}
+	}
+
+	return kv
+}
+
+// Save create a new model or update it
+func (inst *ChatGroupDiscussionN) Save(ctx context.Context, onlyFields ...string) error {
+	if inst.chatGroupDiscussionModel == nil {
+		return query.ErrModelNotSet
+	}
+
+	id, _, err := inst.chatGroupDiscussionModel.SaveOrUpdate(ctx, *inst, onlyFields...)
+	if err != nil {
+		return err
+	}
+
+	inst.Id = null.IntFrom(id)
+	return nil
+}
+
+// Delete remove a chat_group_discussion
+func (inst *ChatGroupDiscussionN) Delete(ctx context.Context) error {
+	if inst.chatGroupDiscussionModel == nil {
+		return query.ErrModelNotSet
+	}
+
+	_, err := inst.chatGroupDiscussionModel.DeleteById(ctx, inst.Id.Int64)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// String convert instance to json string
+func (inst *ChatGroupDiscussionN) String() string {
+	rs, _ := json.Marshal(inst)
+	return string(rs)
+}
+
+type chatGroupDiscussionScope struct {
+	name  string
+	apply func(builder query.Condition)
+}
+
+var chatGroupDiscussionGlobalScopes = make([]chatGroupDiscussionScope, 0)
+var chatGroupDiscussionLocalScopes = make([]chatGroupDiscussionScope, 0)
+
+// AddGlobalScopeForChatGroupDiscussion assign a global scope to a model
+func AddGlobalScopeForChatGroupDiscussion(name string, apply func(builder query.Condition)) {
+	chatGroupDiscussionGlobalScopes = append(chatGroupDiscussionGlobalScopes, chatGroupDiscussionScope{name: name, apply: apply})
+}
+
+// AddLocalScopeForChatGroupDiscussion assign a local scope to a model
+func AddLocalScopeForChatGroupDiscussion(name string, apply func(builder query.Condition)) {
+	chatGroupDiscussionLocalScopes = append(chatGroupDiscussionLocalScopes, chatGroupDiscussionScope{name: name, apply: apply})
+}
+
+func (m *ChatGroupDiscussionModel) applyScope() query.Condition {
+	scopeCond := query.ConditionBuilder()
+	for _, g := range chatGroupDiscussionGlobalScopes {
+		if m.globalScopeEnabled(g.name) {
+			g.apply(scopeCond)
+		}
+	}
+
+	for _, s := range chatGroupDiscussionLocalScopes {
+		if m.localScopeEnabled(s.name) {
+			s.apply(scopeCond)
+		}
+	}
+
+	return scopeCond
+}
+
+func (m *ChatGroupDiscussionModel) localScopeEnabled(name string) bool {
+	for _, n := range m.includeLocalScopes {
+		if name == n {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m *ChatGroupDiscussionModel) globalScopeEnabled(name string) bool {
+	for _, n := range m.excludeGlobalScopes {
+		if name == n {
+			return false
+		}
+	}
+
+	return true
+}
+
+type ChatGroupDiscussion struct {
+	Id              int64  `json:"id"`
+	GroupId         int64  `json:"group_id,omitempty"`
+	UserId          int64  `json:"user_id,omitempty"`
+	QuestionId      int64  `json:"question_id,omitempty"`
+	MemberIds       string `json:"member_ids,omitempty"`
+	TurnOrder       string `json:"turn_order,omitempty"`
+	ModeratorId     int64  `json:"moderator_id,omitempty"`
+	MaxRounds       int64  `json:"max_rounds,omitempty"`
+	StopOnConsensus int64  `json:"stop_on_consensus,omitempty"`
+	CoinBudget      int64  `json:"coin_budget,omitempty"`
+	CoinsConsumed   int64  `json:"coins_consumed,omitempty"`
+	Turns           int64  `json:"turns,omitempty"`
+	AgreeCount      int64  `json:"agree_count,omitempty"`
+	NextMemberId    int64  `json:"next_member_id,omitempty"`
+	Status          int64  `json:"status,omitempty"`
+	StopReason      string `json:"stop_reason,omitempty"`
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+func (w ChatGroupDiscussion) ToChatGroupDiscussionN(allows ...string) ChatGroupDiscussionN {
+	if len(allows) == 0 {
+		return ChatGroupDiscussionN{
+
+			Id:              null.IntFrom(int64(w.Id)),
+			GroupId:         null.IntFrom(int64(w.GroupId)),
+			UserId:          null.IntFrom(int64(w.UserId)),
+			QuestionId:      null.IntFrom(int64(w.QuestionId)),
+			MemberIds:       null.StringFrom(w.MemberIds),
+			TurnOrder:       null.StringFrom(w.TurnOrder),
+			ModeratorId:     null.IntFrom(int64(w.ModeratorId)),
+			MaxRounds:       null.IntFrom(int64(w.MaxRounds)),
+			StopOnConsensus: null.IntFrom(int64(w.StopOnConsensus)),
+			CoinBudget:      null.IntFrom(int64(w.CoinBudget)),
+			CoinsConsumed:   null.IntFrom(int64(w.CoinsConsumed)),
+			Turns:           null.IntFrom(int64(w.Turns)),
+			AgreeCount:      null.IntFrom(int64(w.AgreeCount)),
+			NextMemberId:    null.IntFrom(int64(w.NextMemberId)),
+			Status:          null.IntFrom(int64(w.Status)),
+			StopReason:      null.StringFrom(w.StopReason),
+			CreatedAt:       null.TimeFrom(w.CreatedAt),
+			UpdatedAt:       null.TimeFrom(w.UpdatedAt),
+		}
+	}
+
+	res := ChatGroupDiscussionN{}
+	for _, al := range allows {
+		switch strcase.ToSnake(al) {
+
+		case "id":
+			res.Id = null.IntFrom(int64(w.Id))
+		case "group_id":
+			res.GroupId = null.IntFrom(int64(w.GroupId))
+		case "user_id":
+			res.UserId = null.IntFrom(int64(w.UserId))
+		case "question_id":
+			res.QuestionId = null.IntFrom(int64(w.QuestionId))
+		case "member_ids":
+			res.MemberIds = null.StringFrom(w.MemberIds)
+		case "turn_order":
+			res.TurnOrder = null.StringFrom(w.TurnOrder)
+		case "moderator_id":
+			res.ModeratorId = null.IntFrom(int64(w.ModeratorId))
+		case "max_rounds":
+			res.MaxRounds = null.IntFrom(int64(w.MaxRounds))
+		case "stop_on_consensus":
+			res.StopOnConsensus = null.IntFrom(int64(w.StopOnConsensus))
+		case "coin_budget":
+			res.CoinBudget = null.IntFrom(int64(w.CoinBudget))
+		case "coins_consumed":
+			res.CoinsConsumed = null.IntFrom(int64(w.CoinsConsumed))
+		case "turns":
+			res.Turns = null.IntFrom(int64(w.Turns))
+		case "agree_count":
+			res.AgreeCount = null.IntFrom(int64(w.AgreeCount))
+		case "next_member_id":
+			res.NextMemberId = null.IntFrom(int64(w.NextMemberId))
+		case "status":
+			res.Status = null.IntFrom(int64(w.Status))
+		case "stop_reason":
+			res.StopReason = null.StringFrom(w.StopReason)
+		case "created_at":
+			res.CreatedAt = null.TimeFrom(w.CreatedAt)
+		case "updated_at":
+			res.UpdatedAt = null.TimeFrom(w.UpdatedAt)
+		default:
+		}
+	}
+
+	return res
+}
+
+// As convert object to other type
+// dst must be a pointer to struct
+func (w ChatGroupDiscussion) As(dst interface{}) error {
+	return query.Copy(w, dst)
+}
+
+func (w *ChatGroupDiscussionN) ToChatGroupDiscussion() ChatGroupDiscussion {
+	return ChatGroupDiscussion{
+
+		Id:              w.Id.Int64,
+		GroupId:         w.GroupId.Int64,
+		UserId:          w.UserId.Int64,
+		QuestionId:      w.QuestionId.Int64,
+		MemberIds:       w.MemberIds.String,
+		TurnOrder:       w.TurnOrder.String,
+		ModeratorId:     w.ModeratorId.Int64,
+		MaxRounds:       w.MaxRounds.Int64,
+		StopOnConsensus: w.StopOnConsensus.Int64,
+		CoinBudget:      w.CoinBudget.Int64,
+		CoinsConsumed:   w.CoinsConsumed.Int64,
+		Turns:           w.Turns.Int64,
+		AgreeCount:      w.AgreeCount.Int64,
+		NextMemberId:    w.NextMemberId.Int64,
+		Status:          w.Status.Int64,
+		StopReason:      w.StopReason.String,
+		CreatedAt:       w.CreatedAt.Time,
+		UpdatedAt:       w.UpdatedAt.Time,
+	}
+}
+
+// ChatGroupDiscussionModel is a model which encapsulates the operations of the object
+type ChatGroupDiscussionModel struct {
+	db        *query.DatabaseWrap
+	tableName string
+
+	excludeGlobalScopes []string
+	includeLocalScopes  []string
+
+	query query.SQLBuilder
+}
+
+var chatGroupDiscussionTableName = "chat_group_discussion"
+
+// ChatGroupDiscussionTable return table name for ChatGroupDiscussion
+func ChatGroupDiscussionTable() string {
+	return chatGroupDiscussionTableName
+}
+
+const (
+	FieldChatGroupDiscussionId              = "id"
+	FieldChatGroupDiscussionGroupId         = "group_id"
+	FieldChatGroupDiscussionUserId          = "user_id"
+	FieldChatGroupDiscussionQuestionId      = "question_id"
+	FieldChatGroupDiscussionMemberIds       = "member_ids"
+	FieldChatGroupDiscussionTurnOrder       = "turn_order"
+	FieldChatGroupDiscussionModeratorId     = "moderator_id"
+	FieldChatGroupDiscussionMaxRounds       = "max_rounds"
+	FieldChatGroupDiscussionStopOnConsensus = "stop_on_consensus"
+	FieldChatGroupDiscussionCoinBudget      = "coin_budget"
+	FieldChatGroupDiscussionCoinsConsumed   = "coins_consumed"
+	FieldChatGroupDiscussionTurns           = "turns"
+	FieldChatGroupDiscussionAgreeCount      = "agree_count"
+	FieldChatGroupDiscussionNextMemberId    = "next_member_id"
+	FieldChatGroupDiscussionStatus          = "status"
+	FieldChatGroupDiscussionStopReason      = "stop_reason"
+	FieldChatGroupDiscussionCreatedAt       = "created_at"
+	FieldChatGroupDiscussionUpdatedAt       = "updated_at"
+)
+
+// ChatGroupDiscussionFields return all fields in ChatGroupDiscussion model
+func ChatGroupDiscussionFields() []string {
+	return []string{
+		"id",
+		"group_id",
+		"user_id",
+		"question_id",
+		"member_ids",
+		"turn_order",
+		"moderator_id",
+		"max_rounds",
+		"stop_on_consensus",
+		"coin_budget",
+		"coins_consumed",
+		"turns",
+		"agree_count",
+		"next_member_id",
+		"status",
+		"stop_reason",
+		"created_at",
+		"updated_at",
+	}
+}
+
+func SetChatGroupDiscussionTable(tableName string) {
+	chatGroupDiscussionTableName = tableName
+}
+
+// NewChatGroupDiscussionModel create a ChatGroupDiscussionModel
+func NewChatGroupDiscussionModel(db query.Database) *ChatGroupDiscussionModel {
+	return &ChatGroupDiscussionModel{
+		db:                  query.NewDatabaseWrap(db),
+		tableName:           chatGroupDiscussionTableName,
+		excludeGlobalScopes: make([]string, 0),
+		includeLocalScopes:  make([]string, 0),
+		query:               query.Builder(),
+	}
+}
+
+// GetDB return database instance
+func (m *ChatGroupDiscussionModel) GetDB() query.Database {
+	return m.db.GetDB()
+}
+
+func (m *ChatGroupDiscussionModel) clone() *ChatGroupDiscussionModel {
+	return &ChatGroupDiscussionModel{
+		db:                  m.db,
+		tableName:           m.tableName,
+		excludeGlobalScopes: append([]string{}, m.excludeGlobalScopes...),
+		includeLocalScopes:  append([]string{}, m.includeLocalScopes...),
+		query:               m.query,
+	}
+}
+
+// WithoutGlobalScopes remove a global scope for given query
+func (m *ChatGroupDiscussionModel) WithoutGlobalScopes(names ...string) *ChatGroupDiscussionModel {
+	mc := m.clone()
+	mc.excludeGlobalScopes = append(mc.excludeGlobalScopes, names...)
+
+	return mc
+}
+
+// WithLocalScopes add a local scope for given query
+func (m *ChatGroupDiscussionModel) WithLocalScopes(names ...string) *ChatGroupDiscussionModel {
+	mc := m.clone()
+	mc.includeLocalScopes = append(mc.includeLocalScopes, names...)
+
+	return mc
+}
+
+// Condition add query builder to model
+func (m *ChatGroupDiscussionModel) Condition(builder query.SQLBuilder) *ChatGroupDiscussionModel {
+	mm := m.clone()
+	mm.query = mm.query.Merge(builder)
+
+	return mm
+}
+
+// Find retrieve a model by its primary key
+func (m *ChatGroupDiscussionModel) Find(ctx context.Context, id int64) (*ChatGroupDiscussionN, error) {
+	return m.First(ctx, m.query.Where("id", "=", id))
+}
+
+// Exists return whether the records exists for a given query
+func (m *ChatGroupDiscussionModel) Exists(ctx context.Context, builders ...query.SQLBuilder) (bool, error) {
+	count, err := m.Count(ctx, builders...)
+	return count > 0, err
+}
+
+// Count return model count for a given query
+func (m *ChatGroupDiscussionModel) Count(ctx context.Context, builders ...query.SQLBuilder) (int64, error) {
+	sqlStr, params := m.query.
+		Merge(builders...).
+		Table(m.tableName).
+		AppendCondition(m.applyScope()).
+		ResolveCount()
+
+	rows, err := m.db.QueryContext(ctx, sqlStr, params...)
+	if err != nil {
+		return 0, err
+	}
+
+	defer rows.Close()
+
+	rows.Next()
+	var res int64
+	if err := rows.Scan(&res); err != nil {
+		return 0, err
+	}
+
+	return res, nil
+}
+
+func (m *ChatGroupDiscussionModel) Paginate(ctx context.Context, page int64, perPage int64, builders ...query.SQLBuilder) ([]ChatGroupDiscussionN, query.PaginateMeta, error) {
+	if page <= 0 {
+		page = 1
+	}
+
+	if perPage <= 0 {
+		perPage = 15
+	}
+
+	meta := query.PaginateMeta{
+		PerPage: perPage,
+		Page:    page,
+	}
+
+	count, err := m.Count(ctx, builders...)
+	if err != nil {
+		return nil, meta, err
+	}
+
+	meta.Total = count
+	meta.LastPage = count / perPage
+	if count%perPage != 0 {
+		meta.LastPage += 1
+	}
+
+	res, err := m.Get(ctx, append([]query.SQLBuilder{query.Builder().Limit(perPage).Offset((page - 1) * perPage)}, builders...)...)
+	if err != nil {
+		return res, meta, err
+	}
+
+	return res, meta, nil
+}
+
+// Get retrieve all results for given query
+func (m *ChatGroupDiscussionModel) Get(ctx context.Context, builders ...query.SQLBuilder) ([]ChatGroupDiscussionN, error) {
+	b := m.query.Merge(builders...).Table(m.tableName).AppendCondition(m.applyScope())
+	if len(b.GetFields()) == 0 {
+		b = b.Select(
+			"id",
+			"group_id",
+			"user_id",
+			"question_id",
+			"member_ids",
+			"turn_order",
+			"moderator_id",
+			"max_rounds",
+			"stop_on_consensus",
+			"coin_budget",
+			"coins_consumed",
+			"turns",
+			"agree_count",
+			"next_member_id",
+			"status",
+			"stop_reason",
+			"created_at",
+			"updated_at",
+		)
+	}
+
+	fields := b.GetFields()
+	selectFields := make([]query.Expr, 0)
+
+	for _, f := range fields {
+		switch strcase.ToSnake(f.Value) {
+
+		case "id":
+			selectFields = append(selectFields, f)
+		case "group_id":
+			selectFields = append(selectFields, f)
+		case "user_id":
+			selectFields = append(selectFields, f)
+		case "question_id":
+			selectFields = append(selectFields, f)
+		case "member_ids":
+			selectFields = append(selectFields, f)
+		case "turn_order":
+			selectFields = append(selectFields, f)
+		case "moderator_id":
+			selectFields = append(selectFields, f)
+		case "max_rounds":
+			selectFields = append(selectFields, f)
+		case "stop_on_consensus":
+			selectFields = append(selectFields, f)
+		case "coin_budget":
+			selectFields = append(selectFields, f)
+		case "coins_consumed":
+			selectFields = append(selectFields, f)
+		case "turns":
+			selectFields = append(selectFields, f)
+		case "agree_count":
+			selectFields = append(selectFields, f)
+		case "next_member_id":
+			selectFields = append(selectFields, f)
+		case "status":
+			selectFields = append(selectFields, f)
+		case "stop_reason":
+			selectFields = append(selectFields, f)
+		case "created_at":
+			selectFields = append(selectFields, f)
+		case "updated_at":
+			selectFields = append(selectFields, f)
+		}
+	}
+
+	var createScanVar = func(fields []query.Expr) (*ChatGroupDiscussionN, []interface{}) {
+		var chatGroupDiscussionVar ChatGroupDiscussionN
+		scanFields := make([]interface{}, 0)
+
+		for _, f := range fields {
+			switch strcase.ToSnake(f.Value) {
+
+			case "id":
+				scanFields = append(scanFields, &chatGroupDiscussionVar.Id)
+			case "group_id":
+				scanFields = append(scanFields, &chatGroupDiscussionVar.GroupId)
+			case "user_id":
+				scanFields = append(scanFields, &chatGroupDiscussionVar.UserId)
+			case "question_id":
+				scanFields = append(scanFields, &chatGroupDiscussionVar.QuestionId)
+			case "member_ids":
+				scanFields = append(scanFields, &chatGroupDiscussionVar.MemberIds)
+			case "turn_order":
+				scanFields = append(scanFields, &chatGroupDiscussionVar.TurnOrder)
+			case "moderator_id":
+				scanFields = append(scanFields, &chatGroupDiscussionVar.ModeratorId)
+			case "max_rounds":
+				scanFields = append(scanFields, &chatGroupDiscussionVar.MaxRounds)
+			case "stop_on_consensus":
+				scanFields = append(scanFields, &chatGroupDiscussionVar.StopOnConsensus)
+			case "coin_budget":
+				scanFields = append(scanFields, &chatGroupDiscussionVar.CoinBudget)
+			case "coins_consumed":
+				scanFields = append(scanFields, &chatGroupDiscussionVar.CoinsConsumed)
+			case "turns":
+				scanFields = append(scanFields, &chatGroupDiscussionVar.Turns)
+			case "agree_count":
+				scanFields = append(scanFields, &chatGroupDiscussionVar.AgreeCount)
+			case "next_member_id":
+				scanFields = append(scanFields, &chatGroupDiscussionVar.NextMemberId)
+			case "status":
+				scanFields = append(scanFields, &chatGroupDiscussionVar.Status)
+			case "stop_reason":
+				scanFields = append(scanFields, &chatGroupDiscussionVar.StopReason)
+			case "created_at":
+				scanFields = append(scanFields, &chatGroupDiscussionVar.CreatedAt)
+			case "updated_at":
+				scanFields = append(scanFields, &chatGroupDiscussionVar.UpdatedAt)
+			}
+		}
+
+		return &chatGroupDiscussionVar, scanFields
+	}
+
+	sqlStr, params := b.Fields(selectFields...).ResolveQuery()
+
+	rows, err := m.db.QueryContext(ctx, sqlStr, params...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	chatGroupDiscussions := make([]ChatGroupDiscussionN, 0)
+	for rows.Next() {
+		chatGroupDiscussionReal, scanFields := createScanVar(fields)
+		if err := rows.Scan(scanFields...); err != nil {
+			return nil, err
+		}
+
+		chatGroupDiscussionReal.original = &chatGroupDiscussionOriginal{}
+		_ = query.Copy(chatGroupDiscussionReal, chatGroupDiscussionReal.original)
+
+		chatGroupDiscussionReal.SetModel(m)
+		chatGroupDiscussions = append(chatGroupDiscussions, *chatGroupDiscussionReal)
+	}
+
+	return chatGroupDiscussions, nil
+}
+
+// First return first result for given query
+func (m *ChatGroupDiscussionModel) First(ctx context.Context, builders ...query.SQLBuilder) (*ChatGroupDiscussionN, error) {
+	res, err := m.Get(ctx, append(builders, query.Builder().Limit(1))...)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res) == 0 {
+		return nil, query.ErrNoResult
+	}
+
+	return &res[0], nil
+}
+
+// Create save a new chat_group_discussion to database
+func (m *ChatGroupDiscussionModel) Create(ctx context.Context, kv query.KV) (int64, error) {
+
+	if _, ok := kv["created_at"]; !ok {
+		kv["created_at"] = time.Now()
+	}
+
+	if _, ok := kv["updated_at"]; !ok {
+		kv["updated_at"] = time.Now()
+	}
+
+	sqlStr, params := m.query.Table(m.tableName).ResolveInsert(kv)
+
+	res, err := m.db.ExecContext(ctx, sqlStr, params...)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.LastInsertId()
+}
+
+// SaveAll save all chat_group_discussions to database
+func (m *ChatGroupDiscussionModel) SaveAll(ctx context.Context, chatGroupDiscussions []ChatGroupDiscussionN) ([]int64, error) {
+	ids := make([]int64, 0)
+	for _, chatGroupDiscussion := range chatGroupDiscussions {
+		id, err := m.Save(ctx, chatGroupDiscussion)
+		if err != nil {
+			return ids, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// Save save a chat_group_discussion to database
+func (m *ChatGroupDiscussionModel) Save(ctx context.Context, chatGroupDiscussion ChatGroupDiscussionN, onlyFields ...string) (int64, error) {
+	return m.Create(ctx, chatGroupDiscussion.StaledKV(onlyFields...))
+}
+
+// SaveOrUpdate save a new chat_group_discussion or update it when it has a id > 0
+func (m *ChatGroupDiscussionModel) SaveOrUpdate(ctx context.Context, chatGroupDiscussion ChatGroupDiscussionN, onlyFields ...string) (id int64, updated bool, err error) {
+	if chatGroupDiscussion.Id.Int64 > 0 {
+		_, _err := m.UpdateById(ctx, chatGroupDiscussion.Id.Int64, chatGroupDiscussion, onlyFields...)
+		return chatGroupDiscussion.Id.Int64, true, _err
+	}
+
+	_id, _err := m.Save(ctx, chatGroupDiscussion, onlyFields...)
+	return _id, false, _err
+}
+
+// UpdateFields update kv for a given query
+func (m *ChatGroupDiscussionModel) UpdateFields(ctx context.Context, kv query.KV, builders ...query.SQLBuilder) (int64, error) {
+	if len(kv) == 0 {
+		return 0, nil
+	}
+
+	kv["updated_at"] = time.Now()
+
+	sqlStr, params := m.query.Merge(builders...).AppendCondition(m.applyScope()).
+		Table(m.tableName).
+		ResolveUpdate(kv)
+
+	res, err := m.db.ExecContext(ctx, sqlStr, params...)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
+// Update update a model for given query
+func (m *ChatGroupDiscussionModel) Update(ctx context.Context, builder query.SQLBuilder, chatGroupDiscussion ChatGroupDiscussionN, onlyFields ...string) (int64, error) {
+	return m.UpdateFields(ctx, chatGroupDiscussion.StaledKV(onlyFields...), builder)
+}
+
+// UpdateById update a model by id
+func (m *ChatGroupDiscussionModel) UpdateById(ctx context.Context, id int64, chatGroupDiscussion ChatGroupDiscussionN, onlyFields ...string) (int64, error) {
+	return m.Condition(query.Builder().Where("id", "=", id)).UpdateFields(ctx, chatGroupDiscussion.StaledKV(onlyFields...))
+}
+
+// Delete remove a model
+func (m *ChatGroupDiscussionModel) Delete(ctx context.Context, builders ...query.SQLBuilder) (int64, error) {
+
+	sqlStr, params := m.query.Merge(builders...).AppendCondition(m.applyScope()).Table(m.tableName).ResolveDelete()
+
+	res, err := m.db.ExecContext(ctx, sqlStr, params...)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+
+}
+
+// DeleteById remove a model by id
+func (m *ChatGroupDiscussionModel) DeleteById(ctx context.Context, id int64) (int64, error) {
+	return m.Condition(query.Builder().Where("id", "=", id)).Delete(ctx)
+}
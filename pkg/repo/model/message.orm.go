@@ -32,6 +32,9 @@ type ChatMessagesN struct {
 	Status        null.Int    `json:"status,omitempty"`
 	Error         null.String `json:"error,omitempty"`
 	Meta          null.String `json:"meta,omitempty"`
+	Pinned        null.Int    `json:"pinned,omitempty"`
+	Channel       null.String `json:"-"`
+	SyncAt        null.Time   `json:"-"`
 	CreatedAt     null.Time
 	UpdatedAt     null.Time
 }
@@ -61,6 +64,9 @@ type chatMessagesOriginal struct {
 	Status        null.Int
 	Error         null.String
 	Meta          null.String
+	Pinned        null.Int
+	Channel       null.String
+	SyncAt        null.Time
 	CreatedAt     null.Time
 	UpdatedAt     null.Time
 }
@@ -109,6 +115,15 @@ func (inst *ChatMessagesN) Staled(onlyFields ...string) bool {
 		if inst.Meta != inst.original.Meta {
 			return true
 		}
+		if inst.Pinned != inst.original.Pinned {
+			return true
+		}
+		if inst.Channel != inst.original.Channel {
+			return true
+		}
+		if inst.SyncAt != inst.original.SyncAt {
+			return true
+		}
 		if inst.CreatedAt != inst.original.CreatedAt {
 			return true
 		}
@@ -167,6 +182,18 @@ func (inst *ChatMessagesN) Staled(onlyFields ...string) bool {
 				if inst.Meta != inst.original.Meta {
 					return true
 				}
+			case "pinned":
+				if inst.Pinned != inst.original.Pinned {
+					return true
+				}
+			case "channel":
+				if inst.Channel != inst.original.Channel {
+					return true
+				}
+			case "sync_at":
+				if inst.SyncAt != inst.original.SyncAt {
+					return true
+				}
 			case "created_at":
 				if inst.CreatedAt != inst.original.CreatedAt {
 					return true
@@ -229,6 +256,15 @@ func (inst *ChatMessagesN) StaledKV(onlyFields ...string) query.KV {
 		if inst.Meta != inst.original.Meta {
 			kv["meta"] = inst.Meta
 		}
+		if inst.Pinned != inst.original.Pinned {
+			kv["pinned"] = inst.Pinned
+		}
+		if inst.Channel != inst.original.Channel {
+			kv["channel"] = inst.Channel
+		}
+		if inst.SyncAt != inst.original.SyncAt {
+			kv["sync_at"] = inst.SyncAt
+		}
 		if inst.CreatedAt != inst.original.CreatedAt {
 			kv["created_at"] = inst.CreatedAt
 		}
@@ -287,6 +323,18 @@ func (inst *ChatMessagesN) StaledKV(onlyFields ...string) query.KV {
 				if inst.Meta != inst.original.Meta {
 					kv["meta"] = inst.Meta
 				}
+			case "pinned":
+				if inst.Pinned != inst.original.Pinned {
+					kv["pinned"] = inst.Pinned
+				}
+			case "channel":
+				if inst.Channel != inst.original.Channel {
+					kv["channel"] = inst.Channel
+				}
+			case "sync_at":
+				if inst.SyncAt != inst.original.SyncAt {
+					kv["sync_at"] = inst.SyncAt
+				}
 			case "created_at":
 				if inst.CreatedAt != inst.original.CreatedAt {
 					kv["created_at"] = inst.CreatedAt
@@ -394,18 +442,21 @@ func (m *ChatMessagesModel) globalScopeEnabled(name string) bool {
 }
 
 type ChatMessages struct {
-	Id            int64  `json:"id"`
-	UserId        int64  `json:"user_id,omitempty"`
-	RoomId        int64  `json:"room_id,omitempty"`
-	Message       string `json:"message,omitempty"`
-	Role          int64  `json:"role,omitempty"`
-	TokenConsumed int64  `json:"token_consumed,omitempty"`
-	QuotaConsumed int64  `json:"quota_consumed,omitempty"`
-	Pid           int64  `json:"pid,omitempty"`
-	Model         string `json:"model,omitempty"`
-	Status        int64  `json:"status,omitempty"`
-	Error         string `json:"error,omitempty"`
-	Meta          string `json:"meta,omitempty"`
+	Id            int64     `json:"id"`
+	UserId        int64     `json:"user_id,omitempty"`
+	RoomId        int64     `json:"room_id,omitempty"`
+	Message       string    `json:"message,omitempty"`
+	Role          int64     `json:"role,omitempty"`
+	TokenConsumed int64     `json:"token_consumed,omitempty"`
+	QuotaConsumed int64     `json:"quota_consumed,omitempty"`
+	Pid           int64     `json:"pid,omitempty"`
+	Model         string    `json:"model,omitempty"`
+	Status        int64     `json:"status,omitempty"`
+	Error         string    `json:"error,omitempty"`
+	Meta          string    `json:"meta,omitempty"`
+	Pinned        int64     `json:"pinned,omitempty"`
+	Channel       string    `json:"-"`
+	SyncAt        time.Time `json:"-"`
 	CreatedAt     time.Time
 	UpdatedAt     time.Time
 }
@@ -426,6 +477,9 @@ func (w ChatMessages) ToChatMessagesN(allows ...string) ChatMessagesN {
 			Status:        null.IntFrom(int64(w.Status)),
 			Error:         null.StringFrom(w.Error),
 			Meta:          null.StringFrom(w.Meta),
+			Pinned:        null.IntFrom(int64(w.Pinned)),
+			Channel:       null.StringFrom(w.Channel),
+			SyncAt:        null.TimeFrom(w.SyncAt),
 			CreatedAt:     null.TimeFrom(w.CreatedAt),
 			UpdatedAt:     null.TimeFrom(w.UpdatedAt),
 		}
@@ -459,6 +513,12 @@ func (w ChatMessages) ToChatMessagesN(allows ...string) ChatMessagesN {
 			res.Error = null.StringFrom(w.Error)
 		case "meta":
 			res.Meta = null.StringFrom(w.Meta)
+		case "pinned":
+			res.Pinned = null.IntFrom(int64(w.Pinned))
+		case "channel":
+			res.Channel = null.StringFrom(w.Channel)
+		case "sync_at":
+			res.SyncAt = null.TimeFrom(w.SyncAt)
 		case "created_at":
 			res.CreatedAt = null.TimeFrom(w.CreatedAt)
 		case "updated_at":
@@ -491,6 +551,9 @@ func (w *ChatMessagesN) ToChatMessages() ChatMessages {
 		Status:        w.Status.Int64,
 		Error:         w.Error.String,
 		Meta:          w.Meta.String,
+		Pinned:        w.Pinned.Int64,
+		Channel:       w.Channel.String,
+		SyncAt:        w.SyncAt.Time,
 		CreatedAt:     w.CreatedAt.Time,
 		UpdatedAt:     w.UpdatedAt.Time,
 	}
@@ -527,6 +590,9 @@ const (
 	FieldChatMessagesStatus        = "status"
 	FieldChatMessagesError         = "error"
 	FieldChatMessagesMeta          = "meta"
+	FieldChatMessagesPinned        = "pinned"
+	FieldChatMessagesChannel       = "channel"
+	FieldChatMessagesSyncAt        = "sync_at"
 	FieldChatMessagesCreatedAt     = "created_at"
 	FieldChatMessagesUpdatedAt     = "updated_at"
 )
@@ -546,6 +612,9 @@ func ChatMessagesFields() []string {
 		"status",
 		"error",
 		"meta",
+		"pinned",
+		"channel",
+		"sync_at",
 		"created_at",
 		"updated_at",
 	}
@@ -690,6 +759,9 @@ func (m *ChatMessagesModel) Get(ctx context.Context, builders ...query.SQLBuilde
 			"status",
 			"error",
 			"meta",
+			"pinned",
+			"channel",
+			"sync_at",
 			"created_at",
 			"updated_at",
 		)
@@ -725,6 +797,12 @@ func (m *ChatMessagesModel) Get(ctx context.Context, builders ...query.SQLBuilde
 			selectFields = append(selectFields, f)
 		case "meta":
 			selectFields = append(selectFields, f)
+		case "pinned":
+			selectFields = append(selectFields, f)
+		case "channel":
+			selectFields = append(selectFields, f)
+		case "sync_at":
+			selectFields = append(selectFields, f)
 		case "created_at":
 			selectFields = append(selectFields, f)
 		case "updated_at":
@@ -763,6 +841,12 @@ func (m *ChatMessagesModel) Get(ctx context.Context, builders ...query.SQLBuilde
 				scanFields = append(scanFields, &chatMessagesVar.Error)
 			case "meta":
 				scanFields = append(scanFields, &chatMessagesVar.Meta)
+			case "pinned":
+				scanFields = append(scanFields, &chatMessagesVar.Pinned)
+			case "channel":
+				scanFields = append(scanFields, &chatMessagesVar.Channel)
+			case "sync_at":
+				scanFields = append(scanFields, &chatMessagesVar.SyncAt)
 			case "created_at":
 				scanFields = append(scanFields, &chatMessagesVar.CreatedAt)
 			case "updated_at":
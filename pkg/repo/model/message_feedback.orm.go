@@ -0,0 +1,859 @@
+package model
+
+// !!! DO NOT EDIT THIS FILE
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/iancoleman/strcase"
+	"github.com/mylxsw/eloquent/query"
+	"gopkg.in/guregu/null.v3"
+	"time"
+)
+
+func init() {
+
+}
+
+// MessageFeedbackN is a MessageFeedback object, all fields are nullable
+type MessageFeedbackN struct {
+	original             *messageFeedbackOriginal
+	messageFeedbackModel *MessageFeedbackModel
+
+	Id          null.Int    `json:"id"`
+	UserId      null.Int    `json:"user_id,omitempty"`
+	MessageType null.String `json:"message_type"`
+	MessageId   null.Int    `json:"message_id"`
+	RoomId      null.Int    `json:"room_id,omitempty"`
+	Model       null.String `json:"model,omitempty"`
+	Channel     null.String `json:"channel,omitempty"`
+	Rating      null.Int    `json:"rating"`
+	Reasons     null.String `json:"reasons,omitempty"`
+	Comment     null.String `json:"comment,omitempty"`
+	CreatedAt   null.Time
+	UpdatedAt   null.Time
+}
+
+// As convert object to other type
+// dst must be a pointer to struct
+func (inst *MessageFeedbackN) As(dst interface{}) error {
+	return query.Copy(inst, dst)
+}
+
+// SetModel set model for MessageFeedback
+func (inst *MessageFeedbackN) SetModel(messageFeedbackModel *MessageFeedbackModel) {
+	inst.messageFeedbackModel = messageFeedbackModel
+}
+
+// messageFeedbackOriginal is an object which stores original MessageFeedback from database
+type messageFeedbackOriginal struct {
+	Id          null.Int
+	UserId      null.Int
+	MessageType null.String
+	MessageId   null.Int
+	RoomId      null.Int
+	Model       null.String
+	Channel     null.String
+	Rating      null.Int
+	Reasons     null.String
+	Comment     null.String
+	CreatedAt   null.Time
+	UpdatedAt   null.Time
+}
+
+// Staled identify whether the object has been modified
+func (inst *MessageFeedbackN) Staled(onlyFields ...string) bool {
+	if inst.original == nil {
+		inst.original = &messageFeedbackOriginal{}
+	}
+
+	if len(onlyFields) == 0 {
+
+		if inst.Id != inst.original.Id {
+			return true
+		}
+		if inst.UserId != inst.original.UserId {
+			return true
+		}
+		if inst.MessageType != inst.original.MessageType {
+			return true
+		}
+		if inst.MessageId != inst.original.MessageId {
+			return true
+		}
+		if inst.RoomId != inst.original.RoomId {
+			return true
+		}
+		if inst.Model != inst.original.Model {
+			return true
+		}
+		if inst.Channel != inst.original.Channel {
+			return true
+		}
+		if inst.Rating != inst.original.Rating {
+			return true
+		}
+		if inst.Reasons != inst.original.Reasons {
+			return true
+		}
+		if inst.Comment != inst.original.Comment {
+			return true
+		}
+		if inst.CreatedAt != inst.original.CreatedAt {
+			return true
+		}
+		if inst.UpdatedAt != inst.original.UpdatedAt {
+			return true
+		}
+	} else {
+		for _, f := range onlyFields {
+			switch strcase.ToSnake(f) {
+
+			case "id":
+				if inst.Id != inst.original.Id {
+					return true
+				}
+			case "user_id":
+				if inst.UserId != inst.original.UserId {
+					return true
+				}
+			case "message_type":
+				if inst.MessageType != inst.original.MessageType {
+					return true
+				}
+			case "message_id":
+				if inst.MessageId != inst.original.MessageId {
+					return true
+				}
+			case "room_id":
+				if inst.RoomId != inst.original.RoomId {
+					return true
+				}
+			case "model":
+				if inst.Model != inst.original.Model {
+					return true
+				}
+			case "channel":
+				if inst.Channel != inst.original.Channel {
+					return true
+				}
+			case "rating":
+				if inst.Rating != inst.original.Rating {
+					return true
+				}
+			case "reasons":
+				if inst.Reasons != inst.original.Reasons {
+					return true
+				}
+			case "comment":
+				if inst.Comment != inst.original.Comment {
+					return true
+				}
+			case "created_at":
+				if inst.CreatedAt != inst.original.CreatedAt {
+					return true
+				}
+			case "updated_at":
+				if inst.UpdatedAt != inst.original.UpdatedAt {
+					return true
+				}
+			default:
+			}
+		}
+	}
+
+	return false
+}
+
+// StaledKV return all fields has been modified
+func (inst *MessageFeedbackN) StaledKV(onlyFields ...string) query.KV {
+	kv := make(query.KV, 0)
+
+	if inst.original == nil {
+		inst.original = &messageFeedbackOriginal{}
+	}
+
+	if len(onlyFields) == 0 {
+
+		if inst.Id != inst.original.Id {
+			kv["id"] = inst.Id
+		}
+		if inst.UserId != inst.original.UserId {
+			kv["user_id"] = inst.UserId
+		}
+		if inst.MessageType != inst.original.MessageType {
+			kv["message_type"] = inst.MessageType
+		}
+		if inst.MessageId != inst.original.MessageId {
+			kv["message_id"] = inst.MessageId
+		}
+		if inst.RoomId != inst.original.RoomId {
+			kv["room_id"] = inst.RoomId
+		}
+		if inst.Model != inst.original.Model {
+			kv["model"] = inst.Model
+		}
+		if inst.Channel != inst.original.Channel {
+			kv["channel"] = inst.Channel
+		}
+		if inst.Rating != inst.original.Rating {
+			kv["rating"] = inst.Rating
+		}
+		if inst.Reasons != inst.original.Reasons {
+			kv["reasons"] = inst.Reasons
+		}
+		if inst.Comment != inst.original.Comment {
+			kv["comment"] = inst.Comment
+		}
+		if inst.CreatedAt != inst.original.CreatedAt {
+			kv["created_at"] = inst.CreatedAt
+		}
+		if inst.UpdatedAt != inst.original.UpdatedAt {
+			kv["updated_at"] = inst.UpdatedAt
+		}
+	} else {
+		for _, f := range onlyFields {
+			switch strcase.ToSnake(f) {
+
+			case "id":
+				if inst.Id != inst.original.Id {
+					kv["id"] = inst.Id
+				}
+			case "user_id":
+				if inst.UserId != inst.original.UserId {
+					kv["user_id"] = inst.UserId
+				}
+			case "message_type":
+				if inst.MessageType != inst.original.MessageType {
+					kv["message_type"] = inst.MessageType
+				}
+			case "message_id":
+				if inst.MessageId != inst.original.MessageId {
+					kv["message_id"] = inst.MessageId
+				}
+			case "room_id":
+				if inst.RoomId != inst.original.RoomId {
+					kv["room_id"] = inst.RoomId
+				}
+			case "model":
+				if inst.Model != inst.original.Model {
+					kv["model"] = inst.Model
+				}
+			case "channel":
+				if inst.Channel != inst.original.Channel {
+					kv["channel"] = inst.Channel
+				}
+			case "rating":
+				if inst.Rating != inst.original.Rating {
+					kv["rating"] = inst.Rating
+				}
+			case "reasons":
+				if inst.Reasons != inst.original.Reasons {
+					kv["reasons"] = inst.Reasons
+				}
+			case "comment":
+				if inst.Comment != inst.original.Comment {
+					kv["comment"] = inst.Comment
+				}
+			case "created_at":
+				if inst.CreatedAt != inst.original.CreatedAt {
+					kv["created_at"] = inst.CreatedAt
+				}
+			case "updated_at":
+				if inst.UpdatedAt != inst.original.UpdatedAt {
+					kv["updated_at"] = inst.UpdatedAt
+				}
+			default:
+			}
+		}
+	}
+
+	return kv
+}
+
+// Save create a new model or update it
+func (inst *MessageFeedbackN) Save(ctx context.Context, onlyFields ...string) error {
+	if inst.messageFeedbackModel == nil {
+		return query.ErrModelNotSet
+	}
+
+	id, _, err := inst.messageFeedbackModel.SaveOrUpdate(ctx, *inst, onlyFields...)
+	if err != nil {
+		return err
+	}
+
+	inst.Id = null.IntFrom(id)
+	return nil
+}
+
+// Delete remove a message_feedback
+func (inst *MessageFeedbackN) Delete(ctx context.Context) error {
+	if inst.messageFeedbackModel == nil {
+		return query.ErrModelNotSet
+	}
+
+	_, err := inst.messageFeedbackModel.DeleteById(ctx, inst.Id.Int64)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// String convert instance to json string
+func (inst *MessageFeedbackN) String() string {
+	rs, _ := json.Marshal(inst)
+	return string(rs)
+}
+
+type messageFeedbackScope struct {
+	name  string
+	apply func(builder query.Condition)
+}
+
+var messageFeedbackGlobalScopes = make([]messageFeedbackScope, 0)
+var messageFeedbackLocalScopes = make([]messageFeedbackScope, 0)
+
+// AddGlobalScopeForMessageFeedback assign a global scope to a model
+func AddGlobalScopeForMessageFeedback(name string, apply func(builder query.Condition)) {
+	messageFeedbackGlobalScopes = append(messageFeedbackGlobalScopes, messageFeedbackScope{name: name, apply: apply})
+}
+
+// AddLocalScopeForMessageFeedback assign a local scope to a model
+func AddLocalScopeForMessageFeedback(name string, apply func(builder query.Condition)) {
+	messageFeedbackLocalScopes = append(messageFeedbackLocalScopes, messageFeedbackScope{name: name, apply: apply})
+}
+
+func (m *MessageFeedbackModel) applyScope() query.Condition {
+	scopeCond := query.ConditionBuilder()
+	for _, g := range messageFeedbackGlobalScopes {
+		if m.globalScopeEnabled(g.name) {
+			g.apply(scopeCond)
+		}
+	}
+
+	for _, s := range messageFeedbackLocalScopes {
+		if m.localScopeEnabled(s.name) {
+			s.apply(scopeCond)
+		}
+	}
+
+	return scopeCond
+}
+
+func (m *MessageFeedbackModel) localScopeEnabled(name string) bool {
+	for _, n := range m.includeLocalScopes {
+		if name == n {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m *MessageFeedbackModel) globalScopeEnabled(name string) bool {
+	for _, n := range m.excludeGlobalScopes {
+		if name == n {
+			return false
+		}
+	}
+
+	return true
+}
+
+type MessageFeedback struct {
+	Id          int64  `json:"id"`
+	UserId      int64  `json:"user_id,omitempty"`
+	MessageType string `json:"message_type"`
+	MessageId   int64  `json:"message_id"`
+	RoomId      int64  `json:"room_id,omitempty"`
+	Model       string `json:"model,omitempty"`
+	Channel     string `json:"channel,omitempty"`
+	Rating      int64  `json:"rating"`
+	Reasons     string `json:"reasons,omitempty"`
+	Comment     string `json:"comment,omitempty"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (w MessageFeedback) ToMessageFeedbackN(allows ...string) MessageFeedbackN {
+	if len(allows) == 0 {
+		return MessageFeedbackN{
+
+			Id:          null.IntFrom(int64(w.Id)),
+			UserId:      null.IntFrom(int64(w.UserId)),
+			MessageType: null.StringFrom(w.MessageType),
+			MessageId:   null.IntFrom(int64(w.MessageId)),
+			RoomId:      null.IntFrom(int64(w.RoomId)),
+			Model:       null.StringFrom(w.Model),
+			Channel:     null.StringFrom(w.Channel),
+			Rating:      null.IntFrom(int64(w.Rating)),
+			Reasons:     null.StringFrom(w.Reasons),
+			Comment:     null.StringFrom(w.Comment),
+			CreatedAt:   null.TimeFrom(w.CreatedAt),
+			UpdatedAt:   null.TimeFrom(w.UpdatedAt),
+		}
+	}
+
+	res := MessageFeedbackN{}
+	for _, al := range allows {
+		switch strcase.ToSnake(al) {
+
+		case "id":
+			res.Id = null.IntFrom(int64(w.Id))
+		case "user_id":
+			res.UserId = null.IntFrom(int64(w.UserId))
+		case "message_type":
+			res.MessageType = null.StringFrom(w.MessageType)
+		case "message_id":
+			res.MessageId = null.IntFrom(int64(w.MessageId))
+		case "room_id":
+			res.RoomId = null.IntFrom(int64(w.RoomId))
+		case "model":
+			res.Model = null.StringFrom(w.Model)
+		case "channel":
+			res.Channel = null.StringFrom(w.Channel)
+		case "rating":
+			res.Rating = null.IntFrom(int64(w.Rating))
+		case "reasons":
+			res.Reasons = null.StringFrom(w.Reasons)
+		case "comment":
+			res.Comment = null.StringFrom(w.Comment)
+		case "created_at":
+			res.CreatedAt = null.TimeFrom(w.CreatedAt)
+		case "updated_at":
+			res.UpdatedAt = null.TimeFrom(w.UpdatedAt)
+		default:
+		}
+	}
+
+	return res
+}
+
+// As convert object to other type
+// dst must be a pointer to struct
+func (w MessageFeedback) As(dst interface{}) error {
+	return query.Copy(w, dst)
+}
+
+func (w *MessageFeedbackN) ToMessageFeedback() MessageFeedback {
+	return MessageFeedback{
+
+		Id:          w.Id.Int64,
+		UserId:      w.UserId.Int64,
+		MessageType: w.MessageType.String,
+		MessageId:   w.MessageId.Int64,
+		RoomId:      w.RoomId.Int64,
+		Model:       w.Model.String,
+		Channel:     w.Channel.String,
+		Rating:      w.Rating.Int64,
+		Reasons:     w.Reasons.String,
+		Comment:     w.Comment.String,
+		CreatedAt:   w.CreatedAt.Time,
+		UpdatedAt:   w.UpdatedAt.Time,
+	}
+}
+
+// MessageFeedbackModel is a model which encapsulates the operations of the object
+type MessageFeedbackModel struct {
+	db        *query.DatabaseWrap
+	tableName string
+
+	excludeGlobalScopes []string
+	includeLocalScopes  []string
+
+	query query.SQLBuilder
+}
+
+var messageFeedbackTableName = "message_feedback"
+
+// MessageFeedbackTable return table name for MessageFeedback
+func MessageFeedbackTable() string {
+	return messageFeedbackTableName
+}
+
+const (
+	FieldMessageFeedbackId          = "id"
+	FieldMessageFeedbackUserId      = "user_id"
+	FieldMessageFeedbackMessageType = "message_type"
+	FieldMessageFeedbackMessageId   = "message_id"
+	FieldMessageFeedbackRoomId      = "room_id"
+	FieldMessageFeedbackModel       = "model"
+	FieldMessageFeedbackChannel     = "channel"
+	FieldMessageFeedbackRating      = "rating"
+	FieldMessageFeedbackReasons     = "reasons"
+	FieldMessageFeedbackComment     = "comment"
+	FieldMessageFeedbackCreatedAt   = "created_at"
+	FieldMessageFeedbackUpdatedAt   = "updated_at"
+)
+
+// MessageFeedbackFields return all fields in MessageFeedback model
+func MessageFeedbackFields() []string {
+	return []string{
+		"id",
+		"user_id",
+		"message_type",
+		"message_id",
+		"room_id",
+		"model",
+		"channel",
+		"rating",
+		"reasons",
+		"comment",
+		"created_at",
+		"updated_at",
+	}
+}
+
+func SetMessageFeedbackTable(tableName string) {
+	messageFeedbackTableName = tableName
+}
+
+// NewMessageFeedbackModel create a MessageFeedbackModel
+func NewMessageFeedbackModel(db query.Database) *MessageFeedbackModel {
+	return &MessageFeedbackModel{
+		db:                  query.NewDatabaseWrap(db),
+		tableName:           messageFeedbackTableName,
+		excludeGlobalScopes: make([]string, 0),
+		includeLocalScopes:  make([]string, 0),
+		query:               query.Builder(),
+	}
+}
+
+// GetDB return database instance
+func (m *MessageFeedbackModel) GetDB() query.Database {
+	return m.db.GetDB()
+}
+
+func (m *MessageFeedbackModel) clone() *MessageFeedbackModel {
+	return &MessageFeedbackModel{
+		db:                  m.db,
+		tableName:           m.tableName,
+		excludeGlobalScopes: append([]string{}, m.excludeGlobalScopes...),
+		includeLocalScopes:  append([]string{}, m.includeLocalScopes...),
+		query:               m.query,
+	}
+}
+
+// WithoutGlobalScopes remove a global scope for given query
+func (m *MessageFeedbackModel) WithoutGlobalScopes(names ...string) *MessageFeedbackModel {
+	mc := m.clone()
+	mc.excludeGlobalScopes = append(mc.excludeGlobalScopes, names...)
+
+	return mc
+}
+
+// WithLocalScopes add a local scope for given query
+func (m *MessageFeedbackModel) WithLocalScopes(names ...string) *MessageFeedbackModel {
+	mc := m.clone()
+	mc.includeLocalScopes = append(mc.includeLocalScopes, names...)
+
+	return mc
+}
+
+// Condition add query builder to model
+func (m *MessageFeedbackModel) Condition(builder query.SQLBuilder) *MessageFeedbackModel {
+	mm := m.clone()
+	mm.query = mm.query.Merge(builder)
+
+	return mm
+}
+
+// Find retrieve a model by its primary key
+func (m *MessageFeedbackModel) Find(ctx context.Context, id int64) (*MessageFeedbackN, error) {
+	return m.First(ctx, m.query.Where("id", "=", id))
+}
+
+// Exists return whether the records exists for a given query
+func (m *MessageFeedbackModel) Exists(ctx context.Context, builders ...query.SQLBuilder) (bool, error) {
+	count, err := m.Count(ctx, builders...)
+	return count > 0, err
+}
+
+// Count return model count for a given query
+func (m *MessageFeedbackModel) Count(ctx context.Context, builders ...query.SQLBuilder) (int64, error) {
+	sqlStr, params := m.query.
+		Merge(builders...).
+		Table(m.tableName).
+		AppendCondition(m.applyScope()).
+		ResolveCount()
+
+	rows, err := m.db.QueryContext(ctx, sqlStr, params...)
+	if err != nil {
+		return 0, err
+	}
+
+	defer rows.Close()
+
+	rows.Next()
+	var res int64
+	if err := rows.Scan(&res); err != nil {
+		return 0, err
+	}
+
+	return res, nil
+}
+
+func (m *MessageFeedbackModel) Paginate(ctx context.Context, page int64, perPage int64, builders ...query.SQLBuilder) ([]MessageFeedbackN, query.PaginateMeta, error) {
+	if page <= 0 {
+		page = 1
+	}
+
+	if perPage <= 0 {
+		perPage = 15
+	}
+
+	meta := query.PaginateMeta{
+		PerPage: perPage,
+		Page:    page,
+	}
+
+	count, err := m.Count(ctx, builders...)
+	if err != nil {
+		return nil, meta, err
+	}
+
+	meta.Total = count
+	meta.LastPage = count / perPage
+	if count%perPage != 0 {
+		meta.LastPage += 1
+	}
+
+	res, err := m.Get(ctx, append([]query.SQLBuilder{query.Builder().Limit(perPage).Offset((page - 1) * perPage)}, builders...)...)
+	if err != nil {
+		return res, meta, err
+	}
+
+	return res, meta, nil
+}
+
+// Get retrieve all results for given query
+func (m *MessageFeedbackModel) Get(ctx context.Context, builders ...query.SQLBuilder) ([]MessageFeedbackN, error) {
+	b := m.query.Merge(builders...).Table(m.tableName).AppendCondition(m.applyScope())
+	if len(b.GetFields()) == 0 {
+		b = b.Select(
+			"id",
+			"user_id",
+			"message_type",
+			"message_id",
+			"room_id",
+			"model",
+			"channel",
+			"rating",
+			"reasons",
+			"comment",
+			"created_at",
+			"updated_at",
+		)
+	}
+
+	fields := b.GetFields()
+	selectFields := make([]query.Expr, 0)
+
+	for _, f := range fields {
+		switch strcase.ToSnake(f.Value) {
+
+		case "id":
+			selectFields = append(selectFields, f)
+		case "user_id":
+			selectFields = append(selectFields, f)
+		case "message_type":
+			selectFields = append(selectFields, f)
+		case "message_id":
+			selectFields = append(selectFields, f)
+		case "room_id":
+			selectFields = append(selectFields, f)
+		case "model":
+			selectFields = append(selectFields, f)
+		case "channel":
+			selectFields = append(selectFields, f)
+		case "rating":
+			selectFields = append(selectFields, f)
+		case "reasons":
+			selectFields = append(selectFields, f)
+		case "comment":
+			selectFields = append(selectFields, f)
+		case "created_at":
+			selectFields = append(selectFields, f)
+		case "updated_at":
+			selectFields = append(selectFields, f)
+		}
+	}
+
+	var createScanVar = func(fields []query.Expr) (*MessageFeedbackN, []interface{}) {
+		var messageFeedbackVar MessageFeedbackN
+		scanFields := make([]interface{}, 0)
+
+		for _, f := range fields {
+			switch strcase.ToSnake(f.Value) {
+
+			case "id":
+				scanFields = append(scanFields, &messageFeedbackVar.Id)
+			case "user_id":
+				scanFields = append(scanFields, &messageFeedbackVar.UserId)
+			case "message_type":
+				scanFields = append(scanFields, &messageFeedbackVar.MessageType)
+			case "message_id":
+				scanFields = append(scanFields, &messageFeedbackVar.MessageId)
+			case "room_id":
+				scanFields = append(scanFields, &messageFeedbackVar.RoomId)
+			case "model":
+				scanFields = append(scanFields, &messageFeedbackVar.Model)
+			case "channel":
+				scanFields = append(scanFields, &messageFeedbackVar.Channel)
+			case "rating":
+				scanFields = append(scanFields, &messageFeedbackVar.Rating)
+			case "reasons":
+				scanFields = append(scanFields, &messageFeedbackVar.Reasons)
+			case "comment":
+				scanFields = append(scanFields, &messageFeedbackVar.Comment)
+			case "created_at":
+				scanFields = append(scanFields, &messageFeedbackVar.CreatedAt)
+			case "updated_at":
+				scanFields = append(scanFields, &messageFeedbackVar.UpdatedAt)
+			}
+		}
+
+		return &messageFeedbackVar, scanFields
+	}
+
+	sqlStr, params := b.Fields(selectFields...).ResolveQuery()
+
+	rows, err := m.db.QueryContext(ctx, sqlStr, params...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	messageFeedbacks := make([]MessageFeedbackN, 0)
+	for rows.Next() {
+		messageFeedbackReal, scanFields := createScanVar(fields)
+		if err := rows.Scan(scanFields...); err != nil {
+			return nil, err
+		}
+
+		messageFeedbackReal.original = &messageFeedbackOriginal{}
+		_ = query.Copy(messageFeedbackReal, messageFeedbackReal.original)
+
+		messageFeedbackReal.SetModel(m)
+		messageFeedbacks = append(messageFeedbacks, *messageFeedbackReal)
+	}
+
+	return messageFeedbacks, nil
+}
+
+// First return first result for given query
+func (m *MessageFeedbackModel) First(ctx context.Context, builders ...query.SQLBuilder) (*MessageFeedbackN, error) {
+	res, err := m.Get(ctx, append(builders, query.Builder().Limit(1))...)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res) == 0 {
+		return nil, query.ErrNoResult
+	}
+
+	return &res[0], nil
+}
+
+// Create save a new message_feedback to database
+func (m *MessageFeedbackModel) Create(ctx context.Context, kv query.KV) (int64, error) {
+
+	if _, ok := kv["created_at"]; !ok {
+		kv["created_at"] = time.Now()
+	}
+
+	if _, ok := kv["updated_at"]; !ok {
+		kv["updated_at"] = time.Now()
+	}
+
+	sqlStr, params := m.query.Table(m.tableName).ResolveInsert(kv)
+
+	res, err := m.db.ExecContext(ctx, sqlStr, params...)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.LastInsertId()
+}
+
+// SaveAll save all message_feedbacks to database
+func (m *MessageFeedbackModel) SaveAll(ctx context.Context, messageFeedbacks []MessageFeedbackN) ([]int64, error) {
+	ids := make([]int64, 0)
+	for _, messageFeedback := range messageFeedbacks {
+		id, err := m.Save(ctx, messageFeedback)
+		if err != nil {
+			return ids, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// Save save a message_feedback to database
+func (m *MessageFeedbackModel) Save(ctx context.Context, messageFeedback MessageFeedbackN, onlyFields ...string) (int64, error) {
+	return m.Create(ctx, messageFeedback.StaledKV(onlyFields...))
+}
+
+// SaveOrUpdate save a new message_feedback or update it when it has a id > 0
+func (m *MessageFeedbackModel) SaveOrUpdate(ctx context.Context, messageFeedback MessageFeedbackN, onlyFields ...string) (id int64, updated bool, err error) {
+	if messageFeedback.Id.Int64 > 0 {
+		_, _err := m.UpdateById(ctx, messageFeedback.Id.Int64, messageFeedback, onlyFields...)
+		return messageFeedback.Id.Int64, true, _err
+	}
+
+	_id, _err := m.Save(ctx, messageFeedback, onlyFields...)
+	return _id, false, _err
+}
+
+// UpdateFields update kv for a given query
+func (m *MessageFeedbackModel) UpdateFields(ctx context.Context, kv query.KV, builders ...query.SQLBuilder) (int64, error) {
+	if len(kv) == 0 {
+		return 0, nil
+	}
+
+	kv["updated_at"] = time.Now()
+
+	sqlStr, params := m.query.Merge(builders...).AppendCondition(m.applyScope()).
+		Table(m.tableName).
+		ResolveUpdate(kv)
+
+	res, err := m.db.ExecContext(ctx, sqlStr, params...)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
+// Update update a model for given query
+func (m *MessageFeedbackModel) Update(ctx context.Context, builder query.SQLBuilder, messageFeedback MessageFeedbackN, onlyFields ...string) (int64, error) {
+	return m.UpdateFields(ctx, messageFeedback.StaledKV(onlyFields...), builder)
+}
+
+// UpdateById update a model by id
+func (m *MessageFeedbackModel) UpdateById(ctx context.Context, id int64, messageFeedback MessageFeedbackN, onlyFields ...string) (int64, error) {
+	return m.Condition(query.Builder().Where("id", "=", id)).UpdateFields(ctx, messageFeedback.StaledKV(onlyFields...))
+}
+
+// Delete remove a model
+func (m *MessageFeedbackModel) Delete(ctx context.Context, builders ...query.SQLBuilder) (int64, error) {
+
+	sqlStr, params := m.query.Merge(builders...).AppendCondition(m.applyScope()).Table(m.tableName).ResolveDelete()
+
+	res, err := m.db.ExecContext(ctx, sqlStr, params...)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+
+}
+
+// DeleteById remove a model by id
+func (m *MessageFeedbackModel) DeleteById(ctx context.Context, id int64) (int64, error) {
+	return m.Condition(query.Builder().Where("id", "=", id)).Delete(ctx)
+}
@@ -34,6 +34,14 @@ type RoomsN struct {
 	RoomType       null.Int    `json:"room_type,omitempty"`
 	InitMessage    null.String `json:"init_message,omitempty"`
 	LastActiveTime null.Time   `json:"last_active_time,omitempty"`
+	Version        null.Int    `json:"version,omitempty"`
+	FolderId       null.Int    `json:"folder_id,omitempty"`
+	Pinned         null.Int    `json:"pinned,omitempty"`
+	Archived       null.Int    `json:"archived,omitempty"`
+	MemoryDisabled null.Int    `json:"memory_disabled,omitempty"`
+	AutoTitle      null.Int    `json:"auto_title,omitempty"`
+	PresetsJson    null.String `json:"presets_json,omitempty"`
+	SyncAt         null.Time   `json:"-"`
 	CreatedAt      null.Time
 	UpdatedAt      null.Time
 }
@@ -65,6 +73,14 @@ type roomsOriginal struct {
 	RoomType       null.Int
 	InitMessage    null.String
 	LastActiveTime null.Time
+	Version        null.Int
+	FolderId       null.Int
+	Pinned         null.Int
+	Archived       null.Int
+	MemoryDisabled null.Int
+	AutoTitle      null.Int
+	PresetsJson    null.String
+	SyncAt         null.Time
 	CreatedAt      null.Time
 	UpdatedAt      null.Time
 }
@@ -119,6 +135,30 @@ func (inst *RoomsN) Staled(onlyFields ...string) bool {
 		if inst.LastActiveTime != inst.original.LastActiveTime {
 			return true
 		}
+		if inst.Version != inst.original.Version {
+			return true
+		}
+		if inst.FolderId != inst.original.FolderId {
+			return true
+		}
+		if inst.Pinned != inst.original.Pinned {
+			return true
+		}
+		if inst.Archived != inst.original.Archived {
+			return true
+		}
+		if inst.MemoryDisabled != inst.original.MemoryDisabled {
+			return true
+		}
+		if inst.AutoTitle != inst.original.AutoTitle {
+			return true
+		}
+		if inst.PresetsJson != inst.original.PresetsJson {
+			return true
+		}
+		if inst.SyncAt != inst.original.SyncAt {
+			return true
+		}
 		if inst.CreatedAt != inst.original.CreatedAt {
 			return true
 		}
@@ -185,6 +225,38 @@ func (inst *RoomsN) Staled(onlyFields ...string) bool {
 				if inst.LastActiveTime != inst.original.LastActiveTime {
 					return true
 				}
+			case "version":
+				if inst.Version != inst.original.Version {
+					return true
+				}
+			case "folder_id":
+				if inst.FolderId != inst.original.FolderId {
+					return true
+				}
+			case "pinned":
+				if inst.Pinned != inst.original.Pinned {
+					return true
+				}
+			case "archived":
+				if inst.Archived != inst.original.Archived {
+					return true
+				}
+			case "memory_disabled":
+				if inst.MemoryDisabled != inst.original.MemoryDisabled {
+					return true
+				}
+			case "auto_title":
+				if inst.AutoTitle != inst.original.AutoTitle {
+					return true
+				}
+			case "presets_json":
+				if inst.PresetsJson != inst.original.PresetsJson {
+					return true
+				}
+			case "sync_at":
+				if inst.SyncAt != inst.original.SyncAt {
+					return true
+				}
 			case "created_at":
 				if inst.CreatedAt != inst.original.CreatedAt {
 					return true
@@ -253,6 +325,30 @@ func (inst *RoomsN) StaledKV(onlyFields ...string) query.KV {
 		if inst.LastActiveTime != inst.original.LastActiveTime {
 			kv["last_active_time"] = inst.LastActiveTime
 		}
+		if inst.Version != inst.original.Version {
+			kv["version"] = inst.Version
+		}
+		if inst.FolderId != inst.original.FolderId {
+			kv["folder_id"] = inst.FolderId
+		}
+		if inst.Pinned != inst.original.Pinned {
+			kv["pinned"] = inst.Pinned
+		}
+		if inst.Archived != inst.original.Archived {
+			kv["archived"] = inst.Archived
+		}
+		if inst.MemoryDisabled != inst.original.MemoryDisabled {
+			kv["memory_disabled"] = inst.MemoryDisabled
+		}
+		if inst.AutoTitle != inst.original.AutoTitle {
+			kv["auto_title"] = inst.AutoTitle
+		}
+		if inst.PresetsJson != inst.original.PresetsJson {
+			kv["presets_json"] = inst.PresetsJson
+		}
+		if inst.SyncAt != inst.original.SyncAt {
+			kv["sync_at"] = inst.SyncAt
+		}
 		if inst.CreatedAt != inst.original.CreatedAt {
 			kv["created_at"] = inst.CreatedAt
 		}
@@ -319,6 +415,38 @@ func (inst *RoomsN) StaledKV(onlyFields ...string) query.KV {
 				if inst.LastActiveTime != inst.original.LastActiveTime {
 					kv["last_active_time"] = inst.LastActiveTime
 				}
+			case "version":
+				if inst.Version != inst.original.Version {
+					kv["version"] = inst.Version
+				}
+			case "folder_id":
+				if inst.FolderId != inst.original.FolderId {
+					kv["folder_id"] = inst.FolderId
+				}
+			case "pinned":
+				if inst.Pinned != inst.original.Pinned {
+					kv["pinned"] = inst.Pinned
+				}
+			case "archived":
+				if inst.Archived != inst.original.Archived {
+					kv["archived"] = inst.Archived
+				}
+			case "memory_disabled":
+				if inst.MemoryDisabled != inst.original.MemoryDisabled {
+					kv["memory_disabled"] = inst.MemoryDisabled
+				}
+			case "auto_title":
+				if inst.AutoTitle != inst.original.AutoTitle {
+					kv["auto_title"] = inst.AutoTitle
+				}
+			case "presets_json":
+				if inst.PresetsJson != inst.original.PresetsJson {
+					kv["presets_json"] = inst.PresetsJson
+				}
+			case "sync_at":
+				if inst.SyncAt != inst.original.SyncAt {
+					kv["sync_at"] = inst.SyncAt
+				}
 			case "created_at":
 				if inst.CreatedAt != inst.original.CreatedAt {
 					kv["created_at"] = inst.CreatedAt
@@ -440,6 +568,14 @@ type Rooms struct {
 	RoomType       int64     `json:"room_type,omitempty"`
 	InitMessage    string    `json:"init_message,omitempty"`
 	LastActiveTime time.Time `json:"last_active_time,omitempty"`
+	Version        int64     `json:"version,omitempty"`
+	FolderId       int64     `json:"folder_id,omitempty"`
+	Pinned         int64     `json:"pinned,omitempty"`
+	Archived       int64     `json:"archived,omitempty"`
+	MemoryDisabled int64     `json:"memory_disabled,omitempty"`
+	AutoTitle      int64     `json:"auto_title,omitempty"`
+	PresetsJson    string    `json:"presets_json,omitempty"`
+	SyncAt         time.Time `json:"-"`
 	CreatedAt      time.Time
 	UpdatedAt      time.Time
 }
@@ -462,6 +598,14 @@ func (w Rooms) ToRoomsN(allows ...string) RoomsN {
 			RoomType:       null.IntFrom(int64(w.RoomType)),
 			InitMessage:    null.StringFrom(w.InitMessage),
 			LastActiveTime: null.TimeFrom(w.LastActiveTime),
+			Version:        null.IntFrom(int64(w.Version)),
+			FolderId:       null.IntFrom(int64(w.FolderId)),
+			Pinned:         null.IntFrom(int64(w.Pinned)),
+			Archived:       null.IntFrom(int64(w.Archived)),
+			MemoryDisabled: null.IntFrom(int64(w.MemoryDisabled)),
+			AutoTitle:      null.IntFrom(int64(w.AutoTitle)),
+			PresetsJson:    null.StringFrom(w.PresetsJson),
+			SyncAt:         null.TimeFrom(w.SyncAt),
 			CreatedAt:      null.TimeFrom(w.CreatedAt),
 			UpdatedAt:      null.TimeFrom(w.UpdatedAt),
 		}
@@ -499,6 +643,22 @@ func (w Rooms) ToRoomsN(allows ...string) RoomsN {
 			res.InitMessage = null.StringFrom(w.InitMessage)
 		case "last_active_time":
 			res.LastActiveTime = null.TimeFrom(w.LastActiveTime)
+		case "version":
+			res.Version = null.IntFrom(int64(w.Version))
+		case "folder_id":
+			res.FolderId = null.IntFrom(int64(w.FolderId))
+		case "pinned":
+			res.Pinned = null.IntFrom(int64(w.Pinned))
+		case "archived":
+			res.Archived = null.IntFrom(int64(w.Archived))
+		case "memory_disabled":
+			res.MemoryDisabled = null.IntFrom(int64(w.MemoryDisabled))
+		case "auto_title":
+			res.AutoTitle = null.IntFrom(int64(w.AutoTitle))
+		case "presets_json":
+			res.PresetsJson = null.StringFrom(w.PresetsJson)
+		case "sync_at":
+			res.SyncAt = null.TimeFrom(w.SyncAt)
 		case "created_at":
 			res.CreatedAt = null.TimeFrom(w.CreatedAt)
 		case "updated_at":
@@ -533,6 +693,14 @@ func (w *RoomsN) ToRooms() Rooms {
 		RoomType:       w.RoomType.Int64,
 		InitMessage:    w.InitMessage.String,
 		LastActiveTime: w.LastActiveTime.Time,
+		Version:        w.Version.Int64,
+		FolderId:       w.FolderId.Int64,
+		Pinned:         w.Pinned.Int64,
+		Archived:       w.Archived.Int64,
+		MemoryDisabled: w.MemoryDisabled.Int64,
+		AutoTitle:      w.AutoTitle.Int64,
+		PresetsJson:    w.PresetsJson.String,
+		SyncAt:         w.SyncAt.Time,
 		CreatedAt:      w.CreatedAt.Time,
 		UpdatedAt:      w.UpdatedAt.Time,
 	}
@@ -571,6 +739,14 @@ const (
 	FieldRoomsRoomType       = "room_type"
 	FieldRoomsInitMessage    = "init_message"
 	FieldRoomsLastActiveTime = "last_active_time"
+	FieldRoomsVersion        = "version"
+	FieldRoomsFolderId       = "folder_id"
+	FieldRoomsPinned         = "pinned"
+	FieldRoomsArchived       = "archived"
+	FieldRoomsMemoryDisabled = "memory_disabled"
+	FieldRoomsAutoTitle      = "auto_title"
+	FieldRoomsPresetsJson    = "presets_json"
+	FieldRoomsSyncAt         = "sync_at"
 	FieldRoomsCreatedAt      = "created_at"
 	FieldRoomsUpdatedAt      = "updated_at"
 )
@@ -592,6 +768,14 @@ func RoomsFields() []string {
 		"room_type",
 		"init_message",
 		"last_active_time",
+		"version",
+		"folder_id",
+		"pinned",
+		"archived",
+		"memory_disabled",
+		"auto_title",
+		"presets_json",
+		"sync_at",
 		"created_at",
 		"updated_at",
 	}
@@ -738,6 +922,14 @@ func (m *RoomsModel) Get(ctx context.Context, builders ...query.SQLBuilder) ([]R
 			"room_type",
 			"init_message",
 			"last_active_time",
+			"version",
+			"folder_id",
+			"pinned",
+			"archived",
+			"memory_disabled",
+			"auto_title",
+			"presets_json",
+			"sync_at",
 			"created_at",
 			"updated_at",
 		)
@@ -777,6 +969,22 @@ func (m *RoomsModel) Get(ctx context.Context, builders ...query.SQLBuilder) ([]R
 			selectFields = append(selectFields, f)
 		case "last_active_time":
 			selectFields = append(selectFields, f)
+		case "version":
+			selectFields = append(selectFields, f)
+		case "folder_id":
+			selectFields = append(selectFields, f)
+		case "pinned":
+			selectFields = append(selectFields, f)
+		case "archived":
+			selectFields = append(selectFields, f)
+		case "memory_disabled":
+			selectFields = append(selectFields, f)
+		case "auto_title":
+			selectFields = append(selectFields, f)
+		case "presets_json":
+			selectFields = append(selectFields, f)
+		case "sync_at":
+			selectFields = append(selectFields, f)
 		case "created_at":
 			selectFields = append(selectFields, f)
 		case "updated_at":
@@ -819,6 +1027,22 @@ func (m *RoomsModel) Get(ctx context.Context, builders ...query.SQLBuilder) ([]R
 				scanFields = append(scanFields, &roomsVar.InitMessage)
 			case "last_active_time":
 				scanFields = append(scanFields, &roomsVar.LastActiveTime)
+			case "version":
+				scanFields = append(scanFields, &roomsVar.Version)
+			case "folder_id":
+				scanFields = append(scanFields, &roomsVar.FolderId)
+			case "pinned":
+				scanFields = append(scanFields, &roomsVar.Pinned)
+			case "archived":
+				scanFields = append(scanFields, &roomsVar.Archived)
+			case "memory_disabled":
+				scanFields = append(scanFields, &roomsVar.MemoryDisabled)
+			case "auto_title":
+				scanFields = append(scanFields, &roomsVar.AutoTitle)
+			case "presets_json":
+				scanFields = append(scanFields, &roomsVar.PresetsJson)
+			case "sync_at":
+				scanFields = append(scanFields, &roomsVar.SyncAt)
 			case "created_at":
 				scanFields = append(scanFields, &roomsVar.CreatedAt)
 			case "updated_at":
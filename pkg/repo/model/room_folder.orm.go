@@ -0,0 +1,1366 @@
+package model
+
+// !!! DO NOT EDIT THIS FILE
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/iancoleman/strcase"
+	"github.com/mylxsw/eloquent/query"
+	"gopkg.in/guregu/null.v3"
+	"time"
+)
+
+func init() {
+
+}
+
+// RoomFolderN is a RoomFolder object, all fields are nullable
+type RoomFolderN struct {
+	original        *roomFolderOriginal
+	roomFolderModel *RoomFolderModel
+
+	Id        null.Int    `json:"id"`
+	UserId    null.Int    `json:"user_id,omitempty"`
+	Name      null.String `json:"name"`
+	Sort      null.Int    `json:"sort"`
+	CreatedAt null.Time
+	UpdatedAt null.Time
+}
+
+// As convert object to other type
+// dst must be a pointer to struct
+func (inst *RoomFolderN) As(dst interface{}) error {
+	return query.Copy(inst, dst)
+}
+
+// SetModel set model for RoomFolder
+func (inst *RoomFolderN) SetModel(roomFolderModel *RoomFolderModel) {
+	inst.roomFolderModel = roomFolderModel
+}
+
+// roomFolderOriginal is an object which stores original RoomFolder from database
+type roomFolderOriginal struct {
+	Id        null.Int
+	UserId    null.Int
+	Name      null.String
+	Sort      null.Int
+	CreatedAt null.Time
+	UpdatedAt null.Time
+}
+
+// Staled identify whether the object has been modified
+func (inst *RoomFolderN) Staled(onlyFields ...string) bool {
+	if inst.original == nil {
+		inst.original = &roomFolderOriginal{}
+	}
+
+	if len(onlyFields) == 0 {
+
+		if inst.Id != inst.original.Id {
+			return true
+		}
+		if inst.UserId != inst.original.UserId {
+			return true
+		}
+		if inst.Name != inst.original.Name {
+			return true
+		}
+		if inst.Sort != inst.original.Sort {
+			return true
+		}
+		if inst.CreatedAt != inst.original.CreatedAt {
+			return true
+		}
+		if inst.UpdatedAt != inst.original.UpdatedAt {
+			return true
+		}
+	} else {
+		for _, f := range onlyFields {
+			switch strcase.ToSnake(f) {
+
+			case "id":
+				if inst.Id != inst.original.Id {
+					return true
+				}
+			case "user_id":
+				if inst.UserId != inst.original.UserId {
+					return true
+				}
+			case "name":
+				if inst.Name != inst.original.Name {
+					return true
+				}
+			case "sort":
+				if inst.Sort != inst.original.Sort {
+					return true
+				}
+			case "created_at":
+				if inst.CreatedAt != inst.original.CreatedAt {
+					return true
+				}
+			case "updated_at":
+				if inst.UpdatedAt != inst.original.UpdatedAt {
+					return true
+				}
+			default:
+			}
+		}
+	}
+
+	return false
+}
+
+// StaledKV return all fields has been modified
+func (inst *RoomFolderN) StaledKV(onlyFields ...string) query.KV {
+	kv := make(query.KV, 0)
+
+	if inst.original == nil {
+		inst.original = &roomFolderOriginal{}
+	}
+
+	if len(onlyFields) == 0 {
+
+		if inst.Id != inst.original.Id {
+			kv["id"] = inst.Id
+		}
+		if inst.UserId != inst.original.UserId {
+			kv["user_id"] = inst.UserId
+		}
+		if inst.Name != inst.original.Name {
+			kv["name"] = inst.Name
+		}
+		if inst.Sort != inst.original.Sort {
+			kv["sort"] = inst.Sort
+		}
+		if inst.CreatedAt != inst.original.CreatedAt {
+			kv["created_at"] = inst.CreatedAt
+		}
+		if inst.UpdatedAt != inst.original.UpdatedAt {
+			kv["updated_at"] = inst.UpdatedAt
+		}
+	} else {
+		for _, f := range onlyFields {
+			switch strcase.ToSnake(f) {
+
+			case "id":
+				if inst.Id != inst.original.Id {
+					kv["id"] = inst.Id
+				}
+			case "user_id":
+				if inst.UserId != inst.original.UserId {
+					kv["user_id"] = inst.UserId
+				}
+			case "name":
+				if inst.Name != inst.original.Name {
+					kv["name"] = inst.Name
+				}
+			case "sort":
+				if inst.Sort != inst.original.Sort {
+					kv["sort"] = inst.Sort
+				}
+			case "created_at":
+				if inst.CreatedAt != inst.original.CreatedAt {
+					kv["created_at"] = inst.CreatedAt
+				}
+			case "updated_at":
+				if inst.UpdatedAt != inst.original.UpdatedAt {
+					kv["updated_at"] = inst.UpdatedAt
+				}
+			default:
+			}
+		}
+	}
+
+	return kv
+}
+
+// Save create a new model or update it
+func (inst *RoomFolderN) Save(ctx context.Context, onlyFields ...string) error {
+	if inst.roomFolderModel == nil {
+		return query.ErrModelNotSet
+	}
+
+	id, _, err := inst.roomFolderModel.SaveOrUpdate(ctx, *inst, onlyFields...)
+	if err != nil {
+		return err
+	}
+
+	inst.Id = null.IntFrom(id)
+	return nil
+}
+
+// Delete remove a room_folder
+func (inst *RoomFolderN) Delete(ctx context.Context) error {
+	if inst.roomFolderModel == nil {
+		return query.ErrModelNotSet
+	}
+
+	_, err := inst.roomFolderModel.DeleteById(ctx, inst.Id.Int64)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// String convert instance to json string
+func (inst *RoomFolderN) String() string {
+	rs, _ := json.Marshal(inst)
+	return string(rs)
+}
+
+type roomFolderScope struct {
+	name  string
+	apply func(builder query.Condition)
+}
+
+var roomFolderGlobalScopes = make([]roomFolderScope, 0)
+var roomFolderLocalScopes = make([]roomFolderScope, 0)
+
+// AddGlobalScopeForRoomFolder assign a global scope to a model
+func AddGlobalScopeForRoomFolder(name string, apply func(builder query.Condition)) {
+	roomFolderGlobalScopes = append(roomFolderGlobalScopes, roomFolderScope{name: name, apply: apply})
+}
+
+// AddLocalScopeForRoomFolder assign a local scope to a model
+func AddLocalScopeForRoomFolder(name string, apply func(builder query.Condition)) {
+	roomFolderLocalScopes = append(roomFolderLocalScopes, roomFolderScope{name: name, apply: apply})
+}
+
+func (m *RoomFolderModel) applyScope() query.Condition {
+	scopeCond := query.ConditionBuilder()
+	for _, g := range roomFolderGlobalScopes {
+		if m.globalScopeEnabled(g.name) {
+			g.apply(scopeCond)
+		}
+	}
+
+	for _, s := range roomFolderLocalScopes {
+		if m.localScopeEnabled(s.name) {
+			s.apply(scopeCond)
+		}
+	}
+
+	return scopeCond
+}
+
+func (m *RoomFolderModel) localScopeEnabled(name string) bool {
+	for _, n := range m.includeLocalScopes {
+		if name == n {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m *RoomFolderModel) globalScopeEnabled(name string) bool {
+	for _, n := range m.excludeGlobalScopes {
+		if name == n {
+			return false
+		}
+	}
+
+	return true
+}
+
+type RoomFolder struct {
+	Id        int64  `json:"id"`
+	UserId    int64  `json:"user_id,omitempty"`
+	Name      string `json:"name"`
+	Sort      int64  `json:"sort"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (w RoomFolder) ToRoomFolderN(allows ...string) RoomFolderN {
+	if len(allows) == 0 {
+		return RoomFolderN{
+
+			Id:        null.IntFrom(int64(w.Id)),
+			UserId:    null.IntFrom(int64(w.UserId)),
+			Name:      null.StringFrom(w.Name),
+			Sort:      null.IntFrom(int64(w.Sort)),
+			CreatedAt: null.TimeFrom(w.CreatedAt),
+			UpdatedAt: null.TimeFrom(w.UpdatedAt),
+		}
+	}
+
+	res := RoomFolderN{}
+	for _, al := range allows {
+		switch strcase.ToSnake(al) {
+
+		case "id":
+			res.Id = null.IntFrom(int64(w.Id))
+		case "user_id":
+			res.UserId = null.IntFrom(int64(w.UserId))
+		case "name":
+			res.Name = null.StringFrom(w.Name)
+		case "sort":
+			res.Sort = null.IntFrom(int64(w.Sort))
+		case "created_at":
+			res.CreatedAt = null.TimeFrom(w.CreatedAt)
+		case "updated_at":
+			res.UpdatedAt = null.TimeFrom(w.UpdatedAt)
+		default:
+		}
+	}
+
+	return res
+}
+
+// As convert object to other type
+// dst must be a pointer to struct
+func (w RoomFolder) As(dst interface{}) error {
+	return query.Copy(w, dst)
+}
+
+func (w *RoomFolderN) ToRoomFolder() RoomFolder {
+	return RoomFolder{
+
+		Id:        w.Id.Int64,
+		UserId:    w.UserId.Int64,
+		Name:      w.Name.String,
+		Sort:      w.Sort.Int64,
+		CreatedAt: w.CreatedAt.Time,
+		UpdatedAt: w.UpdatedAt.Time,
+	}
+}
+
+// RoomFolderModel is a model which encapsulates the operations of the object
+type RoomFolderModel struct {
+	db        *query.DatabaseWrap
+	tableName string
+
+	excludeGlobalScopes []string
+	includeLocalScopes  []string
+
+	query query.SQLBuilder
+}
+
+var roomFolderTableName = "room_folder"
+
+// RoomFolderTable return table name for RoomFolder
+func RoomFolderTable() string {
+	return roomFolderTableName
+}
+
+const (
+	FieldRoomFolderId        = "id"
+	FieldRoomFolderUserId    = "user_id"
+	FieldRoomFolderName      = "name"
+	FieldRoomFolderSort      = "sort"
+	FieldRoomFolderCreatedAt = "created_at"
+	FieldRoomFolderUpdatedAt = "updated_at"
+)
+
+// RoomFolderFields return all fields in RoomFolder model
+func RoomFolderFields() []string {
+	return []string{
+		"id",
+		"user_id",
+		"name",
+		"sort",
+		"created_at",
+		"updated_at",
+	}
+}
+
+func SetRoomFolderTable(tableName string) {
+	roomFolderTableName = tableName
+}
+
+// NewRoomFolderModel create a RoomFolderModel
+func NewRoomFolderModel(db query.Database) *RoomFolderModel {
+	return &RoomFolderModel{
+		db:                  query.NewDatabaseWrap(db),
+		tableName:           roomFolderTableName,
+		excludeGlobalScopes: make([]string, 0),
+		includeLocalScopes:  make([]string, 0),
+		query:               query.Builder(),
+	}
+}
+
+// GetDB return database instance
+func (m *RoomFolderModel) GetDB() query.Database {
+	return m.db.GetDB()
+}
+
+func (m *RoomFolderModel) clone() *RoomFolderModel {
+	return &RoomFolderModel{
+		db:                  m.db,
+		tableName:           m.tableName,
+		excludeGlobalScopes: append([]string{}, m.excludeGlobalScopes...),
+		includeLocalScopes:  append([]string{}, m.includeLocalScopes...),
+		query:               m.query,
+	}
+}
+
+// WithoutGlobalScopes remove a global scope for given query
+func (m *RoomFolderModel) WithoutGlobalScopes(names ...string) *RoomFolderModel {
+	mc := m.clone()
+	mc.excludeGlobalScopes = append(mc.excludeGlobalScopes, names...)
+
+	return mc
+}
+
+// WithLocalScopes add a local scope for given query
+func (m *RoomFolderModel) WithLocalScopes(names ...string) *RoomFolderModel {
+	mc := m.clone()
+	mc.includeLocalScopes = append(mc.includeLocalScopes, names...)
+
+	return mc
+}
+
+// Condition add query builder to model
+func (m *RoomFolderModel) Condition(builder query.SQLBuilder) *RoomFolderModel {
+	mm := m.clone()
+	mm.query = mm.query.Merge(builder)
+
+	return mm
+}
+
+// Find retrieve a model by its primary key
+func (m *RoomFolderModel) Find(ctx context.Context, id int64) (*RoomFolderN, error) {
+	return m.First(ctx, m.query.Where("id", "=", id))
+}
+
+// Exists return whether the records exists for a given query
+func (m *RoomFolderModel) Exists(ctx context.Context, builders ...query.SQLBuilder) (bool, error) {
+	count, err := m.Count(ctx, builders...)
+	return count > 0, err
+}
+
+// Count return model count for a given query
+func (m *RoomFolderModel) Count(ctx context.Context, builders ...query.SQLBuilder) (int64, error) {
+	sqlStr, params := m.query.
+		Merge(builders...).
+		Table(m.tableName).
+		AppendCondition(m.applyScope()).
+		ResolveCount()
+
+	rows, err := m.db.QueryContext(ctx, sqlStr, params...)
+	if err != nil {
+		return 0, err
+	}
+
+	defer rows.Close()
+
+	rows.Next()
+	var res int64
+	if err := rows.Scan(&res); err != nil {
+		return 0, err
+	}
+
+	return res, nil
+}
+
+func (m *RoomFolderModel) Paginate(ctx context.Context, page int64, perPage int64, builders ...query.SQLBuilder) ([]RoomFolderN, query.PaginateMeta, error) {
+	if page <= 0 {
+		page = 1
+	}
+
+	if perPage <= 0 {
+		perPage = 15
+	}
+
+	meta := query.PaginateMeta{
+		PerPage: perPage,
+		Page:    page,
+	}
+
+	count, err := m.Count(ctx, builders...)
+	if err != nil {
+		return nil, meta, err
+	}
+
+	meta.Total = count
+	meta.LastPage = count / perPage
+	if count%perPage != 0 {
+		meta.LastPage += 1
+	}
+
+	res, err := m.Get(ctx, append([]query.SQLBuilder{query.Builder().Limit(perPage).Offset((page - 1) * perPage)}, builders...)...)
+	if err != nil {
+		return res, meta, err
+	}
+
+	return res, meta, nil
+}
+
+// Get retrieve all results for given query
+func (m *RoomFolderModel) Get(ctx context.Context, builders ...query.SQLBuilder) ([]RoomFolderN, error) {
+	b := m.query.Merge(builders...).Table(m.tableName).AppendCondition(m.applyScope())
+	if len(b.GetFields()) == 0 {
+		b = b.Select(
+			"id",
+			"user_id",
+			"name",
+			"sort",
+			"created_at",
+			"updated_at",
+		)
+	}
+
+	fields := b.GetFields()
+	selectFields := make([]query.Expr, 0)
+
+	for _, f := range fields {
+		switch strcase.ToSnake(f.Value) {
+
+		case "id":
+			selectFields = append(selectFields, f)
+		case "user_id":
+			selectFields = append(selectFields, f)
+		case "name":
+			selectFields = append(selectFields, f)
+		case "sort":
+			selectFields = append(selectFields, f)
+		case "created_at":
+			selectFields = append(selectFields, f)
+		case "updated_at":
+			selectFields = append(selectFields, f)
+		}
+	}
+
+	var createScanVar = func(fields []query.Expr) (*RoomFolderN, []interface{}) {
+		var roomFolderVar RoomFolderN
+		scanFields := make([]interface{}, 0)
+
+		for _, f := range fields {
+			switch strcase.ToSnake(f.Value) {
+
+			case "id":
+				scanFields = append(scanFields, &roomFolderVar.Id)
+			case "user_id":
+				scanFields = append(scanFields, &roomFolderVar.UserId)
+			case "name":
+				scanFields = append(scanFields, &roomFolderVar.Name)
+			case "sort":
+				scanFields = append(scanFields, &roomFolderVar.Sort)
+			case "created_at":
+				scanFields = append(scanFields, &roomFolderVar.CreatedAt)
+			case "updated_at":
+				scanFields = append(scanFields, &roomFolderVar.UpdatedAt)
+			}
+		}
+
+		return &roomFolderVar, scanFields
+	}
+
+	sqlStr, params := b.Fields(selectFields...).ResolveQuery()
+
+	rows, err := m.db.QueryContext(ctx, sqlStr, params...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	roomFolders := make([]RoomFolderN, 0)
+	for rows.Next() {
+		roomFolderReal, scanFields := createScanVar(fields)
+		if err := rows.Scan(scanFields...); err != nil {
+			return nil, err
+		}
+
+		roomFolderReal.original = &roomFolderOriginal{}
+		_ = query.Copy(roomFolderReal, roomFolderReal.original)
+
+		roomFolderReal.SetModel(m)
+		roomFolders = append(roomFolders, *roomFolderReal)
+	}
+
+	return roomFolders, nil
+}
+
+// First return first result for given query
+func (m *RoomFolderModel) First(ctx context.Context, builders ...query.SQLBuilder) (*RoomFolderN, error) {
+	res, err := m.Get(ctx, append(builders, query.Builder().Limit(1))...)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res) == 0 {
+		return nil, query.ErrNoResult
+	}
+
+	return &res[0], nil
+}
+
+// Create save a new room_folder to database
+func (m *RoomFolderModel) Create(ctx context.Context, kv query.KV) (int64, error) {
+
+	if _, ok := kv["created_at"]; !ok {
+		kv["created_at"] = time.Now()
+	}
+
+	if _, ok := kv["updated_at"]; !ok {
+		kv["updated_at"] = time.Now()
+	}
+
+	sqlStr, params := m.query.Table(m.tableName).ResolveInsert(kv)
+
+	res, err := m.db.ExecContext(ctx, sqlStr, params...)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.LastInsertId()
+}
+
+// SaveAll save all room_folders to database
+func (m *RoomFolderModel) SaveAll(ctx context.Context, roomFolders []RoomFolderN) ([]int64, error) {
+	ids := make([]int64, 0)
+	for _, roomFolder := range roomFolders {
+		id, err := m.Save(ctx, roomFolder)
+		if err != nil {
+			return ids, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// Save save a room_folder to database
+func (m *RoomFolderModel) Save(ctx context.Context, roomFolder RoomFolderN, onlyFields ...string) (int64, error) {
+	return m.Create(ctx, roomFolder.StaledKV(onlyFields...))
+}
+
+// SaveOrUpdate save a new room_folder or update it when it has a id > 0
+func (m *RoomFolderModel) SaveOrUpdate(ctx context.Context, roomFolder RoomFolderN, onlyFields ...string) (id int64, updated bool, err error) {
+	if roomFolder.Id.Int64 > 0 {
+		_, _err := m.UpdateById(ctx, roomFolder.Id.Int64, roomFolder, onlyFields...)
+		return roomFolder.Id.Int64, true, _err
+	}
+
+	_id, _err := m.Save(ctx, roomFolder, onlyFields...)
+	return _id, false, _err
+}
+
+// UpdateFields update kv for a given query
+func (m *RoomFolderModel) UpdateFields(ctx context.Context, kv query.KV, builders ...query.SQLBuilder) (int64, error) {
+	if len(kv) == 0 {
+		return 0, nil
+	}
+
+	kv["updated_at"] = time.Now()
+
+	sqlStr, params := m.query.Merge(builders...).AppendCondition(m.applyScope()).
+		Table(m.tableName).
+		ResolveUpdate(kv)
+
+	res, err := m.db.ExecContext(ctx, sqlStr, params...)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
+// Update update a model for given query
+func (m *RoomFolderModel) Update(ctx context.Context, builder query.SQLBuilder, roomFolder RoomFolderN, onlyFields ...string) (int64, error) {
+	return m.UpdateFields(ctx, roomFolder.StaledKV(onlyFields...), builder)
+}
+
+// UpdateById update a model by id
+func (m *RoomFolderModel) UpdateById(ctx context.Context, id int64, roomFolder RoomFolderN, onlyFields ...string) (int64, error) {
+	return m.Condition(query.Builder().Where("id", "=", id)).UpdateFields(ctx, roomFolder.StaledKV(onlyFields...))
+}
+
+// Delete remove a model
+func (m *RoomFolderModel) Delete(ctx context.Context, builders ...query.SQLBuilder) (int64, error) {
+
+	sqlStr, params := m.query.Merge(builders...).AppendCondition(m.applyScope()).Table(m.tableName).ResolveDelete()
+
+	res, err := m.db.ExecContext(ctx, sqlStr, params...)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+
+}
+
+// DeleteById remove a model by id
+func (m *RoomFolderModel) DeleteById(ctx context.Context, id int64) (int64, error) {
+	return m.Condition(query.Builder().Where("id", "=", id)).Delete(ctx)
+}
+
+// RoomTagN is a RoomTag object, all fields are nullable
+type RoomTagN struct {
+	original     *roomTagOriginal
+	roomTagModel *RoomTagModel
+
+	Id        null.Int    `json:"id"`
+	UserId    null.Int    `json:"user_id,omitempty"`
+	RoomId    null.Int    `json:"room_id"`
+	Name      null.String `json:"name"`
+	CreatedAt null.Time
+	UpdatedAt null.Time
+}
+
+// As convert object to other type
+// dst must be a pointer to struct
+func (inst *RoomTagN) As(dst interface{}) error {
+	return query.Copy(inst, dst)
+}
+
+// SetModel set model for RoomTag
+func (inst *RoomTagN) SetModel(roomTagModel *RoomTagModel) {
+	inst.roomTagModel = roomTagModel
+}
+
+// roomTagOriginal is an object which stores original RoomTag from database
+type roomTagOriginal struct {
+	Id        null.Int
+	UserId    null.Int
+	RoomId    null.Int
+	Name      null.String
+	CreatedAt null.Time
+	UpdatedAt null.Time
+}
+
+// Staled identify whether the object has been modified
+func (inst *RoomTagN) Staled(onlyFields ...string) bool {
+	if inst.original == nil {
+		inst.original = &roomTagOriginal{}
+	}
+
+	if len(onlyFields) == 0 {
+
+		if inst.Id != inst.original.Id {
+			return true
+		}
+		if inst.UserId != inst.original.UserId {
+			return true
+		}
+		if inst.RoomId != inst.original.RoomId {
+			return true
+		}
+		if inst.Name != inst.original.Name {
+			return true
+		}
+		if inst.CreatedAt != inst.original.CreatedAt {
+			return true
+		}
+		if inst.UpdatedAt != inst.original.UpdatedAt {
+			return true
+		}
+	} else {
+		for _, f := range onlyFields {
+			switch strcase.ToSnake(f) {
+
+			case "id":
+				if inst.Id != inst.original.Id {
+					return true
+				}
+			case "user_id":
+				if inst.UserId != inst.original.UserId {
+					return true
+				}
+			case "room_id":
+				if inst.RoomId != inst.original.RoomId {
+					return true
+				}
+			case "name":
+				if inst.Name != inst.original.Name {
+					return true
+				}
+			case "created_at":
+				if inst.CreatedAt != inst.original.CreatedAt {
+					return true
+				}
+			case "updated_at":
+				if inst.UpdatedAt != inst.original.UpdatedAt {
+					return true
+				}
+			default:
+			}
+		}
+	}
+
+	return false
+}
+
+// StaledKV return all fields has been modified
+func (inst *RoomTagN) StaledKV(onlyFields ...string) query.KV {
+	kv := make(query.KV, 0)
+
+	if inst.original == nil {
+		inst.original = &roomTagOriginal{}
+	}
+
+	if len(onlyFields) == 0 {
+
+		if inst.Id != inst.original.Id {
+			kv["id"] = inst.Id
+		}
+		if inst.UserId != inst.original.UserId {
+			kv["user_id"] = inst.UserId
+		}
+		if inst.RoomId != inst.original.RoomId {
+			kv["room_id"] = inst.RoomId
+		}
+		if inst.Name != inst.original.Name {
+			kv["name"] = inst.Name
+		}
+		if inst.CreatedAt != inst.original.CreatedAt {
+			kv["created_at"] = inst.CreatedAt
+		}
+		if inst.UpdatedAt != inst.original.UpdatedAt {
+			kv["updated_at"] = inst.UpdatedAt
+		}
+	} else {
+		for _, f := range onlyFields {
+			switch strcase.ToSnake(f) {
+
+			case "id":
+				if inst.Id != inst.original.Id {
+					kv["id"] = inst.Id
+				}
+			case "user_id":
+				if inst.UserId != inst.original.UserId {
+					kv["user_id"] = inst.UserId
+				}
+			case "room_id":
+				if inst.RoomId != inst.original.RoomId {
+					kv["room_id"] = inst.RoomId
+				}
+			case "name":
+				if inst.Name != inst.original.Name {
+					kv["name"] = inst.Name
+				}
+			case "created_at":
+				if inst.CreatedAt != inst.original.CreatedAt {
+					kv["created_at"] = inst.CreatedAt
+				}
+			case "updated_at":
+				if inst.UpdatedAt != inst.original.UpdatedAt {
+					kv["updated_at"] = inst.UpdatedAt
+				}
+			default:
+			}
+		}
+	}
+
+	return kv
+}
+
+// Save create a new model or update it
+func (inst *RoomTagN) Save(ctx context.Context, onlyFields ...string) error {
+	if inst.roomTagModel == nil {
+		return query.ErrModelNotSet
+	}
+
+	id, _, err := inst.roomTagModel.SaveOrUpdate(ctx, *inst, onlyFields...)
+	if err != nil {
+		return err
+	}
+
+	inst.Id = null.IntFrom(id)
+	return nil
+}
+
+// Delete remove a room_tag
+func (inst *RoomTagN) Delete(ctx context.Context) error {
+	if inst.roomTagModel == nil {
+		return query.ErrModelNotSet
+	}
+
+	_, err := inst.roomTagModel.DeleteById(ctx, inst.Id.Int64)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// String convert instance to json string
+func (inst *RoomTagN) String() string {
+	rs, _ := json.Marshal(inst)
+	return string(rs)
+}
+
+type roomTagScope struct {
+	name  string
+	apply func(builder query.Condition)
+}
+
+var roomTagGlobalScopes = make([]roomTagScope, 0)
+var roomTagLocalScopes = make([]roomTagScope, 0)
+
+// AddGlobalScopeForRoomTag assign a global scope to a model
+func AddGlobalScopeForRoomTag(name string, apply func(builder query.Condition)) {
+	roomTagGlobalScopes = append(roomTagGlobalScopes, roomTagScope{name: name, apply: apply})
+}
+
+// AddLocalScopeForRoomTag assign a local scope to a model
+func AddLocalScopeForRoomTag(name string, apply func(builder query.Condition)) {
+	roomTagLocalScopes = append(roomTagLocalScopes, roomTagScope{name: name, apply: apply})
+}
+
+func (m *RoomTagModel) applyScope() query.Condition {
+	scopeCond := query.ConditionBuilder()
+	for _, g := range roomTagGlobalScopes {
+		if m.globalScopeEnabled(g.name) {
+			g.apply(scopeCond)
+		}
+	}
+
+	for _, s := range roomTagLocalScopes {
+		if m.localScopeEnabled(s.name) {
+			s.apply(scopeCond)
+		}
+	}
+
+	return scopeCond
+}
+
+func (m *RoomTagModel) localScopeEnabled(name string) bool {
+	for _, n := range m.includeLocalScopes {
+		if name == n {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m *RoomTagModel) globalScopeEnabled(name string) bool {
+	for _, n := range m.excludeGlobalScopes {
+		if name == n {
+			return false
+		}
+	}
+
+	return true
+}
+
+type RoomTag struct {
+	Id        int64  `json:"id"`
+	UserId    int64  `json:"user_id,omitempty"`
+	RoomId    int64  `json:"room_id"`
+	Name      string `json:"name"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (w RoomTag) ToRoomTagN(allows ...string) RoomTagN {
+	if len(allows) == 0 {
+		return RoomTagN{
+
+			Id:        null.IntFrom(int64(w.Id)),
+			UserId:    null.IntFrom(int64(w.UserId)),
+			RoomId:    null.IntFrom(int64(w.RoomId)),
+			Name:      null.StringFrom(w.Name),
+			CreatedAt: null.TimeFrom(w.CreatedAt),
+			UpdatedAt: null.TimeFrom(w.UpdatedAt),
+		}
+	}
+
+	res := RoomTagN{}
+	for _, al := range allows {
+		switch strcase.ToSnake(al) {
+
+		case "id":
+			res.Id = null.IntFrom(int64(w.Id))
+		case "user_id":
+			res.UserId = null.IntFrom(int64(w.UserId))
+		case "room_id":
+			res.RoomId = null.IntFrom(int64(w.RoomId))
+		case "name":
+			res.Name = null.StringFrom(w.Name)
+		case "created_at":
+			res.CreatedAt = null.TimeFrom(w.CreatedAt)
+		case "updated_at":
+			res.UpdatedAt = null.TimeFrom(w.UpdatedAt)
+		default:
+		}
+	}
+
+	return res
+}
+
+// As convert object to other type
+// dst must be a pointer to struct
+func (w RoomTag) As(dst interface{}) error {
+	return query.Copy(w, dst)
+}
+
+func (w *RoomTagN) ToRoomTag() RoomTag {
+	return RoomTag{
+
+		Id:        w.Id.Int64,
+		UserId:    w.UserId.Int64,
+		RoomId:    w.RoomId.Int64,
+		Name:      w.Name.String,
+		CreatedAt: w.CreatedAt.Time,
+		UpdatedAt: w.UpdatedAt.Time,
+	}
+}
+
+// RoomTagModel is a model which encapsulates the operations of the object
+type RoomTagModel struct {
+	db        *query.DatabaseWrap
+	tableName string
+
+	excludeGlobalScopes []string
+	includeLocalScopes  []string
+
+	query query.SQLBuilder
+}
+
+var roomTagTableName = "room_tag"
+
+// RoomTagTable return table name for RoomTag
+func RoomTagTable() string {
+	return roomTagTableName
+}
+
+const (
+	FieldRoomTagId        = "id"
+	FieldRoomTagUserId    = "user_id"
+	FieldRoomTagRoomId    = "room_id"
+	FieldRoomTagName      = "name"
+	FieldRoomTagCreatedAt = "created_at"
+	FieldRoomTagUpdatedAt = "updated_at"
+)
+
+// RoomTagFields return all fields in RoomTag model
+func RoomTagFields() []string {
+	return []string{
+		"id",
+		"user_id",
+		"room_id",
+		"name",
+		"created_at",
+		"updated_at",
+	}
+}
+
+func SetRoomTagTable(tableName string) {
+	roomTagTableName = tableName
+}
+
+// NewRoomTagModel create a RoomTagModel
+func NewRoomTagModel(db query.Database) *RoomTagModel {
+	return &RoomTagModel{
+		db:                  query.NewDatabaseWrap(db),
+		tableName:           roomTagTableName,
+		excludeGlobalScopes: make([]string, 0),
+		includeLocalScopes:  make([]string, 0),
+		query:               query.Builder(),
+	}
+}
+
+// GetDB return database instance
+func (m *RoomTagModel) GetDB() query.Database {
+	return m.db.GetDB()
+}
+
+func (m *RoomTagModel) clone() *RoomTagModel {
+	return &RoomTagModel{
+		db:                  m.db,
+		tableName:           m.tableName,
+		excludeGlobalScopes: append([]string{}, m.excludeGlobalScopes...),
+		includeLocalScopes:  append([]string{}, m.includeLocalScopes...),
+		query:               m.query,
+	}
+}
+
+// WithoutGlobalScopes remove a global scope for given query
+func (m *RoomTagModel) WithoutGlobalScopes(names ...string) *RoomTagModel {
+	mc := m.clone()
+	mc.excludeGlobalScopes = append(mc.excludeGlobalScopes, names...)
+
+	return mc
+}
+
+// WithLocalScopes add a local scope for given query
+func (m *RoomTagModel) WithLocalScopes(names ...string) *RoomTagModel {
+	mc := m.clone()
+	mc.includeLocalScopes = append(mc.includeLocalScopes, names...)
+
+	return mc
+}
+
+// Condition add query builder to model
+func (m *RoomTagModel) Condition(builder query.SQLBuilder) *RoomTagModel {
+	mm := m.clone()
+	mm.query = mm.query.Merge(builder)
+
+	return mm
+}
+
+// Find retrieve a model by its primary key
+func (m *RoomTagModel) Find(ctx context.Context, id int64) (*RoomTagN, error) {
+	return m.First(ctx, m.query.Where("id", "=", id))
+}
+
+// Exists return whether the records exists for a given query
+func (m *RoomTagModel) Exists(ctx context.Context, builders ...query.SQLBuilder) (bool, error) {
+	count, err := m.Count(ctx, builders...)
+	return count > 0, err
+}
+
+// Count return model count for a given query
+func (m *RoomTagModel) Count(ctx context.Context, builders ...query.SQLBuilder) (int64, error) {
+	sqlStr, params := m.query.
+		Merge(builders...).
+		Table(m.tableName).
+		AppendCondition(m.applyScope()).
+		ResolveCount()
+
+	rows, err := m.db.QueryContext(ctx, sqlStr, params...)
+	if err != nil {
+		return 0, err
+	}
+
+	defer rows.Close()
+
+	rows.Next()
+	var res int64
+	if err := rows.Scan(&res); err != nil {
+		return 0, err
+	}
+
+	return res, nil
+}
+
+func (m *RoomTagModel) Paginate(ctx context.Context, page int64, perPage int64, builders ...query.SQLBuilder) ([]RoomTagN, query.PaginateMeta, error) {
+	if page <= 0 {
+		page = 1
+	}
+
+	if perPage <= 0 {
+		perPage = 15
+	}
+
+	meta := query.PaginateMeta{
+		PerPage: perPage,
+		Page:    page,
+	}
+
+	count, err := m.Count(ctx, builders...)
+	if err != nil {
+		return nil, meta, err
+	}
+
+	meta.Total = count
+	meta.LastPage = count / perPage
+	if count%perPage != 0 {
+		meta.LastPage += 1
+	}
+
+	res, err := m.Get(ctx, append([]query.SQLBuilder{query.Builder().Limit(perPage).Offset((page - 1) * perPage)}, builders...)...)
+	if err != nil {
+		return res, meta, err
+	}
+
+	return res, meta, nil
+}
+
+// Get retrieve all results for given query
+func (m *RoomTagModel) Get(ctx context.Context, builders ...query.SQLBuilder) ([]RoomTagN, error) {
+	b := m.query.Merge(builders...).Table(m.tableName).AppendCondition(m.applyScope())
+	if len(b.GetFields()) == 0 {
+		b = b.Select(
+			"id",
+			"user_id",
+			"room_id",
+			"name",
+			"created_at",
+			"updated_at",
+		)
+	}
+
+	fields := b.GetFields()
+	selectFields := make([]query.Expr, 0)
+
+	for _, f := range fields {
+		switch strcase.ToSnake(f.Value) {
+
+		case "id":
+			selectFields = append(selectFields, f)
+		case "user_id":
+			selectFields = append(selectFields, f)
+		case "room_id":
+			selectFields = append(selectFields, f)
+		case "name":
+			selectFields = append(selectFields, f)
+		case "created_at":
+			selectFields = append(selectFields, f)
+		case "updated_at":
+			selectFields = append(selectFields, f)
+		}
+	}
+
+	var createScanVar = func(fields []query.Expr) (*RoomTagN, []interface{}) {
+		var roomTagVar RoomTagN
+		scanFields := make([]interface{}, 0)
+
+		for _, f := range fields {
+			switch strcase.ToSnake(f.Value) {
+
+			case "id":
+				scanFields = append(scanFields, &roomTagVar.Id)
+			case "user_id":
+				scanFields = append(scanFields, &roomTagVar.UserId)
+			case "room_id":
+				scanFields = append(scanFields, &roomTagVar.RoomId)
+			case "name":
+				scanFields = append(scanFields, &roomTagVar.Name)
+			case "created_at":
+				scanFields = append(scanFields, &roomTagVar.CreatedAt)
+			case "updated_at":
+				scanFields = append(scanFields, &roomTagVar.UpdatedAt)
+			}
+		}
+
+		return &roomTagVar, scanFields
+	}
+
+	sqlStr, params := b.Fields(selectFields...).ResolveQuery()
+
+	rows, err := m.db.QueryContext(ctx, sqlStr, params...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	roomTags := make([]RoomTagN, 0)
+	for rows.Next() {
+		roomTagReal, scanFields := createScanVar(fields)
+		if err := rows.Scan(scanFields...); err != nil {
+			return nil, err
+		}
+
+		roomTagReal.original = &roomTagOriginal{}
+		_ = query.Copy(roomTagReal, roomTagReal.original)
+
+		roomTagReal.SetModel(m)
+		roomTags = append(roomTags, *roomTagReal)
+	}
+
+	return roomTags, nil
+}
+
+// First return first result for given query
+func (m *RoomTagModel) First(ctx context.Context, builders ...query.SQLBuilder) (*RoomTagN, error) {
+	res, err := m.Get(ctx, append(builders, query.Builder().Limit(1))...)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res) == 0 {
+		return nil, query.ErrNoResult
+	}
+
+	return &res[0], nil
+}
+
+// Create save a new room_tag to database
+func (m *RoomTagModel) Create(ctx context.Context, kv query.KV) (int64, error) {
+
+	if _, ok := kv["created_at"]; !ok {
+		kv["created_at"] = time.Now()
+	}
+
+	if _, ok := kv["updated_at"]; !ok {
+		kv["updated_at"] = time.Now()
+	}
+
+	sqlStr, params := m.query.Table(m.tableName).ResolveInsert(kv)
+
+	res, err := m.db.ExecContext(ctx, sqlStr, params...)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.LastInsertId()
+}
+
+// SaveAll save all room_tags to database
+func (m *RoomTagModel) SaveAll(ctx context.Context, roomTags []RoomTagN) ([]int64, error) {
+	ids := make([]int64, 0)
+	for _, roomTag := range roomTags {
+		id, err := m.Save(ctx, roomTag)
+		if err != nil {
+			return ids, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// Save save a room_tag to database
+func (m *RoomTagModel) Save(ctx context.Context, roomTag RoomTagN, onlyFields ...string) (int64, error) {
+	return m.Create(ctx, roomTag.StaledKV(onlyFields...))
+}
+
+// SaveOrUpdate save a new room_tag or update it when it has a id > 0
+func (m *RoomTagModel) SaveOrUpdate(ctx context.Context, roomTag RoomTagN, onlyFields ...string) (id int64, updated bool, err error) {
+	if roomTag.Id.Int64 > 0 {
+		_, _err := m.UpdateById(ctx, roomTag.Id.Int64, roomTag, onlyFields...)
+		return roomTag.Id.Int64, true, _err
+	}
+
+	_id, _err := m.Save(ctx, roomTag, onlyFields...)
+	return _id, false, _err
+}
+
+// UpdateFields update kv for a given query
+func (m *RoomTagModel) UpdateFields(ctx context.Context, kv query.KV, builders ...query.SQLBuilder) (int64, error) {
+	if len(kv) == 0 {
+		return 0, nil
+	}
+
+	kv["updated_at"] = time.Now()
+
+	sqlStr, params := m.query.Merge(builders...).AppendCondition(m.applyScope()).
+		Table(m.tableName).
+		ResolveUpdate(kv)
+
+	res, err := m.db.ExecContext(ctx, sqlStr, params...)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
+// Update update a model for given query
+func (m *RoomTagModel) Update(ctx context.Context, builder query.SQLBuilder, roomTag RoomTagN, onlyFields ...string) (int64, error) {
+	return m.UpdateFields(ctx, roomTag.StaledKV(onlyFields...), builder)
+}
+
+// UpdateById update a model by id
+func (m *RoomTagModel) UpdateById(ctx context.Context, id int64, roomTag RoomTagN, onlyFields ...string) (int64, error) {
+	return m.Condition(query.Builder().Where("id", "=", id)).UpdateFields(ctx, roomTag.StaledKV(onlyFields...))
+}
+
+// Delete remove a model
+func (m *RoomTagModel) Delete(ctx context.Context, builders ...query.SQLBuilder) (int64, error) {
+
+	sqlStr, params := m.query.Merge(builders...).AppendCondition(m.applyScope()).Table(m.tableName).ResolveDelete()
+
+	res, err := m.db.ExecContext(ctx, sqlStr, params...)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+
+}
+
+// DeleteById remove a model by id
+func (m *RoomTagModel) DeleteById(ctx context.Context, id int64) (int64, error) {
+	return m.Condition(query.Builder().Where("id", "=", id)).Delete(ctx)
+}
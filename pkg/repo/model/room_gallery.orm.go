@@ -34,6 +34,7 @@ type RoomGalleryN struct {
 	VersionMin  null.String `json:"version_min,omitempty"`
 	VersionMax  null.String `json:"version_max,omitempty"`
 	RoomType    null.String `json:"room_type,omitempty"`
+	PresetsJson null.String `json:"-"`
 	CreatedAt   null.Time
 	UpdatedAt   null.Time
 }
@@ -65,6 +66,7 @@ type roomGalleryOriginal struct {
 	VersionMin  null.String
 	VersionMax  null.String
 	RoomType    null.String
+	PresetsJson null.String
 	CreatedAt   null.Time
 	UpdatedAt   null.Time
 }
@@ -119,6 +121,9 @@ func (inst *RoomGalleryN) Staled(onlyFields ...string) bool {
 		if inst.RoomType != inst.original.RoomType {
 			return true
 		}
+		if inst.PresetsJson != inst.original.PresetsJson {
+			return true
+		}
 		if inst.CreatedAt != inst.original.CreatedAt {
 			return true
 		}
@@ -185,6 +190,10 @@ func (inst *RoomGalleryN) Staled(onlyFields ...string) bool {
 				if inst.RoomType != inst.original.RoomType {
 					return true
 				}
+			case "presets_json":
+				if inst.PresetsJson != inst.original.PresetsJson {
+					return true
+				}
 			case "created_at":
 				if inst.CreatedAt != inst.original.CreatedAt {
 					return true
@@ -253,6 +262,9 @@ func (inst *RoomGalleryN) StaledKV(onlyFields ...string) query.KV {
 		if inst.RoomType != inst.original.RoomType {
 			kv["room_type"] = inst.RoomType
 		}
+		if inst.PresetsJson != inst.original.PresetsJson {
+			kv["presets_json"] = inst.PresetsJson
+		}
 		if inst.CreatedAt != inst.original.CreatedAt {
 			kv["created_at"] = inst.CreatedAt
 		}
@@ -319,6 +331,10 @@ func (inst *RoomGalleryN) StaledKV(onlyFields ...string) query.KV {
 				if inst.RoomType != inst.original.RoomType {
 					kv["room_type"] = inst.RoomType
 				}
+			case "presets_json":
+				if inst.PresetsJson != inst.original.PresetsJson {
+					kv["presets_json"] = inst.PresetsJson
+				}
 			case "created_at":
 				if inst.CreatedAt != inst.original.CreatedAt {
 					kv["created_at"] = inst.CreatedAt
@@ -440,6 +456,7 @@ type RoomGallery struct {
 	VersionMin  string `json:"version_min,omitempty"`
 	VersionMax  string `json:"version_max,omitempty"`
 	RoomType    string `json:"room_type,omitempty"`
+	PresetsJson string `json:"-"`
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 }
@@ -462,6 +479,7 @@ func (w RoomGallery) ToRoomGalleryN(allows ...string) RoomGalleryN {
 			VersionMin:  null.StringFrom(w.VersionMin),
 			VersionMax:  null.StringFrom(w.VersionMax),
 			RoomType:    null.StringFrom(w.RoomType),
+			PresetsJson: null.StringFrom(w.PresetsJson),
 			CreatedAt:   null.TimeFrom(w.CreatedAt),
 			UpdatedAt:   null.TimeFrom(w.UpdatedAt),
 		}
@@ -499,6 +517,8 @@ func (w RoomGallery) ToRoomGalleryN(allows ...string) RoomGalleryN {
 			res.VersionMax = null.StringFrom(w.VersionMax)
 		case "room_type":
 			res.RoomType = null.StringFrom(w.RoomType)
+		case "presets_json":
+			res.PresetsJson = null.StringFrom(w.PresetsJson)
 		case "created_at":
 			res.CreatedAt = null.TimeFrom(w.CreatedAt)
 		case "updated_at":
@@ -533,6 +553,7 @@ func (w *RoomGalleryN) ToRoomGallery() RoomGallery {
 		VersionMin:  w.VersionMin.String,
 		VersionMax:  w.VersionMax.String,
 		RoomType:    w.RoomType.String,
+		PresetsJson: w.PresetsJson.String,
 		CreatedAt:   w.CreatedAt.Time,
 		UpdatedAt:   w.UpdatedAt.Time,
 	}
@@ -571,6 +592,7 @@ const (
 	FieldRoomGalleryVersionMin  = "version_min"
 	FieldRoomGalleryVersionMax  = "version_max"
 	FieldRoomGalleryRoomType    = "room_type"
+	FieldRoomGalleryPresetsJson = "presets_json"
 	FieldRoomGalleryCreatedAt   = "created_at"
 	FieldRoomGalleryUpdatedAt   = "updated_at"
 )
@@ -592,6 +614,7 @@ func RoomGalleryFields() []string {
 		"version_min",
 		"version_max",
 		"room_type",
+		"presets_json",
 		"created_at",
 		"updated_at",
 	}
@@ -738,6 +761,7 @@ func (m *RoomGalleryModel) Get(ctx context.Context, builders ...query.SQLBuilder
 			"version_min",
 			"version_max",
 			"room_type",
+			"presets_json",
 			"created_at",
 			"updated_at",
 		)
@@ -777,6 +801,8 @@ func (m *RoomGalleryModel) Get(ctx context.Context, builders ...query.SQLBuilder
 			selectFields = append(selectFields, f)
 		case "room_type":
 			selectFields = append(selectFields, f)
+		case "presets_json":
+			selectFields = append(selectFields, f)
 		case "created_at":
 			selectFields = append(selectFields, f)
 		case "updated_at":
@@ -819,6 +845,8 @@ func (m *RoomGalleryModel) Get(ctx context.Context, builders ...query.SQLBuilder
 				scanFields = append(scanFields, &roomGalleryVar.VersionMax)
 			case "room_type":
 				scanFields = append(scanFields, &roomGalleryVar.RoomType)
+			case "presets_json":
+				scanFields = append(scanFields, &roomGalleryVar.PresetsJson)
 			case "created_at":
 				scanFields = append(scanFields, &roomGalleryVar.CreatedAt)
 			case "updated_at":
@@ -0,0 +1,691 @@
+package model
+
+// !!! DO NOT EDIT THIS FILE
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/iancoleman/strcase"
+	"github.com/mylxsw/eloquent/query"
+	"gopkg.in/guregu/null.v3"
+	"time"
+)
+
+func init() {
+
+}
+
+// SyncTombstoneN is a SyncTombstone object, all fields are nullable
+type SyncTombstoneN struct {
+	original           *syncTombstoneOriginal
+	syncTombstoneModel *SyncTombstoneModel
+
+	Id         null.Int    `json:"id"`
+	UserId     null.Int    `json:"user_id,omitempty"`
+	EntityType null.String `json:"entity_type,omitempty"`
+	EntityId   null.Int    `json:"entity_id,omitempty"`
+	CreatedAt  null.Time
+	UpdatedAt  null.Time
+}
+
+// As convert object to other type
+// dst must be a pointer to struct
+func (inst *SyncTombstoneN) As(dst interface{}) error {
+	return query.Copy(inst, dst)
+}
+
+// SetModel set model for SyncTombstone
+func (inst *SyncTombstoneN) SetModel(syncTombstoneModel *SyncTombstoneModel) {
+	inst.syncTombstoneModel = syncTombstoneModel
+}
+
+// syncTombstoneOriginal is an object which stores original SyncTombstone from database
+type syncTombstoneOriginal struct {
+	Id         null.Int
+	UserId     null.Int
+	EntityType null.String
+	EntityId   null.Int
+	CreatedAt  null.Time
+	UpdatedAt  null.Time
+}
+
+// Staled identify whether the object has been modified
+func (inst *SyncTombstoneN) Staled(onlyFields ...string) bool {
+	if inst.original == nil {
+		inst.original = &syncTombstoneOriginal{}
+	}
+
+	if len(onlyFields) == 0 {
+
+		if inst.Id != inst.original.Id {
+			return true
+		}
+		if inst.UserId != inst.original.UserId {
+			return true
+		}
+		if inst.EntityType != inst.original.EntityType {
+			return true
+		}
+		if inst.EntityId != inst.original.EntityId {
+			return true
+		}
+		if inst.CreatedAt != inst.original.CreatedAt {
+			return true
+		}
+		if inst.UpdatedAt != inst.original.UpdatedAt {
+			return true
+		}
+	} else {
+		for _, f := range onlyFields {
+			switch strcase.ToSnake(f) {
+
+			case "id":
+				if inst.Id != inst.original.Id {
+					return true
+				}
+			case "user_id":
+				if inst.UserId != inst.original.UserId {
+					return true
+				}
+			case "entity_type":
+				if inst.EntityType != inst.original.EntityType {
+					return true
+				}
+			case "entity_id":
+				if inst.EntityId != inst.original.EntityId {
+					return true
+				}
+			case "created_at":
+				if inst.CreatedAt != inst.original.CreatedAt {
+					return true
+				}
+			case "updated_at":
+				if inst.UpdatedAt != inst.original.UpdatedAt {
+					return true
+				}
+			default:
+			}
+		}
+	}
+
+	return false
+}
+
+// StaledKV return all fields has been modified
+func (inst *SyncTombstoneN) StaledKV(onlyFields ...string) query.KV {
+	kv := make(query.KV, 0)
+
+	if inst.original == nil {
+		inst.original = &syncTombstoneOriginal{}
+	}
+
+	if len(onlyFields) == 0 {
+
+		if inst.Id != inst.original.Id {
+			kv["id"] = inst.Id
+		}
+		if inst.UserId != inst.original.UserId {
+			kv["user_id"] = inst.UserId
+		}
+		if inst.EntityType != inst.original.EntityType {
+			kv["entity_type"] = inst.EntityType
+		}
+		if inst.EntityId != inst.original.EntityId {
+			kv["entity_id"] = inst.EntityId
+		}
+		if inst.CreatedAt != inst.original.CreatedAt {
+			kv["created_at"] = inst.CreatedAt
+		}
+		if inst.UpdatedAt != inst.original.UpdatedAt {
+			kv["updated_at"] = inst.UpdatedAt
+		}
+	} else {
+		for _, f := range onlyFields {
+			switch strcase.ToSnake(f) {
+
+			case "id":
+				if inst.Id != inst.original.Id {
+					kv["id"] = inst.Id
+				}
+			case "user_id":
+				if inst.UserId != inst.original.UserId {
+					kv["user_id"] = inst.UserId
+				}
+			case "entity_type":
+				if inst.EntityType != inst.original.EntityType {
+					kv["entity_type"] = inst.EntityType
+				}
+			case "entity_id":
+				if inst.EntityId != inst.original.EntityId {
+					kv["entity_id"] = inst.EntityId
+				}
+			case "created_at":
+				if inst.CreatedAt != inst.original.CreatedAt {
+					kv["created_at"] = inst.CreatedAt
+				}
+			case "updated_at":
+				if inst.UpdatedAt != inst.original.UpdatedAt {
+					kv["updated_at"] = inst.UpdatedAt
+				}
+			default:
+			}
+		}
+	}
+
+	return kv
+}
+
+// Save create a new model or update it
+func (inst *SyncTombstoneN) Save(ctx context.Context, onlyFields ...string) error {
+	if inst.syncTombstoneModel == nil {
+		return query.ErrModelNotSet
+	}
+
+	id, _, err := inst.syncTombstoneModel.SaveOrUpdate(ctx, *inst, onlyFields...)
+	if err != nil {
+		return err
+	}
+
+	inst.Id = null.IntFrom(id)
+	return nil
+}
+
+// Delete remove a sync_tombstone
+func (inst *SyncTombstoneN) Delete(ctx context.Context) error {
+	if inst.syncTombstoneModel == nil {
+		return query.ErrModelNotSet
+	}
+
+	_, err := inst.syncTombstoneModel.DeleteById(ctx, inst.Id.Int64)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// String convert instance to json string
+func (inst *SyncTombstoneN) String() string {
+	rs, _ := json.Marshal(inst)
+	return string(rs)
+}
+
+type syncTombstoneScope struct {
+	name  string
+	apply func(builder query.Condition)
+}
+
+var syncTombstoneGlobalScopes = make([]syncTombstoneScope, 0)
+var syncTombstoneLocalScopes = make([]syncTombstoneScope, 0)
+
+// AddGlobalScopeForSyncTombstone assign a global scope to a model
+func AddGlobalScopeForSyncTombstone(name string, apply func(builder query.Condition)) {
+	syncTombstoneGlobalScopes = append(syncTombstoneGlobalScopes, syncTombstoneScope{name: name, apply: apply})
+}
+
+// AddLocalScopeForSyncTombstone assign a local scope to a model
+func AddLocalScopeForSyncTombstone(name string, apply func(builder query.Condition)) {
+	syncTombstoneLocalScopes = append(syncTombstoneLocalScopes, syncTombstoneScope{name: name, apply: apply})
+}
+
+func (m *SyncTombstoneModel) applyScope() query.Condition {
+	scopeCond := query.ConditionBuilder()
+	for _, g := range syncTombstoneGlobalScopes {
+		if m.globalScopeEnabled(g.name) {
+			g.apply(scopeCond)
+		}
+	}
+
+	for _, s := range syncTombstoneLocalScopes {
+		if m.localScopeEnabled(s.name) {
+			s.apply(scopeCond)
+		}
+	}
+
+	return scopeCond
+}
+
+func (m *SyncTombstoneModel) localScopeEnabled(name string) bool {
+	for _, n := range m.includeLocalScopes {
+		if name == n {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m *SyncTombstoneModel) globalScopeEnabled(name string) bool {
+	for _, n := range m.excludeGlobalScopes {
+		if name == n {
+			return false
+		}
+	}
+
+	return true
+}
+
+type SyncTombstone struct {
+	Id         int64  `json:"id"`
+	UserId     int64  `json:"user_id,omitempty"`
+	EntityType string `json:"entity_type,omitempty"`
+	EntityId   int64  `json:"entity_id,omitempty"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func (w SyncTombstone) ToSyncTombstoneN(allows ...string) SyncTombstoneN {
+	if len(allows) == 0 {
+		return SyncTombstoneN{
+
+			Id:         null.IntFrom(int64(w.Id)),
+			UserId:     null.IntFrom(int64(w.UserId)),
+			EntityType: null.StringFrom(w.EntityType),
+			EntityId:   null.IntFrom(int64(w.EntityId)),
+			CreatedAt:  null.TimeFrom(w.CreatedAt),
+			UpdatedAt:  null.TimeFrom(w.UpdatedAt),
+		}
+	}
+
+	res := SyncTombstoneN{}
+	for _, al := range allows {
+		switch strcase.ToSnake(al) {
+
+		case "id":
+			res.Id = null.IntFrom(int64(w.Id))
+		case "user_id":
+			res.UserId = null.IntFrom(int64(w.UserId))
+		case "entity_type":
+			res.EntityType = null.StringFrom(w.EntityType)
+		case "entity_id":
+			res.EntityId = null.IntFrom(int64(w.EntityId))
+		case "created_at":
+			res.CreatedAt = null.TimeFrom(w.CreatedAt)
+		case "updated_at":
+			res.UpdatedAt = null.TimeFrom(w.UpdatedAt)
+		default:
+		}
+	}
+
+	return res
+}
+
+// As convert object to other type
+// dst must be a pointer to struct
+func (w SyncTombstone) As(dst interface{}) error {
+	return query.Copy(w, dst)
+}
+
+func (w *SyncTombstoneN) ToSyncTombstone() SyncTombstone {
+	return SyncTombstone{
+
+		Id:         w.Id.Int64,
+		UserId:     w.UserId.Int64,
+		EntityType: w.EntityType.String,
+		EntityId:   w.EntityId.Int64,
+		CreatedAt:  w.CreatedAt.Time,
+		UpdatedAt:  w.UpdatedAt.Time,
+	}
+}
+
+// SyncTombstoneModel is a model which encapsulates the operations of the object
+type SyncTombstoneModel struct {
+	db        *query.DatabaseWrap
+	tableName string
+
+	excludeGlobalScopes []string
+	includeLocalScopes  []string
+
+	query query.SQLBuilder
+}
+
+var syncTombstoneTableName = "sync_tombstone"
+
+// SyncTombstoneTable return table name for SyncTombstone
+func SyncTombstoneTable() string {
+	return syncTombstoneTableName
+}
+
+const (
+	FieldSyncTombstoneId         = "id"
+	FieldSyncTombstoneUserId     = "user_id"
+	FieldSyncTombstoneEntityType = "entity_type"
+	FieldSyncTombstoneEntityId   = "entity_id"
+	FieldSyncTombstoneCreatedAt  = "created_at"
+	FieldSyncTombstoneUpdatedAt  = "updated_at"
+)
+
+// SyncTombstoneFields return all fields in SyncTombstone model
+func SyncTombstoneFields() []string {
+	return []string{
+		"id",
+		"user_id",
+		"entity_type",
+		"entity_id",
+		"created_at",
+		"updated_at",
+	}
+}
+
+func SetSyncTombstoneTable(tableName string) {
+	syncTombstoneTableName = tableName
+}
+
+// NewSyncTombstoneModel create a SyncTombstoneModel
+func NewSyncTombstoneModel(db query.Database) *SyncTombstoneModel {
+	return &SyncTombstoneModel{
+		db:                  query.NewDatabaseWrap(db),
+		tableName:           syncTombstoneTableName,
+		excludeGlobalScopes: make([]string, 0),
+		includeLocalScopes:  make([]string, 0),
+		query:               query.Builder(),
+	}
+}
+
+// GetDB return database instance
+func (m *SyncTombstoneModel) GetDB() query.Database {
+	return m.db.GetDB()
+}
+
+func (m *SyncTombstoneModel) clone() *SyncTombstoneModel {
+	return &SyncTombstoneModel{
+		db:                  m.db,
+		tableName:           m.tableName,
+		excludeGlobalScopes: append([]string{}, m.excludeGlobalScopes...),
+		includeLocalScopes:  append([]string{}, m.includeLocalScopes...),
+		query:               m.query,
+	}
+}
+
+// WithoutGlobalScopes remove a global scope for given query
+func (m *SyncTombstoneModel) WithoutGlobalScopes(names ...string) *SyncTombstoneModel {
+	mc := m.clone()
+	mc.excludeGlobalScopes = append(mc.excludeGlobalScopes, names...)
+
+	return mc
+}
+
+// WithLocalScopes add a local scope for given query
+func (m *SyncTombstoneModel) WithLocalScopes(names ...string) *SyncTombstoneModel {
+	mc := m.clone()
+	mc.includeLocalScopes = append(mc.includeLocalScopes, names...)
+
+	return mc
+}
+
+// Condition add query builder to model
+func (m *SyncTombstoneModel) Condition(builder query.SQLBuilder) *SyncTombstoneModel {
+	mm := m.clone()
+	mm.query = mm.query.Merge(builder)
+
+	return mm
+}
+
+// Find retrieve a model by its primary key
+func (m *SyncTombstoneModel) Find(ctx context.Context, id int64) (*SyncTombstoneN, error) {
+	return m.First(ctx, m.query.Where("id", "=", id))
+}
+
+// Exists return whether the records exists for a given query
+func (m *SyncTombstoneModel) Exists(ctx context.Context, builders ...query.SQLBuilder) (bool, error) {
+	count, err := m.Count(ctx, builders...)
+	return count > 0, err
+}
+
+// Count return model count for a given query
+func (m *SyncTombstoneModel) Count(ctx context.Context, builders ...query.SQLBuilder) (int64, error) {
+	sqlStr, params := m.query.
+		Merge(builders...).
+		Table(m.tableName).
+		AppendCondition(m.applyScope()).
+		ResolveCount()
+
+	rows, err := m.db.QueryContext(ctx, sqlStr, params...)
+	if err != nil {
+		return 0, err
+	}
+
+	defer rows.Close()
+
+	rows.Next()
+	var res int64
+	if err := rows.Scan(&res); err != nil {
+		return 0, err
+	}
+
+	return res, nil
+}
+
+func (m *SyncTombstoneModel) Paginate(ctx context.Context, page int64, perPage int64, builders ...query.SQLBuilder) ([]SyncTombstoneN, query.PaginateMeta, error) {
+	if page <= 0 {
+		page = 1
+	}
+
+	if perPage <= 0 {
+		perPage = 15
+	}
+
+	meta := query.PaginateMeta{
+		PerPage: perPage,
+		Page:    page,
+	}
+
+	count, err := m.Count(ctx, builders...)
+	if err != nil {
+		return nil, meta, err
+	}
+
+	meta.Total = count
+	meta.LastPage = count / perPage
+	if count%perPage != 0 {
+		meta.LastPage += 1
+	}
+
+	res, err := m.Get(ctx, append([]query.SQLBuilder{query.Builder().Limit(perPage).Offset((page - 1) * perPage)}, builders...)...)
+	if err != nil {
+		return res, meta, err
+	}
+
+	return res, meta, nil
+}
+
+// Get retrieve all results for given query
+func (m *SyncTombstoneModel) Get(ctx context.Context, builders ...query.SQLBuilder) ([]SyncTombstoneN, error) {
+	b := m.query.Merge(builders...).Table(m.tableName).AppendCondition(m.applyScope())
+	if len(b.GetFields()) == 0 {
+		b = b.Select(
+			"id",
+			"user_id",
+			"entity_type",
+			"entity_id",
+			"created_at",
+			"updated_at",
+		)
+	}
+
+	fields := b.GetFields()
+	selectFields := make([]query.Expr, 0)
+
+	for _, f := range fields {
+		switch strcase.ToSnake(f.Value) {
+
+		case "id":
+			selectFields = append(selectFields, f)
+		case "user_id":
+			selectFields = append(selectFields, f)
+		case "entity_type":
+			selectFields = append(selectFields, f)
+		case "entity_id":
+			selectFields = append(selectFields, f)
+		case "created_at":
+			selectFields = append(selectFields, f)
+		case "updated_at":
+			selectFields = append(selectFields, f)
+		}
+	}
+
+	var createScanVar = func(fields []query.Expr) (*SyncTombstoneN, []interface{}) {
+		var syncTombstoneVar SyncTombstoneN
+		scanFields := make([]interface{}, 0)
+
+		for _, f := range fields {
+			switch strcase.ToSnake(f.Value) {
+
+			case "id":
+				scanFields = append(scanFields, &syncTombstoneVar.Id)
+			case "user_id":
+				scanFields = append(scanFields, &syncTombstoneVar.UserId)
+			case "entity_type":
+				scanFields = append(scanFields, &syncTombstoneVar.EntityType)
+			case "entity_id":
+				scanFields = append(scanFields, &syncTombstoneVar.EntityId)
+			case "created_at":
+				scanFields = append(scanFields, &syncTombstoneVar.CreatedAt)
+			case "updated_at":
+				scanFields = append(scanFields, &syncTombstoneVar.UpdatedAt)
+			}
+		}
+
+		return &syncTombstoneVar, scanFields
+	}
+
+	sqlStr, params := b.Fields(selectFields...).ResolveQuery()
+
+	rows, err := m.db.QueryContext(ctx, sqlStr, params...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	syncTombstones := make([]SyncTombstoneN, 0)
+	for rows.Next() {
+		syncTombstoneReal, scanFields := createScanVar(fields)
+		if err := rows.Scan(scanFields...); err != nil {
+			return nil, err
+		}
+
+		syncTombstoneReal.original = &syncTombstoneOriginal{}
+		_ = query.Copy(syncTombstoneReal, syncTombstoneReal.original)
+
+		syncTombstoneReal.SetModel(m)
+		syncTombstones = append(syncTombstones, *syncTombstoneReal)
+	}
+
+	return syncTombstones, nil
+}
+
+// First return first result for given query
+func (m *SyncTombstoneModel) First(ctx context.Context, builders ...query.SQLBuilder) (*SyncTombstoneN, error) {
+	res, err := m.Get(ctx, append(builders, query.Builder().Limit(1))...)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res) == 0 {
+		return nil, query.ErrNoResult
+	}
+
+	return &res[0], nil
+}
+
+// Create save a new sync_tombstone to database
+func (m *SyncTombstoneModel) Create(ctx context.Context, kv query.KV) (int64, error) {
+
+	if _, ok := kv["created_at"]; !ok {
+		kv["created_at"] = time.Now()
+	}
+
+	if _, ok := kv["updated_at"]; !ok {
+		kv["updated_at"] = time.Now()
+	}
+
+	sqlStr, params := m.query.Table(m.tableName).ResolveInsert(kv)
+
+	res, err := m.db.ExecContext(ctx, sqlStr, params...)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.LastInsertId()
+}
+
+// SaveAll save all sync_tombstones to database
+func (m *SyncTombstoneModel) SaveAll(ctx context.Context, syncTombstones []SyncTombstoneN) ([]int64, error) {
+	ids := make([]int64, 0)
+	for _, syncTombstone := range syncTombstones {
+		id, err := m.Save(ctx, syncTombstone)
+		if err != nil {
+			return ids, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// Save save a sync_tombstone to database
+func (m *SyncTombstoneModel) Save(ctx context.Context, syncTombstone SyncTombstoneN, onlyFields ...string) (int64, error) {
+	return m.Create(ctx, syncTombstone.StaledKV(onlyFields...))
+}
+
+// SaveOrUpdate save a new sync_tombstone or update it when it has a id > 0
+func (m *SyncTombstoneModel) SaveOrUpdate(ctx context.Context, syncTombstone SyncTombstoneN, onlyFields ...string) (id int64, updated bool, err error) {
+	if syncTombstone.Id.Int64 > 0 {
+		_, _err := m.UpdateById(ctx, syncTombstone.Id.Int64, syncTombstone, onlyFields...)
+		return syncTombstone.Id.Int64, true, _err
+	}
+
+	_id, _err := m.Save(ctx, syncTombstone, onlyFields...)
+	return _id, false, _err
+}
+
+// UpdateFields update kv for a given query
+func (m *SyncTombstoneModel) UpdateFields(ctx context.Context, kv query.KV, builders ...query.SQLBuilder) (int64, error) {
+	if len(kv) == 0 {
+		return 0, nil
+	}
+
+	kv["updated_at"] = time.Now()
+
+	sqlStr, params := m.query.Merge(builders...).AppendCondition(m.applyScope()).
+		Table(m.tableName).
+		ResolveUpdate(kv)
+
+	res, err := m.db.ExecContext(ctx, sqlStr, params...)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
+// Update update a model for given query
+func (m *SyncTombstoneModel) Update(ctx context.Context, builder query.SQLBuilder, syncTombstone SyncTombstoneN, onlyFields ...string) (int64, error) {
+	return m.UpdateFields(ctx, syncTombstone.StaledKV(onlyFields...), builder)
+}
+
+// UpdateById update a model by id
+func (m *SyncTombstoneModel) UpdateById(ctx context.Context, id int64, syncTombstone SyncTombstoneN, onlyFields ...string) (int64, error) {
+	return m.Condition(query.Builder().Where("id", "=", id)).UpdateFields(ctx, syncTombstone.StaledKV(onlyFields...))
+}
+
+// Delete remove a model
+func (m *SyncTombstoneModel) Delete(ctx context.Context, builders ...query.SQLBuilder) (int64, error) {
+
+	sqlStr, params := m.query.Merge(builders...).AppendCondition(m.applyScope()).Table(m.tableName).ResolveDelete()
+
+	res, err := m.db.ExecContext(ctx, sqlStr, params...)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+
+}
+
+// DeleteById remove a model by id
+func (m *SyncTombstoneModel) DeleteById(ctx context.Context, id int64) (int64, error) {
+	return m.Condition(query.Builder().Where("id", "=", id)).Delete(ctx)
+}
@@ -0,0 +1,719 @@
+package model
+
+// !!! DO NOT EDIT THIS FILE
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/iancoleman/strcase"
+	"github.com/mylxsw/eloquent/query"
+	"gopkg.in/guregu/null.v3"
+	"time"
+)
+
+func init() {
+
+}
+
+// UserMemoryN is a UserMemory object, all fields are nullable
+type UserMemoryN struct {
+	original        *userMemoryOriginal
+	userMemoryModel *UserMemoryModel
+
+	Id        null.Int    `json:"id"`
+	UserId    null.Int    `json:"user_id,omitempty"`
+	Content   null.String `json:"content"`
+	RoomId    null.Int    `json:"room_id,omitempty"`
+	Source    null.String `json:"source"`
+	CreatedAt null.Time
+	UpdatedAt null.Time
+}
+
+// As convert object to other type
+// dst must be a pointer to struct
+func (inst *UserMemoryN) As(dst interface{}) error {
+	return query.Copy(inst, dst)
+}
+
+// SetModel set model for UserMemory
+func (inst *UserMemoryN) SetModel(userMemoryModel *UserMemoryModel) {
+	inst.userMemoryModel = userMemoryModel
+}
+
+// userMemoryOriginal is an object which stores original UserMemory from database
+type userMemoryOriginal struct {
+	Id        null.Int
+	UserId    null.Int
+	Content   null.String
+	RoomId    null.Int
+	Source    null.String
+	CreatedAt null.Time
+	UpdatedAt null.Time
+}
+
+// Staled identify whether the object has been modified
+func (inst *UserMemoryN) Staled(onlyFields ...string) bool {
+	if inst.original == nil {
+		inst.original = &userMemoryOriginal{}
+	}
+
+	if len(onlyFields) == 0 {
+
+		if inst.Id != inst.original.Id {
+			return true
+		}
+		if inst.UserId != inst.original.UserId {
+			return true
+		}
+		if inst.Content != inst.original.Content {
+			return true
+		}
+		if inst.RoomId != inst.original.RoomId {
+			return true
+		}
+		if inst.Source != inst.original.Source {
+			return true
+		}
+		if inst.CreatedAt != inst.original.CreatedAt {
+			return true
+		}
+		if inst.UpdatedAt != inst.original.UpdatedAt {
+			return true
+		}
+	} else {
+		for _, f := range onlyFields {
+			switch strcase.ToSnake(f) {
+
+			case "id":
+				if inst.Id != inst.original.Id {
+					return true
+				}
+			case "user_id":
+				if inst.UserId != inst.original.UserId {
+					return true
+				}
+			case "content":
+				if inst.Content != inst.original.Content {
+					return true
+				}
+			case "room_id":
+				if inst.RoomId != inst.original.RoomId {
+					return true
+				}
+			case "source":
+				if inst.Source != inst.original.Source {
+					return true
+				}
+			case "created_at":
+				if inst.CreatedAt != inst.original.CreatedAt {
+					return true
+				}
+			case "updated_at":
+				if inst.UpdatedAt != inst.original.UpdatedAt {
+					return true
+				}
+			default:
+			}
+		}
+	}
+
+	return false
+}
+
+// StaledKV return all fields has been modified
+func (inst *UserMemoryN) StaledKV(onlyFields ...string) query.KV {
+	kv := make(query.KV, 0)
+
+	if inst.original == nil {
+		inst.original = &userMemoryOriginal{}
+	}
+
+	if len(onlyFields) == 0 {
+
+		if inst.Id != inst.original.Id {
+			kv["id"] = inst.Id
+		}
+		if inst.UserId != inst.original.UserId {
+			kv["user_id"] = inst.UserId
+		}
+		if inst.Content != inst.original.Content {
+			kv["content"] = inst.Content
+		}
+		if inst.RoomId != inst.original.RoomId {
+			kv["room_id"] = inst.RoomId
+		}
+		if inst.Source != inst.original.Source {
+			kv["source"] = inst.Source
+		}
+		if inst.CreatedAt != inst.original.CreatedAt {
+			kv["created_at"] = inst.CreatedAt
+		}
+		if inst.UpdatedAt != inst.original.UpdatedAt {
+			kv["updated_at"] = inst.UpdatedAt
+		}
+	} else {
+		for _, f := range onlyFields {
+			switch strcase.ToSnake(f) {
+
+			case "id":
+				if inst.Id != inst.original.Id {
+					kv["id"] = inst.Id
+				}
+			case "user_id":
+				if inst.UserId != inst.original.UserId {
+					kv["user_id"] = inst.UserId
+				}
+			case "content":
+				if inst.Content != inst.original.Content {
+					kv["content"] = inst.Content
+				}
+			case "room_id":
+				if inst.RoomId != inst.original.RoomId {
+					kv["room_id"] = inst.RoomId
+				}
+			case "source":
+				if inst.Source != inst.original.Source {
+					kv["source"] = inst.Source
+				}
+			case "created_at":
+				if inst.CreatedAt != inst.original.CreatedAt {
+					kv["created_at"] = inst.CreatedAt
+				}
+			case "updated_at":
+				if inst.UpdatedAt != inst.original.UpdatedAt {
+					kv["updated_at"] = inst.UpdatedAt
+				}
+			default:
+			}
+		}
+	}
+
+	return kv
+}
+
+// Save create a new model or update it
+func (inst *UserMemoryN) Save(ctx context.Context, onlyFields ...string) error {
+	if inst.userMemoryModel == nil {
+		return query.ErrModelNotSet
+	}
+
+	id, _, err := inst.userMemoryModel.SaveOrUpdate(ctx, *inst, onlyFields...)
+	if err != nil {
+		return err
+	}
+
+	inst.Id = null.IntFrom(id)
+	return nil
+}
+
+// Delete remove a user_memory
+func (inst *UserMemoryN) Delete(ctx context.Context) error {
+	if inst.userMemoryModel == nil {
+		return query.ErrModelNotSet
+	}
+
+	_, err := inst.userMemoryModel.DeleteById(ctx, inst.Id.Int64)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// String convert instance to json string
+func (inst *UserMemoryN) String() string {
+	rs, _ := json.Marshal(inst)
+	return string(rs)
+}
+
+type userMemoryScope struct {
+	name  string
+	apply func(builder query.Condition)
+}
+
+var userMemoryGlobalScopes = make([]userMemoryScope, 0)
+var userMemoryLocalScopes = make([]userMemoryScope, 0)
+
+// AddGlobalScopeForUserMemory assign a global scope to a model
+func AddGlobalScopeForUserMemory(name string, apply func(builder query.Condition)) {
+	userMemoryGlobalScopes = append(userMemoryGlobalScopes, userMemoryScope{name: name, apply: apply})
+}
+
+// AddLocalScopeForUserMemory assign a local scope to a model
+func AddLocalScopeForUserMemory(name string, apply func(builder query.Condition)) {
+	userMemoryLocalScopes = append(userMemoryLocalScopes, userMemoryScope{name: name, apply: apply})
+}
+
+func (m *UserMemoryModel) applyScope() query.Condition {
+	scopeCond := query.ConditionBuilder()
+	for _, g := range userMemoryGlobalScopes {
+		if m.globalScopeEnabled(g.name) {
+			g.apply(scopeCond)
+		}
+	}
+
+	for _, s := range userMemoryLocalScopes {
+		if m.localScopeEnabled(s.name) {
+			s.apply(scopeCond)
+		}
+	}
+
+	return scopeCond
+}
+
+func (m *UserMemoryModel) localScopeEnabled(name string) bool {
+	for _, n := range m.includeLocalScopes {
+		if name == n {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m *UserMemoryModel) globalScopeEnabled(name string) bool {
+	for _, n := range m.excludeGlobalScopes {
+		if name == n {
+			return false
+		}
+	}
+
+	return true
+}
+
+type UserMemory struct {
+	Id        int64  `json:"id"`
+	UserId    int64  `json:"user_id,omitempty"`
+	Content   string `json:"content"`
+	RoomId    int64  `json:"room_id,omitempty"`
+	Source    string `json:"source"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (w UserMemory) ToUserMemoryN(allows ...string) UserMemoryN {
+	if len(allows) == 0 {
+		return UserMemoryN{
+
+			Id:        null.IntFrom(int64(w.Id)),
+			UserId:    null.IntFrom(int64(w.UserId)),
+			Content:   null.StringFrom(w.Content),
+			RoomId:    null.IntFrom(int64(w.RoomId)),
+			Source:    null.StringFrom(w.Source),
+			CreatedAt: null.TimeFrom(w.CreatedAt),
+			UpdatedAt: null.TimeFrom(w.UpdatedAt),
+		}
+	}
+
+	res := UserMemoryN{}
+	for _, al := range allows {
+		switch strcase.ToSnake(al) {
+
+		case "id":
+			res.Id = null.IntFrom(int64(w.Id))
+		case "user_id":
+			res.UserId = null.IntFrom(int64(w.UserId))
+		case "content":
+			res.Content = null.StringFrom(w.Content)
+		case "room_id":
+			res.RoomId = null.IntFrom(int64(w.RoomId))
+		case "source":
+			res.Source = null.StringFrom(w.Source)
+		case "created_at":
+			res.CreatedAt = null.TimeFrom(w.CreatedAt)
+		case "updated_at":
+			res.UpdatedAt = null.TimeFrom(w.UpdatedAt)
+		default:
+		}
+	}
+
+	return res
+}
+
+// As convert object to other type
+// dst must be a pointer to struct
+func (w UserMemory) As(dst interface{}) error {
+	return query.Copy(w, dst)
+}
+
+func (w *UserMemoryN) ToUserMemory() UserMemory {
+	return UserMemory{
+
+		Id:        w.Id.Int64,
+		UserId:    w.UserId.Int64,
+		Content:   w.Content.String,
+		RoomId:    w.RoomId.Int64,
+		Source:    w.Source.String,
+		CreatedAt: w.CreatedAt.Time,
+		UpdatedAt: w.UpdatedAt.Time,
+	}
+}
+
+// UserMemoryModel is a model which encapsulates the operations of the object
+type UserMemoryModel struct {
+	db        *query.DatabaseWrap
+	tableName string
+
+	excludeGlobalScopes []string
+	includeLocalScopes  []string
+
+	query query.SQLBuilder
+}
+
+var userMemoryTableName = "user_memory"
+
+// UserMemoryTable return table name for UserMemory
+func UserMemoryTable() string {
+	return userMemoryTableName
+}
+
+const (
+	FieldUserMemoryId        = "id"
+	FieldUserMemoryUserId    = "user_id"
+	FieldUserMemoryContent   = "content"
+	FieldUserMemoryRoomId    = "room_id"
+	FieldUserMemorySource    = "source"
+	FieldUserMemoryCreatedAt = "created_at"
+	FieldUserMemoryUpdatedAt = "updated_at"
+)
+
+// UserMemoryFields return all fields in UserMemory model
+func UserMemoryFields() []string {
+	return []string{
+		"id",
+		"user_id",
+		"content",
+		"room_id",
+		"source",
+		"created_at",
+		"updated_at",
+	}
+}
+
+func SetUserMemoryTable(tableName string) {
+	userMemoryTableName = tableName
+}
+
+// NewUserMemoryModel create a UserMemoryModel
+func NewUserMemoryModel(db query.Database) *UserMemoryModel {
+	return &UserMemoryModel{
+		db:                  query.NewDatabaseWrap(db),
+		tableName:           userMemoryTableName,
+		excludeGlobalScopes: make([]string, 0),
+		includeLocalScopes:  make([]string, 0),
+		query:               query.Builder(),
+	}
+}
+
+// GetDB return database instance
+func (m *UserMemoryModel) GetDB() query.Database {
+	return m.db.GetDB()
+}
+
+func (m *UserMemoryModel) clone() *UserMemoryModel {
+	return &UserMemoryModel{
+		db:                  m.db,
+		tableName:           m.tableName,
+		excludeGlobalScopes: append([]string{}, m.excludeGlobalScopes...),
+		includeLocalScopes:  append([]string{}, m.includeLocalScopes...),
+		query:               m.query,
+	}
+}
+
+// WithoutGlobalScopes remove a global scope for given query
+func (m *UserMemoryModel) WithoutGlobalScopes(names ...string) *UserMemoryModel {
+	mc := m.clone()
+	mc.excludeGlobalScopes = append(mc.excludeGlobalScopes, names...)
+
+	return mc
+}
+
+// WithLocalScopes add a local scope for given query
+func (m *UserMemoryModel) WithLocalScopes(names ...string) *UserMemoryModel {
+	mc := m.clone()
+	mc.includeLocalScopes = append(mc.includeLocalScopes, names...)
+
+	return mc
+}
+
+// Condition add query builder to model
+func (m *UserMemoryModel) Condition(builder query.SQLBuilder) *UserMemoryModel {
+	mm := m.clone()
+	mm.query = mm.query.Merge(builder)
+
+	return mm
+}
+
+// Find retrieve a model by its primary key
+func (m *UserMemoryModel) Find(ctx context.Context, id int64) (*UserMemoryN, error) {
+	return m.First(ctx, m.query.Where("id", "=", id))
+}
+
+// Exists return whether the records exists for a given query
+func (m *UserMemoryModel) Exists(ctx context.Context, builders ...query.SQLBuilder) (bool, error) {
+	count, err := m.Count(ctx, builders...)
+	return count > 0, err
+}
+
+// Count return model count for a given query
+func (m *UserMemoryModel) Count(ctx context.Context, builders ...query.SQLBuilder) (int64, error) {
+	sqlStr, params := m.query.
+		Merge(builders...).
+		Table(m.tableName).
+		AppendCondition(m.applyScope()).
+		ResolveCount()
+
+	rows, err := m.db.QueryContext(ctx, sqlStr, params...)
+	if err != nil {
+		return 0, err
+	}
+
+	defer rows.Close()
+
+	rows.Next()
+	var res int64
+	if err := rows.Scan(&res); err != nil {
+		return 0, err
+	}
+
+	return res, nil
+}
+
+func (m *UserMemoryModel) Paginate(ctx context.Context, page int64, perPage int64, builders ...query.SQLBuilder) ([]UserMemoryN, query.PaginateMeta, error) {
+	if page <= 0 {
+		page = 1
+	}
+
+	if perPage <= 0 {
+		perPage = 15
+	}
+
+	meta := query.PaginateMeta{
+		PerPage: perPage,
+		Page:    page,
+	}
+
+	count, err := m.Count(ctx, builders...)
+	if err != nil {
+		return nil, meta, err
+	}
+
+	meta.Total = count
+	meta.LastPage = count / perPage
+	if count%perPage != 0 {
+		meta.LastPage += 1
+	}
+
+	res, err := m.Get(ctx, append([]query.SQLBuilder{query.Builder().Limit(perPage).Offset((page - 1) * perPage)}, builders...)...)
+	if err != nil {
+		return res, meta, err
+	}
+
+	return res, meta, nil
+}
+
+// Get retrieve all results for given query
+func (m *UserMemoryModel) Get(ctx context.Context, builders ...query.SQLBuilder) ([]UserMemoryN, error) {
+	b := m.query.Merge(builders...).Table(m.tableName).AppendCondition(m.applyScope())
+	if len(b.GetFields()) == 0 {
+		b = b.Select(
+			"id",
+			"user_id",
+			"content",
+			"room_id",
+			"source",
+			"created_at",
+			"updated_at",
+		)
+	}
+
+	fields := b.GetFields()
+	selectFields := make([]query.Expr, 0)
+
+	for _, f := range fields {
+		switch strcase.ToSnake(f.Value) {
+
+		case "id":
+			selectFields = append(selectFields, f)
+		case "user_id":
+			selectFields = append(selectFields, f)
+		case "content":
+			selectFields = append(selectFields, f)
+		case "room_id":
+			selectFields = append(selectFields, f)
+		case "source":
+			selectFields = append(selectFields, f)
+		case "created_at":
+			selectFields = append(selectFields, f)
+		case "updated_at":
+			selectFields = append(selectFields, f)
+		}
+	}
+
+	var createScanVar = func(fields []query.Expr) (*UserMemoryN, []interface{}) {
+		var userMemoryVar UserMemoryN
+		scanFields := make([]interface{}, 0)
+
+		for _, f := range fields {
+			switch strcase.ToSnake(f.Value) {
+
+			case "id":
+				scanFields = append(scanFields, &userMemoryVar.Id)
+			case "user_id":
+				scanFields = append(scanFields, &userMemoryVar.UserId)
+			case "content":
+				scanFields = append(scanFields, &userMemoryVar.Content)
+			case "room_id":
+				scanFields = append(scanFields, &userMemoryVar.RoomId)
+			case "source":
+				scanFields = append(scanFields, &userMemoryVar.Source)
+			case "created_at":
+				scanFields = append(scanFields, &userMemoryVar.CreatedAt)
+			case "updated_at":
+				scanFields = append(scanFields, &userMemoryVar.UpdatedAt)
+			}
+		}
+
+		return &userMemoryVar, scanFields
+	}
+
+	sqlStr, params := b.Fields(selectFields...).ResolveQuery()
+
+	rows, err := m.db.QueryContext(ctx, sqlStr, params...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	userMemorys := make([]UserMemoryN, 0)
+	for rows.Next() {
+		userMemoryReal, scanFields := createScanVar(fields)
+		if err := rows.Scan(scanFields...); err != nil {
+			return nil, err
+		}
+
+		userMemoryReal.original = &userMemoryOriginal{}
+		_ = query.Copy(userMemoryReal, userMemoryReal.original)
+
+		userMemoryReal.SetModel(m)
+		userMemorys = append(userMemorys, *userMemoryReal)
+	}
+
+	return userMemorys, nil
+}
+
+// First return first result for given query
+func (m *UserMemoryModel) First(ctx context.Context, builders ...query.SQLBuilder) (*UserMemoryN, error) {
+	res, err := m.Get(ctx, append(builders, query.Builder().Limit(1))...)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res) == 0 {
+		return nil, query.ErrNoResult
+	}
+
+	return &res[0], nil
+}
+
+// Create save a new user_memory to database
+func (m *UserMemoryModel) Create(ctx context.Context, kv query.KV) (int64, error) {
+
+	if _, ok := kv["created_at"]; !ok {
+		kv["created_at"] = time.Now()
+	}
+
+	if _, ok := kv["updated_at"]; !ok {
+		kv["updated_at"] = time.Now()
+	}
+
+	sqlStr, params := m.query.Table(m.tableName).ResolveInsert(kv)
+
+	res, err := m.db.ExecContext(ctx, sqlStr, params...)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.LastInsertId()
+}
+
+// SaveAll save all user_memorys to database
+func (m *UserMemoryModel) SaveAll(ctx context.Context, userMemorys []UserMemoryN) ([]int64, error) {
+	ids := make([]int64, 0)
+	for _, userMemory := range userMemorys {
+		id, err := m.Save(ctx, userMemory)
+		if err != nil {
+			return ids, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// Save save a user_memory to database
+func (m *UserMemoryModel) Save(ctx context.Context, userMemory UserMemoryN, onlyFields ...string) (int64, error) {
+	return m.Create(ctx, userMemory.StaledKV(onlyFields...))
+}
+
+// SaveOrUpdate save a new user_memory or update it when it has a id > 0
+func (m *UserMemoryModel) SaveOrUpdate(ctx context.Context, userMemory UserMemoryN, onlyFields ...string) (id int64, updated bool, err error) {
+	if userMemory.Id.Int64 > 0 {
+		_, _err := m.UpdateById(ctx, userMemory.Id.Int64, userMemory, onlyFields...)
+		return userMemory.Id.Int64, true, _err
+	}
+
+	_id, _err := m.Save(ctx, userMemory, onlyFields...)
+	return _id, false, _err
+}
+
+// UpdateFields update kv for a given query
+func (m *UserMemoryModel) UpdateFields(ctx context.Context, kv query.KV, builders ...query.SQLBuilder) (int64, error) {
+	if len(kv) == 0 {
+		return 0, nil
+	}
+
+	kv["updated_at"] = time.Now()
+
+	sqlStr, params := m.query.Merge(builders...).AppendCondition(m.applyScope()).
+		Table(m.tableName).
+		ResolveUpdate(kv)
+
+	res, err := m.db.ExecContext(ctx, sqlStr, params...)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
+// Update update a model for given query
+func (m *UserMemoryModel) Update(ctx context.Context, builder query.SQLBuilder, userMemory UserMemoryN, onlyFields ...string) (int64, error) {
+	return m.UpdateFields(ctx, userMemory.StaledKV(onlyFields...), builder)
+}
+
+// UpdateById update a model by id
+func (m *UserMemoryModel) UpdateById(ctx context.Context, id int64, userMemory UserMemoryN, onlyFields ...string) (int64, error) {
+	return m.Condition(query.Builder().Where("id", "=", id)).UpdateFields(ctx, userMemory.StaledKV(onlyFields...))
+}
+
+// Delete remove a model
+func (m *UserMemoryModel) Delete(ctx context.Context, builders ...query.SQLBuilder) (int64, error) {
+
+	sqlStr, params := m.query.Merge(builders...).AppendCondition(m.applyScope()).Table(m.tableName).ResolveDelete()
+
+	res, err := m.db.ExecContext(ctx, sqlStr, params...)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+
+}
+
+// DeleteById remove a model by id
+func (m *UserMemoryModel) DeleteById(ctx context.Context, id int64) (int64, error) {
+	return m.Condition(query.Builder().Where("id", "=", id)).Delete(ctx)
+}
@@ -0,0 +1,306 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mylxsw/aidea-server/pkg/prompt"
+	"github.com/mylxsw/aidea-server/pkg/repo/model"
+	"github.com/mylxsw/asteria/log"
+	"github.com/mylxsw/eloquent"
+	"github.com/mylxsw/eloquent/query"
+	"github.com/mylxsw/go-utils/array"
+	"github.com/mylxsw/go-utils/ternary"
+)
+
+const (
+	// PromptTemplateMaxCount 每个用户最多可以创建的提示语模板数量
+	PromptTemplateMaxCount = 200
+	// PromptTemplateMaxTags 每个模板最多可以设置的标签数量
+	PromptTemplateMaxTags = 5
+)
+
+const (
+	// PromptTemplatesSortHot 按照复制次数排序（默认）
+	PromptTemplatesSortHot = "hot"
+	// PromptTemplatesSortLatest 按照发布时间排序
+	PromptTemplatesSortLatest = "latest"
+)
+
+var ErrPromptTemplateLimitExceeded = errors.New("prompt template limit exceeded")
+
+// PromptTemplate 用户创建的提示语模板
+type PromptTemplate struct {
+	ID          int64             `json:"id"`
+	UserID      int64             `json:"user_id,omitempty"`
+	Title       string            `json:"title"`
+	Description string            `json:"description,omitempty"`
+	Content     string            `json:"content"`
+	Variables   []prompt.Variable `json:"variables"`
+	Tags        []string          `json:"tags"`
+	Published   bool              `json:"published"`
+	PublishedAt *time.Time        `json:"published_at,omitempty"`
+	CopyCount   int64             `json:"copy_count"`
+	SourceID    int64             `json:"source_id,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+// Render 使用变量取值渲染模板
+func (t PromptTemplate) Render(values map[string]string) (string, error) {
+	return prompt.Render(t.Content, t.Variables, values)
+}
+
+func createPromptTemplateFromModel(item model.PromptTemplateN) PromptTemplate {
+	ret := PromptTemplate{
+		ID:          item.Id.ValueOrZero(),
+		UserID:      item.UserId.ValueOrZero(),
+		Title:       item.Title.ValueOrZero(),
+		Description: item.Description.ValueOrZero(),
+		Content:     item.Content.ValueOrZero(),
+		Variables:   make([]prompt.Variable, 0),
+		Tags:        make([]string, 0),
+		Published:   item.Published.ValueOrZero() == 1,
+		CopyCount:   item.CopyCount.ValueOrZero(),
+		SourceID:    item.SourceId.ValueOrZero(),
+		CreatedAt:   item.CreatedAt.ValueOrZero(),
+		UpdatedAt:   item.UpdatedAt.ValueOrZero(),
+	}
+
+	if item.PublishedAt.Valid {
+		ret.PublishedAt = &item.PublishedAt.Time
+	}
+
+	if vars := item.Variables.ValueOrZero(); vars != "" {
+		if err := json.Unmarshal([]byte(vars), &ret.Variables); err != nil {
+			log.F(log.M{"template_id": ret.ID}).Errorf("unmarshal prompt template variables failed: %v", err)
+		}
+	}
+
+	if tags := item.Tags.ValueOrZero(); tags != "" {
+		if err := json.Unmarshal([]byte(tags), &ret.Tags); err != nil {
+			log.F(log.M{"template_id": ret.ID}).Errorf("unmarshal prompt template tags failed: %v", err)
+		}
+	}
+
+	return ret
+}
+
+// PromptTemplateInput 创建或者更新模板时提交的内容
+type PromptTemplateInput struct {
+	Title       string            `json:"title"`
+	Description string            `json:"description,omitempty"`
+	Content     string            `json:"content"`
+	Variables   []prompt.Variable `json:"variables,omitempty"`
+	Tags        []string          `json:"tags,omitempty"`
+}
+
+func (in PromptTemplateInput) kv() query.KV {
+	vars, _ := json.Marshal(ternary.If(in.Variables != nil, in.Variables, []prompt.Variable{}))
+	tags, _ := json.Marshal(ternary.If(in.Tags != nil, in.Tags, []string{}))
+
+	return query.KV{
+		model.FieldPromptTemplateTitle:       in.Title,
+		model.FieldPromptTemplateDescription: in.Description,
+		model.FieldPromptTemplateContent:     in.Content,
+		model.FieldPromptTemplateVariables:   string(vars),
+		model.FieldPromptTemplateTags:        string(tags),
+	}
+}
+
+// PromptTemplateFilter 公共模板库的过滤以及排序条件
+type PromptTemplateFilter struct {
+	Tag     string
+	Keyword string
+	// Sort 排序方式：hot/latest
+	Sort string
+}
+
+type PromptTemplateRepo struct {
+	db *sql.DB
+}
+
+func NewPromptTemplateRepo(db *sql.DB) *PromptTemplateRepo {
+	return &PromptTemplateRepo{db: db}
+}
+
+// Templates 查询用户自己的模板列表，tag 不为空时只返回包含该标签的模板
+func (r *PromptTemplateRepo) Templates(ctx context.Context, userID int64, tag string) ([]PromptTemplate, error) {
+	q := query.Builder().
+		Where(model.FieldPromptTemplateUserId, userID).
+		OrderBy(model.FieldPromptTemplateUpdatedAt, "DESC").
+		Limit(PromptTemplateMaxCount)
+	if tag != "" {
+		q = q.WhereRaw("JSON_CONTAINS(tags, JSON_QUOTE(?))", tag)
+	}
+
+	items, err := model.NewPromptTemplateModel(r.db).Get(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("query prompt templates failed: %w", err)
+	}
+
+	return array.Map(items, func(item model.PromptTemplateN, _ int) PromptTemplate { return createPromptTemplateFromModel(item) }), nil
+}
+
+// Template 查询用户自己的模板
+func (r *PromptTemplateRepo) Template(ctx context.Context, userID, id int64) (*PromptTemplate, error) {
+	return r.first(ctx, query.Builder().
+		Where(model.FieldPromptTemplateUserId, userID).
+		Where(model.FieldPromptTemplateId, id))
+}
+
+// PublishedTemplate 查询已发布到公共模板库的模板
+func (r *PromptTemplateRepo) PublishedTemplate(ctx context.Context, id int64) (*PromptTemplate, error) {
+	return r.first(ctx, query.Builder().
+		Where(model.FieldPromptTemplatePublished, 1).
+		Where(model.FieldPromptTemplateId, id))
+}
+
+// UsableTemplate 查询用户可以使用的模板：自己的模板或者已发布的模板
+func (r *PromptTemplateRepo) UsableTemplate(ctx context.Context, userID, id int64) (*PromptTemplate, error) {
+	return r.first(ctx, query.Builder().
+		Where(model.FieldPromptTemplateId, id).
+		WhereRaw("(user_id = ? OR published = 1)", userID))
+}
+
+func (r *PromptTemplateRepo) first(ctx context.Context, q query.SQLBuilder) (*PromptTemplate, error) {
+	item, err := model.NewPromptTemplateModel(r.db).First(ctx, q)
+	if err != nil {
+		if errors.Is(err, query.ErrNoResult) {
+			return nil, ErrNotFound
+		}
+
+		return nil, err
+	}
+
+	ret := createPromptTemplateFromModel(*item)
+	return &ret, nil
+}
+
+// Create 创建模板，数量达到上限时返回 ErrPromptTemplateLimitExceeded
+func (r *PromptTemplateRepo) Create(ctx context.Context, userID int64, in PromptTemplateInput) (int64, error) {
+	return r.create(ctx, r.db, userID, in, 0)
+}
+
+func (r *PromptTemplateRepo) create(ctx context.Context, db query.Database, userID int64, in PromptTemplateInput, sourceID int64) (int64, error) {
+	count, err := model.NewPromptTemplateModel(db).Count(ctx, query.Builder().Where(model.FieldPromptTemplateUserId, userID))
+	if err != nil {
+		return 0, fmt.Errorf("query prompt templates failed: %w", err)
+	}
+
+	if count >= PromptTemplateMaxCount {
+		return 0, ErrPromptTemplateLimitExceeded
+	}
+
+	kv := in.kv()
+	kv[model.FieldPromptTemplateUserId] = userID
+	kv[model.FieldPromptTemplateSourceId] = sourceID
+
+	return model.NewPromptTemplateModel(db).Create(ctx, kv)
+}
+
+// Update 更新模板内容
+func (r *PromptTemplateRepo) Update(ctx context.Context, userID, id int64, in PromptTemplateInput) error {
+	_, err := model.NewPromptTemplateModel(r.db).UpdateFields(ctx, in.kv(), query.Builder().
+		Where(model.FieldPromptTemplateUserId, userID).
+		Where(model.FieldPromptTemplateId, id))
+
+	return err
+}
+
+// Delete 删除模板
+func (r *PromptTemplateRepo) Delete(ctx context.Context, userID, id int64) error {
+	_, err := model.NewPromptTemplateModel(r.db).Delete(ctx, query.Builder().
+		Where(model.FieldPromptTemplateUserId, userID).
+		Where(model.FieldPromptTemplateId, id))
+
+	return err
+}
+
+// Publish 发布模板到公共模板库，或者从公共模板库中撤下
+func (r *PromptTemplateRepo) Publish(ctx context.Context, userID, id int64, published bool) error {
+	kv := query.KV{model.FieldPromptTemplatePublished: 0}
+	if published {
+		kv = query.KV{
+			model.FieldPromptTemplatePublished:   1,
+			model.FieldPromptTemplatePublishedAt: time.Now(),
+		}
+	}
+
+	_, err := model.NewPromptTemplateModel(r.db).UpdateFields(ctx, kv, query.Builder().
+		Where(model.FieldPromptTemplateUserId, userID).
+		Where(model.FieldPromptTemplateId, id))
+
+	return err
+}
+
+// PublishedTemplates 分页查询公共模板库
+func (r *PromptTemplateRepo) PublishedTemplates(ctx context.Context, filter PromptTemplateFilter, page, perPage int64) ([]PromptTemplate, query.PaginateMeta, error) {
+	q := query.Builder().Where(model.FieldPromptTemplatePublished, 1)
+	if filter.Tag != "" {
+		q = q.WhereRaw("JSON_CONTAINS(tags, JSON_QUOTE(?))", filter.Tag)
+	}
+
+	if filter.Keyword != "" {
+		keyword := "%" + filter.Keyword + "%"
+		q = q.WhereRaw("(title LIKE ? OR description LIKE ?)", keyword, keyword)
+	}
+
+	if filter.Sort == PromptTemplatesSortLatest {
+		q = q.OrderBy(model.FieldPromptTemplatePublishedAt, "DESC")
+	} else {
+		q = q.OrderBy(model.FieldPromptTemplateCopyCount, "DESC")
+	}
+
+	items, meta, err := model.NewPromptTemplateModel(r.db).Paginate(ctx, page, perPage, q.OrderBy(model.FieldPromptTemplateId, "DESC"))
+	if err != nil {
+		return nil, meta, fmt.Errorf("query published prompt templates failed: %w", err)
+	}
+
+	return array.Map(items, func(item model.PromptTemplateN, _ int) PromptTemplate {
+		ret := createPromptTemplateFromModel(item)
+		// 公共模板库中不暴露作者信息
+		ret.UserID = 0
+		return ret
+	}), meta, nil
+}
+
+// Copy 复制公共模板库中的模板到用户自己的模板列表，同时增加原模板的复制次数
+func (r *PromptTemplateRepo) Copy(ctx context.Context, userID, id int64) (newID int64, err error) {
+	err = eloquent.Transaction(r.db, func(tx query.Database) error {
+		source, err := model.NewPromptTemplateModel(tx).First(ctx, query.Builder().
+			Where(model.FieldPromptTemplatePublished, 1).
+			Where(model.FieldPromptTemplateId, id))
+		if err != nil {
+			if errors.Is(err, query.ErrNoResult) {
+				return ErrNotFound
+			}
+
+			return err
+		}
+
+		tpl := createPromptTemplateFromModel(*source)
+		newID, err = r.create(ctx, tx, userID, PromptTemplateInput{
+			Title:       tpl.Title,
+			Description: tpl.Description,
+			Content:     tpl.Content,
+			Variables:   tpl.Variables,
+			Tags:        tpl.Tags,
+		}, id)
+		if err != nil {
+			return err
+		}
+
+		_, err = model.NewPromptTemplateModel(tx).UpdateFields(ctx, query.KV{
+			model.FieldPromptTemplateCopyCount: query.Raw("IFNULL(copy_count, 0) + 1"),
+		}, query.Builder().Where(model.FieldPromptTemplateId, id))
+
+		return err
+	})
+
+	return
+}
@@ -47,6 +47,12 @@ func (Provider) Register(binder infra.Binder) {
 	binder.MustSingleton(NewNotificationRepo)
 	binder.MustSingleton(NewModelRepo)
 	binder.MustSingleton(NewSettingRepo)
+	binder.MustSingleton(NewSyncRepo)
+	binder.MustSingleton(NewMemoryRepo)
+	binder.MustSingleton(NewShareRepo)
+	binder.MustSingleton(NewPromptTemplateRepo)
+	binder.MustSingleton(NewMessageFeedbackRepo)
+	binder.MustSingleton(NewGalleryRepo)
 
 	// MySQL 数据库连接
 	binder.MustSingleton(func(conf *config.Config) (*sql.DB, error) {
@@ -93,20 +99,26 @@ func (Provider) Boot(resolver infra.Resolver) {
 }
 
 type Repository struct {
-	Cache        *CacheRepo        `autowire:"@"`
-	Quota        *QuotaRepo        `autowire:"@"`
-	Queue        *QueueRepo        `autowire:"@"`
-	User         *UserRepo         `autowire:"@"`
-	Event        *EventRepo        `autowire:"@"`
-	Payment      *PaymentRepo      `autowire:"@"`
-	Room         *RoomRepo         `autowire:"@"`
-	Creative     *CreativeRepo     `autowire:"@"`
-	Message      *MessageRepo      `autowire:"@"`
-	Prompt       *PromptRepo       `autowire:"@"`
-	ChatGroup    *ChatGroupRepo    `autowire:"@"`
-	FileStorage  *FileStorageRepo  `autowire:"@"`
-	Notification *NotificationRepo `autowire:"@"`
-	Article      *ArticleRepo      `autowire:"@"`
-	Model        *ModelRepo        `autowire:"@"`
-	Setting      *SettingRepo      `autowire:"@"`
+	Cache          *CacheRepo           `autowire:"@"`
+	Quota          *QuotaRepo           `autowire:"@"`
+	Queue          *QueueRepo           `autowire:"@"`
+	User           *UserRepo            `autowire:"@"`
+	Event          *EventRepo           `autowire:"@"`
+	Payment        *PaymentRepo         `autowire:"@"`
+	Room           *RoomRepo            `autowire:"@"`
+	Creative       *CreativeRepo        `autowire:"@"`
+	Message        *MessageRepo         `autowire:"@"`
+	Prompt         *PromptRepo          `autowire:"@"`
+	ChatGroup      *ChatGroupRepo       `autowire:"@"`
+	FileStorage    *FileStorageRepo     `autowire:"@"`
+	Notification   *NotificationRepo    `autowire:"@"`
+	Article        *ArticleRepo         `autowire:"@"`
+	Model          *ModelRepo           `autowire:"@"`
+	Setting        *SettingRepo         `autowire:"@"`
+	Sync           *SyncRepo            `autowire:"@"`
+	Memory         *MemoryRepo          `autowire:"@"`
+	Share          *ShareRepo           `autowire:"@"`
+	PromptTemplate *PromptTemplateRepo  `autowire:"@"`
+	Feedback       *MessageFeedbackRepo `autowire:"@"`
+	Gallery        *GalleryRepo         `autowire:"@"`
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"github.com/mylxsw/aidea-server/pkg/misc"
 	model2 "github.com/mylxsw/aidea-server/pkg/repo/model"
 	"time"
@@ -25,12 +26,29 @@ const (
 type QueueRepo struct {
 	db   *sql.DB
 	conf *config.Config
+
+	statusUpdateCallback func(task model2.QueueTasks)
 }
 
 func NewQueueRepo(db *sql.DB, conf *config.Config) *QueueRepo {
 	return &QueueRepo{db: db, conf: conf}
 }
 
+// RegisterStatusUpdateCallback 注册任务状态变更回调，任务创建以及状态更新后触发
+func (repo *QueueRepo) RegisterStatusUpdateCallback(callback func(task model2.QueueTasks)) {
+	if repo.statusUpdateCallback != nil {
+		panic(errors.New("queue status update callback already registered"))
+	}
+
+	repo.statusUpdateCallback = callback
+}
+
+func (repo *QueueRepo) notifyStatusUpdate(task model2.QueueTasks) {
+	if repo.statusUpdateCallback != nil {
+		repo.statusUpdateCallback(task)
+	}
+}
+
 func (repo *QueueRepo) Add(ctx context.Context, uid int64, taskID, taskType, queueName string, title string, payload []byte) error {
 	_, err := model2.NewQueueTasksModel(repo.db).Create(ctx, query.KV{
 		model2.FieldQueueTasksTitle:     misc.SubString(title, 70),
@@ -41,8 +59,19 @@ func (repo *QueueRepo) Add(ctx context.Context, uid int64, taskID, taskType, que
 		model2.FieldQueueTasksStatus:    QueueTaskStatusPending,
 		model2.FieldQueueTasksPayload:   null.StringFrom(string(payload)),
 	})
+	if err != nil {
+		return err
+	}
+
+	repo.notifyStatusUpdate(model2.QueueTasks{
+		Uid:       uid,
+		TaskId:    taskID,
+		TaskType:  taskType,
+		QueueName: queueName,
+		Status:    string(QueueTaskStatusPending),
+	})
 
-	return err
+	return nil
 }
 
 func (repo *QueueRepo) Update(ctx context.Context, taskID string, status QueueTaskStatus, result any) error {
@@ -61,7 +90,12 @@ func (repo *QueueRepo) Update(ctx context.Context, taskID string, status QueueTa
 		task.Result = null.StringFrom(string(data))
 	}
 
-	return task.Save(ctx, model2.FieldQueueTasksStatus, model2.FieldQueueTasksResult)
+	if err := task.Save(ctx, model2.FieldQueueTasksStatus, model2.FieldQueueTasksResult); err != nil {
+		return err
+	}
+
+	repo.notifyStatusUpdate(task.ToQueueTasks())
+	return nil
 }
 
 func (repo *QueueRepo) Tasks(ctx context.Context, userID int64, taskType string) ([]model2.QueueTasks, error) {
@@ -123,6 +157,7 @@ const (
 	PendingTaskStatusSuccess    PendingTaskStatus = 2
 	PendingTaskStatusFailed     PendingTaskStatus = 3
 	PendingTaskStatusTimeout    PendingTaskStatus = 4
+	PendingTaskStatusCancelled  PendingTaskStatus = 5
 )
 
 func (repo *QueueRepo) CreatePendingTask(ctx context.Context, task *PendingTask) error {
@@ -187,12 +222,46 @@ func (repo *QueueRepo) UpdatePendingTask(ctx context.Context, id int64, task *Pe
 		data.ExecuteTimes = null.IntFrom(task.ExecuteTimes)
 	}
 
-	q := query.Builder().Where(model2.FieldQueueTasksPendingId, id)
+	// 已经取消的任务不再更新状态
+	q := query.Builder().Where(model2.FieldQueueTasksPendingId, id).
+		Where(model2.FieldQueueTasksPendingStatus, "!=", PendingTaskStatusCancelled)
 	_, err := model2.NewQueueTasksPendingModel(repo.db).Update(ctx, q, data)
 
 	return err
 }
 
+// CancelPendingTask 取消处理中的异步任务，返回被取消的任务，任务不存在或者已经结束时返回 ErrNotFound
+func (repo *QueueRepo) CancelPendingTask(ctx context.Context, taskID string) (*model2.QueueTasksPending, error) {
+	q := query.Builder().
+		Where(model2.FieldQueueTasksPendingTaskId, taskID).
+		Where(model2.FieldQueueTasksPendingStatus, PendingTaskStatusProcessing)
+
+	task, err := model2.NewQueueTasksPendingModel(repo.db).First(ctx, q)
+	if err != nil {
+		if errors.Is(err, query.ErrNoResult) {
+			return nil, ErrNotFound
+		}
+
+		return nil, err
+	}
+
+	affected, err := model2.NewQueueTasksPendingModel(repo.db).Update(
+		ctx,
+		q.Where(model2.FieldQueueTasksPendingId, task.Id.ValueOrZero()),
+		model2.QueueTasksPendingN{Status: null.IntFrom(int64(PendingTaskStatusCancelled))},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if affected == 0 {
+		return nil, ErrNotFound
+	}
+
+	res := task.ToQueueTasksPending()
+	return &res, nil
+}
+
 // RemovePendingTasks 删除已经执行成功的任务
 func (repo *QueueRepo) RemovePendingTasks(ctx context.Context, before time.Time) error {
 	q := query.Builder().
@@ -0,0 +1,335 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mylxsw/aidea-server/pkg/repo/model"
+	"github.com/mylxsw/eloquent"
+	"github.com/mylxsw/eloquent/query"
+	"github.com/mylxsw/go-utils/array"
+	"gopkg.in/guregu/null.v3"
+)
+
+const (
+	// RoomsSortActive 按照优先级以及最后活跃时间排序（默认）
+	RoomsSortActive = "active"
+	// RoomsSortCreated 按照创建时间排序，最新创建的在前
+	RoomsSortCreated = "created"
+	// RoomsSortName 按照名称排序
+	RoomsSortName = "name"
+)
+
+const (
+	// RoomsArchivedExclude 不包含已归档的房间（默认）
+	RoomsArchivedExclude = ""
+	// RoomsArchivedOnly 只返回已归档的房间
+	RoomsArchivedOnly = "only"
+	// RoomsArchivedAll 包含已归档的房间
+	RoomsArchivedAll = "all"
+)
+
+const (
+	// RoomFolderMaxCount 每个用户最多可以创建的文件夹数量
+	RoomFolderMaxCount = 50
+	// RoomTagMaxCount 每个房间最多可以设置的标签数量
+	RoomTagMaxCount = 10
+)
+
+var ErrRoomFolderLimitExceeded = errors.New("room folder limit exceeded")
+
+// RoomFilter 房间列表的过滤以及排序条件
+type RoomFilter struct {
+	// FolderID 文件夹 ID，为 nil 时不限制，0 表示未分类的房间
+	FolderID *int64
+	Tag      string
+	Keyword  string
+	// Archived 归档状态过滤：""/only/all
+	Archived string
+	// PinnedOnly 是否只返回置顶的房间
+	PinnedOnly bool
+	// Sort 排序方式：active/created/name，置顶的房间总是排在最前面
+	Sort string
+}
+
+// FilterRooms 按照过滤条件查询房间列表
+func (r *RoomRepo) FilterRooms(ctx context.Context, userID int64, roomTypes []int, filter RoomFilter, limit int64) ([]Room, error) {
+	q := query.Builder().
+		Where(model.FieldRoomsUserId, userID).
+		WhereIn(model.FieldRoomsRoomType, roomTypes).
+		OrderBy(model.FieldRoomsPinned, "DESC")
+
+	if filter.FolderID != nil {
+		if *filter.FolderID == 0 {
+			// 兼容未回填默认值的历史数据
+			q = q.WhereRaw("(folder_id IS NULL OR folder_id = 0)")
+		} else {
+			q = q.Where(model.FieldRoomsFolderId, *filter.FolderID)
+		}
+	}
+
+	switch filter.Archived {
+	case RoomsArchivedOnly:
+		q = q.Where(model.FieldRoomsArchived, 1)
+	case RoomsArchivedAll:
+	default:
+		q = q.WhereRaw("(archived IS NULL OR archived = 0)")
+	}
+
+	if filter.PinnedOnly {
+		q = q.Where(model.FieldRoomsPinned, 1)
+	}
+
+	if filter.Keyword != "" {
+		q = q.Where(model.FieldRoomsName, "LIKE", "%"+escapeLike(filter.Keyword)+"%")
+	}
+
+	if filter.Tag != "" {
+		q = q.WhereRaw(
+			"id IN (SELECT room_id FROM "+model.RoomTagTable()+" WHERE user_id = ? AND name = ?)",
+			userID, filter.Tag,
+		)
+	}
+
+	switch filter.Sort {
+	case RoomsSortCreated:
+		q = q.OrderBy(model.FieldRoomsId, "DESC")
+	case RoomsSortName:
+		q = q.OrderBy(model.FieldRoomsName, "ASC")
+	default:
+		q = q.OrderBy(model.FieldRoomsPriority, "DESC").
+			OrderBy(model.FieldRoomsLastActiveTime, "DESC")
+	}
+
+	rooms, err := model.NewRoomsModel(r.db).Get(ctx, q.Limit(limit))
+	if err != nil {
+		return nil, err
+	}
+
+	ret, err := r.fillRoomMembers(ctx, userID, rooms)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.fillRoomTags(ctx, userID, ret)
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// fillRoomTags 查询房间的标签
+func (r *RoomRepo) fillRoomTags(ctx context.Context, userID int64, rooms []Room) ([]Room, error) {
+	if len(rooms) == 0 {
+		return rooms, nil
+	}
+
+	tags, err := model.NewRoomTagModel(r.db).Get(ctx, query.Builder().
+		Where(model.FieldRoomTagUserId, userID).
+		WhereIn(model.FieldRoomTagRoomId, array.Map(rooms, func(room Room, _ int) int64 { return room.Id })).
+		OrderBy(model.FieldRoomTagId, "ASC"))
+	if err != nil {
+		return nil, fmt.Errorf("query room tags failed: %w", err)
+	}
+
+	roomTags := make(map[int64][]string)
+	for _, tag := range tags {
+		roomTags[tag.RoomId.ValueOrZero()] = append(roomTags[tag.RoomId.ValueOrZero()], tag.Name.ValueOrZero())
+	}
+
+	for i := range rooms {
+		rooms[i].Tags = roomTags[rooms[i].Id]
+	}
+
+	return rooms, nil
+}
+
+// Tags 返回用户使用过的所有房间标签
+func (r *RoomRepo) Tags(ctx context.Context, userID int64) ([]string, error) {
+	tags, err := model.NewRoomTagModel(r.db).Get(ctx, query.Builder().
+		Where(model.FieldRoomTagUserId, userID).
+		OrderBy(model.FieldRoomTagId, "ASC"))
+	if err != nil {
+		return nil, fmt.Errorf("query room tags failed: %w", err)
+	}
+
+	return array.Uniq(array.Map(tags, func(tag model.RoomTagN, _ int) string { return tag.Name.ValueOrZero() })), nil
+}
+
+// SetTags 设置房间的标签，会覆盖房间原有的标签
+func (r *RoomRepo) SetTags(ctx context.Context, userID, roomID int64, tags []string) error {
+	return eloquent.Transaction(r.db, func(tx query.Database) error {
+		if _, err := model.NewRoomTagModel(tx).Delete(ctx, query.Builder().
+			Where(model.FieldRoomTagUserId, userID).
+			Where(model.FieldRoomTagRoomId, roomID)); err != nil {
+			return fmt.Errorf("delete room tags failed: %w", err)
+		}
+
+		for _, tag := range array.Uniq(tags) {
+			if _, err := model.NewRoomTagModel(tx).Save(ctx, model.RoomTagN{
+				UserId: null.IntFrom(userID),
+				RoomId: null.IntFrom(roomID),
+				Name:   null.StringFrom(tag),
+			}); err != nil {
+				return fmt.Errorf("create room tag failed: %w", err)
+			}
+		}
+
+		// 更新房间的修改时间，以便其它设备可以同步到变更
+		_, err := model.NewRoomsModel(tx).UpdateFields(ctx, query.KV{model.FieldRoomsUpdatedAt: time.Now()}, query.Builder().
+			Where(model.FieldRoomsUserId, userID).
+			Where(model.FieldRoomsId, roomID))
+		return err
+	})
+}
+
+// Folders 返回用户的所有文件夹
+func (r *RoomRepo) Folders(ctx context.Context, userID int64) ([]model.RoomFolder, error) {
+	folders, err := model.NewRoomFolderModel(r.db).Get(ctx, query.Builder().
+		Where(model.FieldRoomFolderUserId, userID).
+		OrderBy(model.FieldRoomFolderSort, "ASC").
+		OrderBy(model.FieldRoomFolderId, "ASC"))
+	if err != nil {
+		return nil, fmt.Errorf("query room folders failed: %w", err)
+	}
+
+	return array.Map(folders, func(f model.RoomFolderN, _ int) model.RoomFolder { return f.ToRoomFolder() }), nil
+}
+
+// Folder 查询单个文件夹
+func (r *RoomRepo) Folder(ctx context.Context, userID, folderID int64) (*model.RoomFolder, error) {
+	folder, err := model.NewRoomFolderModel(r.db).First(ctx, query.Builder().
+		Where(model.FieldRoomFolderUserId, userID).
+		Where(model.FieldRoomFolderId, folderID))
+	if err != nil {
+		if errors.Is(err, query.ErrNoResult) {
+			return nil, ErrNotFound
+		}
+
+		return nil, err
+	}
+
+	ret := folder.ToRoomFolder()
+	return &ret, nil
+}
+
+// CreateFolder 创建文件夹
+func (r *RoomRepo) CreateFolder(ctx context.Context, userID int64, name string, sort int64) (int64, error) {
+	var id int64
+	err := eloquent.Transaction(r.db, func(tx query.Database) error {
+		folders, err := model.NewRoomFolderModel(tx).Get(ctx, query.Builder().Where(model.FieldRoomFolderUserId, userID))
+		if err != nil {
+			return fmt.Errorf("query room folders failed: %w", err)
+		}
+
+		if len(folders) >= RoomFolderMaxCount {
+			return ErrRoomFolderLimitExceeded
+		}
+
+		if array.In(name, array.Map(folders, func(f model.RoomFolderN, _ int) string { return f.Name.ValueOrZero() })) {
+			return ErrAlreadyExists
+		}
+
+		id, err = model.NewRoomFolderModel(tx).Save(ctx, model.RoomFolderN{
+			UserId: null.IntFrom(userID),
+			Name:   null.StringFrom(name),
+			Sort:   null.IntFrom(sort),
+		})
+		return err
+	})
+
+	return id, err
+}
+
+// UpdateFolder 更新文件夹名称以及排序
+func (r *RoomRepo) UpdateFolder(ctx context.Context, userID, folderID int64, name string, sort int64) error {
+	exist, err := model.NewRoomFolderModel(r.db).Count(ctx, query.Builder().
+		Where(model.FieldRoomFolderUserId, userID).
+		Where(model.FieldRoomFolderName, name).
+		Where(model.FieldRoomFolderId, "!=", folderID))
+	if err != nil {
+		return fmt.Errorf("query room folders failed: %w", err)
+	}
+
+	if exist > 0 {
+		return ErrAlreadyExists
+	}
+
+	_, err = model.NewRoomFolderModel(r.db).UpdateFields(ctx, query.KV{
+		model.FieldRoomFolderName: name,
+		model.FieldRoomFolderSort: sort,
+	}, query.Builder().
+		Where(model.FieldRoomFolderUserId, userID).
+		Where(model.FieldRoomFolderId, folderID))
+
+	return err
+}
+
+// DeleteFolder 删除文件夹，文件夹中的房间会被移动到未分类
+func (r *RoomRepo) DeleteFolder(ctx context.Context, userID, folderID int64) error {
+	return eloquent.Transaction(r.db, func(tx query.Database) error {
+		if _, err := model.NewRoomsModel(tx).UpdateFields(ctx, query.KV{
+			model.FieldRoomsFolderId: 0,
+		}, query.Builder().
+			Where(model.FieldRoomsUserId, userID).
+			Where(model.FieldRoomsFolderId, folderID)); err != nil {
+			return fmt.Errorf("move rooms out of folder failed: %w", err)
+		}
+
+		_, err := model.NewRoomFolderModel(tx).Delete(ctx, query.Builder().
+			Where(model.FieldRoomFolderUserId, userID).
+			Where(model.FieldRoomFolderId, folderID))
+		return err
+	})
+}
+
+// BatchUpdate 批量更新房间的文件夹、置顶以及归档状态，只会更新 kv 中指定的字段
+func (r *RoomRepo) BatchUpdate(ctx context.Context, userID int64, roomIDs []int64, kv query.KV) (int64, error) {
+	if len(roomIDs) == 0 || len(kv) == 0 {
+		return 0, nil
+	}
+
+	return model.NewRoomsModel(r.db).UpdateFields(ctx, kv, query.Builder().
+		Where(model.FieldRoomsUserId, userID).
+		WhereIn(model.FieldRoomsId, roomIDs))
+}
+
+// BatchRemove 批量删除房间（不包括群聊，群聊需要使用 ChatGroupRepo.DeleteGroup 删除），返回实际删除的房间 ID
+func (r *RoomRepo) BatchRemove(ctx context.Context, userID int64, roomIDs []int64) ([]int64, error) {
+	var removed []int64
+	err := eloquent.Transaction(r.db, func(tx query.Database) error {
+		q := query.Builder().
+			Where(model.FieldRoomsUserId, userID).
+			WhereIn(model.FieldRoomsId, roomIDs).
+			Where(model.FieldRoomsRoomType, "!=", RoomTypeGroupChat)
+
+		rooms, err := model.NewRoomsModel(tx).Get(ctx, q)
+		if err != nil {
+			return fmt.Errorf("query rooms failed: %w", err)
+		}
+
+		removed = array.Map(rooms, func(room model.RoomsN, _ int) int64 { return room.Id.ValueOrZero() })
+		if len(removed) == 0 {
+			return nil
+		}
+
+		if _, err := model.NewRoomsModel(tx).Delete(ctx, query.Builder().
+			Where(model.FieldRoomsUserId, userID).
+			WhereIn(model.FieldRoomsId, removed)); err != nil {
+			return fmt.Errorf("delete rooms failed: %w", err)
+		}
+
+		if _, err := model.NewRoomTagModel(tx).Delete(ctx, query.Builder().
+			Where(model.FieldRoomTagUserId, userID).
+			WhereIn(model.FieldRoomTagRoomId, removed)); err != nil {
+			return fmt.Errorf("delete room tags failed: %w", err)
+		}
+
+		return addSyncTombstones(ctx, tx, userID, SyncEntityRoom, removed)
+	})
+
+	return removed, err
+}
@@ -0,0 +1,82 @@
+package repo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/mylxsw/asteria/log"
+	"gopkg.in/guregu/null.v3"
+)
+
+const (
+	// ReasoningEffortLow 低推理强度
+	ReasoningEffortLow = "low"
+	// ReasoningEffortMedium 中等推理强度
+	ReasoningEffortMedium = "medium"
+	// ReasoningEffortHigh 高推理强度
+	ReasoningEffortHigh = "high"
+)
+
+// RoomPresetMaxTokens 数字人预设的最大输出 Tokens 上限
+const RoomPresetMaxTokens = 128000
+
+// RoomPresets 数字人的生成参数预设，对话请求中未指定时使用
+type RoomPresets struct {
+	// Temperature 温度，为 0 时使用模型默认值
+	Temperature float64 `json:"temperature,omitempty"`
+	// MaxTokens 最大输出 Tokens，为 0 时使用模型默认值
+	MaxTokens int `json:"max_tokens,omitempty"`
+	// ReasoningEffort 推理强度：low/medium/high，为空时使用模型默认值
+	ReasoningEffort string `json:"reasoning_effort,omitempty"`
+	// EnableSearch 是否默认启用联网搜索
+	EnableSearch bool `json:"enable_search,omitempty"`
+	// SearchEngine 优先使用的搜索引擎，为空时使用系统默认的搜索引擎
+	SearchEngine string `json:"search_engine,omitempty"`
+}
+
+// Validate 检查预设是否合法，搜索引擎是否可用由调用方检查
+func (p RoomPresets) Validate() error {
+	if p.Temperature < 0 || p.Temperature > 2 {
+		return errors.New("temperature must be between 0 and 2")
+	}
+
+	if p.MaxTokens < 0 || p.MaxTokens > RoomPresetMaxTokens {
+		return fmt.Errorf("max_tokens must be between 0 and %d", RoomPresetMaxTokens)
+	}
+
+	switch p.ReasoningEffort {
+	case "", ReasoningEffortLow, ReasoningEffortMedium, ReasoningEffortHigh:
+	default:
+		return fmt.Errorf("invalid reasoning_effort: %s", p.ReasoningEffort)
+	}
+
+	return nil
+}
+
+// RoomPresetsOf 解析数字人的生成参数预设
+func RoomPresetsOf(presetsJson string) RoomPresets {
+	var presets RoomPresets
+	if presetsJson != "" {
+		if err := json.Unmarshal([]byte(presetsJson), &presets); err != nil {
+			log.F(log.M{"presets": presetsJson}).Errorf("unmarshal room presets failed: %s", err)
+		}
+	}
+
+	return presets
+}
+
+// nullableJSON JSON 类型的字段不允许写入空字符串，空值时写入 NULL
+func nullableJSON(data string) null.String {
+	return null.NewString(data, data != "")
+}
+
+// EncodeRoomPresets 将生成参数预设编码为 JSON 字符串，没有任何预设时返回空字符串
+func EncodeRoomPresets(presets RoomPresets) string {
+	if presets == (RoomPresets{}) {
+		return ""
+	}
+
+	data, _ := json.Marshal(presets)
+	return string(data)
+}
@@ -0,0 +1,22 @@
+package repo_test
+
+import (
+	"testing"
+
+	"github.com/mylxsw/aidea-server/pkg/repo"
+	"github.com/mylxsw/go-utils/assert"
+)
+
+func TestRoomPresets(t *testing.T) {
+	presets := repo.RoomPresets{Temperature: 0.3, MaxTokens: 4096, ReasoningEffort: repo.ReasoningEffortHigh, EnableSearch: true, SearchEngine: "bochaai"}
+	assert.NoError(t, presets.Validate())
+	assert.Equal(t, presets, repo.RoomPresetsOf(repo.EncodeRoomPresets(presets)))
+
+	assert.Equal(t, "", repo.EncodeRoomPresets(repo.RoomPresets{}))
+	assert.Equal(t, repo.RoomPresets{}, repo.RoomPresetsOf(""))
+	assert.Equal(t, repo.RoomPresets{}, repo.RoomPresetsOf("invalid"))
+
+	assert.True(t, repo.RoomPresets{Temperature: 2.5}.Validate() != nil)
+	assert.True(t, repo.RoomPresets{MaxTokens: -1}.Validate() != nil)
+	assert.True(t, repo.RoomPresets{ReasoningEffort: "extreme"}.Validate() != nil)
+}
@@ -5,7 +5,9 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/mylxsw/aidea-server/pkg/repo/model"
+	"github.com/mylxsw/eloquent"
 	"github.com/mylxsw/go-utils/maps"
 	"time"
 
@@ -18,6 +20,8 @@ import (
 
 var (
 	ErrRoomExists = errors.New("room exists")
+	// ErrRoomVersionConflict 房间已经被其它设备修改
+	ErrRoomVersionConflict = errors.New("room has been modified by another device")
 )
 
 const (
@@ -31,6 +35,9 @@ const (
 	RoomTypeGroupChat = 4
 )
 
+// RoomDefaultName 创建数字人时未指定名称使用的默认名称，首轮对话完成后会自动生成标题
+const RoomDefaultName = "新对话"
+
 type RoomRepo struct {
 	db *sql.DB
 }
@@ -42,21 +49,15 @@ func NewRoomRepo(db *sql.DB) *RoomRepo {
 type Room struct {
 	model.Rooms
 	Members []string `json:"members,omitempty"`
+	Tags    []string `json:"tags,omitempty"`
 }
 
 func (r *RoomRepo) Rooms(ctx context.Context, userID int64, roomTypes []int, limit int64) ([]Room, error) {
-	q := query.Builder().
-		Where(model.FieldRoomsUserId, userID).
-		WhereIn(model.FieldRoomsRoomType, roomTypes).
-		OrderBy(model.FieldRoomsPriority, "DESC").
-		OrderBy(model.FieldRoomsLastActiveTime, "DESC").
-		Limit(limit)
-
-	rooms, err := model.NewRoomsModel(r.db).Get(ctx, q)
-	if err != nil {
-		return nil, err
-	}
+	return r.FilterRooms(ctx, userID, roomTypes, RoomFilter{Archived: RoomsArchivedAll}, limit)
+}
 
+// fillRoomMembers 查询群聊成员，用于展示群聊头像列表
+func (r *RoomRepo) fillRoomMembers(ctx context.Context, userID int64, rooms []model.RoomsN) ([]Room, error) {
 	// 查询群聊头像列表
 	groupRooms := array.Filter(rooms, func(item model.RoomsN, index int) bool {
 		return item.RoomType.ValueOrZero() == RoomTypeGroupChat
@@ -158,7 +159,10 @@ func (r *RoomRepo) Create(ctx context.Context, userID int64, room *model.Rooms,
 		model.FieldRoomsMaxContext,
 		model.FieldRoomsRoomType,
 		model.FieldRoomsInitMessage,
+		model.FieldRoomsAutoTitle,
+		model.FieldRoomsPresetsJson,
 	)
+	roomN.PresetsJson = nullableJSON(room.PresetsJson)
 
 	id, err = model.NewRoomsModel(r.db).Save(ctx, roomN)
 
@@ -166,20 +170,49 @@ func (r *RoomRepo) Create(ctx context.Context, userID int64, room *model.Rooms,
 }
 
 func (r *RoomRepo) Remove(ctx context.Context, userID, roomID int64) error {
-	q := query.Builder().
-		Where(model.FieldRoomsUserId, userID).
-		Where(model.FieldRoomsId, roomID)
+	return eloquent.Transaction(r.db, func(tx query.Database) error {
+		q := query.Builder().
+			Where(model.FieldRoomsUserId, userID).
+			Where(model.FieldRoomsId, roomID)
 
-	_, err := model.NewRoomsModel(r.db).Delete(ctx, q)
-	return err
+		affected, err := model.NewRoomsModel(tx).Delete(ctx, q)
+		if err != nil {
+			return err
+		}
+
+		if affected == 0 {
+			return nil
+		}
+
+		if _, err := model.NewRoomTagModel(tx).Delete(ctx, query.Builder().
+			Where(model.FieldRoomTagUserId, userID).
+			Where(model.FieldRoomTagRoomId, roomID)); err != nil {
+			return fmt.Errorf("delete room tags failed: %w", err)
+		}
+
+		return addSyncTombstones(ctx, tx, userID, SyncEntityRoom, []int64{roomID})
+	})
 }
 
+// Update 更新房间信息，room.Version 为客户端修改前看到的版本号，如果房间已经被其它设备修改过，返回 ErrRoomVersionConflict
 func (r *RoomRepo) Update(ctx context.Context, userID, roomID int64, room *model.Rooms) error {
+	// 默认房间不存储在数据库中
+	if roomID == 1 {
+		return nil
+	}
+
 	q := query.Builder().
 		Where(model.FieldRoomsUserId, userID).
 		Where(model.FieldRoomsId, roomID)
 
-	_, err := model.NewRoomsModel(r.db).Update(ctx, q, room.ToRoomsN(
+	if room.Version > 0 {
+		q = q.Where(model.FieldRoomsVersion, room.Version)
+	} else {
+		q = q.WhereRaw("(version IS NULL OR version = 0)")
+	}
+
+	room.Version++
+	roomN := room.ToRoomsN(
 		model.FieldRoomsName,
 		model.FieldRoomsDescription,
 		model.FieldRoomsAvatarId,
@@ -191,9 +224,26 @@ func (r *RoomRepo) Update(ctx context.Context, userID, roomID int64, room *model
 		model.FieldRoomsMaxContext,
 		model.FieldRoomsRoomType,
 		model.FieldRoomsInitMessage,
-	))
+		model.FieldRoomsAutoTitle,
+		model.FieldRoomsPresetsJson,
+		model.FieldRoomsVersion,
+	)
+	kv := roomN.StaledKV()
+	// 清空预设时需要显式写入 NULL
+	kv[model.FieldRoomsPresetsJson] = nullableJSON(room.PresetsJson)
 
-	return err
+	affected, err := model.NewRoomsModel(r.db).UpdateFields(ctx, kv, q)
+	if err != nil {
+		room.Version--
+		return err
+	}
+
+	if affected == 0 {
+		room.Version--
+		return ErrRoomVersionConflict
+	}
+
+	return nil
 }
 
 func (r *RoomRepo) UpdateLastActiveTime(ctx context.Context, userID, roomID int64) error {
@@ -208,6 +258,22 @@ func (r *RoomRepo) UpdateLastActiveTime(ctx context.Context, userID, roomID int6
 	return err
 }
 
+// ClaimAutoTitle 标记数字人已经尝试过自动生成标题，无论生成是否成功都只会尝试一次
+// 返回 false 表示数字人不需要自动生成标题，或者已经被其它任务处理
+func (r *RoomRepo) ClaimAutoTitle(ctx context.Context, userID, roomID int64) (bool, error) {
+	q := query.Builder().
+		Where(model.FieldRoomsUserId, userID).
+		Where(model.FieldRoomsId, roomID).
+		Where(model.FieldRoomsAutoTitle, 1)
+
+	affected, err := model.NewRoomsModel(r.db).UpdateFields(ctx, query.KV{model.FieldRoomsAutoTitle: 0}, q)
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
 type GalleryRoom struct {
 	Id          int64    `json:"id"`
 	Name        string   `json:"name,omitempty"`
@@ -224,6 +290,8 @@ type GalleryRoom struct {
 	Prompt      string `json:"-"`
 	MaxContext  int64  `json:"-"`
 	InitMessage string `json:"-"`
+
+	Presets RoomPresets `json:"-"`
 }
 
 func createGalleryRoomFromModel(room model.RoomGallery) GalleryRoom {
@@ -254,6 +322,7 @@ func createGalleryRoomFromModel(room model.RoomGallery) GalleryRoom {
 		RoomType:    room.RoomType,
 		VersionMin:  room.VersionMin,
 		VersionMax:  room.VersionMax,
+		Presets:     RoomPresetsOf(room.PresetsJson),
 	}
 }
 
@@ -0,0 +1,240 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mylxsw/aidea-server/pkg/misc"
+	"github.com/mylxsw/aidea-server/pkg/repo/model"
+	"github.com/mylxsw/eloquent/query"
+	"github.com/mylxsw/go-utils/array"
+	"golang.org/x/crypto/bcrypt"
+	"gopkg.in/guregu/null.v3"
+)
+
+const (
+	// ShareTypeMessages 分享聊天记录
+	ShareTypeMessages = "messages"
+	// ShareTypeCreative 分享创作岛作品
+	ShareTypeCreative = "creative"
+)
+
+var (
+	ErrShareExpired          = errors.New("share link has expired")
+	ErrShareRevoked          = errors.New("share link has been revoked")
+	ErrSharePasswordRequired = errors.New("share link requires a password")
+	ErrSharePasswordMismatch = errors.New("share link password mismatch")
+)
+
+// ShareOptions 分享链接的访问控制选项
+type ShareOptions struct {
+	// ExpiredAt 过期时间，零值表示永不过期
+	ExpiredAt time.Time
+	// Password 访问密码，为空表示不需要密码
+	Password string
+}
+
+// Unrestricted 是否没有任何访问限制
+func (opts ShareOptions) Unrestricted() bool {
+	return opts.ExpiredAt.IsZero() && opts.Password == ""
+}
+
+// ShareLink 分享链接信息，用于用户管理自己的分享
+type ShareLink struct {
+	Code         string          `json:"code"`
+	Type         string          `json:"type"`
+	Data         json.RawMessage `json:"data,omitempty"`
+	HasPassword  bool            `json:"has_password"`
+	ExpiredAt    *time.Time      `json:"expired_at,omitempty"`
+	Expired      bool            `json:"expired"`
+	Revoked      bool            `json:"revoked"`
+	Views        int64           `json:"views"`
+	LastViewedAt *time.Time      `json:"last_viewed_at,omitempty"`
+	CreatedAt    time.Time       `json:"created_at"`
+}
+
+// Active 分享链接是否可以访问
+func (link ShareLink) Active() bool {
+	return !link.Expired && !link.Revoked
+}
+
+func newShareLink(share model.ChatMessagesShareN) ShareLink {
+	link := ShareLink{
+		Code:        share.Code.ValueOrZero(),
+		Type:        shareType(share),
+		HasPassword: share.Password.ValueOrZero() != "",
+		Revoked:     share.Revoked.ValueOrZero() == 1,
+		Views:       share.Views.ValueOrZero(),
+		CreatedAt:   share.CreatedAt.ValueOrZero(),
+	}
+
+	if data := share.Data.ValueOrZero(); data != "" {
+		link.Data = json.RawMessage(data)
+	}
+
+	if share.ExpiredAt.Valid {
+		link.ExpiredAt = &share.ExpiredAt.Time
+		link.Expired = share.ExpiredAt.Time.Before(time.Now())
+	}
+
+	if share.LastViewedAt.Valid {
+		link.LastViewedAt = &share.LastViewedAt.Time
+	}
+
+	return link
+}
+
+// shareType 历史数据没有分享类型，默认为聊天记录
+func shareType(share model.ChatMessagesShareN) string {
+	if t := share.ShareType.ValueOrZero(); t != "" {
+		return t
+	}
+
+	return ShareTypeMessages
+}
+
+type ShareRepo struct {
+	db *sql.DB
+}
+
+func NewShareRepo(db *sql.DB) *ShareRepo {
+	return &ShareRepo{db: db}
+}
+
+// Shares 查询用户创建的分享链接，activeOnly 为 true 时只返回未过期且未撤销的分享
+func (r *ShareRepo) Shares(ctx context.Context, userID int64, activeOnly bool) ([]ShareLink, error) {
+	shares, err := model.NewChatMessagesShareModel(r.db).Get(ctx, query.Builder().
+		Where(model.FieldChatMessagesShareUserId, userID).
+		OrderBy(model.FieldChatMessagesShareId, "DESC"))
+	if err != nil {
+		return nil, fmt.Errorf("query shares failed: %w", err)
+	}
+
+	links := array.Map(shares, func(share model.ChatMessagesShareN, _ int) ShareLink { return newShareLink(share) })
+	if activeOnly {
+		links = array.Filter(links, func(link ShareLink, _ int) bool { return link.Active() })
+	}
+
+	return links, nil
+}
+
+// Revoke 撤销分享链接，撤销后的链接无法再访问
+func (r *ShareRepo) Revoke(ctx context.Context, userID int64, code string) error {
+	affected, err := model.NewChatMessagesShareModel(r.db).UpdateFields(ctx, query.KV{
+		model.FieldChatMessagesShareRevoked: 1,
+	}, query.Builder().
+		Where(model.FieldChatMessagesShareUserId, userID).
+		Where(model.FieldChatMessagesShareCode, code))
+	if err != nil {
+		return fmt.Errorf("revoke share failed: %w", err)
+	}
+
+	if affected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// createShare 创建分享链接
+// 没有访问限制时，相同的分享内容复用同一个分享码；存在访问限制时，每次都生成新的分享码，以便单独撤销
+func createShare(ctx context.Context, db *sql.DB, userID int64, typ string, fingerprint string, data any, opts ShareOptions) (string, error) {
+	if opts.Unrestricted() {
+		code := misc.Sha1([]byte(fmt.Sprintf("%d:%s", userID, fingerprint)))
+		share, err := model.NewChatMessagesShareModel(db).First(ctx, query.Builder().Where(model.FieldChatMessagesShareCode, code))
+		if err != nil && !errors.Is(err, query.ErrNoResult) {
+			return "", err
+		}
+
+		if share == nil {
+			return code, insertShare(ctx, db, userID, typ, code, data, opts)
+		}
+
+		link := newShareLink(*share)
+		if link.Active() && !link.HasPassword {
+			return code, nil
+		}
+	}
+
+	code := misc.Sha1([]byte(fmt.Sprintf("%d:%s:%s", userID, fingerprint, misc.UUID())))
+	return code, insertShare(ctx, db, userID, typ, code, data, opts)
+}
+
+func insertShare(ctx context.Context, db *sql.DB, userID int64, typ string, code string, data any, opts ShareOptions) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	share := model.ChatMessagesShareN{
+		UserId:    null.IntFrom(userID),
+		Code:      null.StringFrom(code),
+		ShareType: null.StringFrom(typ),
+		Data:      null.StringFrom(string(payload)),
+	}
+
+	if !opts.ExpiredAt.IsZero() {
+		share.ExpiredAt = null.TimeFrom(opts.ExpiredAt)
+	}
+
+	if opts.Password != "" {
+		password, err := bcrypt.GenerateFromPassword([]byte(opts.Password), bcrypt.DefaultCost)
+		if err != nil {
+			return err
+		}
+
+		share.Password = null.StringFrom(string(password))
+	}
+
+	_, err = model.NewChatMessagesShareModel(db).Save(ctx, share)
+	return err
+}
+
+// accessShare 访问分享链接，校验分享类型、有效期以及访问密码，校验通过后记录访问次数
+func accessShare(ctx context.Context, db *sql.DB, code string, typ string, password string) (*model.ChatMessagesShare, error) {
+	share, err := model.NewChatMessagesShareModel(db).First(ctx, query.Builder().Where(model.FieldChatMessagesShareCode, code))
+	if err != nil {
+		if errors.Is(err, query.ErrNoResult) {
+			return nil, ErrNotFound
+		}
+
+		return nil, err
+	}
+
+	link := newShareLink(*share)
+	if link.Type != typ {
+		return nil, ErrNotFound
+	}
+
+	if link.Revoked {
+		return nil, ErrShareRevoked
+	}
+
+	if link.Expired {
+		return nil, ErrShareExpired
+	}
+
+	if link.HasPassword {
+		if password == "" {
+			return nil, ErrSharePasswordRequired
+		}
+
+		if err := bcrypt.CompareHashAndPassword([]byte(share.Password.ValueOrZero()), []byte(password)); err != nil {
+			return nil, ErrSharePasswordMismatch
+		}
+	}
+
+	if _, err := model.NewChatMessagesShareModel(db).UpdateFields(ctx, query.KV{
+		model.FieldChatMessagesShareViews:        query.Raw("IFNULL(views, 0) + 1"),
+		model.FieldChatMessagesShareLastViewedAt: time.Now(),
+	}, query.Builder().Where(model.FieldChatMessagesShareId, share.Id.ValueOrZero())); err != nil {
+		return nil, fmt.Errorf("update share views failed: %w", err)
+	}
+
+	ret := share.ToChatMessagesShare()
+	return &ret, nil
+}
@@ -0,0 +1,181 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mylxsw/aidea-server/pkg/repo/model"
+	"github.com/mylxsw/eloquent/query"
+	"github.com/mylxsw/go-utils/array"
+)
+
+const (
+	// SyncEntityRoom 数字人（包括群聊）
+	SyncEntityRoom = "room"
+	// SyncEntityMessage 聊天消息
+	SyncEntityMessage = "message"
+)
+
+// SyncMaxLimit 单次同步最多返回的每种数据的数量
+const SyncMaxLimit = 500
+
+// SyncCursor 同步游标，记录客户端已经同步到的位置
+// 房间和消息按照 (sync_at, id) 排序，sync_at 为数据库维护的微秒级变更时间，删除记录按照 id 排序
+type SyncCursor struct {
+	RoomSyncAt    int64 `json:"rs,omitempty"`
+	RoomID        int64 `json:"ri,omitempty"`
+	MessageSyncAt int64 `json:"ms,omitempty"`
+	MessageID     int64 `json:"mi,omitempty"`
+	TombstoneID   int64 `json:"t,omitempty"`
+}
+
+// Encode 将游标编码为不透明的字符串，客户端只需要原样传回
+func (c SyncCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeSyncCursor 解析客户端传回的游标，空字符串表示从头开始同步
+func DecodeSyncCursor(cursor string) (SyncCursor, error) {
+	var ret SyncCursor
+	if cursor == "" {
+		return ret, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return ret, fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &ret); err != nil {
+		return ret, fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	return ret, nil
+}
+
+type SyncTombstone struct {
+	EntityType string    `json:"entity_type"`
+	EntityID   int64     `json:"entity_id"`
+	DeletedAt  time.Time `json:"deleted_at"`
+}
+
+type SyncChanges struct {
+	Rooms      []model.Rooms        `json:"rooms"`
+	Messages   []model.ChatMessages `json:"messages"`
+	Tombstones []SyncTombstone      `json:"tombstones"`
+	Cursor     string               `json:"cursor"`
+	// HasMore 是否还有更多的变更，客户端应该使用新的游标继续拉取
+	HasMore bool `json:"has_more"`
+}
+
+type SyncRepo struct {
+	db *sql.DB
+}
+
+func NewSyncRepo(db *sql.DB) *SyncRepo {
+	return &SyncRepo{db: db}
+}
+
+// Changes 查询游标之后发生变更的房间、消息以及删除记录
+func (r *SyncRepo) Changes(ctx context.Context, userID int64, cursor SyncCursor, limit int64) (*SyncChanges, error) {
+	if limit <= 0 || limit > SyncMaxLimit {
+		limit = SyncMaxLimit
+	}
+
+	ret := SyncChanges{
+		Rooms:      make([]model.Rooms, 0),
+		Messages:   make([]model.ChatMessages, 0),
+		Tombstones: make([]SyncTombstone, 0),
+	}
+
+	// 多查询一条记录，用于判断是否还有更多数据
+	rooms, err := model.NewRoomsModel(r.db).Get(ctx, query.Builder().
+		Where(model.FieldRoomsUserId, userID).
+		WhereRaw(
+			"(sync_at > ? OR (sync_at = ? AND id > ?))",
+			time.UnixMicro(cursor.RoomSyncAt), time.UnixMicro(cursor.RoomSyncAt), cursor.RoomID,
+		).
+		OrderBy(model.FieldRoomsSyncAt, "ASC").
+		OrderBy(model.FieldRoomsId, "ASC").
+		Limit(limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("query changed rooms failed: %w", err)
+	}
+
+	if int64(len(rooms)) > limit {
+		rooms, ret.HasMore = rooms[:limit], true
+	}
+
+	for _, room := range rooms {
+		ret.Rooms = append(ret.Rooms, room.ToRooms())
+		cursor.RoomSyncAt, cursor.RoomID = room.SyncAt.ValueOrZero().UnixMicro(), room.Id.ValueOrZero()
+	}
+
+	messages, err := model.NewChatMessagesModel(r.db).Get(ctx, query.Builder().
+		Where(model.FieldChatMessagesUserId, userID).
+		WhereRaw(
+			"(sync_at > ? OR (sync_at = ? AND id > ?))",
+			time.UnixMicro(cursor.MessageSyncAt), time.UnixMicro(cursor.MessageSyncAt), cursor.MessageID,
+		).
+		OrderBy(model.FieldChatMessagesSyncAt, "ASC").
+		OrderBy(model.FieldChatMessagesId, "ASC").
+		Limit(limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("query changed messages failed: %w", err)
+	}
+
+	if int64(len(messages)) > limit {
+		messages, ret.HasMore = messages[:limit], true
+	}
+
+	for _, msg := range messages {
+		ret.Messages = append(ret.Messages, msg.ToChatMessages())
+		cursor.MessageSyncAt, cursor.MessageID = msg.SyncAt.ValueOrZero().UnixMicro(), msg.Id.ValueOrZero()
+	}
+
+	tombstones, err := model.NewSyncTombstoneModel(r.db).Get(ctx, query.Builder().
+		Where(model.FieldSyncTombstoneUserId, userID).
+		Where(model.FieldSyncTombstoneId, ">", cursor.TombstoneID).
+		OrderBy(model.FieldSyncTombstoneId, "ASC").
+		Limit(limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("query tombstones failed: %w", err)
+	}
+
+	if int64(len(tombstones)) > limit {
+		tombstones, ret.HasMore = tombstones[:limit], true
+	}
+
+	for _, ts := range tombstones {
+		ret.Tombstones = append(ret.Tombstones, SyncTombstone{
+			EntityType: ts.EntityType.ValueOrZero(),
+			EntityID:   ts.EntityId.ValueOrZero(),
+			DeletedAt:  ts.CreatedAt.ValueOrZero(),
+		})
+		cursor.TombstoneID = ts.Id.ValueOrZero()
+	}
+
+	ret.Cursor = cursor.Encode()
+	return &ret, nil
+}
+
+// addSyncTombstones 记录被删除的数据，供其它设备同步删除操作，需要与删除操作在同一个事务中执行
+func addSyncTombstones(ctx context.Context, tx query.Database, userID int64, entityType string, entityIDs []int64) error {
+	entityIDs = array.Uniq(array.Filter(entityIDs, func(id int64, _ int) bool { return id > 0 }))
+	for _, id := range entityIDs {
+		if _, err := model.NewSyncTombstoneModel(tx).Create(ctx, query.KV{
+			model.FieldSyncTombstoneUserId:     userID,
+			model.FieldSyncTombstoneEntityType: entityType,
+			model.FieldSyncTombstoneEntityId:   id,
+		}); err != nil {
+			return fmt.Errorf("create sync tombstone failed: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,22 @@
+package repo_test
+
+import (
+	"testing"
+
+	"github.com/mylxsw/aidea-server/pkg/repo"
+	"github.com/mylxsw/go-utils/assert"
+)
+
+func TestSyncCursor(t *testing.T) {
+	cursor, err := repo.DecodeSyncCursor("")
+	assert.NoError(t, err)
+	assert.Equal(t, repo.SyncCursor{}, cursor)
+
+	expect := repo.SyncCursor{RoomSyncAt: 1700000000123456, RoomID: 12, MessageSyncAt: 1700000100654321, MessageID: 345, TombstoneID: 6}
+	cursor, err = repo.DecodeSyncCursor(expect.Encode())
+	assert.NoError(t, err)
+	assert.Equal(t, expect, cursor)
+
+	_, err = repo.DecodeSyncCursor("not a cursor!")
+	assert.True(t, err != nil)
+}
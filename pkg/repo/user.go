@@ -582,6 +582,8 @@ type UserCustomConfig struct {
 	// HomeModels 主页显示的模型
 	HomeModels   []string      `json:"home_models,omitempty"`
 	HomeModelsV2 []HomeModelV2 `json:"home_models_v2,omitempty"`
+	// MemoryEnabled 是否开启长期记忆，开启后会从对话中提取用户信息并在后续对话中使用
+	MemoryEnabled bool `json:"memory_enabled,omitempty"`
 }
 
 type HomeModelV2 struct {
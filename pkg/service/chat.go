@@ -55,6 +55,11 @@ func (svc *ChatService) Room(ctx context.Context, userID int64, roomID int64) (*
 	return room, nil
 }
 
+// ForgetRoom 清理房间信息缓存，房间配置变更后调用，使新的配置立即生效
+func (svc *ChatService) ForgetRoom(ctx context.Context, userID int64, roomID int64) error {
+	return svc.rds.Del(ctx, fmt.Sprintf("chat-room:%d:%d:info", userID, roomID)).Err()
+}
+
 const (
 	ProviderOpenAI     = "openai"
 	ProviderXunFei     = "讯飞星火"
@@ -67,3 +67,30 @@ func (srv *GalleryService) Gallery(ctx context.Context, page, perPage int64) (*G
 
 	return &resp, nil
 }
+
+// ClearCache 清空图库列表缓存，作品下架等需要立即生效的操作后调用
+//
+// 使用 SCAN 分批查找缓存，避免 KEYS 阻塞 Redis
+func (srv *GalleryService) ClearCache(ctx context.Context) error {
+	var cursor uint64
+	for {
+		var keys []string
+		var err error
+
+		keys, cursor, err = srv.rds.Scan(ctx, cursor, "gallery-list:*", 100).Result()
+		if err != nil {
+			return err
+		}
+		if len(keys) > 0 {
+			if _, err = srv.rds.Del(ctx, keys...).Result(); err != nil {
+				return err
+			}
+		}
+
+		if cursor == 0 {
+			break
+		}
+	}
+
+	return nil
+}
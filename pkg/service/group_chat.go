@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mylxsw/asteria/log"
+	"github.com/mylxsw/glacier/infra"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// GroupChatEventTyping 成员开始回复
+	GroupChatEventTyping = "typing"
+	// GroupChatEventDelta 成员回复的增量内容
+	GroupChatEventDelta = "delta"
+	// GroupChatEventStatus 消息状态变更（成功或者失败）
+	GroupChatEventStatus = "status"
+	// GroupChatEventPing 心跳，用于保持客户端连接
+	GroupChatEventPing = "ping"
+)
+
+// GroupChatEvent 群聊消息事件，由队列消费者发布，通过 Redis 发布订阅机制推送给订阅了该群组的所有服务实例
+type GroupChatEvent struct {
+	Type          string `json:"type"`
+	GroupID       int64  `json:"group_id"`
+	MessageID     int64  `json:"message_id"`
+	MemberID      int64  `json:"member_id,omitempty"`
+	Delta         string `json:"delta,omitempty"`
+	Message       string `json:"message,omitempty"`
+	Status        int64  `json:"status,omitempty"`
+	Error         string `json:"error,omitempty"`
+	TokenConsumed int64  `json:"token_consumed,omitempty"`
+	QuotaConsumed int64  `json:"quota_consumed,omitempty"`
+}
+
+type GroupChatService struct {
+	rds *redis.Client `autowire:"@"`
+}
+
+func NewGroupChatService(resolver infra.Resolver) *GroupChatService {
+	svc := &GroupChatService{}
+	resolver.MustAutoWire(svc)
+	return svc
+}
+
+func groupChatEventChannel(groupID int64) string {
+	return fmt.Sprintf("group-chat:%d:events", groupID)
+}
+
+// Publish 发布群聊消息事件
+func (svc *GroupChatService) Publish(ctx context.Context, evt GroupChatEvent) error {
+	data, err := json.Marshal(evt)
+	if err != nil {
+		return err
+	}
+
+	return svc.rds.Publish(ctx, groupChatEventChannel(evt.GroupID), string(data)).Err()
+}
+
+// Subscribe 订阅群组的消息事件，ctx 取消后自动取消订阅并关闭返回的 channel
+func (svc *GroupChatService) Subscribe(ctx context.Context, groupID int64) (<-chan GroupChatEvent, error) {
+	sub := svc.rds.Subscribe(ctx, groupChatEventChannel(groupID))
+	// 等待订阅确认，确保订阅成功后才返回
+	if _, err := sub.Receive(ctx); err != nil {
+		_ = sub.Close()
+		return nil, fmt.Errorf("subscribe group chat events failed: %w", err)
+	}
+
+	events := make(chan GroupChatEvent)
+	go func() {
+		defer close(events)
+		defer func() { _ = sub.Close() }()
+
+		messages := sub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+
+				var evt GroupChatEvent
+				if err := json.Unmarshal([]byte(msg.Payload), &evt); err != nil {
+					log.F(log.M{"group_id": groupID, "payload": msg.Payload}).Errorf("unmarshal group chat event failed: %s", err)
+					continue
+				}
+
+				select {
+				case events <- evt:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return events, nil
+}
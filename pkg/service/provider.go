@@ -10,6 +10,9 @@ func (Provider) Register(binder infra.Binder) {
 	binder.MustSingleton(NewGalleryService)
 	binder.MustSingleton(NewChatService)
 	binder.MustSingleton(NewSettingService)
+	binder.MustSingleton(NewGroupChatService)
+	binder.MustSingleton(NewSyncService)
+	binder.MustSingleton(NewTaskEventService)
 
 	binder.MustSingleton(func(resolver infra.Resolver) *Service {
 		var svc Service
@@ -20,9 +23,12 @@ func (Provider) Register(binder infra.Binder) {
 }
 
 type Service struct {
-	User     *UserService     `autowire:"@"`
-	Security *SecurityService `autowire:"@"`
-	Gallery  *GalleryService  `autowire:"@"`
-	Chat     *ChatService     `autowire:"@"`
-	Setting  *SettingService  `autowire:"@"`
+	User      *UserService      `autowire:"@"`
+	Security  *SecurityService  `autowire:"@"`
+	Gallery   *GalleryService   `autowire:"@"`
+	Chat      *ChatService      `autowire:"@"`
+	Setting   *SettingService   `autowire:"@"`
+	GroupChat *GroupChatService `autowire:"@"`
+	Sync      *SyncService      `autowire:"@"`
+	TaskEvent *TaskEventService `autowire:"@"`
 }
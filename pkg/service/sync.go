@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mylxsw/asteria/log"
+	"github.com/mylxsw/glacier/infra"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// SyncEventChanged 用户数据发生变更，客户端收到后应该调用同步接口拉取变更
+	SyncEventChanged = "changed"
+	// SyncEventPing 心跳，用于保持客户端连接
+	SyncEventPing = "ping"
+)
+
+// SyncEvent 多端同步事件，某个设备写入数据后，通知该用户的其它设备
+type SyncEvent struct {
+	Type   string `json:"type"`
+	UserID int64  `json:"-"`
+	// Device 产生变更的设备 ID，客户端可以据此忽略自己产生的变更
+	Device    string  `json:"device,omitempty"`
+	Entity    string  `json:"entity,omitempty"`
+	EntityIDs []int64 `json:"entity_ids,omitempty"`
+}
+
+type SyncService struct {
+	rds *redis.Client `autowire:"@"`
+}
+
+func NewSyncService(resolver infra.Resolver) *SyncService {
+	svc := &SyncService{}
+	resolver.MustAutoWire(svc)
+	return svc
+}
+
+func syncEventChannel(userID int64) string {
+	return fmt.Sprintf("sync:%d:events", userID)
+}
+
+// Publish 发布多端同步事件
+func (svc *SyncService) Publish(ctx context.Context, evt SyncEvent) error {
+	data, err := json.Marshal(evt)
+	if err != nil {
+		return err
+	}
+
+	return svc.rds.Publish(ctx, syncEventChannel(evt.UserID), string(data)).Err()
+}
+
+// Notify 通知用户的其它设备数据发生了变更，发布失败只记录日志，不影响正常的业务流程
+func (svc *SyncService) Notify(ctx context.Context, userID int64, device string, entity string, entityIDs ...int64) {
+	evt := SyncEvent{
+		Type:      SyncEventChanged,
+		UserID:    userID,
+		Device:    device,
+		Entity:    entity,
+		EntityIDs: entityIDs,
+	}
+
+	if err := svc.Publish(ctx, evt); err != nil {
+		log.F(log.M{"user_id": userID, "entity": entity, "entity_ids": entityIDs}).Errorf("publish sync event failed: %s", err)
+	}
+}
+
+// Subscribe 订阅用户的多端同步事件，ctx 取消后自动取消订阅并关闭返回的 channel
+func (svc *SyncService) Subscribe(ctx context.Context, userID int64) (<-chan SyncEvent, error) {
+	sub := svc.rds.Subscribe(ctx, syncEventChannel(userID))
+	if _, err := sub.Receive(ctx); err != nil {
+		_ = sub.Close()
+		return nil, fmt.Errorf("subscribe sync events failed: %w", err)
+	}
+
+	events := make(chan SyncEvent)
+	go func() {
+		defer close(events)
+		defer func() { _ = sub.Close() }()
+
+		messages := sub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+
+				var evt SyncEvent
+				if err := json.Unmarshal([]byte(msg.Payload), &evt); err != nil {
+					log.F(log.M{"user_id": userID, "payload": msg.Payload}).Errorf("unmarshal sync event failed: %s", err)
+					continue
+				}
+
+				select {
+				case events <- evt:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return events, nil
+}
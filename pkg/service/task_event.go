@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mylxsw/asteria/log"
+	"github.com/mylxsw/glacier/infra"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// TaskEventQueued 任务已加入队列
+	TaskEventQueued = "queued"
+	// TaskEventRunning 任务执行中，服务商返回进度时，Progress 为进度百分比
+	TaskEventRunning = "running"
+	// TaskEventSucceeded 任务执行成功
+	TaskEventSucceeded = "succeeded"
+	// TaskEventFailed 任务执行失败
+	TaskEventFailed = "failed"
+	// TaskEventPing 心跳，用于保持客户端连接
+	TaskEventPing = "ping"
+)
+
+// TaskEvent 异步任务（创作岛图片、视频生成等）状态变更事件
+type TaskEvent struct {
+	Type     string `json:"type"`
+	UserID   int64  `json:"-"`
+	TaskID   string `json:"task_id,omitempty"`
+	TaskType string `json:"task_type,omitempty"`
+	// Progress 任务执行进度（0-100），只有服务商返回进度时才有
+	Progress int `json:"progress,omitempty"`
+	// Result 任务执行结果，与任务状态查询接口返回的结果一致
+	Result json.RawMessage `json:"result,omitempty"`
+}
+
+type TaskEventService struct {
+	rds *redis.Client `autowire:"@"`
+}
+
+func NewTaskEventService(resolver infra.Resolver) *TaskEventService {
+	svc := &TaskEventService{}
+	resolver.MustAutoWire(svc)
+	return svc
+}
+
+func taskEventChannel(userID int64) string {
+	return fmt.Sprintf("task:%d:events", userID)
+}
+
+// Publish 发布任务状态变更事件
+func (svc *TaskEventService) Publish(ctx context.Context, evt TaskEvent) error {
+	data, err := json.Marshal(evt)
+	if err != nil {
+		return err
+	}
+
+	return svc.rds.Publish(ctx, taskEventChannel(evt.UserID), string(data)).Err()
+}
+
+// Notify 发布任务状态变更事件，发布失败只记录日志，不影响任务的执行
+func (svc *TaskEventService) Notify(ctx context.Context, evt TaskEvent) {
+	if err := svc.Publish(ctx, evt); err != nil {
+		log.F(log.M{"user_id": evt.UserID, "task_id": evt.TaskID, "type": evt.Type}).Errorf("publish task event failed: %s", err)
+	}
+}
+
+// Subscribe 订阅用户的任务状态变更事件，ctx 取消后自动取消订阅并关闭返回的 channel
+func (svc *TaskEventService) Subscribe(ctx context.Context, userID int64) (<-chan TaskEvent, error) {
+	sub := svc.rds.Subscribe(ctx, taskEventChannel(userID))
+	if _, err := sub.Receive(ctx); err != nil {
+		_ = sub.Close()
+		return nil, fmt.Errorf("subscribe task events failed: %w", err)
+	}
+
+	events := make(chan TaskEvent)
+	go func() {
+		defer close(events)
+		defer func() { _ = sub.Close() }()
+
+		messages := sub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+
+				var evt TaskEvent
+				if err := json.Unmarshal([]byte(msg.Payload), &evt); err != nil {
+					log.F(log.M{"user_id": userID, "payload": msg.Payload}).Errorf("unmarshal task event failed: %s", err)
+					continue
+				}
+
+				select {
+				case events <- evt:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return events, nil
+}
@@ -11,6 +11,8 @@ type ClientInfo struct {
 	PlatformVersion string `json:"platform_version"`
 	Language        string `json:"language"`
 	IP              string `json:"ip"`
+	// DeviceID 客户端设备 ID，用于多端同步时区分数据变更来自哪个设备
+	DeviceID string `json:"device_id"`
 }
 
 // IsIOS 返回客户端是否是 IOS 平台
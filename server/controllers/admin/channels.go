@@ -3,6 +3,7 @@ package admin
 import (
 	"context"
 	"errors"
+	"github.com/mylxsw/aidea-server/pkg/ai/imagegen"
 	"github.com/mylxsw/aidea-server/pkg/repo"
 	"github.com/mylxsw/aidea-server/pkg/service"
 	"github.com/mylxsw/aidea-server/server/controllers/common"
@@ -15,8 +16,9 @@ import (
 )
 
 type ChannelController struct {
-	repo *repo.Repository `autowire:"@"`
-	svc  *service.Service `autowire:"@"`
+	repo   *repo.Repository   `autowire:"@"`
+	svc    *service.Service   `autowire:"@"`
+	images *imagegen.Registry `autowire:"@"`
 }
 
 func NewChannelController(resolver infra.Resolver) web.Controller {
@@ -48,7 +50,17 @@ func (ctl *ChannelController) Register(router web.Router) {
 // @Success 200 {object} common.DataArray[service.ChannelType]
 // @Router /v1/admin/channel-types [get]
 func (ctl *ChannelController) ChannelTypes(ctx context.Context, webCtx web.Context) web.Response {
-	return webCtx.JSON(common.NewDataArray(ctl.svc.Chat.ChannelTypes()))
+	return webCtx.JSON(common.NewDataArray(ctl.channelTypes()))
+}
+
+// channelTypes 返回所有支持的渠道类型，包括对话模型渠道以及图片生成渠道
+func (ctl *ChannelController) channelTypes() []service.ChannelType {
+	return append(
+		ctl.svc.Chat.ChannelTypes(),
+		array.Map(ctl.images.ChannelTypes(), func(t imagegen.ChannelType, _ int) service.ChannelType {
+			return service.ChannelType{Name: t.Name, Dynamic: true, Display: t.Display}
+		})...,
+	)
 }
 
 type Channel struct {
@@ -69,7 +81,7 @@ func (ctl *ChannelController) Channels(ctx context.Context, webCtx web.Context)
 		return webCtx.JSONError(err.Error(), http.StatusInternalServerError)
 	}
 
-	types := array.ToMap(ctl.svc.Chat.ChannelTypes(), func(t service.ChannelType, _ int) string {
+	types := array.ToMap(ctl.channelTypes(), func(t service.ChannelType, _ int) string {
 		return t.Name
 	})
 
@@ -107,7 +119,7 @@ func (ctl *ChannelController) Channel(ctx context.Context, webCtx web.Context) w
 
 	data := Channel{Channel: *channel}
 	if data.Id == 0 {
-		types := array.ToMap(ctl.svc.Chat.ChannelTypes(), func(t service.ChannelType, _ int) string {
+		types := array.ToMap(ctl.channelTypes(), func(t service.ChannelType, _ int) string {
 			return t.Name
 		})
 		data.DisplayName = types[channel.Name].Display
@@ -131,7 +143,7 @@ func (ctl *ChannelController) Add(ctx context.Context, webCtx web.Context) web.R
 	}
 
 	allowTypes := array.Map(
-		array.Filter(ctl.svc.Chat.ChannelTypes(), func(item service.ChannelType, _ int) bool { return item.Dynamic }),
+		array.Filter(ctl.channelTypes(), func(item service.ChannelType, _ int) bool { return item.Dynamic }),
 		func(item service.ChannelType, _ int) string { return item.Name },
 	)
 
@@ -0,0 +1,110 @@
+package admin
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/mylxsw/aidea-server/pkg/repo"
+	"github.com/mylxsw/aidea-server/server/controllers/common"
+	"github.com/mylxsw/glacier/infra"
+	"github.com/mylxsw/glacier/web"
+	"github.com/mylxsw/go-utils/array"
+)
+
+type FeedbackController struct {
+	repo *repo.Repository `autowire:"@"`
+}
+
+func NewFeedbackController(resolver infra.Resolver) web.Controller {
+	return infra.Autowire(resolver, &FeedbackController{})
+}
+
+func (ctl *FeedbackController) Register(router web.Router) {
+	router.Group("/feedback", func(router web.Router) {
+		router.Get("/", ctl.Feedbacks)
+		router.Get("/stats", ctl.Stats)
+	})
+}
+
+// Feedbacks Get a list of message feedbacks.
+// @Summary Get a list of message feedbacks.
+// @Tags Admin:Feedback
+// @Produce json
+// @Param page query integer false "Page number" default(1)
+// @Param per_page query integer false "Number of items per page" default(20)
+// @Param model query string false "Model ID"
+// @Param channel query string false "Channel"
+// @Param rating query integer false "Rating: 1 (up) or -1 (down)"
+// @Success 200 {object} common.Pagination[repo.MessageFeedback]
+// @Router /v1/admin/feedback [get]
+func (ctl *FeedbackController) Feedbacks(ctx web.Context) web.Response {
+	page := ctx.Int64Input("page", 1)
+	if page < 1 || page > 1000 {
+		page = 1
+	}
+
+	perPage := ctx.Int64Input("per_page", 20)
+	if perPage < 1 || perPage > 100 {
+		perPage = 20
+	}
+
+	items, meta, err := ctl.repo.Feedback.Feedbacks(ctx, repo.MessageFeedbackFilter{
+		Model:   ctx.Input("model"),
+		Channel: ctx.Input("channel"),
+		Rating:  ctx.Int64Input("rating", 0),
+	}, page, perPage)
+	if err != nil {
+		return ctx.JSONError(err.Error(), http.StatusInternalServerError)
+	}
+
+	return ctx.JSON(common.NewPagination(items, meta))
+}
+
+// Stats Get the aggregated message feedbacks grouped by model, channel or day.
+// @Summary Get the aggregated message feedbacks grouped by model, channel or day.
+// @Tags Admin:Feedback
+// @Produce json
+// @Param dimension query string false "Aggregation dimension: model, channel or day" default(model)
+// @Param start query string false "Start date (inclusive), format: 2006-01-02, default to 30 days ago"
+// @Param end query string false "End date (inclusive), format: 2006-01-02, default to today"
+// @Success 200 {object} common.DataArray[repo.MessageFeedbackStat]
+// @Router /v1/admin/feedback/stats [get]
+func (ctl *FeedbackController) Stats(ctx web.Context) web.Response {
+	dimension := ctx.InputWithDefault("dimension", repo.FeedbackDimensionModel)
+	if !array.In(dimension, []string{repo.FeedbackDimensionModel, repo.FeedbackDimensionChannel, repo.FeedbackDimensionDay}) {
+		return ctx.JSONError("invalid dimension", http.StatusBadRequest)
+	}
+
+	today, _ := time.ParseInLocation("2006-01-02", time.Now().Format("2006-01-02"), time.Local)
+
+	end := today
+	if v := ctx.Input("end"); v != "" {
+		t, err := time.ParseInLocation("2006-01-02", v, time.Local)
+		if err != nil {
+			return ctx.JSONError("invalid end date", http.StatusBadRequest)
+		}
+
+		end = t
+	}
+
+	start := end.AddDate(0, 0, -29)
+	if v := ctx.Input("start"); v != "" {
+		t, err := time.ParseInLocation("2006-01-02", v, time.Local)
+		if err != nil {
+			return ctx.JSONError("invalid start date", http.StatusBadRequest)
+		}
+
+		start = t
+	}
+
+	if start.After(end) || end.Sub(start) > 366*24*time.Hour {
+		return ctx.JSONError("invalid date range", http.StatusBadRequest)
+	}
+
+	stats, err := ctl.repo.Feedback.Stats(ctx, dimension, start, end.AddDate(0, 0, 1))
+	if err != nil {
+		return ctx.JSONError(err.Error(), http.StatusInternalServerError)
+	}
+
+	return ctx.JSON(common.NewDataArray(stats))
+}
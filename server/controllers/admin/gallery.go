@@ -0,0 +1,160 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/mylxsw/aidea-server/pkg/repo"
+	"github.com/mylxsw/aidea-server/pkg/service"
+	"github.com/mylxsw/aidea-server/server/auth"
+	"github.com/mylxsw/aidea-server/server/controllers/common"
+	"github.com/mylxsw/asteria/log"
+	"github.com/mylxsw/glacier/infra"
+	"github.com/mylxsw/glacier/web"
+)
+
+type GalleryController struct {
+	repo       *repo.Repository        `autowire:"@"`
+	gallerySrv *service.GalleryService `autowire:"@"`
+}
+
+func NewGalleryController(resolver infra.Resolver) web.Controller {
+	return infra.Autowire(resolver, &GalleryController{})
+}
+
+func (ctl *GalleryController) Register(router web.Router) {
+	router.Group("/gallery", func(router web.Router) {
+		router.Get("/reports", ctl.Reports)
+		router.Put("/{id}/reports", ctl.HandleReports)
+		router.Put("/{id}/ranking", ctl.UpdateRanking)
+		router.Delete("/comments/{id}", ctl.HideComment)
+	})
+}
+
+// Reports Get the gallery report moderation queue.
+// @Summary Get the gallery report moderation queue.
+// @Tags Admin:Gallery
+// @Produce json
+// @Param status query integer false "Report status: 0 (pending), 1 (accepted), 2 (rejected)" default(0)
+// @Param page query integer false "Page number" default(1)
+// @Param per_page query integer false "Number of items per page" default(20)
+// @Success 200 {object} common.Pagination[repo.GalleryReport]
+// @Router /v1/admin/gallery/reports [get]
+func (ctl *GalleryController) Reports(ctx web.Context) web.Response {
+	page := ctx.Int64Input("page", 1)
+	if page < 1 || page > 1000 {
+		page = 1
+	}
+
+	perPage := ctx.Int64Input("per_page", 20)
+	if perPage < 1 || perPage > 100 {
+		perPage = 20
+	}
+
+	items, meta, err := ctl.repo.Gallery.Reports(ctx, ctx.Int64Input("status", repo.GalleryReportStatusPending), page, perPage)
+	if err != nil {
+		return ctx.JSONError(err.Error(), http.StatusInternalServerError)
+	}
+
+	return ctx.JSON(common.NewPagination(items, meta))
+}
+
+// HandleReports Handle all pending reports of a gallery item.
+// @Summary Handle all pending reports of a gallery item, accepting the reports takes the item down.
+// @Tags Admin:Gallery
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path integer true "Gallery ID"
+// @Param action formData string true "Action: accept or reject"
+// @Success 200 {object} common.EmptyResponse
+// @Router /v1/admin/gallery/{id}/reports [put]
+func (ctl *GalleryController) HandleReports(ctx context.Context, webCtx web.Context, user *auth.User) web.Response {
+	galleryID, err := strconv.Atoi(webCtx.PathVar("id"))
+	if err != nil {
+		return webCtx.JSONError("invalid gallery id", http.StatusBadRequest)
+	}
+
+	action := webCtx.Input("action")
+	if action != "accept" && action != "reject" {
+		return webCtx.JSONError("invalid action", http.StatusBadRequest)
+	}
+
+	handled, err := ctl.repo.Gallery.HandleReports(ctx, user.ID, int64(galleryID), action == "accept")
+	if err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return webCtx.JSONError("no pending reports", http.StatusNotFound)
+		}
+
+		return webCtx.JSONError(err.Error(), http.StatusInternalServerError)
+	}
+
+	// 作品下架后清空图库列表缓存，立即生效
+	if action == "accept" {
+		if err := ctl.gallerySrv.ClearCache(ctx); err != nil {
+			log.F(log.M{"gallery_id": galleryID}).Errorf("清空图库缓存失败: %v", err)
+		}
+	}
+
+	return webCtx.JSON(web.M{"handled": handled})
+}
+
+// UpdateRanking Override the ranking of a gallery item.
+// @Summary Override the ranking of a gallery item, takes effect on the next ranking job.
+// @Tags Admin:Gallery
+// @Accept json
+// @Produce json
+// @Param id path integer true "Gallery ID"
+// @Param req body repo.GalleryRanking true "Hot value boost and star level"
+// @Success 200 {object} common.EmptyResponse
+// @Router /v1/admin/gallery/{id}/ranking [put]
+func (ctl *GalleryController) UpdateRanking(ctx context.Context, webCtx web.Context) web.Response {
+	galleryID, err := strconv.Atoi(webCtx.PathVar("id"))
+	if err != nil {
+		return webCtx.JSONError("invalid gallery id", http.StatusBadRequest)
+	}
+
+	var ranking repo.GalleryRanking
+	if err := webCtx.Unmarshal(&ranking); err != nil {
+		return webCtx.JSONError("invalid request", http.StatusBadRequest)
+	}
+
+	if ranking.StarLevel < 0 || ranking.StarLevel > 5 {
+		return webCtx.JSONError("star level must be between 0 and 5", http.StatusBadRequest)
+	}
+
+	if err := ctl.repo.Gallery.UpdateRanking(ctx, int64(galleryID), ranking); err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return webCtx.JSONError("gallery not found", http.StatusNotFound)
+		}
+
+		return webCtx.JSONError(err.Error(), http.StatusInternalServerError)
+	}
+
+	return webCtx.JSON(web.M{})
+}
+
+// HideComment Hide a gallery comment.
+// @Summary Hide a gallery comment.
+// @Tags Admin:Gallery
+// @Produce json
+// @Param id path integer true "Comment ID"
+// @Success 200 {object} common.EmptyResponse
+// @Router /v1/admin/gallery/comments/{id} [delete]
+func (ctl *GalleryController) HideComment(ctx context.Context, webCtx web.Context) web.Response {
+	commentID, err := strconv.Atoi(webCtx.PathVar("id"))
+	if err != nil {
+		return webCtx.JSONError("invalid comment id", http.StatusBadRequest)
+	}
+
+	if err := ctl.repo.Gallery.DeleteComment(ctx, 0, int64(commentID)); err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return webCtx.JSONError("comment not found", http.StatusNotFound)
+		}
+
+		return webCtx.JSONError(err.Error(), http.StatusInternalServerError)
+	}
+
+	return webCtx.JSON(web.M{})
+}
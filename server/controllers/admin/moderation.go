@@ -0,0 +1,116 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/mylxsw/aidea-server/pkg/moderation"
+	"github.com/mylxsw/aidea-server/pkg/repo"
+	"github.com/mylxsw/aidea-server/server/controllers/common"
+	"github.com/mylxsw/glacier/infra"
+	"github.com/mylxsw/glacier/web"
+	"github.com/mylxsw/go-utils/array"
+)
+
+type ModerationController struct {
+	repo    *repo.SettingRepo           `autowire:"@"`
+	keyword *moderation.KeywordProvider `autowire:"@"`
+	chain   *moderation.Chain           `autowire:"@"`
+}
+
+func NewModerationController(resolver infra.Resolver) web.Controller {
+	return infra.Autowire(resolver, &ModerationController{})
+}
+
+func (ctl *ModerationController) Register(router web.Router) {
+	router.Group("/moderation", func(router web.Router) {
+		router.Get("/words", ctl.Words)
+		router.Put("/words", ctl.UpdateWords)
+		router.Post("/check", ctl.Check)
+	})
+}
+
+// Words Get the local moderation word list.
+// @Summary Get the local moderation word list, entries prefixed with re: are regular expressions.
+// @Tags Admin:Moderation
+// @Produce json
+// @Success 200 {object} common.DataArray[string]
+// @Router /v1/admin/moderation/words [get]
+func (ctl *ModerationController) Words(ctx context.Context, webCtx web.Context) web.Response {
+	data, err := ctl.repo.Get(ctx, moderation.SettingKeyWords)
+	if err != nil && !errors.Is(err, repo.ErrNotFound) {
+		return webCtx.JSONError(err.Error(), http.StatusInternalServerError)
+	}
+
+	words := make([]string, 0)
+	if err == nil {
+		if err := json.Unmarshal([]byte(data), &words); err != nil {
+			return webCtx.JSONError("invalid word list setting", http.StatusInternalServerError)
+		}
+	}
+
+	return webCtx.JSON(common.NewDataArray(words))
+}
+
+type UpdateWordsRequest struct {
+	Words []string `json:"words"`
+}
+
+// UpdateWords Replace the local moderation word list.
+// @Summary Replace the local moderation word list, takes effect immediately on this instance and within a minute on others.
+// @Tags Admin:Moderation
+// @Accept json
+// @Produce json
+// @Param req body UpdateWordsRequest true "Word list"
+// @Success 200 {object} common.EmptyResponse
+// @Router /v1/admin/moderation/words [put]
+func (ctl *ModerationController) UpdateWords(ctx context.Context, webCtx web.Context) web.Response {
+	var req UpdateWordsRequest
+	if err := webCtx.Unmarshal(&req); err != nil {
+		return webCtx.JSONError("invalid request", http.StatusBadRequest)
+	}
+
+	words := array.Uniq(array.Filter(
+		array.Map(req.Words, func(word string, _ int) string { return strings.TrimSpace(word) }),
+		func(word string, _ int) bool { return word != "" },
+	))
+
+	data, err := json.Marshal(words)
+	if err != nil {
+		return webCtx.JSONError(err.Error(), http.StatusInternalServerError)
+	}
+
+	if err := ctl.repo.Set(ctx, moderation.SettingKeyWords, string(data)); err != nil {
+		return webCtx.JSONError(err.Error(), http.StatusInternalServerError)
+	}
+
+	ctl.keyword.Reload(words)
+
+	return webCtx.JSON(common.EmptyResponse{})
+}
+
+// Check Check content against the moderation policy of a scene.
+// @Summary Check content against the moderation policy of a scene, ignores the enable-contentdetect switch.
+// @Tags Admin:Moderation
+// @Accept multipart/form-data
+// @Produce json
+// @Param scene formData string true "Scene: nickname, prompt or chat"
+// @Param content formData string true "Content to check"
+// @Success 200 {object} common.DataObj[moderation.Result]
+// @Router /v1/admin/moderation/check [post]
+func (ctl *ModerationController) Check(ctx context.Context, webCtx web.Context) web.Response {
+	scene := moderation.Scene(webCtx.Input("scene"))
+	if !array.In(scene, []moderation.Scene{moderation.SceneNickname, moderation.ScenePrompt, moderation.SceneChat}) {
+		return webCtx.JSONError("invalid scene", http.StatusBadRequest)
+	}
+
+	content := webCtx.Input("content")
+	if content == "" {
+		return webCtx.JSONError("content is required", http.StatusBadRequest)
+	}
+
+	return webCtx.JSON(common.NewDataObj(ctl.chain.Check(ctx, scene, content)))
+}
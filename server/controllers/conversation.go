@@ -0,0 +1,362 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mylxsw/aidea-server/config"
+	"github.com/mylxsw/aidea-server/pkg/conversation"
+	"github.com/mylxsw/aidea-server/pkg/misc"
+	"github.com/mylxsw/aidea-server/pkg/repo"
+	"github.com/mylxsw/aidea-server/pkg/repo/model"
+	"github.com/mylxsw/aidea-server/pkg/service"
+	"github.com/mylxsw/aidea-server/pkg/uploader"
+	"github.com/mylxsw/aidea-server/server/auth"
+	"github.com/mylxsw/aidea-server/server/controllers/common"
+	"github.com/mylxsw/asteria/log"
+	"github.com/mylxsw/glacier/infra"
+	"github.com/mylxsw/glacier/web"
+	"github.com/mylxsw/go-utils/ternary"
+)
+
+// ConversationController 聊天记录导入导出
+type ConversationController struct {
+	conf     *config.Config     `autowire:"@"`
+	repo     *repo.Repository   `autowire:"@"`
+	svc      *service.Service   `autowire:"@"`
+	uploader *uploader.Uploader `autowire:"@"`
+}
+
+func NewConversationController(resolver infra.Resolver) web.Controller {
+	ctl := ConversationController{}
+	resolver.MustAutoWire(&ctl)
+	return &ctl
+}
+
+func (ctl *ConversationController) Register(router web.Router) {
+	router.Group("/conversations", func(router web.Router) {
+		router.Get("/export", ctl.Export)
+		router.Post("/import/chatgpt", ctl.ImportChatGPT)
+	})
+}
+
+const (
+	// conversationExportURLTTL 导出文件中图片、附件签名 URL 的有效期
+	conversationExportURLTTL = 7 * 24 * time.Hour
+	// conversationImportMaxSize ChatGPT 导出文件大小限制
+	conversationImportMaxSize = 50 * 1024 * 1024
+	// conversationImportMaxRooms 单次最多导入的会话数量
+	conversationImportMaxRooms = 200
+)
+
+// Export 导出聊天记录
+// @Summary 导出聊天记录，支持导出单个数字人、全部数字人或者群聊
+// @Tags Conversation
+// @Produce octet-stream
+// @Param format query string false "导出格式：markdown/json/html，默认 markdown"
+// @Param room_id query int false "数字人 ID，导出单个数字人的聊天记录"
+// @Param group_id query int false "群聊 ID，导出单个群聊的聊天记录"
+// @Param scope query string false "导出范围：rooms/groups，未指定 room_id 和 group_id 时有效，默认 rooms"
+// @Success 200 {file} file
+// @Router /v1/conversations/export [get]
+func (ctl *ConversationController) Export(ctx context.Context, webCtx web.Context, user *auth.User) web.Response {
+	format := ternary.If(webCtx.Input("format") != "", webCtx.Input("format"), conversation.FormatMarkdown)
+	if !conversation.ValidFormat(format) {
+		return webCtx.JSONError("invalid format", http.StatusBadRequest)
+	}
+
+	roomID := webCtx.Int64Input("room_id", 0)
+	groupID := webCtx.Int64Input("group_id", 0)
+	scope := ternary.If(webCtx.Input("scope") != "", webCtx.Input("scope"), "rooms")
+
+	var convs []conversation.Conversation
+	var err error
+	switch {
+	case roomID > 0:
+		var conv *conversation.Conversation
+		conv, err = ctl.exportRoom(ctx, user.ID, roomID)
+		if conv != nil {
+			convs = append(convs, *conv)
+		}
+	case groupID > 0:
+		var conv *conversation.Conversation
+		conv, err = ctl.exportGroup(ctx, user.ID, groupID)
+		if conv != nil {
+			convs = append(convs, *conv)
+		}
+	case scope == "rooms":
+		convs, err = ctl.exportAllRooms(ctx, user.ID)
+	case scope == "groups":
+		convs, err = ctl.exportAllGroups(ctx, user.ID)
+	default:
+		return webCtx.JSONError("invalid scope", http.StatusBadRequest)
+	}
+
+	if err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return webCtx.JSONError(common.ErrNotFound, http.StatusNotFound)
+		}
+
+		log.F(log.M{"user_id": user.ID, "room_id": roomID, "group_id": groupID}).Errorf("export conversations failed: %v", err)
+		return webCtx.JSONError(common.ErrInternalError, http.StatusInternalServerError)
+	}
+
+	data, err := conversation.Render(format, conversation.Export{ExportedAt: time.Now(), Conversations: convs})
+	if err != nil {
+		log.F(log.M{"user_id": user.ID}).Errorf("render conversations failed: %v", err)
+		return webCtx.JSONError(common.ErrInternalError, http.StatusInternalServerError)
+	}
+
+	contentType, ext := conversation.ContentType(format)
+	filename := fmt.Sprintf("aidea-conversations-%s.%s", time.Now().Format("20060102150405"), ext)
+
+	return webCtx.Raw(func(w http.ResponseWriter) {
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+		_, _ = w.Write(data)
+	})
+}
+
+// signURL 为存储在对象存储中的文件生成带签名的 URL，其它 URL 原样返回
+func (ctl *ConversationController) signURL(u string) string {
+	prefix := strings.TrimSuffix(ctl.conf.StorageDomain, "/") + "/"
+	if ctl.conf.StorageDomain == "" || !strings.HasPrefix(u, prefix) {
+		return u
+	}
+
+	key := strings.TrimPrefix(u, prefix)
+	if idx := strings.Index(key, "?"); idx >= 0 {
+		key = key[:idx]
+	}
+
+	return ctl.uploader.MakePrivateURL(key, conversationExportURLTTL)
+}
+
+func (ctl *ConversationController) exportRoom(ctx context.Context, userID, roomID int64) (*conversation.Conversation, error) {
+	room, err := ctl.repo.Room.Room(ctx, userID, roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := ctl.repo.Message.RoomMessages(ctx, userID, roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	conv := conversation.Conversation{
+		ID:           roomID,
+		Type:         conversation.TypeChat,
+		Title:        room.Name,
+		Model:        room.Model,
+		SystemPrompt: room.SystemPrompt,
+		CreatedAt:    room.CreatedAt,
+		Messages:     make([]conversation.Message, 0, len(messages)),
+	}
+
+	for _, msg := range messages {
+		item := conversation.Message{
+			Role:      ternary.If(repo.MessageRole(msg.Role) == repo.MessageRoleUser, conversation.RoleUser, conversation.RoleAssistant),
+			Model:     msg.Model,
+			Content:   msg.Message,
+			CreatedAt: msg.CreatedAt,
+		}
+
+		if msg.Meta != "" {
+			var meta repo.MessageMeta
+			if err := json.Unmarshal([]byte(msg.Meta), &meta); err == nil {
+				for _, img := range meta.Images {
+					item.Images = append(item.Images, ctl.signURL(img))
+				}
+
+				if meta.FileURL != "" {
+					item.File = ctl.signURL(meta.FileURL)
+				}
+			}
+		}
+
+		conv.Messages = append(conv.Messages, item)
+	}
+
+	if conv.CreatedAt.IsZero() && len(conv.Messages) > 0 {
+		conv.CreatedAt = conv.Messages[0].CreatedAt
+	}
+
+	return &conv, nil
+}
+
+func (ctl *ConversationController) exportAllRooms(ctx context.Context, userID int64) ([]conversation.Conversation, error) {
+	rooms, err := ctl.repo.Room.Rooms(ctx, userID, []int{repo.RoomTypePreset, repo.RoomTypeCustom, repo.RoomTypePresetCustom}, RoomsQueryLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	// 默认房间不在数据库中，需要单独导出
+	roomIDs := []int64{1}
+	for _, room := range rooms {
+		roomIDs = append(roomIDs, room.Id)
+	}
+
+	convs := make([]conversation.Conversation, 0, len(roomIDs))
+	for _, roomID := range roomIDs {
+		conv, err := ctl.exportRoom(ctx, userID, roomID)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(conv.Messages) > 0 {
+			convs = append(convs, *conv)
+		}
+	}
+
+	return convs, nil
+}
+
+func (ctl *ConversationController) exportGroup(ctx context.Context, userID, groupID int64) (*conversation.Conversation, error) {
+	grp, err := ctl.repo.ChatGroup.GetGroup(ctx, groupID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := ctl.repo.ChatGroup.ExportChatMessages(ctx, groupID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	members := make(map[int64]model.ChatGroupMember)
+	for _, mem := range grp.Members {
+		members[mem.Id] = mem
+	}
+
+	conv := conversation.Conversation{
+		ID:        groupID,
+		Type:      conversation.TypeGroupChat,
+		Title:     grp.Group.Name,
+		CreatedAt: grp.Group.CreatedAt,
+		Messages:  make([]conversation.Message, 0, len(messages)),
+	}
+
+	for _, msg := range messages {
+		item := conversation.Message{
+			Role:      ternary.If(repo.MessageRole(msg.Role) == repo.MessageRoleUser, conversation.RoleUser, conversation.RoleAssistant),
+			Content:   msg.Message,
+			CreatedAt: msg.CreatedAt,
+		}
+
+		if mem, ok := members[msg.MemberId]; ok {
+			item.Model = mem.ModelId
+			item.Author = ternary.If(mem.ModelName != "", mem.ModelName, mem.ModelId)
+		}
+
+		conv.Messages = append(conv.Messages, item)
+	}
+
+	return &conv, nil
+}
+
+func (ctl *ConversationController) exportAllGroups(ctx context.Context, userID int64) ([]conversation.Conversation, error) {
+	groups, err := ctl.repo.ChatGroup.Groups(ctx, userID, RoomsQueryLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	convs := make([]conversation.Conversation, 0, len(groups))
+	for _, grp := range groups {
+		conv, err := ctl.exportGroup(ctx, userID, grp.Id)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(conv.Messages) > 0 {
+			convs = append(convs, *conv)
+		}
+	}
+
+	return convs, nil
+}
+
+type ConversationImportResponse struct {
+	RoomIDs  []int64 `json:"room_ids"`
+	Messages int64   `json:"messages"`
+}
+
+// ImportChatGPT 导入 ChatGPT 导出的聊天记录（conversations.json），每个会话创建一个新的数字人
+// @Summary 导入 ChatGPT 导出的聊天记录
+// @Tags Conversation
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "ChatGPT 导出的 conversations.json 文件"
+// @Success 200 {object} ConversationImportResponse
+// @Router /v1/conversations/import/chatgpt [post]
+func (ctl *ConversationController) ImportChatGPT(ctx context.Context, webCtx web.Context, user *auth.User) web.Response {
+	uploadedFile, err := webCtx.File("file")
+	if err != nil {
+		log.F(log.M{"user_id": user.ID}).Errorf("upload file failed: %s", err)
+		return webCtx.JSONError(common.ErrInvalidRequest, http.StatusBadRequest)
+	}
+	defer func() { misc.NoError(uploadedFile.Delete()) }()
+
+	if uploadedFile.Size() > conversationImportMaxSize {
+		return webCtx.JSONError(common.ErrFileTooLarge, http.StatusBadRequest)
+	}
+
+	f, err := os.Open(uploadedFile.SavePath)
+	if err != nil {
+		log.F(log.M{"user_id": user.ID}).Errorf("open uploaded file failed: %s", err)
+		return webCtx.JSONError(common.ErrInternalError, http.StatusInternalServerError)
+	}
+	defer f.Close()
+
+	convs, err := conversation.ParseChatGPTExport(f)
+	if err != nil {
+		return webCtx.JSONError(err.Error(), http.StatusBadRequest)
+	}
+
+	if len(convs) > conversationImportMaxRooms {
+		convs = convs[:conversationImportMaxRooms]
+	}
+
+	resp := ConversationImportResponse{RoomIDs: make([]int64, 0, len(convs))}
+	for _, conv := range convs {
+		// 模型不存在时使用默认模型，保证导入的数字人可以继续对话
+		roomModel := ctl.conf.DefaultRoleModel
+		if conv.Model != "" && ctl.svc.Chat.Model(ctx, conv.Model) != nil {
+			roomModel = conv.Model
+		}
+
+		room := model.Rooms{
+			Name:        misc.SubString(conv.Title, 27),
+			Description: "Imported from ChatGPT",
+			Model:       roomModel,
+			Vendor:      "openai",
+			MaxContext:  5,
+			RoomType:    repo.RoomTypeCustom,
+		}
+
+		items := make([]repo.MessageImportItem, 0, len(conv.Messages))
+		for _, msg := range conv.Messages {
+			items = append(items, repo.MessageImportItem{
+				Role:      ternary.If(msg.Role == conversation.RoleUser, repo.MessageRoleUser, repo.MessageRoleAssistant),
+				Message:   msg.Content,
+				Model:     ternary.If(msg.Model != "", msg.Model, roomModel),
+				CreatedAt: msg.CreatedAt,
+			})
+		}
+
+		roomID, err := ctl.repo.Message.ImportRoom(ctx, user.ID, room, items)
+		if err != nil {
+			log.F(log.M{"user_id": user.ID, "title": conv.Title}).Errorf("import chatgpt conversation failed: %v", err)
+			return webCtx.JSONError(common.ErrInternalError, http.StatusInternalServerError)
+		}
+
+		resp.RoomIDs = append(resp.RoomIDs, roomID)
+		resp.Messages += int64(len(items))
+	}
+
+	return webCtx.JSON(resp)
+}
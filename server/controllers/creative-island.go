@@ -145,8 +145,8 @@ func (ctl *CreativeIslandController) histories(ctx context.Context, webCtx web.C
 
 	return webCtx.JSON(web.M{
 		"data": array.Map(items, func(item repo.CreativeHistoryItem, _ int) repo.CreativeHistoryItem {
-			// 客户端目前不支持封禁状态展示，这里转换为失败
-			if item.Status == int64(repo.CreativeStatusForbid) {
+			// 客户端目前不支持封禁、取消状态展示，这里转换为失败
+			if item.Status == int64(repo.CreativeStatusForbid) || item.Status == int64(repo.CreativeStatusCancelled) {
 				item.Status = int64(repo.CreativeStatusFailed)
 			}
 
@@ -177,8 +177,8 @@ func (ctl *CreativeIslandController) itemHistories(ctx context.Context, webCtx w
 
 	return webCtx.JSON(web.M{
 		"data": array.Map(items, func(item repo.CreativeHistoryItem, _ int) repo.CreativeHistoryItem {
-			// 客户端目前不支持封禁状态展示，这里转换为失败
-			if item.Status == int64(repo.CreativeStatusForbid) {
+			// 客户端目前不支持封禁、取消状态展示，这里转换为失败
+			if item.Status == int64(repo.CreativeStatusForbid) || item.Status == int64(repo.CreativeStatusCancelled) {
 				item.Status = int64(repo.CreativeStatusFailed)
 			}
 
@@ -209,8 +209,8 @@ func (ctl *CreativeIslandController) historyItem(ctx context.Context, webCtx web
 		return webCtx.JSONError(common.Text(webCtx, ctl.trans, common.ErrInternalError), http.StatusInternalServerError)
 	}
 
-	// 客户端目前不支持封禁状态展示，这里转换为失败
-	if item.Status == int64(repo.CreativeStatusForbid) {
+	// 客户端目前不支持封禁、取消状态展示，这里转换为失败
+	if item.Status == int64(repo.CreativeStatusForbid) || item.Status == int64(repo.CreativeStatusCancelled) {
 		item.Status = int64(repo.CreativeStatusFailed)
 	}
 
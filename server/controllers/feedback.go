@@ -0,0 +1,164 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/mylxsw/aidea-server/pkg/repo"
+	"github.com/mylxsw/aidea-server/pkg/youdao"
+	"github.com/mylxsw/aidea-server/server/auth"
+	"github.com/mylxsw/aidea-server/server/controllers/common"
+	"github.com/mylxsw/asteria/log"
+	"github.com/mylxsw/glacier/infra"
+	"github.com/mylxsw/glacier/web"
+	"github.com/mylxsw/go-utils/array"
+)
+
+// FeedbackController 用户对模型回复的评价（赞/踩）
+type FeedbackController struct {
+	repo       *repo.Repository  `autowire:"@"`
+	translater youdao.Translater `autowire:"@"`
+}
+
+func NewFeedbackController(resolver infra.Resolver) web.Controller {
+	ctl := FeedbackController{}
+	resolver.MustAutoWire(&ctl)
+	return &ctl
+}
+
+func (ctl *FeedbackController) Register(router web.Router) {
+	router.Group("/feedback", func(router web.Router) {
+		router.Get("/reasons", ctl.Reasons)
+
+		router.Get("/{message_type}/{message_id}", ctl.Feedback)
+		router.Put("/{message_type}/{message_id}", ctl.SaveFeedback)
+		router.Delete("/{message_type}/{message_id}", ctl.DeleteFeedback)
+	})
+}
+
+// FeedbackReasons 评价可选的原因分类
+type FeedbackReasons struct {
+	Up   []string `json:"up"`
+	Down []string `json:"down"`
+}
+
+// Reasons 获取评价可选的原因分类
+// @Summary 获取评价可选的原因分类
+// @Tags Feedback
+// @Success 200 {object} common.DataObj[FeedbackReasons]
+// @Router /v1/feedback/reasons [get]
+func (ctl *FeedbackController) Reasons(webCtx web.Context) web.Response {
+	return webCtx.JSON(common.NewDataObj(FeedbackReasons{
+		Up:   repo.FeedbackReasons(repo.FeedbackRatingUp),
+		Down: repo.FeedbackReasons(repo.FeedbackRatingDown),
+	}))
+}
+
+// parseFeedbackTarget 解析被评价的消息类型以及消息 ID
+func parseFeedbackTarget(webCtx web.Context) (string, int64, error) {
+	messageType := webCtx.PathVar("message_type")
+	if !array.In(messageType, []string{repo.FeedbackMessageTypeChat, repo.FeedbackMessageTypeGroup}) {
+		return "", 0, errors.New("invalid message type")
+	}
+
+	messageID, err := strconv.Atoi(webCtx.PathVar("message_id"))
+	if err != nil || messageID <= 0 {
+		return "", 0, errors.New("invalid message id")
+	}
+
+	return messageType, int64(messageID), nil
+}
+
+// Feedback 获取用户对消息的评价
+// @Summary 获取用户对消息的评价
+// @Tags Feedback
+// @Param message_type path string true "消息类型：chat/group"
+// @Param message_id path int true "消息 ID"
+// @Success 200 {object} common.DataObj[repo.MessageFeedback]
+// @Router /v1/feedback/{message_type}/{message_id} [get]
+func (ctl *FeedbackController) Feedback(ctx context.Context, webCtx web.Context, user *auth.User) web.Response {
+	messageType, messageID, err := parseFeedbackTarget(webCtx)
+	if err != nil {
+		return webCtx.JSONError(err.Error(), http.StatusBadRequest)
+	}
+
+	feedback, err := ctl.repo.Feedback.Feedback(ctx, user.ID, messageType, messageID)
+	if err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return webCtx.JSONError(common.Text(webCtx, ctl.translater, "尚未评价"), http.StatusNotFound)
+		}
+
+		log.F(log.M{"user_id": user.ID, "message_type": messageType, "message_id": messageID}).Errorf("查询消息评价失败: %v", err)
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInternalError), http.StatusInternalServerError)
+	}
+
+	// 模型以及渠道信息仅用于质量分析，不返回给用户
+	feedback.Model, feedback.Channel = "", ""
+
+	return webCtx.JSON(common.NewDataObj(feedback))
+}
+
+// SaveFeedback 评价消息，重复评价时覆盖之前的评价
+// @Summary 评价消息
+// @Tags Feedback
+// @Accept json
+// @Param message_type path string true "消息类型：chat/group"
+// @Param message_id path int true "消息 ID"
+// @Param req body repo.MessageFeedbackInput true "评价内容：rating 为 1（赞）或者 -1（踩）"
+// @Success 200 {object} common.EmptyResponse
+// @Router /v1/feedback/{message_type}/{message_id} [put]
+func (ctl *FeedbackController) SaveFeedback(ctx context.Context, webCtx web.Context, user *auth.User) web.Response {
+	messageType, messageID, err := parseFeedbackTarget(webCtx)
+	if err != nil {
+		return webCtx.JSONError(err.Error(), http.StatusBadRequest)
+	}
+
+	var in repo.MessageFeedbackInput
+	if err := webCtx.Unmarshal(&in); err != nil {
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInvalidRequest), http.StatusBadRequest)
+	}
+
+	in.Comment = strings.TrimSpace(in.Comment)
+	if err := in.Validate(); err != nil {
+		return webCtx.JSONError(err.Error(), http.StatusBadRequest)
+	}
+
+	if err := ctl.repo.Feedback.Save(ctx, user.ID, messageType, messageID, in); err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return webCtx.JSONError(common.Text(webCtx, ctl.translater, "消息不存在"), http.StatusNotFound)
+		}
+
+		if errors.Is(err, repo.ErrFeedbackNotAllowed) {
+			return webCtx.JSONError(common.Text(webCtx, ctl.translater, "只能评价 AI 回复的消息"), http.StatusBadRequest)
+		}
+
+		log.F(log.M{"user_id": user.ID, "message_type": messageType, "message_id": messageID}).Errorf("保存消息评价失败: %v", err)
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInternalError), http.StatusInternalServerError)
+	}
+
+	return webCtx.JSON(web.M{})
+}
+
+// DeleteFeedback 撤销对消息的评价
+// @Summary 撤销对消息的评价
+// @Tags Feedback
+// @Param message_type path string true "消息类型：chat/group"
+// @Param message_id path int true "消息 ID"
+// @Success 200 {object} common.EmptyResponse
+// @Router /v1/feedback/{message_type}/{message_id} [delete]
+func (ctl *FeedbackController) DeleteFeedback(ctx context.Context, webCtx web.Context, user *auth.User) web.Response {
+	messageType, messageID, err := parseFeedbackTarget(webCtx)
+	if err != nil {
+		return webCtx.JSONError(err.Error(), http.StatusBadRequest)
+	}
+
+	if err := ctl.repo.Feedback.Delete(ctx, user.ID, messageType, messageID); err != nil {
+		log.F(log.M{"user_id": user.ID, "message_type": messageType, "message_id": messageID}).Errorf("撤销消息评价失败: %v", err)
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInternalError), http.StatusInternalServerError)
+	}
+
+	return webCtx.JSON(web.M{})
+}
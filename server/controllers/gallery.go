@@ -0,0 +1,329 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mylxsw/aidea-server/pkg/repo"
+	"github.com/mylxsw/aidea-server/pkg/service"
+	"github.com/mylxsw/aidea-server/pkg/youdao"
+	"github.com/mylxsw/aidea-server/server/auth"
+	"github.com/mylxsw/aidea-server/server/controllers/common"
+	"github.com/mylxsw/asteria/log"
+	"github.com/mylxsw/glacier/infra"
+	"github.com/mylxsw/glacier/web"
+	"github.com/mylxsw/go-utils/array"
+)
+
+// GalleryController 创作岛作品图库的点赞、收藏、评论以及举报
+type GalleryController struct {
+	repo        *repo.Repository         `autowire:"@"`
+	translater  youdao.Translater        `autowire:"@"`
+	securitySrv *service.SecurityService `autowire:"@"`
+}
+
+func NewGalleryController(resolver infra.Resolver) web.Controller {
+	ctl := GalleryController{}
+	resolver.MustAutoWire(&ctl)
+	return &ctl
+}
+
+func (ctl *GalleryController) Register(router web.Router) {
+	router.Group("/galleries", func(router web.Router) {
+		router.Get("/favorites", ctl.Favorites)
+		router.Get("/report-reasons", ctl.ReportReasons)
+
+		router.Get("/{id}/interactions", ctl.Interactions)
+		router.Post("/{id}/like", ctl.Like)
+		router.Delete("/{id}/like", ctl.Unlike)
+		router.Post("/{id}/favorite", ctl.Favorite)
+		router.Delete("/{id}/favorite", ctl.Unfavorite)
+
+		router.Get("/{id}/comments", ctl.Comments)
+		router.Post("/{id}/comments", ctl.AddComment)
+		router.Delete("/{id}/comments/{comment_id}", ctl.DeleteComment)
+
+		router.Post("/{id}/report", ctl.Report)
+	})
+}
+
+// GalleryInteractions 用户对作品的互动状态
+type GalleryInteractions struct {
+	Liked     bool `json:"liked"`
+	Favorited bool `json:"favorited"`
+}
+
+// Interactions 查询当前用户对作品的点赞、收藏状态
+// @Summary 查询当前用户对作品的点赞、收藏状态
+// @Tags Gallery
+// @Param id path int true "作品 ID"
+// @Success 200 {object} common.DataObj[GalleryInteractions]
+// @Router /v1/galleries/{id}/interactions [get]
+func (ctl *GalleryController) Interactions(ctx context.Context, webCtx web.Context, user *auth.User) web.Response {
+	galleryID, err := strconv.Atoi(webCtx.PathVar("id"))
+	if err != nil {
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInvalidRequest), http.StatusBadRequest)
+	}
+
+	types, err := ctl.repo.Gallery.UserInteractions(ctx, user.ID, int64(galleryID))
+	if err != nil {
+		log.F(log.M{"user_id": user.ID, "gallery_id": galleryID}).Errorf("查询作品互动状态失败: %v", err)
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInternalError), http.StatusInternalServerError)
+	}
+
+	return webCtx.JSON(common.NewDataObj(GalleryInteractions{
+		Liked:     array.In(repo.GalleryInteractionLike, types),
+		Favorited: array.In(repo.GalleryInteractionFavorite, types),
+	}))
+}
+
+// Like 点赞作品
+// @Summary 点赞作品
+// @Tags Gallery
+// @Param id path int true "作品 ID"
+// @Success 200 {object} common.EmptyResponse
+// @Router /v1/galleries/{id}/like [post]
+func (ctl *GalleryController) Like(ctx context.Context, webCtx web.Context, user *auth.User) web.Response {
+	return ctl.interact(ctx, webCtx, user, repo.GalleryInteractionLike, true)
+}
+
+// Unlike 取消点赞
+// @Summary 取消点赞
+// @Tags Gallery
+// @Param id path int true "作品 ID"
+// @Success 200 {object} common.EmptyResponse
+// @Router /v1/galleries/{id}/like [delete]
+func (ctl *GalleryController) Unlike(ctx context.Context, webCtx web.Context, user *auth.User) web.Response {
+	return ctl.interact(ctx, webCtx, user, repo.GalleryInteractionLike, false)
+}
+
+// Favorite 收藏作品
+// @Summary 收藏作品
+// @Tags Gallery
+// @Param id path int true "作品 ID"
+// @Success 200 {object} common.EmptyResponse
+// @Router /v1/galleries/{id}/favorite [post]
+func (ctl *GalleryController) Favorite(ctx context.Context, webCtx web.Context, user *auth.User) web.Response {
+	return ctl.interact(ctx, webCtx, user, repo.GalleryInteractionFavorite, true)
+}
+
+// Unfavorite 取消收藏
+// @Summary 取消收藏
+// @Tags Gallery
+// @Param id path int true "作品 ID"
+// @Success 200 {object} common.EmptyResponse
+// @Router /v1/galleries/{id}/favorite [delete]
+func (ctl *GalleryController) Unfavorite(ctx context.Context, webCtx web.Context, user *auth.User) web.Response {
+	return ctl.interact(ctx, webCtx, user, repo.GalleryInteractionFavorite, false)
+}
+
+// interact 点赞、收藏以及取消，重复操作不会报错
+func (ctl *GalleryController) interact(ctx context.Context, webCtx web.Context, user *auth.User, typ string, add bool) web.Response {
+	galleryID, err := strconv.Atoi(webCtx.PathVar("id"))
+	if err != nil {
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInvalidRequest), http.StatusBadRequest)
+	}
+
+	if add {
+		_, err = ctl.repo.Gallery.AddInteraction(ctx, user.ID, int64(galleryID), typ)
+	} else {
+		_, err = ctl.repo.Gallery.RemoveInteraction(ctx, user.ID, int64(galleryID), typ)
+	}
+
+	if err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return webCtx.JSONError(common.Text(webCtx, ctl.translater, "作品不存在"), http.StatusNotFound)
+		}
+
+		log.F(log.M{"user_id": user.ID, "gallery_id": galleryID, "type": typ, "add": add}).Errorf("更新作品互动失败: %v", err)
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInternalError), http.StatusInternalServerError)
+	}
+
+	return webCtx.JSON(web.M{})
+}
+
+// Favorites 当前用户收藏的作品列表
+// @Summary 当前用户收藏的作品列表
+// @Tags Gallery
+// @Param page query int false "页码" default(1)
+// @Param per_page query int false "每页数量" default(20)
+// @Success 200 {object} common.Pagination[model.CreativeGallery]
+// @Router /v1/galleries/favorites [get]
+func (ctl *GalleryController) Favorites(ctx context.Context, webCtx web.Context, user *auth.User) web.Response {
+	page, perPage := galleryPagination(webCtx)
+
+	items, meta, err := ctl.repo.Gallery.Favorites(ctx, user.ID, page, perPage)
+	if err != nil {
+		log.F(log.M{"user_id": user.ID}).Errorf("查询收藏的作品失败: %v", err)
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInternalError), http.StatusInternalServerError)
+	}
+
+	return webCtx.JSON(common.NewPagination(items, meta))
+}
+
+// Comments 作品的评论列表
+// @Summary 作品的评论列表
+// @Tags Gallery
+// @Param id path int true "作品 ID"
+// @Param page query int false "页码" default(1)
+// @Param per_page query int false "每页数量" default(20)
+// @Success 200 {object} common.Pagination[repo.GalleryComment]
+// @Router /v1/galleries/{id}/comments [get]
+func (ctl *GalleryController) Comments(ctx context.Context, webCtx web.Context) web.Response {
+	galleryID, err := strconv.Atoi(webCtx.PathVar("id"))
+	if err != nil {
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInvalidRequest), http.StatusBadRequest)
+	}
+
+	page, perPage := galleryPagination(webCtx)
+
+	items, meta, err := ctl.repo.Gallery.Comments(ctx, int64(galleryID), page, perPage)
+	if err != nil {
+		log.F(log.M{"gallery_id": galleryID}).Errorf("查询作品评论失败: %v", err)
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInternalError), http.StatusInternalServerError)
+	}
+
+	return webCtx.JSON(common.NewPagination(items, meta))
+}
+
+// AddComment 评论作品
+// @Summary 评论作品
+// @Tags Gallery
+// @Accept multipart/form-data
+// @Param id path int true "作品 ID"
+// @Param content formData string true "评论内容"
+// @Success 200 {object} common.DataObj[repo.GalleryComment]
+// @Router /v1/galleries/{id}/comments [post]
+func (ctl *GalleryController) AddComment(ctx context.Context, webCtx web.Context, user *auth.User) web.Response {
+	galleryID, err := strconv.Atoi(webCtx.PathVar("id"))
+	if err != nil {
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInvalidRequest), http.StatusBadRequest)
+	}
+
+	content := strings.TrimSpace(webCtx.Input("content"))
+	if content == "" {
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, "评论内容不能为空"), http.StatusBadRequest)
+	}
+
+	if utf8.RuneCountInString(content) > repo.GalleryCommentMaxLength {
+		return webCtx.JSONError(fmt.Sprintf(common.Text(webCtx, ctl.translater, "评论内容不能超过 %d 个字符"), repo.GalleryCommentMaxLength), http.StatusBadRequest)
+	}
+
+	// 内容安全检测
+	if checkRes := ctl.securitySrv.ChatDetect(content); checkRes != nil && checkRes.IsReallyUnSafe() {
+		log.F(log.M{"user_id": user.ID, "details": checkRes.ReasonDetail(), "content": content}).Warningf("用户 %d 评论违规，违规内容：%s", user.ID, checkRes.Reason)
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, "内容违规，已被系统拦截"), http.StatusNotAcceptable)
+	}
+
+	id, err := ctl.repo.Gallery.AddComment(ctx, user.ID, user.Name, int64(galleryID), content)
+	if err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return webCtx.JSONError(common.Text(webCtx, ctl.translater, "作品不存在"), http.StatusNotFound)
+		}
+
+		log.F(log.M{"user_id": user.ID, "gallery_id": galleryID}).Errorf("评论作品失败: %v", err)
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInternalError), http.StatusInternalServerError)
+	}
+
+	return webCtx.JSON(common.NewDataObj(repo.GalleryComment{
+		ID:        id,
+		GalleryID: int64(galleryID),
+		UserID:    user.ID,
+		Username:  user.Name,
+		Content:   content,
+	}))
+}
+
+// DeleteComment 删除自己的评论
+// @Summary 删除自己的评论
+// @Tags Gallery
+// @Param id path int true "作品 ID"
+// @Param comment_id path int true "评论 ID"
+// @Success 200 {object} common.EmptyResponse
+// @Router /v1/galleries/{id}/comments/{comment_id} [delete]
+func (ctl *GalleryController) DeleteComment(ctx context.Context, webCtx web.Context, user *auth.User) web.Response {
+	commentID, err := strconv.Atoi(webCtx.PathVar("comment_id"))
+	if err != nil {
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInvalidRequest), http.StatusBadRequest)
+	}
+
+	if err := ctl.repo.Gallery.DeleteComment(ctx, user.ID, int64(commentID)); err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return webCtx.JSONError(common.Text(webCtx, ctl.translater, "评论不存在"), http.StatusNotFound)
+		}
+
+		log.F(log.M{"user_id": user.ID, "comment_id": commentID}).Errorf("删除作品评论失败: %v", err)
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInternalError), http.StatusInternalServerError)
+	}
+
+	return webCtx.JSON(web.M{})
+}
+
+// ReportReasons 举报可选的原因分类
+// @Summary 举报可选的原因分类
+// @Tags Gallery
+// @Success 200 {object} common.DataArray[string]
+// @Router /v1/galleries/report-reasons [get]
+func (ctl *GalleryController) ReportReasons(webCtx web.Context) web.Response {
+	return webCtx.JSON(common.NewDataArray(repo.GalleryReportReasons))
+}
+
+// Report 举报作品，举报会进入管理后台的审核队列
+// @Summary 举报作品
+// @Tags Gallery
+// @Accept json
+// @Param id path int true "作品 ID"
+// @Param req body repo.GalleryReportInput true "举报内容"
+// @Success 200 {object} common.EmptyResponse
+// @Router /v1/galleries/{id}/report [post]
+func (ctl *GalleryController) Report(ctx context.Context, webCtx web.Context, user *auth.User) web.Response {
+	galleryID, err := strconv.Atoi(webCtx.PathVar("id"))
+	if err != nil {
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInvalidRequest), http.StatusBadRequest)
+	}
+
+	var in repo.GalleryReportInput
+	if err := webCtx.Unmarshal(&in); err != nil {
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInvalidRequest), http.StatusBadRequest)
+	}
+
+	in.Comment = strings.TrimSpace(in.Comment)
+	if err := in.Validate(); err != nil {
+		return webCtx.JSONError(err.Error(), http.StatusBadRequest)
+	}
+
+	if err := ctl.repo.Gallery.Report(ctx, user.ID, int64(galleryID), in); err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return webCtx.JSONError(common.Text(webCtx, ctl.translater, "作品不存在"), http.StatusNotFound)
+		}
+
+		if errors.Is(err, repo.ErrGalleryReported) {
+			return webCtx.JSONError(common.Text(webCtx, ctl.translater, "您已经举报过该作品，请等待处理"), http.StatusConflict)
+		}
+
+		log.F(log.M{"user_id": user.ID, "gallery_id": galleryID}).Errorf("举报作品失败: %v", err)
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInternalError), http.StatusInternalServerError)
+	}
+
+	return webCtx.JSON(web.M{})
+}
+
+// galleryPagination 解析分页参数
+func galleryPagination(webCtx web.Context) (int64, int64) {
+	page := webCtx.Int64Input("page", 1)
+	if page < 1 || page > 1000 {
+		page = 1
+	}
+
+	perPage := webCtx.Int64Input("per_page", 20)
+	if perPage < 1 || perPage > 100 {
+		perPage = 20
+	}
+
+	return page, perPage
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	chat "github.com/mylxsw/aidea-server/pkg/ai/chat"
+	"github.com/mylxsw/aidea-server/pkg/ai/streamwriter"
 	repo "github.com/mylxsw/aidea-server/pkg/repo"
 	"github.com/mylxsw/aidea-server/pkg/repo/model"
 	"github.com/mylxsw/aidea-server/pkg/service"
@@ -16,6 +17,7 @@ import (
 
 	"github.com/mylxsw/aidea-server/config"
 	"github.com/mylxsw/aidea-server/internal/coins"
+	"github.com/mylxsw/aidea-server/pkg/misc"
 	"github.com/mylxsw/aidea-server/server/controllers/common"
 	"github.com/mylxsw/asteria/log"
 
@@ -47,6 +49,7 @@ func (ctl *GroupChatController) Register(router web.Router) {
 		router.Get("/{group_id}", ctl.Group)
 		router.Put("/{group_id}", ctl.UpdateGroup)
 		router.Delete("/{group_id}", ctl.DeleteGroup)
+		router.Put("/{group_id}/members/{member_id}/persona", ctl.UpdateMemberPersona)
 		router.Get("/{group_id}/messages", ctl.GroupMessages)
 		router.Post("/{group_id}/chat", ctl.Chat)
 		router.Post("/{group_id}/chat-system", ctl.ChatSystem)
@@ -54,6 +57,12 @@ func (ctl *GroupChatController) Register(router web.Router) {
 		router.Delete("/{group_id}/all-chat", ctl.DeleteAllMessages)
 
 		router.Get("/{group_id}/chat-messages", ctl.ChatMessageStatus)
+		router.Get("/{group_id}/events", ctl.Events)
+
+		router.Post("/{group_id}/discussions", ctl.StartDiscussion)
+		router.Get("/{group_id}/discussions/{discussion_id}", ctl.Discussion)
+		router.Post("/{group_id}/discussions/{discussion_id}/interject", ctl.InterjectDiscussion)
+		router.Post("/{group_id}/discussions/{discussion_id}/stop", ctl.StopDiscussion)
 	})
 }
 
@@ -63,6 +72,21 @@ type GroupCreateRequest struct {
 	Members   []repo.Member `json:"members,omitempty"`
 }
 
+// ValidatePersonas 检查成员人设是否合法
+func (req GroupCreateRequest) ValidatePersonas() error {
+	for _, mem := range req.Members {
+		if mem.Persona == nil {
+			continue
+		}
+
+		if err := mem.Persona.Validate(); err != nil {
+			return fmt.Errorf("member %s: %w", mem.ModelID, err)
+		}
+	}
+
+	return nil
+}
+
 // CreateGroup 创建群组
 func (ctl *GroupChatController) CreateGroup(ctx context.Context, webCtx web.Context, user *auth.User) web.Response {
 	var req GroupCreateRequest
@@ -98,6 +122,10 @@ func (ctl *GroupChatController) CreateGroup(ctx context.Context, webCtx web.Cont
 		return webCtx.JSONError("empty group name", http.StatusBadRequest)
 	}
 
+	if err := req.ValidatePersonas(); err != nil {
+		return webCtx.JSONError(err.Error(), http.StatusBadRequest)
+	}
+
 	groupID, err := ctl.repo.ChatGroup.CreateGroup(ctx, user.ID, req.Name, req.AvatarURL, req.Members)
 	if err != nil {
 		log.F(log.M{
@@ -127,11 +155,12 @@ func (ctl *GroupChatController) Groups(ctx context.Context, webCtx web.Context,
 }
 
 type GroupMember struct {
-	ID        int64  `json:"id"`
-	ModelId   string `json:"model_id,omitempty"`
-	ModelName string `json:"model_name,omitempty"`
-	AvatarURL string `json:"avatar_url,omitempty"`
-	Status    int64  `json:"status,omitempty"`
+	ID        int64              `json:"id"`
+	ModelId   string             `json:"model_id,omitempty"`
+	ModelName string             `json:"model_name,omitempty"`
+	AvatarURL string             `json:"avatar_url,omitempty"`
+	Status    int64              `json:"status,omitempty"`
+	Persona   repo.MemberPersona `json:"persona"`
 }
 
 // Group 获取群组信息
@@ -165,6 +194,7 @@ func (ctl *GroupChatController) Group(ctx context.Context, webCtx web.Context, u
 					ModelName: mem.ModelName,
 					AvatarURL: models[mem.ModelId].AvatarUrl,
 					Status:    mem.Status,
+					Persona:   repo.MemberPersonaOf(mem),
 				}
 			},
 		),
@@ -187,6 +217,10 @@ func (ctl *GroupChatController) UpdateGroup(ctx context.Context, webCtx web.Cont
 		return webCtx.JSONError("empty group name", http.StatusBadRequest)
 	}
 
+	if err := req.ValidatePersonas(); err != nil {
+		return webCtx.JSONError(err.Error(), http.StatusBadRequest)
+	}
+
 	if err := ctl.repo.ChatGroup.UpdateGroup(ctx, int64(groupID), user.ID, req.Name, req.AvatarURL); err != nil {
 		log.With(req).Errorf("update group %d failed: %s", groupID, err)
 		return webCtx.JSONError("internal server error", http.StatusInternalServerError)
@@ -202,8 +236,42 @@ func (ctl *GroupChatController) UpdateGroup(ctx context.Context, webCtx web.Cont
 	return webCtx.JSON(web.M{})
 }
 
+// UpdateMemberPersona 更新群组成员的人设
+func (ctl *GroupChatController) UpdateMemberPersona(ctx context.Context, webCtx web.Context, user *auth.User) web.Response {
+	groupID, err := strconv.Atoi(webCtx.PathVar("group_id"))
+	if err != nil {
+		return webCtx.JSONError("invalid group id", http.StatusBadRequest)
+	}
+
+	memberID, err := strconv.Atoi(webCtx.PathVar("member_id"))
+	if err != nil {
+		return webCtx.JSONError("invalid member id", http.StatusBadRequest)
+	}
+
+	var persona repo.MemberPersona
+	if err := webCtx.Unmarshal(&persona); err != nil {
+		return webCtx.JSONError(err.Error(), http.StatusBadRequest)
+	}
+
+	persona.SystemPrompt = strings.TrimSpace(persona.SystemPrompt)
+	if err := persona.Validate(); err != nil {
+		return webCtx.JSONError(err.Error(), http.StatusBadRequest)
+	}
+
+	if err := ctl.repo.ChatGroup.UpdateMemberPersona(ctx, int64(groupID), user.ID, int64(memberID), persona); err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return webCtx.JSONError("member not found", http.StatusNotFound)
+		}
+
+		log.With(persona).Errorf("update group %d member %d persona failed: %s", groupID, memberID, err)
+		return webCtx.JSONError("internal server error", http.StatusInternalServerError)
+	}
+
+	return webCtx.JSON(web.M{})
+}
+
 // DeleteGroup 删除群组
-func (ctl *GroupChatController) DeleteGroup(ctx context.Context, webCtx web.Context, user *auth.User) web.Response {
+func (ctl *GroupChatController) DeleteGroup(ctx context.Context, webCtx web.Context, user *auth.User, client *auth.ClientInfo) web.Response {
 	groupID, err := strconv.Atoi(webCtx.PathVar("group_id"))
 	if err != nil {
 		return webCtx.JSONError("invalid group id", http.StatusBadRequest)
@@ -213,6 +281,8 @@ func (ctl *GroupChatController) DeleteGroup(ctx context.Context, webCtx web.Cont
 		return webCtx.JSONError("internal server error", http.StatusInternalServerError)
 	}
 
+	ctl.svc.Sync.Notify(ctx, user.ID, client.DeviceID, repo.SyncEntityRoom, int64(groupID))
+
 	return webCtx.JSON(web.M{})
 }
 
@@ -274,8 +344,9 @@ type Question struct {
 }
 
 type GroupChatMessages struct {
-	Messages  chat.Messages
-	NeedCoins int64
+	Messages    chat.Messages
+	NeedCoins   int64
+	Temperature float64
 }
 
 // Chat 发起聊天
@@ -348,17 +419,26 @@ func (ctl *GroupChatController) Chat(ctx context.Context, webCtx web.Context, us
 	}
 
 	qas := buildQuestionFromChatGroupMessages(contextMessages)
+	membersMap := array.ToMap(grp.Members, func(mem model.ChatGroupMember, _ int) int64 { return mem.Id })
 	messagesPerMembers := make(map[int64]GroupChatMessages)
 	for _, memberID := range availableMembers {
+		persona := repo.MemberPersonaOf(membersMap[memberID])
+
 		memberMessages := make(chat.Messages, 0)
+		if persona.SystemPrompt != "" {
+			memberMessages = append(memberMessages, chat.Message{Role: "system", Content: persona.SystemPrompt})
+		}
+
 		for _, qa := range qas {
 			memberMessages = append(memberMessages, chat.Message{Role: "user", Content: qa.Question})
 			// 从多个回复中选择一个，选择策略如下
 			// 1. 如果有当前 member_id 的回复，优先选择
-			// 2. 没有当前 member_id 的回复，则随便选择一个
+			// 2. 没有当前 member_id 的回复，则从当前成员可见的回复中随便选择一个
 			selectedAnswer := array.Filter(qa.Answers, func(ans repo.ChatGroupMessageRes, _ int) bool { return ans.MemberId == memberID })
 			if len(selectedAnswer) == 0 {
-				selectedAnswer = qa.Answers
+				selectedAnswer = array.Filter(qa.Answers, func(ans repo.ChatGroupMessageRes, _ int) bool {
+					return persona.CanSee(memberID, ans.MemberId)
+				})
 			}
 
 			if len(selectedAnswer) == 0 {
@@ -369,13 +449,12 @@ func (ctl *GroupChatController) Chat(ctx context.Context, webCtx web.Context, us
 		}
 
 		memberMessages = append(memberMessages, chat.Message{Role: "user", Content: req.Message})
-		messagesPerMembers[memberID] = GroupChatMessages{Messages: memberMessages}
+		messagesPerMembers[memberID] = GroupChatMessages{Messages: memberMessages, Temperature: persona.Temperature}
 	}
 
 	log.With(messagesPerMembers).Debugf("group chat messages per members")
 
 	// 检查用户当前是否有足够的费用发起本次对话
-	membersMap := array.ToMap(grp.Members, func(mem model.ChatGroupMember, _ int) int64 { return mem.Id })
 	coinCounts := array.Map(availableMembers, func(memID int64, _ int) int64 {
 		leftCount, _ := ctl.svc.Chat.FreeChatRequestCounts(ctx, user.ID, membersMap[memID].ModelId)
 		if leftCount > 0 {
@@ -461,6 +540,7 @@ func (ctl *GroupChatController) Chat(ctx context.Context, webCtx web.Context, us
 			MessageID:       answerID,
 			ModelID:         membersMap[memberID].ModelId,
 			ContextMessages: mpm.Messages,
+			Temperature:     mpm.Temperature,
 			CreatedAt:       time.Now(),
 			FreezedCoins:    mpm.NeedCoins,
 		}
@@ -583,6 +663,302 @@ func (ctl *GroupChatController) ChatMessageStatus(ctx context.Context, webCtx we
 	})
 }
 
+type GroupDiscussionRequest struct {
+	Message         string  `json:"message,omitempty"`
+	MemberIDs       []int64 `json:"member_ids,omitempty"`
+	TurnOrder       string  `json:"turn_order,omitempty"`
+	ModeratorID     int64   `json:"moderator_id,omitempty"`
+	MaxRounds       int64   `json:"max_rounds,omitempty"`
+	StopOnConsensus bool    `json:"stop_on_consensus,omitempty"`
+	CoinBudget      int64   `json:"coin_budget,omitempty"`
+}
+
+// StartDiscussion 发起群聊讨论，群组成员围绕用户提出的主题自动进行多轮讨论
+func (ctl *GroupChatController) StartDiscussion(ctx context.Context, webCtx web.Context, user *auth.User) web.Response {
+	groupID, err := strconv.Atoi(webCtx.PathVar("group_id"))
+	if err != nil {
+		return webCtx.JSONError("invalid group id", http.StatusBadRequest)
+	}
+
+	var req GroupDiscussionRequest
+	if err := webCtx.Unmarshal(&req); err != nil {
+		return webCtx.JSONError(err.Error(), http.StatusBadRequest)
+	}
+
+	req.Message = strings.TrimSpace(req.Message)
+	if req.Message == "" {
+		return webCtx.JSONError("empty messages", http.StatusBadRequest)
+	}
+
+	switch req.TurnOrder {
+	case "":
+		req.TurnOrder = repo.DiscussionTurnOrderRoundRobin
+	case repo.DiscussionTurnOrderRoundRobin, repo.DiscussionTurnOrderModerator, repo.DiscussionTurnOrderLLM:
+	default:
+		return webCtx.JSONError("invalid turn order", http.StatusBadRequest)
+	}
+
+	if req.MaxRounds <= 0 {
+		req.MaxRounds = 3
+	}
+
+	// 限制最大讨论轮数，避免无限制的消耗用户的智慧果
+	if req.MaxRounds > 10 {
+		req.MaxRounds = 10
+	}
+
+	if req.CoinBudget < 0 {
+		req.CoinBudget = 0
+	}
+
+	// 查询群组信息
+	grp, err := ctl.repo.ChatGroup.GetGroup(ctx, int64(groupID), user.ID)
+	if err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return webCtx.JSONError("group not found", http.StatusNotFound)
+		}
+
+		return webCtx.JSONError("internal server error", http.StatusInternalServerError)
+	}
+
+	mods := array.ToMap(ctl.svc.Chat.Models(ctx, true), func(m repo.Model, _ int) string { return m.ModelId })
+	grp.Members = array.Filter(grp.Members, func(m model.ChatGroupMember, _ int) bool {
+		_, ok := mods[service.PureModelID(m.ModelId)]
+		return ok
+	})
+
+	// 没有指定参与讨论的成员时，所有成员都参与讨论
+	memberIDs := array.Map(grp.Members, func(m model.ChatGroupMember, _ int) int64 { return m.Id })
+	if len(req.MemberIDs) > 0 {
+		memberIDs = array.Intersect(array.Uniq(req.MemberIDs), memberIDs)
+	}
+
+	if len(memberIDs) < 2 {
+		return webCtx.JSONError("at least two available members are required", http.StatusBadRequest)
+	}
+
+	// 主持人模式下，主持人必须是参与讨论的成员，并且由主持人首先发言
+	firstMemberID := memberIDs[0]
+	if req.TurnOrder == repo.DiscussionTurnOrderModerator {
+		if !array.In(req.ModeratorID, memberIDs) {
+			return webCtx.JSONError("invalid moderator", http.StatusBadRequest)
+		}
+
+		firstMemberID = req.ModeratorID
+	} else {
+		req.ModeratorID = 0
+	}
+
+	// 记录讨论主题
+	questionID, err := ctl.repo.ChatGroup.AddChatMessage(ctx, grp.Group.Id, user.ID, repo.ChatGroupMessage{
+		Message: req.Message,
+		Role:    int64(repo.MessageRoleUser),
+		Status:  repo.MessageStatusSucceed,
+	})
+	if err != nil {
+		log.With(req).Errorf("add chat message failed: %s", err)
+		return webCtx.JSONError("internal server error", http.StatusInternalServerError)
+	}
+
+	discussionID, err := ctl.repo.ChatGroup.CreateDiscussion(ctx, grp.Group.Id, user.ID, repo.ChatGroupDiscussionCreate{
+		QuestionID:      questionID,
+		MemberIDs:       memberIDs,
+		TurnOrder:       req.TurnOrder,
+		ModeratorID:     req.ModeratorID,
+		MaxRounds:       req.MaxRounds,
+		StopOnConsensus: req.StopOnConsensus,
+		CoinBudget:      req.CoinBudget,
+		NextMemberID:    firstMemberID,
+	})
+	if err != nil {
+		log.With(req).Errorf("create discussion failed: %s", err)
+		return webCtx.JSONError("internal server error", http.StatusInternalServerError)
+	}
+
+	disc, err := ctl.repo.ChatGroup.GetDiscussion(ctx, grp.Group.Id, user.ID, discussionID)
+	if err != nil {
+		log.With(req).Errorf("query discussion failed: %s", err)
+		return webCtx.JSONError("internal server error", http.StatusInternalServerError)
+	}
+
+	membersMap := array.ToMap(grp.Members, func(mem model.ChatGroupMember, _ int) int64 { return mem.Id })
+	history := []model.ChatGroupMessage{{Id: questionID, Message: req.Message, Role: int64(repo.MessageRoleUser), Status: repo.MessageStatusSucceed}}
+
+	taskID, answerID, err := queue.EnqueueGroupDiscussionTurn(ctx, ctl.queue, ctl.repo, ctl.svc, disc, membersMap[firstMemberID], history)
+	if err != nil {
+		if errors.Is(err, queue.ErrDiscussionQuotaNotEnough) {
+			misc.NoError(ctl.repo.ChatGroup.StopDiscussion(ctx, grp.Group.Id, user.ID, discussionID, repo.ChatGroupDiscussionStatusFinished, repo.DiscussionStopReasonQuota))
+			return webCtx.JSONError(common.ErrQuotaNotEnough, http.StatusPaymentRequired)
+		}
+
+		if errors.Is(err, queue.ErrDiscussionBudgetNotEnough) {
+			misc.NoError(ctl.repo.ChatGroup.StopDiscussion(ctx, grp.Group.Id, user.ID, discussionID, repo.ChatGroupDiscussionStatusFinished, repo.DiscussionStopReasonCoinBudget))
+			return webCtx.JSONError(err.Error(), http.StatusBadRequest)
+		}
+
+		log.With(req).Errorf("enqueue discussion task failed: %s", err)
+		misc.NoError(ctl.repo.ChatGroup.StopDiscussion(ctx, grp.Group.Id, user.ID, discussionID, repo.ChatGroupDiscussionStatusFinished, repo.DiscussionStopReasonError))
+		return webCtx.JSONError("internal server error", http.StatusInternalServerError)
+	}
+
+	return webCtx.JSON(web.M{
+		"discussion_id": discussionID,
+		"question_id":   questionID,
+		"task": GroupChatTask{
+			MemberID: firstMemberID,
+			TaskID:   taskID,
+			AnswerID: answerID,
+		},
+	})
+}
+
+func (ctl *GroupChatController) resolveDiscussion(ctx context.Context, webCtx web.Context, user *auth.User) (*repo.ChatGroupDiscussion, web.Response) {
+	groupID, err := strconv.Atoi(webCtx.PathVar("group_id"))
+	if err != nil {
+		return nil, webCtx.JSONError("invalid group id", http.StatusBadRequest)
+	}
+
+	discussionID, err := strconv.Atoi(webCtx.PathVar("discussion_id"))
+	if err != nil {
+		return nil, webCtx.JSONError("invalid discussion id", http.StatusBadRequest)
+	}
+
+	disc, err := ctl.repo.ChatGroup.GetDiscussion(ctx, int64(groupID), user.ID, int64(discussionID))
+	if err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return nil, webCtx.JSONError("discussion not found", http.StatusNotFound)
+		}
+
+		return nil, webCtx.JSONError("internal server error", http.StatusInternalServerError)
+	}
+
+	return disc, nil
+}
+
+// Discussion 查询群聊讨论状态
+func (ctl *GroupChatController) Discussion(ctx context.Context, webCtx web.Context, user *auth.User) web.Response {
+	disc, resp := ctl.resolveDiscussion(ctx, webCtx, user)
+	if resp != nil {
+		return resp
+	}
+
+	return webCtx.JSON(web.M{
+		"data":    disc.ChatGroupDiscussion,
+		"members": disc.Members,
+	})
+}
+
+// InterjectDiscussion 用户在讨论进行中插话，插话内容会作为后续成员发言的上下文
+func (ctl *GroupChatController) InterjectDiscussion(ctx context.Context, webCtx web.Context, user *auth.User) web.Response {
+	disc, resp := ctl.resolveDiscussion(ctx, webCtx, user)
+	if resp != nil {
+		return resp
+	}
+
+	message := strings.TrimSpace(webCtx.Input("message"))
+	if message == "" {
+		return webCtx.JSONError("empty messages", http.StatusBadRequest)
+	}
+
+	if !disc.IsRunning() {
+		return webCtx.JSONError("discussion has finished", http.StatusBadRequest)
+	}
+
+	messageID, err := ctl.repo.ChatGroup.AddChatMessage(ctx, disc.GroupId, user.ID, repo.ChatGroupMessage{
+		Message: message,
+		Role:    int64(repo.MessageRoleUser),
+		Pid:     disc.QuestionId,
+		Status:  repo.MessageStatusSucceed,
+	})
+	if err != nil {
+		log.F(log.M{"discussion_id": disc.Id, "message": message}).Errorf("add discussion interjection failed: %s", err)
+		return webCtx.JSONError("internal server error", http.StatusInternalServerError)
+	}
+
+	return webCtx.JSON(web.M{"message_id": messageID})
+}
+
+// StopDiscussion 用户终止群聊讨论
+func (ctl *GroupChatController) StopDiscussion(ctx context.Context, webCtx web.Context, user *auth.User) web.Response {
+	disc, resp := ctl.resolveDiscussion(ctx, webCtx, user)
+	if resp != nil {
+		return resp
+	}
+
+	if err := ctl.repo.ChatGroup.StopDiscussion(ctx, disc.GroupId, user.ID, disc.Id, repo.ChatGroupDiscussionStatusStopped, repo.DiscussionStopReasonUser); err != nil {
+		log.F(log.M{"discussion_id": disc.Id}).Errorf("stop discussion failed: %s", err)
+		return webCtx.JSONError("internal server error", http.StatusInternalServerError)
+	}
+
+	return webCtx.JSON(web.M{})
+}
+
+// Events 订阅群组的消息事件（SSE 或者 WebSocket），实时推送成员的回复内容以及消息状态变更
+func (ctl *GroupChatController) Events(ctx context.Context, webCtx web.Context, user *auth.User, w http.ResponseWriter) {
+	writeError := func(message string, statusCode int) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(statusCode)
+		_, _ = w.Write([]byte(fmt.Sprintf(`{"error": %s}`, strconv.Quote(message))))
+	}
+
+	groupID, err := strconv.Atoi(webCtx.PathVar("group_id"))
+	if err != nil {
+		writeError("invalid group id", http.StatusBadRequest)
+		return
+	}
+
+	if _, err := ctl.repo.ChatGroup.GetGroup(ctx, int64(groupID), user.ID); err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			writeError("group not found", http.StatusNotFound)
+			return
+		}
+
+		writeError("internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	sw, err := streamwriter.NewPublisher(webCtx.Input("ws") == "true", ctl.conf.EnableCORS, webCtx.Request().Raw(), w)
+	if err != nil {
+		log.F(log.M{"user_id": user.ID, "group_id": groupID}).Errorf("create stream writer failed: %s", err)
+		return
+	}
+	defer sw.Close()
+
+	sw.SetOnClosed(cancel)
+
+	events, err := ctl.svc.GroupChat.Subscribe(ctx, int64(groupID))
+	if err != nil {
+		log.F(log.M{"user_id": user.ID, "group_id": groupID}).Errorf("subscribe group chat events failed: %s", err)
+		misc.NoError(sw.WriteErrorStream(errors.New("internal server error"), http.StatusInternalServerError))
+		return
+	}
+
+	ticker := time.NewTicker(15 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case evt, ok := <-events:
+			if !ok {
+				return
+			}
+
+			if err := sw.WriteStream(evt); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if err := sw.WriteStream(service.GroupChatEvent{Type: service.GroupChatEventPing, GroupID: int64(groupID)}); err != nil {
+				return
+			}
+		}
+	}
+}
+
 func buildQuestionFromChatGroupMessages(contextMessages []repo.ChatGroupMessageRes) []Question {
 	cutoffIndex := -1
 	for i := 0; i < len(contextMessages); i++ {
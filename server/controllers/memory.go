@@ -0,0 +1,215 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mylxsw/aidea-server/pkg/repo"
+	"github.com/mylxsw/aidea-server/pkg/service"
+	"github.com/mylxsw/aidea-server/pkg/youdao"
+	"github.com/mylxsw/aidea-server/server/auth"
+	"github.com/mylxsw/aidea-server/server/controllers/common"
+	"github.com/mylxsw/asteria/log"
+	"github.com/mylxsw/glacier/infra"
+	"github.com/mylxsw/glacier/web"
+)
+
+// MemoryController 用户长期记忆
+type MemoryController struct {
+	repo       *repo.Repository  `autowire:"@"`
+	svc        *service.Service  `autowire:"@"`
+	translater youdao.Translater `autowire:"@"`
+}
+
+func NewMemoryController(resolver infra.Resolver) web.Controller {
+	ctl := MemoryController{}
+	resolver.MustAutoWire(&ctl)
+	return &ctl
+}
+
+func (ctl *MemoryController) Register(router web.Router) {
+	router.Group("/memories", func(router web.Router) {
+		router.Get("/", ctl.Memories)
+		router.Post("/", ctl.CreateMemory)
+		router.Delete("/", ctl.ClearMemories)
+		router.Get("/settings", ctl.Settings)
+		router.Put("/settings", ctl.UpdateSettings)
+		router.Put("/{id}", ctl.UpdateMemory)
+		router.Delete("/{id}", ctl.DeleteMemory)
+	})
+}
+
+// Memories 获取用户的长期记忆列表
+// @Summary 获取用户的长期记忆列表
+// @Tags Memory
+// @Produce json
+// @Success 200 {object} common.DataArray[model.UserMemory]
+// @Router /v1/memories [get]
+func (ctl *MemoryController) Memories(ctx context.Context, webCtx web.Context, user *auth.User) web.Response {
+	memories, err := ctl.repo.Memory.Memories(ctx, user.ID)
+	if err != nil {
+		log.F(log.M{"user_id": user.ID}).Errorf("查询用户记忆失败: %v", err)
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInternalError), http.StatusInternalServerError)
+	}
+
+	return webCtx.JSON(common.NewDataArray(memories))
+}
+
+func (ctl *MemoryController) parseContent(webCtx web.Context) (string, error) {
+	content := strings.TrimSpace(webCtx.Input("content"))
+	if content == "" {
+		return "", errors.New("记忆内容不能为空")
+	}
+
+	if utf8.RuneCountInString(content) > repo.MemoryMaxLength {
+		return "", errors.New("记忆内容不能超过 500 个字符")
+	}
+
+	return content, nil
+}
+
+// CreateMemory 手动添加一条记忆
+// @Summary 手动添加一条记忆
+// @Tags Memory
+// @Param content formData string true "记忆内容"
+// @Success 200 {object} common.IDResponse
+// @Router /v1/memories [post]
+func (ctl *MemoryController) CreateMemory(ctx context.Context, webCtx web.Context, user *auth.User) web.Response {
+	content, err := ctl.parseContent(webCtx)
+	if err != nil {
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, err.Error()), http.StatusBadRequest)
+	}
+
+	ids, err := ctl.repo.Memory.Add(ctx, user.ID, 0, repo.MemorySourceManual, content)
+	if err != nil {
+		if errors.Is(err, repo.ErrMemoryLimitExceeded) {
+			return webCtx.JSONError(common.Text(webCtx, ctl.translater, "记忆数量已达上限，请删除部分记忆后重试"), http.StatusBadRequest)
+		}
+
+		log.F(log.M{"user_id": user.ID}).Errorf("添加用户记忆失败: %v", err)
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInternalError), http.StatusInternalServerError)
+	}
+
+	return webCtx.JSON(common.NewIDResponse(ids[0]))
+}
+
+// UpdateMemory 编辑记忆内容
+// @Summary 编辑记忆内容
+// @Tags Memory
+// @Param id path int true "记忆 ID"
+// @Param content formData string true "记忆内容"
+// @Success 200 {object} model.UserMemory
+// @Router /v1/memories/{id} [put]
+func (ctl *MemoryController) UpdateMemory(ctx context.Context, webCtx web.Context, user *auth.User) web.Response {
+	id, err := strconv.Atoi(webCtx.PathVar("id"))
+	if err != nil {
+		return webCtx.JSONError("invalid id", http.StatusBadRequest)
+	}
+
+	content, err := ctl.parseContent(webCtx)
+	if err != nil {
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, err.Error()), http.StatusBadRequest)
+	}
+
+	if _, err := ctl.repo.Memory.Memory(ctx, user.ID, int64(id)); err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return webCtx.JSONError(common.Text(webCtx, ctl.translater, "记忆不存在"), http.StatusNotFound)
+		}
+
+		log.F(log.M{"user_id": user.ID, "id": id}).Errorf("查询用户记忆失败: %v", err)
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInternalError), http.StatusInternalServerError)
+	}
+
+	if err := ctl.repo.Memory.Update(ctx, user.ID, int64(id), content); err != nil {
+		log.F(log.M{"user_id": user.ID, "id": id}).Errorf("更新用户记忆失败: %v", err)
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInternalError), http.StatusInternalServerError)
+	}
+
+	memory, err := ctl.repo.Memory.Memory(ctx, user.ID, int64(id))
+	if err != nil {
+		log.F(log.M{"user_id": user.ID, "id": id}).Errorf("查询用户记忆失败: %v", err)
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInternalError), http.StatusInternalServerError)
+	}
+
+	return webCtx.JSON(memory)
+}
+
+// DeleteMemory 删除一条记忆
+// @Summary 删除一条记忆
+// @Tags Memory
+// @Param id path int true "记忆 ID"
+// @Success 200 {object} any
+// @Router /v1/memories/{id} [delete]
+func (ctl *MemoryController) DeleteMemory(ctx context.Context, webCtx web.Context, user *auth.User) web.Response {
+	id, err := strconv.Atoi(webCtx.PathVar("id"))
+	if err != nil {
+		return webCtx.JSONError("invalid id", http.StatusBadRequest)
+	}
+
+	if err := ctl.repo.Memory.Delete(ctx, user.ID, int64(id)); err != nil {
+		log.F(log.M{"user_id": user.ID, "id": id}).Errorf("删除用户记忆失败: %v", err)
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInternalError), http.StatusInternalServerError)
+	}
+
+	return webCtx.JSON(web.M{})
+}
+
+// ClearMemories 清空用户的所有记忆
+// @Summary 清空用户的所有记忆
+// @Tags Memory
+// @Success 200 {object} any
+// @Router /v1/memories [delete]
+func (ctl *MemoryController) ClearMemories(ctx context.Context, webCtx web.Context, user *auth.User) web.Response {
+	if err := ctl.repo.Memory.Clear(ctx, user.ID); err != nil {
+		log.F(log.M{"user_id": user.ID}).Errorf("清空用户记忆失败: %v", err)
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInternalError), http.StatusInternalServerError)
+	}
+
+	return webCtx.JSON(web.M{})
+}
+
+type MemorySettings struct {
+	// Enabled 是否开启长期记忆
+	Enabled bool `json:"enabled"`
+}
+
+// Settings 获取用户的长期记忆设置
+// @Summary 获取用户的长期记忆设置
+// @Tags Memory
+// @Success 200 {object} MemorySettings
+// @Router /v1/memories/settings [get]
+func (ctl *MemoryController) Settings(ctx context.Context, webCtx web.Context, user *auth.User) web.Response {
+	cus, err := ctl.svc.User.CustomConfig(ctx, user.ID)
+	if err != nil {
+		log.F(log.M{"user_id": user.ID}).Errorf("查询用户自定义配置失败: %v", err)
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInternalError), http.StatusInternalServerError)
+	}
+
+	return webCtx.JSON(MemorySettings{Enabled: cus.MemoryEnabled})
+}
+
+// UpdateSettings 更新用户的长期记忆设置，开启后会从对话中提取用户信息
+// @Summary 更新用户的长期记忆设置
+// @Tags Memory
+// @Param enabled formData bool true "是否开启长期记忆"
+// @Success 200 {object} MemorySettings
+// @Router /v1/memories/settings [put]
+func (ctl *MemoryController) UpdateSettings(ctx context.Context, webCtx web.Context, user *auth.User) web.Response {
+	cus, err := ctl.svc.User.CustomConfig(ctx, user.ID)
+	if err != nil {
+		log.F(log.M{"user_id": user.ID}).Errorf("查询用户自定义配置失败: %v", err)
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInternalError), http.StatusInternalServerError)
+	}
+
+	cus.MemoryEnabled = webCtx.Input("enabled") == "true"
+	if err := ctl.svc.User.UpdateCustomConfig(ctx, user.ID, *cus); err != nil {
+		log.F(log.M{"user_id": user.ID}).Errorf("更新用户自定义配置失败: %v", err)
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInternalError), http.StatusInternalServerError)
+	}
+
+	return webCtx.JSON(MemorySettings{Enabled: cus.MemoryEnabled})
+}
@@ -2,18 +2,32 @@ package controllers
 
 import (
 	"context"
-	"errors"
+	"github.com/mylxsw/aidea-server/config"
+	"github.com/mylxsw/aidea-server/pkg/rate"
 	"github.com/mylxsw/aidea-server/pkg/repo"
 	"github.com/mylxsw/aidea-server/pkg/repo/model"
+	"github.com/mylxsw/aidea-server/pkg/service"
+	"github.com/mylxsw/aidea-server/pkg/uploader"
+	"github.com/mylxsw/aidea-server/pkg/youdao"
 	"github.com/mylxsw/aidea-server/server/auth"
+	"github.com/mylxsw/aidea-server/server/controllers/common"
+	"github.com/mylxsw/asteria/log"
 	"github.com/mylxsw/glacier/infra"
 	"github.com/mylxsw/glacier/web"
 	"github.com/mylxsw/go-utils/array"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type MessageController struct {
-	repo *repo.Repository `autowire:"@"`
+	conf       *config.Config     `autowire:"@"`
+	repo       *repo.Repository   `autowire:"@"`
+	svc        *service.Service   `autowire:"@"`
+	translater youdao.Translater  `autowire:"@"`
+	uploader   *uploader.Uploader `autowire:"@"`
+	limiter    *rate.RateLimiter  `autowire:"@"`
 }
 
 func NewMessageController(resolver infra.Resolver) web.Controller {
@@ -25,13 +39,22 @@ func NewMessageController(resolver infra.Resolver) web.Controller {
 func (ctl *MessageController) Register(router web.Router) {
 	router.Group("/messages", func(router web.Router) {
 		router.Post("/share", ctl.ShareMessages)
+		router.Post("/share/poster", ctl.SharePoster)
+		router.Get("/search", ctl.SearchMessages)
+		router.Delete("/{id}", ctl.DeleteMessage)
 	})
 
 	router.Group("/shared-messages", func(router web.Router) {
 		router.Get("/{code}", ctl.GetSharedMessages)
+		router.Post("/{code}", ctl.GetSharedMessages)
 	})
 }
 
+type MessageShareRequest struct {
+	repo.ShareData
+	ShareOptionsRequest
+}
+
 type MessageShareResponse struct {
 	Code string `json:"code"`
 }
@@ -41,21 +64,26 @@ type MessageShareResponse struct {
 // @Tags Message
 // @Accept json
 // @Produce json
-// @Param req body repo.ShareData true "Message Share Request"
+// @Param req body MessageShareRequest true "Message Share Request"
 // @Success 200 {object} MessageShareResponse
 // @Router /v1/messages/share [post]
 func (ctl *MessageController) ShareMessages(ctx context.Context, webCtx web.Context, user *auth.User) web.Response {
-	var req repo.ShareData
+	var req MessageShareRequest
 	if err := webCtx.Unmarshal(&req); err != nil {
 		return webCtx.JSONError(err.Error(), http.StatusBadRequest)
 	}
 
+	opts, err := req.Options()
+	if err != nil {
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, err.Error()), http.StatusBadRequest)
+	}
+
 	req.IDs = array.Uniq(array.Filter(req.IDs, func(id int64, _ int) bool { return id > 0 }))
 	if len(req.IDs) == 0 {
 		return webCtx.JSONError("invalid message ids", http.StatusBadRequest)
 	}
 
-	code, err := ctl.repo.Message.Share(ctx, user.ID, req)
+	code, err := ctl.repo.Message.Share(ctx, user.ID, req.ShareData, opts)
 	if err != nil {
 		return webCtx.JSONError(err.Error(), http.StatusInternalServerError)
 	}
@@ -74,22 +102,126 @@ type SharedMessagesResponse struct {
 // @Accept json
 // @Produce json
 // @Param code path string true "Share Code"
+// @Param password formData string false "Access password, required if the share is protected, only accepted in the POST body"
 // @Success 200 {object} SharedMessagesResponse
 // @Router /v1/shared-messages/{code} [get]
+// @Router /v1/shared-messages/{code} [post]
 func (ctl *MessageController) GetSharedMessages(ctx context.Context, webCtx web.Context) web.Response {
 	code := webCtx.PathVar("code")
 	if code == "" {
 		return webCtx.JSONError("invalid code", http.StatusBadRequest)
 	}
 
-	messages, data, err := ctl.repo.Message.SharedMessages(ctx, code)
+	password, err := SharedAccessPassword(ctx, webCtx, ctl.limiter, code)
 	if err != nil {
-		if errors.Is(err, repo.ErrNotFound) {
-			return webCtx.JSONError(err.Error(), http.StatusNotFound)
-		}
+		return SharedAccessError(webCtx, ctl.translater, err)
+	}
 
-		return webCtx.JSONError(err.Error(), http.StatusInternalServerError)
+	messages, data, err := ctl.repo.Message.SharedMessages(ctx, code, password)
+	if err != nil {
+		return SharedAccessError(webCtx, ctl.translater, err)
 	}
 
 	return webCtx.JSON(SharedMessagesResponse{Messages: messages, Meta: data})
 }
+
+type MessageSearchResponse struct {
+	Data    []repo.MessageSearchResult `json:"data"`
+	Page    int64                      `json:"page"`
+	PerPage int64                      `json:"per_page"`
+}
+
+// SearchMessages Search the user's chat history, including group chats
+// @Summary Search the user's chat history, including group chats
+// @Tags Message
+// @Accept json
+// @Produce json
+// @Param q query string true "Keywords, use double quotes for phrase matching"
+// @Param source query string false "Search scope: chat/group_chat, empty for all"
+// @Param room_id query int false "Room ID or group ID"
+// @Param model query string false "Model ID"
+// @Param start query string false "Start date, format: 2006-01-02"
+// @Param end query string false "End date (inclusive), format: 2006-01-02"
+// @Param page query int false "Page"
+// @Param per_page query int false "Per page"
+// @Success 200 {object} MessageSearchResponse
+// @Router /v1/messages/search [get]
+func (ctl *MessageController) SearchMessages(ctx context.Context, webCtx web.Context, user *auth.User) web.Response {
+	req := repo.MessageSearchReq{
+		Keyword: strings.TrimSpace(webCtx.Input("q")),
+		Source:  webCtx.Input("source"),
+		RoomID:  webCtx.Int64Input("room_id", 0),
+		Model:   webCtx.Input("model"),
+		Page:    webCtx.Int64Input("page", 1),
+		PerPage: webCtx.Int64Input("per_page", 20),
+	}
+
+	if repo.BuildFullTextQuery(req.Keyword) == "" {
+		return webCtx.JSONError("invalid keyword", http.StatusBadRequest)
+	}
+
+	if req.Source != "" && req.Source != repo.MessageSearchSourceChat && req.Source != repo.MessageSearchSourceGroupChat {
+		return webCtx.JSONError("invalid source", http.StatusBadRequest)
+	}
+
+	if req.Page < 1 {
+		req.Page = 1
+	}
+
+	if req.PerPage < 1 || req.PerPage > 100 {
+		req.PerPage = 20
+	}
+
+	if req.Page*req.PerPage > repo.MessageSearchMaxWindow {
+		return webCtx.JSONError("too many pages, please narrow down the search", http.StatusBadRequest)
+	}
+
+	if start := webCtx.Input("start"); start != "" {
+		startAt, err := time.Parse("2006-01-02", start)
+		if err != nil {
+			return webCtx.JSONError("invalid start date", http.StatusBadRequest)
+		}
+
+		req.StartAt = startAt
+	}
+
+	if end := webCtx.Input("end"); end != "" {
+		endAt, err := time.Parse("2006-01-02", end)
+		if err != nil {
+			return webCtx.JSONError("invalid end date", http.StatusBadRequest)
+		}
+
+		req.EndAt = endAt.AddDate(0, 0, 1)
+	}
+
+	results, err := ctl.repo.Message.Search(ctx, user.ID, req)
+	if err != nil {
+		log.F(log.M{"user_id": user.ID, "keyword": req.Keyword}).Errorf("search messages failed: %s", err)
+		return webCtx.JSONError("internal server error", http.StatusInternalServerError)
+	}
+
+	return webCtx.JSON(MessageSearchResponse{Data: results, Page: req.Page, PerPage: req.PerPage})
+}
+
+// DeleteMessage Delete a chat message, other devices will be notified to delete it as well
+// @Summary Delete a chat message
+// @Tags Message
+// @Produce json
+// @Param id path int true "Message ID"
+// @Success 200 {object} any
+// @Router /v1/messages/{id} [delete]
+func (ctl *MessageController) DeleteMessage(ctx context.Context, webCtx web.Context, user *auth.User, client *auth.ClientInfo) web.Response {
+	id, err := strconv.Atoi(webCtx.PathVar("id"))
+	if err != nil || id <= 0 {
+		return webCtx.JSONError("invalid message id", http.StatusBadRequest)
+	}
+
+	if err := ctl.repo.Message.Delete(ctx, user.ID, []int64{int64(id)}); err != nil {
+		log.F(log.M{"user_id": user.ID, "message_id": id}).Errorf("delete message failed: %s", err)
+		return webCtx.JSONError("internal server error", http.StatusInternalServerError)
+	}
+
+	ctl.svc.Sync.Notify(ctx, user.ID, client.DeviceID, repo.SyncEntityMessage, int64(id))
+
+	return webCtx.JSON(web.M{})
+}
@@ -0,0 +1,228 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	goimage "image"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mylxsw/aidea-server/pkg/image"
+	"github.com/mylxsw/aidea-server/pkg/misc"
+	"github.com/mylxsw/aidea-server/pkg/repo"
+	"github.com/mylxsw/aidea-server/pkg/repo/model"
+	"github.com/mylxsw/aidea-server/pkg/uploader"
+	"github.com/mylxsw/aidea-server/server/auth"
+	"github.com/mylxsw/aidea-server/server/controllers/common"
+	"github.com/mylxsw/asteria/log"
+	"github.com/mylxsw/eloquent/query"
+	"github.com/mylxsw/glacier/web"
+	"github.com/mylxsw/go-utils/array"
+	"github.com/mylxsw/go-utils/str"
+	"github.com/mylxsw/go-utils/ternary"
+)
+
+// PosterMaxMessages 单张分享海报最多包含的消息数量
+const PosterMaxMessages = 30
+
+type MessagePosterRequest struct {
+	MessageShareRequest
+	// Theme Poster theme: light/dark/warm
+	Theme string `json:"theme,omitempty"`
+	// Title Poster title, the first question is used if empty
+	Title string `json:"title,omitempty"`
+}
+
+type MessagePosterResponse struct {
+	Code string `json:"code"`
+	URL  string `json:"url"`
+}
+
+// SharePoster Render selected messages into a long image for sharing to social media
+// @Summary Render selected messages into a long image for sharing to social media
+// @Tags Message
+// @Accept json
+// @Produce json
+// @Param req body MessagePosterRequest true "Message Poster Request"
+// @Success 200 {object} MessagePosterResponse
+// @Router /v1/messages/share/poster [post]
+func (ctl *MessageController) SharePoster(ctx context.Context, webCtx web.Context, user *auth.User) web.Response {
+	var req MessagePosterRequest
+	if err := webCtx.Unmarshal(&req); err != nil {
+		return webCtx.JSONError(err.Error(), http.StatusBadRequest)
+	}
+
+	opts, err := req.Options()
+	if err != nil {
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, err.Error()), http.StatusBadRequest)
+	}
+
+	req.IDs = array.Uniq(array.Filter(req.IDs, func(id int64, _ int) bool { return id > 0 }))
+	if len(req.IDs) == 0 || len(req.IDs) > PosterMaxMessages {
+		return webCtx.JSONError("invalid message ids", http.StatusBadRequest)
+	}
+
+	if req.Theme == "" {
+		req.Theme = image.DefaultPosterTheme
+	}
+
+	if _, ok := image.PosterThemes[req.Theme]; !ok {
+		return webCtx.JSONError("invalid theme", http.StatusBadRequest)
+	}
+
+	messages, _, err := ctl.repo.Message.Messages(ctx, 1, PosterMaxMessages, func(builder query.SQLBuilder) query.SQLBuilder {
+		return builder.Where(model.FieldChatMessagesUserId, user.ID).WhereIn(model.FieldChatMessagesId, req.IDs)
+	})
+	if err != nil {
+		log.F(log.M{"user_id": user.ID, "ids": req.IDs}).Errorf("query messages failed: %s", err)
+		return webCtx.JSONError("internal server error", http.StatusInternalServerError)
+	}
+
+	if len(messages) == 0 {
+		return webCtx.JSONError("messages not found", http.StatusNotFound)
+	}
+
+	messages = array.Sort(messages, func(a, b model.ChatMessages) bool { return a.Id < b.Id })
+
+	code, err := ctl.repo.Message.Share(ctx, user.ID, req.ShareData, opts)
+	if err != nil {
+		log.F(log.M{"user_id": user.ID, "ids": req.IDs}).Errorf("share messages failed: %s", err)
+		return webCtx.JSONError("internal server error", http.StatusInternalServerError)
+	}
+
+	poster := image.Poster{
+		Title:    strings.TrimSpace(req.Title),
+		Subtitle: time.Now().Format("2006-01-02"),
+		Messages: ctl.buildPosterMessages(ctx, user, messages),
+		QRLink:   ctl.sharedMessagesURL(code),
+		Footer: []string{
+			common.Text(webCtx, ctl.translater, "扫码查看完整对话"),
+			"AIdea",
+		},
+		Theme: req.Theme,
+	}
+
+	if poster.Title == "" {
+		questions := array.Filter(messages, func(m model.ChatMessages, _ int) bool { return repo.MessageRole(m.Role) == repo.MessageRoleUser })
+		if len(questions) > 0 {
+			poster.Title = misc.SubString(strings.Join(strings.Fields(questions[0].Message), " "), 40)
+		}
+	}
+
+	data, err := image.New(ctl.conf.FontPath).Poster(poster)
+	if err != nil {
+		if errors.Is(err, image.ErrPosterTooLarge) {
+			return webCtx.JSONError(common.Text(webCtx, ctl.translater, "选择的消息内容过长，请减少消息数量后重试"), http.StatusBadRequest)
+		}
+
+		log.F(log.M{"user_id": user.ID, "ids": req.IDs}).Errorf("render share poster failed: %s", err)
+		return webCtx.JSONError("internal server error", http.StatusInternalServerError)
+	}
+
+	// 分享设置了有效期时，海报图片在分享过期后自动删除
+	expireDays := ternary.IfLazy(
+		opts.ExpiredAt.IsZero(),
+		func() int { return 0 },
+		func() int { return int(math.Ceil(time.Until(opts.ExpiredAt).Hours() / 24)) },
+	)
+
+	url, err := ctl.uploader.UploadStream(ctx, int(user.ID), expireDays, data, ".png")
+	if err != nil {
+		log.F(log.M{"user_id": user.ID, "ids": req.IDs}).Errorf("upload share poster failed: %s", err)
+		return webCtx.JSONError("internal server error", http.StatusInternalServerError)
+	}
+
+	return webCtx.JSON(MessagePosterResponse{Code: code, URL: url})
+}
+
+// sharedMessagesURL 分享页面地址，用于生成海报中的二维码
+func (ctl *MessageController) sharedMessagesURL(code string) string {
+	if ctl.conf.ShareMessagesURL != "" {
+		return strings.ReplaceAll(ctl.conf.ShareMessagesURL, "{code}", code)
+	}
+
+	return fmt.Sprintf("%s/v1/shared-messages/%s", ctl.conf.BaseURL, code)
+}
+
+func (ctl *MessageController) buildPosterMessages(ctx context.Context, user *auth.User, messages []model.ChatMessages) []image.PosterMessage {
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	// 同一个头像只下载一次，下载失败时使用名称首字母代替
+	avatars := make(map[string]goimage.Image)
+	loadAvatar := func(url string) goimage.Image {
+		if url == "" {
+			return nil
+		}
+
+		if avatar, ok := avatars[url]; ok {
+			return avatar
+		}
+
+		avatar, err := ctl.downloadAvatar(ctx, url)
+		if err != nil {
+			log.F(log.M{"url": url}).Warningf("download avatar failed: %s", err)
+		}
+
+		avatars[url] = avatar
+		return avatar
+	}
+
+	return array.Map(messages, func(msg model.ChatMessages, _ int) image.PosterMessage {
+		content := misc.SubString(msg.Message, 3000)
+		if repo.MessageRole(msg.Role) == repo.MessageRoleUser {
+			return image.PosterMessage{Name: user.Name, Avatar: loadAvatar(user.Avatar), IsUser: true, Content: content}
+		}
+
+		name, avatarURL := msg.Model, ""
+		if mod := ctl.svc.Chat.Model(ctx, msg.Model); mod != nil {
+			name, avatarURL = mod.Name, mod.AvatarUrl
+		}
+
+		return image.PosterMessage{Name: name, Avatar: loadAvatar(avatarURL), Content: content}
+	})
+}
+
+// avatarHTTPClient 下载海报头像使用的 HTTP 客户端，不跟随重定向，避免跳转到存储域名以外的地址
+var avatarHTTPClient = &http.Client{
+	Timeout: 5 * time.Second,
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+func (ctl *MessageController) downloadAvatar(ctx context.Context, url string) (goimage.Image, error) {
+	// 头像地址由用户提交，只允许下载存储在对象存储中的图片
+	prefixes := []string{"https://ssl.aicode.cc/"}
+	if ctl.conf.StorageDomain != "" {
+		prefixes = append(prefixes, strings.TrimSuffix(ctl.conf.StorageDomain, "/")+"/")
+	}
+
+	if !str.HasPrefixes(url, prefixes) {
+		return nil, fmt.Errorf("avatar url not allowed: %s", url)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uploader.BuildImageURLWithFilter(url, "avatar", ctl.conf.StorageDomain), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := avatarHTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	img, _, err := goimage.Decode(io.LimitReader(resp.Body, 5*1024*1024))
+	return img, err
+}
@@ -17,7 +17,9 @@ import (
 	"github.com/mylxsw/aidea-server/pkg/ai/control"
 	openaiHelper "github.com/mylxsw/aidea-server/pkg/ai/openai"
 	"github.com/mylxsw/aidea-server/pkg/ai/streamwriter"
+	"github.com/mylxsw/aidea-server/pkg/memory"
 	"github.com/mylxsw/aidea-server/pkg/misc"
+	"github.com/mylxsw/aidea-server/pkg/moderation"
 	"github.com/mylxsw/aidea-server/pkg/rate"
 	"github.com/mylxsw/aidea-server/pkg/repo"
 	"github.com/mylxsw/aidea-server/pkg/repo/model"
@@ -31,6 +33,7 @@ import (
 	"github.com/go-redis/redis_rate/v10"
 	"github.com/mylxsw/aidea-server/config"
 	"github.com/mylxsw/aidea-server/internal/coins"
+	"github.com/mylxsw/aidea-server/internal/queue"
 	"github.com/mylxsw/aidea-server/server/auth"
 	"github.com/mylxsw/aidea-server/server/controllers/common"
 	"github.com/mylxsw/asteria/log"
@@ -54,6 +57,8 @@ type OpenAIController struct {
 	limiter     *rate.RateLimiter        `autowire:"@"`
 	repo        *repo.Repository         `autowire:"@"`
 	search      search.Searcher          `autowire:"@"`
+	svc         *service.Service         `autowire:"@"`
+	queue       *queue.Queue             `autowire:"@"`
 
 	upgrader websocket.Upgrader
 
@@ -298,6 +303,10 @@ func (ctl *OpenAIController) Chat(ctx context.Context, webCtx web.Context, user
 	subCtx, subCancel := context.WithCancel(ctx)
 	sw.SetOnClosed(subCancel)
 
+	// 记录实际回答问题的渠道，用于消息反馈的质量分析
+	trace := &control.Trace{}
+	subCtx = control.NewTraceContext(subCtx, trace)
+
 	if user.User.ID == 0 {
 		// 匿名用户，检查模型是否为免费模型
 		currentModel := ternary.If(req.TempModel != "", req.TempModel, req.Model)
@@ -323,6 +332,12 @@ func (ctl *OpenAIController) Chat(ctx context.Context, webCtx web.Context, user
 
 	// 请求参数预处理
 	var inputTokenCount, maxContextLen int64
+	// 是否启用长期记忆
+	var memoryEnabled bool
+	// 是否需要根据首轮对话自动生成数字人标题
+	var autoTitle bool
+	// 使用提示语模板时渲染后的系统提示语，模板变量由用户填写，需要与用户消息一起进行内容安全检测
+	var templatePrompt string
 
 	if ctl.apiMode {
 		// API 模式下，还原 n 参数原始值（不支持 room 上下文配置）
@@ -355,6 +370,35 @@ func (ctl *OpenAIController) Chat(ctx context.Context, webCtx web.Context, user
 			req = req.ReplaceSystemPrompt(room.SystemPrompt)
 		}
 
+		// 请求中未指定的生成参数使用数字人的预设值
+		if room != nil {
+			req = ApplyRoomPresets(req, repo.RoomPresetsOf(room.PresetsJson))
+		}
+
+		// 使用提示语模板时，以渲染后的模板替换系统提示语
+		if req.PromptTemplateID > 0 {
+			templatePrompt, err = ctl.renderPromptTemplate(subCtx, user.User.ID, req)
+			if err != nil {
+				misc.NoError(sw.WriteErrorStream(errors.New(ctl.buildMessageBox(client, "error", err.Error())), http.StatusBadRequest))
+				return
+			}
+
+			req = req.ReplaceSystemPrompt(templatePrompt)
+		}
+
+		// 长期记忆，选择与当前问题相关的记忆合并到系统提示语中
+		memoryEnabled = ctl.memoryEnabled(subCtx, user.User.ID, room)
+		if memoryEnabled {
+			req = req.MergeSystemPrompt(ctl.relevantMemoryPrompt(subCtx, user.User.ID, req))
+		}
+
+		autoTitle = room != nil && room.Id > 1 && room.AutoTitle == 1
+
+		// 置顶的消息总是包含在上下文中，位于系统提示语之后
+		if room != nil && room.Id > 1 {
+			req = req.WithPinnedMessages(ctl.pinnedMessages(subCtx, user.User.ID, room.Id))
+		}
+
 		maxTokens := ternary.If(
 			user.User.ID > 0,
 			ternary.If(mod.Meta.MaxContext > 0, mod.Meta.MaxContext, 1000*200),
@@ -420,7 +464,7 @@ func (ctl *OpenAIController) Chat(ctx context.Context, webCtx web.Context, user
 	}
 
 	// 内容安全检测
-	if err := ctl.contentSafety(req, user.User, sw); err != nil {
+	if err := ctl.contentSafety(req, templatePrompt, user.User, sw); err != nil {
 		return
 	}
 
@@ -476,7 +520,8 @@ func (ctl *OpenAIController) Chat(ctx context.Context, webCtx web.Context, user
 						Content: item.Content,
 					}
 				}),
-				ResultCount: req.SearchCount,
+				ResultCount:  req.SearchCount,
+				PreferEngine: req.SearchEngine,
 			})
 			if err != nil {
 				log.F(log.M{"model": req.Model, "message": req.Messages.ToLogEntry()}).Errorf("search failed: %v", err)
@@ -530,7 +575,18 @@ func (ctl *OpenAIController) Chat(ctx context.Context, webCtx web.Context, user
 		defer cancel()
 
 		// 写入用户消息
-		answerID := ctl.saveChatAnswer(ctx, user.User, replyText, thinkingProcess, quotaConsume.TotalPrice, quotaConsume.TotalTokens(), req, questionID, chatErrorMessage)
+		answerID := ctl.saveChatAnswer(ctx, user.User, replyText, thinkingProcess, quotaConsume.TotalPrice, quotaConsume.TotalTokens(), req, trace.Channel, questionID, chatErrorMessage)
+		if answerID > 0 {
+			ctl.svc.Sync.Notify(ctx, user.User.ID, client.DeviceID, repo.SyncEntityMessage, questionID, answerID)
+		}
+
+		if memoryEnabled && chatErrorMessage == "" && replyText != "" {
+			ctl.extractMemories(user.User.ID, req, replyText)
+		}
+
+		if autoTitle && chatErrorMessage == "" && replyText != "" {
+			ctl.generateRoomTitle(user.User.ID, req, replyText)
+		}
 
 		if errors.Is(ErrChatResponseEmpty, err) {
 			misc.NoError(sw.WriteErrorStream(err, http.StatusInternalServerError))
@@ -695,6 +751,42 @@ func (ctl *OpenAIController) handleChat(
 		return "", ThinkingProcess{}, ErrChatShouldRetry
 	}
 
+	writeChatEvent := func(event ChatCompletionStreamResponse) error {
+		return sw.WriteStream(event)
+	}
+
+	// 模型回复内容审核，检查通过的内容才会输出，发现违规后中断模型输出
+	var checker *moderation.StreamChecker
+	if !ctl.apiMode && ctl.securitySrv.AnswerDetectEnabled() {
+		var stopChat context.CancelFunc
+		chatCtx, stopChat = context.WithCancel(chatCtx)
+		defer stopChat()
+
+		checker = moderation.NewStreamChecker(ctl.securitySrv.AnswerDetect)
+		writeChatEvent = func(event ChatCompletionStreamResponse) error {
+			if checker.Violation() != nil {
+				return nil
+			}
+
+			if event.Type != "chat" || len(event.Choices) == 0 {
+				return sw.WriteStream(event)
+			}
+
+			content, violation := checker.Write(event.Choices[0].Delta.Content)
+			if violation != nil {
+				stopChat()
+				return nil
+			}
+
+			if content == "" {
+				return nil
+			}
+
+			event.Choices[0].Delta.Content = content
+			return sw.WriteStream(event)
+		}
+	}
+
 	replyText, thinkingProcess, err := HandleChatResponse(chatCtx, req, stream, &EventHandler{
 		RequestContext: map[string]any{
 			"user_id": user.ID,
@@ -704,10 +796,29 @@ func (ctl *OpenAIController) handleChat(
 			ctl.writeControlMessage(sw, client, req.Model, event)
 			return nil
 		},
-		WriteChatEvent: func(event ChatCompletionStreamResponse) error {
-			return sw.WriteStream(event)
-		},
+		WriteChatEvent: writeChatEvent,
 	})
+
+	if checker != nil {
+		if content, violation := checker.Flush(); violation == nil && content != "" {
+			misc.NoError(sw.WriteStream(buildChatCompletionStreamResponse(req.Model, 99999, "chat", content)))
+		}
+
+		if violation := checker.Violation(); violation != nil {
+			log.F(log.M{
+				"user_id":     user.ID,
+				"question_id": questionID,
+				"model":       req.Model,
+				"details":     violation.ReasonDetail(),
+				"answer":      replyText,
+			}).Warningf("模型 %s 回复内容违规，已中断输出，违规内容：%s", req.Model, violation.Reason)
+
+			ctl.makeChatQuestionFailed(ctx, questionID, errors.New("回复内容违规"))
+			ctl.sendViolateAnswerPolicyResp(sw, req.Model, violation.ReasonDetail())
+			return "", ThinkingProcess{}, ErrChatResponseHasSent
+		}
+	}
+
 	if err != nil {
 		return replyText, thinkingProcess, err
 	}
@@ -868,7 +979,7 @@ func (ctl *OpenAIController) rateLimitPass(ctx context.Context, client *auth.Cli
 	return nil
 }
 
-func (ctl *OpenAIController) saveChatAnswer(ctx context.Context, user *auth.User, replyText string, thinkingProcess ThinkingProcess, quotaConsumed int64, realWordCount int, req *chat.Request, questionID int64, chatErrorMessage string) int64 {
+func (ctl *OpenAIController) saveChatAnswer(ctx context.Context, user *auth.User, replyText string, thinkingProcess ThinkingProcess, quotaConsumed int64, realWordCount int, req *chat.Request, channel string, questionID int64, chatErrorMessage string) int64 {
 	if ctl.conf.EnableRecordChat && !ctl.apiMode {
 		answerID, err := ctl.messageRepo.Add(ctx, repo.MessageAddReq{
 			UserID:        user.ID,
@@ -886,6 +997,7 @@ func (ctl *OpenAIController) saveChatAnswer(ctx context.Context, user *auth.User
 				ReasoningContent:      thinkingProcess.Content,
 				ReasoningTimeConsumed: thinkingProcess.TimeConsumed,
 			},
+			Channel: channel,
 		}, false)
 		if err != nil {
 			log.With(req).Errorf("add message failed: %s", err)
@@ -1006,18 +1118,160 @@ func (ctl *OpenAIController) loadRoomContext(ctx context.Context, roomID int64,
 	return maxContextMessageCount, nil
 }
 
-// 内容安全检测
-func (ctl *OpenAIController) contentSafety(req *chat.Request, user *auth.User, sw *streamwriter.StreamWriter) error {
+// memoryEnabled 判断本次对话是否使用长期记忆：用户已开启长期记忆，并且当前数字人没有禁用
+func (ctl *OpenAIController) memoryEnabled(ctx context.Context, userID int64, room *model.Rooms) bool {
+	if userID <= 0 || (room != nil && room.MemoryDisabled == 1) {
+		return false
+	}
+
+	cus, err := ctl.svc.User.CustomConfig(ctx, userID)
+	if err != nil {
+		log.F(log.M{"user_id": userID}).Errorf("查询用户自定义配置失败: %s", err)
+		return false
+	}
+
+	return cus.MemoryEnabled
+}
+
+// ChatFlagNoSearch 客户端明确关闭本次对话的联网搜索，不使用数字人预设的联网搜索设置
+const ChatFlagNoSearch = "no-search"
+
+// ApplyRoomPresets 请求中未指定的生成参数使用数字人的预设值
+func ApplyRoomPresets(req *chat.Request, presets repo.RoomPresets) *chat.Request {
+	if req.Temperature == 0 {
+		req.Temperature = presets.Temperature
+	}
+
+	if req.MaxTokens == 0 {
+		req.MaxTokens = presets.MaxTokens
+	}
+
+	if req.ReasoningEffort == "" {
+		req.ReasoningEffort = presets.ReasoningEffort
+	}
+
+	if req.SearchEngine == "" {
+		req.SearchEngine = presets.SearchEngine
+	}
+
+	if presets.EnableSearch && !req.EnableSearch() && !array.In(ChatFlagNoSearch, req.Flags) {
+		req.Flags = append(req.Flags, "search")
+	}
+
+	return req
+}
+
+// pinnedMessages 查询房间中置顶的消息
+func (ctl *OpenAIController) pinnedMessages(ctx context.Context, userID, roomID int64) chat.Messages {
+	messages, err := ctl.messageRepo.PinnedMessages(ctx, userID, roomID)
+	if err != nil {
+		log.F(log.M{"user_id": userID, "room_id": roomID}).Errorf("查询置顶消息失败: %s", err)
+		return nil
+	}
+
+	return array.Map(messages, func(msg model.ChatMessages, _ int) chat.Message {
+		return chat.Message{
+			Role:    ternary.If(repo.MessageRole(msg.Role) == repo.MessageRoleUser, "user", "assistant"),
+			Content: msg.Message,
+		}
+	})
+}
+
+// relevantMemoryPrompt 查询与用户最后一条消息相关的记忆，构建为系统提示语
+func (ctl *OpenAIController) relevantMemoryPrompt(ctx context.Context, userID int64, req *chat.Request) string {
+	if len(req.Messages) == 0 {
+		return ""
+	}
+
+	memories, err := ctl.repo.Memory.Memories(ctx, userID)
+	if err != nil {
+		log.F(log.M{"user_id": userID}).Errorf("查询用户记忆失败: %s", err)
+		return ""
+	}
+
+	selected := memory.Select(
+		req.Messages[len(req.Messages)-1].Content,
+		array.Map(memories, func(m model.UserMemory, _ int) memory.Memory { return memory.Memory{ID: m.Id, Content: m.Content} }),
+		5,
+	)
+
+	return memory.BuildPrompt(selected)
+}
+
+// extractMemories 异步从本轮对话中提取用户的长期记忆
+func (ctl *OpenAIController) extractMemories(userID int64, req *chat.Request, answer string) {
+	if len(req.Messages) == 0 || req.Messages[len(req.Messages)-1].Role != "user" {
+		return
+	}
+
+	payload := queue.MemoryExtractPayload{
+		UserID:    userID,
+		RoomID:    req.RoomID,
+		Question:  req.Messages[len(req.Messages)-1].Content,
+		Answer:    answer,
+		CreatedAt: time.Now(),
+	}
+
+	if _, err := ctl.queue.Enqueue(&payload, queue.NewMemoryExtractTask); err != nil {
+		log.F(log.M{"user_id": userID, "room_id": req.RoomID}).Errorf("创建长期记忆提取任务失败: %s", err)
+	}
+}
+
+// renderPromptTemplate 使用用户提交的变量渲染提示语模板
+func (ctl *OpenAIController) renderPromptTemplate(ctx context.Context, userID int64, req *chat.Request) (string, error) {
+	tpl, err := ctl.repo.PromptTemplate.UsableTemplate(ctx, userID, req.PromptTemplateID)
+	if err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return "", errors.New("提示语模板不存在")
+		}
+
+		log.F(log.M{"user_id": userID, "template_id": req.PromptTemplateID}).Errorf("查询提示语模板失败: %s", err)
+		return "", errors.New(common.ErrInternalError)
+	}
+
+	return tpl.Render(req.PromptVariables)
+}
+
+// generateRoomTitle 异步根据首轮对话生成数字人的标题和描述
+func (ctl *OpenAIController) generateRoomTitle(userID int64, req *chat.Request, answer string) {
+	if len(req.Messages) == 0 || req.Messages[len(req.Messages)-1].Role != "user" {
+		return
+	}
+
+	payload := queue.RoomTitlePayload{
+		UserID:    userID,
+		RoomID:    req.RoomID,
+		Question:  req.Messages[len(req.Messages)-1].Content,
+		Answer:    answer,
+		CreatedAt: time.Now(),
+	}
+
+	if _, err := ctl.queue.Enqueue(&payload, queue.NewRoomTitleTask); err != nil {
+		log.F(log.M{"user_id": userID, "room_id": req.RoomID}).Errorf("创建数字人标题生成任务失败: %s", err)
+	}
+}
+
+// 内容安全检测，检测用户的最后一条消息以及渲染后的提示语模板（为空时不检测）
+func (ctl *OpenAIController) contentSafety(req *chat.Request, templatePrompt string, user *auth.User, sw *streamwriter.StreamWriter) error {
 	// API 模式下，不进行内容安全检测
 	if ctl.apiMode {
 		return nil
 	}
 
-	if len(req.Messages) == 0 {
+	contents := make([]string, 0, 2)
+	if len(req.Messages) > 0 {
+		contents = append(contents, req.Messages[len(req.Messages)-1].Content)
+	}
+
+	if templatePrompt != "" {
+		contents = append(contents, templatePrompt)
+	}
+
+	if len(contents) == 0 {
 		return nil
 	}
 
-	content := req.Messages[len(req.Messages)-1].Content
+	content := strings.Join(contents, "\n")
 	if checkRes := ctl.securitySrv.ChatDetect(content); checkRes != nil {
 		if checkRes.IsReallyUnSafe() {
 			log.F(log.M{"user_id": user.ID, "details": checkRes.ReasonDetail(), "content": content}).Warningf("用户 %d 违规，违规内容：%s", user.ID, checkRes.Reason)
@@ -1044,6 +1298,18 @@ func (ctl *OpenAIController) sendViolateContentPolicyResp(sw *streamwriter.Strea
 	)))
 }
 
+const violateAnswerPolicyMessage = "\n\n---\n\n抱歉，本次回复包含违规内容，已被系统中断，请尝试调整您的问题。如果您对此有任何疑问，欢迎通过以下渠道与我们联系：\n\n服务邮箱：support@aicode.cc\n\n微博：@mylxsw\n\n客服微信：x-prometheus\n\n\n---\n\n> 本次请求不扣除智慧果。"
+
+// sendViolateAnswerPolicyResp 模型回复内容违规时，使用提示信息代替剩余的回复内容
+func (ctl *OpenAIController) sendViolateAnswerPolicyResp(sw *streamwriter.StreamWriter, model string, detail string) {
+	reason := violateAnswerPolicyMessage
+	if detail != "" {
+		reason += fmt.Sprintf("\n> \n> 原因：%s", detail)
+	}
+
+	misc.NoError(sw.WriteStream(buildChatCompletionStreamResponse(model, 99999, "chat", reason)))
+}
+
 // Images 图像生成接口，接口参数参考 https://platform.openai.com/docs/api-reference/images/create
 func (ctl *OpenAIController) Images(ctx context.Context, webCtx web.Context, user *auth.User, quotaRepo *repo.QuotaRepo) web.Response {
 	var req openai.ImageRequest
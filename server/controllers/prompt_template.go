@@ -0,0 +1,413 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mylxsw/aidea-server/pkg/prompt"
+	"github.com/mylxsw/aidea-server/pkg/repo"
+	"github.com/mylxsw/aidea-server/pkg/service"
+	"github.com/mylxsw/aidea-server/pkg/youdao"
+	"github.com/mylxsw/aidea-server/server/auth"
+	"github.com/mylxsw/aidea-server/server/controllers/common"
+	"github.com/mylxsw/asteria/log"
+	"github.com/mylxsw/glacier/infra"
+	"github.com/mylxsw/glacier/web"
+	"github.com/mylxsw/go-utils/array"
+)
+
+// PromptTemplateController 用户自定义提示语模板
+type PromptTemplateController struct {
+	repo        *repo.Repository         `autowire:"@"`
+	translater  youdao.Translater        `autowire:"@"`
+	securitySrv *service.SecurityService `autowire:"@"`
+}
+
+func NewPromptTemplateController(resolver infra.Resolver) web.Controller {
+	ctl := PromptTemplateController{}
+	resolver.MustAutoWire(&ctl)
+	return &ctl
+}
+
+func (ctl *PromptTemplateController) Register(router web.Router) {
+	router.Group("/prompt-templates", func(router web.Router) {
+		router.Get("/", ctl.Templates)
+		router.Post("/", ctl.CreateTemplate)
+
+		// 公共模板库
+		router.Get("/gallery", ctl.Gallery)
+		router.Get("/gallery/{id}", ctl.GalleryItem)
+		router.Post("/gallery/{id}/copy", ctl.CopyGalleryItem)
+
+		router.Get("/{id}", ctl.Template)
+		router.Put("/{id}", ctl.UpdateTemplate)
+		router.Delete("/{id}", ctl.DeleteTemplate)
+		router.Put("/{id}/publish", ctl.PublishTemplate)
+		router.Post("/{id}/render", ctl.RenderTemplate)
+	})
+}
+
+// ParsePromptTemplateInput 解析并校验用户提交的模板内容
+func ParsePromptTemplateInput(webCtx web.Context) (*repo.PromptTemplateInput, error) {
+	var in repo.PromptTemplateInput
+	if err := webCtx.Unmarshal(&in); err != nil {
+		return nil, errors.New("请求参数格式错误")
+	}
+
+	in.Title = strings.TrimSpace(in.Title)
+	if in.Title == "" {
+		return nil, errors.New("模板标题不能为空")
+	}
+
+	if utf8.RuneCountInString(in.Title) > 50 {
+		return nil, errors.New("模板标题不能超过 50 个字符")
+	}
+
+	in.Description = strings.TrimSpace(in.Description)
+	if utf8.RuneCountInString(in.Description) > 200 {
+		return nil, errors.New("模板描述不能超过 200 个字符")
+	}
+
+	if strings.TrimSpace(in.Content) == "" {
+		return nil, errors.New("模板内容不能为空")
+	}
+
+	if utf8.RuneCountInString(in.Content) > 10000 {
+		return nil, errors.New("模板内容不能超过 10000 个字符")
+	}
+
+	if err := prompt.Validate(in.Content, in.Variables); err != nil {
+		return nil, err
+	}
+
+	in.Tags = array.Uniq(array.Filter(
+		array.Map(in.Tags, func(tag string, _ int) string { return strings.TrimSpace(tag) }),
+		func(tag string, _ int) bool { return tag != "" },
+	))
+	if len(in.Tags) > repo.PromptTemplateMaxTags {
+		return nil, errors.New("模板标签不能超过 5 个")
+	}
+
+	for _, tag := range in.Tags {
+		if utf8.RuneCountInString(tag) > 20 {
+			return nil, errors.New("模板标签不能超过 20 个字符")
+		}
+	}
+
+	return &in, nil
+}
+
+func (ctl *PromptTemplateController) templateID(webCtx web.Context) (int64, error) {
+	id, err := strconv.Atoi(webCtx.PathVar("id"))
+	if err != nil || id <= 0 {
+		return 0, errors.New("invalid template id")
+	}
+
+	return int64(id), nil
+}
+
+// Templates 获取用户的提示语模板列表
+// @Summary 获取用户的提示语模板列表
+// @Tags PromptTemplate
+// @Produce json
+// @Param tag query string false "标签"
+// @Success 200 {object} common.DataArray[repo.PromptTemplate]
+// @Router /v1/prompt-templates [get]
+func (ctl *PromptTemplateController) Templates(ctx context.Context, webCtx web.Context, user *auth.User) web.Response {
+	templates, err := ctl.repo.PromptTemplate.Templates(ctx, user.ID, strings.TrimSpace(webCtx.Input("tag")))
+	if err != nil {
+		log.F(log.M{"user_id": user.ID}).Errorf("查询提示语模板失败: %v", err)
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInternalError), http.StatusInternalServerError)
+	}
+
+	return webCtx.JSON(common.NewDataArray(templates))
+}
+
+// Template 获取提示语模板详情
+// @Summary 获取提示语模板详情
+// @Tags PromptTemplate
+// @Produce json
+// @Param id path int true "模板 ID"
+// @Success 200 {object} repo.PromptTemplate
+// @Router /v1/prompt-templates/{id} [get]
+func (ctl *PromptTemplateController) Template(ctx context.Context, webCtx web.Context, user *auth.User) web.Response {
+	id, err := ctl.templateID(webCtx)
+	if err != nil {
+		return webCtx.JSONError(err.Error(), http.StatusBadRequest)
+	}
+
+	tpl, err := ctl.repo.PromptTemplate.Template(ctx, user.ID, id)
+	if err != nil {
+		return ctl.templateError(webCtx, user.ID, id, err)
+	}
+
+	return webCtx.JSON(tpl)
+}
+
+func (ctl *PromptTemplateController) templateError(webCtx web.Context, userID, id int64, err error) web.Response {
+	if errors.Is(err, repo.ErrNotFound) {
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, "提示语模板不存在"), http.StatusNotFound)
+	}
+
+	log.F(log.M{"user_id": userID, "template_id": id}).Errorf("查询提示语模板失败: %v", err)
+	return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInternalError), http.StatusInternalServerError)
+}
+
+// CreateTemplate 创建提示语模板
+// @Summary 创建提示语模板
+// @Tags PromptTemplate
+// @Accept json
+// @Param body body repo.PromptTemplateInput true "模板内容"
+// @Success 200 {object} common.IDResponse
+// @Router /v1/prompt-templates [post]
+func (ctl *PromptTemplateController) CreateTemplate(ctx context.Context, webCtx web.Context, user *auth.User) web.Response {
+	in, err := ParsePromptTemplateInput(webCtx)
+	if err != nil {
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, err.Error()), http.StatusBadRequest)
+	}
+
+	id, err := ctl.repo.PromptTemplate.Create(ctx, user.ID, *in)
+	if err != nil {
+		if errors.Is(err, repo.ErrPromptTemplateLimitExceeded) {
+			return webCtx.JSONError(common.Text(webCtx, ctl.translater, "提示语模板数量已达上限"), http.StatusBadRequest)
+		}
+
+		log.F(log.M{"user_id": user.ID}).Errorf("创建提示语模板失败: %v", err)
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInternalError), http.StatusInternalServerError)
+	}
+
+	return webCtx.JSON(common.NewIDResponse(id))
+}
+
+// UpdateTemplate 更新提示语模板
+// @Summary 更新提示语模板
+// @Tags PromptTemplate
+// @Accept json
+// @Param id path int true "模板 ID"
+// @Param body body repo.PromptTemplateInput true "模板内容"
+// @Success 200 {object} any
+// @Router /v1/prompt-templates/{id} [put]
+func (ctl *PromptTemplateController) UpdateTemplate(ctx context.Context, webCtx web.Context, user *auth.User) web.Response {
+	id, err := ctl.templateID(webCtx)
+	if err != nil {
+		return webCtx.JSONError(err.Error(), http.StatusBadRequest)
+	}
+
+	in, err := ParsePromptTemplateInput(webCtx)
+	if err != nil {
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, err.Error()), http.StatusBadRequest)
+	}
+
+	tpl, err := ctl.repo.PromptTemplate.Template(ctx, user.ID, id)
+	if err != nil {
+		return ctl.templateError(webCtx, user.ID, id, err)
+	}
+
+	// 已发布的模板修改后立即在公共模板库中生效，需要重新进行内容安全检测
+	if tpl.Published && !ctl.contentSafety(user.ID, id, *in) {
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, "内容违规，已被系统拦截"), http.StatusNotAcceptable)
+	}
+
+	if err := ctl.repo.PromptTemplate.Update(ctx, user.ID, id, *in); err != nil {
+		log.F(log.M{"user_id": user.ID, "template_id": id}).Errorf("更新提示语模板失败: %v", err)
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInternalError), http.StatusInternalServerError)
+	}
+
+	return webCtx.JSON(web.M{})
+}
+
+// DeleteTemplate 删除提示语模板
+// @Summary 删除提示语模板
+// @Tags PromptTemplate
+// @Param id path int true "模板 ID"
+// @Success 200 {object} any
+// @Router /v1/prompt-templates/{id} [delete]
+func (ctl *PromptTemplateController) DeleteTemplate(ctx context.Context, webCtx web.Context, user *auth.User) web.Response {
+	id, err := ctl.templateID(webCtx)
+	if err != nil {
+		return webCtx.JSONError(err.Error(), http.StatusBadRequest)
+	}
+
+	if err := ctl.repo.PromptTemplate.Delete(ctx, user.ID, id); err != nil {
+		log.F(log.M{"user_id": user.ID, "template_id": id}).Errorf("删除提示语模板失败: %v", err)
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInternalError), http.StatusInternalServerError)
+	}
+
+	return webCtx.JSON(web.M{})
+}
+
+// PublishTemplate 发布提示语模板到公共模板库，或者从公共模板库中撤下
+// @Summary 发布提示语模板到公共模板库，或者从公共模板库中撤下
+// @Tags PromptTemplate
+// @Param id path int true "模板 ID"
+// @Param published formData bool true "是否发布"
+// @Success 200 {object} any
+// @Router /v1/prompt-templates/{id}/publish [put]
+func (ctl *PromptTemplateController) PublishTemplate(ctx context.Context, webCtx web.Context, user *auth.User) web.Response {
+	id, err := ctl.templateID(webCtx)
+	if err != nil {
+		return webCtx.JSONError(err.Error(), http.StatusBadRequest)
+	}
+
+	tpl, err := ctl.repo.PromptTemplate.Template(ctx, user.ID, id)
+	if err != nil {
+		return ctl.templateError(webCtx, user.ID, id, err)
+	}
+
+	published := webCtx.Input("published") == "true"
+	if published && !ctl.contentSafety(user.ID, id, repo.PromptTemplateInput{
+		Title:       tpl.Title,
+		Description: tpl.Description,
+		Content:     tpl.Content,
+		Tags:        tpl.Tags,
+	}) {
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, "内容违规，已被系统拦截"), http.StatusNotAcceptable)
+	}
+
+	if err := ctl.repo.PromptTemplate.Publish(ctx, user.ID, id, published); err != nil {
+		log.F(log.M{"user_id": user.ID, "template_id": id}).Errorf("发布提示语模板失败: %v", err)
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInternalError), http.StatusInternalServerError)
+	}
+
+	return webCtx.JSON(web.M{})
+}
+
+// contentSafety 对发布到公共模板库的内容进行安全检测，内容违规时返回 false
+func (ctl *PromptTemplateController) contentSafety(userID, id int64, in repo.PromptTemplateInput) bool {
+	content := strings.Join(append([]string{in.Title, in.Description, in.Content}, in.Tags...), "\n")
+	if checkRes := ctl.securitySrv.ChatDetect(content); checkRes != nil && checkRes.IsReallyUnSafe() {
+		log.F(log.M{"user_id": userID, "template_id": id, "details": checkRes.ReasonDetail(), "content": content}).
+			Warningf("用户 %d 提示语模板违规，违规内容：%s", userID, checkRes.Reason)
+		return false
+	}
+
+	return true
+}
+
+// PromptTemplateRenderRequest 渲染模板时提交的变量取值
+type PromptTemplateRenderRequest struct {
+	Variables map[string]string `json:"variables"`
+}
+
+// RenderTemplate 使用变量渲染提示语模板，可以渲染自己的模板以及公共模板库中的模板
+// @Summary 使用变量渲染提示语模板
+// @Tags PromptTemplate
+// @Accept json
+// @Param id path int true "模板 ID"
+// @Param body body PromptTemplateRenderRequest true "变量取值"
+// @Success 200 {object} common.DataObj[string]
+// @Router /v1/prompt-templates/{id}/render [post]
+func (ctl *PromptTemplateController) RenderTemplate(ctx context.Context, webCtx web.Context, user *auth.User) web.Response {
+	id, err := ctl.templateID(webCtx)
+	if err != nil {
+		return webCtx.JSONError(err.Error(), http.StatusBadRequest)
+	}
+
+	var req PromptTemplateRenderRequest
+	if err := webCtx.Unmarshal(&req); err != nil {
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, "请求参数格式错误"), http.StatusBadRequest)
+	}
+
+	tpl, err := ctl.repo.PromptTemplate.UsableTemplate(ctx, user.ID, id)
+	if err != nil {
+		return ctl.templateError(webCtx, user.ID, id, err)
+	}
+
+	content, err := tpl.Render(req.Variables)
+	if err != nil {
+		return webCtx.JSONError(err.Error(), http.StatusBadRequest)
+	}
+
+	return webCtx.JSON(common.NewDataObj(content))
+}
+
+// Gallery 公共模板库
+// @Summary 公共模板库
+// @Tags PromptTemplate
+// @Produce json
+// @Param tag query string false "标签"
+// @Param keyword query string false "关键词"
+// @Param sort query string false "排序方式：hot/latest"
+// @Param page query int false "页码"
+// @Param per_page query int false "每页数量"
+// @Success 200 {object} common.Pagination[repo.PromptTemplate]
+// @Router /v1/prompt-templates/gallery [get]
+func (ctl *PromptTemplateController) Gallery(ctx context.Context, webCtx web.Context, user *auth.User) web.Response {
+	filter := repo.PromptTemplateFilter{
+		Tag:     strings.TrimSpace(webCtx.Input("tag")),
+		Keyword: strings.TrimSpace(webCtx.Input("keyword")),
+		Sort:    webCtx.Input("sort"),
+	}
+
+	if filter.Sort != "" && !array.In(filter.Sort, []string{repo.PromptTemplatesSortHot, repo.PromptTemplatesSortLatest}) {
+		return webCtx.JSONError("invalid sort", http.StatusBadRequest)
+	}
+
+	page := webCtx.Int64Input("page", 1)
+	if page < 1 || page > 1000 {
+		page = 1
+	}
+
+	perPage := webCtx.Int64Input("per_page", 20)
+	if perPage < 1 || perPage > 100 {
+		perPage = 20
+	}
+
+	items, meta, err := ctl.repo.PromptTemplate.PublishedTemplates(ctx, filter, page, perPage)
+	if err != nil {
+		log.F(log.M{"user_id": user.ID}).Errorf("查询公共提示语模板失败: %v", err)
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInternalError), http.StatusInternalServerError)
+	}
+
+	return webCtx.JSON(common.NewPagination(items, meta))
+}
+
+// GalleryItem 公共模板库中的模板详情
+// @Summary 公共模板库中的模板详情
+// @Tags PromptTemplate
+// @Produce json
+// @Param id path int true "模板 ID"
+// @Success 200 {object} repo.PromptTemplate
+// @Router /v1/prompt-templates/gallery/{id} [get]
+func (ctl *PromptTemplateController) GalleryItem(ctx context.Context, webCtx web.Context, user *auth.User) web.Response {
+	id, err := ctl.templateID(webCtx)
+	if err != nil {
+		return webCtx.JSONError(err.Error(), http.StatusBadRequest)
+	}
+
+	tpl, err := ctl.repo.PromptTemplate.PublishedTemplate(ctx, id)
+	if err != nil {
+		return ctl.templateError(webCtx, user.ID, id, err)
+	}
+
+	tpl.UserID = 0
+	return webCtx.JSON(tpl)
+}
+
+// CopyGalleryItem 复制公共模板库中的模板到自己的模板列表
+// @Summary 复制公共模板库中的模板到自己的模板列表
+// @Tags PromptTemplate
+// @Param id path int true "模板 ID"
+// @Success 200 {object} common.IDResponse
+// @Router /v1/prompt-templates/gallery/{id}/copy [post]
+func (ctl *PromptTemplateController) CopyGalleryItem(ctx context.Context, webCtx web.Context, user *auth.User) web.Response {
+	id, err := ctl.templateID(webCtx)
+	if err != nil {
+		return webCtx.JSONError(err.Error(), http.StatusBadRequest)
+	}
+
+	newID, err := ctl.repo.PromptTemplate.Copy(ctx, user.ID, id)
+	if err != nil {
+		if errors.Is(err, repo.ErrPromptTemplateLimitExceeded) {
+			return webCtx.JSONError(common.Text(webCtx, ctl.translater, "提示语模板数量已达上限"), http.StatusBadRequest)
+		}
+
+		return ctl.templateError(webCtx, user.ID, id, err)
+	}
+
+	return webCtx.JSON(common.NewIDResponse(newID))
+}
@@ -0,0 +1,315 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mylxsw/aidea-server/pkg/repo"
+	"github.com/mylxsw/aidea-server/pkg/repo/model"
+	"github.com/mylxsw/aidea-server/server/auth"
+	"github.com/mylxsw/aidea-server/server/controllers/common"
+	"github.com/mylxsw/asteria/log"
+	"github.com/mylxsw/eloquent/query"
+	"github.com/mylxsw/glacier/web"
+	"github.com/mylxsw/go-utils/array"
+)
+
+// ParseRoomFilter 从请求参数中解析房间列表的过滤以及排序条件
+func ParseRoomFilter(webCtx web.Context) (repo.RoomFilter, error) {
+	filter := repo.RoomFilter{
+		Tag:      strings.TrimSpace(webCtx.Input("tag")),
+		Keyword:  strings.TrimSpace(webCtx.Input("keyword")),
+		Archived: webCtx.Input("archived"),
+		Sort:     webCtx.Input("sort"),
+	}
+
+	if folderID := webCtx.Input("folder_id"); folderID != "" {
+		id, err := strconv.ParseInt(folderID, 10, 64)
+		if err != nil || id < 0 {
+			return filter, errors.New("invalid folder_id")
+		}
+
+		filter.FolderID = &id
+	}
+
+	if !array.In(filter.Archived, []string{repo.RoomsArchivedExclude, repo.RoomsArchivedOnly, repo.RoomsArchivedAll}) {
+		return filter, errors.New("invalid archived")
+	}
+
+	if filter.Sort != "" && !array.In(filter.Sort, []string{repo.RoomsSortActive, repo.RoomsSortCreated, repo.RoomsSortName}) {
+		return filter, errors.New("invalid sort")
+	}
+
+	filter.PinnedOnly = webCtx.Input("pinned") == "true"
+
+	return filter, nil
+}
+
+// Folders 获取用户的数字人文件夹列表
+func (ctl *RoomController) Folders(ctx context.Context, webCtx web.Context, user *auth.User) web.Response {
+	folders, err := ctl.roomRepo.Folders(ctx, user.ID)
+	if err != nil {
+		log.F(log.M{"user_id": user.ID}).Errorf("查询用户文件夹失败: %v", err)
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInternalError), http.StatusInternalServerError)
+	}
+
+	return webCtx.JSON(common.NewDataArray(folders))
+}
+
+func (ctl *RoomController) parseFolderName(webCtx web.Context) (string, error) {
+	name := strings.TrimSpace(webCtx.Input("name"))
+	if name == "" {
+		return "", errors.New("文件夹名称不能为空")
+	}
+
+	if utf8.RuneCountInString(name) > 20 {
+		return "", errors.New("文件夹名称不能超过 20 个字符")
+	}
+
+	return name, nil
+}
+
+// CreateFolder 创建数字人文件夹
+func (ctl *RoomController) CreateFolder(ctx context.Context, webCtx web.Context, user *auth.User) web.Response {
+	name, err := ctl.parseFolderName(webCtx)
+	if err != nil {
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, err.Error()), http.StatusBadRequest)
+	}
+
+	id, err := ctl.roomRepo.CreateFolder(ctx, user.ID, name, webCtx.Int64Input("sort", 0))
+	if err != nil {
+		if errors.Is(err, repo.ErrAlreadyExists) {
+			return webCtx.JSONError(common.Text(webCtx, ctl.translater, "文件夹名称已存在"), http.StatusBadRequest)
+		}
+
+		if errors.Is(err, repo.ErrRoomFolderLimitExceeded) {
+			return webCtx.JSONError(common.Text(webCtx, ctl.translater, "文件夹数量已达上限"), http.StatusBadRequest)
+		}
+
+		log.F(log.M{"user_id": user.ID, "name": name}).Errorf("创建文件夹失败: %v", err)
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInternalError), http.StatusInternalServerError)
+	}
+
+	return webCtx.JSON(common.NewIDResponse(id))
+}
+
+// UpdateFolder 更新数字人文件夹
+func (ctl *RoomController) UpdateFolder(ctx context.Context, webCtx web.Context, user *auth.User) web.Response {
+	folderID, err := strconv.Atoi(webCtx.PathVar("folder_id"))
+	if err != nil {
+		return webCtx.JSONError("invalid folder id", http.StatusBadRequest)
+	}
+
+	name, err := ctl.parseFolderName(webCtx)
+	if err != nil {
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, err.Error()), http.StatusBadRequest)
+	}
+
+	if _, err := ctl.roomRepo.Folder(ctx, user.ID, int64(folderID)); err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return webCtx.JSONError(common.Text(webCtx, ctl.translater, "文件夹不存在"), http.StatusNotFound)
+		}
+
+		log.F(log.M{"user_id": user.ID, "folder_id": folderID}).Errorf("查询文件夹失败: %v", err)
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInternalError), http.StatusInternalServerError)
+	}
+
+	if err := ctl.roomRepo.UpdateFolder(ctx, user.ID, int64(folderID), name, webCtx.Int64Input("sort", 0)); err != nil {
+		if errors.Is(err, repo.ErrAlreadyExists) {
+			return webCtx.JSONError(common.Text(webCtx, ctl.translater, "文件夹名称已存在"), http.StatusBadRequest)
+		}
+
+		log.F(log.M{"user_id": user.ID, "folder_id": folderID}).Errorf("更新文件夹失败: %v", err)
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInternalError), http.StatusInternalServerError)
+	}
+
+	return webCtx.JSON(web.M{})
+}
+
+// DeleteFolder 删除数字人文件夹，文件夹中的数字人会被移动到未分类
+func (ctl *RoomController) DeleteFolder(ctx context.Context, webCtx web.Context, user *auth.User) web.Response {
+	folderID, err := strconv.Atoi(webCtx.PathVar("folder_id"))
+	if err != nil {
+		return webCtx.JSONError("invalid folder id", http.StatusBadRequest)
+	}
+
+	if err := ctl.roomRepo.DeleteFolder(ctx, user.ID, int64(folderID)); err != nil {
+		log.F(log.M{"user_id": user.ID, "folder_id": folderID}).Errorf("删除文件夹失败: %v", err)
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInternalError), http.StatusInternalServerError)
+	}
+
+	return webCtx.JSON(web.M{})
+}
+
+// Tags 获取用户使用过的所有数字人标签
+func (ctl *RoomController) Tags(ctx context.Context, webCtx web.Context, user *auth.User) web.Response {
+	tags, err := ctl.roomRepo.Tags(ctx, user.ID)
+	if err != nil {
+		log.F(log.M{"user_id": user.ID}).Errorf("查询用户标签失败: %v", err)
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInternalError), http.StatusInternalServerError)
+	}
+
+	return webCtx.JSON(common.NewDataArray(tags))
+}
+
+type RoomTagsRequest struct {
+	Tags []string `json:"tags"`
+}
+
+// UpdateRoomTags 设置数字人的标签
+func (ctl *RoomController) UpdateRoomTags(ctx context.Context, webCtx web.Context, user *auth.User, client *auth.ClientInfo) web.Response {
+	roomID, err := strconv.Atoi(webCtx.PathVar("room_id"))
+	if err != nil || roomID <= 1 {
+		return webCtx.JSONError("invalid room id", http.StatusBadRequest)
+	}
+
+	var req RoomTagsRequest
+	if err := webCtx.Unmarshal(&req); err != nil {
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInvalidRequest), http.StatusBadRequest)
+	}
+
+	tags := array.Uniq(array.Filter(
+		array.Map(req.Tags, func(tag string, _ int) string { return strings.TrimSpace(tag) }),
+		func(tag string, _ int) bool { return tag != "" },
+	))
+	if len(tags) > repo.RoomTagMaxCount {
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, "标签数量不能超过 10 个"), http.StatusBadRequest)
+	}
+
+	for _, tag := range tags {
+		if utf8.RuneCountInString(tag) > 20 {
+			return webCtx.JSONError(common.Text(webCtx, ctl.translater, "标签不能超过 20 个字符"), http.StatusBadRequest)
+		}
+	}
+
+	if _, err := ctl.roomRepo.Room(ctx, user.ID, int64(roomID)); err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return webCtx.JSONError(common.Text(webCtx, ctl.translater, "数字人不存在"), http.StatusNotFound)
+		}
+
+		log.F(log.M{"user_id": user.ID, "room_id": roomID}).Errorf("查询用户房间失败: %v", err)
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInternalError), http.StatusInternalServerError)
+	}
+
+	if err := ctl.roomRepo.SetTags(ctx, user.ID, int64(roomID), tags); err != nil {
+		log.F(log.M{"user_id": user.ID, "room_id": roomID}).Errorf("更新数字人标签失败: %v", err)
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInternalError), http.StatusInternalServerError)
+	}
+
+	ctl.svc.Sync.Notify(ctx, user.ID, client.DeviceID, repo.SyncEntityRoom, int64(roomID))
+
+	return webCtx.JSON(common.NewDataArray(tags))
+}
+
+const (
+	RoomBatchActionMove      = "move"
+	RoomBatchActionPin       = "pin"
+	RoomBatchActionUnpin     = "unpin"
+	RoomBatchActionArchive   = "archive"
+	RoomBatchActionUnarchive = "unarchive"
+	RoomBatchActionDelete    = "delete"
+)
+
+type RoomBatchRequest struct {
+	Action  string  `json:"action"`
+	RoomIDs []int64 `json:"room_ids"`
+	// FolderID 移动到的文件夹 ID，0 表示移动到未分类，仅 move 操作有效
+	FolderID int64 `json:"folder_id,omitempty"`
+}
+
+type RoomBatchResponse struct {
+	Affected int64 `json:"affected"`
+}
+
+// BatchRooms 批量操作数字人：移动、置顶、归档、删除
+func (ctl *RoomController) BatchRooms(ctx context.Context, webCtx web.Context, user *auth.User, client *auth.ClientInfo) web.Response {
+	var req RoomBatchRequest
+	if err := webCtx.Unmarshal(&req); err != nil {
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInvalidRequest), http.StatusBadRequest)
+	}
+
+	// 默认房间不存储在数据库中，不支持批量操作
+	req.RoomIDs = array.Uniq(array.Filter(req.RoomIDs, func(id int64, _ int) bool { return id > 1 }))
+	if len(req.RoomIDs) == 0 || len(req.RoomIDs) > RoomsQueryLimit {
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInvalidRequest), http.StatusBadRequest)
+	}
+
+	var kv query.KV
+	switch req.Action {
+	case RoomBatchActionMove:
+		if req.FolderID > 0 {
+			if _, err := ctl.roomRepo.Folder(ctx, user.ID, req.FolderID); err != nil {
+				if errors.Is(err, repo.ErrNotFound) {
+					return webCtx.JSONError(common.Text(webCtx, ctl.translater, "文件夹不存在"), http.StatusNotFound)
+				}
+
+				log.F(log.M{"user_id": user.ID, "folder_id": req.FolderID}).Errorf("查询文件夹失败: %v", err)
+				return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInternalError), http.StatusInternalServerError)
+			}
+		}
+
+		kv = query.KV{model.FieldRoomsFolderId: req.FolderID}
+	case RoomBatchActionPin:
+		kv = query.KV{model.FieldRoomsPinned: 1}
+	case RoomBatchActionUnpin:
+		kv = query.KV{model.FieldRoomsPinned: 0}
+	case RoomBatchActionArchive:
+		kv = query.KV{model.FieldRoomsArchived: 1}
+	case RoomBatchActionUnarchive:
+		kv = query.KV{model.FieldRoomsArchived: 0}
+	case RoomBatchActionDelete:
+		return ctl.batchDeleteRooms(ctx, webCtx, user, client, req.RoomIDs)
+	default:
+		return webCtx.JSONError("invalid action", http.StatusBadRequest)
+	}
+
+	affected, err := ctl.roomRepo.BatchUpdate(ctx, user.ID, req.RoomIDs, kv)
+	if err != nil {
+		log.F(log.M{"user_id": user.ID, "action": req.Action, "room_ids": req.RoomIDs}).Errorf("批量更新数字人失败: %v", err)
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInternalError), http.StatusInternalServerError)
+	}
+
+	ctl.svc.Sync.Notify(ctx, user.ID, client.DeviceID, repo.SyncEntityRoom, req.RoomIDs...)
+
+	return webCtx.JSON(RoomBatchResponse{Affected: affected})
+}
+
+func (ctl *RoomController) batchDeleteRooms(ctx context.Context, webCtx web.Context, user *auth.User, client *auth.ClientInfo, roomIDs []int64) web.Response {
+	removed, err := ctl.roomRepo.BatchRemove(ctx, user.ID, roomIDs)
+	if err != nil {
+		log.F(log.M{"user_id": user.ID, "room_ids": roomIDs}).Errorf("批量删除数字人失败: %v", err)
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInternalError), http.StatusInternalServerError)
+	}
+
+	// 群聊需要同时删除成员以及讨论等信息
+	for _, id := range roomIDs {
+		if array.In(id, removed) {
+			continue
+		}
+
+		if _, err := ctl.groupRepo.GetGroup(ctx, id, user.ID); err != nil {
+			if !errors.Is(err, repo.ErrNotFound) {
+				log.F(log.M{"user_id": user.ID, "group_id": id}).Errorf("查询群组失败: %v", err)
+			}
+
+			continue
+		}
+
+		if err := ctl.groupRepo.DeleteGroup(ctx, id, user.ID, false); err != nil {
+			log.F(log.M{"user_id": user.ID, "group_id": id}).Errorf("删除群组失败: %v", err)
+			continue
+		}
+
+		removed = append(removed, id)
+	}
+
+	if len(removed) > 0 {
+		ctl.svc.Sync.Notify(ctx, user.ID, client.DeviceID, repo.SyncEntityRoom, removed...)
+	}
+
+	return webCtx.JSON(RoomBatchResponse{Affected: int64(len(removed))})
+}
@@ -0,0 +1,72 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/mylxsw/aidea-server/pkg/repo"
+	"github.com/mylxsw/aidea-server/server/auth"
+	"github.com/mylxsw/aidea-server/server/controllers/common"
+	"github.com/mylxsw/asteria/log"
+	"github.com/mylxsw/glacier/web"
+)
+
+// PinnedMessages 获取数字人中置顶到上下文的消息
+// @Summary 获取数字人中置顶到上下文的消息
+// @Tags Room
+// @Param room_id path int true "数字人 ID"
+// @Success 200 {object} common.DataArray[model.ChatMessages]
+// @Router /v1/rooms/{room_id}/pinned-messages [get]
+func (ctl *RoomController) PinnedMessages(ctx context.Context, webCtx web.Context, user *auth.User) web.Response {
+	roomID, err := strconv.Atoi(webCtx.PathVar("room_id"))
+	if err != nil || roomID <= 1 {
+		return webCtx.JSONError("invalid room id", http.StatusBadRequest)
+	}
+
+	messages, err := ctl.messageRepo.PinnedMessages(ctx, user.ID, int64(roomID))
+	if err != nil {
+		log.F(log.M{"user_id": user.ID, "room_id": roomID}).Errorf("查询置顶消息失败: %v", err)
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInternalError), http.StatusInternalServerError)
+	}
+
+	return webCtx.JSON(common.NewDataArray(messages))
+}
+
+// PinMessage 置顶或者取消置顶消息，置顶的消息总是包含在对话上下文中
+// @Summary 置顶或者取消置顶消息
+// @Tags Room
+// @Param room_id path int true "数字人 ID"
+// @Param message_id path int true "消息 ID"
+// @Param pinned formData bool true "是否置顶"
+// @Success 200 {object} common.DataArray[model.ChatMessages]
+// @Router /v1/rooms/{room_id}/messages/{message_id}/pin [put]
+func (ctl *RoomController) PinMessage(ctx context.Context, webCtx web.Context, user *auth.User, client *auth.ClientInfo) web.Response {
+	roomID, err := strconv.Atoi(webCtx.PathVar("room_id"))
+	if err != nil || roomID <= 1 {
+		return webCtx.JSONError("invalid room id", http.StatusBadRequest)
+	}
+
+	messageID, err := strconv.Atoi(webCtx.PathVar("message_id"))
+	if err != nil {
+		return webCtx.JSONError("invalid message id", http.StatusBadRequest)
+	}
+
+	if err := ctl.messageRepo.Pin(ctx, user.ID, int64(roomID), int64(messageID), webCtx.Input("pinned") == "true"); err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return webCtx.JSONError(common.Text(webCtx, ctl.translater, "消息不存在"), http.StatusNotFound)
+		}
+
+		if errors.Is(err, repo.ErrPinnedMessageLimitExceeded) {
+			return webCtx.JSONError(common.Text(webCtx, ctl.translater, "置顶消息数量已达上限"), http.StatusBadRequest)
+		}
+
+		log.F(log.M{"user_id": user.ID, "room_id": roomID, "message_id": messageID}).Errorf("置顶消息失败: %v", err)
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInternalError), http.StatusInternalServerError)
+	}
+
+	ctl.svc.Sync.Notify(ctx, user.ID, client.DeviceID, repo.SyncEntityMessage, int64(messageID))
+
+	return ctl.PinnedMessages(ctx, webCtx, user)
+}
@@ -0,0 +1,31 @@
+package controllers_test
+
+import (
+	"testing"
+
+	"github.com/mylxsw/aidea-server/pkg/ai/chat"
+	"github.com/mylxsw/aidea-server/pkg/repo"
+	"github.com/mylxsw/aidea-server/server/controllers"
+	"github.com/mylxsw/go-utils/assert"
+)
+
+func TestApplyRoomPresets(t *testing.T) {
+	presets := repo.RoomPresets{Temperature: 0.3, MaxTokens: 4096, ReasoningEffort: repo.ReasoningEffortLow, EnableSearch: true, SearchEngine: "bochaai"}
+
+	req := controllers.ApplyRoomPresets(&chat.Request{}, presets)
+	assert.Equal(t, 0.3, req.Temperature)
+	assert.Equal(t, 4096, req.MaxTokens)
+	assert.Equal(t, repo.ReasoningEffortLow, req.ReasoningEffort)
+	assert.Equal(t, "bochaai", req.SearchEngine)
+	assert.True(t, req.EnableSearch())
+
+	// 请求中指定的参数优先
+	req = controllers.ApplyRoomPresets(&chat.Request{Temperature: 1, MaxTokens: 100, ReasoningEffort: repo.ReasoningEffortHigh, Flags: []string{controllers.ChatFlagNoSearch}}, presets)
+	assert.Equal(t, 1.0, req.Temperature)
+	assert.Equal(t, 100, req.MaxTokens)
+	assert.Equal(t, repo.ReasoningEffortHigh, req.ReasoningEffort)
+	assert.False(t, req.EnableSearch())
+
+	req = controllers.ApplyRoomPresets(&chat.Request{Flags: []string{"search"}}, presets)
+	assert.Equal(t, 1, len(req.Flags))
+}
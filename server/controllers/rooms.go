@@ -20,6 +20,7 @@ import (
 	"github.com/mylxsw/aidea-server/server/auth"
 	"github.com/mylxsw/aidea-server/server/controllers/common"
 	"github.com/mylxsw/asteria/log"
+	"github.com/mylxsw/eloquent/query"
 	"github.com/mylxsw/glacier/infra"
 	"github.com/mylxsw/glacier/web"
 	"github.com/mylxsw/go-utils/array"
@@ -27,10 +28,12 @@ import (
 
 // RoomController 数字人
 type RoomController struct {
-	roomRepo   *repo.RoomRepo    `autowire:"@"`
-	translater youdao.Translater `autowire:"@"`
-	conf       *config.Config    `autowire:"@"`
-	svc        *service.Service  `autowire:"@"`
+	roomRepo    *repo.RoomRepo      `autowire:"@"`
+	groupRepo   *repo.ChatGroupRepo `autowire:"@"`
+	messageRepo *repo.MessageRepo   `autowire:"@"`
+	translater  youdao.Translater   `autowire:"@"`
+	conf        *config.Config      `autowire:"@"`
+	svc         *service.Service    `autowire:"@"`
 }
 
 func NewRoomController(resolver infra.Resolver) web.Controller {
@@ -45,10 +48,23 @@ func (ctl *RoomController) Register(router web.Router) {
 	router.Group("/rooms", func(router web.Router) {
 		router.Post("/", ctl.CreateRoom)
 		router.Get("/", ctl.Rooms)
+		router.Get("/tags", ctl.Tags)
+		router.Post("/batch", ctl.BatchRooms)
 		router.Get("/{room_id}", ctl.Room)
 		router.Delete("/{room_id}", ctl.DeleteRoom)
 		router.Put("/{room_id}", ctl.UpdateRoom)
 		router.Put("/{room_id}/active-time", ctl.UpdateRoomActiveTime)
+		router.Put("/{room_id}/tags", ctl.UpdateRoomTags)
+		router.Put("/{room_id}/memory", ctl.UpdateRoomMemory)
+		router.Get("/{room_id}/pinned-messages", ctl.PinnedMessages)
+		router.Put("/{room_id}/messages/{message_id}/pin", ctl.PinMessage)
+	})
+
+	router.Group("/room-folders", func(router web.Router) {
+		router.Get("/", ctl.Folders)
+		router.Post("/", ctl.CreateFolder)
+		router.Put("/{folder_id}", ctl.UpdateFolder)
+		router.Delete("/{folder_id}", ctl.DeleteFolder)
 	})
 
 	router.Group("/room-galleries", func(router web.Router) {
@@ -188,6 +204,7 @@ func (ctl *RoomController) CopyGalleryItem(ctx context.Context, webCtx web.Conte
 				AvatarId:       item.AvatarId,
 				AvatarUrl:      item.AvatarUrl,
 				LastActiveTime: time.Now(),
+				PresetsJson:    repo.EncodeRoomPresets(item.Presets),
 			},
 			false,
 		)
@@ -214,7 +231,7 @@ func (ctl *RoomController) CopyGalleryItem(ctx context.Context, webCtx web.Conte
 }
 
 // CreateRoom 创建数字人
-func (ctl *RoomController) CreateRoom(ctx context.Context, webCtx web.Context, user *auth.User) web.Response {
+func (ctl *RoomController) CreateRoom(ctx context.Context, webCtx web.Context, user *auth.User, client *auth.ClientInfo) web.Response {
 	req, err := ctl.parseRoomRequest(webCtx, false)
 	if err != nil {
 		return webCtx.JSONError(common.Text(webCtx, ctl.translater, err.Error()), http.StatusBadRequest)
@@ -250,6 +267,11 @@ func (ctl *RoomController) CreateRoom(ctx context.Context, webCtx web.Context, u
 		AvatarId:       req.AvatarID,
 		AvatarUrl:      req.AvatarURL,
 		InitMessage:    req.InitMessage,
+		AutoTitle:      ternary.If[int64](req.AutoTitle, 1, 0),
+	}
+
+	if req.Presets != nil {
+		room.PresetsJson = repo.EncodeRoomPresets(*req.Presets)
 	}
 
 	id, err := ctl.roomRepo.Create(ctx, user.ID, &room, true)
@@ -262,6 +284,8 @@ func (ctl *RoomController) CreateRoom(ctx context.Context, webCtx web.Context, u
 		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInternalError), http.StatusInternalServerError)
 	}
 
+	ctl.svc.Sync.Notify(ctx, user.ID, client.DeviceID, repo.SyncEntityRoom, id)
+
 	return webCtx.JSON(web.M{
 		"id": id,
 	})
@@ -274,7 +298,12 @@ func (ctl *RoomController) Rooms(ctx context.Context, webCtx web.Context, user *
 		roomTypes = append(roomTypes, repo.RoomTypeGroupChat)
 	}
 
-	rooms, err := ctl.roomRepo.Rooms(ctx, user.ID, roomTypes, RoomsQueryLimit)
+	filter, err := ParseRoomFilter(webCtx)
+	if err != nil {
+		return webCtx.JSONError(err.Error(), http.StatusBadRequest)
+	}
+
+	rooms, err := ctl.roomRepo.FilterRooms(ctx, user.ID, roomTypes, filter, RoomsQueryLimit)
 	if err != nil {
 		log.F(log.M{"user_id": user.ID}).Errorf("查询用户自定义角色列表失败: %v", err)
 		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInternalError), http.StatusInternalServerError)
@@ -313,11 +342,18 @@ func (ctl *RoomController) Room(ctx context.Context, webCtx web.Context, user *a
 		}
 	}
 
-	return webCtx.JSON(room)
+	pinned, err := ctl.messageRepo.PinnedMessages(ctx, user.ID, int64(roomID))
+	if err != nil {
+		log.F(log.M{"user_id": user.ID, "room_id": roomID}).Errorf("查询置顶消息失败: %v", err)
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInternalError), http.StatusInternalServerError)
+	}
+
+	presets := repo.RoomPresetsOf(room.PresetsJson)
+	return webCtx.JSON(RoomDetail{Rooms: *room, Presets: &presets, PinnedMessages: pinned})
 }
 
 // DeleteRoom 删除数字人
-func (ctl *RoomController) DeleteRoom(ctx context.Context, webCtx web.Context, user *auth.User) web.Response {
+func (ctl *RoomController) DeleteRoom(ctx context.Context, webCtx web.Context, user *auth.User, client *auth.ClientInfo) web.Response {
 	roomID, err := strconv.Atoi(webCtx.PathVar("room_id"))
 	if err != nil {
 		return webCtx.JSONError("invalid room id", http.StatusBadRequest)
@@ -338,9 +374,18 @@ func (ctl *RoomController) DeleteRoom(ctx context.Context, webCtx web.Context, u
 		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInternalError), http.StatusInternalServerError)
 	}
 
+	ctl.svc.Sync.Notify(ctx, user.ID, client.DeviceID, repo.SyncEntityRoom, int64(roomID))
+
 	return webCtx.JSON(web.M{})
 }
 
+// RoomDetail 数字人详情，包含生成参数预设以及置顶到上下文的消息
+type RoomDetail struct {
+	model.Rooms
+	Presets        *repo.RoomPresets    `json:"presets,omitempty"`
+	PinnedMessages []model.ChatMessages `json:"pinned_messages"`
+}
+
 type RoomRequest struct {
 	RoomID       int64  `json:"room_id,omitempty"`
 	AvatarID     int64  `json:"avatar_id,omitempty"`
@@ -352,6 +397,10 @@ type RoomRequest struct {
 	SystemPrompt string `json:"system_prompt,omitempty"`
 	InitMessage  string `json:"init_message,omitempty"`
 	MaxContext   int64  `json:"max_context,omitempty"`
+	// AutoTitle 是否在首轮对话完成后自动生成标题
+	AutoTitle bool `json:"auto_title,omitempty"`
+	// Presets 生成参数预设，请求中没有提交任何预设参数时为 nil，更新时保持原有的预设不变
+	Presets *repo.RoomPresets `json:"presets,omitempty"`
 }
 
 func (ctl *RoomController) parseRoomRequest(webCtx web.Context, isUpdate bool) (*RoomRequest, error) {
@@ -381,7 +430,12 @@ func (ctl *RoomController) parseRoomRequest(webCtx web.Context, isUpdate bool) (
 
 	name := webCtx.Input("name")
 	if name == "" {
-		return nil, errors.New("数字人名称不能为空")
+		if isUpdate {
+			return nil, errors.New("数字人名称不能为空")
+		}
+
+		// 创建时未指定名称，使用默认名称，首轮对话完成后自动生成标题
+		name, req.AutoTitle = repo.RoomDefaultName, true
 	}
 
 	if utf8.RuneCountInString(name) > 30 {
@@ -430,11 +484,44 @@ func (ctl *RoomController) parseRoomRequest(webCtx web.Context, isUpdate bool) (
 		req.AvatarID = avatarId
 	}
 
+	presets, err := ctl.parseRoomPresets(webCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Presets = presets
+
 	return &req, nil
 }
 
+// parseRoomPresets 解析数字人的生成参数预设，没有提交任何预设参数时返回 nil
+func (ctl *RoomController) parseRoomPresets(webCtx web.Context) (*repo.RoomPresets, error) {
+	keys := []string{"temperature", "max_tokens", "reasoning_effort", "enable_search", "search_engine"}
+	if len(array.Filter(keys, func(key string, _ int) bool { return webCtx.Input(key) != "" })) == 0 {
+		return nil, nil
+	}
+
+	presets := repo.RoomPresets{
+		Temperature:     webCtx.Float64Input("temperature", 0),
+		MaxTokens:       webCtx.IntInput("max_tokens", 0),
+		ReasoningEffort: webCtx.Input("reasoning_effort"),
+		EnableSearch:    webCtx.Input("enable_search") == "true",
+		SearchEngine:    webCtx.Input("search_engine"),
+	}
+
+	if err := presets.Validate(); err != nil {
+		return nil, err
+	}
+
+	if presets.SearchEngine != "" && !array.In(presets.SearchEngine, ctl.conf.AvailableSearchEngines) {
+		return nil, errors.New("不支持该搜索引擎")
+	}
+
+	return &presets, nil
+}
+
 // UpdateRoom 更新数字人信息
-func (ctl *RoomController) UpdateRoom(ctx context.Context, webCtx web.Context, user *auth.User) web.Response {
+func (ctl *RoomController) UpdateRoom(ctx context.Context, webCtx web.Context, user *auth.User, client *auth.ClientInfo) web.Response {
 	req, err := ctl.parseRoomRequest(webCtx, true)
 	if err != nil {
 		return webCtx.JSONError(common.Text(webCtx, ctl.translater, err.Error()), http.StatusBadRequest)
@@ -450,11 +537,17 @@ func (ctl *RoomController) UpdateRoom(ctx context.Context, webCtx web.Context, u
 		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInternalError), http.StatusInternalServerError)
 	}
 
+	// 客户端提交了修改前的版本号时，检查是否已经被其它设备修改过
+	if version := webCtx.Int64Input("version", -1); version >= 0 && version != room.Version {
+		return ctl.roomConflict(ctx, webCtx, user.ID, req.RoomID)
+	}
+
 	var changed bool
 
 	room.UserId = user.ID
 	if req.Name != room.Name {
-		room.Name = req.Name
+		// 用户修改了名称，不再自动生成标题
+		room.Name, room.AutoTitle = req.Name, 0
 		changed = true
 	}
 
@@ -489,6 +582,13 @@ func (ctl *RoomController) UpdateRoom(ctx context.Context, webCtx web.Context, u
 		changed = true
 	}
 
+	if req.Presets != nil {
+		if presetsJson := repo.EncodeRoomPresets(*req.Presets); presetsJson != room.PresetsJson {
+			room.PresetsJson = presetsJson
+			changed = true
+		}
+	}
+
 	if req.InitMessage != room.InitMessage {
 		room.InitMessage = req.InitMessage
 		changed = true
@@ -509,13 +609,38 @@ func (ctl *RoomController) UpdateRoom(ctx context.Context, webCtx web.Context, u
 	}
 
 	if err := ctl.roomRepo.Update(ctx, user.ID, req.RoomID, room); err != nil {
+		if errors.Is(err, repo.ErrRoomVersionConflict) {
+			return ctl.roomConflict(ctx, webCtx, user.ID, req.RoomID)
+		}
+
 		log.F(log.M{"user_id": user.ID, "room_id": req.RoomID}).Errorf("更新用户房间失败: %v", err)
 		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInternalError), http.StatusInternalServerError)
 	}
 
+	// 清理房间缓存，使新的系统提示语以及生成参数预设立即生效
+	if err := ctl.svc.Chat.ForgetRoom(ctx, user.ID, req.RoomID); err != nil {
+		log.F(log.M{"user_id": user.ID, "room_id": req.RoomID}).Errorf("清理房间缓存失败: %v", err)
+	}
+
+	ctl.svc.Sync.Notify(ctx, user.ID, client.DeviceID, repo.SyncEntityRoom, req.RoomID)
+
 	return webCtx.JSON(room)
 }
 
+// roomConflict 数字人已经被其它设备修改，返回最新的数字人信息，由客户端决定如何合并
+func (ctl *RoomController) roomConflict(ctx context.Context, webCtx web.Context, userID, roomID int64) web.Response {
+	latest, err := ctl.roomRepo.Room(ctx, userID, roomID)
+	if err != nil {
+		log.F(log.M{"user_id": userID, "room_id": roomID}).Errorf("查询用户房间失败: %v", err)
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInternalError), http.StatusInternalServerError)
+	}
+
+	return webCtx.JSONWithCode(web.M{
+		"error": common.Text(webCtx, ctl.translater, "数字人已在其它设备上修改，请刷新后重试"),
+		"room":  latest,
+	}, http.StatusConflict)
+}
+
 // UpdateRoomActiveTime 更新数字人活跃时间
 func (ctl *RoomController) UpdateRoomActiveTime(ctx context.Context, webCtx web.Context, user *auth.User) web.Response {
 	roomID, err := strconv.Atoi(webCtx.PathVar("room_id"))
@@ -530,3 +655,44 @@ func (ctl *RoomController) UpdateRoomActiveTime(ctx context.Context, webCtx web.
 
 	return webCtx.JSON(web.M{})
 }
+
+// UpdateRoomMemory 设置数字人是否使用长期记忆
+// @Summary 设置数字人是否使用长期记忆
+// @Tags Room
+// @Param room_id path int true "数字人 ID"
+// @Param disabled formData bool true "是否禁用长期记忆"
+// @Success 200 {object} model.Rooms
+// @Router /v1/rooms/{room_id}/memory [put]
+func (ctl *RoomController) UpdateRoomMemory(ctx context.Context, webCtx web.Context, user *auth.User, client *auth.ClientInfo) web.Response {
+	roomID, err := strconv.Atoi(webCtx.PathVar("room_id"))
+	if err != nil || roomID <= 1 {
+		return webCtx.JSONError("invalid room id", http.StatusBadRequest)
+	}
+
+	room, err := ctl.roomRepo.Room(ctx, user.ID, int64(roomID))
+	if err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return webCtx.JSONError(common.Text(webCtx, ctl.translater, "数字人不存在"), http.StatusNotFound)
+		}
+
+		log.F(log.M{"user_id": user.ID, "room_id": roomID}).Errorf("查询用户房间失败: %v", err)
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInternalError), http.StatusInternalServerError)
+	}
+
+	disabled := ternary.If(webCtx.Input("disabled") == "true", int64(1), int64(0))
+	if _, err := ctl.roomRepo.BatchUpdate(ctx, user.ID, []int64{int64(roomID)}, query.KV{
+		model.FieldRoomsMemoryDisabled: disabled,
+	}); err != nil {
+		log.F(log.M{"user_id": user.ID, "room_id": roomID}).Errorf("更新数字人长期记忆设置失败: %v", err)
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInternalError), http.StatusInternalServerError)
+	}
+
+	if err := ctl.svc.Chat.ForgetRoom(ctx, user.ID, int64(roomID)); err != nil {
+		log.F(log.M{"user_id": user.ID, "room_id": roomID}).Errorf("清理房间缓存失败: %v", err)
+	}
+
+	ctl.svc.Sync.Notify(ctx, user.ID, client.DeviceID, repo.SyncEntityRoom, int64(roomID))
+
+	room.MemoryDisabled = disabled
+	return webCtx.JSON(room)
+}
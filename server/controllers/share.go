@@ -0,0 +1,197 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mylxsw/aidea-server/pkg/rate"
+	"github.com/mylxsw/aidea-server/pkg/repo"
+	"github.com/mylxsw/aidea-server/pkg/youdao"
+	"github.com/mylxsw/aidea-server/server/auth"
+	"github.com/mylxsw/aidea-server/server/controllers/common"
+	"github.com/mylxsw/asteria/log"
+	"github.com/mylxsw/glacier/infra"
+	"github.com/mylxsw/glacier/web"
+	"github.com/mylxsw/go-utils/ternary"
+)
+
+// ShareMaxExpiresIn 分享链接最长有效期
+const ShareMaxExpiresIn = 365 * 24 * time.Hour
+
+// ShareController 分享链接管理
+type ShareController struct {
+	repo       *repo.Repository  `autowire:"@"`
+	translater youdao.Translater `autowire:"@"`
+	limiter    *rate.RateLimiter `autowire:"@"`
+}
+
+func NewShareController(resolver infra.Resolver) web.Controller {
+	ctl := ShareController{}
+	resolver.MustAutoWire(&ctl)
+	return &ctl
+}
+
+func (ctl *ShareController) Register(router web.Router) {
+	router.Group("/shares", func(router web.Router) {
+		router.Get("/", ctl.Shares)
+		router.Delete("/{code}", ctl.RevokeShare)
+	})
+
+	router.Group("/shared-creative", func(router web.Router) {
+		router.Get("/{code}", ctl.SharedCreativeHistory)
+		router.Post("/{code}", ctl.SharedCreativeHistory)
+	})
+}
+
+// ShareOptionsRequest 分享链接的访问控制选项
+type ShareOptionsRequest struct {
+	// ExpiresIn 有效期（秒），为 0 时永不过期
+	ExpiresIn int64 `json:"expires_in,omitempty"`
+	// Password 访问密码，为空时不需要密码
+	Password string `json:"password,omitempty"`
+}
+
+// Options 校验并转换为分享链接的访问控制选项
+func (req ShareOptionsRequest) Options() (repo.ShareOptions, error) {
+	opts := repo.ShareOptions{Password: req.Password}
+	if req.ExpiresIn < 0 || time.Duration(req.ExpiresIn)*time.Second > ShareMaxExpiresIn {
+		return opts, errors.New("分享有效期不能超过 365 天")
+	}
+
+	if req.ExpiresIn > 0 {
+		opts.ExpiredAt = time.Now().Add(time.Duration(req.ExpiresIn) * time.Second)
+	}
+
+	if utf8.RuneCountInString(req.Password) > 32 {
+		return opts, errors.New("访问密码不能超过 32 个字符")
+	}
+
+	return opts, nil
+}
+
+// ParseShareOptions 从请求参数中解析分享链接的访问控制选项
+func ParseShareOptions(webCtx web.Context) (repo.ShareOptions, error) {
+	return ShareOptionsRequest{
+		ExpiresIn: webCtx.Int64Input("expires_in", 0),
+		Password:  webCtx.Input("password"),
+	}.Options()
+}
+
+// SharedAccessPassword 读取访问分享链接的密码，并按照分享码以及客户端 IP 限制密码的尝试次数
+//
+// 密码只从请求体中读取，不接受 URL 参数，避免密码被记录到访问日志中
+func SharedAccessPassword(ctx context.Context, webCtx web.Context, limiter *rate.RateLimiter, code string) (string, error) {
+	if !webCtx.IsPost() {
+		return "", nil
+	}
+
+	password := ternary.IfLazy(
+		webCtx.IsJSON(),
+		func() string { return webCtx.Request().JSONGet("password") },
+		func() string { return webCtx.Request().Raw().PostFormValue("password") },
+	)
+	if password == "" {
+		return "", nil
+	}
+
+	clientIP := webCtx.Header("X-Real-IP")
+	if clientIP == "" {
+		clientIP, _, _ = net.SplitHostPort(webCtx.Request().Raw().RemoteAddr)
+	}
+
+	if err := limiter.Allow(ctx, fmt.Sprintf("share:%s:password:%s:limit", code, clientIP), rate.MaxRequestsInPeriod(10, 10*time.Minute)); err != nil {
+		return "", err
+	}
+
+	return password, nil
+}
+
+// SharedAccessError 访问分享链接失败时的响应，需要密码时返回 password_required，客户端据此提示用户输入密码
+func SharedAccessError(webCtx web.Context, translater youdao.Translater, err error) web.Response {
+	switch {
+	case errors.Is(err, repo.ErrNotFound):
+		return webCtx.JSONError(common.Text(webCtx, translater, "分享不存在"), http.StatusNotFound)
+	case errors.Is(err, repo.ErrShareRevoked):
+		return webCtx.JSONError(common.Text(webCtx, translater, "分享已被取消"), http.StatusGone)
+	case errors.Is(err, repo.ErrShareExpired):
+		return webCtx.JSONError(common.Text(webCtx, translater, "分享已过期"), http.StatusGone)
+	case errors.Is(err, rate.ErrRateLimitExceeded):
+		return webCtx.JSONError(common.Text(webCtx, translater, "密码尝试次数过多，请稍后再试"), http.StatusTooManyRequests)
+	case errors.Is(err, repo.ErrSharePasswordRequired), errors.Is(err, repo.ErrSharePasswordMismatch):
+		return webCtx.JSONWithCode(web.M{
+			"error":             common.Text(webCtx, translater, "访问密码错误"),
+			"password_required": true,
+		}, http.StatusForbidden)
+	}
+
+	log.Errorf("access shared content failed: %v", err)
+	return webCtx.JSONError(common.Text(webCtx, translater, common.ErrInternalError), http.StatusInternalServerError)
+}
+
+// Shares 获取用户创建的分享链接以及访问统计
+// @Summary 获取用户创建的分享链接以及访问统计
+// @Tags Share
+// @Produce json
+// @Param all query bool false "是否包含已过期以及已撤销的分享"
+// @Success 200 {object} common.DataArray[repo.ShareLink]
+// @Router /v1/shares [get]
+func (ctl *ShareController) Shares(ctx context.Context, webCtx web.Context, user *auth.User) web.Response {
+	shares, err := ctl.repo.Share.Shares(ctx, user.ID, webCtx.Input("all") != "true")
+	if err != nil {
+		log.F(log.M{"user_id": user.ID}).Errorf("查询用户分享失败: %v", err)
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInternalError), http.StatusInternalServerError)
+	}
+
+	return webCtx.JSON(common.NewDataArray(shares))
+}
+
+// RevokeShare 撤销分享链接
+// @Summary 撤销分享链接
+// @Tags Share
+// @Param code path string true "分享码"
+// @Success 200 {object} any
+// @Router /v1/shares/{code} [delete]
+func (ctl *ShareController) RevokeShare(ctx context.Context, webCtx web.Context, user *auth.User) web.Response {
+	code := webCtx.PathVar("code")
+	if err := ctl.repo.Share.Revoke(ctx, user.ID, code); err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return webCtx.JSONError(common.Text(webCtx, ctl.translater, "分享不存在"), http.StatusNotFound)
+		}
+
+		log.F(log.M{"user_id": user.ID, "code": code}).Errorf("撤销分享失败: %v", err)
+		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInternalError), http.StatusInternalServerError)
+	}
+
+	return webCtx.JSON(web.M{})
+}
+
+// SharedCreativeHistory 通过分享码查看分享的创作岛作品
+// @Summary 通过分享码查看分享的创作岛作品
+// @Tags Share
+// @Produce json
+// @Param code path string true "分享码"
+// @Param password formData string false "访问密码，仅支持 POST 请求"
+// @Success 200 {object} repo.CreativeHistoryItem
+// @Router /v1/shared-creative/{code} [get]
+// @Router /v1/shared-creative/{code} [post]
+func (ctl *ShareController) SharedCreativeHistory(ctx context.Context, webCtx web.Context) web.Response {
+	code := webCtx.PathVar("code")
+	password, err := SharedAccessPassword(ctx, webCtx, ctl.limiter, code)
+	if err != nil {
+		return SharedAccessError(webCtx, ctl.translater, err)
+	}
+
+	item, err := ctl.repo.Creative.SharedCreativeHistory(ctx, code, password)
+	if err != nil {
+		return SharedAccessError(webCtx, ctl.translater, err)
+	}
+
+	// 分享页面不展示作者的账号信息
+	item.UserID = 0
+	return webCtx.JSON(item)
+}
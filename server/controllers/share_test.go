@@ -0,0 +1,27 @@
+package controllers_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mylxsw/aidea-server/server/controllers"
+	"github.com/mylxsw/go-utils/assert"
+)
+
+func TestShareOptionsRequest(t *testing.T) {
+	opts, err := controllers.ShareOptionsRequest{}.Options()
+	assert.NoError(t, err)
+	assert.True(t, opts.Unrestricted())
+
+	opts, err = controllers.ShareOptionsRequest{ExpiresIn: 3600, Password: "secret"}.Options()
+	assert.NoError(t, err)
+	assert.False(t, opts.Unrestricted())
+	assert.Equal(t, "secret", opts.Password)
+	assert.True(t, opts.ExpiredAt.After(time.Now().Add(59*time.Minute)))
+
+	_, err = controllers.ShareOptionsRequest{ExpiresIn: -1}.Options()
+	assert.True(t, err != nil)
+
+	_, err = controllers.ShareOptionsRequest{ExpiresIn: 400 * 24 * 3600}.Options()
+	assert.True(t, err != nil)
+}
@@ -0,0 +1,110 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/mylxsw/aidea-server/config"
+	"github.com/mylxsw/aidea-server/pkg/ai/streamwriter"
+	"github.com/mylxsw/aidea-server/pkg/misc"
+	"github.com/mylxsw/aidea-server/pkg/repo"
+	"github.com/mylxsw/aidea-server/pkg/service"
+	"github.com/mylxsw/aidea-server/server/auth"
+	"github.com/mylxsw/asteria/log"
+	"github.com/mylxsw/glacier/infra"
+	"github.com/mylxsw/glacier/web"
+)
+
+// SyncController 多端聊天记录同步
+type SyncController struct {
+	conf *config.Config   `autowire:"@"`
+	repo *repo.Repository `autowire:"@"`
+	svc  *service.Service `autowire:"@"`
+}
+
+func NewSyncController(resolver infra.Resolver) web.Controller {
+	ctl := SyncController{}
+	resolver.MustAutoWire(&ctl)
+	return &ctl
+}
+
+func (ctl *SyncController) Register(router web.Router) {
+	router.Group("/sync", func(router web.Router) {
+		router.Get("/changes", ctl.Changes)
+		router.Get("/events", ctl.Events)
+	})
+}
+
+// Changes 增量同步，返回游标之后发生变更的数字人、消息以及删除记录
+// @Summary 增量同步，返回游标之后发生变更的数字人、消息以及删除记录
+// @Tags Sync
+// @Produce json
+// @Param cursor query string false "上次同步返回的游标，为空时从头开始同步"
+// @Param limit query int false "每种数据最多返回的数量"
+// @Success 200 {object} repo.SyncChanges
+// @Router /v1/sync/changes [get]
+func (ctl *SyncController) Changes(ctx context.Context, webCtx web.Context, user *auth.User) web.Response {
+	cursor, err := repo.DecodeSyncCursor(webCtx.Input("cursor"))
+	if err != nil {
+		return webCtx.JSONError(err.Error(), http.StatusBadRequest)
+	}
+
+	changes, err := ctl.repo.Sync.Changes(ctx, user.ID, cursor, webCtx.Int64Input("limit", 200))
+	if err != nil {
+		log.F(log.M{"user_id": user.ID}).Errorf("query sync changes failed: %s", err)
+		return webCtx.JSONError("internal server error", http.StatusInternalServerError)
+	}
+
+	return webCtx.JSON(changes)
+}
+
+// Events 订阅多端同步事件（SSE 或者 WebSocket），其它设备写入数据后推送通知
+func (ctl *SyncController) Events(ctx context.Context, webCtx web.Context, user *auth.User, client *auth.ClientInfo, w http.ResponseWriter) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	sw, err := streamwriter.NewPublisher(webCtx.Input("ws") == "true", ctl.conf.EnableCORS, webCtx.Request().Raw(), w)
+	if err != nil {
+		log.F(log.M{"user_id": user.ID}).Errorf("create stream writer failed: %s", err)
+		return
+	}
+	defer sw.Close()
+
+	sw.SetOnClosed(cancel)
+
+	events, err := ctl.svc.Sync.Subscribe(ctx, user.ID)
+	if err != nil {
+		log.F(log.M{"user_id": user.ID}).Errorf("subscribe sync events failed: %s", err)
+		misc.NoError(sw.WriteErrorStream(errors.New("internal server error"), http.StatusInternalServerError))
+		return
+	}
+
+	ticker := time.NewTicker(15 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case evt, ok := <-events:
+			if !ok {
+				return
+			}
+
+			// 当前设备产生的变更不需要通知自己
+			if client.DeviceID != "" && evt.Device == client.DeviceID {
+				continue
+			}
+
+			if err := sw.WriteStream(evt); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if err := sw.WriteStream(service.SyncEvent{Type: service.SyncEventPing}); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -7,8 +7,14 @@ import (
 	"github.com/mylxsw/aidea-server/pkg/repo"
 	"github.com/mylxsw/aidea-server/pkg/youdao"
 	"net/http"
+	"strings"
 	"time"
 
+	"github.com/mylxsw/aidea-server/pkg/ai/streamwriter"
+	"github.com/mylxsw/aidea-server/pkg/misc"
+	"github.com/mylxsw/aidea-server/pkg/service"
+	"github.com/mylxsw/go-utils/array"
+
 	"github.com/mylxsw/aidea-server/config"
 	"github.com/mylxsw/aidea-server/internal/queue"
 	"github.com/mylxsw/aidea-server/server/auth"
@@ -20,8 +26,9 @@ import (
 
 type TaskController struct {
 	conf       *config.Config
-	queueRepo  *repo.QueueRepo   `autowire:"@"`
-	translater youdao.Translater `autowire:"@"`
+	queueRepo  *repo.QueueRepo           `autowire:"@"`
+	translater youdao.Translater         `autowire:"@"`
+	taskEvents *service.TaskEventService `autowire:"@"`
 }
 
 func NewTaskController(resolver infra.Resolver, conf *config.Config) web.Controller {
@@ -33,6 +40,7 @@ func NewTaskController(resolver infra.Resolver, conf *config.Config) web.Control
 func (ctl *TaskController) Register(router web.Router) {
 	router.Group("/tasks", func(router web.Router) {
 		router.Get("/{task_id}/status", ctl.TaskStatus)
+		router.Get("/events", ctl.Events)
 	})
 }
 
@@ -88,7 +96,72 @@ func (ctl *TaskController) TaskStatus(ctx context.Context, webCtx web.Context, u
 		})
 	}
 
-	return webCtx.JSON(web.M{
-		"status": task.Status,
-	})
+	res := web.M{"status": task.Status}
+
+	// 执行中的任务，服务商返回进度时，返回进度百分比
+	if repo.QueueTaskStatus(task.Status) == repo.QueueTaskStatusRunning && task.Result != "" {
+		var progress queue.ProgressResult
+		if err := json.Unmarshal([]byte(task.Result), &progress); err == nil && progress.Progress > 0 {
+			res["progress"] = progress.Progress
+		}
+	}
+
+	return webCtx.JSON(res)
+}
+
+// Events 订阅当前用户的任务状态变更事件（SSE 或者 WebSocket），任务加入队列、开始执行、进度更新、成功或者失败时推送
+// @Summary 订阅当前用户的任务状态变更事件
+// @Tags Task
+// @Produce text/event-stream
+// @Param ws query bool false "是否使用 WebSocket"
+// @Param task_type query string false "只订阅指定类型的任务，多个类型使用英文逗号分隔"
+// @Success 200 {object} service.TaskEvent
+// @Router /v1/tasks/events [get]
+func (ctl *TaskController) Events(ctx context.Context, webCtx web.Context, user *auth.User, w http.ResponseWriter) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	sw, err := streamwriter.NewPublisher(webCtx.Input("ws") == "true", ctl.conf.EnableCORS, webCtx.Request().Raw(), w)
+	if err != nil {
+		log.F(log.M{"user_id": user.ID}).Errorf("create stream writer failed: %s", err)
+		return
+	}
+	defer sw.Close()
+
+	sw.SetOnClosed(cancel)
+
+	taskTypes := array.Filter(strings.Split(webCtx.Input("task_type"), ","), func(item string, _ int) bool { return item != "" })
+
+	events, err := ctl.taskEvents.Subscribe(ctx, user.ID)
+	if err != nil {
+		log.F(log.M{"user_id": user.ID}).Errorf("subscribe task events failed: %s", err)
+		misc.NoError(sw.WriteErrorStream(errors.New("internal server error"), http.StatusInternalServerError))
+		return
+	}
+
+	ticker := time.NewTicker(15 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case evt, ok := <-events:
+			if !ok {
+				return
+			}
+
+			if len(taskTypes) > 0 && !array.In(evt.TaskType, taskTypes) {
+				continue
+			}
+
+			if err := sw.WriteStream(evt); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if err := sw.WriteStream(service.TaskEvent{Type: service.TaskEventPing}); err != nil {
+				return
+			}
+		}
+	}
 }
@@ -2,10 +2,12 @@ package v2
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/mylxsw/aidea-server/pkg/ai/dashscope"
+	"github.com/mylxsw/aidea-server/pkg/ai/imagegen"
 	"github.com/mylxsw/aidea-server/pkg/ai/xfyun"
 	"github.com/mylxsw/aidea-server/pkg/misc"
 	"github.com/mylxsw/aidea-server/pkg/repo"
@@ -26,6 +28,7 @@ import (
 	"github.com/mylxsw/aidea-server/internal/coins"
 	"github.com/mylxsw/aidea-server/internal/queue"
 	"github.com/mylxsw/aidea-server/server/auth"
+	"github.com/mylxsw/aidea-server/server/controllers"
 	"github.com/mylxsw/aidea-server/server/controllers/common"
 	"github.com/mylxsw/asteria/log"
 	"github.com/mylxsw/glacier/infra"
@@ -41,14 +44,19 @@ const (
 // CreativeIslandController 创作岛
 type CreativeIslandController struct {
 	conf         *config.Config
-	quotaRepo    *repo.QuotaRepo          `autowire:"@"`
-	queue        *queue.Queue             `autowire:"@"`
-	trans        youdao.Translater        `autowire:"@"`
-	creativeRepo *repo.CreativeRepo       `autowire:"@"`
-	securitySrv  *service.SecurityService `autowire:"@"`
-	userSvc      *service.UserService     `autowire:"@"`
-	rds          *redis.Client            `autowire:"@"`
-	xfai         *xfyun.XFYunAI           `autowire:"@"`
+	quotaRepo    *repo.QuotaRepo           `autowire:"@"`
+	queue        *queue.Queue              `autowire:"@"`
+	trans        youdao.Translater         `autowire:"@"`
+	creativeRepo *repo.CreativeRepo        `autowire:"@"`
+	securitySrv  *service.SecurityService  `autowire:"@"`
+	userSvc      *service.UserService      `autowire:"@"`
+	rds          *redis.Client             `autowire:"@"`
+	xfai         *xfyun.XFYunAI            `autowire:"@"`
+	modelRepo    *repo.ModelRepo           `autowire:"@"`
+	images       *imagegen.Registry        `autowire:"@"`
+	up           *uploader.Uploader        `autowire:"@"`
+	queueRepo    *repo.QueueRepo           `autowire:"@"`
+	pendingTasks *queue.PendingTaskManager `autowire:"@"`
 }
 
 // NewCreativeIslandController create a new CreativeIslandController
@@ -74,6 +82,10 @@ func (ctl *CreativeIslandController) Register(router web.Router) {
 			router.Delete("/{hid}", ctl.DeleteHistoryItem)
 			router.Post("/{hid}/share", ctl.ShareHistoryItem)
 			router.Delete("/{hid}/share", ctl.CancelShareHistoryItem)
+			router.Post("/{hid}/share-link", ctl.ShareHistoryItemLink)
+			router.Post("/{hid}/rerun", ctl.RerunHistoryItem)
+			router.Post("/{hid}/variations", ctl.HistoryItemVariations)
+			router.Post("/{hid}/cancel", ctl.CancelHistoryItem)
 		})
 
 		router.Group("/completions", func(router web.Router) {
@@ -268,32 +280,9 @@ func (ctl *CreativeIslandController) loadAllModels(ctx context.Context) []repo.I
 		log.Errorf("get models failed: %v", err)
 	}
 
+	// 模型使用渠道配置，或者服务商已在配置文件中启用时可用
 	return array.Filter(models, func(m repo.ImageModel, _ int) bool {
-		if m.Vendor == "leapai" {
-			return ctl.conf.EnableLeapAI
-		}
-
-		if m.Vendor == "stabilityai" {
-			return ctl.conf.EnableStabilityAI
-		}
-
-		if m.Vendor == "fromston" {
-			return ctl.conf.EnableFromstonAI
-		}
-
-		if m.Vendor == "getimgai" {
-			return ctl.conf.EnableGetimgAI
-		}
-
-		if m.Vendor == "dashscope" {
-			return ctl.conf.EnableDashScopeAI
-		}
-
-		if m.Vendor == "dalle" {
-			return ctl.conf.EnableOpenAIDalle
-		}
-
-		return true
+		return m.ImageMeta.ChannelID > 0 || ctl.images.Enabled(m.Vendor)
 	})
 }
 
@@ -334,25 +323,19 @@ func (ctl *CreativeIslandController) Capacity(ctx context.Context, webCtx web.Co
 
 	log.WithFields(log.Fields{"id": id, "mode": mode}).Debugf("creative capacity request")
 
+	// 查询所有可用的模型，过滤掉当前没有启用的模型对应的风格
+	availableModels := array.ToMap(
+		array.Map(ctl.loadAllModels(ctx), func(item repo.ImageModel, _ int) string {
+			return item.ModelId
+		}),
+		func(val string, _ int) string {
+			return val
+		},
+	)
+
 	filters := array.Sort(
 		array.Filter(ctl.getAllImageStyles(ctx), func(item ImageStyle, index int) bool {
-			if !ctl.conf.EnableLeapAI && item.Vendor == "leapai" {
-				return false
-			}
-
-			if !ctl.conf.EnableStabilityAI && item.Vendor == "stabilityai" {
-				return false
-			}
-
-			if !ctl.conf.EnableFromstonAI && item.Vendor == "fromston" {
-				return false
-			}
-
-			if !ctl.conf.EnableGetimgAI && item.Vendor == "getimgai" {
-				return false
-			}
-
-			if !ctl.conf.EnableOpenAIDalle && item.Vendor == "dalle" {
+			if _, ok := availableModels[item.ModelID]; !ok {
 				return false
 			}
 
@@ -454,6 +437,34 @@ func (ctl *CreativeIslandController) ShareHistoryItem(ctx context.Context, webCt
 	return webCtx.JSON(web.M{})
 }
 
+// ShareHistoryItemLink 生成创作的分享链接，支持设置有效期以及访问密码，可以通过 /v1/shares 撤销
+func (ctl *CreativeIslandController) ShareHistoryItemLink(ctx context.Context, webCtx web.Context, user *auth.User) web.Response {
+	hid, _ := strconv.Atoi(webCtx.PathVar("hid"))
+	if hid <= 0 {
+		return webCtx.JSONError(common.Text(webCtx, ctl.trans, common.ErrInvalidRequest), http.StatusBadRequest)
+	}
+
+	opts, err := controllers.ParseShareOptions(webCtx)
+	if err != nil {
+		return webCtx.JSONError(common.Text(webCtx, ctl.trans, err.Error()), http.StatusBadRequest)
+	}
+
+	code, err := ctl.creativeRepo.ShareCreativeHistory(ctx, user.ID, int64(hid), opts)
+	if err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return webCtx.JSONError(common.Text(webCtx, ctl.trans, common.ErrNotFound), http.StatusNotFound)
+		}
+
+		log.WithFields(log.Fields{
+			"uid":    user.ID,
+			"his_id": hid,
+		}).Errorf("create creative item share link failed: %v", err)
+		return webCtx.JSONError(common.Text(webCtx, ctl.trans, common.ErrInternalError), http.StatusInternalServerError)
+	}
+
+	return webCtx.JSON(controllers.MessageShareResponse{Code: code})
+}
+
 // CancelShareHistoryItem 取消分享创作到发现页
 func (ctl *CreativeIslandController) CancelShareHistoryItem(ctx context.Context, webCtx web.Context, user *auth.User) web.Response {
 	hid, _ := strconv.Atoi(webCtx.PathVar("hid"))
@@ -540,8 +551,8 @@ func (ctl *CreativeIslandController) Histories(ctx context.Context, webCtx web.C
 			item.IslandTitle = "艺术字"
 		}
 
-		// 客户端目前不支持封禁状态展示，这里转换为失败
-		if item.Status == int64(repo.CreativeStatusForbid) {
+		// 客户端目前不支持封禁、取消状态展示，这里转换为失败
+		if item.Status == int64(repo.CreativeStatusForbid) || item.Status == int64(repo.CreativeStatusCancelled) {
 			item.Status = int64(repo.CreativeStatusFailed)
 		}
 
@@ -592,8 +603,8 @@ func (ctl *CreativeIslandController) HistoryItem(ctx context.Context, webCtx web
 		return webCtx.JSONError(common.Text(webCtx, ctl.trans, common.ErrInternalError), http.StatusInternalServerError)
 	}
 
-	// 客户端目前不支持封禁状态展示，这里转换为失败
-	if item.Status == int64(repo.CreativeStatusForbid) {
+	// 客户端目前不支持封禁、取消状态展示，这里转换为失败
+	if item.Status == int64(repo.CreativeStatusForbid) || item.Status == int64(repo.CreativeStatusCancelled) {
 		item.Status = int64(repo.CreativeStatusFailed)
 	}
 
@@ -623,6 +634,239 @@ func (ctl *CreativeIslandController) DeleteHistoryItem(ctx context.Context, webC
 	return webCtx.JSON(web.M{})
 }
 
+// maxImageSeed 图片生成时种子的最大值
+const maxImageSeed = 2147483647
+
+// RerunHistoryItem 使用历史记录中实际使用的提示语、模型、种子等参数重新生成
+func (ctl *CreativeIslandController) RerunHistoryItem(ctx context.Context, webCtx web.Context, user *auth.User) web.Response {
+	item, arg, errResp := ctl.findImageHistoryItem(ctx, webCtx, user)
+	if errResp != nil {
+		return errResp
+	}
+
+	req, _, errResp := ctl.buildHistoryCompletionRequest(ctx, webCtx, user, item, arg, arg.ImageCount)
+	if errResp != nil {
+		return errResp
+	}
+
+	return ctl.submitImageCompletion(ctx, webCtx, user, req, func(newArg *repo.CreativeRecordArguments) {
+		newArg.RerunFrom = item.Id
+	})
+}
+
+// HistoryItemVariations 基于历史记录生成 N 张变体图片
+// 服务商支持变体接口时，使用 index 指定的生成结果作为参考图片调用变体接口，否则使用相同的参数以及相近的种子重新生成
+func (ctl *CreativeIslandController) HistoryItemVariations(ctx context.Context, webCtx web.Context, user *auth.User) web.Response {
+	item, arg, errResp := ctl.findImageHistoryItem(ctx, webCtx, user)
+	if errResp != nil {
+		return errResp
+	}
+
+	if item.Status != int64(repo.CreativeStatusSuccess) {
+		return webCtx.JSONError("只有生成成功的作品才能生成变体", http.StatusBadRequest)
+	}
+
+	imageCount := webCtx.Int64Input("image_count", ternary.If(arg.ImageCount > 0, arg.ImageCount, 1))
+	if imageCount < 1 || imageCount > 4 {
+		return webCtx.JSONError("invalid image count", http.StatusBadRequest)
+	}
+
+	var images []string
+	if err := json.Unmarshal([]byte(item.Answer), &images); err != nil {
+		log.F(log.M{"his_id": item.Id}).Errorf("unmarshal creative answer failed: %v", err)
+		return webCtx.JSONError(common.Text(webCtx, ctl.trans, common.ErrInternalError), http.StatusInternalServerError)
+	}
+
+	index := webCtx.IntInput("index", 0)
+	if index < 0 || index >= len(images) {
+		return webCtx.JSONError("invalid index", http.StatusBadRequest)
+	}
+
+	req, caps, errResp := ctl.buildHistoryCompletionRequest(ctx, webCtx, user, item, arg, imageCount)
+	if errResp != nil {
+		return errResp
+	}
+
+	// 变体接口通常只支持正方形图片，局部重绘、扩图的结果不使用变体接口
+	if caps.Variation && arg.EditMode == "" && arg.Width == arg.Height {
+		req.Image = images[index]
+		req.Variation = true
+	} else {
+		// 优先使用服务商返回的该图片实际使用的种子
+		seed := arg.Seed
+		if index < len(arg.Seeds) {
+			seed = arg.Seeds[index]
+		}
+
+		req.Seed = variationSeed(seed, rand.Int63n(1000))
+	}
+
+	return ctl.submitImageCompletion(ctx, webCtx, user, req, func(newArg *repo.CreativeRecordArguments) {
+		newArg.VariationOf = item.Id
+	})
+}
+
+// variationSeed 生成变体时使用的相近种子，offset 为随机偏移量，避免与原种子相同
+func variationSeed(seed, offset int64) int64 {
+	return (seed + 1 + offset) % (maxImageSeed + 1)
+}
+
+// findImageHistoryItem 查询当前用户的图片生成历史记录以及生成参数，只支持创作岛统一图片生成的记录
+func (ctl *CreativeIslandController) findImageHistoryItem(ctx context.Context, webCtx web.Context, user *auth.User) (*repo.CreativeHistoryItem, *repo.CreativeRecordArguments, web.Response) {
+	hid, _ := strconv.Atoi(webCtx.PathVar("hid"))
+	if hid <= 0 {
+		return nil, nil, webCtx.JSONError(common.Text(webCtx, ctl.trans, common.ErrInvalidRequest), http.StatusBadRequest)
+	}
+
+	item, err := ctl.creativeRepo.FindHistoryRecord(ctx, user.ID, int64(hid))
+	if err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return nil, nil, webCtx.JSONError(common.Text(webCtx, ctl.trans, common.ErrNotFound), http.StatusNotFound)
+		}
+
+		log.Errorf("query creative item failed: %v", err)
+		return nil, nil, webCtx.JSONError(common.Text(webCtx, ctl.trans, common.ErrInternalError), http.StatusInternalServerError)
+	}
+
+	if item.IslandId != AllInOneIslandID || item.IslandType != int64(repo.IslandTypeImage) {
+		return nil, nil, webCtx.JSONError("当前作品不支持重新生成", http.StatusBadRequest)
+	}
+
+	if item.Status == int64(repo.CreativeStatusForbid) {
+		return nil, nil, webCtx.JSONError(common.Text(webCtx, ctl.trans, common.ErrNotFound), http.StatusNotFound)
+	}
+
+	var arg repo.CreativeRecordArguments
+	if item.Arguments != "" {
+		if err := json.Unmarshal([]byte(item.Arguments), &arg); err != nil {
+			log.F(log.M{"his_id": item.Id}).Errorf("unmarshal creative arguments failed: %v", err)
+			return nil, nil, webCtx.JSONError(common.Text(webCtx, ctl.trans, common.ErrInternalError), http.StatusInternalServerError)
+		}
+	}
+
+	return item, &arg, nil
+}
+
+// buildHistoryCompletionRequest 使用历史记录中的参数构建图片生成请求，提示语使用实际生成时的提示语，不再进行 AI 改写以及翻译
+func (ctl *CreativeIslandController) buildHistoryCompletionRequest(
+	ctx context.Context,
+	webCtx web.Context,
+	user *auth.User,
+	item *repo.CreativeHistoryItem,
+	arg *repo.CreativeRecordArguments,
+	imageCount int64,
+) (*queue.ImageCompletionPayload, imagegen.Capabilities, web.Response) {
+	vendorModel := ctl.getVendorModelByRealModel(ctx, arg.Vendor, item.IslandModel)
+	if vendorModel == nil {
+		return nil, imagegen.Capabilities{}, webCtx.JSONError("原作品使用的模型已下线，无法重新生成", http.StatusBadRequest)
+	}
+
+	gen, err := ctl.images.ResolveChannel(ctx, ctl.modelRepo, vendorModel.Vendor, vendorModel.ChannelID)
+	if err != nil {
+		log.F(log.M{"model": vendorModel.ID, "vendor": vendorModel.Vendor}).Errorf("resolve image generator failed: %v", err)
+		return nil, imagegen.Capabilities{}, webCtx.JSONError("原作品使用的模型已下线，无法重新生成", http.StatusBadRequest)
+	}
+
+	caps := gen.Capabilities()
+	if caps.MaxImageCount > 0 && imageCount > caps.MaxImageCount {
+		imageCount = caps.MaxImageCount
+	}
+
+	imageCount = ternary.If(imageCount > 0, imageCount, 1)
+
+	// 历史记录中没有实际使用的提示语时，说明提示语没有经过处理，与原始提示语一致
+	realPrompt := ternary.If(arg.RealPrompt != "", arg.RealPrompt, item.Prompt)
+	realNegativePrompt := ternary.If(arg.RealNegativePrompt != "", arg.RealNegativePrompt, arg.NegativePrompt)
+
+	return &queue.ImageCompletionPayload{
+		Prompt:             item.Prompt,
+		NegativePrompt:     arg.NegativePrompt,
+		PromptTags:         arg.PromptTags,
+		ImageCount:         imageCount,
+		ImageRatio:         arg.ImageRatio,
+		Width:              arg.Width,
+		Height:             arg.Height,
+		Steps:              arg.Steps,
+		Image:              arg.Image,
+		Mode:               arg.Mode,
+		UpscaleBy:          arg.UpscaleBy,
+		StylePreset:        arg.StylePreset,
+		Seed:               arg.Seed,
+		ImageStrength:      ternary.If(arg.ImageStrength > 0, arg.ImageStrength, 0.65),
+		FilterID:           arg.FilterID,
+		FilterName:         arg.FilterName,
+		EditMode:           arg.EditMode,
+		Mask:               arg.Mask,
+		Outpaint:           arg.Outpaint,
+		RealPrompt:         realPrompt,
+		RealNegativePrompt: realNegativePrompt,
+
+		UID:       user.ID,
+		Quota:     int64(coins.GetUnifiedImageGenCoins(vendorModel.Model)) * imageCount,
+		CreatedAt: time.Now(),
+
+		Vendor:    vendorModel.Vendor,
+		Model:     vendorModel.Model,
+		ModelName: vendorModel.Name,
+	}, caps, nil
+}
+
+// CancelHistoryItem 取消等待中或者处理中的创作岛任务（图片、视频生成等）
+// 智慧果在任务成功后才会扣除，取消任务时只需要释放提交任务时冻结的智慧果
+func (ctl *CreativeIslandController) CancelHistoryItem(ctx context.Context, webCtx web.Context, user *auth.User) web.Response {
+	hid, _ := strconv.Atoi(webCtx.PathVar("hid"))
+	if hid <= 0 {
+		return webCtx.JSONError(common.Text(webCtx, ctl.trans, common.ErrInvalidRequest), http.StatusBadRequest)
+	}
+
+	item, err := ctl.creativeRepo.FindHistoryRecord(ctx, user.ID, int64(hid))
+	if err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return webCtx.JSONError(common.Text(webCtx, ctl.trans, common.ErrNotFound), http.StatusNotFound)
+		}
+
+		log.Errorf("query creative item failed: %v", err)
+		return webCtx.JSONError(common.Text(webCtx, ctl.trans, common.ErrInternalError), http.StatusInternalServerError)
+	}
+
+	if item.TaskID == "" {
+		return webCtx.JSONError("当前作品不支持取消", http.StatusBadRequest)
+	}
+
+	// 先更新创作岛历史记录状态，与任务完成时的状态更新互斥，更新成功后任务不会再扣除智慧果
+	cancelled, err := ctl.creativeRepo.CancelRecord(ctx, user.ID, item.Id)
+	if err != nil {
+		log.F(log.M{"uid": user.ID, "his_id": hid}).Errorf("cancel creative item failed: %v", err)
+		return webCtx.JSONError(common.Text(webCtx, ctl.trans, common.ErrInternalError), http.StatusInternalServerError)
+	}
+
+	if !cancelled {
+		return webCtx.JSONError("任务已经结束，无法取消", http.StatusBadRequest)
+	}
+
+	logger := log.F(log.M{"uid": user.ID, "his_id": hid, "task_id": item.TaskID})
+	logger.Infof("cancel creative item")
+
+	// 从队列中删除任务，或者取消正在执行的任务
+	if err := ctl.queue.Cancel(ctx, item.TaskID); err != nil {
+		logger.Errorf("cancel queue task failed: %v", err)
+	}
+
+	// 异步任务停止查询状态，并取消服务商侧的任务
+	if err := ctl.pendingTasks.Cancel(ctx, ctl.queueRepo, item.TaskID); err != nil && !errors.Is(err, repo.ErrNotFound) {
+		logger.Errorf("cancel pending task failed: %v", err)
+	}
+
+	released, err := queue.ReleaseTaskFrozenQuota(ctx, ctl.rds, ctl.userSvc, user.ID, item.TaskID)
+	if err != nil {
+		logger.Errorf("release frozen quota failed: %v", err)
+	}
+
+	return webCtx.JSON(web.M{
+		"released_quota": released, // 释放的冻结智慧果数量
+	})
+}
+
 // CompletionsEvaluate 创作岛项目文本生成 价格评估
 func (ctl *CreativeIslandController) CompletionsEvaluate(ctx context.Context, webCtx web.Context, user *auth.User, client *auth.ClientInfo) web.Response {
 	ctx, cancel := context.WithTimeout(ctx, 120*time.Second)
@@ -726,6 +970,40 @@ func (ctl *CreativeIslandController) resolveImageCompletionRequest(ctx context.C
 		return nil, webCtx.JSONError("没有找到匹配的模型", http.StatusBadRequest)
 	}
 
+	gen, err := ctl.images.ResolveChannel(ctx, ctl.modelRepo, vendorModel.Vendor, vendorModel.ChannelID)
+	if err != nil {
+		log.F(log.M{"model": vendorModel.ID, "vendor": vendorModel.Vendor}).Errorf("resolve image generator failed: %v", err)
+		return nil, webCtx.JSONError("没有找到匹配的模型", http.StatusBadRequest)
+	}
+
+	vendorModel.Capabilities = gen.Capabilities()
+
+	// 服务商单次生成的图片数量有限制时，自动调整为最大数量
+	if vendorModel.Capabilities.MaxImageCount > 0 && imageCount > vendorModel.Capabilities.MaxImageCount {
+		imageCount = vendorModel.Capabilities.MaxImageCount
+	}
+
+	// 局部重绘、扩图模式
+	editMode := webCtx.Input("edit_mode")
+	if editMode != "" {
+		if !array.In(editMode, []string{imagegen.EditModeInpaint, imagegen.EditModeOutpaint}) {
+			return nil, webCtx.JSONError("invalid edit_mode", http.StatusBadRequest)
+		}
+
+		if !vendorModel.Capabilities.Inpaint {
+			return nil, webCtx.JSONError("当前模型不支持局部重绘和扩图", http.StatusBadRequest)
+		}
+
+		if image == "" {
+			return nil, webCtx.JSONError("局部重绘和扩图需要指定图片", http.StatusBadRequest)
+		}
+	}
+
+	// 部分服务商（如 stabilityai 和 fromston）图生图时要求参考图片为正方形，局部重绘时蒙版与图片尺寸需要一致，不做处理
+	if image != "" && editMode == "" && vendorModel.Capabilities.SquareImageOnly {
+		image = uploader.BuildImageURLWithFilter(image, "fix_square_1024", ctl.conf.StorageDomain)
+	}
+
 	imageRatio := webCtx.InputWithDefault("image_ratio", "1:1")
 	if !array.In(imageRatio, []string{"1:1", "4:3", "3:4", "3:2", "2:3", "16:9"}) {
 		return nil, webCtx.JSONError("invalid image ratio", http.StatusBadRequest)
@@ -768,6 +1046,19 @@ func (ctl *CreativeIslandController) resolveImageCompletionRequest(ctx context.C
 		return nil, webCtx.JSONError("invalid seed", http.StatusBadRequest)
 	}
 
+	var mask string
+	var outpaint *repo.ImageExtent
+	if editMode != "" {
+		edit, errResp := ctl.resolveImageEdit(ctx, webCtx, user, editMode, image)
+		if errResp != nil {
+			return nil, errResp
+		}
+
+		// 局部重绘和扩图时，生成的图片尺寸由原图（扩展后的画布）决定
+		mask, outpaint = edit.Mask, edit.Outpaint
+		width, height = int(edit.Width), int(edit.Height)
+	}
+
 	return &queue.ImageCompletionPayload{
 		Prompt:         prompt,
 		NegativePrompt: negativePrompt,
@@ -787,6 +1078,9 @@ func (ctl *CreativeIslandController) resolveImageCompletionRequest(ctx context.C
 		FilterID:       filterID,
 		FilterName:     filterName,
 		GalleryCopyID:  webCtx.Int64Input("gallery_copy_id", 0),
+		EditMode:       editMode,
+		Mask:           mask,
+		Outpaint:       outpaint,
 
 		UID:       user.ID,
 		Quota:     int64(coins.GetUnifiedImageGenCoins(vendorModel.Model)) * imageCount,
@@ -811,6 +1105,7 @@ func (ctl *CreativeIslandController) getAllModels(ctx context.Context) []VendorM
 			ShowImageStrength: m.ImageMeta.ShowImageStrength,
 			IntroURL:          m.ImageMeta.IntroURL,
 			RatioDimensions:   m.ImageMeta.RatioDimensions,
+			ChannelID:         m.ImageMeta.ChannelID,
 		}
 	})
 }
@@ -826,6 +1121,17 @@ func (ctl *CreativeIslandController) getVendorModel(ctx context.Context, modelID
 	return nil
 }
 
+// getVendorModelByRealModel 根据服务商以及服务商侧的模型名称查找模型，vendor 为空时（早期的历史记录）只匹配模型名称
+func (ctl *CreativeIslandController) getVendorModelByRealModel(ctx context.Context, vendor, realModel string) *VendorModel {
+	for _, m := range ctl.getAllModels(ctx) {
+		if m.Model == realModel && (vendor == "" || m.Vendor == vendor) {
+			return &m
+		}
+	}
+
+	return nil
+}
+
 type ArtisticStyle struct {
 	ID           string `json:"id"`
 	Name         string `json:"name"`
@@ -915,25 +1221,15 @@ type VendorModel struct {
 	ShowImageStrength bool                      `json:"show_image_strength,omitempty"`
 	IntroURL          string                    `json:"intro_url,omitempty"`
 	RatioDimensions   map[string]repo.Dimension `json:"-"`
+	ChannelID         int64                     `json:"-"`
+	// Capabilities 模型对应的图片生成服务支持的能力
+	Capabilities imagegen.Capabilities `json:"-"`
 }
 
+// defaultDimension 模型没有配置尺寸时，使用图片生成服务推荐的尺寸
 func (vm VendorModel) defaultDimension(ratio string) repo.Dimension {
-	switch ratio {
-	case "1:1":
-		return repo.Dimension{Width: 512, Height: 512}
-	case "4:3":
-		return repo.Dimension{Width: 768, Height: 576}
-	case "3:4":
-		return repo.Dimension{Width: 576, Height: 768}
-	case "3:2":
-		return repo.Dimension{Width: 768, Height: 512}
-	case "2:3":
-		return repo.Dimension{Width: 512, Height: 768}
-	case "16:9":
-		return repo.Dimension{Width: 1024, Height: 576}
-	}
-
-	return repo.Dimension{Width: 512, Height: 512}
+	dim := vm.Capabilities.Dimension(ratio)
+	return repo.Dimension{Width: dim.Width, Height: dim.Height}
 }
 
 func (vm VendorModel) GetDimension(ratio string) repo.Dimension {
@@ -1287,16 +1583,23 @@ func (ctl *CreativeIslandController) Completions(ctx context.Context, webCtx web
 		return errResp
 	}
 
+	return ctl.submitImageCompletion(ctx, webCtx, user, req, nil)
+}
+
+// submitImageCompletion 检查用户配额以及内容安全后，将图片生成任务加入队列并保存历史记录，
+// decorate 用于在保存历史记录前补充额外的参数
+func (ctl *CreativeIslandController) submitImageCompletion(
+	ctx context.Context,
+	webCtx web.Context,
+	user *auth.User,
+	req *queue.ImageCompletionPayload,
+	decorate func(arg *repo.CreativeRecordArguments),
+) web.Response {
 	// 图片地址检查
 	if req.Image != "" && !str.HasPrefixes(req.Image, []string{"https://ssl.aicode.cc/", ctl.conf.StorageDomain}) {
 		return webCtx.JSONError("invalid image", http.StatusBadRequest)
 	}
 
-	// stabilityai 和 fromston 生成的图片为正方形
-	if req.Image != "" && array.In(req.Vendor, []string{"fromston", "stabilityai"}) {
-		req.Image = uploader.BuildImageURLWithFilter(req.Image, "fix_square_1024", ctl.conf.StorageDomain)
-	}
-
 	// 检查用户是否有足够的智慧果
 	quota, err := ctl.userSvc.UserQuota(ctx, user.ID)
 	if err != nil {
@@ -1339,6 +1642,10 @@ func (ctl *CreativeIslandController) Completions(ctx context.Context, webCtx web
 
 	// 保存历史记录
 	creativeItem, arg := ctl.buildHistorySaveRecord(req, taskID)
+	if decorate != nil {
+		decorate(&arg)
+	}
+
 	if _, err := ctl.creativeRepo.CreateRecordWithArguments(ctx, user.ID, &creativeItem, &arg); err != nil {
 		log.Errorf("create creative item failed: %v", err)
 		return webCtx.JSONError(common.Text(webCtx, ctl.trans, common.ErrInternalError), http.StatusInternalServerError)
@@ -1379,7 +1686,121 @@ func (*CreativeIslandController) buildHistorySaveRecord(req *queue.ImageCompleti
 		FilterName:     req.FilterName,
 		GalleryCopyID:  req.GalleryCopyID,
 		Seed:           req.Seed,
+		EditMode:       req.EditMode,
+		Mask:           req.Mask,
+		Outpaint:       req.Outpaint,
+		Vendor:         req.Vendor,
+		ImageStrength:  req.ImageStrength,
+		// 重新生成时，实际使用的提示语与原记录一致
+		RealPrompt:         req.RealPrompt,
+		RealNegativePrompt: req.RealNegativePrompt,
+	}
+}
+
+// imageEdit 局部重绘、扩图请求参数
+type imageEdit struct {
+	Mask     string
+	Outpaint *repo.ImageExtent
+	Width    int64
+	Height   int64
+}
+
+// maxImageEditSize 局部重绘、扩图时图片（扩展后的画布）宽高的最大值
+const maxImageEditSize = 2048
+
+// resolveImageEdit 解析局部重绘、扩图请求参数
+// 局部重绘需要提供与原图尺寸一致的蒙版（mask），可以是已上传的图片地址，也可以是 Base64 编码的图片（客户端绘制），白色区域为需要重绘的区域
+// 扩图需要提供四个方向扩展的像素值（outpaint_left、outpaint_top、outpaint_right、outpaint_bottom）
+func (ctl *CreativeIslandController) resolveImageEdit(ctx context.Context, webCtx web.Context, user *auth.User, editMode, image string) (*imageEdit, web.Response) {
+	if !str.HasPrefixes(image, []string{"https://ssl.aicode.cc/", ctl.conf.StorageDomain}) {
+		return nil, webCtx.JSONError("invalid image", http.StatusBadRequest)
+	}
+
+	info, err := uploader.QueryImageInfo(image)
+	if err != nil {
+		log.F(log.M{"image": image}).Errorf("query image info failed: %v", err)
+		return nil, webCtx.JSONError("无法读取图片信息，请重新上传", http.StatusBadRequest)
+	}
+
+	if info.Width > maxImageEditSize || info.Height > maxImageEditSize {
+		return nil, webCtx.JSONError(fmt.Sprintf("图片宽高不能超过 %d", maxImageEditSize), http.StatusBadRequest)
 	}
+
+	if editMode == imagegen.EditModeOutpaint {
+		ext := repo.ImageExtent{
+			Left:   webCtx.Int64Input("outpaint_left", 0),
+			Top:    webCtx.Int64Input("outpaint_top", 0),
+			Right:  webCtx.Int64Input("outpaint_right", 0),
+			Bottom: webCtx.Int64Input("outpaint_bottom", 0),
+		}
+
+		for _, v := range []int64{ext.Left, ext.Top, ext.Right, ext.Bottom} {
+			if v < 0 || v > 1024 {
+				return nil, webCtx.JSONError("扩图的扩展范围必须在 0-1024 之间", http.StatusBadRequest)
+			}
+		}
+
+		if ext.Left+ext.Top+ext.Right+ext.Bottom == 0 {
+			return nil, webCtx.JSONError("请指定扩图的扩展范围", http.StatusBadRequest)
+		}
+
+		width, height := info.Width+ext.Left+ext.Right, info.Height+ext.Top+ext.Bottom
+		if width > maxImageEditSize || height > maxImageEditSize {
+			return nil, webCtx.JSONError(fmt.Sprintf("扩图后的图片宽高不能超过 %d", maxImageEditSize), http.StatusBadRequest)
+		}
+
+		return &imageEdit{Outpaint: &ext, Width: width, Height: height}, nil
+	}
+
+	mask := strings.TrimSpace(webCtx.Input("mask"))
+	if mask == "" {
+		return nil, webCtx.JSONError("局部重绘需要指定蒙版", http.StatusBadRequest)
+	}
+
+	var maskWidth, maskHeight int64
+	if str.HasPrefixes(mask, []string{"http://", "https://"}) {
+		if !str.HasPrefixes(mask, []string{"https://ssl.aicode.cc/", ctl.conf.StorageDomain}) {
+			return nil, webCtx.JSONError("invalid mask", http.StatusBadRequest)
+		}
+
+		maskInfo, err := uploader.QueryImageInfo(mask)
+		if err != nil {
+			log.F(log.M{"mask": mask}).Errorf("query mask info failed: %v", err)
+			return nil, webCtx.JSONError("无法读取蒙版信息，请重新上传", http.StatusBadRequest)
+		}
+
+		maskWidth, maskHeight = maskInfo.Width, maskInfo.Height
+	} else {
+		// 客户端绘制的蒙版，Base64 编码，可以包含 data:image/png;base64, 前缀
+		if idx := strings.Index(mask, ";base64,"); idx > 0 {
+			mask = mask[idx+len(";base64,"):]
+		}
+
+		data, err := base64.StdEncoding.DecodeString(mask)
+		if err != nil || len(data) > 5*1024*1024 {
+			return nil, webCtx.JSONError("invalid mask", http.StatusBadRequest)
+		}
+
+		w, h, err := imagegen.ImageSize(data)
+		if err != nil {
+			return nil, webCtx.JSONError("invalid mask", http.StatusBadRequest)
+		}
+
+		maskWidth, maskHeight = int64(w), int64(h)
+		if maskWidth == info.Width && maskHeight == info.Height {
+			mask, err = ctl.up.UploadStream(ctx, int(user.ID), uploader.DefaultUploadExpireAfterDays, data, "png")
+			if err != nil {
+				log.F(log.M{"user_id": user.ID}).Errorf("upload mask failed: %v", err)
+				return nil, webCtx.JSONError(common.Text(webCtx, ctl.trans, common.ErrInternalError), http.StatusInternalServerError)
+			}
+		}
+	}
+
+	if maskWidth != info.Width || maskHeight != info.Height {
+		return nil, webCtx.JSONError(fmt.Sprintf("蒙版尺寸（%dx%d）与图片尺寸（%dx%d）不一致", maskWidth, maskHeight, info.Width, info.Height), http.StatusBadRequest)
+	}
+
+	return &imageEdit{Mask: mask, Width: info.Width, Height: info.Height}, nil
 }
 
 // ImageToVideo 图片生成视频
@@ -10,6 +10,7 @@ import (
 
 	"github.com/mylxsw/aidea-server/config"
 	"github.com/mylxsw/aidea-server/server/auth"
+	"github.com/mylxsw/aidea-server/server/controllers"
 	"github.com/mylxsw/aidea-server/server/controllers/common"
 	"github.com/mylxsw/asteria/log"
 	"github.com/mylxsw/glacier/infra"
@@ -47,7 +48,12 @@ func (ctl *RoomController) Rooms(ctx context.Context, webCtx web.Context, user *
 		roomTypes = append(roomTypes, repo.RoomTypeGroupChat)
 	}
 
-	rooms, err := ctl.roomRepo.Rooms(ctx, user.ID, roomTypes, RoomsQueryLimit)
+	filter, err := controllers.ParseRoomFilter(webCtx)
+	if err != nil {
+		return webCtx.JSONError(err.Error(), http.StatusBadRequest)
+	}
+
+	rooms, err := ctl.roomRepo.FilterRooms(ctx, user.ID, roomTypes, filter, RoomsQueryLimit)
 	if err != nil {
 		log.F(log.M{"user_id": user.ID}).Errorf("查询用户房间列表失败: %v", err)
 		return webCtx.JSONError(common.Text(webCtx, ctl.translater, common.ErrInternalError), http.StatusInternalServerError)
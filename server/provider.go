@@ -98,7 +98,15 @@ func routes(resolver infra.Resolver, router web.Router, mw web.RequestMiddleware
 		"/v1/auth/bind-wechat",  // 绑定微信
 		"/v1/rooms",             // 数字人管理
 		"/v1/room-galleries",    // 数字人 Gallery
+		"/v1/room-folders",      // 数字人文件夹
 		"/v1/messages",          // 聊天记录
+		"/v1/conversations",     // 聊天记录导入导出
+		"/v1/sync",              // 多端同步
+		"/v1/memories",          // 长期记忆
+		"/v1/shares",            // 分享链接管理
+		"/v1/prompt-templates",  // 提示语模板
+		"/v1/feedback",          // 消息评价
+		"/v1/galleries",         // 创作岛作品互动
 		"/v1/voice",             // 语音合成
 		"/v1/admin",             // 管理员接口
 
@@ -258,6 +266,7 @@ func routes(resolver infra.Resolver, router web.Router, mw web.RequestMiddleware
 							PlatformVersion: readFromWebContext(ctx, "platform-version"),
 							Language:        readFromWebContext(ctx, "language"),
 							IP:              ctx.Header("X-Real-IP"),
+							DeviceID:        readFromWebContext(ctx, "device-id"),
 						}
 					})
 
@@ -306,6 +315,13 @@ func routes(resolver infra.Resolver, router web.Router, mw web.RequestMiddleware
 		controllers.NewPaymentController(resolver),
 		controllers.NewRoomController(resolver),
 		controllers.NewMessageController(resolver),
+		controllers.NewConversationController(resolver),
+		controllers.NewSyncController(resolver),
+		controllers.NewMemoryController(resolver),
+		controllers.NewShareController(resolver),
+		controllers.NewPromptTemplateController(resolver),
+		controllers.NewFeedbackController(resolver),
+		controllers.NewGalleryController(resolver),
 		controllers.NewVoiceController(resolver),
 		controllers.NewNotificationController(resolver),
 		controllers.NewArticleController(resolver),
@@ -336,6 +352,9 @@ func routes(resolver infra.Resolver, router web.Router, mw web.RequestMiddleware
 		admin.NewSettingController(resolver),
 		admin.NewPaymentController(resolver),
 		admin.NewMessageController(resolver),
+		admin.NewFeedbackController(resolver),
+		admin.NewGalleryController(resolver),
+		admin.NewModerationController(resolver),
 	)
 
 	// 公开访问信息